@@ -0,0 +1,75 @@
+// Command apikey creates an API key straight in the database. It is how the
+// first admin key is made: the /api/v1/admin/api-keys endpoints already need
+// one.
+//
+//	go run ./cmd/apikey -name bootstrap -roles admin
+//
+// The plain key is printed once and is not stored anywhere.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	authRepository "github.com/douglmendes/mercado-fresco-round-go/internal/auth/repository"
+	authService "github.com/douglmendes/mercado-fresco-round-go/internal/auth/service"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/joho/godotenv"
+)
+
+var roles = []string{
+	authDomain.RoleAdmin,
+	authDomain.RoleWarehouseOperator,
+	authDomain.RoleBuyerService,
+	authDomain.RoleSellerService,
+}
+
+func main() {
+	name := flag.String("name", "bootstrap", "Name of the key")
+	roleList := flag.String("roles", authDomain.RoleAdmin, "Comma separated roles of the key: "+strings.Join(roles, ", "))
+	sellerId := flag.Int("seller-id", 0, "Seller the key is limited to, required with the seller_service role")
+	flag.Parse()
+
+	// The .env file is optional here, the time zone may come from the shell.
+	_ = godotenv.Load(store.PathBuilder("/.env"))
+
+	if err := date.LoadLocation(os.Getenv("TIMEZONE")); err != nil {
+		log.Fatal("invalid TIMEZONE: ", err)
+	}
+
+	keyRoles := strings.Split(*roleList, ",")
+	for _, role := range keyRoles {
+		if !contains(roles, role) {
+			log.Fatalf("unknown role %q, expected one of %s", role, strings.Join(roles, ", "))
+		}
+	}
+	if *sellerId == 0 && contains(keyRoles, authDomain.RoleSellerService) {
+		log.Fatal("the seller_service role needs a -seller-id")
+	}
+
+	service := authService.NewService(authRepository.NewRepository(connections.NewConnection()))
+
+	key, plain, err := service.Create(context.Background(), *name, keyRoles, *sellerId)
+	if err != nil {
+		log.Fatal("failed to create the api key: ", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "created api key %d (%s) with roles %s\n", key.Id, key.Prefix, strings.Join(key.Roles, ","))
+	fmt.Println(plain)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/cmd/server/routes/config"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
 	"github.com/joho/godotenv"
 	"log"
+	"os"
 )
 
 // @title           Mercado Fresco
@@ -23,6 +25,10 @@ func main() {
 	if err != nil {
 		log.Fatal("failed to load .env")
 	}
+
+	if err := date.LoadLocation(os.Getenv("TIMEZONE")); err != nil {
+		log.Fatal("invalid TIMEZONE: ", err)
+	}
 	server := config.NewServer()
 	server.Run()
 
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	auditController "github.com/douglmendes/mercado-fresco-round-go/internal/audit/controller"
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	auditRepository "github.com/douglmendes/mercado-fresco-round-go/internal/audit/repository"
+	auditService "github.com/douglmendes/mercado-fresco-round-go/internal/audit/service"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func AuditRoutes(group *gin.RouterGroup) {
+	auditRouterGroup := group.Group("/audit", middleware.Authorize(authDomain.ResourceAudit))
+	{
+		controller := auditController.NewAuditController(newAuditService(connections.NewConnection()))
+
+		auditRouterGroup.GET("/:entity_type/:id", controller.GetByEntity())
+	}
+}
+
+func newAuditService(db *sql.DB) auditDomain.Service {
+	return auditService.NewService(auditRepository.NewRepository(db))
+}
@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authController "github.com/douglmendes/mercado-fresco-round-go/internal/auth/controller"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
+	authRepository "github.com/douglmendes/mercado-fresco-round-go/internal/auth/repository"
+	authService "github.com/douglmendes/mercado-fresco-round-go/internal/auth/service"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+// Authentication builds the middleware that guards the API. Bearer tokens
+// are only accepted when JWT_HS256_SECRET or JWT_RS256_PUBLIC_KEY_PATH is set.
+func Authentication() gin.HandlerFunc {
+	connection := connections.NewConnection()
+
+	repository := authRepository.NewRepository(connection)
+	service := authService.NewService(repository)
+
+	return middleware.Authenticate(service, jwtVerifier())
+}
+
+func AdminRoutes(group *gin.RouterGroup) {
+	adminRouterGroup := group.Group("/admin", middleware.Authorize(authDomain.ResourceAPIKeys))
+	{
+		connection := connections.NewConnection()
+
+		repository := authRepository.NewRepository(connection)
+		service := authService.NewService(repository)
+		controller := authController.NewAPIKeyController(service)
+
+		adminRouterGroup.POST("/api-keys", controller.Create())
+		adminRouterGroup.GET("/api-keys", controller.GetAll())
+		adminRouterGroup.DELETE("/api-keys/:id", controller.Revoke())
+	}
+}
+
+func jwtVerifier() jwt.Verifier {
+	verifier := jwt.Verifier{
+		Secret:   []byte(os.Getenv("JWT_HS256_SECRET")),
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+		Leeway:   time.Minute,
+	}
+
+	if path := os.Getenv("JWT_RS256_PUBLIC_KEY_PATH"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal("failed to read JWT_RS256_PUBLIC_KEY_PATH: ", err)
+		}
+
+		verifier.PublicKey, err = jwt.ParseRSAPublicKey(data)
+		if err != nil {
+			log.Fatal("invalid JWT_RS256_PUBLIC_KEY_PATH: ", err)
+		}
+	}
+
+	return verifier
+}
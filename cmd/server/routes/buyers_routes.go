@@ -2,25 +2,38 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
 	buyersController "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/controller"
 	buyersRepository "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/repository"
 	buyersService "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/service"
+	localityRepository "github.com/douglmendes/mercado-fresco-round-go/internal/localities/repository"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 	"github.com/gin-gonic/gin"
 )
 
 func BuyersRoutes(group *gin.RouterGroup) {
 
-	buyerRouterGroup := group.Group("/buyers")
+	buyerRouterGroup := group.Group("/buyers", middleware.Authorize(authDomain.ResourceBuyers))
 	{
-		buyersDbRepo := buyersRepository.NewRepository(connections.NewConnection())
-		buyersService := buyersService.NewService(buyersDbRepo)
+		connection := connections.NewConnection()
+
+		buyersDbRepo := buyersRepository.NewRepository(connection)
+		buyersService := buyersService.NewAuditedService(buyersService.NewService(buyersDbRepo, localityRepository.NewRepository(connection)), newAuditService(connection))
 		b := buyersController.NewBuyer(buyersService)
 
 		buyerRouterGroup.POST("/", b.Create())
+		buyerRouterGroup.POST("/import", b.Import(transaction.NewRunner(connection)))
 		buyerRouterGroup.GET("/", b.GetAll())
 		buyerRouterGroup.GET("/:id", b.GetById())
 		buyerRouterGroup.GET("/reportPurchaseOrders", b.GetOrdersByBuyers())
+		buyerRouterGroup.GET("/:id/analytics", b.Analytics())
 		buyerRouterGroup.PATCH("/:id", b.Update())
 		buyerRouterGroup.DELETE("/:id", b.Delete())
+		buyerRouterGroup.POST("/:id/restore", b.Restore())
+		buyerRouterGroup.GET("/:id/addresses", b.GetAddresses())
+		buyerRouterGroup.POST("/:id/addresses", b.CreateAddress())
+		buyerRouterGroup.PATCH("/:id/addresses/:addressId", b.UpdateAddress())
+		buyerRouterGroup.DELETE("/:id/addresses/:addressId", b.DeleteAddress())
 	}
 }
@@ -2,6 +2,8 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
 	carriersController "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/controller"
 	carriersRepository "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/repository"
 	carriersService "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/service"
@@ -12,16 +14,17 @@ import (
 
 func CarriersRoutes(group *gin.RouterGroup) {
 
-	carriersRouterGroup := group.Group("/carriers")
+	carriersRouterGroup := group.Group("/carriers", middleware.Authorize(authDomain.ResourceCarriers))
 	{
 		connection := connections.NewConnection()
 
 		localitiesRepo := localityRepo.NewRepository(connection)
 		carriersRepo := carriersRepository.NewRepository(connection)
-		carrierService := carriersService.NewService(carriersRepo, localitiesRepo)
+		carrierService := carriersService.NewAuditedService(carriersService.NewService(carriersRepo, localitiesRepo), newAuditService(connection))
 		controller := carriersController.NewCarries(carrierService)
 
 		carriersRouterGroup.POST("/", controller.Create())
+		carriersRouterGroup.GET("/workload", controller.Workload())
 
 	}
 }
@@ -14,8 +14,10 @@ func ConfigurationRoutes(router *gin.Engine) *gin.Engine {
 	docs.SwaggerInfo.Host = os.Getenv("HOST")
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	baseUrl := router.Group("/api/v1/")
+	baseUrl := router.Group("/api/v1/", routes.Authentication())
 	{
+		routes.AdminRoutes(baseUrl)
+		routes.AuditRoutes(baseUrl)
 		routes.BuyersRoutes(baseUrl)
 		routes.CarriersRoutes(baseUrl)
 		routes.EmployeesRoutes(baseUrl)
@@ -28,6 +30,8 @@ func ConfigurationRoutes(router *gin.Engine) *gin.Engine {
 		routes.PurchaseOrdersRoutes(baseUrl)
 		routes.ProductBatchesRoutes(baseUrl)
 		routes.ProductRecordsRoutes(baseUrl)
+		routes.ProductTypesRoutes(baseUrl)
+		routes.OrderStatusRoutes(baseUrl)
 	}
 
 	return router
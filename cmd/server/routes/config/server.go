@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/export"
 	"github.com/gin-gonic/gin"
 	"log"
 )
@@ -11,9 +12,13 @@ type ConfigurationServer struct {
 }
 
 func NewServer() ConfigurationServer {
+	// gin.Default with a recovery that lets exports cut broken transfers.
+	server := gin.New()
+	server.Use(gin.Logger(), export.Recovery())
+
 	return ConfigurationServer{
 		port:   "8080",
-		server: gin.Default(),
+		server: server,
 	}
 }
 
@@ -2,25 +2,32 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
 	employeesController "github.com/douglmendes/mercado-fresco-round-go/internal/employees/controller"
 	employeesRepository "github.com/douglmendes/mercado-fresco-round-go/internal/employees/repository"
 	employeesService "github.com/douglmendes/mercado-fresco-round-go/internal/employees/service"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 	"github.com/gin-gonic/gin"
 )
 
 func EmployeesRoutes(group *gin.RouterGroup) {
 
-	employeeRouterGroup := group.Group("/employees")
+	employeeRouterGroup := group.Group("/employees", middleware.Authorize(authDomain.ResourceEmployees))
 	{
-		employeesRepo := employeesRepository.NewRepository(connections.NewConnection())
-		employeeService := employeesService.NewService(employeesRepo)
+		connection := connections.NewConnection()
+
+		employeesRepo := employeesRepository.NewRepository(connection)
+		employeeService := employeesService.NewAuditedService(employeesService.NewService(employeesRepo), newAuditService(connection))
 		e := employeesController.NewEmployees(employeeService)
 
 		employeeRouterGroup.POST("/", e.Create())
+		employeeRouterGroup.POST("/import", e.Import(transaction.NewRunner(connection)))
 		employeeRouterGroup.GET("/", e.GetAll())
 		employeeRouterGroup.GET("/:id", e.GetById())
 		employeeRouterGroup.PATCH("/:id", e.Update())
 		employeeRouterGroup.DELETE("/:id", e.Delete())
+		employeeRouterGroup.POST("/:id/restore", e.Restore())
 
 	}
 }
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	idempotencyDomain "github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/middleware"
+	idempotencyRepository "github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/repository"
+	idempotencyService "github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Idempotency builds the middleware that replays responses to retried
+// requests. Responses are kept for IDEMPOTENCY_TTL, a Go duration such as
+// "12h", or for a day when it is not set.
+func Idempotency() gin.HandlerFunc {
+	repository := idempotencyRepository.NewRepository(connections.NewConnection())
+
+	return middleware.Idempotent(idempotencyService.NewService(repository, idempotencyTTL()))
+}
+
+func idempotencyTTL() time.Duration {
+	value := os.Getenv("IDEMPOTENCY_TTL")
+	if value == "" {
+		return idempotencyDomain.DefaultTTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		log.Fatal("invalid IDEMPOTENCY_TTL: ", value)
+	}
+
+	return ttl
+}
@@ -2,6 +2,8 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
 	employeesRepository "github.com/douglmendes/mercado-fresco-round-go/internal/employees/repository"
 	employeesController "github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/controller"
 	inboudOrdersRepository "github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/repository"
@@ -11,16 +13,17 @@ import (
 
 func InboudOrdersRoutes(group *gin.RouterGroup) {
 	connection := connections.NewConnection()
-	inboudOrdersRouterGroup := group.Group("/inboud-orders")
+	inboudOrdersRouterGroup := group.Group("/inboud-orders", middleware.Authorize(authDomain.ResourceInboundOrders))
 	{
 		inboudOrdersRepo := inboudOrdersRepository.NewRepository(connection)
 		employeesRepo := employeesRepository.NewRepository(connection)
 
-		inboudOrderService := inboudOrdersService.NewService(inboudOrdersRepo, employeesRepo)
+		inboudOrderService := inboudOrdersService.NewService(inboudOrdersRepo, employeesRepo, newSequencesService(connection))
 		io := employeesController.NewInboudOrders(inboudOrderService)
 
-		inboudOrdersRouterGroup.POST("/", io.Create())
+		inboudOrdersRouterGroup.POST("/", Idempotency(), io.Create())
 		inboudOrdersRouterGroup.GET("/report-inboud-orders", io.GetById())
+		inboudOrdersRouterGroup.GET("/productivity", io.Productivity())
 
 	}
 }
@@ -2,22 +2,26 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/localities/controller"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/localities/repository"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/localities/service"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 	"github.com/gin-gonic/gin"
 )
 
 func LocalitiesRoutes(group *gin.RouterGroup) {
 
-	localityRouterGroup := group.Group("/localities")
+	localityRouterGroup := group.Group("/localities", middleware.Authorize(authDomain.ResourceLocalities))
 	{
 		localitiesDb := connections.NewConnection()
 		localitiesRepo := repository.NewRepository(localitiesDb)
-		localitiesService := service.NewService(localitiesRepo)
+		localitiesService := service.NewAuditedService(service.NewService(localitiesRepo), newAuditService(localitiesDb))
 		l := controller.NewLocality(localitiesService)
 
 		localityRouterGroup.POST("/", l.Create())
+		localityRouterGroup.POST("/import", l.Import(transaction.NewRunner(localitiesDb)))
 		localityRouterGroup.GET("/reportSellers", l.GetBySellers())
 		localityRouterGroup.GET("/reportCarriers", l.GetByCarriers())
 	}
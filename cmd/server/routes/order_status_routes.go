@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
+	orderStatusController "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/controller"
+	orderStatusRepository "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/repository"
+	orderStatusService "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/service"
+	"github.com/gin-gonic/gin"
+)
+
+func OrderStatusRoutes(group *gin.RouterGroup) {
+	orderStatusRouterGroup := group.Group("/orderStatuses", middleware.Authorize(authDomain.ResourceOrderStatuses))
+	{
+		connection := connections.NewConnection()
+
+		orderStatusRepo := orderStatusRepository.NewRepository(connection)
+		service := orderStatusService.NewAuditedService(orderStatusService.NewService(orderStatusRepo), newAuditService(connection))
+		controller := orderStatusController.NewOrderStatusController(service)
+
+		orderStatusRouterGroup.POST("/", controller.Create)
+		orderStatusRouterGroup.GET("/", controller.GetAll)
+		orderStatusRouterGroup.GET("/:id", controller.GetById)
+		orderStatusRouterGroup.PATCH("/:id", controller.Update)
+		orderStatusRouterGroup.DELETE("/:id", controller.Delete)
+		orderStatusRouterGroup.POST("/:id/restore", controller.Restore)
+	}
+}
@@ -2,6 +2,8 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
 	pbController "github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/controller"
 	pbRepository "github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/repository"
 	pbService "github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/service"
@@ -13,7 +15,7 @@ import (
 
 func ProductBatchesRoutes(group *gin.RouterGroup) {
 
-	productBatchesRouterGroup := group.Group("/productBatches")
+	productBatchesRouterGroup := group.Group("/productBatches", middleware.Authorize(authDomain.ResourceProductBatches))
 	{
 		connection := connections.NewConnection()
 
@@ -24,5 +26,6 @@ func ProductBatchesRoutes(group *gin.RouterGroup) {
 		controller := pbController.NewController(service)
 
 		productBatchesRouterGroup.POST("/", controller.Create())
+		productBatchesRouterGroup.GET("/export", controller.Export())
 	}
 }
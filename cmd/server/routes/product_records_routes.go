@@ -2,6 +2,8 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/controller"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/repository/mariadb"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/service"
@@ -10,7 +12,7 @@ import (
 )
 
 func ProductRecordsRoutes(group *gin.RouterGroup) {
-	productRecordsRouterGroup := group.Group("/productRecords")
+	productRecordsRouterGroup := group.Group("/productRecords", middleware.Authorize(authDomain.ResourceProductRecords))
 	{
 		connection := connections.NewConnection()
 
@@ -21,5 +23,10 @@ func ProductRecordsRoutes(group *gin.RouterGroup) {
 		productRecordController := controller.NewProductRecordController(productRecordsService)
 
 		productRecordsRouterGroup.POST("/", productRecordController.Create())
+		productRecordsRouterGroup.POST("/batch", productRecordController.CreateBatch())
+		productRecordsRouterGroup.PUT("/batch", productRecordController.UpdateBatch())
+		productRecordsRouterGroup.GET("/margins", productRecordController.Margins())
+		productRecordsRouterGroup.GET("/priceChanges", productRecordController.PriceChanges())
+		productRecordsRouterGroup.GET("/belowCost", productRecordController.BelowCost())
 	}
 }
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
+	productTypesController "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/controller"
+	productTypesRepository "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/repository"
+	productTypesService "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/service"
+	"github.com/gin-gonic/gin"
+)
+
+func ProductTypesRoutes(group *gin.RouterGroup) {
+	productTypesRouterGroup := group.Group("/productTypes", middleware.Authorize(authDomain.ResourceProductTypes))
+	{
+		connection := connections.NewConnection()
+
+		productTypesRepo := productTypesRepository.NewRepository(connection)
+		service := productTypesService.NewAuditedService(productTypesService.NewService(productTypesRepo), newAuditService(connection))
+		controller := productTypesController.NewProductTypesController(service)
+
+		productTypesRouterGroup.POST("/", controller.Create)
+		productTypesRouterGroup.GET("/", controller.GetAll)
+		productTypesRouterGroup.GET("/:id", controller.GetById)
+		productTypesRouterGroup.PATCH("/:id", controller.Update)
+		productTypesRouterGroup.DELETE("/:id", controller.Delete)
+		productTypesRouterGroup.POST("/:id/restore", controller.Restore)
+	}
+}
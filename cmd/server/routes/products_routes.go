@@ -2,22 +2,26 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
 	productRecordController "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/controller"
 	productRecordMariaDB "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/repository/mariadb"
 	productRecordService "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/service"
+	productTypesRepository "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/repository"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/products/controller"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/products/repository/mariadb"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/products/service"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 	"github.com/gin-gonic/gin"
 )
 
 func ProductsRoutes(group *gin.RouterGroup) {
-	productRouterGroup := group.Group("/products")
+	productRouterGroup := group.Group("/products", middleware.Authorize(authDomain.ResourceProducts))
 	{
 		connection := connections.NewConnection()
 
 		productsRepository := mariadb.NewRepository(connection)
-		productsService := service.NewService(productsRepository)
+		productsService := service.NewAuditedService(service.NewService(productsRepository, productTypesRepository.NewRepository(connection)), newAuditService(connection))
 		productsController := controller.NewProductController(productsService)
 
 		productRecordsRepository := productRecordMariaDB.NewRepository(connection)
@@ -25,10 +29,13 @@ func ProductsRoutes(group *gin.RouterGroup) {
 		productRecordController := productRecordController.NewProductRecordController(productRecordsService)
 
 		productRouterGroup.POST("/", productsController.Create())
+		productRouterGroup.POST("/import", productsController.Import(transaction.NewRunner(connection)))
 		productRouterGroup.GET("/", productsController.GetAll())
+		productRouterGroup.GET("/export", productsController.Export())
 		productRouterGroup.GET("/:id", productsController.GetById())
 		productRouterGroup.PATCH("/:id", productsController.Update())
 		productRouterGroup.DELETE("/:id", productsController.Delete())
+		productRouterGroup.POST("/:id/restore", productsController.Restore())
 
 		productRouterGroup.GET("/reportRecords", productRecordController.GetByProductId())
 	}
@@ -2,6 +2,12 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
+	buyersRepository "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/repository"
+	carriersRepository "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/repository"
+	orderStatusRepository "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/repository"
+	productRecordsRepository "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/repository/mariadb"
 	purchaOrdersController "github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/controller"
 	purchaOrdersRepository "github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/repository"
 	purchaOrdersService "github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/service"
@@ -9,12 +15,18 @@ import (
 )
 
 func PurchaseOrdersRoutes(group *gin.RouterGroup) {
-	purchaseOrdersRouterGroup := group.Group("/purchase-orders")
+	purchaseOrdersRouterGroup := group.Group("/purchase-orders", middleware.Authorize(authDomain.ResourcePurchaseOrders))
 	{
-		purchaseOrdersRepo := purchaOrdersRepository.NewRepository(connections.NewConnection())
-		purchaseOrdersService := purchaOrdersService.NewService(purchaseOrdersRepo)
+		connection := connections.NewConnection()
+		purchaseOrdersRepo := purchaOrdersRepository.NewRepository(connection)
+		purchaseOrdersService := purchaOrdersService.NewService(purchaseOrdersRepo, newSequencesService(connection), carriersRepository.NewRepository(connection), buyersRepository.NewRepository(connection), orderStatusRepository.NewRepository(connection), productRecordsRepository.NewRepository(connection))
 		po := purchaOrdersController.NewPurchaseOrders(purchaseOrdersService)
 
-		purchaseOrdersRouterGroup.POST("/", po.Create())
+		purchaseOrdersRouterGroup.POST("/", Idempotency(), po.Create())
+		purchaseOrdersRouterGroup.GET("/export", po.Export())
+		purchaseOrdersRouterGroup.GET("/:id", po.GetById())
+		purchaseOrdersRouterGroup.PATCH("/:id/carrier", po.AssignCarrier())
+		purchaseOrdersRouterGroup.POST("/:id/lines", po.AddLine())
+		purchaseOrdersRouterGroup.DELETE("/:id/lines/:lineId", po.RemoveLine())
 	}
 }
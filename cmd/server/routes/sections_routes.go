@@ -2,9 +2,12 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
 	pbController "github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/controller"
 	pbRepo "github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/repository"
 	pbService "github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/service"
+	productTypesRepository "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/repository"
 	productsRepository "github.com/douglmendes/mercado-fresco-round-go/internal/products/repository/mariadb"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sections/controller"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sections/repository"
@@ -14,12 +17,12 @@ import (
 
 func SectionsRoutes(group *gin.RouterGroup) {
 
-	sectionRouterGroup := group.Group("/sections")
+	sectionRouterGroup := group.Group("/sections", middleware.Authorize(authDomain.ResourceSections))
 	{
 		connection := connections.NewConnection()
 
 		sectionsRepository := repository.NewRepository(connection)
-		sectionsService := service.NewService(sectionsRepository)
+		sectionsService := service.NewAuditedService(service.NewService(sectionsRepository, productTypesRepository.NewRepository(connection)), newAuditService(connection))
 		sectionsController := controller.NewSectionsController(sectionsService)
 
 		productBatchesRepository := pbRepo.NewRepository(connection)
@@ -32,6 +35,7 @@ func SectionsRoutes(group *gin.RouterGroup) {
 		sectionRouterGroup.GET("/:id", sectionsController.GetById)
 		sectionRouterGroup.PATCH("/:id", sectionsController.Update)
 		sectionRouterGroup.DELETE("/:id", sectionsController.Delete)
+		sectionRouterGroup.POST("/:id/restore", sectionsController.Restore)
 
 		sectionRouterGroup.GET("/reportProducts", productBatchesController.GetBySectionId())
 	}
@@ -2,27 +2,34 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
+	localityRepository "github.com/douglmendes/mercado-fresco-round-go/internal/localities/repository"
+	productsRepository "github.com/douglmendes/mercado-fresco-round-go/internal/products/repository/mariadb"
 	sellersController "github.com/douglmendes/mercado-fresco-round-go/internal/sellers/controller"
 	sellersRepository "github.com/douglmendes/mercado-fresco-round-go/internal/sellers/repository"
-	localityRepository "github.com/douglmendes/mercado-fresco-round-go/internal/localities/repository"
 	sellerService "github.com/douglmendes/mercado-fresco-round-go/internal/sellers/service"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 	"github.com/gin-gonic/gin"
 )
 
 func SellersRoutes(group *gin.RouterGroup) {
 
-	sellerRouterGroup := group.Group("/sellers")
+	sellerRouterGroup := group.Group("/sellers", middleware.Authorize(authDomain.ResourceSellers))
 	{
 		sellersDb := connections.NewConnection()
 		sellersRepo := sellersRepository.NewRepository(sellersDb)
 		localityRepo := localityRepository.NewRepository(sellersDb)
-		sellersService := sellerService.NewService(sellersRepo, localityRepo)
+		sellersService := sellerService.NewAuditedService(sellerService.NewService(sellersRepo, localityRepo), newAuditService(sellersDb), productsRepository.NewRepository(sellersDb))
 		s := sellersController.NewSeller(sellersService)
 
 		sellerRouterGroup.POST("/", s.Create())
+		sellerRouterGroup.POST("/import", s.Import(transaction.NewRunner(sellersDb)))
 		sellerRouterGroup.GET("/", s.GetAll())
 		sellerRouterGroup.GET("/:id", s.GetById())
+		sellerRouterGroup.GET("/:id/report", s.Report())
 		sellerRouterGroup.PATCH("/:id", s.Update())
 		sellerRouterGroup.DELETE("/:id", s.Delete())
+		sellerRouterGroup.POST("/:id/restore", s.Restore())
 	}
 }
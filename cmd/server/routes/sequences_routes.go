@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"database/sql"
+	"os"
+	"strings"
+
+	sequencesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	sequencesRepository "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/repository"
+	sequencesService "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/service"
+)
+
+// newSequencesService builds the generator of order numbers and tracking
+// codes. The prefix of each sequence can be changed with an environment
+// variable named after it, such as PURCHASE_ORDER_NUMBER_PREFIX.
+func newSequencesService(db *sql.DB) sequencesDomain.Service {
+	formats := map[string]sequencesDomain.Format{}
+	for name, format := range sequencesDomain.DefaultFormats {
+		if prefix, ok := os.LookupEnv(strings.ToUpper(name) + "_PREFIX"); ok {
+			format.Prefix = prefix
+		}
+		formats[name] = format
+	}
+
+	return sequencesService.NewService(sequencesRepository.NewRepository(db), formats)
+}
@@ -2,6 +2,10 @@ package routes
 
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/connections"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/middleware"
+	employeesRepository "github.com/douglmendes/mercado-fresco-round-go/internal/employees/repository"
+	sectionsRepository "github.com/douglmendes/mercado-fresco-round-go/internal/sections/repository"
 	warehouseController "github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/controller"
 	warehouseRepository "github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/repository"
 	warehouseService "github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/service"
@@ -10,10 +14,16 @@ import (
 
 func WarehousesRoutes(group *gin.RouterGroup) {
 
-	warehouseRouterGroup := group.Group("/warehouses")
+	warehouseRouterGroup := group.Group("/warehouses", middleware.Authorize(authDomain.ResourceWarehouses))
 	{
-		warehousesRepo := warehouseRepository.NewRepository(connections.NewConnection())
-		warehousesService := warehouseService.NewService(warehousesRepo)
+		connection := connections.NewConnection()
+		warehousesRepo := warehouseRepository.NewRepository(connection)
+		warehousesService := warehouseService.NewAuditedService(
+			warehouseService.NewService(warehousesRepo),
+			newAuditService(connection),
+			sectionsRepository.NewRepository(connection),
+			employeesRepository.NewRepository(connection),
+		)
 		whController := warehouseController.NewWarehouse(warehousesService)
 
 		warehouseRouterGroup.POST("/", whController.Create())
@@ -21,6 +31,7 @@ func WarehousesRoutes(group *gin.RouterGroup) {
 		warehouseRouterGroup.GET("/:id", whController.GetById())
 		warehouseRouterGroup.PATCH("/:id", whController.Update())
 		warehouseRouterGroup.DELETE("/:id", whController.Delete())
+		warehouseRouterGroup.POST("/:id/restore", whController.Restore())
 
 	}
 }
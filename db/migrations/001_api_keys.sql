@@ -0,0 +1,15 @@
+-- API keys of service-to-service callers. Only the SHA-256 of the secret is
+-- kept; prefix is the public part of the key used to look it up. roles is a
+-- comma separated list.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+  id INT NOT NULL AUTO_INCREMENT,
+  name VARCHAR(255) NOT NULL,
+  prefix CHAR(8) NOT NULL,
+  key_hash CHAR(64) NOT NULL,
+  roles VARCHAR(255) NOT NULL,
+  created_at DATETIME NOT NULL,
+  revoked_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY api_keys_prefix (prefix)
+);
@@ -0,0 +1,5 @@
+-- Keys of the seller_service role are limited to the products of one seller.
+
+ALTER TABLE api_keys
+  ADD COLUMN IF NOT EXISTS seller_id INT NULL,
+  ADD FOREIGN KEY IF NOT EXISTS api_keys_seller (seller_id) REFERENCES sellers (id);
@@ -0,0 +1,15 @@
+-- One row per create, update, delete or restore of an audited entity, with
+-- the JSON of the entity before and after the change.
+
+CREATE TABLE IF NOT EXISTS audit_entries (
+  id INT NOT NULL AUTO_INCREMENT,
+  entity_type VARCHAR(64) NOT NULL,
+  entity_id INT NOT NULL,
+  action VARCHAR(16) NOT NULL,
+  actor VARCHAR(255) NOT NULL,
+  created_at DATETIME NOT NULL,
+  before_state JSON NULL,
+  after_state JSON NULL,
+  PRIMARY KEY (id),
+  KEY audit_entries_entity (entity_type, entity_id, created_at)
+);
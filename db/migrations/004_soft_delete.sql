@@ -0,0 +1,20 @@
+-- Soft deletion of the entities the API can delete and restore. deleted_at
+-- is NULL while a row is live.
+
+ALTER TABLE buyers
+  ADD COLUMN IF NOT EXISTS deleted_at DATETIME NULL;
+
+ALTER TABLE sellers
+  ADD COLUMN IF NOT EXISTS deleted_at DATETIME NULL;
+
+ALTER TABLE warehouse
+  ADD COLUMN IF NOT EXISTS deleted_at DATETIME NULL;
+
+ALTER TABLE section
+  ADD COLUMN IF NOT EXISTS deleted_at DATETIME NULL;
+
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS deleted_at DATETIME NULL;
+
+ALTER TABLE employees
+  ADD COLUMN IF NOT EXISTS deleted_at DATETIME NULL;
@@ -0,0 +1,8 @@
+-- Optimistic locking of products and sections. version starts at 1 and is
+-- bumped by every write, it is the ETag of the row.
+
+ALTER TABLE section
+  ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,14 @@
+-- Responses of requests sent with an Idempotency-Key, replayed when the same
+-- caller retries. status is 0 while the first request is in progress.
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  owner VARCHAR(255) NOT NULL,
+  idempotency_key VARCHAR(255) NOT NULL,
+  fingerprint CHAR(64) NOT NULL,
+  status INT NOT NULL DEFAULT 0,
+  body MEDIUMBLOB NULL,
+  created_at DATETIME NOT NULL,
+  expires_at DATETIME NOT NULL,
+  PRIMARY KEY (owner, idempotency_key),
+  KEY idempotency_keys_expires_at (expires_at)
+);
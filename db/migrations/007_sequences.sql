@@ -0,0 +1,9 @@
+-- Counters of the identifiers the server generates. period is the formatted
+-- day the counter belongs to, empty for counters that never start over.
+
+CREATE TABLE IF NOT EXISTS sequences (
+  name VARCHAR(64) NOT NULL,
+  period VARCHAR(16) NOT NULL,
+  value BIGINT NOT NULL,
+  PRIMARY KEY (name, period)
+);
@@ -0,0 +1,7 @@
+-- Purchase orders are delivered in a locality by the carrier assigned to it.
+
+ALTER TABLE purchase_orders
+  ADD COLUMN IF NOT EXISTS locality_id INT NULL,
+  ADD COLUMN IF NOT EXISTS carrier_id INT NULL,
+  ADD FOREIGN KEY IF NOT EXISTS purchase_orders_locality (locality_id) REFERENCES localities (id),
+  ADD FOREIGN KEY IF NOT EXISTS purchase_orders_carrier (carrier_id) REFERENCES carries (id);
@@ -0,0 +1,17 @@
+-- Delivery addresses of buyers. Each buyer with addresses has exactly one
+-- default address, the one purchase orders go to unless they name another.
+
+CREATE TABLE IF NOT EXISTS buyer_addresses (
+  id INT NOT NULL AUTO_INCREMENT,
+  buyer_id INT NOT NULL,
+  address VARCHAR(255) NOT NULL,
+  locality_id INT NOT NULL,
+  is_default BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (id),
+  FOREIGN KEY (buyer_id) REFERENCES buyers (id),
+  FOREIGN KEY (locality_id) REFERENCES localities (id)
+);
+
+ALTER TABLE purchase_orders
+  ADD COLUMN IF NOT EXISTS address_id INT NULL,
+  ADD FOREIGN KEY IF NOT EXISTS purchase_orders_address (address_id) REFERENCES buyer_addresses (id);
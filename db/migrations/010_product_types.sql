@@ -0,0 +1,11 @@
+-- Product types group products with the same storage needs. products and
+-- section already carry product_type_id.
+
+CREATE TABLE IF NOT EXISTS product_types (
+  id INT NOT NULL AUTO_INCREMENT,
+  name VARCHAR(255) NOT NULL,
+  temperature_class VARCHAR(16) NOT NULL,
+  minimum_temperature DECIMAL(19,2) NOT NULL,
+  maximum_temperature DECIMAL(19,2) NOT NULL,
+  PRIMARY KEY (id)
+);
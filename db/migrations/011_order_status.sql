@@ -0,0 +1,10 @@
+-- Statuses of purchase orders. New orders start in the initial status;
+-- orders in a terminal status can no longer change.
+
+CREATE TABLE IF NOT EXISTS order_status (
+  id INT NOT NULL AUTO_INCREMENT,
+  description VARCHAR(255) NOT NULL,
+  is_initial BOOLEAN NOT NULL DEFAULT FALSE,
+  is_terminal BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (id)
+);
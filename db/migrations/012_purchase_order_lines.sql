@@ -0,0 +1,19 @@
+-- Purchase orders hold lines. Orders made before lines keep their
+-- product_record_id; new ones leave it NULL and price each line from the
+-- product record in effect.
+
+ALTER TABLE purchase_orders
+  MODIFY product_record_id INT NULL;
+
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+  id INT NOT NULL AUTO_INCREMENT,
+  purchase_order_id INT NOT NULL,
+  product_id INT NOT NULL,
+  product_record_id INT NOT NULL,
+  quantity INT NOT NULL,
+  unit_price DECIMAL(19,2) NOT NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders (id),
+  FOREIGN KEY (product_id) REFERENCES products (id),
+  FOREIGN KEY (product_record_id) REFERENCES product_records (id)
+);
@@ -0,0 +1,14 @@
+-- Optimistic locking of buyers, sellers, warehouses and employees, like
+-- products and sections.
+
+ALTER TABLE buyers
+  ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE sellers
+  ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE warehouse
+  ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE employees
+  ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
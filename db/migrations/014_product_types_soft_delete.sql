@@ -0,0 +1,9 @@
+-- Product types are soft deleted like the rest of the master data, so a
+-- deleted type can be restored with its id, and versioned like it for
+-- optimistic locking.
+
+ALTER TABLE product_types
+  ADD COLUMN IF NOT EXISTS deleted_at DATETIME NULL;
+
+ALTER TABLE product_types
+  ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
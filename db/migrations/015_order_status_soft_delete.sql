@@ -0,0 +1,9 @@
+-- Order statuses are soft deleted like the rest of the master data, so a
+-- deleted status can be restored with its id, and versioned like it for
+-- optimistic locking.
+
+ALTER TABLE order_status
+  ADD COLUMN IF NOT EXISTS deleted_at DATETIME NULL;
+
+ALTER TABLE order_status
+  ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
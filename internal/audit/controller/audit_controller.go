@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	service domain.Service
+}
+
+func NewAuditController(service domain.Service) *AuditController {
+	return &AuditController{service}
+}
+
+// ListAuditEntries godoc
+// @Summary      List the audit trail of an entity
+// @Description  List every recorded change to an entity, newest first
+// @Tags         audit
+// @Produce      json
+// @Param        entity_type  path      string  true  "Entity type, e.g. sellers"
+// @Param        id           path      int     true  "Entity id"
+// @Success      200  {array}   domain.Entry
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /api/v1/audit/{entity_type}/{id} [get]
+func (c *AuditController) GetByEntity() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		entityType := ctx.Param("entity_type")
+		if !domain.IsEntity(entityType) {
+			ctx.JSON(http.StatusNotFound, response.DecodeError(fmt.Sprintf("unknown entity type %q", entityType)))
+			return
+		}
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(message))
+			return
+		}
+
+		entries, err := c.service.GetByEntity(ctx, entityType, id)
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusInternalServerError, response.DecodeError(message))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(entries))
+	}
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRouter(service domain.Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/api/v1/audit/:entity_type/:id", NewAuditController(service).GetByEntity())
+
+	return router
+}
+
+func doRequest(router *gin.Engine, url string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+	return rr
+}
+
+func TestController_GetByEntity(t *testing.T) {
+	t.Run("should list the entries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		service.EXPECT().GetByEntity(gomock.Any(), domain.EntitySellers, 3).Return([]domain.Entry{{Id: 1}}, nil)
+
+		rr := doRequest(newRouter(service), "/api/v1/audit/sellers/3")
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("should return not found for unknown entity types", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+
+		rr := doRequest(newRouter(service), "/api/v1/audit/orders/3")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return bad request for invalid ids", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+
+		rr := doRequest(newRouter(service), "/api/v1/audit/sellers/xpto")
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should return internal server error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		service.EXPECT().GetByEntity(gomock.Any(), domain.EntitySellers, 3).Return(nil, errors.New("connection refused"))
+
+		rr := doRequest(newRouter(service), "/api/v1/audit/sellers/3")
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+)
+
+// Entity types are named after the route groups of the audited resources.
+const (
+	EntityBuyers         = "buyers"
+	EntityBuyerAddresses = "buyer_addresses"
+	EntityCarriers       = "carriers"
+	EntityEmployees      = "employees"
+	EntityLocalities     = "localities"
+	EntityOrderStatuses  = "orderStatuses"
+	EntityProducts       = "products"
+	EntityProductTypes   = "productTypes"
+	EntitySections       = "sections"
+	EntitySellers        = "sellers"
+	EntityWarehouses     = "warehouses"
+)
+
+// ActorAnonymous is recorded when a change is made outside an authenticated
+// request.
+const ActorAnonymous = "anonymous"
+
+func IsEntity(entityType string) bool {
+	switch entityType {
+	case EntityBuyers, EntityBuyerAddresses, EntityCarriers, EntityEmployees,
+		EntityLocalities, EntityOrderStatuses, EntityProducts, EntityProductTypes,
+		EntitySections, EntitySellers, EntityWarehouses:
+		return true
+	}
+	return false
+}
+
+// Entry records one change to an entity. Before is null for creations and
+// After is null for deletions.
+type Entry struct {
+	Id         int             `json:"id"`
+	EntityType string          `json:"entity_type"`
+	EntityId   int             `json:"entity_id"`
+	Action     string          `json:"action"`
+	Actor      string          `json:"actor"`
+	CreatedAt  date.DateTime   `json:"created_at"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+}
+
+//go:generate mockgen -source=./audit.go -destination=./mock/audit_mock.go
+type Repository interface {
+	Create(ctx context.Context, entry Entry) (Entry, error)
+	GetByEntity(ctx context.Context, entityType string, entityId int) ([]Entry, error)
+}
+
+type Service interface {
+	Record(ctx context.Context, entityType string, entityId int, action string, before, after interface{})
+	GetByEntity(ctx context.Context, entityType string, entityId int) ([]Entry, error)
+}
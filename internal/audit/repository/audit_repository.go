@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
+)
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domain.Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r repository) Create(ctx context.Context, entry domain.Entry) (domain.Entry, error) {
+	result, err := transaction.From(ctx, r.db).ExecContext(
+		ctx,
+		sqlCreate,
+		entry.EntityType,
+		entry.EntityId,
+		entry.Action,
+		entry.Actor,
+		entry.CreatedAt,
+		nullJSON(entry.Before),
+		nullJSON(entry.After),
+	)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.Entry{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.Entry{}, err
+	}
+
+	entry.Id = int(id)
+
+	return entry, nil
+}
+
+func (r repository) GetByEntity(ctx context.Context, entityType string, entityId int) ([]domain.Entry, error) {
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, sqlGetByEntity, entityType, entityId)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	entries := []domain.Entry{}
+	for rows.Next() {
+		var (
+			entry         domain.Entry
+			before, after []byte
+		)
+
+		if err := rows.Scan(
+			&entry.Id,
+			&entry.EntityType,
+			&entry.EntityId,
+			&entry.Action,
+			&entry.Actor,
+			&entry.CreatedAt,
+			&before,
+			&after,
+		); err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			return nil, err
+		}
+
+		entry.Before = rawJSON(before)
+		entry.After = rawJSON(after)
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func nullJSON(value json.RawMessage) interface{} {
+	if len(value) == 0 || string(value) == "null" {
+		return nil
+	}
+	return string(value)
+}
+
+func rawJSON(value []byte) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(value)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/stretchr/testify/assert"
+)
+
+var createdAt = date.NewDateTime(time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC))
+
+func TestRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	entry := domain.Entry{
+		EntityType: domain.EntitySellers,
+		EntityId:   3,
+		Action:     domain.ActionCreate,
+		Actor:      "user-1",
+		CreatedAt:  createdAt,
+		Before:     json.RawMessage("null"),
+		After:      json.RawMessage(`{"id":3}`),
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlCreate)).
+		WithArgs(domain.EntitySellers, 3, domain.ActionCreate, "user-1", "2022-08-01 10:00:00", nil, `{"id":3}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	result, err := NewRepository(db).Create(context.TODO(), entry)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Id)
+}
+
+func TestRepository_Create_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlCreate)).WillReturnError(errors.New("connection refused"))
+
+	_, err = NewRepository(db).Create(context.TODO(), domain.Entry{CreatedAt: createdAt})
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestRepository_GetByEntity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "entity_type", "entity_id", "action", "actor", "created_at", "before_state", "after_state"}).
+		AddRow(2, domain.EntitySellers, 3, domain.ActionDelete, "api_key:1", "2022-08-02 10:00:00", []byte(`{"id":3}`), nil).
+		AddRow(1, domain.EntitySellers, 3, domain.ActionCreate, "user-1", "2022-08-01 10:00:00", nil, []byte(`{"id":3}`))
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetByEntity)).WithArgs(domain.EntitySellers, 3).WillReturnRows(rows)
+
+	entries, err := NewRepository(db).GetByEntity(context.TODO(), domain.EntitySellers, 3)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.JSONEq(t, `{"id":3}`, string(entries[0].Before))
+	assert.Equal(t, "null", string(entries[0].After))
+	assert.Equal(t, "null", string(entries[1].Before))
+	assert.True(t, entries[1].CreatedAt.Equal(createdAt.Time))
+}
@@ -0,0 +1,13 @@
+package repository
+
+const (
+	sqlCreate = `
+		INSERT INTO audit_entries (entity_type, entity_id, action, actor, created_at, before_state, after_state)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	sqlGetByEntity = `
+		SELECT id, entity_type, entity_id, action, actor, created_at, before_state, after_state
+		FROM audit_entries
+		WHERE entity_type = ? AND entity_id = ?
+		ORDER BY created_at DESC, id DESC`
+)
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+)
+
+type service struct {
+	repository domain.Repository
+}
+
+func NewService(r domain.Repository) domain.Service {
+	return &service{
+		repository: r,
+	}
+}
+
+// Record stores an entry for a change that has already been made. Failures
+// are logged rather than returned, so a broken audit table never undoes or
+// hides a successful change.
+func (s *service) Record(ctx context.Context, entityType string, entityId int, action string, before, after interface{}) {
+	entry := domain.Entry{
+		EntityType: entityType,
+		EntityId:   entityId,
+		Action:     action,
+		Actor:      actor(ctx),
+		CreatedAt:  date.Now(),
+	}
+
+	var err error
+	if entry.Before, err = marshal(before); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return
+	}
+	if entry.After, err = marshal(after); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return
+	}
+
+	if _, err := s.repository.Create(ctx, entry); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+	}
+}
+
+func (s *service) GetByEntity(ctx context.Context, entityType string, entityId int) ([]domain.Entry, error) {
+	entries, err := s.repository.GetByEntity(ctx, entityType, entityId)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func actor(ctx context.Context) string {
+	principal, ok := authDomain.PrincipalFrom(ctx)
+	if !ok || principal.Subject == "" {
+		return domain.ActorAnonymous
+	}
+	return principal.Subject
+}
+
+func marshal(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return json.RawMessage("null"), nil
+	}
+	return json.Marshal(value)
+}
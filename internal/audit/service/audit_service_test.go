@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain/mock"
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type seller struct {
+	Id      int    `json:"id"`
+	Address string `json:"address"`
+}
+
+func TestService_Record(t *testing.T) {
+	t.Run("should record the actor and both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+
+		var stored domain.Entry
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, entry domain.Entry) (domain.Entry, error) {
+				stored = entry
+				return entry, nil
+			})
+
+		ctx := authDomain.WithPrincipal(context.TODO(), authDomain.Principal{Subject: "user-1"})
+		NewService(repository).Record(
+			ctx, domain.EntitySellers, 3, domain.ActionUpdate,
+			seller{3, "Old Street"}, seller{3, "New Street"},
+		)
+
+		assert.Equal(t, domain.EntitySellers, stored.EntityType)
+		assert.Equal(t, 3, stored.EntityId)
+		assert.Equal(t, domain.ActionUpdate, stored.Action)
+		assert.Equal(t, "user-1", stored.Actor)
+		assert.False(t, stored.CreatedAt.IsZero())
+		assert.JSONEq(t, `{"id":3,"address":"Old Street"}`, string(stored.Before))
+		assert.JSONEq(t, `{"id":3,"address":"New Street"}`, string(stored.After))
+	})
+
+	t.Run("should record anonymous changes with a null state", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+
+		var stored domain.Entry
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, entry domain.Entry) (domain.Entry, error) {
+				stored = entry
+				return entry, nil
+			})
+
+		NewService(repository).Record(context.TODO(), domain.EntitySellers, 3, domain.ActionCreate, nil, seller{Id: 3})
+
+		assert.Equal(t, domain.ActorAnonymous, stored.Actor)
+		assert.Equal(t, "null", string(stored.Before))
+	})
+
+	t.Run("should not fail when the entry cannot be stored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.Entry{}, errors.New("connection refused"))
+
+		assert.NotPanics(t, func() {
+			NewService(repository).Record(context.TODO(), domain.EntitySellers, 3, domain.ActionDelete, seller{Id: 3}, nil)
+		})
+	})
+}
+
+func TestService_GetByEntity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mock_domain.NewMockRepository(ctrl)
+	repository.EXPECT().GetByEntity(gomock.Any(), domain.EntitySellers, 3).Return([]domain.Entry{{Id: 1}}, nil)
+
+	entries, err := NewService(repository).GetByEntity(context.TODO(), domain.EntitySellers, 3)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyController struct {
+	service domain.Service
+}
+
+func NewAPIKeyController(service domain.Service) *APIKeyController {
+	return &APIKeyController{service}
+}
+
+// ListAPIKeys godoc
+// @Summary      List API keys
+// @Description  List every API key, including revoked ones. Requires the admin role.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {array}   domain.APIKey
+// @Failure      500  {object}  response.Response
+// @Router       /api/v1/admin/api-keys [get]
+func (c *APIKeyController) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		keys, err := c.service.GetAll(ctx)
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusInternalServerError, response.DecodeError(message))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(keys))
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary      Create an API key
+// @Description  Create an API key. The plain key is only returned by this call. Requires the admin role.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        key  body      createAPIKeyRequest  true  "API key to create"
+// @Success      201  {object}  createAPIKeyResponse
+// @Failure      422  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /api/v1/admin/api-keys [post]
+func (c *APIKeyController) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request createAPIKeyRequest
+
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+
+		if request.SellerId == 0 && containsRole(request.Roles, domain.RoleSellerService) {
+			ctx.JSON(http.StatusUnprocessableEntity, response.DecodeFieldErrors(validation.Message, []response.FieldError{{
+				Field:   "seller_id",
+				Code:    "required",
+				Message: "is required for the " + domain.RoleSellerService + " role",
+			}}))
+			return
+		}
+
+		key, plain, err := c.service.Create(ctx, request.Name, request.Roles, request.SellerId)
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusInternalServerError, response.DecodeError(message))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, response.NewResponse(createAPIKeyResponse{key, plain}))
+	}
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Description  Revoke an API key so it can no longer authenticate. Requires the admin role.
+// @Tags         auth
+// @Param        id   path  int  true  "API key id"
+// @Success      204  "Successfully revoked"
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Router       /api/v1/admin/api-keys/{id} [delete]
+func (c *APIKeyController) Revoke() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(message))
+			return
+		}
+
+		err = c.service.Revoke(ctx, id)
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			status := http.StatusInternalServerError
+			if errors.Is(err, domain.ErrNotFound) {
+				status = http.StatusNotFound
+			}
+
+			ctx.JSON(status, response.DecodeError(message))
+			return
+		}
+
+		ctx.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+type createAPIKeyRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Roles    []string `json:"roles" binding:"required,min=1,dive,oneof=admin warehouse_operator buyer_service seller_service"`
+	SellerId int      `json:"seller_id" binding:"omitempty,min=1"`
+}
+
+type createAPIKeyResponse struct {
+	domain.APIKey
+	Key string `json:"key"`
+}
+
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
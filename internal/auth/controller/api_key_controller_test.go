@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const RELATIVE_PATH = "/api/v1/admin/api-keys"
+
+func newRouter(service domain.Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	controller := NewAPIKeyController(service)
+
+	router := gin.New()
+	router.GET(RELATIVE_PATH, controller.GetAll())
+	router.POST(RELATIVE_PATH, controller.Create())
+	router.DELETE(RELATIVE_PATH+"/:id", controller.Revoke())
+
+	return router
+}
+
+func doRequest(router *gin.Engine, method, url, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestController_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+	service.EXPECT().GetAll(gomock.Any()).Return([]domain.APIKey{{Id: 1, Name: "buyers", Hash: "hash"}}, nil)
+
+	rr := doRequest(newRouter(service), http.MethodGet, RELATIVE_PATH, "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "hash")
+}
+
+func TestController_Create(t *testing.T) {
+	t.Run("should return the plain key once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		service.EXPECT().
+			Create(gomock.Any(), "buyers", []string{domain.RoleAdmin}, 0).
+			Return(domain.APIKey{Id: 1, Name: "buyers", Prefix: "a1b2c3d4", Roles: []string{domain.RoleAdmin}}, "mf_a1b2c3d4_secret", nil)
+
+		rr := doRequest(newRouter(service), http.MethodPost, RELATIVE_PATH, `{"name":"buyers","roles":["admin"]}`)
+		assert.Equal(t, http.StatusCreated, rr.Code)
+
+		var body struct {
+			Data createAPIKeyResponse `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, "mf_a1b2c3d4_secret", body.Data.Key)
+		assert.Equal(t, "a1b2c3d4", body.Data.Prefix)
+	})
+
+	t.Run("should require a name and roles", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+
+		rr := doRequest(newRouter(service), http.MethodPost, RELATIVE_PATH, `{"roles":[]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+		var body response.Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Len(t, body.Fields, 2)
+	})
+
+	t.Run("should require a seller for seller services", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+
+		rr := doRequest(newRouter(service), http.MethodPost, RELATIVE_PATH, `{"name":"sellers","roles":["seller_service"]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+		var body response.Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, "seller_id", body.Fields[0].Field)
+	})
+
+	t.Run("should reject unknown roles", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+
+		rr := doRequest(newRouter(service), http.MethodPost, RELATIVE_PATH, `{"name":"buyers","roles":["root"]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+		var body response.Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, "roles[0]", body.Fields[0].Field)
+	})
+}
+
+func TestController_Revoke(t *testing.T) {
+	t.Run("should revoke the key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		service.EXPECT().Revoke(gomock.Any(), 1).Return(nil)
+
+		rr := doRequest(newRouter(service), http.MethodDelete, RELATIVE_PATH+"/1", "")
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		service.EXPECT().Revoke(gomock.Any(), 1).Return(domain.ErrNotFound)
+
+		rr := doRequest(newRouter(service), http.MethodDelete, RELATIVE_PATH+"/1", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return internal server error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		service.EXPECT().Revoke(gomock.Any(), 1).Return(errors.New("connection refused"))
+
+		rr := doRequest(newRouter(service), http.MethodDelete, RELATIVE_PATH+"/1", "")
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("should return bad request on invalid id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+
+		rr := doRequest(newRouter(service), http.MethodDelete, RELATIVE_PATH+"/xpto", "")
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
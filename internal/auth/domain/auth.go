@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/ctxkey"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+)
+
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+var (
+	ErrNotFound           = errors.New("api key not found")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// APIKey is a credential for service-to-service calls. Only a hash of the
+// key is stored; the plain key is shown once, when it is created.
+type APIKey struct {
+	Id        int           `json:"id"`
+	Name      string        `json:"name"`
+	Prefix    string        `json:"prefix"`
+	Hash      string        `json:"-"`
+	Roles     []string      `json:"roles"`
+	SellerId  int           `json:"seller_id,omitempty"`
+	CreatedAt date.DateTime `json:"created_at"`
+	RevokedAt date.DateTime `json:"revoked_at"`
+}
+
+func (k APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject  string   `json:"subject"`
+	Method   string   `json:"method"`
+	Roles    []string `json:"roles"`
+	SellerId int      `json:"seller_id,omitempty"`
+}
+
+// SellerScope returns the seller whose data the principal is limited to.
+// Seller services are scoped to their own seller; admins never are.
+func (p Principal) SellerScope() (int, bool) {
+	if p.HasRole(RoleAdmin) || !p.HasRole(RoleSellerService) {
+		return 0, false
+	}
+	return p.SellerId, true
+}
+
+// Can reports whether any of the principal's roles grants the permission.
+func (p Principal) Can(permission Permission) bool {
+	for _, role := range p.Roles {
+		if RoleGrants(role, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return ctxkey.With(ctx, ctxkey.Principal, principal)
+}
+
+// PrincipalFrom returns the caller of the request ctx belongs to.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctxkey.Value(ctx, ctxkey.Principal).(Principal)
+	return principal, ok
+}
+
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+//go:generate mockgen -source=./auth.go -destination=./mock/auth_mock.go
+type Repository interface {
+	GetAll(ctx context.Context) ([]APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (APIKey, error)
+	Create(ctx context.Context, arg APIKey) (APIKey, error)
+	Revoke(ctx context.Context, id int, at date.DateTime) error
+}
+
+type Service interface {
+	GetAll(ctx context.Context) ([]APIKey, error)
+	Create(ctx context.Context, name string, roles []string, sellerId int) (APIKey, string, error)
+	Revoke(ctx context.Context, id int) error
+	Authenticate(ctx context.Context, key string) (Principal, error)
+}
@@ -0,0 +1,105 @@
+package domain
+
+const (
+	RoleAdmin             = "admin"
+	RoleWarehouseOperator = "warehouse_operator"
+	RoleBuyerService      = "buyer_service"
+	RoleSellerService     = "seller_service"
+)
+
+const (
+	ActionRead   = "read"
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	// ActionRestore undoes a soft delete. No role but admin is granted it.
+	ActionRestore = "restore"
+	// ActionCascade is a delete that also removes the record's dependents.
+	// Like restore, only admins are granted it.
+	ActionCascade = "cascade"
+)
+
+const (
+	ResourceAPIKeys        = "api_keys"
+	ResourceAudit          = "audit"
+	ResourceBuyers         = "buyers"
+	ResourceCarriers       = "carriers"
+	ResourceEmployees      = "employees"
+	ResourceInboundOrders  = "inbound_orders"
+	ResourceLocalities     = "localities"
+	ResourceOrderStatuses  = "order_statuses"
+	ResourceProductBatches = "product_batches"
+	ResourceProductRecords = "product_records"
+	ResourceProductTypes   = "product_types"
+	ResourceProducts       = "products"
+	ResourcePurchaseOrders = "purchase_orders"
+	ResourceSections       = "sections"
+	ResourceSellers        = "sellers"
+	ResourceWarehouses     = "warehouses"
+)
+
+// Permission is an action on a resource, written as "resource:action".
+type Permission struct {
+	Resource string
+	Action   string
+}
+
+func (p Permission) String() string {
+	return p.Resource + ":" + p.Action
+}
+
+var (
+	readOnly  = []string{ActionRead}
+	readWrite = []string{ActionRead, ActionCreate, ActionUpdate}
+)
+
+// permissions is the matrix of what each role may do. Admins are not listed:
+// they may do everything.
+var permissions = map[string]map[string][]string{
+	RoleWarehouseOperator: {
+		ResourceBuyers:         readOnly,
+		ResourceCarriers:       readOnly,
+		ResourceEmployees:      readOnly,
+		ResourceInboundOrders:  {ActionRead, ActionCreate},
+		ResourceLocalities:     readOnly,
+		ResourceOrderStatuses:  readOnly,
+		ResourceProductBatches: {ActionRead, ActionCreate},
+		ResourceProductRecords: readOnly,
+		ResourceProductTypes:   readOnly,
+		ResourceProducts:       readOnly,
+		ResourcePurchaseOrders: readOnly,
+		ResourceSections:       readWrite,
+		ResourceSellers:        readOnly,
+		ResourceWarehouses:     {ActionRead, ActionUpdate},
+	},
+	RoleBuyerService: {
+		ResourceBuyers:         readWrite,
+		ResourceCarriers:       readOnly,
+		ResourceLocalities:     readOnly,
+		ResourceOrderStatuses:  readOnly,
+		ResourceProductRecords: readOnly,
+		ResourceProducts:       readOnly,
+		ResourcePurchaseOrders: {ActionRead, ActionCreate, ActionDelete},
+	},
+	RoleSellerService: {
+		ResourceLocalities:     readOnly,
+		ResourceProductBatches: readOnly,
+		ResourceProductRecords: {ActionRead, ActionCreate},
+		ResourceProductTypes:   readOnly,
+		ResourceProducts:       readWrite,
+		ResourceSellers:        {ActionRead, ActionUpdate},
+	},
+}
+
+func RoleGrants(role string, permission Permission) bool {
+	if role == RoleAdmin {
+		return true
+	}
+
+	for _, action := range permissions[role][permission.Resource] {
+		if action == permission.Action {
+			return true
+		}
+	}
+	return false
+}
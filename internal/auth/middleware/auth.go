@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/ctxkey"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/jwt"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+var errMissingCredentials = errors.New("missing credentials")
+
+// Authenticate rejects requests that carry neither a valid API key in the
+// X-API-Key header nor a valid bearer token, and stores the caller in the
+// context for the handlers that follow.
+func Authenticate(service domain.Service, verifier jwt.Verifier) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var (
+			principal domain.Principal
+			err       error
+		)
+
+		if key := ctx.GetHeader(APIKeyHeader); key != "" {
+			principal, err = service.Authenticate(ctx, key)
+		} else if token, ok := bearerToken(ctx); ok && verifier.Enabled() {
+			principal, err = fromToken(verifier, token)
+		} else {
+			err = errMissingCredentials
+		}
+
+		if err != nil {
+			if !isCredentialError(err) {
+				logger.Error(ctx, store.GetPathWithLine(), err.Error())
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, response.DecodeError(err.Error()))
+				return
+			}
+
+			ctx.Header("WWW-Authenticate", `Bearer realm="mercado-fresco"`)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response.DecodeError(err.Error()))
+			return
+		}
+
+		ctxkey.Set(ctx, ctxkey.Principal, principal)
+		if sellerId, scoped := principal.SellerScope(); scoped {
+			ctxkey.Set(ctx, ctxkey.SellerId, sellerId)
+		}
+		ctx.Next()
+	}
+}
+
+// Authorize checks that the caller may perform the request on resource. The
+// action is taken from the HTTP method, and a 403 names the permission the
+// caller lacks.
+func Authorize(resource string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		permission := domain.Permission{Resource: resource, Action: action(ctx)}
+
+		principal, ok := GetPrincipal(ctx)
+		if !ok || !principal.Can(permission) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, response.DecodeError("missing permission "+permission.String()))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func action(ctx *gin.Context) string {
+	switch ctx.Request.Method {
+	case http.MethodPost:
+		if strings.HasSuffix(ctx.FullPath(), "/restore") {
+			return domain.ActionRestore
+		}
+		return domain.ActionCreate
+	case http.MethodPut, http.MethodPatch:
+		return domain.ActionUpdate
+	case http.MethodDelete:
+		if cascade, _ := strconv.ParseBool(ctx.Query(dependents.QueryParam)); cascade {
+			return domain.ActionCascade
+		}
+		return domain.ActionDelete
+	}
+	return domain.ActionRead
+}
+
+// GetPrincipal returns the caller stored by Authenticate.
+func GetPrincipal(ctx *gin.Context) (domain.Principal, bool) {
+	return domain.PrincipalFrom(ctx)
+}
+
+func bearerToken(ctx *gin.Context) (string, bool) {
+	scheme, token, found := strings.Cut(ctx.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func fromToken(verifier jwt.Verifier, token string) (domain.Principal, error) {
+	claims, err := verifier.Parse(token)
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	if claims.Subject == "" {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+
+	roles := claims.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	return domain.Principal{
+		Subject:  claims.Subject,
+		Method:   domain.MethodJWT,
+		Roles:    roles,
+		SellerId: claims.SellerId,
+	}, nil
+}
+
+func isCredentialError(err error) bool {
+	for _, target := range []error{
+		errMissingCredentials,
+		domain.ErrInvalidCredentials,
+		jwt.ErrMalformed,
+		jwt.ErrUnsupportedAlg,
+		jwt.ErrInvalidSignature,
+		jwt.ErrExpired,
+		jwt.ErrNotYetValid,
+		jwt.ErrInvalidIssuer,
+		jwt.ErrInvalidAudience,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,290 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/jwt"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var secret = []byte("test-secret")
+
+func newRouter(service domain.Service, verifier jwt.Verifier, handlers ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	group := router.Group("/api/v1", Authenticate(service, verifier))
+	group.GET("/resource", append(handlers, func(ctx *gin.Context) {
+		principal, _ := GetPrincipal(ctx)
+		ctx.JSON(http.StatusOK, principal)
+	})...)
+
+	return router
+}
+
+func doRequest(router *gin.Engine, header, value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/resource", nil)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func claims() jwt.Claims {
+	return jwt.Claims{
+		Subject:   "user-1",
+		Issuer:    "mercado-fresco",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		Roles:     []string{domain.RoleAdmin},
+	}
+}
+
+func TestAuthenticate_APIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+
+	expected := domain.Principal{Subject: "api_key:1", Method: domain.MethodAPIKey, Roles: []string{}}
+	service.EXPECT().Authenticate(gomock.Any(), "mf_a1b2c3d4_secret").Return(expected, nil)
+
+	rr := doRequest(newRouter(service, jwt.Verifier{}), APIKeyHeader, "mf_a1b2c3d4_secret")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var principal domain.Principal
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &principal))
+	assert.Equal(t, expected, principal)
+}
+
+func TestAuthenticate_InvalidAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+	service.EXPECT().Authenticate(gomock.Any(), "mf_a1b2c3d4_wrong").Return(domain.Principal{}, domain.ErrInvalidCredentials)
+
+	rr := doRequest(newRouter(service, jwt.Verifier{}), APIKeyHeader, "mf_a1b2c3d4_wrong")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+
+	var body response.Response
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "invalid credentials", body.Error)
+}
+
+func TestAuthenticate_MissingCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+
+	rr := doRequest(newRouter(service, jwt.Verifier{Secret: secret}), "", "")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// Bearer tokens are ignored when no key is configured to verify them.
+	token, err := jwt.SignHS256(claims(), secret)
+	assert.NoError(t, err)
+
+	rr = doRequest(newRouter(service, jwt.Verifier{}), "Authorization", "Bearer "+token)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestAuthenticate_HS256(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+	router := newRouter(service, jwt.Verifier{Secret: secret, Issuer: "mercado-fresco"})
+
+	t.Run("should accept a valid token", func(t *testing.T) {
+		token, err := jwt.SignHS256(claims(), secret)
+		assert.NoError(t, err)
+
+		rr := doRequest(router, "Authorization", "Bearer "+token)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var principal domain.Principal
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &principal))
+		assert.Equal(t, domain.Principal{Subject: "user-1", Method: domain.MethodJWT, Roles: []string{domain.RoleAdmin}}, principal)
+	})
+
+	t.Run("should reject an expired token", func(t *testing.T) {
+		expired := claims()
+		expired.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+
+		token, err := jwt.SignHS256(expired, secret)
+		assert.NoError(t, err)
+
+		rr := doRequest(router, "Authorization", "Bearer "+token)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), jwt.ErrExpired.Error())
+	})
+
+	t.Run("should reject a token signed with another secret", func(t *testing.T) {
+		token, err := jwt.SignHS256(claims(), []byte("other"))
+		assert.NoError(t, err)
+
+		rr := doRequest(router, "Authorization", "Bearer "+token)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), jwt.ErrInvalidSignature.Error())
+	})
+
+	t.Run("should reject a token from another issuer", func(t *testing.T) {
+		other := claims()
+		other.Issuer = "someone-else"
+
+		token, err := jwt.SignHS256(other, secret)
+		assert.NoError(t, err)
+
+		rr := doRequest(router, "Authorization", "Bearer "+token)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestAuthenticate_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+	router := newRouter(service, jwt.Verifier{PublicKey: &key.PublicKey})
+
+	token, err := jwt.SignRS256(claims(), key)
+	assert.NoError(t, err)
+
+	rr := doRequest(router, "Authorization", "Bearer "+token)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// An HS256 token must not be accepted when only RS256 is configured.
+	token, err = jwt.SignHS256(claims(), secret)
+	assert.NoError(t, err)
+
+	rr = doRequest(router, "Authorization", "Bearer "+token)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), jwt.ErrUnsupportedAlg.Error())
+}
+
+func TestAuthorize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	group := router.Group("/api/v1", Authenticate(service, jwt.Verifier{Secret: secret}))
+	sellers := group.Group("/sellers", Authorize(domain.ResourceSellers))
+	sellers.GET("/:id", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	sellers.DELETE("/:id", func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) })
+
+	tokenFor := func(roles ...string) string {
+		c := claims()
+		c.Roles = roles
+
+		token, err := jwt.SignHS256(c, secret)
+		assert.NoError(t, err)
+		return "Bearer " + token
+	}
+
+	testCases := []struct {
+		name     string
+		method   string
+		roles    []string
+		expected int
+	}{
+		{"admin may delete", http.MethodDelete, []string{domain.RoleAdmin}, http.StatusNoContent},
+		{"warehouse operator may read", http.MethodGet, []string{domain.RoleWarehouseOperator}, http.StatusOK},
+		{"warehouse operator may not delete", http.MethodDelete, []string{domain.RoleWarehouseOperator}, http.StatusForbidden},
+		{"buyer service may not read", http.MethodGet, []string{domain.RoleBuyerService}, http.StatusForbidden},
+		{"any granting role is enough", http.MethodGet, []string{domain.RoleBuyerService, domain.RoleSellerService}, http.StatusOK},
+		{"no roles", http.MethodGet, nil, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/api/v1/sellers/1", nil)
+			req.Header.Set("Authorization", tokenFor(tc.roles...))
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.expected, rr.Code)
+		})
+	}
+
+	t.Run("only admins may cascade a delete", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/sellers/1?cascade=true", nil)
+		req.Header.Set("Authorization", tokenFor(domain.RoleAdmin))
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+
+		req = httptest.NewRequest(http.MethodDelete, "/api/v1/sellers/1?cascade=true", nil)
+		req.Header.Set("Authorization", tokenFor(domain.RoleSellerService))
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		var body response.Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Equal(t, "missing permission sellers:cascade", body.Error)
+	})
+
+	t.Run("should name the missing permission", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/sellers/1", nil)
+		req.Header.Set("Authorization", tokenFor(domain.RoleWarehouseOperator))
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		var body response.Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, "missing permission sellers:delete", body.Error)
+	})
+}
+
+func TestAuthenticate_SellerScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/resource", Authenticate(service, jwt.Verifier{Secret: secret}), func(ctx *gin.Context) {
+		sellerId, scoped := tenant.SellerId(ctx)
+		ctx.JSON(http.StatusOK, gin.H{"seller_id": sellerId, "scoped": scoped})
+	})
+
+	testCases := []struct {
+		name     string
+		roles    []string
+		expected string
+	}{
+		{"seller service is scoped", []string{domain.RoleSellerService}, `{"scoped":true,"seller_id":5}`},
+		{"admin is not scoped", []string{domain.RoleAdmin, domain.RoleSellerService}, `{"scoped":false,"seller_id":0}`},
+		{"other roles are not scoped", []string{domain.RoleWarehouseOperator}, `{"scoped":false,"seller_id":0}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := claims()
+			c.Roles = tc.roles
+			c.SellerId = 5
+
+			token, err := jwt.SignHS256(c, secret)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			assert.JSONEq(t, tc.expected, rr.Body.String())
+		})
+	}
+}
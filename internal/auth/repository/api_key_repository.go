@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+)
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domain.Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row scanner) (domain.APIKey, error) {
+	var (
+		key      domain.APIKey
+		roles    string
+		sellerId sql.NullInt64
+	)
+
+	err := row.Scan(
+		&key.Id,
+		&key.Name,
+		&key.Prefix,
+		&key.Hash,
+		&roles,
+		&sellerId,
+		&key.CreatedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	key.Roles = splitRoles(roles)
+	key.SellerId = int(sellerId.Int64)
+
+	return key, nil
+}
+
+func splitRoles(roles string) []string {
+	if roles == "" {
+		return []string{}
+	}
+	return strings.Split(roles, ",")
+}
+
+func (r repository) GetAll(ctx context.Context) ([]domain.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, sqlGetAll)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	keys := []domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (r repository) GetByPrefix(ctx context.Context, prefix string) (domain.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, sqlGetByPrefix, prefix))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.APIKey{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+func (r repository) Create(ctx context.Context, arg domain.APIKey) (domain.APIKey, error) {
+	result, err := r.db.ExecContext(
+		ctx,
+		sqlCreate,
+		arg.Name,
+		arg.Prefix,
+		arg.Hash,
+		strings.Join(arg.Roles, ","),
+		sql.NullInt64{Int64: int64(arg.SellerId), Valid: arg.SellerId != 0},
+		arg.CreatedAt,
+	)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.APIKey{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.APIKey{}, err
+	}
+
+	arg.Id = int(id)
+
+	return arg, nil
+}
+
+func (r repository) Revoke(ctx context.Context, id int, at date.DateTime) error {
+	result, err := r.db.ExecContext(ctx, sqlRevoke, at, id)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
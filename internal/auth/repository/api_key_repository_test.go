@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	columns   = []string{"id", "name", "prefix", "key_hash", "roles", "seller_id", "created_at", "revoked_at"}
+	createdAt = date.NewDateTime(time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC))
+)
+
+func TestRepository_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, "buyers", "a1b2c3d4", "hash", "admin", nil, "2022-08-01 10:00:00", nil).
+		AddRow(2, "sellers", "e5f6a7b8", "hash", "seller_service", 5, "2022-08-01 10:00:00", "2022-08-02 10:00:00")
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetAll)).WillReturnRows(rows)
+
+	keys, err := NewRepository(db).GetAll(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, []string{"admin"}, keys[0].Roles)
+	assert.False(t, keys[0].Revoked())
+	assert.Equal(t, 5, keys[1].SellerId)
+	assert.True(t, keys[1].Revoked())
+}
+
+func TestRepository_GetByPrefix(t *testing.T) {
+	t.Run("should return the key", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, "buyers", "a1b2c3d4", "hash", "admin,buyer_service", nil, createdAt.String(), nil)
+
+		mock.ExpectQuery(regexp.QuoteMeta(sqlGetByPrefix)).WithArgs("a1b2c3d4").WillReturnRows(rows)
+
+		key, err := NewRepository(db).GetByPrefix(context.TODO(), "a1b2c3d4")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"admin", "buyer_service"}, key.Roles)
+		assert.True(t, key.CreatedAt.Equal(createdAt.Time))
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(sqlGetByPrefix)).WithArgs("a1b2c3d4").WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err = NewRepository(db).GetByPrefix(context.TODO(), "a1b2c3d4")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	arg := domain.APIKey{
+		Name:      "buyers",
+		Prefix:    "a1b2c3d4",
+		Hash:      "hash",
+		Roles:     []string{"admin", "buyer_service"},
+		CreatedAt: createdAt,
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlCreate)).
+		WithArgs(arg.Name, arg.Prefix, arg.Hash, "admin,buyer_service", nil, "2022-08-01 10:00:00").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	key, err := NewRepository(db).Create(context.TODO(), arg)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, key.Id)
+}
+
+func TestRepository_Revoke(t *testing.T) {
+	t.Run("should revoke the key", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(sqlRevoke)).
+			WithArgs("2022-08-01 10:00:00", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, NewRepository(db).Revoke(context.TODO(), 1, createdAt))
+	})
+
+	t.Run("should return not found when nothing was revoked", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(sqlRevoke)).
+			WithArgs("2022-08-01 10:00:00", 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, NewRepository(db).Revoke(context.TODO(), 1, createdAt), domain.ErrNotFound)
+	})
+
+	t.Run("should return the database error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(sqlRevoke)).
+			WithArgs("2022-08-01 10:00:00", 1).
+			WillReturnError(errors.New("connection refused"))
+
+		assert.EqualError(t, NewRepository(db).Revoke(context.TODO(), 1, createdAt), "connection refused")
+	})
+}
@@ -0,0 +1,21 @@
+package repository
+
+const (
+	sqlGetAll = `
+		SELECT id, name, prefix, key_hash, roles, seller_id, created_at, revoked_at
+		FROM api_keys`
+
+	sqlGetByPrefix = `
+		SELECT id, name, prefix, key_hash, roles, seller_id, created_at, revoked_at
+		FROM api_keys
+		WHERE prefix = ?`
+
+	sqlCreate = `
+		INSERT INTO api_keys (name, prefix, key_hash, roles, seller_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	sqlRevoke = `
+		UPDATE api_keys
+		SET revoked_at = ?
+		WHERE id = ? AND revoked_at IS NULL`
+)
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+)
+
+const (
+	keyScheme   = "mf"
+	prefixBytes = 4
+	secretBytes = 24
+)
+
+type service struct {
+	repository domain.Repository
+}
+
+func NewService(r domain.Repository) domain.Service {
+	return &service{
+		repository: r,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.APIKey, error) {
+	keys, err := s.repository.GetAll(ctx)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Create stores a new key and returns it together with the plain key, which
+// is not kept anywhere and cannot be recovered afterwards.
+func (s *service) Create(ctx context.Context, name string, roles []string, sellerId int) (domain.APIKey, string, error) {
+	prefix, err := randomHex(prefixBytes)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.APIKey{}, "", err
+	}
+
+	secret, err := randomHex(secretBytes)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.APIKey{}, "", err
+	}
+
+	key, err := s.repository.Create(ctx, domain.APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hash(secret),
+		Roles:     roles,
+		SellerId:  sellerId,
+		CreatedAt: date.Now(),
+	})
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.APIKey{}, "", err
+	}
+
+	return key, keyScheme + "_" + prefix + "_" + secret, nil
+}
+
+func (s *service) Revoke(ctx context.Context, id int) error {
+	return s.repository.Revoke(ctx, id, date.Now())
+}
+
+func (s *service) Authenticate(ctx context.Context, key string) (domain.Principal, error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != keyScheme {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+
+	apiKey, err := s.repository.GetByPrefix(ctx, parts[1])
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.Principal{}, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash(parts[2])), []byte(apiKey.Hash)) != 1 || apiKey.Revoked() {
+		return domain.Principal{}, domain.ErrInvalidCredentials
+	}
+
+	return domain.Principal{
+		Subject:  "api_key:" + strconv.Itoa(apiKey.Id),
+		Method:   domain.MethodAPIKey,
+		Roles:    apiKey.Roles,
+		SellerId: apiKey.SellerId,
+	}, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mock_domain.NewMockRepository(ctrl)
+
+	var stored domain.APIKey
+	repository.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg domain.APIKey) (domain.APIKey, error) {
+			arg.Id = 1
+			stored = arg
+			return arg, nil
+		})
+
+	key, plain, err := NewService(repository).Create(context.TODO(), "sellers", []string{domain.RoleSellerService}, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, key.Id)
+	assert.Equal(t, 5, stored.SellerId)
+	assert.False(t, key.CreatedAt.IsZero())
+
+	parts := strings.Split(plain, "_")
+	assert.Len(t, parts, 3)
+	assert.Equal(t, "mf", parts[0])
+	assert.Equal(t, stored.Prefix, parts[1])
+	assert.Equal(t, hash(parts[2]), stored.Hash)
+	assert.NotContains(t, stored.Hash, parts[2])
+}
+
+func TestService_Authenticate(t *testing.T) {
+	stored := domain.APIKey{
+		Id:     7,
+		Prefix: "a1b2c3d4",
+		Hash:   hash("secret"),
+		Roles:  []string{domain.RoleAdmin},
+	}
+
+	t.Run("should return the principal of a valid key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().GetByPrefix(gomock.Any(), "a1b2c3d4").Return(stored, nil)
+
+		principal, err := NewService(repository).Authenticate(context.TODO(), "mf_a1b2c3d4_secret")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Principal{
+			Subject: "api_key:7",
+			Method:  domain.MethodAPIKey,
+			Roles:   []string{domain.RoleAdmin},
+		}, principal)
+	})
+
+	t.Run("should reject a wrong secret", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().GetByPrefix(gomock.Any(), "a1b2c3d4").Return(stored, nil)
+
+		_, err := NewService(repository).Authenticate(context.TODO(), "mf_a1b2c3d4_wrong")
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	})
+
+	t.Run("should reject a revoked key", func(t *testing.T) {
+		revoked := stored
+		revoked.RevokedAt = date.Now()
+
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().GetByPrefix(gomock.Any(), "a1b2c3d4").Return(revoked, nil)
+
+		_, err := NewService(repository).Authenticate(context.TODO(), "mf_a1b2c3d4_secret")
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	})
+
+	t.Run("should reject an unknown prefix", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().GetByPrefix(gomock.Any(), "ffffffff").Return(domain.APIKey{}, domain.ErrNotFound)
+
+		_, err := NewService(repository).Authenticate(context.TODO(), "mf_ffffffff_secret")
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	})
+
+	t.Run("should reject a malformed key without querying", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+
+		_, err := NewService(repository).Authenticate(context.TODO(), "not-a-key")
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	})
+
+	t.Run("should return repository errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().GetByPrefix(gomock.Any(), "a1b2c3d4").Return(domain.APIKey{}, errors.New("connection refused"))
+
+		_, err := NewService(repository).Authenticate(context.TODO(), "mf_a1b2c3d4_secret")
+		assert.EqualError(t, err, "connection refused")
+	})
+}
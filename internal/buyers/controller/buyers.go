@@ -1,22 +1,43 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/bulk"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/patch"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
 )
 
+const (
+	defaultTopProducts = 5
+	maxTopProducts     = 100
+)
+
 type BuyerController struct {
 	service domain.Service
 }
 
 type buyerRequest struct {
-	CardNumberId string `json:"card_number_id"`
-	FirstName    string `json:"first_name"`
-	LastName     string `json:"last_name"`
+	CardNumberId string `json:"card_number_id" binding:"required"`
+	FirstName    string `json:"first_name" binding:"required"`
+	LastName     string `json:"last_name" binding:"required"`
+}
+
+type buyerUpdateRequest struct {
+	CardNumberId *string `json:"card_number_id" binding:"omitempty,min=1"`
+	FirstName    *string `json:"first_name" binding:"omitempty,min=1"`
+	LastName     *string `json:"last_name" binding:"omitempty,min=1"`
 }
 
 func NewBuyer(s domain.Service) *BuyerController {
@@ -31,11 +52,18 @@ func NewBuyer(s domain.Service) *BuyerController {
 // @Tags Buyers
 // @Description get buyers
 // @Produce  json
+// @Param include_deleted query bool false "Include soft deleted buyers"
 // @Success 200 {array} buyers.Buyer
+// @Failure 400 {object} string
 // @Failure 404 {object} string
 // @Router /api/v1/buyers [get]
 func (c *BuyerController) GetAll() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if err := softdelete.FromQuery(ctx); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		s, err := c.service.GetAll(ctx)
 		if err != nil {
 			ctx.JSON(http.StatusNotFound, gin.H{
@@ -55,7 +83,9 @@ func (c *BuyerController) GetAll() gin.HandlerFunc {
 // @Accept  json
 // @Produce  json
 // @Param id   path int true "Buyer ID"
+// @Param include_deleted query bool false "Include soft deleted buyers"
 // @Success 200 {object} buyers.Buyer
+// @Failure 400 {object} string
 // @Failure 404 {object} string
 // @Router /api/v1/buyers/{id} [get]
 func (c *BuyerController) GetById() gin.HandlerFunc {
@@ -66,12 +96,18 @@ func (c *BuyerController) GetById() gin.HandlerFunc {
 			return
 		}
 
+		if err := softdelete.FromQuery(ctx); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		s, err := c.service.GetById(ctx, int(id))
 		if err != nil {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
 
+		etag.Set(ctx, s.Version)
 		ctx.JSON(http.StatusOK, response.NewResponse(s))
 	}
 }
@@ -97,6 +133,56 @@ func (c *BuyerController) GetOrdersByBuyers() gin.HandlerFunc {
 	}
 }
 
+// Analytics godoc
+// @Summary Buyer purchase analytics
+// @Tags Buyers
+// @Description orders over time, total spend, top products and average days between orders of a buyer
+// @Produce  json
+// @Param id   path int true "Buyer ID"
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD"
+// @Param period query string false "Grouping of orders over time: day, week or month (default)"
+// @Param top query int false "Number of top products, 5 by default"
+// @Success 200 {object} domain.PurchaseAnalytics
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/buyers/{id}/analytics [get]
+func (c *BuyerController) Analytics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+			return
+		}
+
+		dates, err := period.RangeFromQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
+		unit, err := period.UnitFromQuery(ctx, period.Month)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
+		top, err := strconv.Atoi(ctx.DefaultQuery("top", strconv.Itoa(defaultTopProducts)))
+		if err != nil || top < 1 || top > maxTopProducts {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(fmt.Sprintf("top must be between 1 and %d", maxTopProducts)))
+			return
+		}
+
+		analytics, err := c.service.GetPurchaseAnalytics(ctx, int(id), dates, unit, top)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, response.DecodeError(err.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(analytics))
+	}
+}
+
 // Create godoc
 // @Summary Create buyers
 // @Tags Buyers
@@ -112,7 +198,7 @@ func (c *BuyerController) Create() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var req buyerRequest
 		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			ctx.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 			return
 		}
 
@@ -138,22 +224,48 @@ func (c *BuyerController) Create() gin.HandlerFunc {
 			return
 		}
 
+		etag.Set(ctx, s.Version)
 		ctx.JSON(http.StatusCreated, response.NewResponse(s))
 	}
 
 }
 
+// ImportBuyers godoc
+// @Summary Import buyers
+// @Tags Buyers
+// @Description Create buyers in bulk from a CSV or NDJSON upload, reporting the outcome of each row
+// @Accept text/csv,application/x-ndjson,multipart/form-data
+// @Produce json
+// @Param atomic query bool false "Keep no buyer when any row fails"
+// @Param file formData file false "CSV or NDJSON file, for multipart uploads"
+// @Success 200 {object} bulk.Report
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} bulk.Report
+// @Router /api/v1/buyers/import [post]
+func (c *BuyerController) Import(runner transaction.Runner) gin.HandlerFunc {
+	return bulk.Handler(runner, func(ctx context.Context, req buyerRequest) (int, error) {
+		buyer, err := c.service.Create(ctx, req.CardNumberId, req.FirstName, req.LastName)
+		if err != nil {
+			return 0, err
+		}
+		return buyer.Id, nil
+	})
+}
+
 // ListSellers godoc
 // @Summary Update buyer
 // @Tags Buyers
-// @Description update buyer
+// @Description update buyer with a JSON merge patch (RFC 7396)
 // @Accept  json
 // @Produce  json
-// @Param buyer body buyerRequest true "Buyer to create"
+// @Param buyer body buyerUpdateRequest true "Fields to update"
 // @Param id   path int true "Buyer ID"
+// @Param If-Match header string true "ETag of the buyer being updated"
 // @Success 200 {object} buyers.Buyer
 // @Failure 400 {object} string
 // @Failure 404 {object} string
+// @Failure 412 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Router /api/v1/buyers/{id} [patch]
 func (s *BuyerController) Update() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -163,17 +275,28 @@ func (s *BuyerController) Update() gin.HandlerFunc {
 			return
 		}
 
-		var req buyerRequest
-		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		if err := etag.FromIfMatch(ctx); err != nil {
+			ctx.JSON(etag.Status(err), response.DecodeError(err.Error()))
 			return
 		}
 
-		s, err := s.service.Update(ctx, int(id), req.CardNumberId, req.FirstName, req.LastName)
+		var req buyerUpdateRequest
+		if err := patch.Bind(ctx, &req); err != nil {
+			ctx.JSON(patch.Status(err), validation.NewErrorResponse(err))
+			return
+		}
+
+		s, err := s.service.Update(ctx, int(id), domain.BuyerPatch(req))
 		if err != nil {
+			if errors.Is(err, etag.ErrMismatch) {
+				ctx.JSON(http.StatusPreconditionFailed, response.DecodeError(err.Error()))
+				return
+			}
+
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		etag.Set(ctx, s.Version)
 		ctx.JSON(http.StatusOK, s)
 	}
 
@@ -182,9 +305,14 @@ func (s *BuyerController) Update() gin.HandlerFunc {
 // ListSellers godoc
 // @Summary Delete buyer
 // @Tags Buyers
-// @Description delete buyer
+// @Description delete buyer. Buyers with purchase orders cannot be deleted, the orders are kept as history
 // @Param id   path int true "Buyer ID"
+// @Param If-Match header string true "ETag of the buyer being deleted"
 // @Success 204 {object} request
+// @Failure 404 {object} string
+// @Failure 409 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Router /api/v1/buyers/{id} [delete]
 func (c *BuyerController) Delete() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -194,8 +322,24 @@ func (c *BuyerController) Delete() gin.HandlerFunc {
 			return
 		}
 
+		if err := etag.FromIfMatch(ctx); err != nil {
+			ctx.JSON(etag.Status(err), response.DecodeError(err.Error()))
+			return
+		}
+
 		err = c.service.Delete(ctx, int(id))
 		if err != nil {
+			if errors.Is(err, etag.ErrMismatch) {
+				ctx.JSON(http.StatusPreconditionFailed, response.DecodeError(err.Error()))
+				return
+			}
+
+			var dependentsErr *dependents.Error
+			if errors.As(err, &dependentsErr) {
+				ctx.JSON(http.StatusConflict, response.DecodeDependents(dependentsErr))
+				return
+			}
+
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
@@ -203,3 +347,196 @@ func (c *BuyerController) Delete() gin.HandlerFunc {
 		ctx.JSON(http.StatusNoContent, gin.H{"data": fmt.Sprintf("seller %d was removed", id)})
 	}
 }
+
+// Restore godoc
+// @Summary Restore buyer
+// @Tags Buyers
+// @Description restore a deleted buyer
+// @Produce  json
+// @Param id   path int true "Buyer ID"
+// @Success 200 {object} buyers.Buyer
+// @Failure 404 {object} string
+// @Router /api/v1/buyers/{id}/restore [post]
+func (c *BuyerController) Restore() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "invalid ID"})
+			return
+		}
+
+		s, err := c.service.Restore(ctx, int(id))
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		etag.Set(ctx, s.Version)
+		ctx.JSON(http.StatusOK, response.NewResponse(s))
+	}
+}
+
+type addressRequest struct {
+	Address    string `json:"address" binding:"required"`
+	LocalityId int    `json:"locality_id" binding:"required,min=1"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+type addressUpdateRequest struct {
+	Address    *string `json:"address" binding:"omitempty,min=1"`
+	LocalityId *int    `json:"locality_id" binding:"omitempty,min=1"`
+	IsDefault  *bool   `json:"is_default"`
+}
+
+// addressIds parses the buyer and address ids of the address routes.
+func addressIds(ctx *gin.Context) (buyerId, id int, err error) {
+	buyerId, err = strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return 0, 0, errors.New("invalid ID")
+	}
+	id, err = strconv.Atoi(ctx.Param("addressId"))
+	if err != nil {
+		return 0, 0, errors.New("invalid address ID")
+	}
+	return buyerId, id, nil
+}
+
+// GetAddresses godoc
+// @Summary List buyer addresses
+// @Tags Buyers
+// @Description get the delivery addresses of a buyer
+// @Produce  json
+// @Param id   path int true "Buyer ID"
+// @Success 200 {array} domain.Address
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/buyers/{id}/addresses [get]
+func (c *BuyerController) GetAddresses() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+			return
+		}
+
+		addresses, err := c.service.GetAddresses(ctx, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, response.DecodeError(err.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(addresses))
+	}
+}
+
+// CreateAddress godoc
+// @Summary Create buyer address
+// @Tags Buyers
+// @Description add a delivery address to a buyer. The first address of a buyer is the default one
+// @Accept  json
+// @Produce  json
+// @Param id   path int true "Buyer ID"
+// @Param address body addressRequest true "Address to create"
+// @Success 201 {object} domain.Address
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /api/v1/buyers/{id}/addresses [post]
+func (c *BuyerController) CreateAddress() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+			return
+		}
+
+		var req addressRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+
+		address, err := c.service.CreateAddress(ctx, domain.Address{
+			BuyerId:    id,
+			Address:    req.Address,
+			LocalityId: req.LocalityId,
+			IsDefault:  req.IsDefault,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, response.DecodeError(err.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, response.NewResponse(address))
+	}
+}
+
+// UpdateAddress godoc
+// @Summary Update buyer address
+// @Tags Buyers
+// @Description update a delivery address with a JSON merge patch (RFC 7396). Setting is_default moves the default flag to this address
+// @Accept  json
+// @Produce  json
+// @Param id   path int true "Buyer ID"
+// @Param addressId path int true "Address ID"
+// @Param address body addressUpdateRequest true "Fields to update"
+// @Success 200 {object} domain.Address
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /api/v1/buyers/{id}/addresses/{addressId} [patch]
+func (c *BuyerController) UpdateAddress() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		buyerId, id, err := addressIds(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
+		var req addressUpdateRequest
+		if err := patch.Bind(ctx, &req); err != nil {
+			ctx.JSON(patch.Status(err), validation.NewErrorResponse(err))
+			return
+		}
+
+		address, err := c.service.UpdateAddress(ctx, buyerId, id, domain.AddressPatch(req))
+		if err != nil {
+			if errors.Is(err, domain.ErrDefaultAddress) {
+				ctx.JSON(http.StatusConflict, response.DecodeError(err.Error()))
+				return
+			}
+			ctx.JSON(http.StatusNotFound, response.DecodeError(err.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(address))
+	}
+}
+
+// DeleteAddress godoc
+// @Summary Delete buyer address
+// @Tags Buyers
+// @Description delete a delivery address. When it was the default one, the oldest remaining address becomes the default
+// @Param id   path int true "Buyer ID"
+// @Param addressId path int true "Address ID"
+// @Success 204
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/buyers/{id}/addresses/{addressId} [delete]
+func (c *BuyerController) DeleteAddress() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		buyerId, id, err := addressIds(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
+		if err := c.service.DeleteAddress(ctx, buyerId, id); err != nil {
+			ctx.JSON(http.StatusNotFound, response.DecodeError(err.Error()))
+			return
+		}
+
+		ctx.Status(http.StatusNoContent)
+	}
+}
@@ -2,11 +2,18 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
 	mockbuyers "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -16,9 +23,10 @@ import (
 )
 
 const (
-	relativeBuyerPath       = "/api/v1/buyers/"
-	relativePathBuyersId    = "/api/v1/buyers/:id"
-	relativeOrdersBuyerPath = "/api/v1/buyers/reportPurchaseOrders"
+	relativeBuyerPath        = "/api/v1/buyers/"
+	relativePathBuyersId     = "/api/v1/buyers/:id"
+	relativeOrdersBuyerPath  = "/api/v1/buyers/reportPurchaseOrders"
+	relativeRestoreBuyerPath = "/api/v1/buyers/:id/restore"
 )
 
 func callBuyersMock(t *testing.T) (*mockbuyers.MockService, *BuyerController, *gin.Engine) {
@@ -81,6 +89,7 @@ func TestBuyersController_GetById(t *testing.T) {
 		CardNumberId: "1234",
 		FirstName:    "Mickey",
 		LastName:     "Mouse",
+		Version:      4,
 	}
 
 	service, handler, api := callBuyersMock(t)
@@ -98,6 +107,7 @@ func TestBuyersController_GetById(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.Equal(t, buyer.FirstName, respExpect.Data.FirstName)
+	assert.Equal(t, `"4"`, resp.Header().Get(etag.Header))
 }
 
 func TestBuyersController_GetById_NOK(t *testing.T) {
@@ -211,14 +221,13 @@ func TestBuyerController_Update_OK(t *testing.T) {
 	service.EXPECT().Update(
 		gomock.Any(),
 		1,
-		"1234",
-		"Silvio",
-		"Santos",
+		domain.BuyerPatch{CardNumberId: stringPtr("1234"), FirstName: stringPtr("Silvio"), LastName: stringPtr("Santos")},
 	).Return(&buyer, nil)
 
 	payload := `{"card_number_id": "1234", "first_name": "Silvio", "last_name": "Santos"}`
 
 	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/buyers/%s", "1"), bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
@@ -232,6 +241,7 @@ func TestBuyerController_Update_BadRequest(t *testing.T) {
 	payload := `{"card_number_id": "1234", "first_name": "Silvio", "last_name": "Santos"}`
 
 	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/buyers/%s", "máoi!"), bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
@@ -245,6 +255,7 @@ func TestBuyerController_Update_UnprocessableEntity(t *testing.T) {
 	payload := `{"card_number_id": "1234", "first_name": 25, "last_name": 35}`
 
 	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/buyers/%s", "1"), bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
@@ -258,20 +269,59 @@ func TestBuyerController_Update_NotFound(t *testing.T) {
 	service.EXPECT().Update(
 		gomock.Any(),
 		1,
-		"1234",
-		"Silvio",
-		"Santos",
+		domain.BuyerPatch{CardNumberId: stringPtr("1234"), FirstName: stringPtr("Silvio"), LastName: stringPtr("Santos")},
 	).Return(&domain.Buyer{}, errors.New("employee 1 not found"))
 
 	payload := `{"card_number_id": "1234", "first_name": "Silvio", "last_name": "Santos"}`
 
 	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/buyers/%s", "1"), bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
 
+func TestBuyerController_Update_UnknownField(t *testing.T) {
+	_, handler, api := callBuyersMock(t)
+	api.PATCH(relativePathBuyersId, handler.Update())
+
+	payload := `{"card_number": "1234"}`
+
+	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/buyers/%s", "1"), bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestBuyerController_Update_Precondition(t *testing.T) {
+	service, handler, api := callBuyersMock(t)
+	api.PATCH(relativePathBuyersId, handler.Update())
+
+	payload := `{"last_name": "Santos"}`
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/buyers/1", bytes.NewBuffer([]byte(payload)))
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+
+	service.EXPECT().Update(gomock.Any(), 1, domain.BuyerPatch{LastName: stringPtr("Santos")}).Return(nil, etag.ErrMismatch)
+
+	req = httptest.NewRequest(http.MethodPatch, "/api/v1/buyers/1", bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp = httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
 func TestBuyersController_Delete_OK(t *testing.T) {
 	service, handler, api := callBuyersMock(t)
 	api.DELETE(relativePathBuyersId, handler.Delete())
@@ -279,6 +329,7 @@ func TestBuyersController_Delete_OK(t *testing.T) {
 	service.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 
 	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/buyers/%s", "1"), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
@@ -292,17 +343,61 @@ func TestBuyersController_Delete_NOK(t *testing.T) {
 	service.EXPECT().Delete(gomock.Any(), 1).Return(errors.New("erro 404"))
 
 	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/buyers/%s", "1"), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
 
+func TestBuyersController_Delete_Conflict(t *testing.T) {
+	service, handler, api := callBuyersMock(t)
+	api.DELETE(relativePathBuyersId, handler.Delete())
+
+	service.EXPECT().Delete(gomock.Any(), 1).Return(&dependents.Error{
+		Entity:     "buyer",
+		Id:         1,
+		Dependents: []dependents.Dependent{{Type: "purchase_orders", Count: 4}},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/buyers/%s", "1"), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	var body response.Response
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, []dependents.Dependent{{Type: "purchase_orders", Count: 4}}, body.Dependents)
+}
+
+func TestBuyersController_Delete_Precondition(t *testing.T) {
+	service, handler, api := callBuyersMock(t)
+	api.DELETE(relativePathBuyersId, handler.Delete())
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/buyers/1", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+
+	service.EXPECT().Delete(gomock.Any(), 1).Return(etag.ErrMismatch)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/buyers/1", nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp = httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+}
+
 func TestBuyersController_Delete_BadRequest(t *testing.T) {
 	_, handler, api := callBuyersMock(t)
 	api.DELETE(relativePathBuyersId, handler.Delete())
 
 	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/buyers/%s", "hello"), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
@@ -361,3 +456,277 @@ func TestBuyersController_GetOrdersByBuyers_StatusNotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
+
+func TestBuyersController_GetAll_IncludeDeleted(t *testing.T) {
+	service, handler, api := callBuyersMock(t)
+
+	api.GET(relativeBuyerPath, handler.GetAll())
+
+	service.EXPECT().GetAll(gomock.Any()).DoAndReturn(func(ctx context.Context) ([]domain.Buyer, error) {
+		assert.True(t, softdelete.IncludeDeleted(ctx))
+		return []domain.Buyer{}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/buyers/?include_deleted=true", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestBuyersController_GetAll_InvalidIncludeDeleted(t *testing.T) {
+	_, handler, api := callBuyersMock(t)
+
+	api.GET(relativeBuyerPath, handler.GetAll())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/buyers/?include_deleted=maybe", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestBuyersController_Restore_OK(t *testing.T) {
+	buyer := domain.Buyer{Id: 1, CardNumberId: "1234", FirstName: "Mickey", LastName: "Mouse"}
+
+	service, handler, api := callBuyersMock(t)
+	api.POST(relativeRestoreBuyerPath, handler.Restore())
+	service.EXPECT().Restore(gomock.Any(), 1).Return(&buyer, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/buyers/1/restore", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	respExpect := struct{ Data domain.Buyer }{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &respExpect)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, buyer.FirstName, respExpect.Data.FirstName)
+}
+
+func TestBuyersController_Restore_NotFound(t *testing.T) {
+	service, handler, api := callBuyersMock(t)
+	api.POST(relativeRestoreBuyerPath, handler.Restore())
+	service.EXPECT().Restore(gomock.Any(), 1).Return(nil, errors.New("deleted buyer 1 not found"))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/buyers/1/restore", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestBuyerController_Analytics(t *testing.T) {
+	const path = "/api/v1/buyers/:id/analytics"
+
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.GET(path, handler.Analytics())
+
+		dates := period.Range{From: date.New(2022, 7, 1), To: date.New(2022, 8, 31)}
+		service.EXPECT().
+			GetPurchaseAnalytics(gomock.Any(), 1, dates, period.Week, 3).
+			Return(domain.PurchaseAnalytics{BuyerId: 1, Range: dates, Period: period.Week, OrdersCount: 2}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/buyers/1/analytics?from=2022-07-01&to=2022-08-31&period=week&top=3", nil)
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		body := struct {
+			Data domain.PurchaseAnalytics `json:"data"`
+		}{}
+		json.Unmarshal(resp.Body.Bytes(), &body)
+		assert.Equal(t, 2, body.Data.OrdersCount)
+		assert.Equal(t, dates, body.Data.Range)
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.GET(path, handler.Analytics())
+
+		service.EXPECT().
+			GetPurchaseAnalytics(gomock.Any(), 1, period.Range{}, period.Month, 5).
+			Return(domain.PurchaseAnalytics{}, nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/buyers/1/analytics", nil))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.GET(path, handler.Analytics())
+
+		service.EXPECT().
+			GetPurchaseAnalytics(gomock.Any(), 1, period.Range{}, period.Month, 5).
+			Return(domain.PurchaseAnalytics{}, errors.New("buyer 1 not found"))
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/buyers/1/analytics", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	for _, query := range []string{
+		"1/analytics?from=xpto",
+		"1/analytics?from=2022-09-01&to=2022-08-01",
+		"1/analytics?period=year",
+		"1/analytics?top=0",
+		"xpto/analytics",
+	} {
+		t.Run("Bad request "+query, func(t *testing.T) {
+			_, handler, api := callBuyersMock(t)
+			api.GET(path, handler.Analytics())
+
+			resp := httptest.NewRecorder()
+			api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, relativeBuyerPath+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
+
+func TestBuyerController_Addresses(t *testing.T) {
+	const (
+		path        = "/api/v1/buyers/:id/addresses"
+		addressPath = "/api/v1/buyers/:id/addresses/:addressId"
+	)
+	address := domain.Address{Id: 2, BuyerId: 1, Address: "Rua A, 10", LocalityId: 3, IsDefault: true}
+
+	t.Run("List", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.GET(path, handler.GetAddresses())
+
+		service.EXPECT().GetAddresses(gomock.Any(), 1).Return([]domain.Address{address}, nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/buyers/1/addresses", nil))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		body := struct {
+			Data []domain.Address `json:"data"`
+		}{}
+		json.Unmarshal(resp.Body.Bytes(), &body)
+		assert.Equal(t, []domain.Address{address}, body.Data)
+	})
+
+	t.Run("List buyer not found", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.GET(path, handler.GetAddresses())
+
+		service.EXPECT().GetAddresses(gomock.Any(), 1).Return(nil, errors.New("Buyer 1 not found"))
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/buyers/1/addresses", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.POST(path, handler.CreateAddress())
+
+		service.EXPECT().
+			CreateAddress(gomock.Any(), domain.Address{BuyerId: 1, Address: "Rua A, 10", LocalityId: 3}).
+			Return(&address, nil)
+
+		payload := []byte(`{"address": "Rua A, 10", "locality_id": 3}`)
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/buyers/1/addresses", bytes.NewBuffer(payload)))
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+	})
+
+	t.Run("Create without locality", func(t *testing.T) {
+		_, handler, api := callBuyersMock(t)
+		api.POST(path, handler.CreateAddress())
+
+		payload := []byte(`{"address": "Rua A, 10"}`)
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/buyers/1/addresses", bytes.NewBuffer(payload)))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("Create locality not found", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.POST(path, handler.CreateAddress())
+
+		service.EXPECT().CreateAddress(gomock.Any(), gomock.Any()).Return(nil, errors.New("locality 3 not found"))
+
+		payload := []byte(`{"address": "Rua A, 10", "locality_id": 3}`)
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/buyers/1/addresses", bytes.NewBuffer(payload)))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.PATCH(addressPath, handler.UpdateAddress())
+
+		isDefault := true
+		service.EXPECT().
+			UpdateAddress(gomock.Any(), 1, 2, domain.AddressPatch{IsDefault: &isDefault}).
+			Return(&address, nil)
+
+		payload := []byte(`{"is_default": true}`)
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPatch, "/api/v1/buyers/1/addresses/2", bytes.NewBuffer(payload)))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Update unsets default", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.PATCH(addressPath, handler.UpdateAddress())
+
+		service.EXPECT().
+			UpdateAddress(gomock.Any(), 1, 2, gomock.Any()).
+			Return(nil, fmt.Errorf("%w: address 2", domain.ErrDefaultAddress))
+
+		payload := []byte(`{"is_default": false}`)
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPatch, "/api/v1/buyers/1/addresses/2", bytes.NewBuffer(payload)))
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("Update invalid address id", func(t *testing.T) {
+		_, handler, api := callBuyersMock(t)
+		api.PATCH(addressPath, handler.UpdateAddress())
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPatch, "/api/v1/buyers/1/addresses/xpto", bytes.NewBuffer([]byte(`{}`))))
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.DELETE(addressPath, handler.DeleteAddress())
+
+		service.EXPECT().DeleteAddress(gomock.Any(), 1, 2).Return(nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/api/v1/buyers/1/addresses/2", nil))
+
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+	})
+
+	t.Run("Delete not found", func(t *testing.T) {
+		service, handler, api := callBuyersMock(t)
+		api.DELETE(addressPath, handler.DeleteAddress())
+
+		service.EXPECT().DeleteAddress(gomock.Any(), 1, 2).Return(errors.New("address 2 of buyer 1 not found"))
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/api/v1/buyers/1/addresses/2", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
@@ -1,12 +1,22 @@
 package domain
 
-import "golang.org/x/net/context"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"golang.org/x/net/context"
+)
 
 type Buyer struct {
-	Id           int    `json:"id"`
-	CardNumberId string `json:"card_number_id"`
-	FirstName    string `json:"first_name"`
-	LastName     string `json:"last_name"`
+	Id           int           `json:"id"`
+	CardNumberId string        `json:"card_number_id"`
+	FirstName    string        `json:"first_name"`
+	LastName     string        `json:"last_name"`
+	DeletedAt    date.DateTime `json:"deleted_at"`
+	Version      int           `json:"version"`
 }
 
 type OrdersByBuyers struct {
@@ -17,21 +27,127 @@ type OrdersByBuyers struct {
 	PurchaseOrdersCount int    `json:"purchase_orders_count"`
 }
 
+// Purchase is a line of an order: a quantity of a product bought at the
+// unit price the line was priced at. Orders made before orders had lines
+// are a single unit at the sale price of their product record.
+type Purchase struct {
+	OrderId     int
+	OrderDate   date.Date
+	ProductId   int
+	Description string
+	UnitPrice   float64
+	Quantity    int
+}
+
+// Amount is what the buyer spent on the purchase.
+func (p Purchase) Amount() float64 {
+	return p.UnitPrice * float64(p.Quantity)
+}
+
+// PurchaseAnalytics sums up the purchases of a buyer within a date range.
+type PurchaseAnalytics struct {
+	BuyerId int `json:"buyer_id"`
+	period.Range
+	Period      period.Unit `json:"period"`
+	OrdersCount int         `json:"orders_count"`
+	TotalSpend  float64     `json:"total_spend"`
+	// AverageOrderIntervalDays is null until there are two orders.
+	AverageOrderIntervalDays *float64         `json:"average_order_interval_days"`
+	OrdersOverTime           []PurchasePeriod `json:"orders_over_time"`
+	TopProducts              []TopProduct     `json:"top_products"`
+}
+
+type PurchasePeriod struct {
+	Period      string  `json:"period"`
+	OrdersCount int     `json:"orders_count"`
+	Spend       float64 `json:"spend"`
+}
+
+type TopProduct struct {
+	ProductId   int     `json:"product_id"`
+	Description string  `json:"description"`
+	OrdersCount int     `json:"orders_count"`
+	Spend       float64 `json:"spend"`
+}
+
+// BuyerPatch holds the fields of a partial update. Nil fields are left
+// untouched.
+type BuyerPatch struct {
+	CardNumberId *string `json:"card_number_id"`
+	FirstName    *string `json:"first_name"`
+	LastName     *string `json:"last_name"`
+}
+
+// Address is where the purchase orders of a buyer are delivered. Each buyer
+// with addresses has exactly one default address.
+type Address struct {
+	Id         int    `json:"id"`
+	BuyerId    int    `json:"buyer_id"`
+	Address    string `json:"address"`
+	LocalityId int    `json:"locality_id"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+// ErrDefaultAddress is returned when the default flag of the default address
+// is unset instead of being set on another address.
+var ErrDefaultAddress = errors.New("the default address can only change by setting another address as default")
+
+// ErrorNotFound is returned for a buyer that does not exist.
+type ErrorNotFound struct {
+	Id int
+}
+
+func (e *ErrorNotFound) Error() string {
+	return fmt.Sprintf("Buyer %d not found", e.Id)
+}
+
+// ErrorAddressNotFound is returned for an address the buyer does not have.
+type ErrorAddressNotFound struct {
+	BuyerId int
+	Id      int
+}
+
+func (e *ErrorAddressNotFound) Error() string {
+	return fmt.Sprintf("address %d of buyer %d not found", e.Id, e.BuyerId)
+}
+
+// AddressPatch holds the fields of a partial address update. Nil fields are
+// left untouched.
+type AddressPatch struct {
+	Address    *string `json:"address"`
+	LocalityId *int    `json:"locality_id"`
+	IsDefault  *bool   `json:"is_default"`
+}
+
 //go:generate mockgen -source=./buyers.go -destination=./mock/buyers_mock.go
 type Repository interface {
 	GetById(ctx context.Context, id int) (*Buyer, error)
-	GetAll(ctx context.Context,) ([]Buyer, error)
+	GetAll(ctx context.Context) ([]Buyer, error)
 	GetOrdersByBuyers(ctx context.Context, id int) ([]OrdersByBuyers, error)
+	GetPurchases(ctx context.Context, id int, r period.Range) ([]Purchase, error)
 	Create(ctx context.Context, cardNumberId, firstName, lastName string) (*Buyer, error)
-	Update(ctx context.Context, id int, cardNumberId, firstName, lastName string) (*Buyer, error)
+	Update(ctx context.Context, id int, arg BuyerPatch) (*Buyer, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Dependents(ctx context.Context, id int) ([]dependents.Dependent, error)
+	GetAddresses(ctx context.Context, buyerId int) ([]Address, error)
+	GetAddress(ctx context.Context, buyerId, id int) (*Address, error)
+	CreateAddress(ctx context.Context, arg Address) (*Address, error)
+	UpdateAddress(ctx context.Context, arg Address) error
+	DeleteAddress(ctx context.Context, buyerId, id int) error
 }
 
 type Service interface {
 	GetById(ctx context.Context, id int) (*Buyer, error)
 	GetAll(ctx context.Context) ([]Buyer, error)
 	GetOrdersByBuyers(ctx context.Context, id int) ([]OrdersByBuyers, error)
+	GetPurchaseAnalytics(ctx context.Context, id int, r period.Range, unit period.Unit, top int) (PurchaseAnalytics, error)
 	Create(ctx context.Context, cardNumberId, firstName, lastName string) (*Buyer, error)
-	Update(ctx context.Context, id int, cardNumberId, firstName, lastName string) (*Buyer, error)
+	Update(ctx context.Context, id int, arg BuyerPatch) (*Buyer, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) (*Buyer, error)
+	GetAddresses(ctx context.Context, buyerId int) ([]Address, error)
+	CreateAddress(ctx context.Context, arg Address) (*Address, error)
+	UpdateAddress(ctx context.Context, buyerId, id int, arg AddressPatch) (*Address, error)
+	DeleteAddress(ctx context.Context, buyerId, id int) error
 }
@@ -8,6 +8,8 @@ import (
 	reflect "reflect"
 
 	domain "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	dependents "github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	period "github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	gomock "github.com/golang/mock/gomock"
 	context "golang.org/x/net/context"
 )
@@ -50,6 +52,21 @@ func (mr *MockRepositoryMockRecorder) Create(ctx, cardNumberId, firstName, lastN
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, cardNumberId, firstName, lastName)
 }
 
+// CreateAddress mocks base method.
+func (m *MockRepository) CreateAddress(ctx context.Context, arg domain.Address) (*domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAddress", ctx, arg)
+	ret0, _ := ret[0].(*domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAddress indicates an expected call of CreateAddress.
+func (mr *MockRepositoryMockRecorder) CreateAddress(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAddress", reflect.TypeOf((*MockRepository)(nil).CreateAddress), ctx, arg)
+}
+
 // Delete mocks base method.
 func (m *MockRepository) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
@@ -64,6 +81,65 @@ func (mr *MockRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id)
 }
 
+// DeleteAddress mocks base method.
+func (m *MockRepository) DeleteAddress(ctx context.Context, buyerId, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddress", ctx, buyerId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAddress indicates an expected call of DeleteAddress.
+func (mr *MockRepositoryMockRecorder) DeleteAddress(ctx, buyerId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddress", reflect.TypeOf((*MockRepository)(nil).DeleteAddress), ctx, buyerId, id)
+}
+
+// Dependents mocks base method.
+func (m *MockRepository) Dependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dependents", ctx, id)
+	ret0, _ := ret[0].([]dependents.Dependent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dependents indicates an expected call of Dependents.
+func (mr *MockRepositoryMockRecorder) Dependents(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dependents", reflect.TypeOf((*MockRepository)(nil).Dependents), ctx, id)
+}
+
+// GetAddress mocks base method.
+func (m *MockRepository) GetAddress(ctx context.Context, buyerId, id int) (*domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddress", ctx, buyerId, id)
+	ret0, _ := ret[0].(*domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddress indicates an expected call of GetAddress.
+func (mr *MockRepositoryMockRecorder) GetAddress(ctx, buyerId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddress", reflect.TypeOf((*MockRepository)(nil).GetAddress), ctx, buyerId, id)
+}
+
+// GetAddresses mocks base method.
+func (m *MockRepository) GetAddresses(ctx context.Context, buyerId int) ([]domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddresses", ctx, buyerId)
+	ret0, _ := ret[0].([]domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddresses indicates an expected call of GetAddresses.
+func (mr *MockRepositoryMockRecorder) GetAddresses(ctx, buyerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddresses", reflect.TypeOf((*MockRepository)(nil).GetAddresses), ctx, buyerId)
+}
+
 // GetAll mocks base method.
 func (m *MockRepository) GetAll(ctx context.Context) ([]domain.Buyer, error) {
 	m.ctrl.T.Helper()
@@ -109,19 +185,62 @@ func (mr *MockRepositoryMockRecorder) GetOrdersByBuyers(ctx, id interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersByBuyers", reflect.TypeOf((*MockRepository)(nil).GetOrdersByBuyers), ctx, id)
 }
 
+// GetPurchases mocks base method.
+func (m *MockRepository) GetPurchases(ctx context.Context, id int, r period.Range) ([]domain.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchases", ctx, id, r)
+	ret0, _ := ret[0].([]domain.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchases indicates an expected call of GetPurchases.
+func (mr *MockRepositoryMockRecorder) GetPurchases(ctx, id, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchases", reflect.TypeOf((*MockRepository)(nil).GetPurchases), ctx, id, r)
+}
+
+// Restore mocks base method.
+func (m *MockRepository) Restore(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepository)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockRepository) Update(ctx context.Context, id int, cardNumberId, firstName, lastName string) (*domain.Buyer, error) {
+func (m *MockRepository) Update(ctx context.Context, id int, arg domain.BuyerPatch) (*domain.Buyer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, cardNumberId, firstName, lastName)
+	ret := m.ctrl.Call(m, "Update", ctx, id, arg)
 	ret0, _ := ret[0].(*domain.Buyer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(ctx, id, cardNumberId, firstName, lastName interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, id, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, id, cardNumberId, firstName, lastName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, id, arg)
+}
+
+// UpdateAddress mocks base method.
+func (m *MockRepository) UpdateAddress(ctx context.Context, arg domain.Address) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAddress", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAddress indicates an expected call of UpdateAddress.
+func (mr *MockRepositoryMockRecorder) UpdateAddress(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddress", reflect.TypeOf((*MockRepository)(nil).UpdateAddress), ctx, arg)
 }
 
 // MockService is a mock of Service interface.
@@ -162,6 +281,21 @@ func (mr *MockServiceMockRecorder) Create(ctx, cardNumberId, firstName, lastName
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), ctx, cardNumberId, firstName, lastName)
 }
 
+// CreateAddress mocks base method.
+func (m *MockService) CreateAddress(ctx context.Context, arg domain.Address) (*domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAddress", ctx, arg)
+	ret0, _ := ret[0].(*domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAddress indicates an expected call of CreateAddress.
+func (mr *MockServiceMockRecorder) CreateAddress(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAddress", reflect.TypeOf((*MockService)(nil).CreateAddress), ctx, arg)
+}
+
 // Delete mocks base method.
 func (m *MockService) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
@@ -176,6 +310,35 @@ func (mr *MockServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), ctx, id)
 }
 
+// DeleteAddress mocks base method.
+func (m *MockService) DeleteAddress(ctx context.Context, buyerId, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddress", ctx, buyerId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAddress indicates an expected call of DeleteAddress.
+func (mr *MockServiceMockRecorder) DeleteAddress(ctx, buyerId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddress", reflect.TypeOf((*MockService)(nil).DeleteAddress), ctx, buyerId, id)
+}
+
+// GetAddresses mocks base method.
+func (m *MockService) GetAddresses(ctx context.Context, buyerId int) ([]domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddresses", ctx, buyerId)
+	ret0, _ := ret[0].([]domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddresses indicates an expected call of GetAddresses.
+func (mr *MockServiceMockRecorder) GetAddresses(ctx, buyerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddresses", reflect.TypeOf((*MockService)(nil).GetAddresses), ctx, buyerId)
+}
+
 // GetAll mocks base method.
 func (m *MockService) GetAll(ctx context.Context) ([]domain.Buyer, error) {
 	m.ctrl.T.Helper()
@@ -221,17 +384,62 @@ func (mr *MockServiceMockRecorder) GetOrdersByBuyers(ctx, id interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersByBuyers", reflect.TypeOf((*MockService)(nil).GetOrdersByBuyers), ctx, id)
 }
 
+// GetPurchaseAnalytics mocks base method.
+func (m *MockService) GetPurchaseAnalytics(ctx context.Context, id int, r period.Range, unit period.Unit, top int) (domain.PurchaseAnalytics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchaseAnalytics", ctx, id, r, unit, top)
+	ret0, _ := ret[0].(domain.PurchaseAnalytics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchaseAnalytics indicates an expected call of GetPurchaseAnalytics.
+func (mr *MockServiceMockRecorder) GetPurchaseAnalytics(ctx, id, r, unit, top interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchaseAnalytics", reflect.TypeOf((*MockService)(nil).GetPurchaseAnalytics), ctx, id, r, unit, top)
+}
+
+// Restore mocks base method.
+func (m *MockService) Restore(ctx context.Context, id int) (*domain.Buyer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*domain.Buyer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockServiceMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockService) Update(ctx context.Context, id int, cardNumberId, firstName, lastName string) (*domain.Buyer, error) {
+func (m *MockService) Update(ctx context.Context, id int, arg domain.BuyerPatch) (*domain.Buyer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, cardNumberId, firstName, lastName)
+	ret := m.ctrl.Call(m, "Update", ctx, id, arg)
 	ret0, _ := ret[0].(*domain.Buyer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockServiceMockRecorder) Update(ctx, id, cardNumberId, firstName, lastName interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Update(ctx, id, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, id, arg)
+}
+
+// UpdateAddress mocks base method.
+func (m *MockService) UpdateAddress(ctx context.Context, buyerId, id int, arg domain.AddressPatch) (*domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAddress", ctx, buyerId, id, arg)
+	ret0, _ := ret[0].(*domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAddress indicates an expected call of UpdateAddress.
+func (mr *MockServiceMockRecorder) UpdateAddress(ctx, buyerId, id, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, id, cardNumberId, firstName, lastName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddress", reflect.TypeOf((*MockService)(nil).UpdateAddress), ctx, buyerId, id, arg)
 }
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
+)
+
+type addressScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAddress(row addressScanner) (domain.Address, error) {
+	var a domain.Address
+	err := row.Scan(&a.Id, &a.BuyerId, &a.Address, &a.LocalityId, &a.IsDefault)
+	return a, err
+}
+
+func (r *repository) GetAddresses(ctx context.Context, buyerId int) ([]domain.Address, error) {
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, queryGetAddresses+queryGetAddressesOrder, buyerId)
+	if err != nil {
+		log.Println("Error while quering buyer addresses " + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := []domain.Address{}
+	for rows.Next() {
+		a, err := scanAddress(rows)
+		if err != nil {
+			log.Println("Error while scanning buyer addresses " + err.Error())
+			return nil, err
+		}
+		addresses = append(addresses, a)
+	}
+	return addresses, rows.Err()
+}
+
+func (r *repository) GetAddress(ctx context.Context, buyerId, id int) (*domain.Address, error) {
+	a, err := scanAddress(transaction.From(ctx, r.db).QueryRowContext(ctx, queryGetAddress, buyerId, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &domain.ErrorAddressNotFound{BuyerId: buyerId, Id: id}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// CreateAddress inserts arg, taking the default flag from the other addresses
+// of the buyer when arg is the new default.
+func (r *repository) CreateAddress(ctx context.Context, arg domain.Address) (*domain.Address, error) {
+	err := transaction.NewRunner(r.db).Run(ctx, func(ctx context.Context) error {
+		result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryCreateAddress, arg.BuyerId, arg.Address, arg.LocalityId, arg.IsDefault)
+		if err != nil {
+			return err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		arg.Id = int(id)
+
+		if arg.IsDefault {
+			_, err = transaction.From(ctx, r.db).ExecContext(ctx, queryClearDefault, arg.BuyerId, arg.Id)
+		}
+		return err
+	})
+	if err != nil {
+		log.Println("Error while creating buyer address " + err.Error())
+		return nil, err
+	}
+	return &arg, nil
+}
+
+// UpdateAddress saves arg, taking the default flag from the other addresses of
+// the buyer when arg is the new default.
+func (r *repository) UpdateAddress(ctx context.Context, arg domain.Address) error {
+	err := transaction.NewRunner(r.db).Run(ctx, func(ctx context.Context) error {
+		_, err := transaction.From(ctx, r.db).ExecContext(ctx, queryUpdateAddress, arg.Address, arg.LocalityId, arg.IsDefault, arg.BuyerId, arg.Id)
+		if err != nil {
+			return err
+		}
+
+		if arg.IsDefault {
+			_, err = transaction.From(ctx, r.db).ExecContext(ctx, queryClearDefault, arg.BuyerId, arg.Id)
+		}
+		return err
+	})
+	if err != nil {
+		log.Println("Error while updating buyer address " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeleteAddress removes the address. When it was the default one, the oldest
+// remaining address of the buyer takes its place.
+func (r *repository) DeleteAddress(ctx context.Context, buyerId, id int) error {
+	return transaction.NewRunner(r.db).Run(ctx, func(ctx context.Context) error {
+		address, err := r.GetAddress(ctx, buyerId, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := transaction.From(ctx, r.db).ExecContext(ctx, queryDeleteAddress, buyerId, id); err != nil {
+			return fmt.Errorf("error when deleting address %d of buyer %d", id, buyerId)
+		}
+
+		if address.IsDefault {
+			if _, err := transaction.From(ctx, r.db).ExecContext(ctx, queryPromoteDefault, buyerId); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var addressColumns = []string{"id", "buyer_id", "address", "locality_id", "is_default"}
+
+func TestRepository_GetAddresses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAddresses + queryGetAddressesOrder)).WithArgs(1).WillReturnRows(
+		sqlmock.NewRows(addressColumns).
+			AddRow(1, 1, "Rua A, 10", 3, true).
+			AddRow(2, 1, "Rua B, 20", 4, false),
+	)
+
+	result, err := NewRepository(db).GetAddresses(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Address{
+		{Id: 1, BuyerId: 1, Address: "Rua A, 10", LocalityId: 3, IsDefault: true},
+		{Id: 2, BuyerId: 1, Address: "Rua B, 20", LocalityId: 4},
+	}, result)
+}
+
+func TestRepository_GetAddress(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAddress)).WithArgs(1, 2).WillReturnRows(
+		sqlmock.NewRows(addressColumns).AddRow(2, 1, "Rua B, 20", 4, false),
+	)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAddress)).WithArgs(1, 9).WillReturnRows(sqlmock.NewRows(addressColumns))
+
+	repository := NewRepository(db)
+
+	result, err := repository.GetAddress(context.TODO(), 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.Address{Id: 2, BuyerId: 1, Address: "Rua B, 20", LocalityId: 4}, result)
+
+	_, err = repository.GetAddress(context.TODO(), 1, 9)
+	assert.EqualError(t, err, "address 9 of buyer 1 not found")
+}
+
+func TestRepository_CreateAddress(t *testing.T) {
+	t.Run("Default address", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(queryCreateAddress)).WithArgs(1, "Rua A, 10", 3, true).WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta(queryClearDefault)).WithArgs(1, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		result, err := NewRepository(db).CreateAddress(context.TODO(), domain.Address{BuyerId: 1, Address: "Rua A, 10", LocalityId: 3, IsDefault: true})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, result.Id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Other address", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(queryCreateAddress)).WithArgs(1, "Rua A, 10", 3, false).WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectCommit()
+
+		_, err = NewRepository(db).CreateAddress(context.TODO(), domain.Address{BuyerId: 1, Address: "Rua A, 10", LocalityId: 3})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(queryCreateAddress)).WillReturnError(errors.New("some error"))
+		mock.ExpectRollback()
+
+		_, err = NewRepository(db).CreateAddress(context.TODO(), domain.Address{BuyerId: 1, Address: "Rua A, 10", LocalityId: 3})
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_UpdateAddress(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(queryUpdateAddress)).WithArgs("Rua B, 20", 4, true, 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryClearDefault)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = NewRepository(db).UpdateAddress(context.TODO(), domain.Address{Id: 2, BuyerId: 1, Address: "Rua B, 20", LocalityId: 4, IsDefault: true})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteAddress(t *testing.T) {
+	t.Run("Default address", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(queryGetAddress)).WithArgs(1, 2).WillReturnRows(
+			sqlmock.NewRows(addressColumns).AddRow(2, 1, "Rua B, 20", 4, true),
+		)
+		mock.ExpectExec(regexp.QuoteMeta(queryDeleteAddress)).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(queryPromoteDefault)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, NewRepository(db).DeleteAddress(context.TODO(), 1, 2))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(queryGetAddress)).WithArgs(1, 9).WillReturnRows(sqlmock.NewRows(addressColumns))
+		mock.ExpectRollback()
+
+		err = NewRepository(db).DeleteAddress(context.TODO(), 1, 9)
+		assert.EqualError(t, err, "address 9 of buyer 1 not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
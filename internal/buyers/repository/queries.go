@@ -1,11 +1,32 @@
 package repository
 
 const (
-	queryCreate  = "insert into buyers (id_card_number, first_name, last_name) values (?,?,?)"
-	queryGetAll  = "SELECT id, id_card_number, first_name, last_name FROM buyers"
-	queryGetById = "SELECT id, id_card_number, first_name, last_name FROM buyers where id = ?"
-	queryUpdate  = "update buyers set id_card_number = ?, first_name  = ?, last_name  = ? where id = ?"
-	queryDelete  = "DELETE FROm buyers WHERE id = ?"
-	queryGetOrdersByBuyer = "SELECT b.id, b.id_card_number, b.first_name, b.last_name, count(p.id) AS purchase_orders_count FROM buyers b INNER JOIN purchase_orders p ON b.id = p.buyer_id WHERE b.id = ? GROUP BY b.id"
-	queryGetOrdersByBuyers = "SELECT b.id, b.id_card_number, b.first_name, b.last_name, count(p.id) AS purchase_orders_count FROM buyers b INNER JOIN purchase_orders p ON b.id = p.buyer_id GROUP BY b.id"
+	queryCreate             = "insert into buyers (id_card_number, first_name, last_name) values (?,?,?)"
+	querySelect             = "SELECT id, id_card_number, first_name, last_name, deleted_at, version FROM buyers"
+	queryGetAll             = querySelect + " WHERE deleted_at IS NULL"
+	queryGetAllWithDeleted  = querySelect
+	queryGetById            = querySelect + " where id = ? AND deleted_at IS NULL"
+	queryGetByIdWithDeleted = querySelect + " where id = ?"
+	queryUpdate             = "update buyers set id_card_number = ?, first_name  = ?, last_name  = ?, version = version + 1 where id = ? AND version = ?"
+	queryDelete             = "UPDATE buyers SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL"
+	queryRestore            = "UPDATE buyers SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL"
+	queryCountOrders        = "SELECT COUNT(*) FROM purchase_orders WHERE buyer_id = ?"
+	queryGetOrdersByBuyer   = "SELECT b.id, b.id_card_number, b.first_name, b.last_name, count(p.id) AS purchase_orders_count FROM buyers b INNER JOIN purchase_orders p ON b.id = p.buyer_id WHERE b.id = ? GROUP BY b.id"
+	queryGetOrdersByBuyers  = "SELECT b.id, b.id_card_number, b.first_name, b.last_name, count(p.id) AS purchase_orders_count FROM buyers b INNER JOIN purchase_orders p ON b.id = p.buyer_id GROUP BY b.id"
+	// queryGetPurchases lists the lines of the orders of a buyer, along with
+	// the orders made before orders had lines, as one line of their product
+	// record. Both selects are completed with the date range conditions, and
+	// the union with queryGetPurchasesOrder.
+	queryGetPurchases       = "SELECT p.id, p.order_date, r.product_id, pr.description, l.unit_price, l.quantity FROM purchase_orders p INNER JOIN purchase_order_lines l ON l.purchase_order_id = p.id INNER JOIN product_records r ON l.product_record_id = r.id INNER JOIN products pr ON r.product_id = pr.id WHERE p.buyer_id = ?"
+	queryGetLegacyPurchases = " UNION ALL SELECT p.id, p.order_date, r.product_id, pr.description, r.sale_price, 1 FROM purchase_orders p INNER JOIN product_records r ON p.product_record_id = r.id INNER JOIN products pr ON r.product_id = pr.id WHERE p.buyer_id = ? AND NOT EXISTS (SELECT 1 FROM purchase_order_lines l WHERE l.purchase_order_id = p.id)"
+	queryGetPurchasesOrder  = " ORDER BY order_date, id"
+	queryGetAddresses       = "SELECT id, buyer_id, address, locality_id, is_default FROM buyer_addresses WHERE buyer_id = ?"
+	queryGetAddressesOrder  = " ORDER BY id"
+	queryGetAddress         = queryGetAddresses + " AND id = ?"
+	queryCreateAddress      = "INSERT INTO buyer_addresses (buyer_id, address, locality_id, is_default) VALUES (?, ?, ?, ?)"
+	queryUpdateAddress      = "UPDATE buyer_addresses SET address = ?, locality_id = ?, is_default = ? WHERE buyer_id = ? AND id = ?"
+	queryDeleteAddress      = "DELETE FROM buyer_addresses WHERE buyer_id = ? AND id = ?"
+	queryClearDefault       = "UPDATE buyer_addresses SET is_default = false WHERE buyer_id = ? AND id <> ?"
+	// queryPromoteDefault makes the oldest address of a buyer the default one.
+	queryPromoteDefault = "UPDATE buyer_addresses SET is_default = true WHERE buyer_id = ? ORDER BY id LIMIT 1"
 )
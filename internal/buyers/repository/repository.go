@@ -8,6 +8,14 @@ import (
 	"log"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 )
 
 type repository struct {
@@ -15,7 +23,12 @@ type repository struct {
 }
 
 func (r *repository) GetAll(ctx context.Context) ([]domain.Buyer, error) {
-	rows, err := r.db.QueryContext(ctx, queryGetAll)
+	query := queryGetAll
+	if softdelete.IncludeDeleted(ctx) {
+		query = queryGetAllWithDeleted
+	}
+
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		log.Println("Error while quering buyers table" + err.Error())
 		return nil, err
@@ -23,7 +36,7 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Buyer, error) {
 	buyers := make([]domain.Buyer, 0)
 	for rows.Next() {
 		var b domain.Buyer
-		err := rows.Scan(&b.Id, &b.CardNumberId, &b.FirstName, &b.LastName)
+		err := rows.Scan(&b.Id, &b.CardNumberId, &b.FirstName, &b.LastName, &b.DeletedAt, &b.Version)
 		if err != nil {
 			log.Println("Error while scanning buyers" + err.Error())
 			return nil, err
@@ -34,12 +47,17 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Buyer, error) {
 }
 
 func (r *repository) GetById(ctx context.Context, id int) (*domain.Buyer, error) {
-	row := r.db.QueryRowContext(ctx, queryGetById, id)
+	query := queryGetById
+	if softdelete.IncludeDeleted(ctx) {
+		query = queryGetByIdWithDeleted
+	}
+
+	row := transaction.From(ctx, r.db).QueryRowContext(ctx, query, id)
 	var b domain.Buyer
-	err := row.Scan(&b.Id, &b.CardNumberId, &b.FirstName, &b.LastName)
+	err := row.Scan(&b.Id, &b.CardNumberId, &b.FirstName, &b.LastName, &b.DeletedAt, &b.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("Buyer %d not found", id)
+			return nil, &domain.ErrorNotFound{Id: id}
 		} else {
 			log.Println("Error while scanning customer " + err.Error())
 			return nil, fmt.Errorf("unexpected database error")
@@ -53,7 +71,7 @@ func (r *repository) GetOrdersByBuyers(ctx context.Context, id int) ([]domain.Or
 	var ordersBySellers []domain.OrdersByBuyers
 
 	if id != 0 {
-		row := r.db.QueryRowContext(ctx, queryGetOrdersByBuyer, id)
+		row := transaction.From(ctx, r.db).QueryRowContext(ctx, queryGetOrdersByBuyer, id)
 
 		var orders domain.OrdersByBuyers
 
@@ -75,7 +93,7 @@ func (r *repository) GetOrdersByBuyers(ctx context.Context, id int) ([]domain.Or
 
 		ordersBySellers = append(ordersBySellers, orders)
 	} else {
-		rows, err := r.db.QueryContext(ctx, queryGetOrdersByBuyers)
+		rows, err := transaction.From(ctx, r.db).QueryContext(ctx, queryGetOrdersByBuyers)
 		if err != nil {
 			return []domain.OrdersByBuyers{}, err
 		}
@@ -100,8 +118,39 @@ func (r *repository) GetOrdersByBuyers(ctx context.Context, id int) ([]domain.Or
 	return ordersBySellers, nil
 }
 
+func (r *repository) GetPurchases(ctx context.Context, id int, dates period.Range) ([]domain.Purchase, error) {
+	conditions, args := dates.Where("p.order_date")
+	query := queryGetPurchases + conditions + queryGetLegacyPurchases + conditions + queryGetPurchasesOrder
+	args = append(append(append([]interface{}{id}, args...), id), args...)
+
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	purchases := []domain.Purchase{}
+	for rows.Next() {
+		var purchase domain.Purchase
+		err := rows.Scan(
+			&purchase.OrderId,
+			&purchase.OrderDate,
+			&purchase.ProductId,
+			&purchase.Description,
+			&purchase.UnitPrice,
+			&purchase.Quantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		purchases = append(purchases, purchase)
+	}
+	return purchases, rows.Err()
+}
+
 func (r *repository) Create(ctx context.Context, cardNumberId, firstName, lastName string) (*domain.Buyer, error) {
-	result, err := r.db.ExecContext(ctx, queryCreate, cardNumberId, firstName, lastName)
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryCreate, cardNumberId, firstName, lastName)
 	if err != nil {
 		return nil, err
 	}
@@ -109,40 +158,103 @@ func (r *repository) Create(ctx context.Context, cardNumberId, firstName, lastNa
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving id %d ", id)
 	}
-	e := domain.Buyer{Id: int(id), CardNumberId: cardNumberId, FirstName: firstName, LastName: lastName}
+	e := domain.Buyer{Id: int(id), CardNumberId: cardNumberId, FirstName: firstName, LastName: lastName, Version: 1}
 	return &e, nil
 }
 
-func (r *repository) Update(ctx context.Context, id int, cardNumberId, firstName, lastName string) (*domain.Buyer, error) {
+func (r *repository) Update(ctx context.Context, id int, arg domain.BuyerPatch) (*domain.Buyer, error) {
 
 	buy, err := r.GetById(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("employee %d not found", id)
+		return nil, fmt.Errorf("buyer %d not found", id)
+	}
+	if err := etag.Check(ctx, buy.Version); err != nil {
+		return nil, err
+	}
+	if arg.CardNumberId != nil {
+		buy.CardNumberId = *arg.CardNumberId
 	}
-	if cardNumberId != "" {
-		buy.CardNumberId = cardNumberId
+	if arg.FirstName != nil {
+		buy.FirstName = *arg.FirstName
 	}
-	if firstName != "" {
-		buy.FirstName = firstName
+	if arg.LastName != nil {
+		buy.LastName = *arg.LastName
 	}
-	if lastName != "" {
-		buy.LastName = lastName
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryUpdate, buy.CardNumberId, buy.FirstName, buy.LastName, id, buy.Version)
+	if err != nil {
+		log.Println("Error while updating buyer " + err.Error())
+		return nil, err
 	}
-	result, err := r.db.ExecContext(ctx, queryUpdate, buy.CardNumberId, buy.FirstName, buy.LastName, id)
-	log.Println(result.RowsAffected())
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	// Someone else changed the buyer between the read and the write.
+	if affected == 0 {
+		return nil, etag.ErrMismatch
+	}
+
+	buy.Version++
 	return buy, nil
 
 }
 
 func (r *repository) Delete(ctx context.Context, id int) error {
-	_, err := r.db.ExecContext(ctx, queryDelete, id)
+	query, args := queryDelete, []interface{}{date.Now(), id}
+	version, versioned := etag.Version(ctx)
+	if versioned {
+		query, args = queryDelete+etag.Condition, append(args, version)
+	}
+
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("error when deleting buyer %d", id)
 	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// The buyer is there, just not in the version the request expects.
+		if versioned {
+			if _, err := r.GetById(ctx, id); err == nil {
+				return etag.ErrMismatch
+			}
+		}
+		return fmt.Errorf("buyer %d not found", id)
+	}
+	return nil
+}
+
+func (r *repository) Restore(ctx context.Context, id int) error {
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryRestore, id)
+	if err != nil {
+		return fmt.Errorf("error when restoring buyer %d", id)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("deleted buyer %d not found", id)
+	}
 	return nil
 }
 
+// Dependents counts the purchase orders placed by the buyer.
+func (r *repository) Dependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	var orders int
+	if err := transaction.From(ctx, r.db).QueryRowContext(ctx, queryCountOrders, id).Scan(&orders); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, fmt.Errorf("error when counting purchase orders of buyer %d: %w", id, err)
+	}
+
+	return []dependents.Dependent{{Type: "purchase_orders", Count: orders}}, nil
+}
+
 func NewRepository(db *sql.DB) domain.Repository {
 	return &repository{
 		db: db,
@@ -6,9 +6,15 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,20 +40,24 @@ func TestRepository_GetAll_Ok(t *testing.T) {
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "card_number_id", "first_name", "last_name",
+		"id", "card_number_id", "first_name", "last_name", "deleted_at", "version",
 	}).AddRow(
 		buyerMock[0].Id,
 		buyerMock[0].CardNumberId,
 		buyerMock[0].FirstName,
 		buyerMock[0].LastName,
+		nil,
+		1,
 	).AddRow(
 		buyerMock[1].Id,
 		buyerMock[1].CardNumberId,
 		buyerMock[1].FirstName,
 		buyerMock[1].LastName,
+		nil,
+		1,
 	)
 
-	mock.ExpectQuery(queryGetAll).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAll)).WillReturnRows(rows)
 
 	byRepo := NewRepository(db)
 
@@ -63,7 +73,7 @@ func TestRepository_GetAll_NOk(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(queryGetAll).WillReturnError(errors.New("erro"))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAll)).WillReturnError(errors.New("erro"))
 
 	byRepo := NewRepository(db)
 
@@ -85,21 +95,24 @@ func TestRepository_GetById_Ok(t *testing.T) {
 	}
 
 	row := sqlmock.NewRows([]string{
-		"id", "card_number_id", "first_name", "last_name",
+		"id", "card_number_id", "first_name", "last_name", "deleted_at", "version",
 	}).AddRow(
 		buyerMock.Id,
 		buyerMock.CardNumberId,
 		buyerMock.FirstName,
 		buyerMock.LastName,
+		nil,
+		3,
 	)
 
-	mock.ExpectQuery(queryGetById).WillReturnRows(row)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(row)
 
 	byRepo := NewRepository(db)
 
 	result, err := byRepo.GetById(context.TODO(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, "44dm", result.CardNumberId)
+	assert.Equal(t, 3, result.Version)
 }
 
 func TestRepository_GetById_NOk(t *testing.T) {
@@ -108,7 +121,7 @@ func TestRepository_GetById_NOk(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(queryGetById).WillReturnError(errors.New("error"))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnError(errors.New("error"))
 
 	byRepo := NewRepository(db)
 
@@ -122,7 +135,7 @@ func TestRepository_GetById_NoId(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(queryGetById).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnError(sql.ErrNoRows)
 
 	byRepo := NewRepository(db)
 
@@ -293,6 +306,7 @@ func TestRepository_Update_Ok(t *testing.T) {
 		CardNumberId: "44dm",
 		FirstName:    "Will",
 		LastName:     "Spencer",
+		Version:      2,
 	}
 
 	buyerMockUpdated := domain.Buyer{
@@ -300,33 +314,60 @@ func TestRepository_Update_Ok(t *testing.T) {
 		CardNumberId: "44dm",
 		FirstName:    "Will",
 		LastName:     "M. Spencer",
+		Version:      3,
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "card_number_id", "first_name", "last_name",
+		"id", "card_number_id", "first_name", "last_name", "deleted_at", "version",
 	}).AddRow(
 		buyerMock.Id,
 		buyerMock.CardNumberId,
 		buyerMock.FirstName,
 		buyerMock.LastName,
+		nil,
+		buyerMock.Version,
 	)
 
-	mock.ExpectQuery(queryGetById).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(rows)
 
 	mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).WithArgs(
 		buyerMockUpdated.CardNumberId,
 		buyerMockUpdated.FirstName,
 		buyerMockUpdated.LastName,
 		1,
+		buyerMock.Version,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	byRepo := NewRepository(db)
 
-	result, err := byRepo.Update(context.TODO(), 1, "44dm", "Will", "M. Spencer")
+	result, err := byRepo.Update(etag.WithVersion(context.TODO(), 2), 1, domain.BuyerPatch{LastName: stringPtr("M. Spencer")})
 	assert.NoError(t, err)
 	assert.Equal(t, &buyerMockUpdated, result)
 }
 
+func TestRepository_Update_Version_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "card_number_id", "first_name", "last_name", "deleted_at", "version"}
+	byRepo := NewRepository(db)
+
+	// The client read an older version.
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "44dm", "Will", "Spencer", nil, 3))
+
+	_, err = byRepo.Update(etag.WithVersion(context.TODO(), 2), 1, domain.BuyerPatch{LastName: stringPtr("M. Spencer")})
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+
+	// Someone else wrote the buyer between the read and the write.
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "44dm", "Will", "Spencer", nil, 3))
+	mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = byRepo.Update(etag.WithVersion(context.TODO(), 3), 1, domain.BuyerPatch{LastName: stringPtr("M. Spencer")})
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRepository_Update_NOk(t *testing.T) {
 
 	db, mock, err := sqlmock.New()
@@ -337,7 +378,7 @@ func TestRepository_Update_NOk(t *testing.T) {
 
 	byRepo := NewRepository(db)
 
-	_, err = byRepo.Update(context.TODO(), 1, "44dm", "Will", "M. Spencer")
+	_, err = byRepo.Update(context.TODO(), 1, domain.BuyerPatch{LastName: stringPtr("M. Spencer")})
 	assert.Error(t, err)
 }
 
@@ -347,6 +388,7 @@ func TestRepository_Delete_Ok(t *testing.T) {
 	defer db.Close()
 
 	mock.ExpectExec(regexp.QuoteMeta(queryDelete)).WithArgs(
+		sqlmock.AnyArg(),
 		1,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
 
@@ -367,4 +409,117 @@ func TestRepository_Delete_NOk(t *testing.T) {
 
 	err = byRepo.Delete(context.TODO(), 1)
 	assert.Error(t, err)
-}
\ No newline at end of file
+}
+
+func TestRepository_Dependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryCountOrders)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	byRepo := NewRepository(db)
+
+	result, err := byRepo.Dependents(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []dependents.Dependent{{Type: "purchase_orders", Count: 4}}, result)
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryCountOrders)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+	_, err = byRepo.Dependents(context.TODO(), 2)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func TestRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryDelete)).
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	byRepo := NewRepository(db)
+
+	err = byRepo.Delete(context.TODO(), 1)
+	assert.EqualError(t, err, "buyer 1 not found")
+}
+
+func TestRepository_Delete_Version_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryDelete + etag.Condition)).
+		WithArgs(sqlmock.AnyArg(), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "card_number_id", "first_name", "last_name", "deleted_at", "version"}).AddRow(1, "44dm", "Will", "Spencer", nil, 3))
+
+	byRepo := NewRepository(db)
+
+	err = byRepo.Delete(etag.WithVersion(context.TODO(), 2), 1)
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+}
+
+func TestRepository_GetAll_IncludeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	deletedAt := date.NewDateTime(time.Date(2022, 7, 9, 10, 0, 0, 0, time.UTC))
+
+	rows := sqlmock.NewRows([]string{
+		"id", "card_number_id", "first_name", "last_name", "deleted_at", "version",
+	}).AddRow(1, "44dm", "Will", "Spencer", deletedAt.Time, 2)
+
+	mock.ExpectQuery("^" + regexp.QuoteMeta(queryGetAllWithDeleted) + "$").WillReturnRows(rows)
+
+	byRepo := NewRepository(db)
+
+	result, err := byRepo.GetAll(softdelete.WithDeleted(context.TODO()))
+	assert.NoError(t, err)
+	assert.Equal(t, deletedAt, result[0].DeletedAt)
+}
+
+func TestRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryRestore)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryRestore)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	byRepo := NewRepository(db)
+
+	assert.NoError(t, byRepo.Restore(context.TODO(), 1))
+	assert.EqualError(t, byRepo.Restore(context.TODO(), 2), "deleted buyer 2 not found")
+}
+
+func TestRepository_GetPurchases(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dates := period.Range{From: date.New(2022, 7, 1)}
+	orderDate := time.Date(2022, 7, 9, 0, 0, 0, 0, time.UTC)
+
+	conditions := " AND p.order_date >= ?"
+	mock.ExpectQuery("^"+regexp.QuoteMeta(queryGetPurchases+conditions+queryGetLegacyPurchases+conditions+queryGetPurchasesOrder)+"$").
+		WithArgs(1, dates.From, 1, dates.From).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_date", "product_id", "description", "unit_price", "quantity"}).
+			AddRow(3, orderDate, 7, "Chocolate", 10.1, 1).
+			AddRow(4, orderDate, 5, "Milk", 0.35, 10))
+
+	result, err := NewRepository(db).GetPurchases(context.TODO(), 1, dates)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Purchase{
+		{OrderId: 3, OrderDate: date.New(2022, 7, 9), ProductId: 7, Description: "Chocolate", UnitPrice: 10.1, Quantity: 1},
+		{OrderId: 4, OrderDate: date.New(2022, 7, 9), ProductId: 5, Description: "Milk", UnitPrice: 0.35, Quantity: 10},
+	}, result)
+}
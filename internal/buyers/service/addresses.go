@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+)
+
+func (s service) GetAddresses(ctx context.Context, buyerId int) ([]domain.Address, error) {
+	if _, err := s.repository.GetById(ctx, buyerId); err != nil {
+		return nil, err
+	}
+	return s.repository.GetAddresses(ctx, buyerId)
+}
+
+// CreateAddress adds an address to the buyer. The first address of a buyer is
+// always the default one.
+func (s service) CreateAddress(ctx context.Context, arg domain.Address) (*domain.Address, error) {
+	if _, err := s.repository.GetById(ctx, arg.BuyerId); err != nil {
+		return nil, err
+	}
+	if _, err := s.localities.GetById(ctx, arg.LocalityId); err != nil {
+		return nil, fmt.Errorf("locality %d not found", arg.LocalityId)
+	}
+
+	addresses, err := s.repository.GetAddresses(ctx, arg.BuyerId)
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses) == 0 {
+		arg.IsDefault = true
+	}
+
+	return s.repository.CreateAddress(ctx, arg)
+}
+
+// UpdateAddress applies arg to the address. The default flag moves by setting
+// it on another address, so it cannot be unset directly.
+func (s service) UpdateAddress(ctx context.Context, buyerId, id int, arg domain.AddressPatch) (*domain.Address, error) {
+	address, err := s.repository.GetAddress(ctx, buyerId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if arg.Address != nil {
+		address.Address = *arg.Address
+	}
+	if arg.LocalityId != nil && *arg.LocalityId != address.LocalityId {
+		if _, err := s.localities.GetById(ctx, *arg.LocalityId); err != nil {
+			return nil, fmt.Errorf("locality %d not found", *arg.LocalityId)
+		}
+		address.LocalityId = *arg.LocalityId
+	}
+	if arg.IsDefault != nil {
+		if address.IsDefault && !*arg.IsDefault {
+			return nil, fmt.Errorf("%w: address %d", domain.ErrDefaultAddress, id)
+		}
+		address.IsDefault = *arg.IsDefault
+	}
+
+	if err := s.repository.UpdateAddress(ctx, *address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+func (s service) DeleteAddress(ctx context.Context, buyerId, id int) error {
+	return s.repository.DeleteAddress(ctx, buyerId, id)
+}
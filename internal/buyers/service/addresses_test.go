@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	localityDomain "github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_GetAddresses(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		addresses := []domain.Address{{Id: 1, BuyerId: 1, Address: "Rua A, 10", LocalityId: 3, IsDefault: true}}
+		repository.EXPECT().GetById(context.TODO(), 1).Return(&domain.Buyer{Id: 1}, nil)
+		repository.EXPECT().GetAddresses(context.TODO(), 1).Return(addresses, nil)
+
+		result, err := service.GetAddresses(context.TODO(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, addresses, result)
+	})
+
+	t.Run("Buyer not found", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), 1).Return(nil, errors.New("Buyer 1 not found"))
+
+		_, err := service.GetAddresses(context.TODO(), 1)
+		assert.EqualError(t, err, "Buyer 1 not found")
+	})
+}
+
+func TestService_CreateAddress(t *testing.T) {
+	arg := domain.Address{BuyerId: 1, Address: "Rua A, 10", LocalityId: 3}
+
+	t.Run("First address is the default", func(t *testing.T) {
+		repository, localities, service := callBuyersMockWithLocalities(t)
+
+		repository.EXPECT().GetById(context.TODO(), 1).Return(&domain.Buyer{Id: 1}, nil)
+		localities.EXPECT().GetById(context.TODO(), 3).Return(localityDomain.Locality{Id: 3}, nil)
+		repository.EXPECT().GetAddresses(context.TODO(), 1).Return([]domain.Address{}, nil)
+
+		created := arg
+		created.IsDefault = true
+		repository.EXPECT().CreateAddress(context.TODO(), created).Return(&created, nil)
+
+		result, err := service.CreateAddress(context.TODO(), arg)
+		assert.NoError(t, err)
+		assert.True(t, result.IsDefault)
+	})
+
+	t.Run("Other addresses keep the default", func(t *testing.T) {
+		repository, localities, service := callBuyersMockWithLocalities(t)
+
+		repository.EXPECT().GetById(context.TODO(), 1).Return(&domain.Buyer{Id: 1}, nil)
+		localities.EXPECT().GetById(context.TODO(), 3).Return(localityDomain.Locality{Id: 3}, nil)
+		repository.EXPECT().GetAddresses(context.TODO(), 1).Return([]domain.Address{{Id: 1, IsDefault: true}}, nil)
+		repository.EXPECT().CreateAddress(context.TODO(), arg).Return(&arg, nil)
+
+		result, err := service.CreateAddress(context.TODO(), arg)
+		assert.NoError(t, err)
+		assert.False(t, result.IsDefault)
+	})
+
+	t.Run("Locality not found", func(t *testing.T) {
+		repository, localities, service := callBuyersMockWithLocalities(t)
+
+		repository.EXPECT().GetById(context.TODO(), 1).Return(&domain.Buyer{Id: 1}, nil)
+		localities.EXPECT().GetById(context.TODO(), 3).Return(localityDomain.Locality{}, errors.New("locality 3 not found"))
+
+		_, err := service.CreateAddress(context.TODO(), arg)
+		assert.EqualError(t, err, "locality 3 not found")
+	})
+
+	t.Run("Buyer not found", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), 1).Return(nil, errors.New("Buyer 1 not found"))
+
+		_, err := service.CreateAddress(context.TODO(), arg)
+		assert.EqualError(t, err, "Buyer 1 not found")
+	})
+}
+
+func TestService_UpdateAddress(t *testing.T) {
+	address := domain.Address{Id: 2, BuyerId: 1, Address: "Rua A, 10", LocalityId: 3}
+
+	t.Run("Set as default", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		found := address
+		repository.EXPECT().GetAddress(context.TODO(), 1, 2).Return(&found, nil)
+
+		updated := address
+		updated.IsDefault = true
+		repository.EXPECT().UpdateAddress(context.TODO(), updated).Return(nil)
+
+		isDefault := true
+		result, err := service.UpdateAddress(context.TODO(), 1, 2, domain.AddressPatch{IsDefault: &isDefault})
+		assert.NoError(t, err)
+		assert.Equal(t, &updated, result)
+	})
+
+	t.Run("Change locality", func(t *testing.T) {
+		repository, localities, service := callBuyersMockWithLocalities(t)
+
+		found := address
+		repository.EXPECT().GetAddress(context.TODO(), 1, 2).Return(&found, nil)
+		localities.EXPECT().GetById(context.TODO(), 4).Return(localityDomain.Locality{Id: 4}, nil)
+
+		updated := address
+		updated.Address, updated.LocalityId = "Rua B, 20", 4
+		repository.EXPECT().UpdateAddress(context.TODO(), updated).Return(nil)
+
+		street, localityId := "Rua B, 20", 4
+		result, err := service.UpdateAddress(context.TODO(), 1, 2, domain.AddressPatch{Address: &street, LocalityId: &localityId})
+		assert.NoError(t, err)
+		assert.Equal(t, &updated, result)
+	})
+
+	t.Run("Locality not found", func(t *testing.T) {
+		repository, localities, service := callBuyersMockWithLocalities(t)
+
+		found := address
+		repository.EXPECT().GetAddress(context.TODO(), 1, 2).Return(&found, nil)
+		localities.EXPECT().GetById(context.TODO(), 4).Return(localityDomain.Locality{}, errors.New("locality 4 not found"))
+
+		localityId := 4
+		_, err := service.UpdateAddress(context.TODO(), 1, 2, domain.AddressPatch{LocalityId: &localityId})
+		assert.EqualError(t, err, "locality 4 not found")
+	})
+
+	t.Run("Unset default", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		found := address
+		found.IsDefault = true
+		repository.EXPECT().GetAddress(context.TODO(), 1, 2).Return(&found, nil)
+
+		isDefault := false
+		_, err := service.UpdateAddress(context.TODO(), 1, 2, domain.AddressPatch{IsDefault: &isDefault})
+		assert.ErrorIs(t, err, domain.ErrDefaultAddress)
+	})
+
+	t.Run("Address not found", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		repository.EXPECT().GetAddress(context.TODO(), 1, 2).Return(nil, errors.New("address 2 of buyer 1 not found"))
+
+		_, err := service.UpdateAddress(context.TODO(), 1, 2, domain.AddressPatch{})
+		assert.EqualError(t, err, "address 2 of buyer 1 not found")
+	})
+}
+
+func TestService_DeleteAddress(t *testing.T) {
+	repository, service := callBuyersMock(t)
+
+	repository.EXPECT().DeleteAddress(context.TODO(), 1, 2).Return(nil)
+
+	assert.NoError(t, service.DeleteAddress(context.TODO(), 1, 2))
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/money"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+)
+
+// GetPurchaseAnalytics sums up the orders the buyer placed within dates:
+// spend by the priced lines of the orders, orders grouped by
+// unit, the top products by spend and the mean number of days between
+// orders.
+func (s service) GetPurchaseAnalytics(ctx context.Context, id int, dates period.Range, unit period.Unit, top int) (domain.PurchaseAnalytics, error) {
+	if _, err := s.repository.GetById(ctx, id); err != nil {
+		return domain.PurchaseAnalytics{}, err
+	}
+
+	purchases, err := s.repository.GetPurchases(ctx, id, dates)
+	if err != nil {
+		return domain.PurchaseAnalytics{}, err
+	}
+
+	analytics := domain.PurchaseAnalytics{
+		BuyerId:     id,
+		Range:       dates,
+		Period:      unit,
+		TopProducts: []domain.TopProduct{},
+	}
+
+	// Purchases come ordered by date, and an order may hold several.
+	orders := map[int]bool{}
+	periods := period.NewGrouping(unit, func(key string) domain.PurchasePeriod {
+		return domain.PurchasePeriod{Period: key}
+	})
+	products := map[int]int{}
+	productOrders := map[int]map[int]bool{}
+
+	for _, purchase := range purchases {
+		row := periods.Of(purchase.OrderDate)
+		if !orders[purchase.OrderId] {
+			orders[purchase.OrderId] = true
+			row.OrdersCount++
+		}
+		row.Spend += purchase.Amount()
+		analytics.TotalSpend += purchase.Amount()
+
+		i, ok := products[purchase.ProductId]
+		if !ok {
+			i = len(analytics.TopProducts)
+			products[purchase.ProductId] = i
+			productOrders[purchase.ProductId] = map[int]bool{}
+			analytics.TopProducts = append(analytics.TopProducts, domain.TopProduct{
+				ProductId:   purchase.ProductId,
+				Description: purchase.Description,
+			})
+		}
+		if !productOrders[purchase.ProductId][purchase.OrderId] {
+			productOrders[purchase.ProductId][purchase.OrderId] = true
+			analytics.TopProducts[i].OrdersCount++
+		}
+		analytics.TopProducts[i].Spend += purchase.Amount()
+	}
+	analytics.OrdersCount = len(orders)
+	analytics.OrdersOverTime = periods.Rows()
+
+	if analytics.OrdersCount > 1 {
+		first, last := purchases[0].OrderDate, purchases[len(purchases)-1].OrderDate
+		days := last.Sub(first.Time).Hours() / 24
+		interval := money.Round(days / float64(analytics.OrdersCount-1))
+		analytics.AverageOrderIntervalDays = &interval
+	}
+
+	analytics.TotalSpend = money.Round(analytics.TotalSpend)
+	for i := range analytics.OrdersOverTime {
+		analytics.OrdersOverTime[i].Spend = money.Round(analytics.OrdersOverTime[i].Spend)
+	}
+	for i := range analytics.TopProducts {
+		analytics.TopProducts[i].Spend = money.Round(analytics.TopProducts[i].Spend)
+	}
+
+	sort.SliceStable(analytics.TopProducts, func(i, j int) bool {
+		a, b := analytics.TopProducts[i], analytics.TopProducts[j]
+		if a.Spend != b.Spend {
+			return a.Spend > b.Spend
+		}
+		return a.OrdersCount > b.OrdersCount
+	})
+	if len(analytics.TopProducts) > top {
+		analytics.TopProducts = analytics.TopProducts[:top]
+	}
+
+	return analytics, nil
+}
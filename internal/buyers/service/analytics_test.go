@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_GetPurchaseAnalytics(t *testing.T) {
+	dates := period.Range{From: date.New(2022, 7, 1), To: date.New(2022, 8, 31)}
+
+	t.Run("OK", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), 1).Return(&domain.Buyer{Id: 1}, nil)
+		repository.EXPECT().GetPurchases(context.TODO(), 1, dates).Return([]domain.Purchase{
+			{OrderId: 1, OrderDate: date.New(2022, 7, 1), ProductId: 7, Description: "Chocolate", UnitPrice: 10.1, Quantity: 1},
+			{OrderId: 2, OrderDate: date.New(2022, 7, 11), ProductId: 9, Description: "Ice Cream", UnitPrice: 30.2, Quantity: 1},
+			{OrderId: 3, OrderDate: date.New(2022, 8, 2), ProductId: 7, Description: "Chocolate", UnitPrice: 10.2, Quantity: 1},
+			{OrderId: 3, OrderDate: date.New(2022, 8, 2), ProductId: 5, Description: "Milk", UnitPrice: 5, Quantity: 1},
+		}, nil)
+
+		result, err := service.GetPurchaseAnalytics(context.TODO(), 1, dates, period.Month, 2)
+
+		interval := 16.0
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PurchaseAnalytics{
+			BuyerId:                  1,
+			Range:                    dates,
+			Period:                   period.Month,
+			OrdersCount:              3,
+			TotalSpend:               55.5,
+			AverageOrderIntervalDays: &interval,
+			OrdersOverTime: []domain.PurchasePeriod{
+				{Period: "2022-07", OrdersCount: 2, Spend: 40.3},
+				{Period: "2022-08", OrdersCount: 1, Spend: 15.2},
+			},
+			TopProducts: []domain.TopProduct{
+				{ProductId: 9, Description: "Ice Cream", OrdersCount: 1, Spend: 30.2},
+				{ProductId: 7, Description: "Chocolate", OrdersCount: 2, Spend: 20.3},
+			},
+		}, result)
+	})
+
+	t.Run("Order lines", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), 1).Return(&domain.Buyer{Id: 1}, nil)
+		repository.EXPECT().GetPurchases(context.TODO(), 1, dates).Return([]domain.Purchase{
+			{OrderId: 4, OrderDate: date.New(2022, 7, 2), ProductId: 7, Description: "Chocolate", UnitPrice: 10.1, Quantity: 3},
+			{OrderId: 4, OrderDate: date.New(2022, 7, 2), ProductId: 5, Description: "Milk", UnitPrice: 0.35, Quantity: 10},
+		}, nil)
+
+		result, err := service.GetPurchaseAnalytics(context.TODO(), 1, dates, period.Month, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.OrdersCount)
+		assert.Equal(t, 33.8, result.TotalSpend)
+		assert.Equal(t, []domain.PurchasePeriod{{Period: "2022-07", OrdersCount: 1, Spend: 33.8}}, result.OrdersOverTime)
+		assert.Equal(t, []domain.TopProduct{
+			{ProductId: 7, Description: "Chocolate", OrdersCount: 1, Spend: 30.3},
+			{ProductId: 5, Description: "Milk", OrdersCount: 1, Spend: 3.5},
+		}, result.TopProducts)
+	})
+
+	t.Run("No orders", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), 1).Return(&domain.Buyer{Id: 1}, nil)
+		repository.EXPECT().GetPurchases(context.TODO(), 1, dates).Return([]domain.Purchase{}, nil)
+
+		result, err := service.GetPurchaseAnalytics(context.TODO(), 1, dates, period.Week, 5)
+
+		assert.NoError(t, err)
+		assert.Zero(t, result.OrdersCount)
+		assert.Nil(t, result.AverageOrderIntervalDays)
+		assert.Empty(t, result.OrdersOverTime)
+		assert.Empty(t, result.TopProducts)
+	})
+
+	t.Run("Buyer not found", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), 1).Return(nil, errors.New("buyer 1 not found"))
+
+		_, err := service.GetPurchaseAnalytics(context.TODO(), 1, dates, period.Month, 5)
+
+		assert.EqualError(t, err, "buyer 1 not found")
+	})
+
+	t.Run("Purchases fail", func(t *testing.T) {
+		repository, service := callBuyersMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), 1).Return(&domain.Buyer{Id: 1}, nil)
+		repository.EXPECT().GetPurchases(context.TODO(), 1, dates).Return(nil, errors.New("connection refused"))
+
+		_, err := service.GetPurchaseAnalytics(context.TODO(), 1, dates, period.Month, 5)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+)
+
+type auditedService struct {
+	domain.Service
+	audit auditDomain.Service
+}
+
+// NewAuditedService records every change made through s in the audit trail.
+func NewAuditedService(s domain.Service, audit auditDomain.Service) domain.Service {
+	return &auditedService{s, audit}
+}
+
+func (s *auditedService) Create(ctx context.Context, cardNumberId, firstName, lastName string) (*domain.Buyer, error) {
+	buyer, err := s.Service.Create(ctx, cardNumberId, firstName, lastName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityBuyers, buyer.Id, auditDomain.ActionCreate, nil, buyer)
+
+	return buyer, nil
+}
+
+func (s *auditedService) Update(ctx context.Context, id int, arg domain.BuyerPatch) (*domain.Buyer, error) {
+	before, _ := s.Service.GetById(ctx, id)
+
+	buyer, err := s.Service.Update(ctx, id, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityBuyers, id, auditDomain.ActionUpdate, before, buyer)
+
+	return buyer, nil
+}
+
+func (s *auditedService) Delete(ctx context.Context, id int) error {
+	before, _ := s.Service.GetById(ctx, id)
+
+	if err := s.Service.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityBuyers, id, auditDomain.ActionDelete, before, nil)
+
+	return nil
+}
+
+func (s *auditedService) Restore(ctx context.Context, id int) (*domain.Buyer, error) {
+	before, _ := s.Service.GetById(softdelete.WithDeleted(ctx), id)
+
+	buyer, err := s.Service.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityBuyers, id, auditDomain.ActionRestore, before, buyer)
+
+	return buyer, nil
+}
+
+func (s *auditedService) CreateAddress(ctx context.Context, arg domain.Address) (*domain.Address, error) {
+	address, err := s.Service.CreateAddress(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityBuyerAddresses, address.Id, auditDomain.ActionCreate, nil, address)
+
+	return address, nil
+}
+
+func (s *auditedService) UpdateAddress(ctx context.Context, buyerId, id int, arg domain.AddressPatch) (*domain.Address, error) {
+	before := s.address(ctx, buyerId, id)
+
+	address, err := s.Service.UpdateAddress(ctx, buyerId, id, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityBuyerAddresses, id, auditDomain.ActionUpdate, before, address)
+
+	return address, nil
+}
+
+func (s *auditedService) DeleteAddress(ctx context.Context, buyerId, id int) error {
+	before := s.address(ctx, buyerId, id)
+
+	if err := s.Service.DeleteAddress(ctx, buyerId, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityBuyerAddresses, id, auditDomain.ActionDelete, before, nil)
+
+	return nil
+}
+
+// address returns the address id of the buyer as it is before a change, or
+// nil when it cannot be read.
+func (s *auditedService) address(ctx context.Context, buyerId, id int) *domain.Address {
+	addresses, _ := s.Service.GetAddresses(ctx, buyerId)
+	for i := range addresses {
+		if addresses[i].Id == id {
+			return &addresses[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	mock_audit "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditedService(t *testing.T) {
+	before := &domain.Buyer{Id: 1, CardNumberId: "402323", FirstName: "Jhon"}
+	after := &domain.Buyer{Id: 1, CardNumberId: "402323", FirstName: "John"}
+
+	t.Run("Create records the new buyer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().Create(gomock.Any(), "402323", "Jhon", "Doe").Return(before, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityBuyers, 1, auditDomain.ActionCreate, nil, before)
+
+		_, err := NewAuditedService(service, audit).Create(context.TODO(), "402323", "Jhon", "Doe")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Update records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		name := "John"
+		patch := domain.BuyerPatch{FirstName: &name}
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Update(gomock.Any(), 1, patch).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityBuyers, 1, auditDomain.ActionUpdate, before, after)
+
+		_, err := NewAuditedService(service, audit).Update(context.TODO(), 1, patch)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete records the removed buyer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Delete(gomock.Any(), 1).Return(nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityBuyers, 1, auditDomain.ActionDelete, before, nil)
+
+		assert.NoError(t, NewAuditedService(service, audit).Delete(context.TODO(), 1))
+	})
+
+	t.Run("Restore records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Restore(gomock.Any(), 1).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityBuyers, 1, auditDomain.ActionRestore, before, after)
+
+		_, err := NewAuditedService(service, audit).Restore(context.TODO(), 1)
+		assert.NoError(t, err)
+	})
+
+	home := domain.Address{Id: 3, BuyerId: 1, Address: "Rua A, 10", LocalityId: 1, IsDefault: true}
+	work := domain.Address{Id: 3, BuyerId: 1, Address: "Rua B, 20", LocalityId: 1, IsDefault: true}
+
+	t.Run("CreateAddress records the new address", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		arg := domain.Address{BuyerId: 1, Address: "Rua A, 10", LocalityId: 1}
+		service.EXPECT().CreateAddress(gomock.Any(), arg).Return(&home, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityBuyerAddresses, 3, auditDomain.ActionCreate, nil, &home)
+
+		_, err := NewAuditedService(service, audit).CreateAddress(context.TODO(), arg)
+		assert.NoError(t, err)
+	})
+
+	t.Run("UpdateAddress records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		street := "Rua B, 20"
+		patch := domain.AddressPatch{Address: &street}
+		service.EXPECT().GetAddresses(gomock.Any(), 1).Return([]domain.Address{{Id: 2, BuyerId: 1}, home}, nil)
+		service.EXPECT().UpdateAddress(gomock.Any(), 1, 3, patch).Return(&work, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityBuyerAddresses, 3, auditDomain.ActionUpdate, &home, &work)
+
+		_, err := NewAuditedService(service, audit).UpdateAddress(context.TODO(), 1, 3, patch)
+		assert.NoError(t, err)
+	})
+
+	t.Run("DeleteAddress records the removed address", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetAddresses(gomock.Any(), 1).Return([]domain.Address{home}, nil)
+		service.EXPECT().DeleteAddress(gomock.Any(), 1, 3).Return(nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityBuyerAddresses, 3, auditDomain.ActionDelete, &home, nil)
+
+		assert.NoError(t, NewAuditedService(service, audit).DeleteAddress(context.TODO(), 1, 3))
+	})
+
+	t.Run("Failed address changes are not recorded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetAddresses(gomock.Any(), 1).Return([]domain.Address{home}, nil)
+		service.EXPECT().DeleteAddress(gomock.Any(), 1, 3).Return(domain.ErrDefaultAddress)
+
+		assert.ErrorIs(t, NewAuditedService(service, audit).DeleteAddress(context.TODO(), 1, 3), domain.ErrDefaultAddress)
+	})
+}
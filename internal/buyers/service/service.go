@@ -5,15 +5,20 @@ import (
 	"fmt"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	localityDomain "github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 )
 
 type service struct {
 	repository domain.Repository
+	localities localityDomain.LocalityRepository
 }
 
-func NewService(r domain.Repository) domain.Service {
+func NewService(r domain.Repository, localities localityDomain.LocalityRepository) domain.Service {
 	return &service{
 		repository: r,
+		localities: localities,
 	}
 }
 
@@ -44,7 +49,8 @@ func (s service) GetOrdersByBuyers(ctx context.Context, id int) ([]domain.Orders
 }
 
 func (s service) Create(ctx context.Context, cardNumberId, firstName, lastName string) (*domain.Buyer, error) {
-	buy, err := s.repository.GetAll(ctx)
+	// Deleted buyers keep their card number until they are purged.
+	buy, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
 
 	if err != nil {
 		return nil, err
@@ -55,24 +61,29 @@ func (s service) Create(ctx context.Context, cardNumberId, firstName, lastName s
 			return nil, fmt.Errorf("this card number id already exists")
 		}
 	}
-	buyer, _ := s.repository.Create(ctx, cardNumberId, firstName, lastName)
+	buyer, err := s.repository.Create(ctx, cardNumberId, firstName, lastName)
+	if err != nil {
+		return nil, err
+	}
 
 	return buyer, nil
 }
 
-func (s service) Update(ctx context.Context, id int, cardNumberId, firstName, lastName string) (*domain.Buyer, error) {
-	sl, err := s.repository.GetAll(ctx)
-	if err != nil {
-		return nil, err
-	}
+func (s service) Update(ctx context.Context, id int, arg domain.BuyerPatch) (*domain.Buyer, error) {
+	if arg.CardNumberId != nil {
+		sl, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
+		if err != nil {
+			return nil, err
+		}
 
-	for i := range sl {
-		if sl[i].CardNumberId == cardNumberId {
-			return nil, fmt.Errorf("this Buyer already exists")
+		for i := range sl {
+			if sl[i].Id != id && sl[i].CardNumberId == *arg.CardNumberId {
+				return nil, fmt.Errorf("this Buyer already exists")
+			}
 		}
 	}
 
-	Buyer, err := s.repository.Update(ctx, id, cardNumberId, firstName, lastName)
+	Buyer, err := s.repository.Update(ctx, id, arg)
 	if err != nil {
 		return nil, err
 	}
@@ -81,9 +92,27 @@ func (s service) Update(ctx context.Context, id int, cardNumberId, firstName, la
 }
 
 func (s service) Delete(ctx context.Context, id int) error {
-	err := s.repository.Delete(ctx, id)
+	// Purchase orders are kept as history, so they are never cascaded and
+	// always block the delete.
+	found, err := s.repository.Dependents(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := dependents.Check("buyer", id, found...); err != nil {
+		return err
+	}
+
+	err = s.repository.Delete(ctx, id)
 	if err != nil {
 		return err
 	}
 	return err
 }
+
+func (s service) Restore(ctx context.Context, id int) (*domain.Buyer, error) {
+	if err := s.repository.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repository.GetById(ctx, id)
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
 	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain/mock"
+	mock_localities "github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
 
 	"testing"
 
@@ -14,11 +16,17 @@ import (
 )
 
 func callBuyersMock(t *testing.T) (*mock_domain.MockRepository, domain.Service) {
+	apiMock, _, service := callBuyersMockWithLocalities(t)
+	return apiMock, service
+}
+
+func callBuyersMockWithLocalities(t *testing.T) (*mock_domain.MockRepository, *mock_localities.MockLocalityRepository, domain.Service) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	apiMock := mock_domain.NewMockRepository(ctrl)
-	service := NewService(apiMock)
-	return apiMock, service
+	localities := mock_localities.NewMockLocalityRepository(ctrl)
+	service := NewService(apiMock, localities)
+	return apiMock, localities, service
 }
 
 //CREATE create_ok Se contiver os campos necessários, será criado
@@ -46,7 +54,7 @@ func TestService_Create_Ok(t *testing.T) {
 	}
 
 	apiMock, service := callBuyersMock(t)
-	apiMock.EXPECT().GetAll(context.TODO()).Return(buyList, nil)
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(buyList, nil)
 	apiMock.EXPECT().Create(
 		context.TODO(),
 		"5",
@@ -78,7 +86,7 @@ func TestService_Create_Nok(t *testing.T) {
 	}
 
 	apiMock, service := callBuyersMock(t)
-	apiMock.EXPECT().GetAll(context.TODO()).Return(buyList, nil)
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(buyList, nil)
 	apiMock.EXPECT().Create(context.TODO(), "3", "Douglas", "Mendes").Return(&domain.Buyer{}, errors.New("this card number id already exists"))
 
 	_, err := service.Create(context.TODO(), "3", "Douglas", "Mendes")
@@ -104,7 +112,7 @@ func TestService_GetAll(t *testing.T) {
 	}
 
 	apiMock, service := callBuyersMock(t)
-	apiMock.EXPECT().GetAll(context.TODO()).Return(buyList, nil)
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(buyList, nil)
 
 	result, err := service.GetAll(context.TODO())
 	assert.Equal(t, len(result), len(buyList))
@@ -182,6 +190,7 @@ func TestService_GetOrdersByBuyers_NOk(t *testing.T) {
 //DELETE - delete_non_existent - Quando o funcionário não existir, será retornado null.
 func TestService_Delete_Ok(t *testing.T) {
 	apiMock, service := callBuyersMock(t)
+	apiMock.EXPECT().Dependents(context.TODO(), 1).Return([]dependents.Dependent{{Type: "purchase_orders", Count: 0}}, nil)
 	apiMock.EXPECT().Delete(context.TODO(), 1).Return(nil)
 	err := service.Delete(context.TODO(), 1)
 	assert.Nil(t, err)
@@ -189,11 +198,24 @@ func TestService_Delete_Ok(t *testing.T) {
 
 func TestService_Delete_Nok(t *testing.T) {
 	apiMock, service := callBuyersMock(t)
+	apiMock.EXPECT().Dependents(context.TODO(), 1).Return([]dependents.Dependent{{Type: "purchase_orders", Count: 0}}, nil)
 	apiMock.EXPECT().Delete(context.TODO(), 1).Return(errors.New("buyer 1 not found"))
 	err := service.Delete(context.TODO(), 1)
 	assert.NotNil(t, err)
 }
 
+func TestService_Delete_WithPurchaseOrders(t *testing.T) {
+	apiMock, service := callBuyersMock(t)
+	apiMock.EXPECT().Dependents(gomock.Any(), 1).Return([]dependents.Dependent{{Type: "purchase_orders", Count: 4}}, nil).Times(2)
+
+	err := service.Delete(context.TODO(), 1)
+	assert.EqualError(t, err, "buyer 1 cannot be deleted, it still has 4 purchase_orders")
+
+	// purchase orders are never cascaded
+	err = service.Delete(dependents.WithCascade(context.TODO()), 1)
+	assert.ErrorAs(t, err, new(*dependents.Error))
+}
+
 func TestService_Update_Ok(t *testing.T) {
 	buyList := []domain.Buyer{
 		{
@@ -218,10 +240,10 @@ func TestService_Update_Ok(t *testing.T) {
 	}
 	apiMock, service := callBuyersMock(t)
 
-	apiMock.EXPECT().GetAll(context.TODO()).Return(buyList, nil)
-	apiMock.EXPECT().Update(context.TODO(), 3, "5", "Douglas", "Mendes").Return(&buy, nil)
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(buyList, nil)
+	apiMock.EXPECT().Update(context.TODO(), 3, domain.BuyerPatch{CardNumberId: stringPtr("5"), FirstName: stringPtr("Douglas"), LastName: stringPtr("Mendes")}).Return(&buy, nil)
 
-	result, err := service.Update(context.TODO(), 3, "5", "Douglas", "Mendes")
+	result, err := service.Update(context.TODO(), 3, domain.BuyerPatch{CardNumberId: stringPtr("5"), FirstName: stringPtr("Douglas"), LastName: stringPtr("Mendes")})
 	assert.Nil(t, err)
 	assert.Equal(t, result, &buy)
 }
@@ -243,10 +265,34 @@ func TestService_Update_Nok(t *testing.T) {
 	}
 
 	apiMock, service := callBuyersMock(t)
-	apiMock.EXPECT().GetAll(context.TODO()).Return(buyList, nil)
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(buyList, nil)
 
-	_, err := service.Update(context.TODO(), 1, "3", "Joao", "Zinho")
+	_, err := service.Update(context.TODO(), 1, domain.BuyerPatch{CardNumberId: stringPtr("3"), FirstName: stringPtr("Joao"), LastName: stringPtr("Zinho")})
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, "this Buyer already exists")
 
 }
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func TestService_Restore_Ok(t *testing.T) {
+	buy := domain.Buyer{Id: 1, CardNumberId: "5", FirstName: "Douglas", LastName: "Mendes"}
+
+	apiMock, service := callBuyersMock(t)
+	apiMock.EXPECT().Restore(context.TODO(), 1).Return(nil)
+	apiMock.EXPECT().GetById(context.TODO(), 1).Return(&buy, nil)
+
+	result, err := service.Restore(context.TODO(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, &buy, result)
+}
+
+func TestService_Restore_Nok(t *testing.T) {
+	apiMock, service := callBuyersMock(t)
+	apiMock.EXPECT().Restore(context.TODO(), 1).Return(errors.New("deleted buyer 1 not found"))
+
+	_, err := service.Restore(context.TODO(), 1)
+	assert.NotNil(t, err)
+}
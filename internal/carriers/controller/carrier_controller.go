@@ -3,8 +3,12 @@ package controller
 import (
 	"github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -34,7 +38,7 @@ func (c *CarrierController) Create() gin.HandlerFunc {
 		var request carriesCreateRequest
 
 		if err := ctx.ShouldBindJSON(&request); err != nil {
-			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			ctx.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 			return
 		}
 
@@ -56,10 +60,51 @@ func (c *CarrierController) Create() gin.HandlerFunc {
 	}
 }
 
+// Workload godoc
+// @Summary Carrier workload
+// @Tags Carriers
+// @Description purchase orders assigned to every carrier
+// @Produce  json
+// @Param locality_id query int false "Only the carriers serving this locality"
+// @Param from query string false "First order day, YYYY-MM-DD"
+// @Param to query string false "Last order day, YYYY-MM-DD"
+// @Success 200 {array} domain.CarrierWorkload
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/carriers/workload [get]
+func (c *CarrierController) Workload() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		localityId := 0
+		if value, ok := ctx.GetQuery("locality_id"); ok {
+			id, err := strconv.Atoi(value)
+			if err != nil || id < 1 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid locality_id"})
+				return
+			}
+			localityId = id
+		}
+
+		dates, err := period.RangeFromQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		workload, err := c.service.GetWorkload(ctx, localityId, dates)
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(workload))
+	}
+}
+
 type carriesCreateRequest struct {
 	Cid         string `json:"cid" binding:"required"`
 	CompanyName string `json:"company_name" binding:"required"`
 	Address     string `json:"address" binding:"required"`
-	Telephone   string `json:"telephone" binding:"required"`
-	LocalityId  int    `json:"locality_id" binding:"required"`
+	Telephone   string `json:"telephone" binding:"required,phone"`
+	LocalityId  int    `json:"locality_id" binding:"required,min=1"`
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain"
 	mockcarriers "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -91,3 +93,47 @@ func TestCarrierController_Create_Conflict(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, resp.Code)
 
 }
+
+func TestCarrierController_Workload(t *testing.T) {
+	const path = "/api/v1/carriers/workload"
+
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := callCarriersMock(t)
+		api.GET(path, handler.Workload())
+
+		dates := period.Range{From: date.New(2022, 8, 1), To: date.New(2022, 8, 31)}
+		service.EXPECT().GetWorkload(ctxMock, 2, dates).
+			Return([]domain.CarrierWorkload{{Carrier: domain.Carrier{Id: 1, LocalityId: 2}, OrdersCount: 3, OpenOrdersCount: 2}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, path+"?locality_id=2&from=2022-08-01&to=2022-08-31", nil)
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"data": [{"id": 1, "locality_id": 2, "orders_count": 3, "open_orders_count": 2}]}`, resp.Body.String())
+	})
+
+	t.Run("Locality not found", func(t *testing.T) {
+		service, handler, api := callCarriersMock(t)
+		api.GET(path, handler.Workload())
+
+		service.EXPECT().GetWorkload(ctxMock, 9, period.Range{}).Return(nil, errors.New("locality 9 not found"))
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path+"?locality_id=9", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	for _, query := range []string{"?locality_id=abc", "?from=2022-09-01&to=2022-08-01"} {
+		t.Run("Bad request "+query, func(t *testing.T) {
+			_, handler, api := callCarriersMock(t)
+			api.GET(path, handler.Workload())
+
+			resp := httptest.NewRecorder()
+			api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 )
 
 type Carrier struct {
@@ -13,12 +15,23 @@ type Carrier struct {
 	LocalityId  int    `json:"locality_id,omitempty"`
 }
 
+// CarrierWorkload is a carrier along with the purchase orders assigned to
+// it. OpenOrdersCount leaves out the orders in a terminal status, which the
+// carrier is done with.
+type CarrierWorkload struct {
+	Carrier
+	OrdersCount     int `json:"orders_count"`
+	OpenOrdersCount int `json:"open_orders_count"`
+}
+
 //go:generate mockgen -source=./carrier.go -destination=./mock/carrier_mock.go
 type CarrierRepository interface {
 	GetAll(ctx context.Context) ([]Carrier, error)
 	Create(ctx context.Context, cid, companyName, address, telephone string, localityId int) (Carrier, error)
+	GetWorkload(ctx context.Context, localityId int, dates period.Range) ([]CarrierWorkload, error)
 }
 
 type CarrierService interface {
 	CreateCarrier(ctx context.Context, cid, companyName, address, telephone string, localityId int) (Carrier, error)
+	GetWorkload(ctx context.Context, localityId int, dates period.Range) ([]CarrierWorkload, error)
 }
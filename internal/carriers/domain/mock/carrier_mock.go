@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	domain "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain"
+	period "github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -65,6 +66,21 @@ func (mr *MockCarrierRepositoryMockRecorder) GetAll(ctx interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCarrierRepository)(nil).GetAll), ctx)
 }
 
+// GetWorkload mocks base method.
+func (m *MockCarrierRepository) GetWorkload(ctx context.Context, localityId int, dates period.Range) ([]domain.CarrierWorkload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkload", ctx, localityId, dates)
+	ret0, _ := ret[0].([]domain.CarrierWorkload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkload indicates an expected call of GetWorkload.
+func (mr *MockCarrierRepositoryMockRecorder) GetWorkload(ctx, localityId, dates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkload", reflect.TypeOf((*MockCarrierRepository)(nil).GetWorkload), ctx, localityId, dates)
+}
+
 // MockCarrierService is a mock of CarrierService interface.
 type MockCarrierService struct {
 	ctrl     *gomock.Controller
@@ -102,3 +118,18 @@ func (mr *MockCarrierServiceMockRecorder) CreateCarrier(ctx, cid, companyName, a
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCarrier", reflect.TypeOf((*MockCarrierService)(nil).CreateCarrier), ctx, cid, companyName, address, telephone, localityId)
 }
+
+// GetWorkload mocks base method.
+func (m *MockCarrierService) GetWorkload(ctx context.Context, localityId int, dates period.Range) ([]domain.CarrierWorkload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkload", ctx, localityId, dates)
+	ret0, _ := ret[0].([]domain.CarrierWorkload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkload indicates an expected call of GetWorkload.
+func (mr *MockCarrierServiceMockRecorder) GetWorkload(ctx, localityId, dates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkload", reflect.TypeOf((*MockCarrierService)(nil).GetWorkload), ctx, localityId, dates)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
 )
 
@@ -70,3 +71,43 @@ func (r repository) Create(ctx context.Context, cid, companyName, address, telep
 
 	return carrier, nil
 }
+
+// GetWorkload counts the purchase orders placed within dates that are
+// assigned to every carrier, or to the carriers of localityId when it is not
+// 0, and how many of them are still open. Carriers without orders are listed
+// with counts of 0.
+func (r repository) GetWorkload(ctx context.Context, localityId int, dates period.Range) ([]domain.CarrierWorkload, error) {
+	query, args := dates.Where("p.order_date")
+	query = sqlGetWorkload + query + sqlGetWorkloadStatus
+	if localityId != 0 {
+		query, args = query+" WHERE c.locality_id = ?", append(args, localityId)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query+sqlGetWorkloadGroup, args...)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	workload := []domain.CarrierWorkload{}
+	for rows.Next() {
+		var carrier domain.CarrierWorkload
+		if err := rows.Scan(
+			&carrier.Id,
+			&carrier.Cid,
+			&carrier.CompanyName,
+			&carrier.Address,
+			&carrier.Telephone,
+			&carrier.LocalityId,
+			&carrier.OrdersCount,
+			&carrier.OpenOrdersCount,
+		); err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			return nil, err
+		}
+		workload = append(workload, carrier)
+	}
+	return workload, rows.Err()
+}
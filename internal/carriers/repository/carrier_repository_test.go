@@ -5,6 +5,8 @@ import (
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"github.com/stretchr/testify/assert"
 	"regexp"
 	"testing"
@@ -137,3 +139,47 @@ func TestRepository_GetAll_NOK(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, carriersMock, result)
 }
+
+func TestRepository_GetWorkload(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dates := period.Range{From: date.New(2022, 8, 1)}
+	rows := sqlmock.NewRows([]string{
+		"id", "cid", "company_name", "address", "telephone", "locality_id", "orders_count", "open_orders_count",
+	}).AddRow(1, "1010", "Kato Canecas LTDA", "Wall Street, 20", "55111444111", 25, 3, 1).
+		AddRow(2, "2020", "Leva e Traz", "Rua Um, 1", "55111444222", 25, 0, 0)
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetWorkload+" AND p.order_date >= ?"+sqlGetWorkloadStatus+" WHERE c.locality_id = ?"+sqlGetWorkloadGroup)).
+		WithArgs(dates.From, 25).
+		WillReturnRows(rows)
+
+	carrierRepo := NewRepository(db)
+	result, err := carrierRepo.GetWorkload(context.TODO(), 25, dates)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.CarrierWorkload{
+		{
+			Carrier:         domain.Carrier{Id: 1, Cid: "1010", CompanyName: "Kato Canecas LTDA", Address: "Wall Street, 20", Telephone: "55111444111", LocalityId: 25},
+			OrdersCount:     3,
+			OpenOrdersCount: 1,
+		},
+		{
+			Carrier: domain.Carrier{Id: 2, Cid: "2020", CompanyName: "Leva e Traz", Address: "Rua Um, 1", Telephone: "55111444222", LocalityId: 25},
+		},
+	}, result)
+}
+
+func TestRepository_GetWorkload_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetWorkload + sqlGetWorkloadStatus + sqlGetWorkloadGroup)).WillReturnError(errors.New("connection refused"))
+
+	carrierRepo := NewRepository(db)
+	_, err = carrierRepo.GetWorkload(context.TODO(), 0, period.Range{})
+
+	assert.Error(t, err)
+}
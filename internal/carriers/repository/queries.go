@@ -3,4 +3,10 @@ package repository
 const (
 	sqlCreateCarrier  = "INSERT INTO carries (cid, company_name, address, telephone, locality_id) VALUES (?, ?, ?, ?, ?)"
 	sqlGetAllCarriers = "SELECT id, cid, company_name, address, telephone, locality_id FROM carries"
+	// sqlGetWorkload is completed with the date range conditions of the
+	// orders, sqlGetWorkloadStatus, the locality filter and
+	// sqlGetWorkloadGroup.
+	sqlGetWorkload       = "SELECT c.id, c.cid, c.company_name, c.address, c.telephone, c.locality_id, COUNT(p.id), COUNT(s.id) FROM carries c LEFT JOIN purchase_orders p ON p.carrier_id = c.id"
+	sqlGetWorkloadStatus = " LEFT JOIN order_status s ON s.id = p.order_status_id AND s.is_terminal = FALSE"
+	sqlGetWorkloadGroup  = " GROUP BY c.id ORDER BY c.id"
 )
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	carrierRepo "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain"
+)
+
+type auditedService struct {
+	carrierRepo.CarrierService
+	audit auditDomain.Service
+}
+
+// NewAuditedService records every change made through s in the audit trail.
+func NewAuditedService(s carrierRepo.CarrierService, audit auditDomain.Service) carrierRepo.CarrierService {
+	return &auditedService{s, audit}
+}
+
+func (s *auditedService) CreateCarrier(ctx context.Context, cid, companyName, address, telephone string, localityId int) (carrierRepo.Carrier, error) {
+	carrier, err := s.CarrierService.CreateCarrier(ctx, cid, companyName, address, telephone, localityId)
+	if err != nil {
+		return carrierRepo.Carrier{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityCarriers, carrier.Id, auditDomain.ActionCreate, nil, carrier)
+
+	return carrier, nil
+}
@@ -6,6 +6,7 @@ import (
 	carrierRepo "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain"
 	localityRepo "github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
 )
 
@@ -54,3 +55,19 @@ func (s *service) CreateCarrier(
 	}
 	return carrier, nil
 }
+
+func (s *service) GetWorkload(ctx context.Context, localityId int, dates period.Range) ([]carrierRepo.CarrierWorkload, error) {
+	if localityId != 0 {
+		if _, err := s.localityRepository.GetById(ctx, localityId); err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			return nil, err
+		}
+	}
+
+	workload, err := s.carrierRepository.GetWorkload(ctx, localityId, dates)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+	return workload, nil
+}
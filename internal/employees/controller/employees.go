@@ -1,9 +1,18 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/bulk"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/patch"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
@@ -15,10 +24,17 @@ type EmployeesController struct {
 
 type requestEmployee struct {
 	Id           int    `json:"id"`
-	CardNumberId string `json:"card_number_id"`
-	FirstName    string `json:"first_name"`
-	LastName     string `json:"last_name"`
-	WarehouseId  int    `json:"warehouse_id"`
+	CardNumberId string `json:"card_number_id" binding:"required"`
+	FirstName    string `json:"first_name" binding:"required"`
+	LastName     string `json:"last_name" binding:"required"`
+	WarehouseId  int    `json:"warehouse_id" binding:"required,min=1"`
+}
+
+type requestEmployeeUpdate struct {
+	CardNumberId *string `json:"card_number_id" binding:"omitempty,min=1"`
+	FirstName    *string `json:"first_name" binding:"omitempty,min=1"`
+	LastName     *string `json:"last_name" binding:"omitempty,min=1"`
+	WarehouseId  *int    `json:"warehouse_id" binding:"omitempty,min=1"`
 }
 
 func NewEmployees(e domain.Service) *EmployeesController {
@@ -32,11 +48,17 @@ func NewEmployees(e domain.Service) *EmployeesController {
 // @Tags         employees
 // @Description  get employees
 // @Produce      json
+// @Param        include_deleted  query  bool  false  "Include soft deleted employees"
 // @Success      200  {object}  request
+// @Failure      400  {object}  string
 // @Failure      404  {object}  string
 // @Router       /api/v1/employees [get]
 func (c *EmployeesController) GetAll() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if err := softdelete.FromQuery(ctx); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		e, err := c.service.GetAll(ctx)
 		if err != nil {
 			ctx.JSON(http.StatusNotFound, gin.H{
@@ -54,8 +76,10 @@ func (c *EmployeesController) GetAll() gin.HandlerFunc {
 // @Tags         employees
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "Employee id"
+// @Param        id               path   int   true   "Employee id"
+// @Param        include_deleted  query  bool  false  "Include soft deleted employees"
 // @Success      200  {object}  employees.Employee
+// @Failure      400  {object}  string
 // @Failure      404  {object}  string
 // @Router       /api/v1/employees/{id} [get]
 func (c *EmployeesController) GetById() gin.HandlerFunc {
@@ -65,12 +89,17 @@ func (c *EmployeesController) GetById() gin.HandlerFunc {
 			ctx.JSON(400, gin.H{"error": "Invalid ID"})
 			return
 		}
+		if err := softdelete.FromQuery(ctx); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		e, err := c.service.GetById(ctx, int64(id))
 		if err != nil {
 			ctx.JSON(404, gin.H{"error": err.Error()})
 			return
 		}
 
+		etag.Set(ctx, e.Version)
 		ctx.JSON(http.StatusOK, response.NewResponse(e))
 	}
 }
@@ -90,11 +119,7 @@ func (c *EmployeesController) Create() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var req requestEmployee
 		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.AbortWithStatusJSON(http.StatusBadRequest,
-				gin.H{
-					"error":  "VALIDATEERR-1",
-					"messge": "Invalid inputs. Please check your inputs",
-				})
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 			return
 		}
 		e, err := c.service.Create(ctx, req.CardNumberId, req.FirstName, req.LastName, req.WarehouseId)
@@ -103,22 +128,49 @@ func (c *EmployeesController) Create() gin.HandlerFunc {
 			return
 		}
 
+		etag.Set(ctx, e.Version)
 		ctx.JSON(http.StatusCreated, response.NewResponse(e))
 	}
 
 }
 
+// ImportEmployees godoc
+// @Summary Import employees
+// @Tags Employees
+// @Description Create employees in bulk from a CSV or NDJSON upload, reporting the outcome of each row
+// @Accept text/csv,application/x-ndjson,multipart/form-data
+// @Produce json
+// @Param atomic query bool false "Keep no employee when any row fails"
+// @Param file formData file false "CSV or NDJSON file, for multipart uploads"
+// @Success 200 {object} bulk.Report
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} bulk.Report
+// @Router /api/v1/employees/import [post]
+func (c *EmployeesController) Import(runner transaction.Runner) gin.HandlerFunc {
+	return bulk.Handler(runner, func(ctx context.Context, req requestEmployee) (int, error) {
+		employee, err := c.service.Create(ctx, req.CardNumberId, req.FirstName, req.LastName, req.WarehouseId)
+		if err != nil {
+			return 0, err
+		}
+		return int(employee.Id), nil
+	})
+}
+
 // ListEmployees godoc
 // @Summary      Update employee
 // @Tags         employees
-// @Description  update employee
+// @Description  update employee with a JSON merge patch (RFC 7396)
 // @Accept       json
 // @Produce      json
-// @Param        product  body      requestEmployee  true  "Employee to create"
+// @Param        product  body      requestEmployeeUpdate  true  "Fields to update"
 // @Param        id       path      int      true  "Employee ID"
+// @Param        If-Match header    string   true  "ETag of the employee being updated"
 // @Success      200      {object}  employees.Employee
 // @Failure      400       {object}  string
 // @Failure      404       {object}  string
+// @Failure      412       {object}  response.Response
+// @Failure      422       {object}  string
+// @Failure      428       {object}  response.Response
 // @Router       /api/v1/employees/{id} [patch]
 func (c *EmployeesController) Update() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -127,16 +179,25 @@ func (c *EmployeesController) Update() gin.HandlerFunc {
 			ctx.JSON(400, gin.H{"error": "Invalid ID"})
 			return
 		}
-		var req requestEmployee
-		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(400, gin.H{"error": err.Error})
+		if err := etag.FromIfMatch(ctx); err != nil {
+			ctx.JSON(etag.Status(err), response.DecodeError(err.Error()))
+			return
+		}
+		var req requestEmployeeUpdate
+		if err := patch.Bind(ctx, &req); err != nil {
+			ctx.JSON(patch.Status(err), validation.NewErrorResponse(err))
 			return
 		}
-		e, err := c.service.Update(ctx, int64(id), req.CardNumberId, req.FirstName, req.LastName, req.WarehouseId)
+		e, err := c.service.Update(ctx, int64(id), domain.EmployeePatch(req))
 		if err != nil {
+			if errors.Is(err, etag.ErrMismatch) {
+				ctx.JSON(http.StatusPreconditionFailed, response.DecodeError(err.Error()))
+				return
+			}
 			ctx.JSON(404, gin.H{"error": err.Error()})
 			return
 		}
+		etag.Set(ctx, e.Version)
 		ctx.JSON(200, e)
 
 	}
@@ -150,9 +211,13 @@ func (c *EmployeesController) Update() gin.HandlerFunc {
 // @Accept       json
 // @Produce      json
 // @Param        id   path  int  true  "Employee id"
+// @Param        If-Match  header  string  true  "ETag of the employee being deleted"
 // @Success      204  "Successfully deleted"
 // @Failure      400  {object}  string
 // @Failure      404  {object}  string
+// @Failure      409  {object}  response.Response
+// @Failure      412  {object}  response.Response
+// @Failure      428  {object}  response.Response
 // @Router       /api/v1/employees/{id} [delete]
 func (c *EmployeesController) Delete() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -161,11 +226,51 @@ func (c *EmployeesController) Delete() gin.HandlerFunc {
 			ctx.JSON(400, gin.H{"error": "invalid ID"})
 			return
 		}
+		if err := etag.FromIfMatch(ctx); err != nil {
+			ctx.JSON(etag.Status(err), response.DecodeError(err.Error()))
+			return
+		}
 		err = c.service.Delete(ctx, int64(id))
 		if err != nil {
+			if errors.Is(err, etag.ErrMismatch) {
+				ctx.JSON(http.StatusPreconditionFailed, response.DecodeError(err.Error()))
+				return
+			}
+			var dependentsErr *dependents.Error
+			if errors.As(err, &dependentsErr) {
+				ctx.JSON(http.StatusConflict, response.DecodeDependents(dependentsErr))
+				return
+			}
 			ctx.JSON(404, gin.H{"error": err.Error()})
 			return
 		}
 		ctx.JSON(200, gin.H{"data": fmt.Sprintf("employee %d was removed", id)})
 	}
 }
+
+// RestoreEmployee godoc
+// @Summary      Restore a employee
+// @Description  Restore a deleted employee, selecting by id
+// @Tags         employees
+// @Produce      json
+// @Param        id   path      int  true  "Employee id"
+// @Success      200  {object}  employees.Employee
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Router       /api/v1/employees/{id}/restore [post]
+func (c *EmployeesController) Restore() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			ctx.JSON(400, gin.H{"error": "invalid ID"})
+			return
+		}
+		e, err := c.service.Restore(ctx, int64(id))
+		if err != nil {
+			ctx.JSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		etag.Set(ctx, e.Version)
+		ctx.JSON(http.StatusOK, response.NewResponse(e))
+	}
+}
@@ -2,9 +2,15 @@ package controller
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +22,7 @@ import (
 const (
 	relativePathEmployees = "/api/v1/employees/"
 	target                = "/api/v1/employees/:id"
+	restoreTarget         = "/api/v1/employees/:id/restore"
 )
 
 func callMock(t *testing.T) (*mock_domain.MockService, *EmployeesController) {
@@ -32,18 +39,18 @@ func callMock(t *testing.T) (*mock_domain.MockService, *EmployeesController) {
 func TestController_GetAll(t *testing.T) {
 	empList := []domain.Employee{
 		{
-			1,
-			"3030",
-			"Douglas",
-			"Mendes",
-			3,
+			Id:           1,
+			CardNumberId: "3030",
+			FirstName:    "Douglas",
+			LastName:     "Mendes",
+			WarehouseId:  3,
 		},
 		{
-			2,
-			"40",
-			"Gustavo",
-			"Naganuma",
-			33,
+			Id:           2,
+			CardNumberId: "40",
+			FirstName:    "Gustavo",
+			LastName:     "Naganuma",
+			WarehouseId:  33,
 		},
 	}
 
@@ -98,6 +105,7 @@ func TestController_ById_Ok(t *testing.T) {
 		FirstName:    "Douglas",
 		LastName:     "Mendes",
 		WarehouseId:  3,
+		Version:      5,
 	}
 	service, handler := callMock(t)
 
@@ -113,6 +121,7 @@ func TestController_ById_Ok(t *testing.T) {
 	//_ = json.Unmarshal(resp.Body.Bytes(), &respExpect.Data.Id)
 
 	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `"5"`, resp.Header().Get(etag.Header))
 	//assert.Equal(t, emp.Id, respExpect.Data.Id)
 
 }
@@ -162,6 +171,45 @@ func TestController_Create_Nok(t *testing.T) {
 
 }
 
+//CREATE create_fail Se o objeto JSON não contiver os campos necessários, um código 422 será retornado
+func TestController_Create_Unprocessable(t *testing.T) {
+
+	_, handler := callMock(t)
+	api := gin.New()
+	api.POST(relativePathEmployees, handler.Create())
+
+	body := `{"card_number_id": "3030","first_name": "","warehouse_id": 0}`
+	req := httptest.NewRequest(http.MethodPost, relativePathEmployees, bytes.NewBuffer([]byte(body)))
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	var got response.Response
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	assert.Equal(t, []response.FieldError{
+		{Field: "first_name", Code: "required", Message: "is required"},
+		{Field: "last_name", Code: "required", Message: "is required"},
+		{Field: "warehouse_id", Code: "required", Message: "is required"},
+	}, got.Fields)
+
+}
+
+var employeePatch = domain.EmployeePatch{
+	CardNumberId: stringPtr("3030"),
+	FirstName:    stringPtr("Douglas"),
+	LastName:     stringPtr("Mendes"),
+	WarehouseId:  intPtr(3),
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
 //UPDATE update_ok Quando a atualização dos dados for bem
 //sucedida, o funcionário será devolvido
 //com as informações atualizadas
@@ -180,10 +228,7 @@ func TestController_Update_Ok(t *testing.T) {
 	api.PATCH(target, handler.Update())
 	service.EXPECT().Update(gomock.Any(),
 		int64(1),
-		"3030",
-		"Douglas",
-		"Mendes",
-		3,
+		employeePatch,
 	).Return(emp, nil)
 
 	body := `{"card_number_id": "3030","first_name": "Douglas","last_name": "Mendes","warehouse_id": 3}`
@@ -192,6 +237,7 @@ func TestController_Update_Ok(t *testing.T) {
 		"/api/v1/employees/1",
 		bytes.NewBuffer([]byte(body)),
 	)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
@@ -209,10 +255,7 @@ func TestController_Update_Nok(t *testing.T) {
 	api.PATCH(target, handler.Update())
 	service.EXPECT().Update(gomock.Any(),
 		int64(1),
-		"3030",
-		"Douglas",
-		"Mendes",
-		3,
+		employeePatch,
 	).Return(nil, errors.New("this employee already exists"))
 
 	body := `{"card_number_id": "3030","first_name": "Douglas","last_name": "Mendes","warehouse_id": 3}`
@@ -221,6 +264,7 @@ func TestController_Update_Nok(t *testing.T) {
 		"/api/v1/employees/1",
 		bytes.NewBuffer([]byte(body)),
 	)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
@@ -228,6 +272,44 @@ func TestController_Update_Nok(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 
 }
+func TestController_Update_Precondition(t *testing.T) {
+	service, handler := callMock(t)
+	api := gin.New()
+	api.PATCH(target, handler.Update())
+
+	body := `{"last_name": "Leonardo"}`
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, httptest.NewRequest(http.MethodPatch, "/api/v1/employees/1", bytes.NewBuffer([]byte(body))))
+	assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+
+	lastName := "Leonardo"
+	service.EXPECT().Update(gomock.Any(), int64(1), domain.EmployeePatch{LastName: &lastName}).Return(nil, etag.ErrMismatch)
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/employees/1", bytes.NewBuffer([]byte(body)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp = httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+}
+
+func TestController_Update_UnknownField(t *testing.T) {
+	_, handler := callMock(t)
+	api := gin.New()
+	api.PATCH(target, handler.Update())
+
+	body := `{"id": 2, "first_name": "Douglas"}`
+	req := httptest.NewRequest(
+		http.MethodPatch,
+		"/api/v1/employees/1",
+		bytes.NewBuffer([]byte(body)),
+	)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
 func TestController_Delete_Ok(t *testing.T) {
 	service, handler := callMock(t)
 	api := gin.New()
@@ -239,6 +321,7 @@ func TestController_Delete_Ok(t *testing.T) {
 		"/api/v1/employees/1",
 		nil,
 	)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 	assert.Equal(t, http.StatusOK, resp.Code)
@@ -255,10 +338,51 @@ func TestController_Delete_Nok(t *testing.T) {
 		"/api/v1/employees/1",
 		nil,
 	)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
+
+func TestController_Delete_Precondition(t *testing.T) {
+	service, handler := callMock(t)
+	api := gin.New()
+	api.DELETE(target, handler.Delete())
+
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/api/v1/employees/1", nil))
+	assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+
+	service.EXPECT().Delete(gomock.Any(), int64(1)).Return(etag.ErrMismatch)
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/employees/1", nil)
+	req.Header.Set(etag.IfMatchHeader, `"2"`)
+	resp = httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+}
+
+func TestController_Delete_Dependents(t *testing.T) {
+	service, handler := callMock(t)
+	api := gin.New()
+	api.DELETE(target, handler.Delete())
+
+	service.EXPECT().Delete(gomock.Any(), int64(1)).Return(&dependents.Error{
+		Entity:     "employee",
+		Id:         1,
+		Dependents: []dependents.Dependent{{Type: "inbound_orders", Count: 3}},
+	})
+	req := httptest.NewRequest(
+		http.MethodDelete,
+		"/api/v1/employees/1",
+		nil,
+	)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"inbound_orders"`)
+}
+
 func TestController_Delete_BadRequest(t *testing.T) {
 	_, handler := callMock(t)
 	api := gin.New()
@@ -273,3 +397,53 @@ func TestController_Delete_BadRequest(t *testing.T) {
 	api.ServeHTTP(resp, req)
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
+
+func TestController_ById_IncludeDeleted(t *testing.T) {
+	service, handler := callMock(t)
+	api := gin.New()
+	api.GET(target, handler.GetById())
+	service.EXPECT().GetById(gomock.Any(), int64(1)).DoAndReturn(func(ctx context.Context, id int64) (*domain.Employee, error) {
+		assert.True(t, softdelete.IncludeDeleted(ctx))
+		return &domain.Employee{Id: id}, nil
+	})
+
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/employees/1?include_deleted=true", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = httptest.NewRecorder()
+	api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/employees/1?include_deleted=all", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestController_Restore_Ok(t *testing.T) {
+	emp := &domain.Employee{Id: 1, CardNumberId: "3030", FirstName: "Douglas", LastName: "Mendes", WarehouseId: 3}
+
+	service, handler := callMock(t)
+	api := gin.New()
+	api.POST(restoreTarget, handler.Restore())
+	service.EXPECT().Restore(gomock.Any(), int64(1)).Return(emp, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/employees/1/restore", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	respExpect := struct{ Data domain.Employee }{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &respExpect)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, emp.Id, respExpect.Data.Id)
+}
+
+func TestController_Restore_Nok(t *testing.T) {
+	service, handler := callMock(t)
+	api := gin.New()
+	api.POST(restoreTarget, handler.Restore())
+	service.EXPECT().Restore(gomock.Any(), int64(1)).Return(nil, errors.New("deleted employee 1 not found"))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/employees/1/restore", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -1,13 +1,30 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"fmt"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+)
 
 type Employee struct {
-	Id           int64  `json:"id"`
-	CardNumberId string `json:"card_number_id"`
-	FirstName    string `json:"first_name"`
-	LastName     string `json:"last_name"`
-	WarehouseId  int    `json:"warehouse_id"`
+	Id           int64         `json:"id"`
+	CardNumberId string        `json:"card_number_id"`
+	FirstName    string        `json:"first_name"`
+	LastName     string        `json:"last_name"`
+	WarehouseId  int           `json:"warehouse_id"`
+	DeletedAt    date.DateTime `json:"deleted_at"`
+	Version      int           `json:"version"`
+}
+
+// EmployeePatch holds the fields of a partial update. Nil fields are left
+// untouched.
+type EmployeePatch struct {
+	CardNumberId *string `json:"card_number_id"`
+	FirstName    *string `json:"first_name"`
+	LastName     *string `json:"last_name"`
+	WarehouseId  *int    `json:"warehouse_id"`
 }
 
 //go:generate mockgen -source=./domain.go -destination=./mock/domain_mock.go
@@ -15,14 +32,26 @@ type Repository interface {
 	GetAll(ctx context.Context) ([]Employee, error)
 	GetById(ctx context.Context, id int64) (*Employee, error)
 	Create(ctx context.Context, cardNumberId string, firstName string, lastName string, warehouseId int) (*Employee, error)
-	Update(ctx context.Context, id int64, cardNumberId string, firstName string, lastName string, warehouseId int) (*Employee, error)
+	Update(ctx context.Context, id int64, arg EmployeePatch) (*Employee, error)
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) error
+	Dependents(ctx context.Context, id int64) ([]dependents.Dependent, error)
 }
 
 type Service interface {
 	GetAll(ctx context.Context) ([]Employee, error)
 	GetById(ctx context.Context, id int64) (*Employee, error)
 	Create(ctx context.Context, cardNumberId string, firstName string, lastName string, warehouseId int) (*Employee, error)
-	Update(ctx context.Context, id int64, cardNumberId string, firstName string, lastName string, warehouseId int) (*Employee, error)
+	Update(ctx context.Context, id int64, arg EmployeePatch) (*Employee, error)
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (*Employee, error)
+}
+
+// ErrorNotFound is returned for an employee that does not exist.
+type ErrorNotFound struct {
+	Id int64
+}
+
+func (e *ErrorNotFound) Error() string {
+	return fmt.Sprintf("Employee %d not found", e.Id)
 }
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	domain "github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
+	dependents "github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -64,6 +65,21 @@ func (mr *MockRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id)
 }
 
+// Dependents mocks base method.
+func (m *MockRepository) Dependents(ctx context.Context, id int64) ([]dependents.Dependent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dependents", ctx, id)
+	ret0, _ := ret[0].([]dependents.Dependent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dependents indicates an expected call of Dependents.
+func (mr *MockRepositoryMockRecorder) Dependents(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dependents", reflect.TypeOf((*MockRepository)(nil).Dependents), ctx, id)
+}
+
 // GetAll mocks base method.
 func (m *MockRepository) GetAll(ctx context.Context) ([]domain.Employee, error) {
 	m.ctrl.T.Helper()
@@ -94,19 +110,33 @@ func (mr *MockRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRepository)(nil).GetById), ctx, id)
 }
 
+// Restore mocks base method.
+func (m *MockRepository) Restore(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepository)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockRepository) Update(ctx context.Context, id int64, cardNumberId, firstName, lastName string, warehouseId int) (*domain.Employee, error) {
+func (m *MockRepository) Update(ctx context.Context, id int64, arg domain.EmployeePatch) (*domain.Employee, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, cardNumberId, firstName, lastName, warehouseId)
+	ret := m.ctrl.Call(m, "Update", ctx, id, arg)
 	ret0, _ := ret[0].(*domain.Employee)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(ctx, id, cardNumberId, firstName, lastName, warehouseId interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, id, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, id, cardNumberId, firstName, lastName, warehouseId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, id, arg)
 }
 
 // MockService is a mock of Service interface.
@@ -191,17 +221,32 @@ func (mr *MockServiceMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockService)(nil).GetById), ctx, id)
 }
 
+// Restore mocks base method.
+func (m *MockService) Restore(ctx context.Context, id int64) (*domain.Employee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*domain.Employee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockServiceMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockService) Update(ctx context.Context, id int64, cardNumberId, firstName, lastName string, warehouseId int) (*domain.Employee, error) {
+func (m *MockService) Update(ctx context.Context, id int64, arg domain.EmployeePatch) (*domain.Employee, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, cardNumberId, firstName, lastName, warehouseId)
+	ret := m.ctrl.Call(m, "Update", ctx, id, arg)
 	ret0, _ := ret[0].(*domain.Employee)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockServiceMockRecorder) Update(ctx, id, cardNumberId, firstName, lastName, warehouseId interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Update(ctx, id, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, id, cardNumberId, firstName, lastName, warehouseId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, id, arg)
 }
@@ -1,9 +1,14 @@
 package repository
 
 const (
-	queryGetAll  = "SELECT id, id_card_number, first_name, last_name, warehouse_id FROM employees"
-	queryGetById = "SELECT id, id_card_number, first_name, last_name, warehouse_id FROM employees where id = ?"
-	queryCreate  = "insert into employees (id_card_number , first_name, last_name, warehouse_id) values (?,?,?,?)"
-	queryUpdate  = "UPDATE employees  SET id_card_number  =  ? , first_name= ? , last_name = ? , warehouse_id  = ? WHERE id=?"
-	queryDelete  = "DELETE FROM employees  WHERE id =?"
+	querySelect             = "SELECT id, id_card_number, first_name, last_name, warehouse_id, deleted_at, version FROM employees"
+	queryGetAll             = querySelect + " WHERE deleted_at IS NULL"
+	queryGetAllWithDeleted  = querySelect
+	queryGetById            = querySelect + " where id = ? AND deleted_at IS NULL"
+	queryGetByIdWithDeleted = querySelect + " where id = ?"
+	queryCreate             = "insert into employees (id_card_number , first_name, last_name, warehouse_id) values (?,?,?,?)"
+	queryUpdate             = "UPDATE employees  SET id_card_number  =  ? , first_name= ? , last_name = ? , warehouse_id  = ?, version = version + 1 WHERE id=? AND version = ?"
+	queryDelete             = "UPDATE employees SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL"
+	queryRestore            = "UPDATE employees SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL"
+	queryCountInboundOrders = "SELECT COUNT(*) FROM inbound_orders WHERE employee_id = ?"
 )
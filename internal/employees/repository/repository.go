@@ -5,8 +5,13 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 	"log"
 )
 
@@ -16,7 +21,10 @@ type repository struct {
 
 func (r *repository) GetAll(ctx context.Context) ([]domain.Employee, error) {
 	getAllSql := queryGetAll
-	rows, err := r.db.Query(getAllSql)
+	if softdelete.IncludeDeleted(ctx) {
+		getAllSql = queryGetAllWithDeleted
+	}
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, getAllSql)
 	if err != nil {
 		log.Println("Error while querying customer table" + err.Error())
 		return nil, err
@@ -24,7 +32,7 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Employee, error) {
 	employees := make([]domain.Employee, 0)
 	for rows.Next() {
 		var e domain.Employee
-		err := rows.Scan(&e.Id, &e.CardNumberId, &e.FirstName, &e.LastName, &e.WarehouseId)
+		err := rows.Scan(&e.Id, &e.CardNumberId, &e.FirstName, &e.LastName, &e.WarehouseId, &e.DeletedAt, &e.Version)
 		if err != nil {
 			log.Println("Error while scanning employees " + err.Error())
 			return nil, err
@@ -36,13 +44,17 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Employee, error) {
 }
 
 func (r *repository) GetById(ctx context.Context, id int64) (*domain.Employee, error) {
-	row := r.db.QueryRow(queryGetById, id)
+	getByIdSql := queryGetById
+	if softdelete.IncludeDeleted(ctx) {
+		getByIdSql = queryGetByIdWithDeleted
+	}
+	row := transaction.From(ctx, r.db).QueryRowContext(ctx, getByIdSql, id)
 	var e domain.Employee
-	err := row.Scan(&e.Id, &e.CardNumberId, &e.FirstName, &e.LastName, &e.WarehouseId)
+	err := row.Scan(&e.Id, &e.CardNumberId, &e.FirstName, &e.LastName, &e.WarehouseId, &e.DeletedAt, &e.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
-			return nil, fmt.Errorf("Employee %d not found", id)
+			return nil, &domain.ErrorNotFound{Id: id}
 		} else {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
 			return nil, fmt.Errorf("unexpected database error")
@@ -52,7 +64,7 @@ func (r *repository) GetById(ctx context.Context, id int64) (*domain.Employee, e
 }
 
 func (r *repository) Create(ctx context.Context, cardNumberId string, firstName string, lastName string, warehouseId int) (*domain.Employee, error) {
-	result, err := r.db.Exec(queryCreate, cardNumberId, firstName, lastName, warehouseId)
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryCreate, cardNumberId, firstName, lastName, warehouseId)
 	if err != nil {
 		return nil, err
 	}
@@ -60,32 +72,55 @@ func (r *repository) Create(ctx context.Context, cardNumberId string, firstName
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving id %d ", id)
 	}
-	e := domain.Employee{id, cardNumberId, firstName, lastName, warehouseId}
+	e := domain.Employee{
+		Id:           id,
+		CardNumberId: cardNumberId,
+		FirstName:    firstName,
+		LastName:     lastName,
+		WarehouseId:  warehouseId,
+		Version:      1,
+	}
 	return &e, nil
 
 }
 
-func (r *repository) Update(ctx context.Context, id int64, cardNumberId string, firstName string, lastName string, warehouseId int) (*domain.Employee, error) {
+func (r *repository) Update(ctx context.Context, id int64, arg domain.EmployeePatch) (*domain.Employee, error) {
 
 	emp, err := r.GetById(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("employee %d not found", id)
 	}
-	if cardNumberId != "" {
-		emp.CardNumberId = cardNumberId
+	if err := etag.Check(ctx, emp.Version); err != nil {
+		return nil, err
+	}
+	if arg.CardNumberId != nil {
+		emp.CardNumberId = *arg.CardNumberId
 	}
-	//TODO validar firstName
-	if firstName != "" {
-		emp.FirstName = firstName
+	if arg.FirstName != nil {
+		emp.FirstName = *arg.FirstName
 	}
-	if lastName != "" {
-		emp.LastName = lastName
+	if arg.LastName != nil {
+		emp.LastName = *arg.LastName
 	}
-	if warehouseId != 0 {
-		emp.WarehouseId = warehouseId
+	if arg.WarehouseId != nil {
+		emp.WarehouseId = *arg.WarehouseId
+	}
+
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryUpdate, emp.CardNumberId, emp.FirstName, emp.LastName, emp.WarehouseId, id, emp.Version)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
 	}
 
-	_, err = r.db.Exec(queryUpdate, emp.CardNumberId, emp.FirstName, emp.LastName, emp.WarehouseId, id)
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	// Someone else changed the employee between the read and the write.
+	if affected == 0 {
+		return nil, etag.ErrMismatch
+	}
+	emp.Version++
 
 	return emp, nil
 
@@ -93,11 +128,62 @@ func (r *repository) Update(ctx context.Context, id int64, cardNumberId string,
 
 func (r *repository) Delete(ctx context.Context, id int64) error {
 
-	_, err := r.db.Exec(queryDelete, id)
+	query, args := queryDelete, []interface{}{date.Now(), id}
+	version, versioned := etag.Version(ctx)
+	if versioned {
+		query, args = queryDelete+etag.Condition, append(args, version)
+	}
+
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("error when deleting employee %d", id)
 	}
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// The employee is there, just not in the version the request expects.
+		if versioned {
+			if _, err := r.GetById(ctx, id); err == nil {
+				return etag.ErrMismatch
+			}
+		}
+		logger.Error(ctx, store.GetPathWithLine(), "employee not found")
+		return fmt.Errorf("employee %d not found", id)
+	}
+
+	return nil
+}
+
+// Dependents counts the inbound orders received by the employee.
+func (r *repository) Dependents(ctx context.Context, id int64) ([]dependents.Dependent, error) {
+	var orders int
+	if err := transaction.From(ctx, r.db).QueryRowContext(ctx, queryCountInboundOrders, id).Scan(&orders); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, fmt.Errorf("error when counting inbound orders of employee %d: %w", id, err)
+	}
+
+	return []dependents.Dependent{{Type: "inbound_orders", Count: orders}}, nil
+}
+
+func (r *repository) Restore(ctx context.Context, id int64) error {
+
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryRestore, id)
+	if err != nil {
+		return fmt.Errorf("error when restoring employee %d", id)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		logger.Error(ctx, store.GetPathWithLine(), "deleted employee not found")
+		return fmt.Errorf("deleted employee %d not found", id)
+	}
+
 	return nil
 }
 
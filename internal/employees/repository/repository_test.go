@@ -5,9 +5,14 @@ import (
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/stretchr/testify/assert"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func TestRepository_GetAll_Ok(t *testing.T) {
@@ -17,36 +22,40 @@ func TestRepository_GetAll_Ok(t *testing.T) {
 
 	empList := []domain.Employee{
 		{
-			1,
-			"3030",
-			"Douglas",
-			"Mendes",
-			3,
+			Id:           1,
+			CardNumberId: "3030",
+			FirstName:    "Douglas",
+			LastName:     "Mendes",
+			WarehouseId:  3,
 		},
 		{
-			2,
-			"40",
-			"Gustavo",
-			"Naganuma",
-			33,
+			Id:           2,
+			CardNumberId: "40",
+			FirstName:    "Gustavo",
+			LastName:     "Naganuma",
+			WarehouseId:  33,
 		},
 	}
 	rows := sqlmock.NewRows([]string{
-		"id", "card_number_id", "first_name", "last_name", "warehouse_id",
+		"id", "card_number_id", "first_name", "last_name", "warehouse_id", "deleted_at", "version",
 	}).AddRow(
 		empList[0].Id,
 		empList[0].CardNumberId,
 		empList[0].FirstName,
 		empList[0].LastName,
 		empList[0].WarehouseId,
+		nil,
+		1,
 	).AddRow(
 		empList[1].Id,
 		empList[1].CardNumberId,
 		empList[1].FirstName,
 		empList[1].LastName,
 		empList[0].WarehouseId,
+		nil,
+		1,
 	)
-	mock.ExpectQuery(queryGetAll).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAll)).WillReturnRows(rows)
 
 	empRepo := NewRepository(db)
 
@@ -62,7 +71,7 @@ func TestRepository_GetAll_NOk(t *testing.T) {
 
 	empList := []domain.Employee(nil)
 
-	mock.ExpectQuery(queryGetAll).WillReturnError(errors.New("erro"))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAll)).WillReturnError(errors.New("erro"))
 
 	empRepo := NewRepository(db)
 
@@ -78,23 +87,25 @@ func TestRepositoru_GetById_Ok(t *testing.T) {
 	defer db.Close()
 
 	emp := domain.Employee{
-		44,
-		"3030",
-		"Douglas",
-		"Mendes",
-		3,
+		Id:           44,
+		CardNumberId: "3030",
+		FirstName:    "Douglas",
+		LastName:     "Mendes",
+		WarehouseId:  3,
 	}
 
 	row := sqlmock.NewRows([]string{
-		"id", "card_number_id", "first_name", "last_name", "warehouse_id",
+		"id", "card_number_id", "first_name", "last_name", "warehouse_id", "deleted_at", "version",
 	}).AddRow(
 		emp.Id,
 		emp.CardNumberId,
 		emp.FirstName,
 		emp.LastName,
 		emp.WarehouseId,
+		nil,
+		1,
 	)
-	mock.ExpectQuery(queryGetById).WillReturnRows(row)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(row)
 
 	empRepo := NewRepository(db)
 
@@ -108,7 +119,7 @@ func TestRepository_GetById_NOk(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(queryGetById).WillReturnError(errors.New("error"))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnError(errors.New("error"))
 
 	empRepo := NewRepository(db)
 	var e *domain.Employee
@@ -123,11 +134,11 @@ func TestRepository_Create_Ok(t *testing.T) {
 	defer db.Close()
 
 	emp := domain.Employee{
-		1,
-		"3030",
-		"Douglas",
-		"Mendes",
-		3,
+		Id:           1,
+		CardNumberId: "3030",
+		FirstName:    "Douglas",
+		LastName:     "Mendes",
+		WarehouseId:  3,
 	}
 
 	mock.ExpectExec(regexp.QuoteMeta(queryCreate)).WithArgs(
@@ -166,47 +177,75 @@ func TestRepository_Update_Ok(t *testing.T) {
 	defer db.Close()
 
 	emp := domain.Employee{
-		1,
-		"3030",
-		"Douglas",
-		"Mendes",
-		3,
+		Id:           1,
+		CardNumberId: "3030",
+		FirstName:    "Douglas",
+		LastName:     "Mendes",
+		WarehouseId:  3,
+		Version:      1,
 	}
 
 	empUpdate := domain.Employee{
-		1,
-		"3030",
-		"Douglas",
-		"Leonardo",
-		3,
+		Id:           1,
+		CardNumberId: "3030",
+		FirstName:    "Douglas",
+		LastName:     "Leonardo",
+		WarehouseId:  3,
+		Version:      2,
 	}
 
 	row := sqlmock.NewRows([]string{
-		"id", "card_number_id", "first_name", "last_name", "warehouse_id",
+		"id", "card_number_id", "first_name", "last_name", "warehouse_id", "deleted_at", "version",
 	}).AddRow(
 		emp.Id,
 		emp.CardNumberId,
 		emp.FirstName,
 		emp.LastName,
 		emp.WarehouseId,
+		nil,
+		1,
 	)
-	mock.ExpectQuery(queryGetById).WillReturnRows(row)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(row)
 
 	mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).WithArgs(
-		empUpdate.Id,
 		empUpdate.CardNumberId,
 		empUpdate.FirstName,
 		empUpdate.LastName,
 		empUpdate.WarehouseId,
 		1,
+		1,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	empRepo := NewRepository(db)
 
-	result, err := empRepo.Update(context.TODO(), 1, "3030", "Douglas", "Leonardo", 3)
+	result, err := empRepo.Update(context.TODO(), 1, domain.EmployeePatch{LastName: &empUpdate.LastName})
 	assert.NoError(t, err)
 	assert.Equal(t, &empUpdate, result)
 }
+func TestRepository_Update_Version_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "card_number_id", "first_name", "last_name", "warehouse_id", "deleted_at", "version"}
+	lastName := "Leonardo"
+	empRepo := NewRepository(db)
+
+	// The client read an older version.
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "3030", "Douglas", "Mendes", 3, nil, 3))
+
+	_, err = empRepo.Update(etag.WithVersion(context.TODO(), 2), 1, domain.EmployeePatch{LastName: &lastName})
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+
+	// Someone else wrote the employee between the read and the write.
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "3030", "Douglas", "Mendes", 3, nil, 3))
+	mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = empRepo.Update(etag.WithVersion(context.TODO(), 3), 1, domain.EmployeePatch{LastName: &lastName})
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRepository_Update_NOk(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -216,7 +255,8 @@ func TestRepository_Update_NOk(t *testing.T) {
 
 	empRepo := NewRepository(db)
 
-	_, err = empRepo.Update(context.TODO(), 1, "3030", "Douglas", "Leonardo", 3)
+	lastName := "Leonardo"
+	_, err = empRepo.Update(context.TODO(), 1, domain.EmployeePatch{LastName: &lastName})
 	assert.Error(t, err)
 }
 
@@ -226,6 +266,7 @@ func TestRepository_Delete_Ok(t *testing.T) {
 	defer db.Close()
 
 	mock.ExpectExec(regexp.QuoteMeta(queryDelete)).WithArgs(
+		sqlmock.AnyArg(),
 		1,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
 
@@ -247,3 +288,90 @@ func TestRepositoru_Delete_NOk(t *testing.T) {
 	err = empRepo.Delete(context.TODO(), 1)
 	assert.Error(t, err)
 }
+
+func TestRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryDelete)).
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	empRepo := NewRepository(db)
+
+	err = empRepo.Delete(context.TODO(), 1)
+	assert.EqualError(t, err, "employee 1 not found")
+}
+
+func TestRepository_Delete_Version_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryDelete+etag.Condition)).
+		WithArgs(sqlmock.AnyArg(), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "card_number_id", "first_name", "last_name", "warehouse_id", "deleted_at", "version"}).AddRow(1, "3030", "Douglas", "Mendes", 3, nil, 3))
+
+	empRepo := NewRepository(db)
+
+	err = empRepo.Delete(etag.WithVersion(context.TODO(), 2), 1)
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+}
+
+func TestRepository_Dependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryCountInboundOrders)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(queryCountInboundOrders)).
+		WithArgs(2).
+		WillReturnError(errors.New("connection refused"))
+
+	empRepo := NewRepository(db)
+
+	found, err := empRepo.Dependents(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []dependents.Dependent{{Type: "inbound_orders", Count: 3}}, found)
+
+	_, err = empRepo.Dependents(context.TODO(), 2)
+	assert.EqualError(t, err, "error when counting inbound orders of employee 2: connection refused")
+}
+
+func TestRepository_GetAll_IncludeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	deletedAt := date.NewDateTime(time.Date(2022, 7, 9, 10, 0, 0, 0, time.UTC))
+
+	rows := sqlmock.NewRows([]string{
+		"id", "card_number_id", "first_name", "last_name", "warehouse_id", "deleted_at", "version",
+	}).AddRow(1, "3030", "Douglas", "Mendes", 3, deletedAt.Time, 2)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAllWithDeleted) + "$").WillReturnRows(rows)
+
+	empRepo := NewRepository(db)
+
+	result, err := empRepo.GetAll(softdelete.WithDeleted(context.TODO()))
+	assert.NoError(t, err)
+	assert.Equal(t, deletedAt, result[0].DeletedAt)
+}
+
+func TestRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryRestore)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryRestore)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	empRepo := NewRepository(db)
+
+	assert.NoError(t, empRepo.Restore(context.TODO(), 1))
+	assert.EqualError(t, empRepo.Restore(context.TODO(), 2), "deleted employee 2 not found")
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+)
+
+type auditedService struct {
+	domain.Service
+	audit auditDomain.Service
+}
+
+// NewAuditedService records every change made through s in the audit trail.
+func NewAuditedService(s domain.Service, audit auditDomain.Service) domain.Service {
+	return &auditedService{s, audit}
+}
+
+func (s *auditedService) Create(ctx context.Context, cardNumberId string, firstName string, lastName string, warehouseId int) (*domain.Employee, error) {
+	employee, err := s.Service.Create(ctx, cardNumberId, firstName, lastName, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityEmployees, int(employee.Id), auditDomain.ActionCreate, nil, employee)
+
+	return employee, nil
+}
+
+func (s *auditedService) Update(ctx context.Context, id int64, arg domain.EmployeePatch) (*domain.Employee, error) {
+	before, _ := s.Service.GetById(ctx, id)
+
+	employee, err := s.Service.Update(ctx, id, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityEmployees, int(id), auditDomain.ActionUpdate, before, employee)
+
+	return employee, nil
+}
+
+func (s *auditedService) Delete(ctx context.Context, id int64) error {
+	before, _ := s.Service.GetById(ctx, id)
+
+	if err := s.Service.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityEmployees, int(id), auditDomain.ActionDelete, before, nil)
+
+	return nil
+}
+
+func (s *auditedService) Restore(ctx context.Context, id int64) (*domain.Employee, error) {
+	before, _ := s.Service.GetById(softdelete.WithDeleted(ctx), id)
+
+	employee, err := s.Service.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityEmployees, int(id), auditDomain.ActionRestore, before, employee)
+
+	return employee, nil
+}
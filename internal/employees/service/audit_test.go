@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	mock_audit "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditedService(t *testing.T) {
+	before := &domain.Employee{Id: 1, CardNumberId: "402323", FirstName: "Jhon"}
+	after := &domain.Employee{Id: 1, CardNumberId: "402323", FirstName: "John"}
+
+	t.Run("Create records the new employee", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().Create(gomock.Any(), "402323", "Jhon", "Doe", 1).Return(before, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityEmployees, 1, auditDomain.ActionCreate, nil, before)
+
+		_, err := NewAuditedService(service, audit).Create(context.TODO(), "402323", "Jhon", "Doe", 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Update records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		name := "John"
+		patch := domain.EmployeePatch{FirstName: &name}
+		service.EXPECT().GetById(gomock.Any(), int64(1)).Return(before, nil)
+		service.EXPECT().Update(gomock.Any(), int64(1), patch).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityEmployees, 1, auditDomain.ActionUpdate, before, after)
+
+		_, err := NewAuditedService(service, audit).Update(context.TODO(), 1, patch)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete records the removed employee", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), int64(1)).Return(before, nil)
+		service.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityEmployees, 1, auditDomain.ActionDelete, before, nil)
+
+		assert.NoError(t, NewAuditedService(service, audit).Delete(context.TODO(), 1))
+	})
+
+	t.Run("Restore records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), int64(1)).Return(before, nil)
+		service.EXPECT().Restore(gomock.Any(), int64(1)).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityEmployees, 1, auditDomain.ActionRestore, before, after)
+
+		_, err := NewAuditedService(service, audit).Restore(context.TODO(), 1)
+		assert.NoError(t, err)
+	})
+}
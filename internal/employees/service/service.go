@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"log"
 )
 
@@ -39,7 +41,8 @@ func (s service) GetById(ctx context.Context, id int64) (*domain.Employee, error
 }
 
 func (s service) Create(ctx context.Context, cardNumberId string, firstName string, lastName string, warehouseId int) (*domain.Employee, error) {
-	emp, err := s.repository.GetAll(ctx)
+	// Deleted employees keep their card number until they are purged.
+	emp, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
 
 	if err != nil {
 		return nil, err
@@ -66,19 +69,21 @@ func (s service) Create(ctx context.Context, cardNumberId string, firstName stri
 	return employee, nil
 }
 
-func (s service) Update(ctx context.Context, id int64, cardNumberId string, firstName string, lastName string, warehouseId int) (*domain.Employee, error) {
-	emp, err := s.repository.GetAll(ctx)
+func (s service) Update(ctx context.Context, id int64, arg domain.EmployeePatch) (*domain.Employee, error) {
+	if arg.CardNumberId != nil {
+		emp, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
 
-	if err != nil {
-		return nil, err
-	}
+		if err != nil {
+			return nil, err
+		}
 
-	for e := range emp {
-		if emp[e].CardNumberId == cardNumberId {
-			return nil, fmt.Errorf("this employee already exists")
+		for e := range emp {
+			if emp[e].Id != id && emp[e].CardNumberId == *arg.CardNumberId {
+				return nil, fmt.Errorf("this employee already exists")
+			}
 		}
 	}
-	employee, err := s.repository.Update(ctx, id, cardNumberId, firstName, lastName, warehouseId)
+	employee, err := s.repository.Update(ctx, id, arg)
 	if err != nil {
 		return nil, err
 	}
@@ -86,9 +91,27 @@ func (s service) Update(ctx context.Context, id int64, cardNumberId string, firs
 }
 
 func (s service) Delete(ctx context.Context, id int64) error {
-	err := s.repository.Delete(ctx, id)
+	// Inbound orders are kept as the history of the warehouse stock, so they
+	// are never cascaded and always block the delete.
+	found, err := s.repository.Dependents(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := dependents.Check("employee", int(id), found...); err != nil {
+		return err
+	}
+
+	err = s.repository.Delete(ctx, id)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (s service) Restore(ctx context.Context, id int64) (*domain.Employee, error) {
+	if err := s.repository.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repository.GetById(ctx, id)
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -22,18 +24,18 @@ func callMock(t *testing.T) (*mock_domain.MockRepository, domain.Service) {
 func TestService_Create_Ok(t *testing.T) {
 	empList := []domain.Employee{
 		{
-			1,
-			"3030",
-			"Douglas",
-			"Mendes",
-			3,
+			Id:           1,
+			CardNumberId: "3030",
+			FirstName:    "Douglas",
+			LastName:     "Mendes",
+			WarehouseId:  3,
 		},
 		{
-			2,
-			"40",
-			"Gustavo",
-			"Naganuma",
-			33,
+			Id:           2,
+			CardNumberId: "40",
+			FirstName:    "Gustavo",
+			LastName:     "Naganuma",
+			WarehouseId:  33,
 		},
 	}
 
@@ -46,7 +48,7 @@ func TestService_Create_Ok(t *testing.T) {
 	}
 	apiMock, service := callMock(t)
 	//repository
-	apiMock.EXPECT().GetAll(context.TODO()).Return(empList, nil)
+	apiMock.EXPECT().GetAll(softdelete.WithDeleted(context.TODO())).Return(empList, nil)
 	apiMock.EXPECT().Create(context.TODO(), "5050", "Renata", "Leal", 3).Return(emp, nil)
 	//service
 	result, err := service.Create(context.TODO(), "5050", "Renata", "Leal", 3)
@@ -59,25 +61,25 @@ func TestService_Create_Ok(t *testing.T) {
 func TestService_Create_Nok(t *testing.T) {
 	empList := []domain.Employee{
 		{
-			1,
-			"3030",
-			"Douglas",
-			"Mendes",
-			3,
+			Id:           1,
+			CardNumberId: "3030",
+			FirstName:    "Douglas",
+			LastName:     "Mendes",
+			WarehouseId:  3,
 		},
 		{
-			2,
-			"40",
-			"Gustavo",
-			"Naganuma",
-			33,
+			Id:           2,
+			CardNumberId: "40",
+			FirstName:    "Gustavo",
+			LastName:     "Naganuma",
+			WarehouseId:  33,
 		},
 	}
 
 	apiMock, service := callMock(t)
 	//repository
 	//apiMock.EXPECT().LastID().Return(2, nil)
-	apiMock.EXPECT().GetAll(context.TODO()).Return(empList, nil)
+	apiMock.EXPECT().GetAll(softdelete.WithDeleted(context.TODO())).Return(empList, nil)
 	apiMock.EXPECT().Create(context.TODO(), "3030", "Renata", "Leal", 3).Return(nil, errors.New("this card number id already exists"))
 	//service
 	_, err := service.Create(context.TODO(), "3030", "Renata", "Leal", 3)
@@ -89,18 +91,18 @@ func TestService_Create_Nok(t *testing.T) {
 func TestService_GetAll(t *testing.T) {
 	emp := []domain.Employee{
 		{
-			1,
-			"3030",
-			"Douglas",
-			"Mendes",
-			3,
+			Id:           1,
+			CardNumberId: "3030",
+			FirstName:    "Douglas",
+			LastName:     "Mendes",
+			WarehouseId:  3,
 		},
 		{
-			2,
-			"40",
-			"Gustavo",
-			"Naganuma",
-			33,
+			Id:           2,
+			CardNumberId: "40",
+			FirstName:    "Gustavo",
+			LastName:     "Naganuma",
+			WarehouseId:  33,
 		},
 	}
 	//repository
@@ -143,6 +145,7 @@ func TestService_GetById_Ok(t *testing.T) {
 //DELETE - delete_non_existent - Quando o funcionário não existir, será retornado null.
 func TestService_Delete_Ok(t *testing.T) {
 	apiMock, service := callMock(t)
+	apiMock.EXPECT().Dependents(context.TODO(), int64(1)).Return([]dependents.Dependent{{Type: "inbound_orders"}}, nil)
 	apiMock.EXPECT().Delete(context.TODO(), int64(1)).Return(nil)
 	err := service.Delete(context.TODO(), int64(1))
 	assert.Nil(t, err)
@@ -151,11 +154,55 @@ func TestService_Delete_Ok(t *testing.T) {
 //DELETE delete_ok Se a exclusão for bem-sucedida, o item não aparecerá na lista.
 func TestService_Delete_Nok(t *testing.T) {
 	apiMock, service := callMock(t)
+	apiMock.EXPECT().Dependents(context.TODO(), int64(1)).Return([]dependents.Dependent{{Type: "inbound_orders"}}, nil)
 	apiMock.EXPECT().Delete(context.TODO(), int64(1)).Return(errors.New("employee 1 not found"))
 	err := service.Delete(context.TODO(), int64(1))
 	assert.NotNil(t, err)
 }
 
+func TestService_Delete_Dependents(t *testing.T) {
+	apiMock, service := callMock(t)
+	// Inbound orders block the delete even when it cascades.
+	apiMock.EXPECT().Dependents(gomock.Any(), int64(1)).Times(2).Return([]dependents.Dependent{{Type: "inbound_orders", Count: 3}}, nil)
+	apiMock.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.Delete(context.TODO(), int64(1))
+	assert.EqualError(t, err, "employee 1 cannot be deleted, it still has 3 inbound_orders")
+
+	err = service.Delete(dependents.WithCascade(context.TODO()), int64(1))
+	var dependentsErr *dependents.Error
+	assert.ErrorAs(t, err, &dependentsErr)
+}
+
+func TestService_Delete_Dependents_Fail(t *testing.T) {
+	apiMock, service := callMock(t)
+	apiMock.EXPECT().Dependents(context.TODO(), int64(1)).Return(nil, errors.New("error when counting inbound orders of employee 1"))
+	apiMock.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.Delete(context.TODO(), int64(1))
+	assert.NotNil(t, err)
+}
+
+func TestService_Restore_Ok(t *testing.T) {
+	emp := &domain.Employee{Id: 1, CardNumberId: "3030", FirstName: "Douglas", LastName: "Mendes", WarehouseId: 3}
+
+	apiMock, service := callMock(t)
+	apiMock.EXPECT().Restore(context.TODO(), int64(1)).Return(nil)
+	apiMock.EXPECT().GetById(context.TODO(), int64(1)).Return(emp, nil)
+
+	result, err := service.Restore(context.TODO(), int64(1))
+	assert.Nil(t, err)
+	assert.Equal(t, emp, result)
+}
+
+func TestService_Restore_Nok(t *testing.T) {
+	apiMock, service := callMock(t)
+	apiMock.EXPECT().Restore(context.TODO(), int64(1)).Return(errors.New("deleted employee 1 not found"))
+
+	_, err := service.Restore(context.TODO(), int64(1))
+	assert.NotNil(t, err)
+}
+
 //UPDATE update_existent Quando a atualização dos dados for bem-sucedida, o
 //funcionário será devolvido com as informações atualizadas
 func TestService_Update_Ok(t *testing.T) {
@@ -169,26 +216,26 @@ func TestService_Update_Ok(t *testing.T) {
 	}
 	empList := []domain.Employee{
 		{
-			1,
-			"3030",
-			"Douglas",
-			"Mendes",
-			3,
+			Id:           1,
+			CardNumberId: "3030",
+			FirstName:    "Douglas",
+			LastName:     "Mendes",
+			WarehouseId:  3,
 		},
 		{
-			2,
-			"40",
-			"Gustavo",
-			"Naganuma",
-			33,
+			Id:           2,
+			CardNumberId: "40",
+			FirstName:    "Gustavo",
+			LastName:     "Naganuma",
+			WarehouseId:  33,
 		},
 	}
 	apiMock, service := callMock(t)
 	//repository
-	apiMock.EXPECT().GetAll(context.TODO()).Return(empList, nil)
-	apiMock.EXPECT().Update(context.TODO(), int64(1), "5050", "Douglas", "Mendes", 3).Return(emp, nil)
+	apiMock.EXPECT().GetAll(softdelete.WithDeleted(context.TODO())).Return(empList, nil)
+	apiMock.EXPECT().Update(context.TODO(), int64(1), employeePatch).Return(emp, nil)
 	//service
-	result, err := service.Update(context.TODO(), 1, "5050", "Douglas", "Mendes", 3)
+	result, err := service.Update(context.TODO(), 1, employeePatch)
 	assert.Nil(t, err)
 	assert.Equal(t, result, emp)
 
@@ -200,27 +247,42 @@ func TestService_Update_Nok(t *testing.T) {
 	//emp := domain.Employee{}
 	empList := []domain.Employee{
 		{
-			1,
-			"3030",
-			"Douglas",
-			"Mendes",
-			3,
+			Id:           1,
+			CardNumberId: "3030",
+			FirstName:    "Douglas",
+			LastName:     "Mendes",
+			WarehouseId:  3,
 		},
 		{
-			2,
-			"40",
-			"Gustavo",
-			"Naganuma",
-			33,
+			Id:           2,
+			CardNumberId: "40",
+			FirstName:    "Gustavo",
+			LastName:     "Naganuma",
+			WarehouseId:  33,
 		},
 	}
 	apiMock, service := callMock(t)
 	//repository
-	apiMock.EXPECT().GetAll(context.TODO()).Return(empList, nil)
-	apiMock.EXPECT().Update(context.TODO(), int64(50), "5050", "Douglas", "Mendes", 3).Return(nil, errors.New("employee 60 not found"))
+	apiMock.EXPECT().GetAll(softdelete.WithDeleted(context.TODO())).Return(empList, nil)
+	apiMock.EXPECT().Update(context.TODO(), int64(50), employeePatch).Return(nil, errors.New("employee 60 not found"))
 	//service
-	_, err := service.Update(context.TODO(), int64(50), "5050", "Douglas", "Mendes", 3)
+	_, err := service.Update(context.TODO(), int64(50), employeePatch)
 	assert.NotNil(t, err)
 	//assert.Equal(t, result, emp)
 
 }
+
+var employeePatch = domain.EmployeePatch{
+	CardNumberId: stringPtr("5050"),
+	FirstName:    stringPtr("Douglas"),
+	LastName:     stringPtr("Mendes"),
+	WarehouseId:  intPtr(3),
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
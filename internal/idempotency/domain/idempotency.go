@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+)
+
+const (
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set on responses that were stored by an earlier
+	// request with the same key.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	// MaxKeyLength is the size of the key column.
+	MaxKeyLength = 255
+
+	// DefaultTTL is how long a response is kept for replays when
+	// IDEMPOTENCY_TTL is not set.
+	DefaultTTL = 24 * time.Hour
+)
+
+var (
+	ErrNotFound   = errors.New("idempotency key not found")
+	ErrDuplicate  = errors.New("idempotency key already exists")
+	ErrInProgress = errors.New("a request with this Idempotency-Key is still being processed")
+	ErrKeyReused  = errors.New("the Idempotency-Key was already used for a different request")
+	ErrKeyTooLong = errors.New("the Idempotency-Key must have at most 255 characters")
+)
+
+// Record is the outcome of the first request made with a key. Keys belong to
+// the caller that sent them, so two clients never share a response. Status is
+// zero while that first request is still running.
+type Record struct {
+	Owner       string        `json:"owner"`
+	Key         string        `json:"key"`
+	Fingerprint string        `json:"fingerprint"`
+	Status      int           `json:"status"`
+	Body        []byte        `json:"body"`
+	CreatedAt   date.DateTime `json:"created_at"`
+	ExpiresAt   date.DateTime `json:"expires_at"`
+}
+
+func (r Record) Completed() bool {
+	return r.Status != 0
+}
+
+//go:generate mockgen -source=./idempotency.go -destination=./mock/idempotency_mock.go
+type Repository interface {
+	Get(ctx context.Context, owner, key string) (Record, error)
+	// Create fails with ErrDuplicate when the owner already used the key.
+	Create(ctx context.Context, record Record) error
+	Complete(ctx context.Context, owner, key string, status int, body []byte) error
+	Delete(ctx context.Context, owner, key string) error
+	DeleteExpired(ctx context.Context, now date.DateTime) error
+}
+
+type Service interface {
+	// Start claims the key for a request. It returns the stored record when
+	// the request must be answered with an earlier response, and nil when
+	// the request should run.
+	Start(ctx context.Context, owner, key, fingerprint string) (*Record, error)
+	// Finish stores the response of a request started with Start.
+	Finish(ctx context.Context, owner, key string, status int, body []byte) error
+}
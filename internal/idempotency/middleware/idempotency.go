@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// anonymousOwner owns the keys of requests that reach the middleware without
+// an authenticated caller.
+const anonymousOwner = "anonymous"
+
+// Idempotent makes retries of a request safe. The first request sent with an
+// Idempotency-Key header runs as usual and its response is stored; later
+// requests with the same key and body get that response back without running
+// the handler again. Requests without the header are not affected.
+func Idempotent(service domain.Service) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(domain.Header)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+
+		if len(key) > domain.MaxKeyLength {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, response.DecodeError(domain.ErrKeyTooLong.Error()))
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		owner := owner(ctx)
+
+		record, err := service.Start(ctx, owner, key, fingerprint(ctx.Request, body))
+		if err != nil {
+			ctx.AbortWithStatusJSON(status(err), response.DecodeError(err.Error()))
+			return
+		}
+
+		if record != nil {
+			ctx.Header(domain.ReplayedHeader, "true")
+			ctx.Data(record.Status, gin.MIMEJSON+"; charset=utf-8", record.Body)
+			ctx.Abort()
+			return
+		}
+
+		writer := &recorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+
+		// The key is finished even when a handler panics, as a server error
+		// that releases it. Left unfinished, it would stay in progress until
+		// it expires and every retry would be answered with a 409.
+		defer func() {
+			recovered := recover()
+
+			status := writer.Status()
+			if recovered != nil {
+				status = http.StatusInternalServerError
+			}
+
+			if err := service.Finish(ctx, owner, key, status, writer.body.Bytes()); err != nil {
+				// The response is already on its way, a retry will just run again.
+				logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			}
+
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+
+		ctx.Next()
+	}
+}
+
+func owner(ctx *gin.Context) string {
+	if principal, ok := authDomain.PrincipalFrom(ctx); ok {
+		return principal.Subject
+	}
+	return anonymousOwner
+}
+
+// fingerprint identifies the request a key was first used for, so the same
+// key cannot replay a response to a different request.
+func fingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func status(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInProgress):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrKeyReused):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// recorder keeps a copy of the response body while it is written.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *recorder) WriteString(data string) (int, error) {
+	r.body.WriteString(data)
+	return r.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authDomain "github.com/douglmendes/mercado-fresco-round-go/internal/auth/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/ctxkey"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const payload = `{"order_number":"xpto"}`
+
+func newRouter(service domain.Service, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/orders", func(ctx *gin.Context) {
+		ctxkey.Set(ctx, ctxkey.Principal, authDomain.Principal{Subject: "user-1"})
+	}, Idempotent(service), func(ctx *gin.Context) {
+		*calls++
+
+		var body map[string]interface{}
+		ctx.ShouldBindJSON(&body)
+		ctx.JSON(http.StatusCreated, response.NewResponse(body))
+	})
+
+	return router
+}
+
+func doRequest(router *gin.Engine, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(payload))
+	if key != "" {
+		req.Header.Set(domain.Header, key)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestIdempotent_WithoutKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+
+	calls := 0
+	rr := doRequest(newRouter(service, &calls), "")
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotent_FirstRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+
+	expected := fingerprint(httptest.NewRequest(http.MethodPost, "/orders", nil), []byte(payload))
+	service.EXPECT().Start(gomock.Any(), "user-1", "key-1", expected).Return(nil, nil)
+	service.EXPECT().Finish(gomock.Any(), "user-1", "key-1", http.StatusCreated, gomock.Any()).
+		DoAndReturn(func(_ interface{}, _, _ string, _ int, body []byte) error {
+			assert.JSONEq(t, `{"data":{"order_number":"xpto"}}`, string(body))
+			return nil
+		})
+
+	calls := 0
+	rr := doRequest(newRouter(service, &calls), "key-1")
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, rr.Header().Get(domain.ReplayedHeader))
+}
+
+func TestIdempotent_Replay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+
+	stored := &domain.Record{Status: http.StatusCreated, Body: []byte(`{"data":{"id":7}}`)}
+	service.EXPECT().Start(gomock.Any(), "user-1", "key-1", gomock.Any()).Return(stored, nil)
+
+	calls := 0
+	rr := doRequest(newRouter(service, &calls), "key-1")
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, "true", rr.Header().Get(domain.ReplayedHeader))
+	assert.JSONEq(t, `{"data":{"id":7}}`, rr.Body.String())
+}
+
+func TestIdempotent_Errors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"request still running", domain.ErrInProgress, http.StatusConflict},
+		{"key reused", domain.ErrKeyReused, http.StatusUnprocessableEntity},
+		{"repository failure", assert.AnError, http.StatusInternalServerError},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			service := mock_domain.NewMockService(ctrl)
+			service.EXPECT().Start(gomock.Any(), "user-1", "key-1", gomock.Any()).Return(nil, testCase.err)
+
+			calls := 0
+			rr := doRequest(newRouter(service, &calls), "key-1")
+
+			assert.Equal(t, testCase.status, rr.Code)
+			assert.Equal(t, 0, calls)
+
+			var body response.Response
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+			assert.Equal(t, testCase.err.Error(), body.Error)
+		})
+	}
+}
+
+func TestIdempotent_KeyTooLong(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockService(ctrl)
+
+	calls := 0
+	rr := doRequest(newRouter(service, &calls), strings.Repeat("k", domain.MaxKeyLength+1))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestIdempotent_Panic(t *testing.T) {
+	newPanickingRouter := func(service domain.Service, middlewares ...gin.HandlerFunc) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+
+		router := gin.New()
+		router.Use(middlewares...)
+		router.POST("/orders", Idempotent(service), func(ctx *gin.Context) {
+			panic("nil pointer dereference")
+		})
+
+		return router
+	}
+
+	t.Run("should release the key and panic again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+
+		service.EXPECT().Start(gomock.Any(), anonymousOwner, "key-1", gomock.Any()).Return(nil, nil)
+		service.EXPECT().Finish(gomock.Any(), anonymousOwner, "key-1", http.StatusInternalServerError, gomock.Any()).Return(nil)
+
+		router := newPanickingRouter(service)
+
+		assert.PanicsWithValue(t, "nil pointer dereference", func() {
+			doRequest(router, "key-1")
+		})
+	})
+
+	t.Run("should answer with the recovery of the server", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+
+		service.EXPECT().Start(gomock.Any(), anonymousOwner, "key-1", gomock.Any()).Return(nil, nil)
+		service.EXPECT().Finish(gomock.Any(), anonymousOwner, "key-1", http.StatusInternalServerError, gomock.Any()).Return(nil)
+
+		rr := doRequest(newPanickingRouter(service, gin.CustomRecovery(func(ctx *gin.Context, err interface{}) {
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+		})), "key-1")
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/go-sql-driver/mysql"
+)
+
+// errDuplicateEntry is the MySQL error number of a unique key violation.
+const errDuplicateEntry = 1062
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domain.Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r repository) Get(ctx context.Context, owner, key string) (domain.Record, error) {
+	var (
+		record domain.Record
+		body   []byte
+	)
+
+	err := r.db.QueryRowContext(ctx, sqlGet, owner, key).Scan(
+		&record.Owner,
+		&record.Key,
+		&record.Fingerprint,
+		&record.Status,
+		&body,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Record{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.Record{}, err
+	}
+
+	record.Body = body
+
+	return record, nil
+}
+
+func (r repository) Create(ctx context.Context, record domain.Record) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		sqlCreate,
+		record.Owner,
+		record.Key,
+		record.Fingerprint,
+		record.CreatedAt,
+		record.ExpiresAt,
+	)
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+		return domain.ErrDuplicate
+	}
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r repository) Complete(ctx context.Context, owner, key string, status int, body []byte) error {
+	if _, err := r.db.ExecContext(ctx, sqlComplete, status, body, owner, key); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r repository) Delete(ctx context.Context, owner, key string) error {
+	if _, err := r.db.ExecContext(ctx, sqlDelete, owner, key); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r repository) DeleteExpired(ctx context.Context, now date.DateTime) error {
+	if _, err := r.db.ExecContext(ctx, sqlDeleteExpired, now); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	return nil
+}
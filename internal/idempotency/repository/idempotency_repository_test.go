@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	createdAt = date.NewDateTime(time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC))
+	expiresAt = date.NewDateTime(time.Date(2022, 8, 2, 10, 0, 0, 0, time.UTC))
+)
+
+func TestRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"owner", "idempotency_key", "fingerprint", "status", "body", "created_at", "expires_at"}).
+		AddRow("user-1", "key-1", "abc", 201, []byte(`{"data":{"id":1}}`), "2022-08-01 10:00:00", "2022-08-02 10:00:00")
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGet)).WithArgs("user-1", "key-1").WillReturnRows(rows)
+
+	record, err := NewRepository(db).Get(context.TODO(), "user-1", "key-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 201, record.Status)
+	assert.JSONEq(t, `{"data":{"id":1}}`, string(record.Body))
+	assert.True(t, record.ExpiresAt.Equal(expiresAt.Time))
+}
+
+func TestRepository_Get_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGet)).WithArgs("user-1", "key-1").
+		WillReturnRows(sqlmock.NewRows([]string{"owner"}))
+
+	_, err = NewRepository(db).Get(context.TODO(), "user-1", "key-1")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	record := domain.Record{Owner: "user-1", Key: "key-1", Fingerprint: "abc", CreatedAt: createdAt, ExpiresAt: expiresAt}
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlCreate)).
+		WithArgs("user-1", "key-1", "abc", "2022-08-01 10:00:00", "2022-08-02 10:00:00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, NewRepository(db).Create(context.TODO(), record))
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlCreate)).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+	assert.ErrorIs(t, NewRepository(db).Create(context.TODO(), record), domain.ErrDuplicate)
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlCreate)).WillReturnError(errors.New("connection refused"))
+	assert.EqualError(t, NewRepository(db).Create(context.TODO(), record), "connection refused")
+}
+
+func TestRepository_Complete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlComplete)).
+		WithArgs(201, []byte(`{}`), "user-1", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, NewRepository(db).Complete(context.TODO(), "user-1", "key-1", 201, []byte(`{}`)))
+}
+
+func TestRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlDelete)).WithArgs("user-1", "key-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(sqlDeleteExpired)).WithArgs("2022-08-01 10:00:00").WillReturnResult(sqlmock.NewResult(0, 3))
+
+	assert.NoError(t, NewRepository(db).Delete(context.TODO(), "user-1", "key-1"))
+	assert.NoError(t, NewRepository(db).DeleteExpired(context.TODO(), createdAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,25 @@
+package repository
+
+const (
+	sqlGet = `
+		SELECT owner, idempotency_key, fingerprint, status, body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE owner = ? AND idempotency_key = ?`
+
+	sqlCreate = `
+		INSERT INTO idempotency_keys (owner, idempotency_key, fingerprint, status, created_at, expires_at)
+		VALUES (?, ?, ?, 0, ?, ?)`
+
+	sqlComplete = `
+		UPDATE idempotency_keys
+		SET status = ?, body = ?
+		WHERE owner = ? AND idempotency_key = ?`
+
+	sqlDelete = `
+		DELETE FROM idempotency_keys
+		WHERE owner = ? AND idempotency_key = ?`
+
+	sqlDeleteExpired = `
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= ?`
+)
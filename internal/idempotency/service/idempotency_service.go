@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+)
+
+type service struct {
+	repository domain.Repository
+	ttl        time.Duration
+}
+
+func NewService(r domain.Repository, ttl time.Duration) domain.Service {
+	return &service{
+		repository: r,
+		ttl:        ttl,
+	}
+}
+
+func (s *service) Start(ctx context.Context, owner, key, fingerprint string) (*domain.Record, error) {
+	now := date.Now()
+
+	// Expired keys may be used again, so they are cleared before claiming.
+	if err := s.repository.DeleteExpired(ctx, now); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	err := s.repository.Create(ctx, domain.Record{
+		Owner:       owner,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   date.NewDateTime(now.Add(s.ttl)),
+	})
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, domain.ErrDuplicate) {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	record, err := s.repository.Get(ctx, owner, key)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	if record.Fingerprint != fingerprint {
+		return nil, domain.ErrKeyReused
+	}
+
+	if !record.Completed() {
+		return nil, domain.ErrInProgress
+	}
+
+	return &record, nil
+}
+
+// Finish stores the response for replays. Server errors are not stored: the
+// key is released instead, so the client can retry the request for real.
+func (s *service) Finish(ctx context.Context, owner, key string, status int, body []byte) error {
+	var err error
+	if status >= 500 {
+		err = s.repository.Delete(ctx, owner, key)
+	} else {
+		err = s.repository.Complete(ctx, owner, key, status, body)
+	}
+
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	return nil
+}
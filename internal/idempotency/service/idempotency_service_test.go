@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/idempotency/domain/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Start(t *testing.T) {
+	t.Run("should claim a new key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+
+		var stored domain.Record
+		repository.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(nil)
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, record domain.Record) error {
+				stored = record
+				return nil
+			})
+
+		record, err := NewService(repository, time.Hour).Start(context.TODO(), "user-1", "key-1", "abc")
+		assert.NoError(t, err)
+		assert.Nil(t, record)
+		assert.Equal(t, "abc", stored.Fingerprint)
+		assert.Equal(t, time.Hour, stored.ExpiresAt.Sub(stored.CreatedAt.Time))
+	})
+
+	testCases := []struct {
+		name     string
+		existing domain.Record
+		err      error
+	}{
+		{
+			name:     "should replay a completed request",
+			existing: domain.Record{Fingerprint: "abc", Status: 201, Body: []byte(`{}`)},
+		},
+		{
+			name:     "should reject a request that is still running",
+			existing: domain.Record{Fingerprint: "abc"},
+			err:      domain.ErrInProgress,
+		},
+		{
+			name:     "should reject a key reused for another request",
+			existing: domain.Record{Fingerprint: "def", Status: 201},
+			err:      domain.ErrKeyReused,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repository := mock_domain.NewMockRepository(ctrl)
+
+			repository.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(nil)
+			repository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.ErrDuplicate)
+			repository.EXPECT().Get(gomock.Any(), "user-1", "key-1").Return(testCase.existing, nil)
+
+			record, err := NewService(repository, time.Hour).Start(context.TODO(), "user-1", "key-1", "abc")
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+				assert.Nil(t, record)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, &testCase.existing, record)
+		})
+	}
+
+	t.Run("should return repository errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+
+		_, err := NewService(repository, time.Hour).Start(context.TODO(), "user-1", "key-1", "abc")
+		assert.EqualError(t, err, "connection refused")
+	})
+}
+
+func TestService_Finish(t *testing.T) {
+	t.Run("should store the response", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().Complete(gomock.Any(), "user-1", "key-1", 201, []byte(`{}`)).Return(nil)
+
+		assert.NoError(t, NewService(repository, time.Hour).Finish(context.TODO(), "user-1", "key-1", 201, []byte(`{}`)))
+	})
+
+	t.Run("should release the key after a server error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().Delete(gomock.Any(), "user-1", "key-1").Return(nil)
+
+		assert.NoError(t, NewService(repository, time.Hour).Finish(context.TODO(), "user-1", "key-1", 500, nil))
+	})
+}
@@ -1,8 +1,14 @@
 package controller
 
 import (
+	"errors"
+	employeesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain"
+	sequencesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"github.com/gin-gonic/gin"
 	"log"
 	"net/http"
@@ -15,11 +21,11 @@ type InboudOrdersController struct {
 
 type requestInboudOrders struct {
 	Id             int    `json:"id"`
-	OrderDate      string `json:"order_date"`
+	OrderDate      string `json:"order_date" binding:"required,date"`
 	OrderNumber    string `json:"order_number"`
-	EmployeeId     int    `json:"employee_id"`
-	ProductBatchId int    `json:"product_batch_id"`
-	WarehouseId    int    `json:"warehouse_id"`
+	EmployeeId     int    `json:"employee_id" binding:"required,min=1"`
+	ProductBatchId int    `json:"product_batch_id" binding:"required,min=1"`
+	WarehouseId    int    `json:"warehouse_id" binding:"required,min=1"`
 }
 
 func NewInboudOrders(e domain.Service) *InboudOrdersController {
@@ -28,20 +34,37 @@ func NewInboudOrders(e domain.Service) *InboudOrdersController {
 	}
 }
 
+// createStatus maps the errors of creating an order to HTTP statuses. Errors
+// it does not know answer 500, so the idempotency key of the request is
+// released and the client may retry it.
+func createStatus(err error) int {
+	errNF := &employeesDomain.ErrorNotFound{}
+	switch {
+	case errors.As(err, &errNF):
+		return http.StatusNotFound
+	case errors.Is(err, sequencesDomain.ErrReserved):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrOrderNumberExists):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
 func (ioc *InboudOrdersController) Create() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var req requestInboudOrders
 		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity,
-				gin.H{
-					"error":  "VALIDATEERR-1",
-					"messge": "Invalid inputs. Please check your inputs",
-				})
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+		orderDate, err := date.Parse(req.OrderDate)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 			return
 		}
-		io, err := ioc.service.Create(ctx, req.OrderDate, req.OrderNumber, req.EmployeeId, req.ProductBatchId, req.WarehouseId)
+		io, err := ioc.service.Create(ctx, orderDate, req.OrderNumber, req.EmployeeId, req.ProductBatchId, req.WarehouseId)
 		if err != nil {
-			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			ctx.JSON(createStatus(err), gin.H{"error": err.Error()})
 			return
 		}
 		ctx.JSON(http.StatusCreated, response.NewResponse(io))
@@ -63,3 +86,49 @@ func (ioc *InboudOrdersController) GetById() gin.HandlerFunc {
 		ctx.JSON(http.StatusOK, response.NewResponse(i))
 	}
 }
+
+// Productivity godoc
+// @Summary Employee inbound productivity
+// @Tags InboundOrders
+// @Description inbound orders and units received per employee and period, ranked within each warehouse
+// @Produce  json
+// @Param warehouse_id query int false "Warehouse ID, every warehouse when missing"
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD"
+// @Param period query string false "day, week (default) or month"
+// @Success 200 {array} domain.EmployeeProductivity
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/inboud-orders/productivity [get]
+func (ioc *InboudOrdersController) Productivity() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		warehouseId := 0
+		if value, ok := ctx.GetQuery("warehouse_id"); ok {
+			id, err := strconv.Atoi(value)
+			if err != nil || id < 1 {
+				ctx.JSON(http.StatusBadRequest, response.DecodeError("invalid warehouse_id"))
+				return
+			}
+			warehouseId = id
+		}
+
+		dates, err := period.RangeFromQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
+		unit, err := period.UnitFromQuery(ctx, period.Week)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
+		report, err := ioc.service.GetProductivity(ctx, warehouseId, dates, unit)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, response.DecodeError(err.Error()))
+			return
+		}
+		ctx.JSON(http.StatusOK, response.NewResponse(report))
+	}
+}
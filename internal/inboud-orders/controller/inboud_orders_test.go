@@ -2,9 +2,16 @@ package controller
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	employeesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain"
 	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain/mock"
+	sequencesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -65,7 +72,7 @@ func TestController_GetByEmployee_Nok(t *testing.T) {
 func TestController_Create_Ok(t *testing.T) {
 	io := domain.InboudOrder{
 		Id:             4,
-		OrderDate:      "1900-01-01",
+		OrderDate:      date.New(1900, 1, 1),
 		OrderNumber:    "order#3",
 		EmployeeId:     4,
 		ProductBatchId: 2,
@@ -75,7 +82,7 @@ func TestController_Create_Ok(t *testing.T) {
 	api := gin.New()
 	api.POST(relativePathInboudOrders, handler.Create())
 
-	service.EXPECT().Create(gomock.Any(), "1900-01-01", "order#3", 4, 2, 2).Return(&io, nil)
+	service.EXPECT().Create(gomock.Any(), date.New(1900, 1, 1), "order#3", 4, 2, 2).Return(&io, nil)
 
 	body := `{"order_date": "1900-01-01","order_number": "order#3","employee_id":4,"product_batch_id": 2,"warehouse_id": 2}`
 	req := httptest.NewRequest(http.MethodPost, relativePathInboudOrders, bytes.NewBuffer([]byte(body)))
@@ -84,3 +91,96 @@ func TestController_Create_Ok(t *testing.T) {
 
 	assert.Equal(t, http.StatusCreated, resp.Code)
 }
+
+func TestController_Create_Errors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"employee not found", &employeesDomain.ErrorNotFound{Id: 4}, http.StatusNotFound},
+		{"reserved order number", fmt.Errorf("order number IO-20220801-0000425: %w", sequencesDomain.ErrReserved), http.StatusUnprocessableEntity},
+		{"order number exists", domain.ErrOrderNumberExists, http.StatusConflict},
+		{"unknown", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, handler := callMock(t)
+			api := gin.New()
+			api.POST(relativePathInboudOrders, handler.Create())
+
+			service.EXPECT().Create(gomock.Any(), date.New(1900, 1, 1), "order#3", 4, 2, 2).Return(nil, testCase.err)
+
+			body := `{"order_date": "1900-01-01","order_number": "order#3","employee_id":4,"product_batch_id": 2,"warehouse_id": 2}`
+			req := httptest.NewRequest(http.MethodPost, relativePathInboudOrders, bytes.NewBuffer([]byte(body)))
+			resp := httptest.NewRecorder()
+			api.ServeHTTP(resp, req)
+
+			assert.Equal(t, testCase.status, resp.Code)
+		})
+	}
+}
+
+func TestController_Create_InvalidDate(t *testing.T) {
+	_, handler := callMock(t)
+	api := gin.New()
+	api.POST(relativePathInboudOrders, handler.Create())
+
+	body := `{"order_date": "01/01/1900","order_number": "order#3","employee_id":4,"product_batch_id": 2,"warehouse_id": 2}`
+	req := httptest.NewRequest(http.MethodPost, relativePathInboudOrders, bytes.NewBuffer([]byte(body)))
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	var got response.Response
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	assert.Equal(t, []response.FieldError{
+		{Field: "order_date", Code: "date", Message: "must be a date in the format 2006-01-02"},
+	}, got.Fields)
+}
+
+func TestController_Productivity(t *testing.T) {
+	const path = "/api/v1/inboud-orders/productivity"
+
+	t.Run("Ok", func(t *testing.T) {
+		service, handler := callMock(t)
+		api := gin.New()
+		api.GET(path, handler.Productivity())
+		dates := period.Range{From: date.New(2022, 7, 1), To: date.New(2022, 7, 31)}
+		service.EXPECT().GetProductivity(gomock.Any(), 3, dates, period.Day).
+			Return([]domain.EmployeeProductivity{{EmployeeId: 1, WarehouseId: 3, Rank: 1}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, path+"?warehouse_id=3&from=2022-07-01&to=2022-07-31&period=day", nil)
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Default period", func(t *testing.T) {
+		service, handler := callMock(t)
+		api := gin.New()
+		api.GET(path, handler.Productivity())
+		service.EXPECT().GetProductivity(gomock.Any(), 0, period.Range{}, period.Week).Return(nil, errors.New("error"))
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+
+	for _, query := range []string{"?warehouse_id=abc", "?from=07-01-2022", "?period=year"} {
+		t.Run("Bad request "+query, func(t *testing.T) {
+			_, handler := callMock(t)
+			api := gin.New()
+			api.GET(path, handler.Productivity())
+
+			resp := httptest.NewRecorder()
+			api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
@@ -1,14 +1,24 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+)
+
+// ErrOrderNumberExists is returned when an order is created with the number of
+// another order.
+var ErrOrderNumberExists = errors.New("order number already exists")
 
 type InboudOrder struct {
-	Id             int    `json:"id"`
-	OrderDate      string `json:"order_date"`
-	OrderNumber    string `json:"order_number"`
-	EmployeeId     int    `json:"employee_id"`
-	ProductBatchId int    `json:"product_batch_id"`
-	WarehouseId    int    `json:"warehouse_id"`
+	Id             int       `json:"id"`
+	OrderDate      date.Date `json:"order_date"`
+	OrderNumber    string    `json:"order_number"`
+	EmployeeId     int       `json:"employee_id"`
+	ProductBatchId int       `json:"product_batch_id"`
+	WarehouseId    int       `json:"warehouse_id"`
 }
 
 type EmployeeInboudOrder struct {
@@ -20,14 +30,50 @@ type EmployeeInboudOrder struct {
 	InboudOrderCount int    `json:"inboud_order_count"`
 }
 
+// Receipt is one inbound order handled by an employee along with the units
+// of the product batch it brought in.
+type Receipt struct {
+	EmployeeId   int
+	CardNumberId string
+	FirstName    string
+	LastName     string
+	WarehouseId  int
+	OrderDate    date.Date
+	Units        int
+}
+
+// EmployeeProductivity sums up the inbound orders an employee handled in a
+// warehouse.
+// Rank is the position of the employee within the warehouse by units
+// received, then by orders; ties share a rank.
+type EmployeeProductivity struct {
+	EmployeeId    int                  `json:"employee_id"`
+	CardNumberId  string               `json:"card_number_id"`
+	FirstName     string               `json:"first_name"`
+	LastName      string               `json:"last_name"`
+	WarehouseId   int                  `json:"warehouse_id"`
+	Rank          int                  `json:"rank"`
+	OrdersCount   int                  `json:"inbound_orders_count"`
+	UnitsReceived int                  `json:"units_received"`
+	Periods       []ProductivityPeriod `json:"periods"`
+}
+
+type ProductivityPeriod struct {
+	Period        string `json:"period"`
+	OrdersCount   int    `json:"inbound_orders_count"`
+	UnitsReceived int    `json:"units_received"`
+}
+
 //go:generate mockgen -source=./domain.go -destination=./mock/domain_mock.go
 type Repository interface {
-	Create(context.Context, string, string, int, int, int) (*InboudOrder, error)
+	Create(context.Context, date.Date, string, int, int, int) (*InboudOrder, error)
 	GetAll(context.Context) ([]InboudOrder, error)
 	GetByEmployee(ctx context.Context, employee int64) ([]EmployeeInboudOrder, error)
+	GetReceipts(ctx context.Context, warehouseId int, dates period.Range) ([]Receipt, error)
 }
 
 type Service interface {
-	Create(context.Context, string, string, int, int, int) (*InboudOrder, error)
+	Create(context.Context, date.Date, string, int, int, int) (*InboudOrder, error)
 	GetByEmployee(ctx context.Context, employee int64) ([]EmployeeInboudOrder, error)
+	GetProductivity(ctx context.Context, warehouseId int, dates period.Range, unit period.Unit) ([]EmployeeProductivity, error)
 }
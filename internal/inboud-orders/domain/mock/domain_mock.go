@@ -9,6 +9,8 @@ import (
 	reflect "reflect"
 
 	domain "github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain"
+	date "github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	period "github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -36,7 +38,7 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockRepository) Create(arg0 context.Context, arg1, arg2 string, arg3, arg4, arg5 int) (*domain.InboudOrder, error) {
+func (m *MockRepository) Create(arg0 context.Context, arg1 date.Date, arg2 string, arg3, arg4, arg5 int) (*domain.InboudOrder, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(*domain.InboudOrder)
@@ -80,6 +82,21 @@ func (mr *MockRepositoryMockRecorder) GetByEmployee(ctx, employee interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmployee", reflect.TypeOf((*MockRepository)(nil).GetByEmployee), ctx, employee)
 }
 
+// GetReceipts mocks base method.
+func (m *MockRepository) GetReceipts(ctx context.Context, warehouseId int, dates period.Range) ([]domain.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReceipts", ctx, warehouseId, dates)
+	ret0, _ := ret[0].([]domain.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceipts indicates an expected call of GetReceipts.
+func (mr *MockRepositoryMockRecorder) GetReceipts(ctx, warehouseId, dates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceipts", reflect.TypeOf((*MockRepository)(nil).GetReceipts), ctx, warehouseId, dates)
+}
+
 // MockService is a mock of Service interface.
 type MockService struct {
 	ctrl     *gomock.Controller
@@ -104,7 +121,7 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockService) Create(arg0 context.Context, arg1, arg2 string, arg3, arg4, arg5 int) (*domain.InboudOrder, error) {
+func (m *MockService) Create(arg0 context.Context, arg1 date.Date, arg2 string, arg3, arg4, arg5 int) (*domain.InboudOrder, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(*domain.InboudOrder)
@@ -132,3 +149,18 @@ func (mr *MockServiceMockRecorder) GetByEmployee(ctx, employee interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmployee", reflect.TypeOf((*MockService)(nil).GetByEmployee), ctx, employee)
 }
+
+// GetProductivity mocks base method.
+func (m *MockService) GetProductivity(ctx context.Context, warehouseId int, dates period.Range, unit period.Unit) ([]domain.EmployeeProductivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductivity", ctx, warehouseId, dates, unit)
+	ret0, _ := ret[0].([]domain.EmployeeProductivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductivity indicates an expected call of GetProductivity.
+func (mr *MockServiceMockRecorder) GetProductivity(ctx, warehouseId, dates, unit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductivity", reflect.TypeOf((*MockService)(nil).GetProductivity), ctx, warehouseId, dates, unit)
+}
@@ -1,7 +1,14 @@
 package repository
 
 const (
-	queryGetAll       = "SELECT id,order_date,order_number,employee_id,product_batch_id, warehouse_id FROM inbound_orders"
-	queryGetByEmplyee = "Select e.id ,e.id_card_number , e.first_name , e.last_name , e.warehouse_id ,count(*) as inbound_orders_count from inbound_orders io inner join employees e on e.id = io.employee_id where employee_id = ?"
-	queryCreate       = "insert into inbound_orders(order_date, order_number, employee_id, product_batch_id, warehouse_id) values(?,?,?,?,?)"
+	queryGetAll          = "SELECT id,order_date,order_number,employee_id,product_batch_id, warehouse_id FROM inbound_orders"
+	queryCountByEmployee = "SELECT e.id, e.id_card_number, e.first_name, e.last_name, e.warehouse_id, COUNT(io.id) AS inbound_orders_count FROM employees e LEFT JOIN inbound_orders io ON e.id = io.employee_id WHERE e.deleted_at IS NULL"
+	queryGetByEmplyee    = queryCountByEmployee + " AND e.id = ? GROUP BY e.id"
+	queryGetByEmployees  = queryCountByEmployee + " GROUP BY e.id ORDER BY e.id"
+	queryCreate          = "insert into inbound_orders(order_date, order_number, employee_id, product_batch_id, warehouse_id) values(?,?,?,?,?)"
+	// queryGetReceipts is completed with the conditions of the request and
+	// queryGetReceiptsOrder. Orders count for the warehouse they were
+	// received in, not the one the employee is assigned to now.
+	queryGetReceipts      = "SELECT e.id, e.id_card_number, e.first_name, e.last_name, io.warehouse_id, io.order_date, pb.initial_quantity FROM inbound_orders io INNER JOIN employees e ON e.id = io.employee_id INNER JOIN product_batches pb ON pb.id = io.product_batch_id WHERE e.deleted_at IS NULL"
+	queryGetReceiptsOrder = " ORDER BY io.warehouse_id, e.id, io.order_date"
 )
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"log"
 )
 
@@ -45,11 +47,12 @@ func (r *repository) GetByEmployee(ctx context.Context, employee int64) ([]domai
 		}
 		io = append(io, i)
 	} else {
-		rows, err := r.db.Query(queryGetByEmplyee)
+		rows, err := r.db.Query(queryGetByEmployees)
 		if err != nil {
 			log.Println("Error while querying inboud orders table" + err.Error())
 			return nil, err
 		}
+		defer rows.Close()
 		for rows.Next() {
 			var i domain.EmployeeInboudOrder
 			err := rows.Scan(&i.Id, &i.CardNumberId, &i.FirstName, &i.LastName, &i.WarehouseId, &i.InboudOrderCount)
@@ -63,7 +66,37 @@ func (r *repository) GetByEmployee(ctx context.Context, employee int64) ([]domai
 	return io, nil
 }
 
-func (r *repository) Create(ctx context.Context, orderDate string, orderNumber string, employeeId int, productBatchId int, warehouseId int) (*domain.InboudOrder, error) {
+// GetReceipts lists the inbound orders placed within dates, with the units of
+// their batches, by the warehouse that received them and employee. A
+// warehouseId of 0 covers every warehouse.
+func (r *repository) GetReceipts(ctx context.Context, warehouseId int, dates period.Range) ([]domain.Receipt, error) {
+	query, args := queryGetReceipts, []interface{}{}
+	if warehouseId != 0 {
+		query, args = query+" AND io.warehouse_id = ?", append(args, warehouseId)
+	}
+	conditions, rangeArgs := dates.Where("io.order_date")
+
+	rows, err := r.db.QueryContext(ctx, query+conditions+queryGetReceiptsOrder, append(args, rangeArgs...)...)
+	if err != nil {
+		log.Println("Error while querying inboud orders table" + err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	receipts := make([]domain.Receipt, 0)
+	for rows.Next() {
+		var i domain.Receipt
+		err := rows.Scan(&i.EmployeeId, &i.CardNumberId, &i.FirstName, &i.LastName, &i.WarehouseId, &i.OrderDate, &i.Units)
+		if err != nil {
+			log.Println("Error while scanning inbound orders " + err.Error())
+			return nil, err
+		}
+		receipts = append(receipts, i)
+	}
+	return receipts, rows.Err()
+}
+
+func (r *repository) Create(ctx context.Context, orderDate date.Date, orderNumber string, employeeId int, productBatchId int, warehouseId int) (*domain.InboudOrder, error) {
 	result, err := r.db.Exec(queryCreate, orderDate, orderNumber, employeeId, productBatchId, warehouseId)
 	if err != nil {
 		return nil, err
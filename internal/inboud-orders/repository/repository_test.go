@@ -5,6 +5,8 @@ import (
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"github.com/stretchr/testify/assert"
 	"regexp"
 	"testing"
@@ -18,7 +20,7 @@ func TestRepository_GetAll_Ok(t *testing.T) {
 	ioList := []domain.InboudOrder{
 		{
 			1,
-			"1900-01-01",
+			date.New(1900, 1, 1),
 			"order#1",
 			2,
 			1,
@@ -26,7 +28,7 @@ func TestRepository_GetAll_Ok(t *testing.T) {
 		},
 		{
 			2,
-			"1900-01-01",
+			date.New(1900, 1, 1),
 			"order#2",
 			3,
 			2,
@@ -37,14 +39,14 @@ func TestRepository_GetAll_Ok(t *testing.T) {
 		"id", "order_date", "order_number", "employee_id", "product_batch_id", "warehouse_id",
 	}).AddRow(
 		ioList[0].Id,
-		ioList[0].OrderDate,
+		ioList[0].OrderDate.String(),
 		ioList[0].OrderNumber,
 		ioList[0].EmployeeId,
 		ioList[0].ProductBatchId,
 		ioList[0].WarehouseId,
 	).AddRow(
 		ioList[1].Id,
-		ioList[1].OrderDate,
+		ioList[1].OrderDate.String(),
 		ioList[1].OrderNumber,
 		ioList[1].EmployeeId,
 		ioList[1].ProductBatchId,
@@ -82,7 +84,7 @@ func TestRepository_Create_Ok(t *testing.T) {
 
 	io := domain.InboudOrder{
 		1,
-		"1900-01-01",
+		date.New(1900, 1, 1),
 		"order#1",
 		2,
 		1,
@@ -98,7 +100,7 @@ func TestRepository_Create_Ok(t *testing.T) {
 	).WillReturnResult(sqlmock.NewResult(1, 1))
 	ioRepo := NewRepository(db)
 
-	result, err := ioRepo.Create(context.TODO(), "1900-01-01", "order#1", 2, 1, 1)
+	result, err := ioRepo.Create(context.TODO(), date.New(1900, 1, 1), "order#1", 2, 1, 1)
 	assert.NoError(t, err)
 	assert.Equal(t, result.Id, 1)
 }
@@ -113,7 +115,7 @@ func TestRepository_Create_Nok(t *testing.T) {
 
 	ioRepo := NewRepository(db)
 
-	_, err = ioRepo.Create(context.TODO(), "1900-01-01", "order#1", 2, 1, 1)
+	_, err = ioRepo.Create(context.TODO(), date.New(1900, 1, 1), "order#1", 2, 1, 1)
 
 	assert.Error(t, err)
 }
@@ -215,7 +217,7 @@ func TestRepository_GetByEmployee_equal_zero(t *testing.T) {
 		ioList[0].WarehouseId,
 		ioList[0].InboudOrderCount,
 	)
-	mock.ExpectQuery(regexp.QuoteMeta(queryGetByEmplyee)).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetByEmployees)).WillReturnRows(rows)
 
 	empIo := NewRepository(db)
 
@@ -225,3 +227,43 @@ func TestRepository_GetByEmployee_equal_zero(t *testing.T) {
 	assert.Equal(t, ioReport[0], result[0])
 
 }
+
+func TestRepository_GetReceipts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dates := period.Range{From: date.New(2022, 7, 1)}
+	rows := sqlmock.NewRows([]string{
+		"id", "id_card_number", "first_name", "last_name", "warehouse_id", "order_date", "initial_quantity",
+	}).AddRow(1, "5555", "Douglas", "Mendes", 3, "2022-07-04", 120)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetReceipts+" AND io.warehouse_id = ? AND io.order_date >= ?"+queryGetReceiptsOrder)).
+		WithArgs(3, dates.From).
+		WillReturnRows(rows)
+
+	ioRepo := NewRepository(db)
+	result, err := ioRepo.GetReceipts(context.TODO(), 3, dates)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Receipt{{
+		EmployeeId:   1,
+		CardNumberId: "5555",
+		FirstName:    "Douglas",
+		LastName:     "Mendes",
+		WarehouseId:  3,
+		OrderDate:    date.New(2022, 7, 4),
+		Units:        120,
+	}}, result)
+}
+
+func TestRepository_GetReceipts_Nok(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetReceipts + queryGetReceiptsOrder)).WillReturnError(errors.New("error"))
+
+	ioRepo := NewRepository(db)
+	_, err = ioRepo.GetReceipts(context.TODO(), 0, period.Range{})
+	assert.Error(t, err)
+}
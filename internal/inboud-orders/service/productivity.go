@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+)
+
+// GetProductivity sums up the inbound orders and units every employee
+// received within dates, grouped by unit, and ranks the employees of each
+// warehouse.
+func (s service) GetProductivity(ctx context.Context, warehouseId int, dates period.Range, unit period.Unit) ([]domain.EmployeeProductivity, error) {
+	receipts, err := s.repository.GetReceipts(ctx, warehouseId, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	// Receipts come ordered by warehouse, employee and date. An employee who
+	// received orders in several warehouses is counted in each of them.
+	report := make([]domain.EmployeeProductivity, 0)
+	var periods []*period.Grouping[domain.ProductivityPeriod]
+	for _, receipt := range receipts {
+		last := len(report) - 1
+		if last < 0 || report[last].EmployeeId != receipt.EmployeeId || report[last].WarehouseId != receipt.WarehouseId {
+			report = append(report, domain.EmployeeProductivity{
+				EmployeeId:   receipt.EmployeeId,
+				CardNumberId: receipt.CardNumberId,
+				FirstName:    receipt.FirstName,
+				LastName:     receipt.LastName,
+				WarehouseId:  receipt.WarehouseId,
+			})
+			periods = append(periods, period.NewGrouping(unit, func(key string) domain.ProductivityPeriod {
+				return domain.ProductivityPeriod{Period: key}
+			}))
+			last++
+		}
+		employee := &report[last]
+
+		row := periods[last].Of(receipt.OrderDate)
+		row.OrdersCount++
+		row.UnitsReceived += receipt.Units
+		employee.OrdersCount++
+		employee.UnitsReceived += receipt.Units
+	}
+	for i := range report {
+		report[i].Periods = periods[i].Rows()
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.WarehouseId != b.WarehouseId {
+			return a.WarehouseId < b.WarehouseId
+		}
+		if a.UnitsReceived != b.UnitsReceived {
+			return a.UnitsReceived > b.UnitsReceived
+		}
+		return a.OrdersCount > b.OrdersCount
+	})
+
+	first := 0
+	for i := range report {
+		if i > 0 && report[i].WarehouseId != report[i-1].WarehouseId {
+			first = i
+		}
+
+		if i > first && report[i].UnitsReceived == report[i-1].UnitsReceived && report[i].OrdersCount == report[i-1].OrdersCount {
+			report[i].Rank = report[i-1].Rank
+		} else {
+			report[i].Rank = i - first + 1
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/stretchr/testify/assert"
+)
+
+func receipt(employeeId, warehouseId int, orderDate date.Date, units int) domain.Receipt {
+	return domain.Receipt{EmployeeId: employeeId, WarehouseId: warehouseId, OrderDate: orderDate, Units: units}
+}
+
+func TestService_GetProductivity(t *testing.T) {
+	t.Run("Ranks within warehouse", func(t *testing.T) {
+		apiMockIo, _, serviceIo := callMock(t)
+
+		dates := period.Range{From: date.New(2022, 7, 1)}
+		apiMockIo.EXPECT().GetReceipts(context.TODO(), 0, dates).Return([]domain.Receipt{
+			receipt(1, 1, date.New(2022, 7, 4), 10),
+			receipt(1, 1, date.New(2022, 7, 5), 20),
+			receipt(1, 1, date.New(2022, 7, 12), 5),
+			receipt(2, 1, date.New(2022, 7, 4), 50),
+			receipt(3, 1, date.New(2022, 7, 6), 35),
+			receipt(4, 2, date.New(2022, 7, 6), 1),
+		}, nil)
+
+		result, err := serviceIo.GetProductivity(context.TODO(), 0, dates, period.Week)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.EmployeeProductivity{
+			{
+				EmployeeId: 2, WarehouseId: 1, Rank: 1, OrdersCount: 1, UnitsReceived: 50,
+				Periods: []domain.ProductivityPeriod{{Period: "2022-W27", OrdersCount: 1, UnitsReceived: 50}},
+			},
+			{
+				EmployeeId: 1, WarehouseId: 1, Rank: 2, OrdersCount: 3, UnitsReceived: 35,
+				Periods: []domain.ProductivityPeriod{
+					{Period: "2022-W27", OrdersCount: 2, UnitsReceived: 30},
+					{Period: "2022-W28", OrdersCount: 1, UnitsReceived: 5},
+				},
+			},
+			{
+				EmployeeId: 3, WarehouseId: 1, Rank: 3, OrdersCount: 1, UnitsReceived: 35,
+				Periods: []domain.ProductivityPeriod{{Period: "2022-W27", OrdersCount: 1, UnitsReceived: 35}},
+			},
+			{
+				EmployeeId: 4, WarehouseId: 2, Rank: 1, OrdersCount: 1, UnitsReceived: 1,
+				Periods: []domain.ProductivityPeriod{{Period: "2022-W27", OrdersCount: 1, UnitsReceived: 1}},
+			},
+		}, result)
+	})
+
+	t.Run("Ties share a rank", func(t *testing.T) {
+		apiMockIo, _, serviceIo := callMock(t)
+
+		apiMockIo.EXPECT().GetReceipts(context.TODO(), 1, period.Range{}).Return([]domain.Receipt{
+			receipt(1, 1, date.New(2022, 7, 4), 10),
+			receipt(2, 1, date.New(2022, 7, 4), 10),
+			receipt(3, 1, date.New(2022, 7, 4), 5),
+		}, nil)
+
+		result, err := serviceIo.GetProductivity(context.TODO(), 1, period.Range{}, period.Day)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 1, 3}, []int{result[0].Rank, result[1].Rank, result[2].Rank})
+	})
+
+	t.Run("Employee in several warehouses", func(t *testing.T) {
+		apiMockIo, _, serviceIo := callMock(t)
+
+		apiMockIo.EXPECT().GetReceipts(context.TODO(), 0, period.Range{}).Return([]domain.Receipt{
+			receipt(1, 1, date.New(2022, 7, 4), 10),
+			receipt(1, 2, date.New(2022, 7, 5), 20),
+		}, nil)
+
+		result, err := serviceIo.GetProductivity(context.TODO(), 0, period.Range{}, period.Day)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.EmployeeProductivity{
+			{
+				EmployeeId: 1, WarehouseId: 1, Rank: 1, OrdersCount: 1, UnitsReceived: 10,
+				Periods: []domain.ProductivityPeriod{{Period: "2022-07-04", OrdersCount: 1, UnitsReceived: 10}},
+			},
+			{
+				EmployeeId: 1, WarehouseId: 2, Rank: 1, OrdersCount: 1, UnitsReceived: 20,
+				Periods: []domain.ProductivityPeriod{{Period: "2022-07-05", OrdersCount: 1, UnitsReceived: 20}},
+			},
+		}, result)
+	})
+
+	t.Run("Nok", func(t *testing.T) {
+		apiMockIo, _, serviceIo := callMock(t)
+
+		apiMockIo.EXPECT().GetReceipts(context.TODO(), 0, period.Range{}).Return(nil, errors.New("error"))
+
+		_, err := serviceIo.GetProductivity(context.TODO(), 0, period.Range{}, period.Week)
+
+		assert.Error(t, err)
+	})
+}
@@ -5,33 +5,50 @@ import (
 	"fmt"
 	repositoryEmployee "github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain"
+	sequencesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 )
 
 type service struct {
 	repository         domain.Repository
 	repositoryEmployee repositoryEmployee.Repository
+	sequences          sequencesDomain.Service
 }
 
-func NewService(r domain.Repository, re repositoryEmployee.Repository) domain.Service {
+func NewService(r domain.Repository, re repositoryEmployee.Repository, sequences sequencesDomain.Service) domain.Service {
 	return &service{
 		repository:         r,
 		repositoryEmployee: re,
+		sequences:          sequences,
 	}
 }
 
-func (s service) Create(ctx context.Context, orderDate string, orderNumber string, employeeId int, productBatchId int, warehouseId int) (*domain.InboudOrder, error) {
+func (s service) Create(ctx context.Context, orderDate date.Date, orderNumber string, employeeId int, productBatchId int, warehouseId int) (*domain.InboudOrder, error) {
 
 	_, err := s.repositoryEmployee.GetById(ctx, int64(employeeId))
-	if err != nil {
-		return nil, fmt.Errorf("employee number not found")
-	}
-	in, err := s.repository.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
-	for i := range in {
-		if in[i].OrderNumber == orderNumber {
-			return nil, fmt.Errorf("order number already exists")
+	// Numbers sent by clients may not take the shape of the generated ones,
+	// so a number generated later never collides with one a client chose.
+	if orderNumber == "" {
+		number, err := s.sequences.Next(ctx, sequencesDomain.InboundOrderNumber)
+		if err != nil {
+			return nil, err
+		}
+		orderNumber = number
+	} else {
+		if s.sequences.Reserved(sequencesDomain.InboundOrderNumber, orderNumber) {
+			return nil, fmt.Errorf("order number %s: %w", orderNumber, sequencesDomain.ErrReserved)
+		}
+		in, err := s.repository.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := range in {
+			if in[i].OrderNumber == orderNumber {
+				return nil, domain.ErrOrderNumberExists
+			}
 		}
 	}
 	io, err := s.repository.Create(ctx, orderDate, orderNumber, employeeId, productBatchId, warehouseId)
@@ -7,25 +7,39 @@ import (
 	employeeMock "github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain/mock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/inboud-orders/domain/mock"
+	sequencesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	sequencesMock "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
 
 func callMock(t *testing.T) (*mock_domain.MockRepository, *employeeMock.MockRepository, domain.Service) {
+	apiMockIo, apiMockEmp, _, serviceIo := callMockWithSequences(t)
+	return apiMockIo, apiMockEmp, serviceIo
+}
+
+func callMockWithSequences(t *testing.T) (*mock_domain.MockRepository, *employeeMock.MockRepository, *sequencesMock.MockService, domain.Service) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	apiMockIo := mock_domain.NewMockRepository(ctrl)
 	apiMockEmp := employeeMock.NewMockRepository(ctrl)
-	serviceIo := NewService(apiMockIo, apiMockEmp)
-	return apiMockIo, apiMockEmp, serviceIo
+	apiMockSeq := sequencesMock.NewMockService(ctrl)
+	serviceIo := NewService(apiMockIo, apiMockEmp, apiMockSeq)
+	apiMockSeq.EXPECT().Reserved(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(name, value string) bool {
+			return sequencesDomain.DefaultFormats[name].Matches(value)
+		},
+	)
+	return apiMockIo, apiMockEmp, apiMockSeq, serviceIo
 }
 
 func TestService_Create_Ok(t *testing.T) {
 	ioList := []domain.InboudOrder{
 		{
 			1,
-			"1900-01-01",
+			date.New(1900, 1, 1),
 			"order#1",
 			2,
 			1,
@@ -33,7 +47,7 @@ func TestService_Create_Ok(t *testing.T) {
 		},
 		{
 			2,
-			"1900-01-01",
+			date.New(1900, 1, 1),
 			"order#2",
 			3,
 			2,
@@ -42,7 +56,7 @@ func TestService_Create_Ok(t *testing.T) {
 	}
 	io := &domain.InboudOrder{
 		Id:             4,
-		OrderDate:      "1900-01-01",
+		OrderDate:      date.New(1900, 1, 1),
 		OrderNumber:    "order#3",
 		EmployeeId:     4,
 		ProductBatchId: 2,
@@ -59,8 +73,8 @@ func TestService_Create_Ok(t *testing.T) {
 
 	apiMockEmp.EXPECT().GetById(context.TODO(), int64(4)).Return(emp, nil)
 	apiMockIo.EXPECT().GetAll(context.TODO()).Return(ioList, nil)
-	apiMockIo.EXPECT().Create(context.TODO(), "1900-01-01", "order#3", 4, 2, 2).Return(io, nil)
-	result, err := service.Create(context.TODO(), "1900-01-01", "order#3", 4, 2, 2)
+	apiMockIo.EXPECT().Create(context.TODO(), date.New(1900, 1, 1), "order#3", 4, 2, 2).Return(io, nil)
+	result, err := service.Create(context.TODO(), date.New(1900, 1, 1), "order#3", 4, 2, 2)
 	assert.Equal(t, io, result)
 	assert.Nil(t, err)
 }
@@ -69,7 +83,7 @@ func TestService_Create_Nok(t *testing.T) {
 	ioList := []domain.InboudOrder{
 		{
 			1,
-			"1900-01-01",
+			date.New(1900, 1, 1),
 			"order#1",
 			2,
 			1,
@@ -77,7 +91,7 @@ func TestService_Create_Nok(t *testing.T) {
 		},
 		{
 			2,
-			"1900-01-01",
+			date.New(1900, 1, 1),
 			"order#2",
 			3,
 			2,
@@ -95,12 +109,68 @@ func TestService_Create_Nok(t *testing.T) {
 
 	apiMockEmp.EXPECT().GetById(context.TODO(), int64(4)).Return(emp, nil)
 	apiMockIo.EXPECT().GetAll(context.TODO()).Return(ioList, nil)
-	apiMockIo.EXPECT().Create(context.TODO(), "1900-01-01", "order#3", 4, 2, 2).Return(nil, errors.New("employee number not found"))
+	apiMockIo.EXPECT().Create(context.TODO(), date.New(1900, 1, 1), "order#3", 4, 2, 2).Return(nil, errors.New("employee number not found"))
 
-	_, err := service.Create(context.TODO(), "1900-01-01", "order#3", 4, 2, 2)
+	_, err := service.Create(context.TODO(), date.New(1900, 1, 1), "order#3", 4, 2, 2)
 	assert.NotNil(t, err)
 }
 
+func TestService_Create_GeneratedOrderNumber(t *testing.T) {
+	io := &domain.InboudOrder{
+		Id:             4,
+		OrderDate:      date.New(1900, 1, 1),
+		OrderNumber:    "IO-20220801-0000013",
+		EmployeeId:     4,
+		ProductBatchId: 2,
+		WarehouseId:    2,
+	}
+	apiMockIo, apiMockEmp, apiMockSeq, service := callMockWithSequences(t)
+
+	apiMockEmp.EXPECT().GetById(context.TODO(), int64(4)).Return(&employeeDomain.Employee{Id: 4}, nil)
+	apiMockSeq.EXPECT().Next(context.TODO(), sequencesDomain.InboundOrderNumber).Return("IO-20220801-0000013", nil)
+	apiMockIo.EXPECT().GetAll(gomock.Any()).Times(0)
+	apiMockIo.EXPECT().Create(context.TODO(), date.New(1900, 1, 1), "IO-20220801-0000013", 4, 2, 2).Return(io, nil)
+
+	result, err := service.Create(context.TODO(), date.New(1900, 1, 1), "", 4, 2, 2)
+	assert.Equal(t, io, result)
+	assert.Nil(t, err)
+}
+
+func TestService_Create_ReservedOrderNumber(t *testing.T) {
+	apiMockIo, apiMockEmp, service := callMock(t)
+
+	apiMockEmp.EXPECT().GetById(context.TODO(), int64(4)).Return(&employeeDomain.Employee{Id: 4}, nil)
+	apiMockIo.EXPECT().GetAll(gomock.Any()).Times(0)
+	apiMockIo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := service.Create(context.TODO(), date.New(1900, 1, 1), "IO-20220801-0000425", 4, 2, 2)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, sequencesDomain.ErrReserved)
+}
+
+func TestService_Create_EmployeeNotFound(t *testing.T) {
+	apiMockIo, apiMockEmp, service := callMock(t)
+
+	apiMockEmp.EXPECT().GetById(context.TODO(), int64(9)).Return(nil, &employeeDomain.ErrorNotFound{Id: 9})
+	apiMockIo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := service.Create(context.TODO(), date.New(1900, 1, 1), "order#3", 9, 2, 2)
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "Employee 9 not found")
+}
+
+func TestService_Create_OrderNumberExists(t *testing.T) {
+	apiMockIo, apiMockEmp, service := callMock(t)
+
+	apiMockEmp.EXPECT().GetById(context.TODO(), int64(4)).Return(&employeeDomain.Employee{Id: 4}, nil)
+	apiMockIo.EXPECT().GetAll(context.TODO()).Return([]domain.InboudOrder{{Id: 1, OrderNumber: "order#3"}}, nil)
+	apiMockIo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := service.Create(context.TODO(), date.New(1900, 1, 1), "order#3", 4, 2, 2)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domain.ErrOrderNumberExists)
+}
+
 func TestService_GetByEmployee(t *testing.T) {
 	ioReport := []domain.EmployeeInboudOrder{{
 		Id:               1,
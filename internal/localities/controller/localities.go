@@ -1,13 +1,17 @@
 package controller
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/bulk"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"github.com/gin-gonic/gin"
 )
 
@@ -79,7 +83,7 @@ func (c *LocalityController) Create() gin.HandlerFunc {
 		var req sqlCreateRequest
 		if err := ctx.ShouldBindJSON(&req); err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
-			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			ctx.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 			return
 		}
 
@@ -94,3 +98,22 @@ func (c *LocalityController) Create() gin.HandlerFunc {
 
 	}
 }
+
+// ImportLocalities godoc
+// @Summary Import localities
+// @Tags Localities
+// @Description Create localities in bulk from a CSV or NDJSON upload, reporting the outcome of each row
+// @Accept text/csv,application/x-ndjson,multipart/form-data
+// @Produce json
+// @Param atomic query bool false "Keep no locality when any row fails"
+// @Param file formData file false "CSV or NDJSON file, for multipart uploads"
+// @Success 200 {object} bulk.Report
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} bulk.Report
+// @Router /api/v1/localities/import [post]
+func (c *LocalityController) Import(runner transaction.Runner) gin.HandlerFunc {
+	return bulk.Handler(runner, func(ctx context.Context, req sqlCreateRequest) (int, error) {
+		locality, err := c.service.Create(ctx, req.ZipCode, req.LocalityName, req.ProvinceName, req.CountryName)
+		return locality.Id, err
+	})
+}
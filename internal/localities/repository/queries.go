@@ -2,7 +2,7 @@ package repository
 
 const (
 	queryGetAll  = "SELECT id, zip_code, locality_name, province_name, country_name FROM localities"
-	queryGetById = "SELECT zip_code, id, locality_name, province_name, country_name FROM localities WHERE id = ?"
+	queryGetById = "SELECT id, zip_code, locality_name, province_name, country_name FROM localities WHERE id = ?"
 	queryCreate  = "INSERT INTO localities (zip_code, locality_name, province_name, country_name) VALUES (?, ?, ?, ?)"
 	queryUpdate  = "UPDATE localities SET zip_code = ?, locality_name = ?, province_name = ?, country_name = ? WHERE id = ?"
 	queryDelete  = "DELETE FROM localities WHERE id = ?"
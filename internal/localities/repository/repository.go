@@ -9,6 +9,7 @@ import (
 	"github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 )
 
 type repository struct {
@@ -17,7 +18,7 @@ type repository struct {
 
 func (r *repository) GetAll(ctx context.Context) ([]domain.Locality, error) {
 	var localities []domain.Locality
-	rows, err := r.db.QueryContext(ctx, queryGetAll)
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, queryGetAll)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return []domain.Locality{}, err
@@ -45,7 +46,7 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Locality, error) {
 }
 
 func (r *repository) GetById(ctx context.Context, id int) (domain.Locality, error) {
-	row := r.db.QueryRowContext(ctx, queryGetById, id)
+	row := transaction.From(ctx, r.db).QueryRowContext(ctx, queryGetById, id)
 
 	locality := domain.Locality{}
 
@@ -74,7 +75,7 @@ func (r *repository) GetBySellers(ctx context.Context, id int) ([]domain.Sellers
 	var sellersByLocality []domain.SellersByLocality
 
 	if id != 0 {
-		row := r.db.QueryRowContext(ctx, queryGetBySeller, id)
+		row := transaction.From(ctx, r.db).QueryRowContext(ctx, queryGetBySeller, id)
 
 		var sellers domain.SellersByLocality
 
@@ -95,7 +96,7 @@ func (r *repository) GetBySellers(ctx context.Context, id int) ([]domain.Sellers
 
 		sellersByLocality = append(sellersByLocality, sellers)
 	} else {
-		rows, err := r.db.QueryContext(ctx, queryGetBySellers)
+		rows, err := transaction.From(ctx, r.db).QueryContext(ctx, queryGetBySellers)
 		if err != nil {
 			return []domain.SellersByLocality{}, err
 		}
@@ -126,7 +127,7 @@ func (r *repository) Create(ctx context.Context, zipCode, localityName, province
 		CountryName:  countryName,
 	}
 
-	result, err := r.db.ExecContext(
+	result, err := transaction.From(ctx, r.db).ExecContext(
 		ctx,
 		queryCreate,
 		zipCode,
@@ -154,7 +155,7 @@ func (r *repository) GetByCarriers(ctx context.Context, id int) ([]domain.Carrie
 	var carriersByLocality []domain.CarriersByLocality
 
 	if id != 0 {
-		row := r.db.QueryRowContext(ctx, queryGetByCarrier, id)
+		row := transaction.From(ctx, r.db).QueryRowContext(ctx, queryGetByCarrier, id)
 
 		var carriers domain.CarriersByLocality
 
@@ -173,7 +174,7 @@ func (r *repository) GetByCarriers(ctx context.Context, id int) ([]domain.Carrie
 		}
 		carriersByLocality = append(carriersByLocality, carriers)
 	} else {
-		rows, err := r.db.QueryContext(ctx, queryGetByCarriers)
+		rows, err := transaction.From(ctx, r.db).QueryContext(ctx, queryGetByCarriers)
 		if err != nil {
 			return []domain.CarriersByLocality{}, err
 		}
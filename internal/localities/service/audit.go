@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain"
+)
+
+type auditedService struct {
+	domain.LocalityService
+	audit auditDomain.Service
+}
+
+// NewAuditedService records every change made through s in the audit trail.
+func NewAuditedService(s domain.LocalityService, audit auditDomain.Service) domain.LocalityService {
+	return &auditedService{s, audit}
+}
+
+func (s *auditedService) Create(ctx context.Context, zipCode, localityName, provinceName, countryName string) (domain.Locality, error) {
+	locality, err := s.LocalityService.Create(ctx, zipCode, localityName, provinceName, countryName)
+	if err != nil {
+		return domain.Locality{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityLocalities, locality.Id, auditDomain.ActionCreate, nil, locality)
+
+	return locality, nil
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	mock_audit "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditedService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := mock_domain.NewMockLocalityService(ctrl)
+	audit := mock_audit.NewMockService(ctrl)
+
+	locality := domain.Locality{Id: 1, ZipCode: "6700", LocalityName: "Salvador"}
+	service.EXPECT().Create(gomock.Any(), "6700", "Salvador", "Bahia", "Brasil").Return(locality, nil)
+	audit.EXPECT().Record(gomock.Any(), auditDomain.EntityLocalities, 1, auditDomain.ActionCreate, nil, locality)
+
+	_, err := NewAuditedService(service, audit).Create(context.TODO(), "6700", "Salvador", "Bahia", "Brasil")
+	assert.NoError(t, err)
+}
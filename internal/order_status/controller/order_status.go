@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/patch"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+)
+
+type OrderStatusController struct {
+	service domain.Service
+}
+
+func NewOrderStatusController(s domain.Service) *OrderStatusController {
+	return &OrderStatusController{
+		service: s,
+	}
+}
+
+type orderStatusRequest struct {
+	Description string `json:"description" binding:"required"`
+	IsInitial   bool   `json:"is_initial"`
+	IsTerminal  bool   `json:"is_terminal"`
+}
+
+type updateOrderStatusRequest struct {
+	Description *string `json:"description" binding:"omitempty,min=1"`
+	IsInitial   *bool   `json:"is_initial"`
+	IsTerminal  *bool   `json:"is_terminal"`
+}
+
+// status maps the errors of the order status service to HTTP statuses.
+func status(err error) int {
+	errNF := &domain.ErrorNotFound{}
+	if errors.As(err, &errNF) {
+		return http.StatusNotFound
+	}
+
+	errCF := &domain.ErrorConflict{}
+	if errors.As(err, &errCF) {
+		return http.StatusConflict
+	}
+
+	if errors.Is(err, domain.ErrInitialTerminal) {
+		return http.StatusUnprocessableEntity
+	}
+
+	if errors.Is(err, etag.ErrMismatch) {
+		return http.StatusPreconditionFailed
+	}
+
+	return http.StatusInternalServerError
+}
+
+// ListOrderStatuses godoc
+// @Summary      List all order statuses
+// @Description  List the statuses a purchase order can be in
+// @Tags         order_status
+// @Produce      json
+// @Param        include_deleted  query  bool  false  "Include soft deleted order statuses"
+// @Success      200  {array}   domain.OrderStatus
+// @Failure      400  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /api/v1/orderStatuses [get]
+func (o *OrderStatusController) GetAll(c *gin.Context) {
+	if err := softdelete.FromQuery(c); err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+		return
+	}
+
+	statuses, err := o.service.GetAll(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DecodeError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewResponse(statuses))
+}
+
+// GetOrderStatus godoc
+// @Summary      Get an order status by id
+// @Tags         order_status
+// @Produce      json
+// @Param        id               path   int   true   "Order status id"
+// @Param        include_deleted  query  bool  false  "Include soft deleted order statuses"
+// @Success      200  {object}  domain.OrderStatus
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Router       /api/v1/orderStatuses/{id} [get]
+func (o *OrderStatusController) GetById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+		return
+	}
+
+	if err := softdelete.FromQuery(c); err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+		return
+	}
+
+	orderStatus, err := o.service.GetById(c, id)
+	if err != nil {
+		c.JSON(status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	etag.Set(c, orderStatus.Version)
+	c.JSON(http.StatusOK, response.NewResponse(orderStatus))
+}
+
+// CreateOrderStatus godoc
+// @Summary      Create an order status
+// @Description  Create an order status. Descriptions are unique, ignoring case, and a status cannot be both initial and terminal
+// @Tags         order_status
+// @Accept       json
+// @Produce      json
+// @Param        orderStatus  body      orderStatusRequest  true  "Order status to be created"
+// @Success      201          {object}  domain.OrderStatus
+// @Failure      409          {object}  response.Response
+// @Failure      422          {object}  response.Response
+// @Router       /api/v1/orderStatuses [post]
+func (o *OrderStatusController) Create(c *gin.Context) {
+	var req orderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+		return
+	}
+
+	orderStatus, err := o.service.Create(c, domain.OrderStatus{
+		Description: req.Description,
+		IsInitial:   req.IsInitial,
+		IsTerminal:  req.IsTerminal,
+	})
+	if err != nil {
+		c.JSON(status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.NewResponse(orderStatus))
+}
+
+// UpdateOrderStatus godoc
+// @Summary      Update an order status
+// @Description  Update an order status with a JSON merge patch (RFC 7396)
+// @Tags         order_status
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                       true   "Order status id"
+// @Param        If-Match     header    string                    true   "ETag of the order status being updated"
+// @Param        orderStatus  body      updateOrderStatusRequest  false  "Fields to update"
+// @Success      200          {object}  domain.OrderStatus
+// @Failure      400          {object}  response.Response
+// @Failure      404          {object}  response.Response
+// @Failure      409          {object}  response.Response
+// @Failure      412          {object}  response.Response
+// @Failure      422          {object}  response.Response
+// @Failure      428          {object}  response.Response
+// @Router       /api/v1/orderStatuses/{id} [patch]
+func (o *OrderStatusController) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+		return
+	}
+
+	if err := etag.FromIfMatch(c); err != nil {
+		c.JSON(etag.Status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	var req updateOrderStatusRequest
+	if err := patch.Bind(c, &req); err != nil {
+		c.JSON(patch.Status(err), validation.NewErrorResponse(err))
+		return
+	}
+
+	orderStatus, err := o.service.Update(c, id, domain.OrderStatusPatch(req))
+	if err != nil {
+		c.JSON(status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	etag.Set(c, orderStatus.Version)
+	c.JSON(http.StatusOK, response.NewResponse(orderStatus))
+}
+
+// DeleteOrderStatus godoc
+// @Summary      Delete an order status
+// @Description  Soft delete an order status. Statuses of existing purchase orders are not deleted
+// @Tags         order_status
+// @Param        id        path    int     true  "Order status id"
+// @Param        If-Match  header  string  true  "ETag of the order status being deleted"
+// @Success      204  "Successfully deleted"
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Failure      412  {object}  response.Response
+// @Failure      428  {object}  response.Response
+// @Router       /api/v1/orderStatuses/{id} [delete]
+func (o *OrderStatusController) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+		return
+	}
+
+	if err := etag.FromIfMatch(c); err != nil {
+		c.JSON(etag.Status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	if err := o.service.Delete(c, id); err != nil {
+		var dependentsErr *dependents.Error
+		if errors.As(err, &dependentsErr) {
+			c.JSON(http.StatusConflict, response.DecodeDependents(dependentsErr))
+			return
+		}
+
+		c.JSON(status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreOrderStatus godoc
+// @Summary      Restore an order status
+// @Description  Restore a deleted order status, selecting by id
+// @Tags         order_status
+// @Produce      json
+// @Param        id   path      int  true  "Order status id"
+// @Success      200  {object}  domain.OrderStatus
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /api/v1/orderStatuses/{id}/restore [post]
+func (o *OrderStatusController) Restore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+		return
+	}
+
+	orderStatus, err := o.service.Restore(c, id)
+	if err != nil {
+		c.JSON(status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	etag.Set(c, orderStatus.Version)
+	c.JSON(http.StatusOK, response.NewResponse(orderStatus))
+}
@@ -0,0 +1,324 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	pathOrderStatuses   = "/api/v1/orderStatuses/"
+	pathIdOrderStatuses = "/api/v1/orderStatuses/:id"
+	pathRestoreStatuses = "/api/v1/orderStatuses/:id/restore"
+)
+
+var pending = domain.OrderStatus{Id: 1, Description: "pending", IsInitial: true, Version: 1}
+
+func mockOrderStatus(t *testing.T) (*mock_domain.MockService, *OrderStatusController, *gin.Engine) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock_domain.NewMockService(ctrl)
+	return service, NewOrderStatusController(service), gin.New()
+}
+
+// ifMatch sends tag as the ETag the request expects the order status to have.
+func ifMatch(req *http.Request, tag string) *http.Request {
+	req.Header.Set(etag.IfMatchHeader, tag)
+	return req
+}
+
+func TestOrderStatus_GetAll(t *testing.T) {
+	service, handler, api := mockOrderStatus(t)
+	api.GET(pathOrderStatuses, handler.GetAll)
+
+	service.EXPECT().GetAll(gomock.Any()).Return([]domain.OrderStatus{pending}, nil)
+
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathOrderStatuses, nil))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	body := struct {
+		Data []domain.OrderStatus `json:"data"`
+	}{}
+	json.Unmarshal(resp.Body.Bytes(), &body)
+	assert.Equal(t, []domain.OrderStatus{pending}, body.Data)
+}
+
+func TestOrderStatus_GetAll_IncludeDeleted(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.GET(pathOrderStatuses, handler.GetAll)
+
+		service.EXPECT().GetAll(gomock.Any()).DoAndReturn(func(ctx context.Context) ([]domain.OrderStatus, error) {
+			assert.True(t, softdelete.IncludeDeleted(ctx))
+			return []domain.OrderStatus{pending}, nil
+		})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathOrderStatuses+"?include_deleted=true", nil))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		_, handler, api := mockOrderStatus(t)
+		api.GET(pathOrderStatuses, handler.GetAll)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathOrderStatuses+"?include_deleted=maybe", nil))
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestOrderStatus_GetById(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.GET(pathIdOrderStatuses, handler.GetById)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(pending, nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathOrderStatuses+"1", nil))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.GET(pathIdOrderStatuses, handler.GetById)
+
+		service.EXPECT().GetById(gomock.Any(), 9).Return(domain.OrderStatus{}, &domain.ErrorNotFound{Id: 9})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathOrderStatuses+"9", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		_, handler, api := mockOrderStatus(t)
+		api.GET(pathIdOrderStatuses, handler.GetById)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathOrderStatuses+"xpto", nil))
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestOrderStatus_Create(t *testing.T) {
+	payload := `{"description": "pending", "is_initial": true}`
+
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.POST(pathOrderStatuses, handler.Create)
+
+		arg := pending
+		arg.Id, arg.Version = 0, 0
+		service.EXPECT().Create(gomock.Any(), arg).Return(pending, nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathOrderStatuses, strings.NewReader(payload)))
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.POST(pathOrderStatuses, handler.Create)
+
+		service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.OrderStatus{}, &domain.ErrorConflict{Description: "pending"})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathOrderStatuses, strings.NewReader(payload)))
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("Initial and terminal", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.POST(pathOrderStatuses, handler.Create)
+
+		service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.OrderStatus{}, domain.ErrInitialTerminal)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathOrderStatuses, strings.NewReader(`{"description": "void", "is_initial": true, "is_terminal": true}`)))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("Missing description", func(t *testing.T) {
+		_, handler, api := mockOrderStatus(t)
+		api.POST(pathOrderStatuses, handler.Create)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathOrderStatuses, strings.NewReader(`{"is_initial": true}`)))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+}
+
+func TestOrderStatus_Update(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.PATCH(pathIdOrderStatuses, handler.Update)
+
+		terminal := true
+		service.EXPECT().Update(gomock.Any(), 2, domain.OrderStatusPatch{IsTerminal: &terminal}).Return(pending, nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodPatch, pathOrderStatuses+"2", strings.NewReader(`{"is_terminal": true}`)), `"1"`))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, etag.Format(pending.Version), resp.Header().Get(etag.Header))
+	})
+
+	t.Run("Without If-Match", func(t *testing.T) {
+		_, handler, api := mockOrderStatus(t)
+		api.PATCH(pathIdOrderStatuses, handler.Update)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPatch, pathOrderStatuses+"2", strings.NewReader(`{"description": "shipped"}`)))
+
+		assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+	})
+
+	t.Run("Changed since read", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.PATCH(pathIdOrderStatuses, handler.Update)
+
+		service.EXPECT().Update(gomock.Any(), 2, gomock.Any()).DoAndReturn(func(ctx context.Context, id int, arg domain.OrderStatusPatch) (domain.OrderStatus, error) {
+			version, _ := etag.Version(ctx)
+			assert.Equal(t, 1, version)
+			return domain.OrderStatus{}, etag.ErrMismatch
+		})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodPatch, pathOrderStatuses+"2", strings.NewReader(`{"description": "shipped"}`)), `"1"`))
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.PATCH(pathIdOrderStatuses, handler.Update)
+
+		service.EXPECT().Update(gomock.Any(), 9, gomock.Any()).Return(domain.OrderStatus{}, &domain.ErrorNotFound{Id: 9})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodPatch, pathOrderStatuses+"9", strings.NewReader(`{"description": "shipped"}`)), `"1"`))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestOrderStatus_Delete(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.DELETE(pathIdOrderStatuses, handler.Delete)
+
+		service.EXPECT().Delete(gomock.Any(), 2).Return(nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodDelete, pathOrderStatuses+"2", nil), `"1"`))
+
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+	})
+
+	t.Run("Without If-Match", func(t *testing.T) {
+		_, handler, api := mockOrderStatus(t)
+		api.DELETE(pathIdOrderStatuses, handler.Delete)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, pathOrderStatuses+"2", nil))
+
+		assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+	})
+
+	t.Run("Changed since read", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.DELETE(pathIdOrderStatuses, handler.Delete)
+
+		service.EXPECT().Delete(gomock.Any(), 2).Return(etag.ErrMismatch)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodDelete, pathOrderStatuses+"2", nil), `"1"`))
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+	})
+
+	t.Run("In use", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.DELETE(pathIdOrderStatuses, handler.Delete)
+
+		service.EXPECT().Delete(gomock.Any(), 2).Return(dependents.Check("order status", 2, dependents.Dependent{Type: "purchase_orders", Count: 3}))
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodDelete, pathOrderStatuses+"2", nil), `"1"`))
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.DELETE(pathIdOrderStatuses, handler.Delete)
+
+		service.EXPECT().Delete(gomock.Any(), 2).Return(errors.New("some error"))
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodDelete, pathOrderStatuses+"2", nil), `"1"`))
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func TestOrderStatus_Restore(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.POST(pathRestoreStatuses, handler.Restore)
+
+		service.EXPECT().Restore(gomock.Any(), 1).Return(pending, nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathOrderStatuses+"1/restore", nil))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		service, handler, api := mockOrderStatus(t)
+		api.POST(pathRestoreStatuses, handler.Restore)
+
+		service.EXPECT().Restore(gomock.Any(), 9).Return(domain.OrderStatus{}, &domain.ErrorNotFound{Id: 9})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathOrderStatuses+"9/restore", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		_, handler, api := mockOrderStatus(t)
+		api.POST(pathRestoreStatuses, handler.Restore)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathOrderStatuses+"xpto/restore", nil))
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+)
+
+// OrderStatus is a step of the purchase order lifecycle. Orders are created
+// in an initial status, and are no longer editable once in a terminal one.
+type OrderStatus struct {
+	Id          int           `json:"id"`
+	Description string        `json:"description"`
+	IsInitial   bool          `json:"is_initial"`
+	IsTerminal  bool          `json:"is_terminal"`
+	DeletedAt   date.DateTime `json:"deleted_at"`
+	Version     int           `json:"version"`
+}
+
+// OrderStatusPatch holds the fields of a partial update. Nil fields are left
+// untouched.
+type OrderStatusPatch struct {
+	Description *string `json:"description"`
+	IsInitial   *bool   `json:"is_initial"`
+	IsTerminal  *bool   `json:"is_terminal"`
+}
+
+// ErrInitialTerminal is returned for a status that would be both initial and
+// terminal, since orders created in it could never change.
+var ErrInitialTerminal = errors.New("an order status cannot be both initial and terminal")
+
+//go:generate mockgen -source=./domain.go -destination=./mock/domain_mock.go
+type Repository interface {
+	GetAll(ctx context.Context) ([]OrderStatus, error)
+	GetById(ctx context.Context, id int) (OrderStatus, error)
+	Create(ctx context.Context, arg OrderStatus) (OrderStatus, error)
+	Update(ctx context.Context, arg OrderStatus) (OrderStatus, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Dependents(ctx context.Context, id int) ([]dependents.Dependent, error)
+}
+
+type Service interface {
+	GetAll(ctx context.Context) ([]OrderStatus, error)
+	GetById(ctx context.Context, id int) (OrderStatus, error)
+	Create(ctx context.Context, arg OrderStatus) (OrderStatus, error)
+	Update(ctx context.Context, id int, arg OrderStatusPatch) (OrderStatus, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) (OrderStatus, error)
+}
+
+type ErrorNotFound struct {
+	Id int
+}
+
+func (e *ErrorNotFound) Error() string {
+	return fmt.Sprintf("order status %d not found", e.Id)
+}
+
+type ErrorConflict struct {
+	Description string
+}
+
+func (e *ErrorConflict) Error() string {
+	return fmt.Sprintf("an order status described as %q already exists", e.Description)
+}
@@ -0,0 +1,17 @@
+package repository
+
+const (
+	querySelect      = "SELECT id, description, is_initial, is_terminal, deleted_at, version FROM order_status"
+	queryGetAll      = querySelect + " WHERE deleted_at IS NULL ORDER BY id"
+	queryGetById     = querySelect + " WHERE id = ? AND deleted_at IS NULL"
+	queryCreate      = "INSERT INTO order_status (description, is_initial, is_terminal) VALUES (?, ?, ?)"
+	queryUpdate      = "UPDATE order_status SET description = ?, is_initial = ?, is_terminal = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL AND version = ?"
+	queryDelete      = "UPDATE order_status SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL"
+	queryRestore     = "UPDATE order_status SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL"
+	queryCountOrders = "SELECT COUNT(*) FROM purchase_orders WHERE order_status_id = ?"
+
+	// Variants that also read soft deleted statuses, used when the request
+	// has ?include_deleted=true.
+	queryGetAllWithDeleted  = querySelect + " ORDER BY id"
+	queryGetByIdWithDeleted = querySelect + " WHERE id = ?"
+)
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
+)
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domain.Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (domain.OrderStatus, error) {
+	var status domain.OrderStatus
+	err := row.Scan(
+		&status.Id,
+		&status.Description,
+		&status.IsInitial,
+		&status.IsTerminal,
+		&status.DeletedAt,
+		&status.Version,
+	)
+	return status, err
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.OrderStatus, error) {
+	query := queryGetAll
+	if softdelete.IncludeDeleted(ctx) {
+		query = queryGetAllWithDeleted
+	}
+
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := []domain.OrderStatus{}
+	for rows.Next() {
+		status, err := scan(rows)
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
+}
+
+func (r *repository) GetById(ctx context.Context, id int) (domain.OrderStatus, error) {
+	query := queryGetById
+	if softdelete.IncludeDeleted(ctx) {
+		query = queryGetByIdWithDeleted
+	}
+
+	status, err := scan(transaction.From(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.OrderStatus{}, &domain.ErrorNotFound{Id: id}
+	}
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.OrderStatus{}, err
+	}
+	return status, nil
+}
+
+func (r *repository) Create(ctx context.Context, arg domain.OrderStatus) (domain.OrderStatus, error) {
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryCreate, arg.Description, arg.IsInitial, arg.IsTerminal)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.OrderStatus{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.OrderStatus{}, err
+	}
+
+	arg.Id = int(id)
+	arg.Version = 1
+	return arg, nil
+}
+
+func (r *repository) Update(ctx context.Context, arg domain.OrderStatus) (domain.OrderStatus, error) {
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryUpdate, arg.Description, arg.IsInitial, arg.IsTerminal, arg.Id, arg.Version)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.OrderStatus{}, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return domain.OrderStatus{}, err
+	}
+	// The status was read with this version, so no rows means someone else
+	// wrote it in between.
+	if affected == 0 {
+		return domain.OrderStatus{}, etag.ErrMismatch
+	}
+
+	arg.Version++
+	return arg, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id int) error {
+	return r.exec(ctx, id, queryDelete, date.Now(), id)
+}
+
+func (r *repository) Restore(ctx context.Context, id int) error {
+	return r.exec(ctx, id, queryRestore, id)
+}
+
+// exec runs a statement that must change the order status id, requiring the
+// version the client read, when there is one.
+func (r *repository) exec(ctx context.Context, id int, query string, args ...interface{}) error {
+	version, versioned := etag.Version(ctx)
+	if versioned {
+		query, args = query+etag.Condition, append(args, version)
+	}
+
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// The status is there, just not in the version the request expects.
+		if versioned {
+			if _, err := r.GetById(ctx, id); err == nil {
+				return etag.ErrMismatch
+			}
+		}
+		return &domain.ErrorNotFound{Id: id}
+	}
+	return nil
+}
+
+// Dependents counts the purchase orders in the status.
+func (r *repository) Dependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	var orders int
+	if err := transaction.From(ctx, r.db).QueryRowContext(ctx, queryCountOrders, id).Scan(&orders); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	return []dependents.Dependent{{Type: "purchase_orders", Count: orders}}, nil
+}
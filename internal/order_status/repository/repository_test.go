@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	columns   = []string{"id", "description", "is_initial", "is_terminal", "deleted_at", "version"}
+	pending   = domain.OrderStatus{Id: 1, Description: "pending", IsInitial: true, Version: 1}
+	delivered = domain.OrderStatus{Id: 2, Description: "delivered", IsTerminal: true, Version: 3}
+)
+
+func TestRepository_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAll)).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(pending.Id, pending.Description, pending.IsInitial, pending.IsTerminal, nil, pending.Version).
+		AddRow(delivered.Id, delivered.Description, delivered.IsInitial, delivered.IsTerminal, nil, delivered.Version))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAll)).WillReturnError(errors.New("some error"))
+
+	repository := NewRepository(db)
+
+	result, err := repository.GetAll(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.OrderStatus{pending, delivered}, result)
+
+	_, err = repository.GetAll(context.TODO())
+	assert.Error(t, err)
+}
+
+func TestRepository_GetById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WithArgs(2).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(delivered.Id, delivered.Description, delivered.IsInitial, delivered.IsTerminal, nil, delivered.Version))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WithArgs(9).WillReturnRows(sqlmock.NewRows(columns))
+
+	repository := NewRepository(db)
+
+	result, err := repository.GetById(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, delivered, result)
+
+	_, err = repository.GetById(context.TODO(), 9)
+	assert.EqualError(t, err, "order status 9 not found")
+}
+
+func TestRepository_IncludeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAllWithDeleted) + "$").WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(pending.Id, pending.Description, pending.IsInitial, pending.IsTerminal, nil, pending.Version).
+		AddRow(delivered.Id, delivered.Description, delivered.IsInitial, delivered.IsTerminal, "2022-07-09 10:00:00", delivered.Version))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetByIdWithDeleted) + "$").WithArgs(2).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(delivered.Id, delivered.Description, delivered.IsInitial, delivered.IsTerminal, "2022-07-09 10:00:00", delivered.Version))
+
+	repository := NewRepository(db)
+	ctx := softdelete.WithDeleted(context.TODO())
+
+	result, err := repository.GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.True(t, result[0].DeletedAt.IsZero())
+	assert.False(t, result[1].DeletedAt.IsZero())
+
+	status, err := repository.GetById(ctx, 2)
+	assert.NoError(t, err)
+	assert.False(t, status.DeletedAt.IsZero())
+}
+
+func TestRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryCreate)).
+		WithArgs(delivered.Description, delivered.IsInitial, delivered.IsTerminal).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	arg := delivered
+	arg.Id, arg.Version = 0, 0
+
+	result, err := NewRepository(db).Create(context.TODO(), arg)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Id)
+	assert.Equal(t, 1, result.Version)
+}
+
+func TestRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).
+		WithArgs(delivered.Description, delivered.IsInitial, delivered.IsTerminal, delivered.Id, delivered.Version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).
+		WithArgs(delivered.Description, delivered.IsInitial, delivered.IsTerminal, delivered.Id, delivered.Version).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repository := NewRepository(db)
+
+	result, err := repository.Update(context.TODO(), delivered)
+	assert.NoError(t, err)
+	assert.Equal(t, delivered.Version+1, result.Version)
+
+	// Someone else wrote the status after it was read.
+	_, err = repository.Update(context.TODO(), delivered)
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+}
+
+func TestRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryDelete)).WithArgs(sqlmock.AnyArg(), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryDelete)).WithArgs(sqlmock.AnyArg(), 9).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repository := NewRepository(db)
+
+	assert.NoError(t, repository.Delete(context.TODO(), 2))
+	assert.EqualError(t, repository.Delete(context.TODO(), 9), "order status 9 not found")
+}
+
+func TestRepository_Delete_Versioned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	query := regexp.QuoteMeta(queryDelete+etag.Condition) + "$"
+	mock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), 2, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), 2, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WithArgs(2).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(delivered.Id, delivered.Description, delivered.IsInitial, delivered.IsTerminal, nil, delivered.Version))
+	mock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), 9, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WithArgs(9).WillReturnRows(sqlmock.NewRows(columns))
+
+	repository := NewRepository(db)
+
+	assert.NoError(t, repository.Delete(etag.WithVersion(context.TODO(), 3), 2))
+	assert.ErrorIs(t, repository.Delete(etag.WithVersion(context.TODO(), 2), 2), etag.ErrMismatch)
+	assert.EqualError(t, repository.Delete(etag.WithVersion(context.TODO(), 1), 9), "order status 9 not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryRestore)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryRestore)).WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repository := NewRepository(db)
+
+	assert.NoError(t, repository.Restore(context.TODO(), 2))
+	assert.EqualError(t, repository.Restore(context.TODO(), 9), "order status 9 not found")
+}
+
+func TestRepository_Dependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryCountOrders)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	result, err := NewRepository(db).Dependents(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []dependents.Dependent{{Type: "purchase_orders", Count: 5}}, result)
+}
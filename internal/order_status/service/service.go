@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+)
+
+type service struct {
+	repository domain.Repository
+}
+
+func NewService(r domain.Repository) domain.Service {
+	return &service{
+		repository: r,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.OrderStatus, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *service) GetById(ctx context.Context, id int) (domain.OrderStatus, error) {
+	return s.repository.GetById(ctx, id)
+}
+
+func (s *service) Create(ctx context.Context, arg domain.OrderStatus) (domain.OrderStatus, error) {
+	if err := s.validate(ctx, arg); err != nil {
+		return domain.OrderStatus{}, err
+	}
+
+	return s.repository.Create(ctx, arg)
+}
+
+func (s *service) Update(ctx context.Context, id int, arg domain.OrderStatusPatch) (domain.OrderStatus, error) {
+	status, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return domain.OrderStatus{}, err
+	}
+
+	if err := etag.Check(ctx, status.Version); err != nil {
+		return domain.OrderStatus{}, err
+	}
+
+	if arg.Description != nil {
+		status.Description = *arg.Description
+	}
+	if arg.IsInitial != nil {
+		status.IsInitial = *arg.IsInitial
+	}
+	if arg.IsTerminal != nil {
+		status.IsTerminal = *arg.IsTerminal
+	}
+
+	if err := s.validate(ctx, status); err != nil {
+		return domain.OrderStatus{}, err
+	}
+
+	return s.repository.Update(ctx, status)
+}
+
+// Delete soft deletes the status. Statuses of existing purchase orders are
+// never deleted.
+func (s *service) Delete(ctx context.Context, id int) error {
+	found, err := s.repository.Dependents(ctx, id)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	if err := dependents.Check("order status", id, found...); err != nil {
+		return err
+	}
+
+	return s.repository.Delete(ctx, id)
+}
+
+func (s *service) Restore(ctx context.Context, id int) (domain.OrderStatus, error) {
+	if err := s.repository.Restore(ctx, id); err != nil {
+		return domain.OrderStatus{}, err
+	}
+
+	return s.repository.GetById(ctx, id)
+}
+
+// validate checks the flags of arg and that no other status has its
+// description, ignoring case. Deleted statuses keep their descriptions, so
+// they can be restored.
+func (s *service) validate(ctx context.Context, arg domain.OrderStatus) error {
+	if arg.IsInitial && arg.IsTerminal {
+		return domain.ErrInitialTerminal
+	}
+
+	statuses, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	for _, status := range statuses {
+		if status.Id != arg.Id && strings.EqualFold(status.Description, arg.Description) {
+			return &domain.ErrorConflict{Description: arg.Description}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	pending   = domain.OrderStatus{Id: 1, Description: "pending", IsInitial: true}
+	delivered = domain.OrderStatus{Id: 2, Description: "delivered", IsTerminal: true, Version: 2}
+)
+
+func callMock(t *testing.T) (*mock_domain.MockRepository, domain.Service, context.Context) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mock_domain.NewMockRepository(ctrl)
+	return repository, NewService(repository), context.Background()
+}
+
+func TestService_Create(t *testing.T) {
+	arg := domain.OrderStatus{Description: "shipped"}
+
+	t.Run("OK", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		created := arg
+		created.Id = 3
+		repository.EXPECT().GetAll(softdelete.WithDeleted(ctx)).Return([]domain.OrderStatus{pending, delivered}, nil)
+		repository.EXPECT().Create(ctx, arg).Return(created, nil)
+
+		result, err := service.Create(ctx, arg)
+		assert.NoError(t, err)
+		assert.Equal(t, created, result)
+	})
+
+	t.Run("Description taken", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().GetAll(softdelete.WithDeleted(ctx)).Return([]domain.OrderStatus{pending, delivered}, nil)
+
+		taken := arg
+		taken.Description = "Pending"
+		_, err := service.Create(ctx, taken)
+
+		var errConflict *domain.ErrorConflict
+		assert.ErrorAs(t, err, &errConflict)
+	})
+
+	t.Run("Description of a deleted status", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		deleted := delivered
+		deleted.DeletedAt = date.Now()
+		repository.EXPECT().GetAll(softdelete.WithDeleted(ctx)).Return([]domain.OrderStatus{pending, deleted}, nil)
+
+		taken := arg
+		taken.Description = "delivered"
+		_, err := service.Create(ctx, taken)
+
+		var errConflict *domain.ErrorConflict
+		assert.ErrorAs(t, err, &errConflict)
+	})
+
+	t.Run("Initial and terminal", func(t *testing.T) {
+		_, service, ctx := callMock(t)
+
+		both := arg
+		both.IsInitial, both.IsTerminal = true, true
+		_, err := service.Create(ctx, both)
+		assert.ErrorIs(t, err, domain.ErrInitialTerminal)
+	})
+}
+
+func TestService_Update(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		description := "received"
+		updated := delivered
+		updated.Description = description
+
+		repository.EXPECT().GetById(ctx, 2).Return(delivered, nil)
+		repository.EXPECT().GetAll(softdelete.WithDeleted(ctx)).Return([]domain.OrderStatus{pending, delivered}, nil)
+		repository.EXPECT().Update(ctx, updated).Return(updated, nil)
+
+		result, err := service.Update(ctx, 2, domain.OrderStatusPatch{Description: &description})
+		assert.NoError(t, err)
+		assert.Equal(t, updated, result)
+	})
+
+	t.Run("Initial and terminal", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		initial := true
+		repository.EXPECT().GetById(ctx, 2).Return(delivered, nil)
+
+		_, err := service.Update(ctx, 2, domain.OrderStatusPatch{IsInitial: &initial})
+		assert.ErrorIs(t, err, domain.ErrInitialTerminal)
+	})
+
+	t.Run("Changed since read", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		ctx = etag.WithVersion(ctx, 1)
+		repository.EXPECT().GetById(ctx, 2).Return(delivered, nil)
+
+		_, err := service.Update(ctx, 2, domain.OrderStatusPatch{})
+		assert.ErrorIs(t, err, etag.ErrMismatch)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().GetById(ctx, 9).Return(domain.OrderStatus{}, &domain.ErrorNotFound{Id: 9})
+
+		_, err := service.Update(ctx, 9, domain.OrderStatusPatch{})
+		assert.EqualError(t, err, "order status 9 not found")
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().Dependents(ctx, 2).Return([]dependents.Dependent{{Type: "purchase_orders"}}, nil)
+		repository.EXPECT().Delete(ctx, 2).Return(nil)
+
+		assert.NoError(t, service.Delete(ctx, 2))
+	})
+
+	t.Run("In use", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().Dependents(ctx, 2).Return([]dependents.Dependent{{Type: "purchase_orders", Count: 3}}, nil)
+
+		err := service.Delete(ctx, 2)
+		assert.EqualError(t, err, "order status 2 cannot be deleted, it still has 3 purchase_orders")
+	})
+
+	t.Run("Dependents fail", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().Dependents(ctx, 2).Return(nil, errors.New("some error"))
+
+		assert.Error(t, service.Delete(ctx, 2))
+	})
+}
+
+func TestService_Restore(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().Restore(ctx, 2).Return(nil)
+		repository.EXPECT().GetById(ctx, 2).Return(delivered, nil)
+
+		result, err := service.Restore(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, delivered, result)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().Restore(ctx, 9).Return(&domain.ErrorNotFound{Id: 9})
+
+		_, err := service.Restore(ctx, 9)
+		assert.EqualError(t, err, "order status 9 not found")
+	})
+}
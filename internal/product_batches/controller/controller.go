@@ -5,7 +5,10 @@ import (
 	"strconv"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/export"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,6 +23,20 @@ func NewController(pbs domain.ProductBatchesService) *ProductBatchesController {
 	}
 }
 
+// Export godoc
+// @Summary Export product batches
+// @Tags Products
+// @Description stream every product batch as CSV, NDJSON or XLSX
+// @Produce text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv (default), ndjson or xlsx"
+// @Success 200
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/productBatches/export [get]
+func (c *ProductBatchesController) Export() gin.HandlerFunc {
+	return export.Handler("product_batches", c.service.Stream)
+}
+
 // Create godoc
 // @Summary Create product batches
 // @Tags Products
@@ -35,7 +52,19 @@ func (c *ProductBatchesController) Create() gin.HandlerFunc {
 		var request createRequest
 
 		if err := ctx.ShouldBindJSON(&request); err != nil {
-			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			ctx.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+
+		dueDate, err := date.Parse(request.DueDate)
+		if err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+
+		manufacturingDate, err := date.Parse(request.ManufacturingDate)
+		if err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 			return
 		}
 
@@ -43,12 +72,12 @@ func (c *ProductBatchesController) Create() gin.HandlerFunc {
 			ctx,
 			request.BatchNumber,
 			request.CurrentQuantity,
-			request.CurrentTemperature,
-			request.DueDate,
+			*request.CurrentTemperature,
+			dueDate,
 			request.InitialQuantity,
-			request.ManufacturingDate,
-			request.ManufacturingHour,
-			request.MinimumTemperature,
+			manufacturingDate,
+			*request.ManufacturingHour,
+			*request.MinimumTemperature,
 			request.ProductId,
 			request.SectionId,
 		)
@@ -62,9 +91,20 @@ func (c *ProductBatchesController) Create() gin.HandlerFunc {
 	}
 }
 
+// GetBySectionId godoc
+// @Summary Report products by section
+// @Tags Sections
+// @Description report the quantity of products in each section, with its breakdown by product
+// @Produce json
+// @Param id query int false "Section id"
+// @Param warehouse_id query int false "Only the sections of this warehouse"
+// @Success 200 {array} domain.SectionRecords
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/sections/reportProducts [get]
 func (c *ProductBatchesController) GetBySectionId() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		var id int64
+		var id, warehouseId int64
 		var err error
 
 		if stringId, exists := ctx.GetQuery("id"); exists {
@@ -75,7 +115,15 @@ func (c *ProductBatchesController) GetBySectionId() gin.HandlerFunc {
 			}
 		}
 
-		records, err := c.service.GetBySectionId(ctx, int(id))
+		if stringId, exists := ctx.GetQuery("warehouse_id"); exists {
+			warehouseId, err = strconv.ParseInt(stringId, 10, 64)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+				return
+			}
+		}
+
+		records, err := c.service.GetBySectionId(ctx, int(id), int(warehouseId))
 		if err != nil {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -85,15 +133,17 @@ func (c *ProductBatchesController) GetBySectionId() gin.HandlerFunc {
 	}
 }
 
+// createRequest takes the hour and the temperatures as pointers, so required
+// tells a missing value apart from a valid 0.
 type createRequest struct {
 	BatchNumber        int    `json:"batch_number" binding:"required"`
-	CurrentQuantity    int    `json:"current_quantity" binding:"required"`
-	CurrentTemperature int    `json:"current_temperature" binding:"required"`
-	DueDate            string `json:"due_date" binding:"required"`
-	InitialQuantity    int    `json:"initial_quantity" binding:"required"`
-	ManufacturingDate  string `json:"manufacturing_date" binding:"required"`
-	ManufacturingHour  int    `json:"manufacturing_hour" binding:"required"`
-	MinimumTemperature int    `json:"minimum_temperature" binding:"required"`
-	ProductId          int    `json:"product_id" binding:"required"`
-	SectionId          int    `json:"section_id" binding:"required"`
+	CurrentQuantity    int    `json:"current_quantity" binding:"required,ltefield=InitialQuantity"`
+	CurrentTemperature *int   `json:"current_temperature" binding:"required"`
+	DueDate            string `json:"due_date" binding:"required,date,date_gtefield=ManufacturingDate"`
+	InitialQuantity    int    `json:"initial_quantity" binding:"required,min=1"`
+	ManufacturingDate  string `json:"manufacturing_date" binding:"required,date"`
+	ManufacturingHour  *int   `json:"manufacturing_hour" binding:"required,min=0,max=23"`
+	MinimumTemperature *int   `json:"minimum_temperature" binding:"required"`
+	ProductId          int    `json:"product_id" binding:"required,min=1"`
+	SectionId          int    `json:"section_id" binding:"required,min=1"`
 }
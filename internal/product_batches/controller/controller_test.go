@@ -2,6 +2,7 @@ package controller
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/domain"
 	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -35,9 +38,9 @@ func TestController_Create_Ok(t *testing.T) {
 		BatchNumber:        1,
 		CurrentQuantity:    1,
 		CurrentTemperature: 1,
-		DueDate:            "2020-01-01",
+		DueDate:            date.New(2020, 1, 1),
 		InitialQuantity:    1,
-		ManufacturingDate:  "2020-01-01",
+		ManufacturingDate:  date.New(2020, 1, 1),
 		ManufacturingHour:  1,
 		MinimumTemperature: 1,
 		ProductId:          1,
@@ -47,7 +50,7 @@ func TestController_Create_Ok(t *testing.T) {
 	service, handler, api := callMock(t)
 	api.POST(postPath, handler.Create())
 
-	service.EXPECT().Create(gomock.Any(), 1, 1, 1, "2020-01-01", 1, "2020-01-01", 1, 1, 1, 1).Return(&result, nil)
+	service.EXPECT().Create(gomock.Any(), 1, 1, 1, date.New(2020, 1, 1), 1, date.New(2020, 1, 1), 1, 1, 1, 1).Return(&result, nil)
 
 	payload := `{"batch_number":1,"current_quantity":1,"current_temperature":1,"due_date":"2020-01-01","initial_quantity":1,"manufacturing_date":"2020-01-01","manufacturing_hour":1,"minimum_temperature":1,"product_id":1,"section_id":1}`
 	req := httptest.NewRequest(http.MethodPost, postPath, bytes.NewBuffer([]byte(payload)))
@@ -69,11 +72,70 @@ func TestController_Create_Unprocessable(t *testing.T) {
 	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
 }
 
+func TestController_Create_Zeros(t *testing.T) {
+	service, handler, api := callMock(t)
+	api.POST(postPath, handler.Create())
+
+	result := domain.ProductBatch{
+		Id:                1,
+		BatchNumber:       1,
+		CurrentQuantity:   1,
+		DueDate:           date.New(2020, 1, 1),
+		InitialQuantity:   1,
+		ManufacturingDate: date.New(2020, 1, 1),
+		ProductId:         1,
+		SectionId:         1,
+	}
+
+	// A batch made at midnight and kept at 0 degrees is valid.
+	service.EXPECT().Create(gomock.Any(), 1, 1, 0, date.New(2020, 1, 1), 1, date.New(2020, 1, 1), 0, 0, 1, 1).Return(&result, nil)
+
+	payload := `{"batch_number":1,"current_quantity":1,"current_temperature":0,"due_date":"2020-01-01","initial_quantity":1,"manufacturing_date":"2020-01-01","manufacturing_hour":0,"minimum_temperature":0,"product_id":1,"section_id":1}`
+	req := httptest.NewRequest(http.MethodPost, postPath, bytes.NewBuffer([]byte(payload)))
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+}
+
+func TestController_Create_InvalidHour(t *testing.T) {
+	for _, hour := range []string{``, `,"manufacturing_hour":24`, `,"manufacturing_hour":-1`} {
+		_, handler, api := callMock(t)
+		api.POST(postPath, handler.Create())
+
+		payload := `{"batch_number":1,"current_quantity":1,"current_temperature":0,"due_date":"2020-01-01","initial_quantity":1,"manufacturing_date":"2020-01-01","minimum_temperature":0,"product_id":1,"section_id":1` + hour + `}`
+		req := httptest.NewRequest(http.MethodPost, postPath, bytes.NewBuffer([]byte(payload)))
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, hour)
+		assert.Contains(t, resp.Body.String(), `"field":"manufacturing_hour"`, hour)
+	}
+}
+
+func TestController_Create_DueBeforeManufacturing(t *testing.T) {
+	_, handler, api := callMock(t)
+	api.POST(postPath, handler.Create())
+
+	payload := `{"batch_number":1,"current_quantity":1,"current_temperature":1,"due_date":"2019-12-31","initial_quantity":1,"manufacturing_date":"2020-01-01","manufacturing_hour":1,"minimum_temperature":1,"product_id":1,"section_id":1}`
+	req := httptest.NewRequest(http.MethodPost, postPath, bytes.NewBuffer([]byte(payload)))
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	var body response.Response
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, []response.FieldError{
+		{Field: "due_date", Code: "date_gtefield", Message: "must be greater than or equal to manufacturing_date"},
+	}, body.Fields)
+}
+
 func TestController_Create_Conflict(t *testing.T) {
 	service, handler, api := callMock(t)
 	api.POST(postPath, handler.Create())
 
-	service.EXPECT().Create(gomock.Any(), 1, 1, 1, "2020-01-01", 1, "2020-01-01", 1, 1, 1, 1).Return(nil, errors.New("conflict"))
+	service.EXPECT().Create(gomock.Any(), 1, 1, 1, date.New(2020, 1, 1), 1, date.New(2020, 1, 1), 1, 1, 1, 1).Return(nil, errors.New("conflict"))
 
 	payload := `{"batch_number":1,"current_quantity":1,"current_temperature":1,"due_date":"2020-01-01","initial_quantity":1,"manufacturing_date":"2020-01-01","manufacturing_hour":1,"minimum_temperature":1,"product_id":1,"section_id":1}`
 	req := httptest.NewRequest(http.MethodPost, postPath, bytes.NewBuffer([]byte(payload)))
@@ -87,7 +149,7 @@ func TestController_Get_By_Section_Id_OK(t *testing.T) {
 	service, handler, api := callMock(t)
 	api.GET(getPath, handler.GetBySectionId())
 
-	service.EXPECT().GetBySectionId(gomock.Any(), 1).Return([]domain.SectionRecords{}, nil)
+	service.EXPECT().GetBySectionId(gomock.Any(), 1, 0).Return([]domain.SectionRecords{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, getPath+"?id=1", nil)
 	resp := httptest.NewRecorder()
@@ -100,7 +162,7 @@ func TestController_Get_By_Section_Id_Bad_Request(t *testing.T) {
 	_, handler, api := callMock(t)
 	api.GET(getPath, handler.GetBySectionId())
 
-	// service.EXPECT().GetBySectionId(gomock.Any(), 1).Return([]domain.SectionRecords{}, nil)
+	// service.EXPECT().GetBySectionId(gomock.Any(), 1, 0).Return([]domain.SectionRecords{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, getPath+"?id=a", nil)
 	resp := httptest.NewRecorder()
@@ -113,7 +175,7 @@ func TestController_Get_By_Section_Id_Not_Found(t *testing.T) {
 	service, handler, api := callMock(t)
 	api.GET(getPath, handler.GetBySectionId())
 
-	service.EXPECT().GetBySectionId(gomock.Any(), 1).Return(nil, errors.New("not found"))
+	service.EXPECT().GetBySectionId(gomock.Any(), 1, 0).Return(nil, errors.New("not found"))
 
 	req := httptest.NewRequest(http.MethodGet, getPath+"?id=1", nil)
 	resp := httptest.NewRecorder()
@@ -121,3 +183,22 @@ func TestController_Get_By_Section_Id_Not_Found(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
+
+func TestController_Get_By_Section_Id_Warehouse(t *testing.T) {
+	service, handler, api := callMock(t)
+	api.GET(getPath, handler.GetBySectionId())
+
+	service.EXPECT().GetBySectionId(gomock.Any(), 0, 2).Return([]domain.SectionRecords{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, getPath+"?warehouse_id=2", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req = httptest.NewRequest(http.MethodGet, getPath+"?warehouse_id=a", nil)
+	resp = httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
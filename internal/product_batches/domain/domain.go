@@ -2,36 +2,63 @@ package domain
 
 import (
 	"context"
+	"time"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 )
 
 type ProductBatch struct {
-	Id                 int    `json:"id,omitempty"`
-	BatchNumber        int    `json:"batch_number,omitempty"`
-	CurrentQuantity    int    `json:"current_quantity,omitempty"`
-	CurrentTemperature int    `json:"current_temperature,omitempty"`
-	DueDate            string `json:"due_date,omitempty"`
-	InitialQuantity    int    `json:"initial_quantity,omitempty"`
-	ManufacturingDate  string `json:"manufacturing_date,omitempty"`
-	ManufacturingHour  int    `json:"manufacturing_hour,omitempty"`
-	MinimumTemperature int    `json:"minimum_temperature,omitempty"`
-	ProductId          int    `json:"product_id,omitempty"`
-	SectionId          int    `json:"section_id,omitempty"`
+	Id                 int       `json:"id,omitempty"`
+	BatchNumber        int       `json:"batch_number,omitempty"`
+	CurrentQuantity    int       `json:"current_quantity,omitempty"`
+	CurrentTemperature int       `json:"current_temperature,omitempty"`
+	DueDate            date.Date `json:"due_date"`
+	InitialQuantity    int       `json:"initial_quantity,omitempty"`
+	ManufacturingDate  date.Date `json:"manufacturing_date"`
+	ManufacturingHour  int       `json:"manufacturing_hour,omitempty"`
+	MinimumTemperature int       `json:"minimum_temperature,omitempty"`
+	ProductId          int       `json:"product_id,omitempty"`
+	SectionId          int       `json:"section_id,omitempty"`
+}
+
+// ManufacturedAt combines the manufacturing date and hour in the configured
+// location.
+func (pb ProductBatch) ManufacturedAt() time.Time {
+	year, month, day := pb.ManufacturingDate.Date()
+	return time.Date(year, month, day, pb.ManufacturingHour, 0, 0, 0, date.Location())
 }
 
+// Expired reports whether the batch is past its due date on the given day.
+func (pb ProductBatch) Expired(on date.Date) bool {
+	return pb.DueDate.Before(on.Time)
+}
+
+// SectionRecords is the stock of a section: the quantity of all its batches
+// and its breakdown by product.
 type SectionRecords struct {
-	SectionId     int `json:"section_id,omitempty"`
-	SectionNumber int `json:"section_number,omitempty"`
-	ProductsCount int `json:"products_count,omitempty"`
+	SectionId     int                     `json:"section_id"`
+	SectionNumber int                     `json:"section_number"`
+	WarehouseId   int                     `json:"warehouse_id"`
+	ProductsCount int                     `json:"products_count"`
+	Products      []SectionProductRecords `json:"products"`
+}
+
+type SectionProductRecords struct {
+	ProductId     int    `json:"product_id"`
+	Description   string `json:"description"`
+	ProductsCount int    `json:"products_count"`
 }
 
 //go:generate mockgen -source=./domain.go -destination=./mock/domain.go
 type ProductBatchesRepository interface {
 	GetAll(ctx context.Context) ([]ProductBatch, error)
-	Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate string, initialQuantity int, manufacturingDate string, manufacturingHour, minimumTemperature, productId, sectionId int) (*ProductBatch, error)
-	GetBySectionId(ctx context.Context, sectionId int) ([]SectionRecords, error)
+	Stream(ctx context.Context, fn func(productBatch ProductBatch) error) error
+	Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate date.Date, initialQuantity int, manufacturingDate date.Date, manufacturingHour, minimumTemperature, productId, sectionId int) (*ProductBatch, error)
+	GetBySectionId(ctx context.Context, sectionId, warehouseId int) ([]SectionRecords, error)
 }
 
 type ProductBatchesService interface {
-	Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate string, initialQuantity int, manufacturingDate string, manufacturingHour, minimumTemperature, productId, sectionId int) (*ProductBatch, error)
-	GetBySectionId(ctx context.Context, sectionId int) ([]SectionRecords, error)
+	Stream(ctx context.Context, fn func(productBatch ProductBatch) error) error
+	Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate date.Date, initialQuantity int, manufacturingDate date.Date, manufacturingHour, minimumTemperature, productId, sectionId int) (*ProductBatch, error)
+	GetBySectionId(ctx context.Context, sectionId, warehouseId int) ([]SectionRecords, error)
 }
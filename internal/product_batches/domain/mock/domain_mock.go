@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	domain "github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/domain"
+	date "github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -36,7 +37,7 @@ func (m *MockProductBatchesRepository) EXPECT() *MockProductBatchesRepositoryMoc
 }
 
 // Create mocks base method.
-func (m *MockProductBatchesRepository) Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate string, initialQuantity int, manufacturingDate string, manufacturingHour, minimumTemperature, productId, sectionId int) (*domain.ProductBatch, error) {
+func (m *MockProductBatchesRepository) Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate date.Date, initialQuantity int, manufacturingDate date.Date, manufacturingHour, minimumTemperature, productId, sectionId int) (*domain.ProductBatch, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, batchNumber, currentQuantity, currentTemperature, dueDate, initialQuantity, manufacturingDate, manufacturingHour, minimumTemperature, productId, sectionId)
 	ret0, _ := ret[0].(*domain.ProductBatch)
@@ -66,18 +67,32 @@ func (mr *MockProductBatchesRepositoryMockRecorder) GetAll(ctx interface{}) *gom
 }
 
 // GetBySectionId mocks base method.
-func (m *MockProductBatchesRepository) GetBySectionId(ctx context.Context, sectionId int) ([]domain.SectionRecords, error) {
+func (m *MockProductBatchesRepository) GetBySectionId(ctx context.Context, sectionId, warehouseId int) ([]domain.SectionRecords, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBySectionId", ctx, sectionId)
+	ret := m.ctrl.Call(m, "GetBySectionId", ctx, sectionId, warehouseId)
 	ret0, _ := ret[0].([]domain.SectionRecords)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBySectionId indicates an expected call of GetBySectionId.
-func (mr *MockProductBatchesRepositoryMockRecorder) GetBySectionId(ctx, sectionId interface{}) *gomock.Call {
+func (mr *MockProductBatchesRepositoryMockRecorder) GetBySectionId(ctx, sectionId, warehouseId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySectionId", reflect.TypeOf((*MockProductBatchesRepository)(nil).GetBySectionId), ctx, sectionId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySectionId", reflect.TypeOf((*MockProductBatchesRepository)(nil).GetBySectionId), ctx, sectionId, warehouseId)
+}
+
+// Stream mocks base method.
+func (m *MockProductBatchesRepository) Stream(ctx context.Context, fn func(domain.ProductBatch) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockProductBatchesRepositoryMockRecorder) Stream(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockProductBatchesRepository)(nil).Stream), ctx, fn)
 }
 
 // MockProductBatchesService is a mock of ProductBatchesService interface.
@@ -104,7 +119,7 @@ func (m *MockProductBatchesService) EXPECT() *MockProductBatchesServiceMockRecor
 }
 
 // Create mocks base method.
-func (m *MockProductBatchesService) Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate string, initialQuantity int, manufacturingDate string, manufacturingHour, minimumTemperature, productId, sectionId int) (*domain.ProductBatch, error) {
+func (m *MockProductBatchesService) Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate date.Date, initialQuantity int, manufacturingDate date.Date, manufacturingHour, minimumTemperature, productId, sectionId int) (*domain.ProductBatch, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, batchNumber, currentQuantity, currentTemperature, dueDate, initialQuantity, manufacturingDate, manufacturingHour, minimumTemperature, productId, sectionId)
 	ret0, _ := ret[0].(*domain.ProductBatch)
@@ -119,16 +134,30 @@ func (mr *MockProductBatchesServiceMockRecorder) Create(ctx, batchNumber, curren
 }
 
 // GetBySectionId mocks base method.
-func (m *MockProductBatchesService) GetBySectionId(ctx context.Context, sectionId int) ([]domain.SectionRecords, error) {
+func (m *MockProductBatchesService) GetBySectionId(ctx context.Context, sectionId, warehouseId int) ([]domain.SectionRecords, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBySectionId", ctx, sectionId)
+	ret := m.ctrl.Call(m, "GetBySectionId", ctx, sectionId, warehouseId)
 	ret0, _ := ret[0].([]domain.SectionRecords)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBySectionId indicates an expected call of GetBySectionId.
-func (mr *MockProductBatchesServiceMockRecorder) GetBySectionId(ctx, sectionId interface{}) *gomock.Call {
+func (mr *MockProductBatchesServiceMockRecorder) GetBySectionId(ctx, sectionId, warehouseId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySectionId", reflect.TypeOf((*MockProductBatchesService)(nil).GetBySectionId), ctx, sectionId, warehouseId)
+}
+
+// Stream mocks base method.
+func (m *MockProductBatchesService) Stream(ctx context.Context, fn func(domain.ProductBatch) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockProductBatchesServiceMockRecorder) Stream(ctx, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySectionId", reflect.TypeOf((*MockProductBatchesService)(nil).GetBySectionId), ctx, sectionId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockProductBatchesService)(nil).Stream), ctx, fn)
 }
@@ -1,8 +1,36 @@
 package repository
 
 const (
-	createQuery              = "INSERT INTO product_batches (batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
-	getQuery                 = "SELECT id, batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id FROM product_batches"
-	singleSectionReportQuery = "SELECT product_batches.current_quantity, sections.section_id, sections.section_number FROM product_batches INNER JOIN sections ON product_batches.section_id = sections.id WHERE product_batches.section_id = ?"
-	allSectionsReportQuery   = "SELECT product_batches.current_quantity, sections.section_id, sections.section_number FROM product_batches INNER JOIN sections ON product_batches.section_id = sections.id"
+	createQuery      = "INSERT INTO product_batches (batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	getQuery         = "SELECT id, batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id FROM product_batches"
+	getBySellerQuery = "SELECT product_batches.id, product_batches.batch_number, product_batches.current_quantity, product_batches.current_temperature, product_batches.due_date, product_batches.initial_quantity, product_batches.manufacturing_date, product_batches.manufacturing_hour, product_batches.minimum_temperature, product_batches.product_id, product_batches.section_id FROM product_batches INNER JOIN products ON product_batches.product_id = products.id WHERE products.seller_id = ?"
+
+	// The section report has a row per section and product, with a NULL
+	// product for sections without batches. Batches of soft deleted
+	// products are left out, so their section may show none. The
+	// conditions go between the query and its grouping.
+	sectionReportQuery = `
+		SELECT
+			section.id,
+			section.section_number,
+			section.warehouse_id,
+			product_batches.product_id,
+			products.description,
+			COALESCE(SUM(product_batches.current_quantity), 0)
+		FROM section
+		LEFT JOIN (
+			product_batches
+			INNER JOIN products ON product_batches.product_id = products.id AND products.deleted_at IS NULL
+		) ON product_batches.section_id = section.id
+		WHERE section.deleted_at IS NULL`
+	sectionReportBySection   = ` AND section.id = ?`
+	sectionReportByWarehouse = ` AND section.warehouse_id = ?`
+	sectionReportGroupBy     = `
+		GROUP BY
+			section.id,
+			section.section_number,
+			section.warehouse_id,
+			product_batches.product_id,
+			products.description
+		ORDER BY section.id, product_batches.product_id`
 )
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/tenant"
 )
 
 type repository struct {
@@ -20,9 +22,25 @@ func NewRepository(db *sql.DB) domain.ProductBatchesRepository {
 func (r repository) GetAll(ctx context.Context) ([]domain.ProductBatch, error) {
 	var productBatches []domain.ProductBatch
 
-	rows, err := r.db.QueryContext(ctx, getQuery)
+	err := r.Stream(ctx, func(productBatch domain.ProductBatch) error {
+		productBatches = append(productBatches, productBatch)
+		return nil
+	})
+
+	return productBatches, err
+}
+
+// Stream hands the product batches GetAll returns to fn one at a time, as
+// they are read.
+func (r repository) Stream(ctx context.Context, fn func(productBatch domain.ProductBatch) error) error {
+	query, args := getQuery, []interface{}{}
+	if sellerId, scoped := tenant.SellerId(ctx); scoped {
+		query, args = getBySellerQuery, append(args, sellerId)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	defer rows.Close()
@@ -43,16 +61,18 @@ func (r repository) GetAll(ctx context.Context) ([]domain.ProductBatch, error) {
 			&product_batch.ProductId,
 			&product_batch.SectionId,
 		); err != nil {
-			return productBatches, err
+			return err
 		}
 
-		productBatches = append(productBatches, product_batch)
+		if err := fn(product_batch); err != nil {
+			return err
+		}
 	}
 
-	return productBatches, nil
+	return rows.Err()
 }
 
-func (r repository) Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate string, initialQuantity int, manufacturingDate string, manufacturingHour, minimumTemperature, productId, sectionId int) (*domain.ProductBatch, error) {
+func (r repository) Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate date.Date, initialQuantity int, manufacturingDate date.Date, manufacturingHour, minimumTemperature, productId, sectionId int) (*domain.ProductBatch, error) {
 	product_batch := domain.ProductBatch{
 		BatchNumber:        batchNumber,
 		CurrentQuantity:    currentQuantity,
@@ -81,76 +101,63 @@ func (r repository) Create(ctx context.Context, batchNumber, currentQuantity, cu
 	return &product_batch, nil
 }
 
-func (r repository) GetBySectionId(ctx context.Context, sectionId int) ([]domain.SectionRecords, error) {
-	records := []domain.SectionRecords{}
-	if sectionId == 0 {
-		mappedRecords := map[int]domain.SectionRecords{}
-
-		rows, err := r.db.QueryContext(ctx, allSectionsReportQuery)
-		if err != nil {
-			return nil, err
-		}
-
-		defer rows.Close()
-
-		for rows.Next() {
-			var section_id int
-			var current_quantity int
-			var section_number int
-
-			if err := rows.Scan(
-				&current_quantity,
-				&section_id,
-				&section_number,
-			); err != nil {
-				return nil, err
-			}
-
-			if _, ok := mappedRecords[section_id]; !ok {
-				mappedRecords[section_id] = domain.SectionRecords{
-					SectionId:     section_id,
-					SectionNumber: section_number,
-					ProductsCount: current_quantity,
-				}
-			} else {
-				updatedRecord := mappedRecords[section_id]
-				updatedRecord.ProductsCount += current_quantity
-				mappedRecords[section_id] = updatedRecord
-			}
-		}
-
-		for _, record := range mappedRecords {
-			records = append(records, record)
-		}
-
-		return records, nil
+// GetBySectionId reports the stock of the sections, or of one section when
+// sectionId is set, optionally only those of a warehouse. Quantities are
+// summed by the database.
+func (r repository) GetBySectionId(ctx context.Context, sectionId, warehouseId int) ([]domain.SectionRecords, error) {
+	query, args := sectionReportQuery, []interface{}{}
+	if sectionId != 0 {
+		query, args = query+sectionReportBySection, append(args, sectionId)
+	}
+	if warehouseId != 0 {
+		query, args = query+sectionReportByWarehouse, append(args, warehouseId)
 	}
 
-	rows, err := r.db.QueryContext(ctx, singleSectionReportQuery, sectionId)
+	rows, err := r.db.QueryContext(ctx, query+sectionReportGroupBy, args...)
 	if err != nil {
 		return nil, err
 	}
 
 	defer rows.Close()
 
-	total := 0
-	sectionNumber := 0
+	records := []domain.SectionRecords{}
 	for rows.Next() {
-		var record domain.SectionRecords
-		err := rows.Scan(&record.ProductsCount, &record.SectionId, &record.SectionNumber)
-		if err != nil {
+		var (
+			record      domain.SectionRecords
+			productId   sql.NullInt64
+			description sql.NullString
+			quantity    int
+		)
+
+		if err := rows.Scan(
+			&record.SectionId,
+			&record.SectionNumber,
+			&record.WarehouseId,
+			&productId,
+			&description,
+			&quantity,
+		); err != nil {
 			return nil, err
 		}
 
-		total += record.ProductsCount
-		sectionNumber = record.SectionNumber
-	}
+		// Rows come ordered by section, so a new section starts a record.
+		if len(records) == 0 || records[len(records)-1].SectionId != record.SectionId {
+			record.Products = []domain.SectionProductRecords{}
+			records = append(records, record)
+		}
 
-	records = append(records, domain.SectionRecords{
-		ProductsCount: total,
-		SectionId:     sectionId,
-		SectionNumber: sectionNumber,
-	})
+		if !productId.Valid {
+			continue
+		}
+
+		last := &records[len(records)-1]
+		last.ProductsCount += quantity
+		last.Products = append(last.Products, domain.SectionProductRecords{
+			ProductId:     int(productId.Int64),
+			Description:   description.String,
+			ProductsCount: quantity,
+		})
+	}
 
-	return records, nil
+	return records, rows.Err()
 }
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MariaDBEnv names the DSN of a MariaDB server the tests in this file may
+// create a scratch database in, e.g. "root:12345@tcp(localhost:3306)/".
+// Without it they are skipped.
+const MariaDBEnv = "MARIADB_TEST_DSN"
+
+const scratchDatabase = "mercado_fresco_section_report_test"
+
+func openMariaDB(t *testing.T) *sql.DB {
+	dsn, ok := os.LookupEnv(MariaDBEnv)
+	if !ok {
+		t.Skipf("%s is not set", MariaDBEnv)
+	}
+
+	server, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+
+	_, err = server.Exec("DROP DATABASE IF EXISTS " + scratchDatabase)
+	require.NoError(t, err)
+	_, err = server.Exec("CREATE DATABASE " + scratchDatabase)
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Exec("DROP DATABASE " + scratchDatabase) })
+
+	db, err := sql.Open("mysql", strings.TrimSuffix(dsn, "/")+"/"+scratchDatabase+"?parseTime=true")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile("testdata/schema.sql")
+	require.NoError(t, err)
+	for _, statement := range strings.Split(string(schema), ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		_, err := db.Exec(statement)
+		require.NoError(t, err)
+	}
+
+	return db
+}
+
+func TestMariaDB_GetBySectionId(t *testing.T) {
+	db := openMariaDB(t)
+	ctx := context.Background()
+
+	exec := func(query string, args ...interface{}) {
+		_, err := db.ExecContext(ctx, query, args...)
+		require.NoError(t, err)
+	}
+
+	section := "INSERT INTO section (id, section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, product_type_id, deleted_at) VALUES (?, ?, 1, 1, 1, 1, 100, ?, 1, ?)"
+	exec(section, 1, 10, 1, nil)
+	exec(section, 2, 20, 1, nil)
+	exec(section, 3, 30, 2, nil)
+	exec(section, 4, 40, 1, "2022-08-01 00:00:00")
+
+	product := "INSERT INTO products (id, product_code, description, width, height, length, net_weight, expiration_rate, recommended_freezing_temperature, freezing_rate, product_type_id, seller_id, deleted_at) VALUES (?, ?, ?, 1, 1, 1, 1, 1, 1, 1, 1, 1, ?)"
+	exec(product, 1, "choc", "Chocolate", nil)
+	exec(product, 2, "ice", "Ice Cream", nil)
+	exec(product, 3, "milk", "Milk", "2022-08-01 00:00:00")
+
+	repository := NewRepository(db)
+	batches := []struct{ number, quantity, product, section int }{
+		{1, 5, 1, 1},
+		{2, 7, 1, 1},
+		{3, 3, 2, 1},
+		{4, 11, 2, 3},
+		{5, 13, 1, 4},
+		// The product is soft deleted, so section 2 stays empty.
+		{6, 17, 3, 2},
+	}
+	for _, batch := range batches {
+		_, err := repository.Create(ctx, batch.number, batch.quantity, 1, date.New(2022, 9, 1), batch.quantity, date.New(2022, 8, 1), 8, 0, batch.product, batch.section)
+		require.NoError(t, err)
+	}
+
+	first := domain.SectionRecords{
+		SectionId:     1,
+		SectionNumber: 10,
+		WarehouseId:   1,
+		ProductsCount: 15,
+		Products: []domain.SectionProductRecords{
+			{ProductId: 1, Description: "Chocolate", ProductsCount: 12},
+			{ProductId: 2, Description: "Ice Cream", ProductsCount: 3},
+		},
+	}
+	empty := domain.SectionRecords{SectionId: 2, SectionNumber: 20, WarehouseId: 1, Products: []domain.SectionProductRecords{}}
+	third := domain.SectionRecords{
+		SectionId:     3,
+		SectionNumber: 30,
+		WarehouseId:   2,
+		ProductsCount: 11,
+		Products: []domain.SectionProductRecords{
+			{ProductId: 2, Description: "Ice Cream", ProductsCount: 11},
+		},
+	}
+
+	testCases := []struct {
+		sectionId, warehouseId int
+		expected               []domain.SectionRecords
+	}{
+		{0, 0, []domain.SectionRecords{first, empty, third}},
+		{1, 0, []domain.SectionRecords{first}},
+		{2, 0, []domain.SectionRecords{empty}},
+		{0, 1, []domain.SectionRecords{first, empty}},
+		{3, 1, []domain.SectionRecords{}},
+		{4, 0, []domain.SectionRecords{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("section %d warehouse %d", tc.sectionId, tc.warehouseId), func(t *testing.T) {
+			records, err := repository.GetBySectionId(ctx, tc.sectionId, tc.warehouseId)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, records)
+		})
+	}
+}
@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/tenant"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,19 +19,14 @@ var (
 		BatchNumber:        1,
 		CurrentQuantity:    2,
 		CurrentTemperature: 3,
-		DueDate:            "2020-01-01",
+		DueDate:            date.New(2020, 1, 1),
 		InitialQuantity:    4,
-		ManufacturingDate:  "2020-01-01",
+		ManufacturingDate:  date.New(2020, 1, 1),
 		ManufacturingHour:  5,
 		MinimumTemperature: 6,
 		ProductId:          7,
 		SectionId:          8,
 	}
-	sampleRecord = domain.SectionRecords{
-		SectionId:     1,
-		SectionNumber: 2,
-		ProductsCount: 5,
-	}
 )
 
 func TestRepository_Create_OK(t *testing.T) {
@@ -55,9 +53,9 @@ func TestRepository_Create_OK(t *testing.T) {
 		1,
 		2,
 		3,
-		"2020-01-01",
+		date.New(2020, 1, 1),
 		4,
-		"2020-01-01",
+		date.New(2020, 1, 1),
 		5,
 		6,
 		7,
@@ -83,9 +81,9 @@ func TestRepository_Create_Conflict(t *testing.T) {
 		1,
 		2,
 		3,
-		"2020-01-01",
+		date.New(2020, 1, 1),
 		4,
-		"2020-01-01",
+		date.New(2020, 1, 1),
 		5,
 		6,
 		7,
@@ -105,9 +103,9 @@ func TestRepository_Get_All(t *testing.T) {
 		sampleBatch.BatchNumber,
 		sampleBatch.CurrentQuantity,
 		sampleBatch.CurrentTemperature,
-		sampleBatch.DueDate,
+		sampleBatch.DueDate.String(),
 		sampleBatch.InitialQuantity,
-		sampleBatch.ManufacturingDate,
+		sampleBatch.ManufacturingDate.String(),
 		sampleBatch.ManufacturingHour,
 		sampleBatch.MinimumTemperature,
 		sampleBatch.ProductId,
@@ -123,42 +121,86 @@ func TestRepository_Get_All(t *testing.T) {
 	assert.Equal(t, []domain.ProductBatch{sampleBatch}, batches)
 }
 
-func TestRepository_Get_By_Single_Section_Id(t *testing.T) {
+func TestRepository_Get_All_Seller_Scoped(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	result := sqlmock.NewRows([]string{"current_quantity", "section_id", "section_number"}).AddRow(
-		sampleRecord.ProductsCount,
-		sampleRecord.SectionId,
-		sampleRecord.SectionNumber,
-	)
+	result := sqlmock.NewRows([]string{"id", "batch_number", "current_quantity", "current_temperature", "due_date", "initial_quantity", "manufacturing_date", "manufacturing_hour", "minimum_temperature", "product_id", "section_id"})
 
-	mock.ExpectQuery(regexp.QuoteMeta(singleSectionReportQuery)).WillReturnRows(result)
+	mock.ExpectQuery(regexp.QuoteMeta(getBySellerQuery)).WithArgs(9).WillReturnRows(result)
 
 	repository := NewRepository(db)
-	records, err := repository.GetBySectionId(context.TODO(), 1)
+	_, err = repository.GetAll(tenant.WithSeller(context.TODO(), 9))
 
 	assert.NoError(t, err)
-	assert.Equal(t, []domain.SectionRecords{sampleRecord}, records)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestRepository_Get_By_All_Sections(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	result := sqlmock.NewRows([]string{"current_quantity", "section_id", "section_number"}).AddRow(
-		sampleRecord.ProductsCount,
-		sampleRecord.SectionId,
-		sampleRecord.SectionNumber,
-	)
-
-	mock.ExpectQuery(regexp.QuoteMeta(allSectionsReportQuery)).WillReturnRows(result)
-
-	repository := NewRepository(db)
-	records, err := repository.GetBySectionId(context.TODO(), 0)
-
-	assert.NoError(t, err)
-	assert.Equal(t, []domain.SectionRecords{sampleRecord}, records)
+func TestRepository_Get_By_Section_Id(t *testing.T) {
+	columns := []string{"id", "section_number", "warehouse_id", "product_id", "description", "products_count"}
+
+	t.Run("All sections", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, 2, 3, 7, "Chocolate", 5).
+			AddRow(1, 2, 3, 9, "Ice Cream", 4).
+			AddRow(4, 5, 3, nil, nil, 0)
+
+		mock.ExpectQuery(regexp.QuoteMeta(sectionReportQuery + sectionReportGroupBy)).WithArgs().WillReturnRows(rows)
+
+		records, err := NewRepository(db).GetBySectionId(context.TODO(), 0, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.SectionRecords{
+			{
+				SectionId:     1,
+				SectionNumber: 2,
+				WarehouseId:   3,
+				ProductsCount: 9,
+				Products: []domain.SectionProductRecords{
+					{ProductId: 7, Description: "Chocolate", ProductsCount: 5},
+					{ProductId: 9, Description: "Ice Cream", ProductsCount: 4},
+				},
+			},
+			{
+				SectionId:     4,
+				SectionNumber: 5,
+				WarehouseId:   3,
+				Products:      []domain.SectionProductRecords{},
+			},
+		}, records)
+	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(sectionReportQuery+sectionReportBySection+sectionReportByWarehouse+sectionReportGroupBy)).
+			WithArgs(1, 3).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		records, err := NewRepository(db).GetBySectionId(context.TODO(), 1, 3)
+
+		assert.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(sectionReportQuery + sectionReportByWarehouse + sectionReportGroupBy)).
+			WithArgs(3).
+			WillReturnError(sql.ErrConnDone)
+
+		_, err = NewRepository(db).GetBySectionId(context.TODO(), 0, 3)
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+	})
 }
@@ -0,0 +1,51 @@
+-- The tables the section report reads, as they are in mercado_fresco.
+
+CREATE TABLE section (
+  id INT NOT NULL AUTO_INCREMENT,
+  section_number INT NOT NULL,
+  current_temperature DECIMAL(19,2) NOT NULL,
+  minimum_temperature DECIMAL(19,2) NOT NULL,
+  current_capacity INT NOT NULL,
+  minimum_capacity INT NOT NULL,
+  maximum_capacity INT NOT NULL,
+  warehouse_id INT NOT NULL,
+  product_type_id INT NOT NULL,
+  deleted_at DATETIME NULL,
+  version INT NOT NULL DEFAULT 1,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE products (
+  id INT NOT NULL AUTO_INCREMENT,
+  product_code VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL,
+  width DECIMAL(19,2) NOT NULL,
+  height DECIMAL(19,2) NOT NULL,
+  length DECIMAL(19,2) NOT NULL,
+  net_weight DECIMAL(19,2) NOT NULL,
+  expiration_rate DECIMAL(19,2) NOT NULL,
+  recommended_freezing_temperature DECIMAL(19,2) NOT NULL,
+  freezing_rate DECIMAL(19,2) NOT NULL,
+  product_type_id INT NOT NULL,
+  seller_id INT NOT NULL,
+  deleted_at DATETIME NULL,
+  version INT NOT NULL DEFAULT 1,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE product_batches (
+  id INT NOT NULL AUTO_INCREMENT,
+  batch_number INT NOT NULL,
+  current_quantity INT NOT NULL,
+  current_temperature DECIMAL(19,2) NOT NULL,
+  due_date DATE NOT NULL,
+  initial_quantity INT NOT NULL,
+  manufacturing_date DATE NOT NULL,
+  manufacturing_hour INT NOT NULL,
+  minimum_temperature DECIMAL(19,2) NOT NULL,
+  product_id INT NOT NULL,
+  section_id INT NOT NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (product_id) REFERENCES products (id),
+  FOREIGN KEY (section_id) REFERENCES section (id)
+);
@@ -7,6 +7,7 @@ import (
 	pbRepo "github.com/douglmendes/mercado-fresco-round-go/internal/product_batches/domain"
 	productRepo "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
 	sectionRepo "github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 )
 
 type service struct {
@@ -23,7 +24,11 @@ func NewService(pbr pbRepo.ProductBatchesRepository, pr productRepo.ProductRepos
 	}
 }
 
-func (s *service) Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate string, initialQuantity int, manufacturingDate string, manufacturingHour, minimumTemperature, productId, sectionId int) (*pbRepo.ProductBatch, error) {
+func (s *service) Stream(ctx context.Context, fn func(productBatch pbRepo.ProductBatch) error) error {
+	return s.productBatchesRepository.Stream(ctx, fn)
+}
+
+func (s *service) Create(ctx context.Context, batchNumber, currentQuantity, currentTemperature int, dueDate date.Date, initialQuantity int, manufacturingDate date.Date, manufacturingHour, minimumTemperature, productId, sectionId int) (*pbRepo.ProductBatch, error) {
 	productBatches, err := s.productBatchesRepository.GetAll(ctx)
 	if err != nil {
 		return nil, err
@@ -40,7 +45,7 @@ func (s *service) Create(ctx context.Context, batchNumber, currentQuantity, curr
 		return nil, fmt.Errorf("product %d not found", productId)
 	}
 
-	section, err := s.sectionRepo.GetById(sectionId)
+	section, err := s.sectionRepo.GetById(ctx, sectionId)
 	if section == nil || section.Id == 0 || err != nil {
 		return nil, fmt.Errorf("section %d not found", productId)
 	}
@@ -48,15 +53,15 @@ func (s *service) Create(ctx context.Context, batchNumber, currentQuantity, curr
 	return s.productBatchesRepository.Create(ctx, batchNumber, currentQuantity, currentTemperature, dueDate, initialQuantity, manufacturingDate, manufacturingHour, minimumTemperature, productId, sectionId)
 }
 
-func (s *service) GetBySectionId(ctx context.Context, sectionId int) ([]pbRepo.SectionRecords, error) {
+func (s *service) GetBySectionId(ctx context.Context, sectionId, warehouseId int) ([]pbRepo.SectionRecords, error) {
 	if sectionId != 0 {
-		_, err := s.sectionRepo.GetById(sectionId)
+		_, err := s.sectionRepo.GetById(ctx, sectionId)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	records, err := s.productBatchesRepository.GetBySectionId(ctx, sectionId)
+	records, err := s.productBatchesRepository.GetBySectionId(ctx, sectionId, warehouseId)
 	if err != nil {
 		return nil, err
 	}
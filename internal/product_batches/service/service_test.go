@@ -11,6 +11,7 @@ import (
 	products_mock "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain/mock"
 	sections_domain "github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
 	sections_mock "github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/golang/mock/gomock"
@@ -22,9 +23,9 @@ var (
 		BatchNumber:        1,
 		CurrentQuantity:    2,
 		CurrentTemperature: 3,
-		DueDate:            "2020-01-01",
+		DueDate:            date.New(2020, 1, 1),
 		InitialQuantity:    4,
-		ManufacturingDate:  "2020-01-01",
+		ManufacturingDate:  date.New(2020, 1, 1),
 		ManufacturingHour:  5,
 		MinimumTemperature: 6,
 		ProductId:          7,
@@ -79,10 +80,10 @@ func TestService_Create_OK(t *testing.T) {
 
 	api.EXPECT().GetAll(context.TODO()).Return([]domain.ProductBatch{}, nil)
 	prMock.EXPECT().GetById(context.TODO(), sampleBatch.ProductId).Return(sampleProduct, nil)
-	scMock.EXPECT().GetById(sampleBatch.SectionId).Return(&sampleSection, nil)
-	api.EXPECT().Create(context.TODO(), 1, 2, 3, "2020-01-01", 4, "2020-01-01", 5, 6, 7, 8).Return(&sampleBatch, nil)
+	scMock.EXPECT().GetById(gomock.Any(), sampleBatch.SectionId).Return(&sampleSection, nil)
+	api.EXPECT().Create(context.TODO(), 1, 2, 3, date.New(2020, 1, 1), 4, date.New(2020, 1, 1), 5, 6, 7, 8).Return(&sampleBatch, nil)
 
-	result, err := service.Create(context.TODO(), 1, 2, 3, "2020-01-01", 4, "2020-01-01", 5, 6, 7, 8)
+	result, err := service.Create(context.TODO(), 1, 2, 3, date.New(2020, 1, 1), 4, date.New(2020, 1, 1), 5, 6, 7, 8)
 	assert.Equal(t, sampleBatch.Id, result.Id)
 	assert.Nil(t, err)
 }
@@ -91,9 +92,9 @@ func TestService_Create_Not_OK(t *testing.T) {
 	api, _, _, service := callMock(t)
 
 	api.EXPECT().GetAll(context.TODO()).Return(nil, errors.New("error"))
-	api.EXPECT().Create(context.TODO(), 1, 2, 3, "2020-01-01", 4, "2020-01-01", 5, 6, 7, 8).Return(nil, errors.New("error"))
+	api.EXPECT().Create(context.TODO(), 1, 2, 3, date.New(2020, 1, 1), 4, date.New(2020, 1, 1), 5, 6, 7, 8).Return(nil, errors.New("error"))
 
-	_, err := service.Create(context.TODO(), 1, 2, 3, "2020-01-01", 4, "2020-01-01", 5, 6, 7, 8)
+	_, err := service.Create(context.TODO(), 1, 2, 3, date.New(2020, 1, 1), 4, date.New(2020, 1, 1), 5, 6, 7, 8)
 	assert.NotNil(t, err)
 }
 
@@ -101,9 +102,9 @@ func TestService_Create_Conflict(t *testing.T) {
 	api, _, _, service := callMock(t)
 
 	api.EXPECT().GetAll(context.TODO()).Return([]domain.ProductBatch{sampleBatch}, nil)
-	api.EXPECT().Create(context.TODO(), 1, 2, 3, "2020-01-01", 4, "2020-01-01", 5, 6, 7, 8).Return(nil, errors.New("conflict"))
+	api.EXPECT().Create(context.TODO(), 1, 2, 3, date.New(2020, 1, 1), 4, date.New(2020, 1, 1), 5, 6, 7, 8).Return(nil, errors.New("conflict"))
 
-	_, err := service.Create(context.TODO(), 1, 2, 3, "2020-01-01", 4, "2020-01-01", 5, 6, 7, 8)
+	_, err := service.Create(context.TODO(), 1, 2, 3, date.New(2020, 1, 1), 4, date.New(2020, 1, 1), 5, 6, 7, 8)
 	assert.NotNil(t, err)
 }
 
@@ -112,9 +113,9 @@ func TestService_Create_Product_Not_Found(t *testing.T) {
 
 	api.EXPECT().GetAll(context.TODO()).Return([]domain.ProductBatch{}, nil)
 	prMock.EXPECT().GetById(context.TODO(), sampleBatch.ProductId).Return(products_domain.Product{}, errors.New("error"))
-	api.EXPECT().Create(context.TODO(), 1, 2, 3, "2020-01-01", 4, "2020-01-01", 5, 6, 7, 8).Return(nil, errors.New("product not found"))
+	api.EXPECT().Create(context.TODO(), 1, 2, 3, date.New(2020, 1, 1), 4, date.New(2020, 1, 1), 5, 6, 7, 8).Return(nil, errors.New("product not found"))
 
-	_, err := service.Create(context.TODO(), 1, 2, 3, "2020-01-01", 4, "2020-01-01", 5, 6, 7, 8)
+	_, err := service.Create(context.TODO(), 1, 2, 3, date.New(2020, 1, 1), 4, date.New(2020, 1, 1), 5, 6, 7, 8)
 	assert.NotNil(t, err)
 }
 
@@ -123,20 +124,20 @@ func TestService_Create_Section_Not_Found(t *testing.T) {
 
 	api.EXPECT().GetAll(context.TODO()).Return([]domain.ProductBatch{}, nil)
 	prMock.EXPECT().GetById(context.TODO(), sampleBatch.ProductId).Return(sampleProduct, nil)
-	scMock.EXPECT().GetById(sampleBatch.SectionId).Return(nil, nil)
-	api.EXPECT().Create(context.TODO(), 1, 2, 3, "2020-01-01", 4, "2020-01-01", 5, 6, 7, 8).Return(nil, errors.New("section not found"))
+	scMock.EXPECT().GetById(gomock.Any(), sampleBatch.SectionId).Return(nil, nil)
+	api.EXPECT().Create(context.TODO(), 1, 2, 3, date.New(2020, 1, 1), 4, date.New(2020, 1, 1), 5, 6, 7, 8).Return(nil, errors.New("section not found"))
 
-	_, err := service.Create(context.TODO(), 1, 2, 3, "2020-01-01", 4, "2020-01-01", 5, 6, 7, 8)
+	_, err := service.Create(context.TODO(), 1, 2, 3, date.New(2020, 1, 1), 4, date.New(2020, 1, 1), 5, 6, 7, 8)
 	assert.NotNil(t, err)
 }
 
 func TestService_Get_By_Section_Id_OK(t *testing.T) {
 	api, _, scMock, service := callMock(t)
 
-	scMock.EXPECT().GetById(sampleBatch.SectionId).Return(&sampleSection, nil)
-	api.EXPECT().GetBySectionId(context.TODO(), sampleBatch.SectionId).Return([]domain.SectionRecords{sampleRecord}, nil)
+	scMock.EXPECT().GetById(gomock.Any(), sampleBatch.SectionId).Return(&sampleSection, nil)
+	api.EXPECT().GetBySectionId(context.TODO(), sampleBatch.SectionId, 0).Return([]domain.SectionRecords{sampleRecord}, nil)
 
-	result, err := service.GetBySectionId(context.TODO(), sampleBatch.SectionId)
+	result, err := service.GetBySectionId(context.TODO(), sampleBatch.SectionId, 0)
 	assert.Equal(t, sampleBatch.SectionId, result[0].SectionId)
 	assert.Nil(t, err)
 }
@@ -144,19 +145,29 @@ func TestService_Get_By_Section_Id_OK(t *testing.T) {
 func TestService_Get_By_Section_Id_Section_Not_Found(t *testing.T) {
 	_, _, scMock, service := callMock(t)
 
-	scMock.EXPECT().GetById(sampleBatch.SectionId).Return(nil, errors.New("not found"))
-	// api.EXPECT().GetBySectionId(context.TODO(), sampleBatch.SectionId).Return([]domain.SectionRecords{sampleRecord}, nil)
+	scMock.EXPECT().GetById(gomock.Any(), sampleBatch.SectionId).Return(nil, errors.New("not found"))
+	// api.EXPECT().GetBySectionId(context.TODO(), sampleBatch.SectionId, 0).Return([]domain.SectionRecords{sampleRecord}, nil)
 
-	_, err := service.GetBySectionId(context.TODO(), sampleBatch.SectionId)
+	_, err := service.GetBySectionId(context.TODO(), sampleBatch.SectionId, 0)
 	assert.NotNil(t, err)
 }
 
 func TestService_Get_By_Section_Id_Products_Not_Found(t *testing.T) {
 	api, _, scMock, service := callMock(t)
 
-	scMock.EXPECT().GetById(sampleBatch.SectionId).Return(&sampleSection, nil)
-	api.EXPECT().GetBySectionId(context.TODO(), sampleBatch.SectionId).Return(nil, errors.New("error"))
+	scMock.EXPECT().GetById(gomock.Any(), sampleBatch.SectionId).Return(&sampleSection, nil)
+	api.EXPECT().GetBySectionId(context.TODO(), sampleBatch.SectionId, 0).Return(nil, errors.New("error"))
 
-	_, err := service.GetBySectionId(context.TODO(), sampleBatch.SectionId)
+	_, err := service.GetBySectionId(context.TODO(), sampleBatch.SectionId, 0)
 	assert.NotNil(t, err)
 }
+
+func TestService_Get_By_Section_Id_Warehouse_Only(t *testing.T) {
+	api, _, _, service := callMock(t)
+
+	api.EXPECT().GetBySectionId(context.TODO(), 0, 2).Return([]domain.SectionRecords{sampleRecord}, nil)
+
+	result, err := service.GetBySectionId(context.TODO(), 0, 2)
+	assert.Equal(t, []domain.SectionRecords{sampleRecord}, result)
+	assert.Nil(t, err)
+}
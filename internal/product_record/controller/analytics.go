@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultPriceChangeThreshold is the percentage a price must move by to be
+// reported when the request does not set one.
+const DefaultPriceChangeThreshold = 10.0
+
+// Margins godoc
+// @Summary      Margin per product over time
+// @Description  Purchase price, sale price and margin of the last record of each period
+// @Tags         productRecords
+// @Produce      json
+// @Param        id      query  int     false  "Product ID, every product when missing"  minimum(1)
+// @Param        from    query  string  false  "First day, YYYY-MM-DD"
+// @Param        to      query  string  false  "Last day, YYYY-MM-DD"
+// @Param        period  query  string  false  "day, week or month (default)"
+// @Success      200  {array}   domain.ProductMargins
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Router       /api/v1/productRecords/margins [get]
+func (c *ProductRecordController) Margins() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		productId, dates, ok := analyticsQuery(ctx)
+		if !ok {
+			return
+		}
+
+		unit, err := period.UnitFromQuery(ctx, period.Month)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
+		margins, err := c.service.GetMargins(ctx, productId, dates, unit)
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusNotFound, response.DecodeError(message))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(margins))
+	}
+}
+
+// PriceChanges godoc
+// @Summary      Price changes above a percentage
+// @Description  Purchase and sale prices that changed from one record to the next by more than threshold percent
+// @Tags         productRecords
+// @Produce      json
+// @Param        id         query  int     false  "Product ID, every product when missing"  minimum(1)
+// @Param        from       query  string  false  "First day, YYYY-MM-DD"
+// @Param        to         query  string  false  "Last day, YYYY-MM-DD"
+// @Param        threshold  query  number  false  "Percentage, 10 by default"
+// @Success      200  {array}   domain.PriceChange
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Router       /api/v1/productRecords/priceChanges [get]
+func (c *ProductRecordController) PriceChanges() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		productId, dates, ok := analyticsQuery(ctx)
+		if !ok {
+			return
+		}
+
+		threshold := DefaultPriceChangeThreshold
+		if value, exists := ctx.GetQuery("threshold"); exists {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil || parsed < 0 {
+				ctx.JSON(http.StatusBadRequest, response.DecodeError(fmt.Sprintf("invalid threshold value %q", value)))
+				return
+			}
+			threshold = parsed
+		}
+
+		changes, err := c.service.GetPriceChanges(ctx, productId, dates, threshold)
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusNotFound, response.DecodeError(message))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(changes))
+	}
+}
+
+// BelowCost godoc
+// @Summary      Products selling below purchase price
+// @Description  Products whose latest record has a sale price lower than the purchase price
+// @Tags         productRecords
+// @Produce      json
+// @Success      200  {array}   domain.BelowCost
+// @Failure      500  {object}  response.Response
+// @Router       /api/v1/productRecords/belowCost [get]
+func (c *ProductRecordController) BelowCost() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		products, err := c.service.GetBelowCost(ctx)
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusInternalServerError, response.DecodeError(message))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(products))
+	}
+}
+
+// analyticsQuery reads the product and the date range of an analytics
+// request, answering 400 when they are invalid.
+func analyticsQuery(ctx *gin.Context) (int, period.Range, bool) {
+	var productId int64
+
+	if stringId, exists := ctx.GetQuery("id"); exists {
+		id, err := strconv.ParseInt(stringId, 10, 64)
+		if err != nil || id < 1 {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+			return 0, period.Range{}, false
+		}
+		productId = id
+	}
+
+	dates, err := period.RangeFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+		return 0, period.Range{}, false
+	}
+
+	return int(productId), dates, true
+}
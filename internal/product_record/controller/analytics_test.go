@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
+	productRecordMockDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductRecordController_Margins(t *testing.T) {
+	testCases := []struct {
+		name       string
+		query      string
+		buildStubs func(service *productRecordMockDomain.MockProductRecordService)
+		status     int
+	}{
+		{
+			name:  "OK",
+			query: "?id=1&from=2022-07-01&period=week",
+			buildStubs: func(service *productRecordMockDomain.MockProductRecordService) {
+				service.EXPECT().
+					GetMargins(gomock.Any(), 1, period.Range{From: date.New(2022, 7, 1)}, period.Week).
+					Times(ONCE).
+					Return([]domain.ProductMargins{{ProductId: 1}}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:  "Not found",
+			query: "?id=99",
+			buildStubs: func(service *productRecordMockDomain.MockProductRecordService) {
+				service.EXPECT().
+					GetMargins(gomock.Any(), INVALID_PRODUCT_ID, period.Range{}, period.Month).
+					Times(ONCE).
+					Return(nil, someError)
+			},
+			status: http.StatusNotFound,
+		},
+		{name: "Invalid id", query: "?id=" + INVALID_ID, status: http.StatusBadRequest},
+		{name: "Invalid range", query: "?from=2022-08-01&to=2022-07-01", status: http.StatusBadRequest},
+		{name: "Invalid period", query: "?period=year", status: http.StatusBadRequest},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, handler, api, _ := callProductsMock(t)
+			if testCase.buildStubs != nil {
+				testCase.buildStubs(service)
+			}
+			api.GET(PRODUCT_RECORDS_PATH+"margins", handler.Margins())
+
+			resp := httptest.NewRecorder()
+			api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, PRODUCT_RECORDS_PATH+"margins"+testCase.query, nil))
+
+			assert.Equal(t, testCase.status, resp.Code)
+		})
+	}
+}
+
+func TestProductRecordController_PriceChanges(t *testing.T) {
+	testCases := []struct {
+		name       string
+		query      string
+		buildStubs func(service *productRecordMockDomain.MockProductRecordService)
+		status     int
+	}{
+		{
+			name:  "Default threshold",
+			query: "",
+			buildStubs: func(service *productRecordMockDomain.MockProductRecordService) {
+				service.EXPECT().
+					GetPriceChanges(gomock.Any(), GET_ALL_ID, period.Range{}, DefaultPriceChangeThreshold).
+					Times(ONCE).
+					Return([]domain.PriceChange{}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:  "Threshold",
+			query: "?threshold=2.5",
+			buildStubs: func(service *productRecordMockDomain.MockProductRecordService) {
+				service.EXPECT().
+					GetPriceChanges(gomock.Any(), GET_ALL_ID, period.Range{}, 2.5).
+					Times(ONCE).
+					Return([]domain.PriceChange{}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:  "Not found",
+			query: "?id=99",
+			buildStubs: func(service *productRecordMockDomain.MockProductRecordService) {
+				service.EXPECT().
+					GetPriceChanges(gomock.Any(), INVALID_PRODUCT_ID, period.Range{}, DefaultPriceChangeThreshold).
+					Times(ONCE).
+					Return(nil, someError)
+			},
+			status: http.StatusNotFound,
+		},
+		{name: "Invalid threshold", query: "?threshold=-1", status: http.StatusBadRequest},
+		{name: "Invalid id", query: "?id=0", status: http.StatusBadRequest},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, handler, api, _ := callProductsMock(t)
+			if testCase.buildStubs != nil {
+				testCase.buildStubs(service)
+			}
+			api.GET(PRODUCT_RECORDS_PATH+"priceChanges", handler.PriceChanges())
+
+			resp := httptest.NewRecorder()
+			api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, PRODUCT_RECORDS_PATH+"priceChanges"+testCase.query, nil))
+
+			assert.Equal(t, testCase.status, resp.Code)
+		})
+	}
+}
+
+func TestProductRecordController_BelowCost(t *testing.T) {
+	for name, testCase := range map[string]struct {
+		err    error
+		status int
+	}{
+		"OK":   {nil, http.StatusOK},
+		"Fail": {someError, http.StatusInternalServerError},
+	} {
+		t.Run(name, func(t *testing.T) {
+			service, handler, api, ctx := callProductsMock(t)
+			service.EXPECT().GetBelowCost(ctx).Times(ONCE).Return([]domain.BelowCost{}, testCase.err)
+			api.GET(PRODUCT_RECORDS_PATH+"belowCost", handler.BelowCost())
+
+			resp := httptest.NewRecorder()
+			api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, PRODUCT_RECORDS_PATH+"belowCost", nil))
+
+			assert.Equal(t, testCase.status, resp.Code)
+		})
+	}
+}
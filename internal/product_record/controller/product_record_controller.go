@@ -1,14 +1,21 @@
 package controller
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/bulk"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 type ProductRecordController struct {
@@ -60,12 +67,19 @@ func (c *ProductRecordController) GetByProductId() gin.HandlerFunc {
 }
 
 type productRecordsRequest struct {
-	LastUpdateDate string  `json:"last_update_date" binding:"required"`
+	LastUpdateDate string  `json:"last_update_date" binding:"required,timestamp"`
 	PurchasePrice  float64 `json:"purchase_price" binding:"required,min=0"`
 	SalePrice      float64 `json:"sale_price" binding:"required,min=0"`
 	ProductId      int     `json:"product_id" binding:"required,min=1"`
 }
 
+// productRecordsUpdateRequest is a record of a batch update: the whole
+// record, along with its id.
+type productRecordsUpdateRequest struct {
+	Id int `json:"id" binding:"required,min=1"`
+	productRecordsRequest
+}
+
 // CreateProductRecord godoc
 // @Summary      Create a new product record
 // @Description  Create a new product record in the system
@@ -85,12 +99,18 @@ func (c *ProductRecordController) Create() gin.HandlerFunc {
 			message := err.Error()
 			logger.Error(ctx, store.GetPathWithLine(), message)
 
-			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, response.DecodeError(message))
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+
+		lastUpdateDate, err := date.ParseDateTime(req.LastUpdateDate)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 			return
 		}
 
 		arg := domain.ProductRecord{
-			LastUpdateDate: req.LastUpdateDate,
+			LastUpdateDate: lastUpdateDate,
 			PurchasePrice:  req.PurchasePrice,
 			SalePrice:      req.SalePrice,
 			ProductId:      req.ProductId,
@@ -108,3 +128,161 @@ func (c *ProductRecordController) Create() gin.HandlerFunc {
 		ctx.JSON(http.StatusCreated, response.NewResponse(product))
 	}
 }
+
+// CreateProductRecordsBatch godoc
+// @Summary      Create product records in batch
+// @Description  Create many product records at once, as the nightly price updates do. Valid records are created together and every record gets its outcome in the report
+// @Tags         productRecords
+// @Accept       json
+// @Produce      json
+// @Param        productRecords  body  []productRecordsRequest  true  "Product records to be created"
+// @Success      200      {object}  bulk.Report
+// @Failure      422      {object}  response.Response
+// @Failure      500      {object}  response.Response
+// @Router       /api/v1/productRecords/batch [post]
+func (c *ProductRecordController) CreateBatch() gin.HandlerFunc {
+	return batch(decodeProductRecord, c.service.CreateBatch, bulk.StatusCreated)
+}
+
+// UpdateProductRecordsBatch godoc
+// @Summary      Update product records in batch
+// @Description  Replace many product records at once, by id. Valid records are updated together and every record gets its outcome in the report. Records purchase orders were priced with cannot be replaced
+// @Tags         productRecords
+// @Accept       json
+// @Produce      json
+// @Param        productRecords  body  []productRecordsUpdateRequest  true  "Product records to be updated"
+// @Success      200      {object}  bulk.Report
+// @Failure      422      {object}  response.Response
+// @Failure      500      {object}  response.Response
+// @Router       /api/v1/productRecords/batch [put]
+func (c *ProductRecordController) UpdateBatch() gin.HandlerFunc {
+	return batch(decodeProductRecordUpdate, c.service.UpdateBatch, bulk.StatusUpdated)
+}
+
+// batch decodes the records of the body with decode, hands the valid ones to
+// run and reports every record, the ones run accepted with status.
+func batch(
+	decode func(item json.RawMessage) (domain.ProductRecord, error),
+	run func(ctx context.Context, args []domain.ProductRecord) ([]domain.BatchResult, error),
+	status string,
+) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var items []json.RawMessage
+
+		if err := ctx.ShouldBindJSON(&items); err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+
+		if len(items) == 0 || len(items) > bulk.MaxRows {
+			message := fmt.Sprintf("a batch must have between 1 and %d product records", bulk.MaxRows)
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, response.DecodeError(message))
+			return
+		}
+
+		report := bulk.Report{Total: len(items), Rows: make([]bulk.Result, len(items))}
+
+		args, positions := []domain.ProductRecord{}, []int{}
+		for i, item := range items {
+			report.Rows[i] = bulk.Result{Row: i + 1, Status: bulk.StatusFailed}
+
+			arg, err := decode(item)
+			if err != nil {
+				report.Rows[i].Error = validation.Message
+				report.Rows[i].Fields = validation.Fields(err)
+				continue
+			}
+
+			args, positions = append(args, arg), append(positions, i)
+		}
+
+		if len(args) > 0 {
+			results, err := run(ctx, args)
+			if err != nil {
+				message := err.Error()
+				logger.Error(ctx, store.GetPathWithLine(), message)
+
+				ctx.JSON(http.StatusInternalServerError, response.DecodeError(message))
+				return
+			}
+
+			for i, result := range results {
+				row := &report.Rows[positions[i]]
+				if result.Err != nil {
+					row.Error = result.Err.Error()
+					continue
+				}
+
+				row.Status = status
+				row.Id = result.Record.Id
+			}
+		}
+
+		for _, row := range report.Rows {
+			switch row.Status {
+			case bulk.StatusCreated:
+				report.Created++
+			case bulk.StatusUpdated:
+				report.Updated++
+			default:
+				report.Failed++
+			}
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(report))
+	}
+}
+
+// decodeProductRecord binds one record of a batch like the body of Create.
+func decodeProductRecord(item json.RawMessage) (domain.ProductRecord, error) {
+	var req productRecordsRequest
+
+	if err := json.Unmarshal(item, &req); err != nil {
+		return domain.ProductRecord{}, err
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return domain.ProductRecord{}, err
+	}
+
+	lastUpdateDate, err := date.ParseDateTime(req.LastUpdateDate)
+	if err != nil {
+		return domain.ProductRecord{}, err
+	}
+
+	return domain.ProductRecord{
+		LastUpdateDate: lastUpdateDate,
+		PurchasePrice:  req.PurchasePrice,
+		SalePrice:      req.SalePrice,
+		ProductId:      req.ProductId,
+	}, nil
+}
+
+// decodeProductRecordUpdate binds one record of a batch update.
+func decodeProductRecordUpdate(item json.RawMessage) (domain.ProductRecord, error) {
+	var req productRecordsUpdateRequest
+
+	if err := json.Unmarshal(item, &req); err != nil {
+		return domain.ProductRecord{}, err
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return domain.ProductRecord{}, err
+	}
+
+	lastUpdateDate, err := date.ParseDateTime(req.LastUpdateDate)
+	if err != nil {
+		return domain.ProductRecord{}, err
+	}
+
+	return domain.ProductRecord{
+		Id:             req.Id,
+		LastUpdateDate: lastUpdateDate,
+		PurchasePrice:  req.PurchasePrice,
+		SalePrice:      req.SalePrice,
+		ProductId:      req.ProductId,
+	}, nil
+}
@@ -8,9 +8,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
 	productRecordMockDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/bulk"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -28,7 +31,7 @@ const (
 var (
 	productRecord = domain.ProductRecord{
 		Id:             1,
-		LastUpdateDate: "2022-07-09",
+		LastUpdateDate: date.NewDateTime(time.Date(2022, 7, 9, 0, 0, 0, 0, time.UTC)),
 		PurchasePrice:  22.2,
 		SalePrice:      33.3,
 		ProductId:      1,
@@ -314,3 +317,241 @@ func TestProductRecordController_GetByProductId(t *testing.T) {
 		})
 	}
 }
+
+func TestProductRecordController_CreateBatch(t *testing.T) {
+	newProductRecord := productRecord
+	newProductRecord.Id = 0
+
+	type reportBody struct {
+		Data  bulk.Report `json:"data"`
+		Error string      `json:"error"`
+	}
+
+	testCases := []struct {
+		name       string
+		payload    interface{}
+		buildStubs func(
+			service *productRecordMockDomain.MockProductRecordService,
+			ctx gomock.Matcher,
+		)
+		checkResult func(t *testing.T, res *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: []interface{}{
+				newProductRecord,
+				map[string]interface{}{"product_id": 1},
+				newProductRecord,
+			},
+			buildStubs: func(
+				service *productRecordMockDomain.MockProductRecordService,
+				ctx gomock.Matcher,
+			) {
+				service.
+					EXPECT().
+					CreateBatch(ctx, []domain.ProductRecord{newProductRecord, newProductRecord}).
+					Times(ONCE).
+					Return([]domain.BatchResult{
+						{Record: productRecord},
+						{Err: fmt.Errorf("product with id (1) not found")},
+					}, nil)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+
+				body := reportBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Equal(t, 3, body.Data.Total)
+				assert.Equal(t, 1, body.Data.Created)
+				assert.Equal(t, 2, body.Data.Failed)
+
+				assert.Equal(t, bulk.Result{Row: 1, Status: bulk.StatusCreated, Id: productRecord.Id}, body.Data.Rows[0])
+				assert.Equal(t, bulk.StatusFailed, body.Data.Rows[1].Status)
+				assert.NotEmpty(t, body.Data.Rows[1].Fields)
+				assert.Equal(t, bulk.Result{Row: 3, Status: bulk.StatusFailed, Error: "product with id (1) not found"}, body.Data.Rows[2])
+			},
+		},
+		{
+			name:    "All invalid",
+			payload: []interface{}{map[string]interface{}{}},
+			buildStubs: func(
+				service *productRecordMockDomain.MockProductRecordService,
+				ctx gomock.Matcher,
+			) {
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+
+				body := reportBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Equal(t, 1, body.Data.Failed)
+			},
+		},
+		{
+			name:    "Empty",
+			payload: []interface{}{},
+			buildStubs: func(
+				service *productRecordMockDomain.MockProductRecordService,
+				ctx gomock.Matcher,
+			) {
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+			},
+		},
+		{
+			name:    "Not a list",
+			payload: newProductRecord,
+			buildStubs: func(
+				service *productRecordMockDomain.MockProductRecordService,
+				ctx gomock.Matcher,
+			) {
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+			},
+		},
+		{
+			name:    "Service error",
+			payload: []interface{}{newProductRecord},
+			buildStubs: func(
+				service *productRecordMockDomain.MockProductRecordService,
+				ctx gomock.Matcher,
+			) {
+				service.
+					EXPECT().
+					CreateBatch(ctx, []domain.ProductRecord{newProductRecord}).
+					Times(ONCE).
+					Return(nil, someError)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, res.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, handler, api, ctx := callProductsMock(t)
+			tc.buildStubs(service, ctx)
+
+			api.POST(PRODUCT_RECORDS_PATH+"batch", handler.CreateBatch())
+
+			payload, err := json.Marshal(tc.payload)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, PRODUCT_RECORDS_PATH+"batch", bytes.NewBuffer(payload))
+			res := httptest.NewRecorder()
+
+			api.ServeHTTP(res, req)
+
+			tc.checkResult(t, res)
+		})
+	}
+}
+
+func TestProductRecordController_UpdateBatch(t *testing.T) {
+	newProductRecord := productRecord
+	newProductRecord.Id = 0
+
+	type reportBody struct {
+		Data  bulk.Report `json:"data"`
+		Error string      `json:"error"`
+	}
+
+	testCases := []struct {
+		name       string
+		payload    interface{}
+		buildStubs func(
+			service *productRecordMockDomain.MockProductRecordService,
+			ctx gomock.Matcher,
+		)
+		checkResult func(t *testing.T, res *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: []interface{}{productRecord, newProductRecord, productRecord},
+			buildStubs: func(
+				service *productRecordMockDomain.MockProductRecordService,
+				ctx gomock.Matcher,
+			) {
+				service.
+					EXPECT().
+					UpdateBatch(ctx, []domain.ProductRecord{productRecord, productRecord}).
+					Times(ONCE).
+					Return([]domain.BatchResult{
+						{Record: productRecord},
+						{Err: fmt.Errorf("product record with id (1) not found")},
+					}, nil)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+
+				body := reportBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Equal(t, 3, body.Data.Total)
+				assert.Equal(t, 0, body.Data.Created)
+				assert.Equal(t, 1, body.Data.Updated)
+				assert.Equal(t, 2, body.Data.Failed)
+
+				assert.Equal(t, bulk.Result{Row: 1, Status: bulk.StatusUpdated, Id: productRecord.Id}, body.Data.Rows[0])
+				// The record without an id is not bound.
+				assert.Equal(t, bulk.StatusFailed, body.Data.Rows[1].Status)
+				assert.NotEmpty(t, body.Data.Rows[1].Fields)
+				assert.Equal(t, bulk.Result{Row: 3, Status: bulk.StatusFailed, Error: "product record with id (1) not found"}, body.Data.Rows[2])
+			},
+		},
+		{
+			name:    "Too many",
+			payload: make([]domain.ProductRecord, bulk.MaxRows+1),
+			buildStubs: func(
+				service *productRecordMockDomain.MockProductRecordService,
+				ctx gomock.Matcher,
+			) {
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+				assert.Contains(t, res.Body.String(), fmt.Sprintf("between 1 and %d", bulk.MaxRows))
+			},
+		},
+		{
+			name:    "Service error",
+			payload: []interface{}{productRecord},
+			buildStubs: func(
+				service *productRecordMockDomain.MockProductRecordService,
+				ctx gomock.Matcher,
+			) {
+				service.
+					EXPECT().
+					UpdateBatch(ctx, []domain.ProductRecord{productRecord}).
+					Times(ONCE).
+					Return(nil, someError)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, res.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, handler, api, ctx := callProductsMock(t)
+			tc.buildStubs(service, ctx)
+
+			api.PUT(PRODUCT_RECORDS_PATH+"batch", handler.UpdateBatch())
+
+			payload, err := json.Marshal(tc.payload)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, PRODUCT_RECORDS_PATH+"batch", bytes.NewBuffer(payload))
+			res := httptest.NewRecorder()
+
+			api.ServeHTTP(res, req)
+
+			tc.checkResult(t, res)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	domain "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
+	period "github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -50,6 +51,36 @@ func (mr *MockProductRecordRepositoryMockRecorder) Create(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductRecordRepository)(nil).Create), arg0, arg1)
 }
 
+// CreateBatch mocks base method.
+func (m *MockProductRecordRepository) CreateBatch(arg0 context.Context, arg1 []domain.ProductRecord) ([]domain.ProductRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", arg0, arg1)
+	ret0, _ := ret[0].([]domain.ProductRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockProductRecordRepositoryMockRecorder) CreateBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockProductRecordRepository)(nil).CreateBatch), arg0, arg1)
+}
+
+// ExistingIds mocks base method.
+func (m *MockProductRecordRepository) ExistingIds(arg0 context.Context, arg1 []int) (map[int]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistingIds", arg0, arg1)
+	ret0, _ := ret[0].(map[int]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistingIds indicates an expected call of ExistingIds.
+func (mr *MockProductRecordRepositoryMockRecorder) ExistingIds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistingIds", reflect.TypeOf((*MockProductRecordRepository)(nil).ExistingIds), arg0, arg1)
+}
+
 // GetByProductId mocks base method.
 func (m *MockProductRecordRepository) GetByProductId(arg0 context.Context, arg1 int) ([]domain.ProductRecordCount, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +96,65 @@ func (mr *MockProductRecordRepositoryMockRecorder) GetByProductId(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProductId", reflect.TypeOf((*MockProductRecordRepository)(nil).GetByProductId), arg0, arg1)
 }
 
+// GetCurrent mocks base method.
+func (m *MockProductRecordRepository) GetCurrent(arg0 context.Context, arg1 int) (domain.ProductRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrent", arg0, arg1)
+	ret0, _ := ret[0].(domain.ProductRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrent indicates an expected call of GetCurrent.
+func (mr *MockProductRecordRepositoryMockRecorder) GetCurrent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrent", reflect.TypeOf((*MockProductRecordRepository)(nil).GetCurrent), arg0, arg1)
+}
+
+// GetHistory mocks base method.
+func (m *MockProductRecordRepository) GetHistory(arg0 context.Context, arg1 int, arg2 period.Range) ([]domain.PriceRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.PriceRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockProductRecordRepositoryMockRecorder) GetHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockProductRecordRepository)(nil).GetHistory), arg0, arg1, arg2)
+}
+
+// ReferencedIds mocks base method.
+func (m *MockProductRecordRepository) ReferencedIds(arg0 context.Context, arg1 []int) (map[int]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferencedIds", arg0, arg1)
+	ret0, _ := ret[0].(map[int]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferencedIds indicates an expected call of ReferencedIds.
+func (mr *MockProductRecordRepositoryMockRecorder) ReferencedIds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferencedIds", reflect.TypeOf((*MockProductRecordRepository)(nil).ReferencedIds), arg0, arg1)
+}
+
+// UpdateBatch mocks base method.
+func (m *MockProductRecordRepository) UpdateBatch(arg0 context.Context, arg1 []domain.ProductRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBatch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBatch indicates an expected call of UpdateBatch.
+func (mr *MockProductRecordRepositoryMockRecorder) UpdateBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBatch", reflect.TypeOf((*MockProductRecordRepository)(nil).UpdateBatch), arg0, arg1)
+}
+
 // MockProductRecordService is a mock of ProductRecordService interface.
 type MockProductRecordService struct {
 	ctrl     *gomock.Controller
@@ -103,6 +193,36 @@ func (mr *MockProductRecordServiceMockRecorder) Create(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductRecordService)(nil).Create), arg0, arg1)
 }
 
+// CreateBatch mocks base method.
+func (m *MockProductRecordService) CreateBatch(arg0 context.Context, arg1 []domain.ProductRecord) ([]domain.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", arg0, arg1)
+	ret0, _ := ret[0].([]domain.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockProductRecordServiceMockRecorder) CreateBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockProductRecordService)(nil).CreateBatch), arg0, arg1)
+}
+
+// GetBelowCost mocks base method.
+func (m *MockProductRecordService) GetBelowCost(arg0 context.Context) ([]domain.BelowCost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBelowCost", arg0)
+	ret0, _ := ret[0].([]domain.BelowCost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBelowCost indicates an expected call of GetBelowCost.
+func (mr *MockProductRecordServiceMockRecorder) GetBelowCost(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBelowCost", reflect.TypeOf((*MockProductRecordService)(nil).GetBelowCost), arg0)
+}
+
 // GetByProductId mocks base method.
 func (m *MockProductRecordService) GetByProductId(arg0 context.Context, arg1 int) ([]domain.ProductRecordCount, error) {
 	m.ctrl.T.Helper()
@@ -117,3 +237,48 @@ func (mr *MockProductRecordServiceMockRecorder) GetByProductId(arg0, arg1 interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProductId", reflect.TypeOf((*MockProductRecordService)(nil).GetByProductId), arg0, arg1)
 }
+
+// GetMargins mocks base method.
+func (m *MockProductRecordService) GetMargins(arg0 context.Context, arg1 int, arg2 period.Range, arg3 period.Unit) ([]domain.ProductMargins, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMargins", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]domain.ProductMargins)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMargins indicates an expected call of GetMargins.
+func (mr *MockProductRecordServiceMockRecorder) GetMargins(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMargins", reflect.TypeOf((*MockProductRecordService)(nil).GetMargins), arg0, arg1, arg2, arg3)
+}
+
+// GetPriceChanges mocks base method.
+func (m *MockProductRecordService) GetPriceChanges(arg0 context.Context, arg1 int, arg2 period.Range, arg3 float64) ([]domain.PriceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceChanges", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]domain.PriceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceChanges indicates an expected call of GetPriceChanges.
+func (mr *MockProductRecordServiceMockRecorder) GetPriceChanges(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceChanges", reflect.TypeOf((*MockProductRecordService)(nil).GetPriceChanges), arg0, arg1, arg2, arg3)
+}
+
+// UpdateBatch mocks base method.
+func (m *MockProductRecordService) UpdateBatch(arg0 context.Context, arg1 []domain.ProductRecord) ([]domain.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBatch", arg0, arg1)
+	ret0, _ := ret[0].([]domain.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBatch indicates an expected call of UpdateBatch.
+func (mr *MockProductRecordServiceMockRecorder) UpdateBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBatch", reflect.TypeOf((*MockProductRecordService)(nil).UpdateBatch), arg0, arg1)
+}
@@ -1,13 +1,19 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+)
 
 type ProductRecord struct {
-	Id             int     `json:"id"`
-	LastUpdateDate string  `json:"last_update_date"`
-	PurchasePrice  float64 `json:"purchase_price"`
-	SalePrice      float64 `json:"sale_price"`
-	ProductId      int     `json:"product_id"`
+	Id             int           `json:"id"`
+	LastUpdateDate date.DateTime `json:"last_update_date"`
+	PurchasePrice  float64       `json:"purchase_price"`
+	SalePrice      float64       `json:"sale_price"`
+	ProductId      int           `json:"product_id"`
 }
 
 type ProductRecordCount struct {
@@ -16,12 +22,91 @@ type ProductRecordCount struct {
 	RecordsCount int    `json:"records_count"`
 }
 
+// BatchResult is the outcome of one record of a batch: the record as it was
+// created or updated, or the reason it was left out.
+type BatchResult struct {
+	Record ProductRecord
+	Err    error
+}
+
+// PriceRecord is a product record along with the description of its
+// product.
+type PriceRecord struct {
+	ProductRecord
+	Description string
+}
+
+// ProductMargins follows the margin of a product over time. Every period
+// holds the prices of the last record updated within it.
+type ProductMargins struct {
+	ProductId   int            `json:"product_id"`
+	Description string         `json:"description"`
+	Periods     []MarginPeriod `json:"periods"`
+}
+
+type MarginPeriod struct {
+	Period        string  `json:"period"`
+	RecordsCount  int     `json:"records_count"`
+	PurchasePrice float64 `json:"purchase_price"`
+	SalePrice     float64 `json:"sale_price"`
+	Margin        float64 `json:"margin"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+// Price names the prices of a product record.
+type Price string
+
+const (
+	PurchasePrice Price = "purchase_price"
+	SalePrice     Price = "sale_price"
+)
+
+// PriceChange is a price of a product that moved from one record to the
+// next by more than the requested percentage.
+type PriceChange struct {
+	ProductId      int           `json:"product_id"`
+	Description    string        `json:"description"`
+	RecordId       int           `json:"record_id"`
+	LastUpdateDate date.DateTime `json:"last_update_date"`
+	Price          Price         `json:"price"`
+	OldPrice       float64       `json:"old_price"`
+	NewPrice       float64       `json:"new_price"`
+	ChangePercent  float64       `json:"change_percent"`
+}
+
+// BelowCost is a product whose latest record sells it for less than it was
+// purchased for.
+type BelowCost struct {
+	ProductId      int           `json:"product_id"`
+	Description    string        `json:"description"`
+	RecordId       int           `json:"record_id"`
+	LastUpdateDate date.DateTime `json:"last_update_date"`
+	PurchasePrice  float64       `json:"purchase_price"`
+	SalePrice      float64       `json:"sale_price"`
+	Loss           float64       `json:"loss"`
+}
+
+// ErrNoCurrentRecord is returned for a product whose records, if any, are
+// all scheduled for later.
+var ErrNoCurrentRecord = errors.New("product has no record in effect")
+
 type ProductRecordRepository interface {
 	GetByProductId(ctx context.Context, productId int) ([]ProductRecordCount, error)
 	Create(ctx context.Context, arg ProductRecord) (ProductRecord, error)
+	CreateBatch(ctx context.Context, args []ProductRecord) ([]ProductRecord, error)
+	UpdateBatch(ctx context.Context, args []ProductRecord) error
+	ExistingIds(ctx context.Context, ids []int) (map[int]bool, error)
+	ReferencedIds(ctx context.Context, ids []int) (map[int]bool, error)
+	GetHistory(ctx context.Context, productId int, dates period.Range) ([]PriceRecord, error)
+	GetCurrent(ctx context.Context, productId int) (ProductRecord, error)
 }
 
 type ProductRecordService interface {
 	GetByProductId(ctx context.Context, productId int) ([]ProductRecordCount, error)
 	Create(ctx context.Context, arg ProductRecord) (ProductRecord, error)
+	CreateBatch(ctx context.Context, args []ProductRecord) ([]BatchResult, error)
+	UpdateBatch(ctx context.Context, args []ProductRecord) ([]BatchResult, error)
+	GetMargins(ctx context.Context, productId int, dates period.Range, unit period.Unit) ([]ProductMargins, error)
+	GetPriceChanges(ctx context.Context, productId int, dates period.Range, threshold float64) ([]PriceChange, error)
+	GetBelowCost(ctx context.Context) ([]BelowCost, error)
 }
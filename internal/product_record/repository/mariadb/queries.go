@@ -9,6 +9,46 @@ const (
 			product_id
 		) VALUES (?, ?, ?, ?)
 	`
+	// CreateBatchQuery is followed by one "(?, ?, ?, ?)" per record.
+	CreateBatchQuery = `
+		INSERT INTO product_records (
+			last_update_date,
+			purchase_price,
+			sale_price,
+			product_id
+		) VALUES `
+	// AutoIncrementIncrementQuery reads the step between the ids the
+	// session gives to the rows of a multi-row insert.
+	AutoIncrementIncrementQuery = `SELECT @@SESSION.auto_increment_increment`
+	UpdateQuery                 = `
+		UPDATE product_records SET
+			last_update_date = ?,
+			purchase_price = ?,
+			sale_price = ?,
+			product_id = ?
+		WHERE id = ?
+	`
+	// The ExistingIds queries take the placeholders of the ids in place of %s.
+	ExistingIdsQuery = `
+		SELECT product_records.id
+		FROM product_records
+		INNER JOIN products ON product_records.product_id = products.id
+		WHERE products.deleted_at IS NULL AND product_records.id IN (%s)
+	`
+	ExistingIdsBySellerQuery = `
+		SELECT product_records.id
+		FROM product_records
+		INNER JOIN products ON product_records.product_id = products.id
+		WHERE products.deleted_at IS NULL AND products.seller_id = ? AND product_records.id IN (%s)
+	`
+	// ReferencedIdsQuery takes the placeholders of the ids in place of %[1]s,
+	// once for the order lines and once for the orders made before orders
+	// had lines.
+	ReferencedIdsQuery = `
+		SELECT product_record_id FROM purchase_order_lines WHERE product_record_id IN (%[1]s)
+		UNION
+		SELECT product_record_id FROM purchase_orders WHERE product_record_id IN (%[1]s)
+	`
 	GetAllGroupByProductIdQuery = `
 		SELECT
 			product_records.product_id,
@@ -28,4 +68,58 @@ const (
 		WHERE product_records.product_id = ?
 		GROUP BY product_records.product_id;
 	`
+	GetAllGroupByProductIdBySellerQuery = `
+		SELECT
+			product_records.product_id,
+			products.description,
+			count(*) AS records_count
+		FROM product_records
+		INNER JOIN products ON product_records.product_id = products.id
+		WHERE products.seller_id = ?
+		GROUP BY product_records.product_id;
+	`
+	GetAllGroupByProductIdWhereIdBySellerQuery = `
+		SELECT
+			product_records.product_id,
+			products.description,
+			count(*) AS records_count
+		FROM product_records
+		INNER JOIN products ON product_records.product_id = products.id
+		WHERE product_records.product_id = ? AND products.seller_id = ?
+		GROUP BY product_records.product_id;
+	`
+	// GetHistoryQuery is completed with the conditions of the request and
+	// GetHistoryOrder.
+	GetHistoryQuery = `
+		SELECT
+			product_records.id,
+			product_records.last_update_date,
+			product_records.purchase_price,
+			product_records.sale_price,
+			product_records.product_id,
+			products.description
+		FROM product_records
+		INNER JOIN products ON product_records.product_id = products.id
+	`
+	GetHistoryOrder = `
+		ORDER BY product_records.product_id, product_records.last_update_date, product_records.id
+	`
+	// GetHistoryPrevious selects, for the record of the outer query, the id
+	// of the last record of its product updated before a day.
+	GetHistoryPrevious = `
+		SELECT previous.id
+		FROM product_records previous
+		WHERE previous.product_id = product_records.product_id AND DATE(previous.last_update_date) < ?
+		ORDER BY previous.last_update_date DESC, previous.id DESC
+		LIMIT 1
+	`
+	// GetCurrentQuery selects the latest record of a product in effect at a
+	// date, leaving out the ones scheduled after it.
+	GetCurrentQuery = `
+		SELECT id, last_update_date, purchase_price, sale_price, product_id
+		FROM product_records
+		WHERE product_id = ? AND last_update_date <= ?
+		ORDER BY last_update_date DESC, id DESC
+		LIMIT 1
+	`
 )
@@ -3,12 +3,22 @@ package mariadb
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/tenant"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 )
 
+// BatchSize bounds the rows of one insert statement of CreateBatch.
+const BatchSize = 500
+
 type repository struct {
 	db *sql.DB
 }
@@ -20,8 +30,15 @@ func NewRepository(db *sql.DB) domain.ProductRecordRepository {
 func (r repository) GetByProductId(ctx context.Context, productId int) ([]domain.ProductRecordCount, error) {
 	productRecords := []domain.ProductRecordCount{}
 
+	sellerId, scoped := tenant.SellerId(ctx)
+
 	if productId != 0 {
-		row := r.db.QueryRowContext(ctx, GetAllGroupByProductIdWhereIdQuery, productId)
+		query, args := GetAllGroupByProductIdWhereIdQuery, []interface{}{productId}
+		if scoped {
+			query, args = GetAllGroupByProductIdWhereIdBySellerQuery, append(args, sellerId)
+		}
+
+		row := r.db.QueryRowContext(ctx, query, args...)
 
 		productRecordCount := domain.ProductRecordCount{}
 
@@ -38,7 +55,12 @@ func (r repository) GetByProductId(ctx context.Context, productId int) ([]domain
 
 		productRecords = append(productRecords, productRecordCount)
 	} else {
-		rows, err := r.db.Query(GetAllGroupByProductIdQuery)
+		query, args := GetAllGroupByProductIdQuery, []interface{}{}
+		if scoped {
+			query, args = GetAllGroupByProductIdBySellerQuery, append(args, sellerId)
+		}
+
+		rows, err := r.db.QueryContext(ctx, query, args...)
 		if err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
 
@@ -93,3 +115,250 @@ func (r repository) Create(ctx context.Context, arg domain.ProductRecord) (domai
 
 	return productRecord, nil
 }
+
+// CreateBatch inserts args in one transaction, with multi-row inserts of up
+// to BatchSize records each.
+func (r repository) CreateBatch(ctx context.Context, args []domain.ProductRecord) ([]domain.ProductRecord, error) {
+	productRecords := make([]domain.ProductRecord, 0, len(args))
+
+	err := transaction.NewRunner(r.db).Run(ctx, func(ctx context.Context) error {
+		var increment int
+		err := transaction.From(ctx, r.db).QueryRowContext(ctx, AutoIncrementIncrementQuery).Scan(&increment)
+		if err != nil {
+			return err
+		}
+
+		for start := 0; start < len(args); start += BatchSize {
+			end := start + BatchSize
+			if end > len(args) {
+				end = len(args)
+			}
+			chunk := args[start:end]
+
+			values := make([]interface{}, 0, len(chunk)*4)
+			for _, arg := range chunk {
+				values = append(values, arg.LastUpdateDate, arg.PurchasePrice, arg.SalePrice, arg.ProductId)
+			}
+			query := CreateBatchQuery + strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?), ", len(chunk)), ", ")
+
+			result, err := transaction.From(ctx, r.db).ExecContext(ctx, query, values...)
+			if err != nil {
+				return err
+			}
+
+			// MySQL returns the id of the first row of a multi-row insert.
+			// InnoDB reserves the ids of an INSERT ... VALUES all at once,
+			// whatever innodb_autoinc_lock_mode, so the others follow it
+			// auto_increment_increment apart.
+			id, err := result.LastInsertId()
+			if err != nil {
+				return err
+			}
+
+			for i, arg := range chunk {
+				arg.Id = int(id) + i*increment
+				productRecords = append(productRecords, arg)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return nil, err
+	}
+
+	return productRecords, nil
+}
+
+// UpdateBatch replaces the fields of the records of args, by id, in one
+// transaction.
+func (r repository) UpdateBatch(ctx context.Context, args []domain.ProductRecord) error {
+	err := transaction.NewRunner(r.db).Run(ctx, func(ctx context.Context) error {
+		for _, arg := range args {
+			_, err := transaction.From(ctx, r.db).ExecContext(
+				ctx,
+				UpdateQuery,
+				arg.LastUpdateDate,
+				arg.PurchasePrice,
+				arg.SalePrice,
+				arg.ProductId,
+				arg.Id,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return err
+	}
+
+	return nil
+}
+
+// ExistingIds reports which of ids belong to records of products that can be
+// read, in a single query.
+func (r repository) ExistingIds(ctx context.Context, ids []int) (map[int]bool, error) {
+	existing := map[int]bool{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	query, args := ExistingIdsQuery, []interface{}{}
+	if sellerId, scoped := tenant.SellerId(ctx); scoped {
+		query, args = ExistingIdsBySellerQuery, append(args, sellerId)
+	}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query = fmt.Sprintf(query, strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "))
+
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+			return nil, err
+		}
+		existing[id] = true
+	}
+
+	return existing, rows.Err()
+}
+
+// ReferencedIds reports which of ids belong to records purchase orders were
+// priced with, in a single query.
+func (r repository) ReferencedIds(ctx context.Context, ids []int) (map[int]bool, error) {
+	referenced := map[int]bool{}
+	if len(ids) == 0 {
+		return referenced, nil
+	}
+
+	args := make([]interface{}, 0, 2*len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, args...)
+	query := fmt.Sprintf(ReferencedIdsQuery, strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "))
+
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+			return nil, err
+		}
+		referenced[id] = true
+	}
+
+	return referenced, rows.Err()
+}
+
+// GetHistory lists the records of productId, or of every product when it is
+// 0, updated within dates, ordered by product and then by update. The records
+// of a product start with the last one updated before dates, when there is
+// one, so the first change within dates can be measured.
+func (r repository) GetHistory(ctx context.Context, productId int, dates period.Range) ([]domain.PriceRecord, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if productId != 0 {
+		conditions, args = append(conditions, "product_records.product_id = ?"), append(args, productId)
+	}
+	if sellerId, scoped := tenant.SellerId(ctx); scoped {
+		conditions, args = append(conditions, "products.seller_id = ?"), append(args, sellerId)
+	}
+	if !dates.From.IsZero() {
+		conditions = append(conditions, "(DATE(product_records.last_update_date) >= ? OR product_records.id = ("+GetHistoryPrevious+"))")
+		args = append(args, dates.From, dates.From)
+	}
+	if !dates.To.IsZero() {
+		conditions, args = append(conditions, "DATE(product_records.last_update_date) <= ?"), append(args, dates.To)
+	}
+
+	query := GetHistoryQuery
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.db.QueryContext(ctx, query+GetHistoryOrder, args...)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []domain.PriceRecord{}
+	for rows.Next() {
+		var record domain.PriceRecord
+
+		err := rows.Scan(
+			&record.Id,
+			&record.LastUpdateDate,
+			&record.PurchasePrice,
+			&record.SalePrice,
+			&record.ProductId,
+			&record.Description,
+		)
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// GetCurrent returns the latest record of productId that is already in
+// effect, or domain.ErrNoCurrentRecord when every record is scheduled for
+// later or there is none.
+func (r repository) GetCurrent(ctx context.Context, productId int) (domain.ProductRecord, error) {
+	var record domain.ProductRecord
+
+	err := transaction.From(ctx, r.db).QueryRowContext(ctx, GetCurrentQuery, productId, date.Now()).Scan(
+		&record.Id,
+		&record.LastUpdateDate,
+		&record.PurchasePrice,
+		&record.SalePrice,
+		&record.ProductId,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ProductRecord{}, domain.ErrNoCurrentRecord
+	}
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return domain.ProductRecord{}, err
+	}
+
+	return record, nil
+}
@@ -4,11 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"fmt"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/tenant"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,7 +36,7 @@ var (
 	noProductRecordsCount = []domain.ProductRecordCount{}
 	productRecord         = domain.ProductRecord{
 		Id:             1,
-		LastUpdateDate: "2022-07-09",
+		LastUpdateDate: date.NewDateTime(time.Date(2022, 7, 9, 0, 0, 0, 0, time.UTC)),
 		PurchasePrice:  25.50,
 		SalePrice:      49.99,
 	}
@@ -168,6 +174,33 @@ func TestMariaDB_GetByProductId(t *testing.T) {
 	}
 }
 
+func TestMariaDB_GetByProductId_SellerScoped(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	scopedCtx := tenant.WithSeller(ctx, 9)
+	repository := NewRepository(db)
+
+	rows := sqlmock.NewRows([]string{"product_id", "description", "records_count"}).AddRow(
+		firstProductRecordsCount.ProductId,
+		firstProductRecordsCount.Description,
+		firstProductRecordsCount.RecordsCount,
+	)
+	mock.ExpectQuery(regexp.QuoteMeta(GetAllGroupByProductIdBySellerQuery)).WithArgs(9).WillReturnRows(rows)
+
+	result, err := repository.GetByProductId(scopedCtx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ProductRecordCount{firstProductRecordsCount}, result)
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetAllGroupByProductIdWhereIdBySellerQuery)).
+		WithArgs(secondProductRecordsCount.ProductId, 9).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repository.GetByProductId(scopedCtx, secondProductRecordsCount.ProductId)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
 func TestMariaDB_Create(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -250,3 +283,277 @@ func TestMariaDB_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestMariaDB_CreateBatch(t *testing.T) {
+	second := productRecord
+	second.Id = 0
+	second.SalePrice = 59.99
+
+	query := CreateBatchQuery + "(?, ?, ?, ?), (?, ?, ?, ?)"
+	args := []driver.Value{
+		productRecord.LastUpdateDate, productRecord.PurchasePrice, productRecord.SalePrice, productRecord.ProductId,
+		second.LastUpdateDate, second.PurchasePrice, second.SalePrice, second.ProductId,
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(AutoIncrementIncrementQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"increment"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(args...).WillReturnResult(sqlmock.NewResult(10, 2))
+		mock.ExpectCommit()
+
+		result, err := NewRepository(db).CreateBatch(ctx, []domain.ProductRecord{productRecord, second})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, result[0].Id)
+		assert.Equal(t, 11, result[1].Id)
+		assert.Equal(t, second.SalePrice, result[1].SalePrice)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(AutoIncrementIncrementQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"increment"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(args...).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		_, err = NewRepository(db).CreateBatch(ctx, []domain.ProductRecord{productRecord, second})
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Increment", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(AutoIncrementIncrementQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"increment"}).AddRow(2))
+		mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(args...).WillReturnResult(sqlmock.NewResult(11, 2))
+		mock.ExpectCommit()
+
+		result, err := NewRepository(db).CreateBatch(ctx, []domain.ProductRecord{productRecord, second})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 11, result[0].Id)
+		assert.Equal(t, 13, result[1].Id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Split in statements", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		records := make([]domain.ProductRecord, BatchSize+1)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(AutoIncrementIncrementQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"increment"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta(CreateBatchQuery + strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?), ", BatchSize), ", "))).
+			WillReturnResult(sqlmock.NewResult(1, BatchSize))
+		mock.ExpectExec(regexp.QuoteMeta(CreateBatchQuery + "(?, ?, ?, ?)")).
+			WillReturnResult(sqlmock.NewResult(BatchSize+1, 1))
+		mock.ExpectCommit()
+
+		result, err := NewRepository(db).CreateBatch(ctx, records)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, BatchSize+1)
+		assert.Equal(t, BatchSize+1, result[BatchSize].Id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMariaDB_UpdateBatch(t *testing.T) {
+	second := productRecord
+	second.Id = 2
+	second.SalePrice = 59.99
+
+	t.Run("OK", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		for _, record := range []domain.ProductRecord{productRecord, second} {
+			mock.ExpectExec(regexp.QuoteMeta(UpdateQuery)).
+				WithArgs(record.LastUpdateDate, record.PurchasePrice, record.SalePrice, record.ProductId, record.Id).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectCommit()
+
+		err = NewRepository(db).UpdateBatch(ctx, []domain.ProductRecord{productRecord, second})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(UpdateQuery)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(UpdateQuery)).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		err = NewRepository(db).UpdateBatch(ctx, []domain.ProductRecord{productRecord, second})
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMariaDB_ReferencedIds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repository := NewRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(ReferencedIdsQuery, "?, ?"))).
+		WithArgs(1, 2, 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"product_record_id"}).AddRow(2))
+
+	referenced, err := repository.ReferencedIds(ctx, []int{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]bool{2: true}, referenced)
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(ReferencedIdsQuery, "?"))).WillReturnError(sql.ErrConnDone)
+
+	_, err = repository.ReferencedIds(ctx, []int{1})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	referenced, err = repository.ReferencedIds(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, referenced)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMariaDB_ExistingIds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repository := NewRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(ExistingIdsQuery, "?, ?"))).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	existing, err := repository.ExistingIds(ctx, []int{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]bool{1: true}, existing)
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(ExistingIdsBySellerQuery, "?"))).
+		WithArgs(9, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	existing, err = repository.ExistingIds(tenant.WithSeller(ctx, 9), []int{1})
+	assert.NoError(t, err)
+	assert.Empty(t, existing)
+
+	existing, err = repository.ExistingIds(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, existing)
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(ExistingIdsQuery, "?"))).WillReturnError(sql.ErrConnDone)
+
+	_, err = repository.ExistingIds(ctx, []int{1})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMariaDB_GetHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repository := NewRepository(db)
+	columns := []string{"id", "last_update_date", "purchase_price", "sale_price", "product_id", "description"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetHistoryQuery + GetHistoryOrder)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			productRecord.Id, productRecord.LastUpdateDate.Time, productRecord.PurchasePrice, productRecord.SalePrice, 2, "Chocolate",
+		))
+
+	result, err := repository.GetHistory(ctx, 0, period.Range{})
+	assert.NoError(t, err)
+
+	record := productRecord
+	record.ProductId = 2
+	assert.Equal(t, []domain.PriceRecord{{ProductRecord: record, Description: "Chocolate"}}, result)
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetHistoryQuery+"WHERE product_records.product_id = ? AND products.seller_id = ?"+GetHistoryOrder)).
+		WithArgs(2, 9).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	result, err = repository.GetHistory(tenant.WithSeller(ctx, 9), 2, period.Range{})
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+
+	dates := period.Range{From: date.New(2022, 7, 1), To: date.New(2022, 7, 31)}
+	mock.ExpectQuery(regexp.QuoteMeta(GetHistoryQuery+
+		"WHERE (DATE(product_records.last_update_date) >= ? OR product_records.id = ("+GetHistoryPrevious+"))"+
+		" AND DATE(product_records.last_update_date) <= ?"+GetHistoryOrder)).
+		WithArgs(dates.From, dates.From, dates.To).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	result, err = repository.GetHistory(ctx, 0, dates)
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetHistoryQuery)).WillReturnError(sql.ErrConnDone)
+
+	_, err = repository.GetHistory(ctx, 0, period.Range{})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+}
+
+func TestMariaDB_GetCurrent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	productRecordRepo := NewRepository(db)
+	columns := []string{"id", "last_update_date", "purchase_price", "sale_price", "product_id"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetCurrentQuery)).
+		WithArgs(2, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			productRecord.Id, productRecord.LastUpdateDate.Time, productRecord.PurchasePrice, productRecord.SalePrice, 2,
+		))
+
+	result, err := productRecordRepo.GetCurrent(context.TODO(), 2)
+	assert.NoError(t, err)
+
+	record := productRecord
+	record.ProductId = 2
+	assert.Equal(t, record, result)
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetCurrentQuery)).
+		WithArgs(3, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	_, err = productRecordRepo.GetCurrent(context.TODO(), 3)
+	assert.ErrorIs(t, err, domain.ErrNoCurrentRecord)
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetCurrentQuery)).WillReturnError(sql.ErrConnDone)
+
+	_, err = productRecordRepo.GetCurrent(context.TODO(), 4)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/money"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+)
+
+// GetMargins groups the records of productId, or of every product when it
+// is 0, updated within dates by unit.
+func (s service) GetMargins(ctx context.Context, productId int, dates period.Range, unit period.Unit) ([]domain.ProductMargins, error) {
+	history, err := s.getHistory(ctx, productId, dates)
+	if err != nil {
+		return []domain.ProductMargins{}, err
+	}
+
+	margins := []domain.ProductMargins{}
+	for _, records := range byProduct(history) {
+		product := domain.ProductMargins{
+			ProductId:   records[0].ProductId,
+			Description: records[0].Description,
+		}
+
+		periods := period.NewGrouping(unit, func(key string) domain.MarginPeriod {
+			return domain.MarginPeriod{Period: key}
+		})
+		for _, record := range records {
+			if !dates.Contains(record.LastUpdateDate.Date()) {
+				continue
+			}
+
+			// Records come in update order, so the last one of the period
+			// sets its prices.
+			margin := periods.Of(record.LastUpdateDate.Date())
+			margin.RecordsCount++
+			margin.PurchasePrice = record.PurchasePrice
+			margin.SalePrice = record.SalePrice
+			margin.Margin = money.Round(record.SalePrice - record.PurchasePrice)
+			margin.MarginPercent = 0
+			if record.SalePrice != 0 {
+				margin.MarginPercent = money.Round((record.SalePrice - record.PurchasePrice) / record.SalePrice * 100)
+			}
+		}
+		product.Periods = periods.Rows()
+
+		if len(product.Periods) > 0 {
+			margins = append(margins, product)
+		}
+	}
+
+	return margins, nil
+}
+
+// GetPriceChanges compares every record of productId, or of every product
+// when it is 0, with the one before it and returns the prices that changed
+// by more than threshold percent in records updated within dates.
+func (s service) GetPriceChanges(ctx context.Context, productId int, dates period.Range, threshold float64) ([]domain.PriceChange, error) {
+	history, err := s.getHistory(ctx, productId, dates)
+	if err != nil {
+		return []domain.PriceChange{}, err
+	}
+
+	changes := []domain.PriceChange{}
+	for _, records := range byProduct(history) {
+		for i := 1; i < len(records); i++ {
+			previous, record := records[i-1], records[i]
+			if !dates.Contains(record.LastUpdateDate.Date()) {
+				continue
+			}
+
+			for _, price := range []struct {
+				name     domain.Price
+				old, new float64
+			}{
+				{domain.PurchasePrice, previous.PurchasePrice, record.PurchasePrice},
+				{domain.SalePrice, previous.SalePrice, record.SalePrice},
+			} {
+				if price.old == 0 {
+					continue
+				}
+
+				change := (price.new - price.old) / price.old * 100
+				if math.Abs(change) <= threshold {
+					continue
+				}
+
+				changes = append(changes, domain.PriceChange{
+					ProductId:      record.ProductId,
+					Description:    record.Description,
+					RecordId:       record.Id,
+					LastUpdateDate: record.LastUpdateDate,
+					Price:          price.name,
+					OldPrice:       price.old,
+					NewPrice:       price.new,
+					ChangePercent:  money.Round(change),
+				})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// GetBelowCost lists the products whose latest record in effect has a sale
+// price lower than the purchase price. Records scheduled for later are left
+// out, as they do not price anything yet.
+func (s service) GetBelowCost(ctx context.Context) ([]domain.BelowCost, error) {
+	now := date.Now()
+
+	history, err := s.getHistory(ctx, 0, period.Range{To: now.Date()})
+	if err != nil {
+		return []domain.BelowCost{}, err
+	}
+
+	products := []domain.BelowCost{}
+	for _, records := range byProduct(history) {
+		// Records of today may still be scheduled for a later hour.
+		last := len(records) - 1
+		for last >= 0 && records[last].LastUpdateDate.After(now.Time) {
+			last--
+		}
+		if last < 0 {
+			continue
+		}
+
+		latest := records[last]
+		if latest.SalePrice >= latest.PurchasePrice {
+			continue
+		}
+
+		products = append(products, domain.BelowCost{
+			ProductId:      latest.ProductId,
+			Description:    latest.Description,
+			RecordId:       latest.Id,
+			LastUpdateDate: latest.LastUpdateDate,
+			PurchasePrice:  latest.PurchasePrice,
+			SalePrice:      latest.SalePrice,
+			Loss:           money.Round(latest.PurchasePrice - latest.SalePrice),
+		})
+	}
+
+	return products, nil
+}
+
+func (s service) getHistory(ctx context.Context, productId int, dates period.Range) ([]domain.PriceRecord, error) {
+	if productId != 0 {
+		if _, err := s.productRepository.GetById(ctx, productId); err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+			return nil, err
+		}
+	}
+
+	history, err := s.productRecordRepository.GetHistory(ctx, productId, dates)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// byProduct splits history, which is ordered by product, into the records
+// of each product.
+func byProduct(history []domain.PriceRecord) [][]domain.PriceRecord {
+	var products [][]domain.PriceRecord
+
+	for len(history) > 0 {
+		end := 1
+		for end < len(history) && history[end].ProductId == history[0].ProductId {
+			end++
+		}
+		products, history = append(products, history[:end]), history[end:]
+	}
+
+	return products
+}
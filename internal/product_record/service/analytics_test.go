@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func priceRecord(id, productId int, day time.Time, purchasePrice, salePrice float64) domain.PriceRecord {
+	return domain.PriceRecord{
+		ProductRecord: domain.ProductRecord{
+			Id:             id,
+			LastUpdateDate: date.NewDateTime(day),
+			PurchasePrice:  purchasePrice,
+			SalePrice:      salePrice,
+			ProductId:      productId,
+		},
+		Description: map[int]string{1: "Chocolate", 2: "Ice Cream"}[productId],
+	}
+}
+
+var history = []domain.PriceRecord{
+	priceRecord(1, 1, time.Date(2022, 6, 20, 10, 0, 0, 0, time.UTC), 10, 15),
+	priceRecord(2, 1, time.Date(2022, 7, 2, 10, 0, 0, 0, time.UTC), 10, 16),
+	priceRecord(3, 1, time.Date(2022, 7, 28, 10, 0, 0, 0, time.UTC), 12, 16),
+	priceRecord(4, 2, time.Date(2022, 7, 5, 10, 0, 0, 0, time.UTC), 20, 25),
+	priceRecord(5, 2, time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC), 20, 18),
+}
+
+func TestGetMargins(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		productRecordRepository, _, service, ctx := callMock(t)
+
+		dates := period.Range{From: date.New(2022, 7, 1)}
+		productRecordRepository.EXPECT().GetHistory(ctx, GET_ALL_ID, dates).Times(ONCE).Return(history, nil)
+
+		result, err := service.GetMargins(ctx, GET_ALL_ID, dates, period.Month)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.ProductMargins{
+			{
+				ProductId:   1,
+				Description: "Chocolate",
+				Periods: []domain.MarginPeriod{
+					{Period: "2022-07", RecordsCount: 2, PurchasePrice: 12, SalePrice: 16, Margin: 4, MarginPercent: 25},
+				},
+			},
+			{
+				ProductId:   2,
+				Description: "Ice Cream",
+				Periods: []domain.MarginPeriod{
+					{Period: "2022-07", RecordsCount: 1, PurchasePrice: 20, SalePrice: 25, Margin: 5, MarginPercent: 20},
+					{Period: "2022-08", RecordsCount: 1, PurchasePrice: 20, SalePrice: 18, Margin: -2, MarginPercent: -11.11},
+				},
+			},
+		}, result)
+	})
+
+	t.Run("Product not found", func(t *testing.T) {
+		_, productRepository, service, ctx := callMock(t)
+
+		productRepository.EXPECT().GetById(ctx, 3).Times(ONCE).Return(emptyProduct, someError)
+
+		_, err := service.GetMargins(ctx, 3, period.Range{}, period.Month)
+
+		assert.ErrorIs(t, err, someError)
+	})
+}
+
+func TestGetPriceChanges(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		productRecordRepository, productRepository, service, ctx := callMock(t)
+
+		productRepository.EXPECT().GetById(ctx, 1).Times(ONCE).Return(emptyProduct, nil)
+		productRecordRepository.EXPECT().GetHistory(ctx, 1, period.Range{}).Times(ONCE).Return(history[:3], nil)
+
+		result, err := service.GetPriceChanges(ctx, 1, period.Range{}, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.PriceChange{
+			{
+				ProductId:      1,
+				Description:    "Chocolate",
+				RecordId:       3,
+				LastUpdateDate: history[2].LastUpdateDate,
+				Price:          domain.PurchasePrice,
+				OldPrice:       10,
+				NewPrice:       12,
+				ChangePercent:  20,
+			},
+		}, result)
+	})
+
+	t.Run("Within range", func(t *testing.T) {
+		productRecordRepository, _, service, ctx := callMock(t)
+
+		// The repository starts each product with its last record before the
+		// range, which only serves as the base of the first change.
+		dates := period.Range{From: date.New(2022, 8, 1)}
+		productRecordRepository.EXPECT().GetHistory(ctx, GET_ALL_ID, dates).Times(ONCE).Return(
+			[]domain.PriceRecord{history[2], history[3], history[4]}, nil,
+		)
+
+		result, err := service.GetPriceChanges(ctx, GET_ALL_ID, dates, 5)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, 5, result[0].RecordId)
+		assert.Equal(t, domain.SalePrice, result[0].Price)
+		assert.Equal(t, -28.0, result[0].ChangePercent)
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		productRecordRepository, _, service, ctx := callMock(t)
+
+		productRecordRepository.EXPECT().GetHistory(ctx, GET_ALL_ID, period.Range{}).Times(ONCE).Return(nil, someError)
+
+		_, err := service.GetPriceChanges(ctx, GET_ALL_ID, period.Range{}, 10)
+
+		assert.ErrorIs(t, err, someError)
+	})
+}
+
+func TestGetBelowCost(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		productRecordRepository, _, service, ctx := callMock(t)
+
+		productRecordRepository.EXPECT().GetHistory(ctx, GET_ALL_ID, gomock.Any()).Times(ONCE).Return(history, nil)
+
+		result, err := service.GetBelowCost(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.BelowCost{
+			{
+				ProductId:      2,
+				Description:    "Ice Cream",
+				RecordId:       5,
+				LastUpdateDate: history[4].LastUpdateDate,
+				PurchasePrice:  20,
+				SalePrice:      18,
+				Loss:           2,
+			},
+		}, result)
+	})
+
+	t.Run("Scheduled records", func(t *testing.T) {
+		productRecordRepository, _, service, ctx := callMock(t)
+
+		later := date.Now().Add(time.Hour)
+		productRecordRepository.EXPECT().
+			GetHistory(ctx, GET_ALL_ID, gomock.Any()).
+			Times(ONCE).
+			DoAndReturn(func(_ context.Context, _ int, dates period.Range) ([]domain.PriceRecord, error) {
+				// Only records up to today are read.
+				assert.True(t, dates.From.IsZero())
+				assert.False(t, dates.To.IsZero())
+
+				return []domain.PriceRecord{
+					priceRecord(6, 1, time.Date(2022, 7, 2, 10, 0, 0, 0, time.UTC), 10, 16),
+					priceRecord(7, 1, later, 10, 5),
+					priceRecord(8, 2, later, 20, 18),
+				}, nil
+			})
+
+		result, err := service.GetBelowCost(ctx)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
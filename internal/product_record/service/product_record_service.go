@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
 	productDomain "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
 )
@@ -47,21 +47,22 @@ func (s service) GetByProductId(ctx context.Context, productId int) ([]domain.Pr
 	return productRecords, nil
 }
 
-func isValidDate(dateString string) bool {
-	layout := "2006-01-02"
+var errPastLastUpdateDate = errors.New("last update date must be greater than or equal current date")
 
-	parsedDate, err := time.Parse(layout, dateString)
-	if err != nil {
-		return false
-	}
+func errProductNotFound(productId int) error {
+	return fmt.Errorf("product with id (%v) not found", productId)
+}
 
-	currentDate := time.Now()
-	currentDateString := currentDate.String()[:10]
-	parsedCurrentDate, _ := time.Parse(layout, currentDateString)
+func errProductRecordNotFound(id int) error {
+	return fmt.Errorf("product record with id (%v) not found", id)
+}
 
-	diff := parsedDate.Sub(parsedCurrentDate)
+func errProductRecordReferenced(id int) error {
+	return fmt.Errorf("product record with id (%v) was used to price purchase orders, create a new record instead", id)
+}
 
-	return diff >= 0
+func isValidDate(lastUpdate date.DateTime) bool {
+	return !lastUpdate.Date().Before(date.Today().Time)
 }
 
 func (s service) Create(ctx context.Context, arg domain.ProductRecord) (
@@ -73,16 +74,14 @@ func (s service) Create(ctx context.Context, arg domain.ProductRecord) (
 	if !isValidDate(arg.LastUpdateDate) {
 		logger.Error(ctx, store.GetPathWithLine(), "invalid date")
 
-		return productRecord,
-			errors.New("last update date must be valid date (ex.: 2020-02-20) and greater than or equal current date")
+		return productRecord, errPastLastUpdateDate
 	}
 
 	_, err := s.productRepository.GetById(ctx, arg.ProductId)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 
-		return productRecord,
-			fmt.Errorf("product with id (%v) not found", arg.ProductId)
+		return productRecord, errProductNotFound(arg.ProductId)
 	}
 
 	productRecord, err = s.productRecordRepository.Create(ctx, arg)
@@ -95,3 +94,107 @@ func (s service) Create(ctx context.Context, arg domain.ProductRecord) (
 
 	return productRecord, nil
 }
+
+// CreateBatch creates the valid records of args together and reports, in the
+// order of args, what happened to each one. The products of all records are
+// checked in a single query.
+func (s service) CreateBatch(ctx context.Context, args []domain.ProductRecord) ([]domain.BatchResult, error) {
+	productIds := make([]int, 0, len(args))
+	for _, arg := range args {
+		productIds = append(productIds, arg.ProductId)
+	}
+
+	existing, err := s.productRepository.ExistingIds(ctx, productIds)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	results := make([]domain.BatchResult, len(args))
+	valid, positions := []domain.ProductRecord{}, []int{}
+	for i, arg := range args {
+		switch {
+		case !isValidDate(arg.LastUpdateDate):
+			results[i].Err = errPastLastUpdateDate
+		case !existing[arg.ProductId]:
+			results[i].Err = errProductNotFound(arg.ProductId)
+		default:
+			valid, positions = append(valid, arg), append(positions, i)
+		}
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	productRecords, err := s.productRecordRepository.CreateBatch(ctx, valid)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, fmt.Errorf("failed to create product records")
+	}
+
+	for i, productRecord := range productRecords {
+		results[positions[i]].Record = productRecord
+	}
+
+	return results, nil
+}
+
+// UpdateBatch updates the valid records of args together and reports, in the
+// order of args, what happened to each one. Records are replaced by id and
+// checked like the ones of CreateBatch; the records and their products are
+// looked up in one query each. Records purchase orders were priced with are
+// price history and are left as they are.
+func (s service) UpdateBatch(ctx context.Context, args []domain.ProductRecord) ([]domain.BatchResult, error) {
+	ids, productIds := make([]int, 0, len(args)), make([]int, 0, len(args))
+	for _, arg := range args {
+		ids, productIds = append(ids, arg.Id), append(productIds, arg.ProductId)
+	}
+
+	existingRecords, err := s.productRecordRepository.ExistingIds(ctx, ids)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	existingProducts, err := s.productRepository.ExistingIds(ctx, productIds)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	referenced, err := s.productRecordRepository.ReferencedIds(ctx, ids)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	results := make([]domain.BatchResult, len(args))
+	valid := []domain.ProductRecord{}
+	for i, arg := range args {
+		switch {
+		case !existingRecords[arg.Id]:
+			results[i].Err = errProductRecordNotFound(arg.Id)
+		case referenced[arg.Id]:
+			results[i].Err = errProductRecordReferenced(arg.Id)
+		case !isValidDate(arg.LastUpdateDate):
+			results[i].Err = errPastLastUpdateDate
+		case !existingProducts[arg.ProductId]:
+			results[i].Err = errProductNotFound(arg.ProductId)
+		default:
+			valid = append(valid, arg)
+			results[i].Record = arg
+		}
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	if err := s.productRecordRepository.UpdateBatch(ctx, valid); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, fmt.Errorf("failed to update product records")
+	}
+
+	return results, nil
+}
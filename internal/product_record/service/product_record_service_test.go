@@ -12,6 +12,7 @@ import (
 	productRecordMockDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain/mock"
 	productDomain "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
 	productMockDomain "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -32,7 +33,7 @@ var (
 	}
 	productRecordWithInvalidUpdateDate = domain.ProductRecord{
 		Id:             1,
-		LastUpdateDate: "2022/07/09",
+		LastUpdateDate: date.NewDateTime(time.Now().AddDate(0, 0, -1)),
 		PurchasePrice:  23.89,
 		SalePrice:      43.99,
 		ProductId:      1,
@@ -60,10 +61,8 @@ var (
 	someError                = errors.New("some error")
 )
 
-func getCurrentDate() string {
-	currentDate := time.Now()
-
-	return currentDate.String()[:10]
+func getCurrentDate() date.DateTime {
+	return date.Now()
 }
 
 func callMock(t *testing.T) (
@@ -182,7 +181,7 @@ func TestCreate(t *testing.T) {
 				assert.Equal(
 					t,
 					errors.New(
-						"last update date must be valid date (ex.: 2020-02-20) and greater than or equal current date",
+						"last update date must be greater than or equal current date",
 					),
 					err,
 				)
@@ -320,3 +319,241 @@ func TestGetByProductId(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateBatch(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		productRecordRepository, productRepository, service, ctx := callMock(t)
+
+		valid := productRecord
+		valid.Id = 0
+		unknownProduct := valid
+		unknownProduct.ProductId = 99
+		pastDate := productRecordWithInvalidUpdateDate
+		pastDate.Id = 0
+
+		created := valid
+		created.Id = 7
+
+		productRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{valid.ProductId, unknownProduct.ProductId, pastDate.ProductId}).
+			Times(ONCE).
+			Return(map[int]bool{valid.ProductId: true}, nil)
+
+		productRecordRepository.
+			EXPECT().
+			CreateBatch(ctx, []domain.ProductRecord{valid}).
+			Times(ONCE).
+			Return([]domain.ProductRecord{created}, nil)
+
+		results, err := service.CreateBatch(ctx, []domain.ProductRecord{valid, unknownProduct, pastDate})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.BatchResult{
+			{Record: created},
+			{Err: fmt.Errorf("product with id (99) not found")},
+			{Err: errPastLastUpdateDate},
+		}, results)
+	})
+
+	t.Run("Nothing to create", func(t *testing.T) {
+		_, productRepository, service, ctx := callMock(t)
+
+		productRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.ProductId}).
+			Times(ONCE).
+			Return(map[int]bool{}, nil)
+
+		results, err := service.CreateBatch(ctx, []domain.ProductRecord{productRecord})
+
+		assert.NoError(t, err)
+		assert.Error(t, results[0].Err)
+	})
+
+	t.Run("Products query fails", func(t *testing.T) {
+		_, productRepository, service, ctx := callMock(t)
+
+		productRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.ProductId}).
+			Times(ONCE).
+			Return(nil, someError)
+
+		_, err := service.CreateBatch(ctx, []domain.ProductRecord{productRecord})
+
+		assert.ErrorIs(t, err, someError)
+	})
+
+	t.Run("Insert fails", func(t *testing.T) {
+		productRecordRepository, productRepository, service, ctx := callMock(t)
+
+		productRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.ProductId}).
+			Times(ONCE).
+			Return(map[int]bool{productRecord.ProductId: true}, nil)
+
+		productRecordRepository.
+			EXPECT().
+			CreateBatch(ctx, []domain.ProductRecord{productRecord}).
+			Times(ONCE).
+			Return(nil, sql.ErrConnDone)
+
+		_, err := service.CreateBatch(ctx, []domain.ProductRecord{productRecord})
+
+		assert.EqualError(t, err, "failed to create product records")
+	})
+}
+
+func TestUpdateBatch(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		productRecordRepository, productRepository, service, ctx := callMock(t)
+
+		unknownRecord := productRecord
+		unknownRecord.Id = 99
+		unknownProduct := productRecord
+		unknownProduct.Id = 2
+		unknownProduct.ProductId = 99
+		pastDate := productRecordWithInvalidUpdateDate
+		pastDate.Id = 3
+		priced := productRecord
+		priced.Id = 4
+
+		ids := []int{productRecord.Id, unknownRecord.Id, unknownProduct.Id, pastDate.Id, priced.Id}
+
+		productRecordRepository.
+			EXPECT().
+			ExistingIds(ctx, ids).
+			Times(ONCE).
+			Return(map[int]bool{productRecord.Id: true, unknownProduct.Id: true, pastDate.Id: true, priced.Id: true}, nil)
+
+		productRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.ProductId, unknownRecord.ProductId, unknownProduct.ProductId, pastDate.ProductId, priced.ProductId}).
+			Times(ONCE).
+			Return(map[int]bool{productRecord.ProductId: true}, nil)
+
+		productRecordRepository.
+			EXPECT().
+			ReferencedIds(ctx, ids).
+			Times(ONCE).
+			Return(map[int]bool{priced.Id: true}, nil)
+
+		productRecordRepository.
+			EXPECT().
+			UpdateBatch(ctx, []domain.ProductRecord{productRecord}).
+			Times(ONCE).
+			Return(nil)
+
+		results, err := service.UpdateBatch(ctx, []domain.ProductRecord{productRecord, unknownRecord, unknownProduct, pastDate, priced})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.BatchResult{
+			{Record: productRecord},
+			{Err: fmt.Errorf("product record with id (99) not found")},
+			{Err: fmt.Errorf("product with id (99) not found")},
+			{Err: errPastLastUpdateDate},
+			{Err: fmt.Errorf("product record with id (4) was used to price purchase orders, create a new record instead")},
+		}, results)
+	})
+
+	t.Run("Nothing to update", func(t *testing.T) {
+		productRecordRepository, productRepository, service, ctx := callMock(t)
+
+		productRecordRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.Id}).
+			Times(ONCE).
+			Return(map[int]bool{}, nil)
+
+		productRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.ProductId}).
+			Times(ONCE).
+			Return(map[int]bool{productRecord.ProductId: true}, nil)
+
+		productRecordRepository.
+			EXPECT().
+			ReferencedIds(ctx, []int{productRecord.Id}).
+			Times(ONCE).
+			Return(map[int]bool{}, nil)
+
+		results, err := service.UpdateBatch(ctx, []domain.ProductRecord{productRecord})
+
+		assert.NoError(t, err)
+		assert.Error(t, results[0].Err)
+	})
+
+	t.Run("Records query fails", func(t *testing.T) {
+		productRecordRepository, _, service, ctx := callMock(t)
+
+		productRecordRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.Id}).
+			Times(ONCE).
+			Return(nil, someError)
+
+		_, err := service.UpdateBatch(ctx, []domain.ProductRecord{productRecord})
+
+		assert.ErrorIs(t, err, someError)
+	})
+
+	t.Run("References query fails", func(t *testing.T) {
+		productRecordRepository, productRepository, service, ctx := callMock(t)
+
+		productRecordRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.Id}).
+			Times(ONCE).
+			Return(map[int]bool{productRecord.Id: true}, nil)
+
+		productRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.ProductId}).
+			Times(ONCE).
+			Return(map[int]bool{productRecord.ProductId: true}, nil)
+
+		productRecordRepository.
+			EXPECT().
+			ReferencedIds(ctx, []int{productRecord.Id}).
+			Times(ONCE).
+			Return(nil, someError)
+
+		_, err := service.UpdateBatch(ctx, []domain.ProductRecord{productRecord})
+
+		assert.ErrorIs(t, err, someError)
+	})
+
+	t.Run("Update fails", func(t *testing.T) {
+		productRecordRepository, productRepository, service, ctx := callMock(t)
+
+		productRecordRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.Id}).
+			Times(ONCE).
+			Return(map[int]bool{productRecord.Id: true}, nil)
+
+		productRepository.
+			EXPECT().
+			ExistingIds(ctx, []int{productRecord.ProductId}).
+			Times(ONCE).
+			Return(map[int]bool{productRecord.ProductId: true}, nil)
+
+		productRecordRepository.
+			EXPECT().
+			ReferencedIds(ctx, []int{productRecord.Id}).
+			Times(ONCE).
+			Return(map[int]bool{}, nil)
+
+		productRecordRepository.
+			EXPECT().
+			UpdateBatch(ctx, []domain.ProductRecord{productRecord}).
+			Times(ONCE).
+			Return(sql.ErrConnDone)
+
+		_, err := service.UpdateBatch(ctx, []domain.ProductRecord{productRecord})
+
+		assert.EqualError(t, err, "failed to update product records")
+	})
+}
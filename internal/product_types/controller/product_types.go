@@ -0,0 +1,264 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/patch"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+)
+
+type ProductTypesController struct {
+	service domain.Service
+}
+
+func NewProductTypesController(s domain.Service) *ProductTypesController {
+	return &ProductTypesController{
+		service: s,
+	}
+}
+
+type productTypeRequest struct {
+	Name               string   `json:"name" binding:"required"`
+	TemperatureClass   string   `json:"temperature_class" binding:"required,oneof=ambient refrigerated frozen"`
+	MinimumTemperature *float64 `json:"minimum_temperature" binding:"required"`
+	MaximumTemperature *float64 `json:"maximum_temperature" binding:"required"`
+}
+
+type updateProductTypeRequest struct {
+	Name               *string                  `json:"name" binding:"omitempty,min=1"`
+	TemperatureClass   *domain.TemperatureClass `json:"temperature_class" binding:"omitempty,oneof=ambient refrigerated frozen"`
+	MinimumTemperature *float64                 `json:"minimum_temperature"`
+	MaximumTemperature *float64                 `json:"maximum_temperature"`
+}
+
+// status maps the errors of the product types service to HTTP statuses.
+func status(err error) int {
+	errNF := &domain.ErrorNotFound{}
+	if errors.As(err, &errNF) {
+		return http.StatusNotFound
+	}
+
+	errCF := &domain.ErrorConflict{}
+	if errors.As(err, &errCF) {
+		return http.StatusConflict
+	}
+
+	if errors.Is(err, domain.ErrTemperatureRange) {
+		return http.StatusUnprocessableEntity
+	}
+
+	if errors.Is(err, etag.ErrMismatch) {
+		return http.StatusPreconditionFailed
+	}
+
+	return http.StatusInternalServerError
+}
+
+// ListProductTypes godoc
+// @Summary      List all product types
+// @Description  List the product types with their temperature class and storage range
+// @Tags         product_types
+// @Produce      json
+// @Param        include_deleted  query  bool  false  "Include soft deleted product types"
+// @Success      200  {array}   domain.ProductType
+// @Failure      400  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /api/v1/productTypes [get]
+func (p *ProductTypesController) GetAll(c *gin.Context) {
+	if err := softdelete.FromQuery(c); err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+		return
+	}
+
+	productTypes, err := p.service.GetAll(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.DecodeError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewResponse(productTypes))
+}
+
+// GetProductType godoc
+// @Summary      Get a product type by id
+// @Tags         product_types
+// @Produce      json
+// @Param        id               path   int   true   "Product type id"
+// @Param        include_deleted  query  bool  false  "Include soft deleted product types"
+// @Success      200  {object}  domain.ProductType
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Router       /api/v1/productTypes/{id} [get]
+func (p *ProductTypesController) GetById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+		return
+	}
+
+	if err := softdelete.FromQuery(c); err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+		return
+	}
+
+	productType, err := p.service.GetById(c, id)
+	if err != nil {
+		c.JSON(status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	etag.Set(c, productType.Version)
+	c.JSON(http.StatusOK, response.NewResponse(productType))
+}
+
+// CreateProductType godoc
+// @Summary      Create a product type
+// @Description  Create a product type. Names are unique, ignoring case
+// @Tags         product_types
+// @Accept       json
+// @Produce      json
+// @Param        productType  body      productTypeRequest  true  "Product type to be created"
+// @Success      201          {object}  domain.ProductType
+// @Failure      409          {object}  response.Response
+// @Failure      422          {object}  response.Response
+// @Router       /api/v1/productTypes [post]
+func (p *ProductTypesController) Create(c *gin.Context) {
+	var req productTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+		return
+	}
+
+	productType, err := p.service.Create(c, domain.ProductType{
+		Name:               req.Name,
+		TemperatureClass:   domain.TemperatureClass(req.TemperatureClass),
+		MinimumTemperature: *req.MinimumTemperature,
+		MaximumTemperature: *req.MaximumTemperature,
+	})
+	if err != nil {
+		c.JSON(status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.NewResponse(productType))
+}
+
+// UpdateProductType godoc
+// @Summary      Update a product type
+// @Description  Update a product type with a JSON merge patch (RFC 7396)
+// @Tags         product_types
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                       true   "Product type id"
+// @Param        If-Match     header    string                    true   "ETag of the product type being updated"
+// @Param        productType  body      updateProductTypeRequest  false  "Fields to update"
+// @Success      200          {object}  domain.ProductType
+// @Failure      400          {object}  response.Response
+// @Failure      404          {object}  response.Response
+// @Failure      409          {object}  response.Response
+// @Failure      412          {object}  response.Response
+// @Failure      422          {object}  response.Response
+// @Failure      428          {object}  response.Response
+// @Router       /api/v1/productTypes/{id} [patch]
+func (p *ProductTypesController) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+		return
+	}
+
+	if err := etag.FromIfMatch(c); err != nil {
+		c.JSON(etag.Status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	var req updateProductTypeRequest
+	if err := patch.Bind(c, &req); err != nil {
+		c.JSON(patch.Status(err), validation.NewErrorResponse(err))
+		return
+	}
+
+	productType, err := p.service.Update(c, id, domain.ProductTypePatch(req))
+	if err != nil {
+		c.JSON(status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	etag.Set(c, productType.Version)
+	c.JSON(http.StatusOK, response.NewResponse(productType))
+}
+
+// DeleteProductType godoc
+// @Summary      Delete a product type
+// @Description  Soft delete a product type. Types still used by products or sections are not deleted
+// @Tags         product_types
+// @Param        id        path    int     true  "Product type id"
+// @Param        If-Match  header  string  true  "ETag of the product type being deleted"
+// @Success      204  "Successfully deleted"
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Failure      412  {object}  response.Response
+// @Failure      428  {object}  response.Response
+// @Router       /api/v1/productTypes/{id} [delete]
+func (p *ProductTypesController) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+		return
+	}
+
+	if err := etag.FromIfMatch(c); err != nil {
+		c.JSON(etag.Status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	if err := p.service.Delete(c, id); err != nil {
+		var dependentsErr *dependents.Error
+		if errors.As(err, &dependentsErr) {
+			c.JSON(http.StatusConflict, response.DecodeDependents(dependentsErr))
+			return
+		}
+
+		c.JSON(status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreProductType godoc
+// @Summary      Restore a product type
+// @Description  Restore a deleted product type, selecting by id
+// @Tags         product_types
+// @Produce      json
+// @Param        id   path      int  true  "Product type id"
+// @Success      200  {object}  domain.ProductType
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /api/v1/productTypes/{id}/restore [post]
+func (p *ProductTypesController) Restore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError("invalid ID"))
+		return
+	}
+
+	productType, err := p.service.Restore(c, id)
+	if err != nil {
+		c.JSON(status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	etag.Set(c, productType.Version)
+	c.JSON(http.StatusOK, response.NewResponse(productType))
+}
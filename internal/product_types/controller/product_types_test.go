@@ -0,0 +1,351 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	pathProductTypes   = "/api/v1/productTypes/"
+	pathIdProductTypes = "/api/v1/productTypes/:id"
+	pathRestoreTypes   = "/api/v1/productTypes/:id/restore"
+)
+
+var dairy = domain.ProductType{Id: 2, Name: "Dairy", TemperatureClass: domain.Refrigerated, MinimumTemperature: 1, MaximumTemperature: 5, Version: 1}
+
+func mockProductTypes(t *testing.T) (*mock_domain.MockService, *ProductTypesController, *gin.Engine) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock_domain.NewMockService(ctrl)
+	return service, NewProductTypesController(service), gin.New()
+}
+
+// ifMatch sends tag as the ETag the request expects the product type to have.
+func ifMatch(req *http.Request, tag string) *http.Request {
+	req.Header.Set(etag.IfMatchHeader, tag)
+	return req
+}
+
+func TestProductTypes_GetAll(t *testing.T) {
+	service, handler, api := mockProductTypes(t)
+	api.GET(pathProductTypes, handler.GetAll)
+
+	service.EXPECT().GetAll(gomock.Any()).Return([]domain.ProductType{dairy}, nil)
+
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathProductTypes, nil))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	body := struct {
+		Data []domain.ProductType `json:"data"`
+	}{}
+	json.Unmarshal(resp.Body.Bytes(), &body)
+	assert.Equal(t, []domain.ProductType{dairy}, body.Data)
+}
+
+func TestProductTypes_GetAll_IncludeDeleted(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.GET(pathProductTypes, handler.GetAll)
+
+		service.EXPECT().GetAll(gomock.Any()).DoAndReturn(func(ctx context.Context) ([]domain.ProductType, error) {
+			assert.True(t, softdelete.IncludeDeleted(ctx))
+			return []domain.ProductType{dairy}, nil
+		})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathProductTypes+"?include_deleted=true", nil))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		_, handler, api := mockProductTypes(t)
+		api.GET(pathProductTypes, handler.GetAll)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathProductTypes+"?include_deleted=maybe", nil))
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestProductTypes_GetById(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.GET(pathIdProductTypes, handler.GetById)
+
+		service.EXPECT().GetById(gomock.Any(), 2).Return(dairy, nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathProductTypes+"2", nil))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.GET(pathIdProductTypes, handler.GetById)
+
+		service.EXPECT().GetById(gomock.Any(), 9).Return(domain.ProductType{}, &domain.ErrorNotFound{Id: 9})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathProductTypes+"9", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		_, handler, api := mockProductTypes(t)
+		api.GET(pathIdProductTypes, handler.GetById)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, pathProductTypes+"xpto", nil))
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestProductTypes_Create(t *testing.T) {
+	payload := `{"name": "Dairy", "temperature_class": "refrigerated", "minimum_temperature": 1, "maximum_temperature": 5}`
+
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.POST(pathProductTypes, handler.Create)
+
+		arg := dairy
+		arg.Id, arg.Version = 0, 0
+		service.EXPECT().Create(gomock.Any(), arg).Return(dairy, nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathProductTypes, strings.NewReader(payload)))
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.POST(pathProductTypes, handler.Create)
+
+		service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.ProductType{}, &domain.ErrorConflict{Name: "Dairy"})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathProductTypes, strings.NewReader(payload)))
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("Inverted temperature range", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.POST(pathProductTypes, handler.Create)
+
+		service.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.ProductType{}, domain.ErrTemperatureRange)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathProductTypes, strings.NewReader(payload)))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	for name, invalid := range map[string]string{
+		"Unknown class":       `{"name": "Dairy", "temperature_class": "warm", "minimum_temperature": 1, "maximum_temperature": 5}`,
+		"Missing temperature": `{"name": "Dairy", "temperature_class": "refrigerated", "minimum_temperature": 1}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, handler, api := mockProductTypes(t)
+			api.POST(pathProductTypes, handler.Create)
+
+			resp := httptest.NewRecorder()
+			api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathProductTypes, strings.NewReader(invalid)))
+
+			assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		})
+	}
+}
+
+func TestProductTypes_Update(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.PATCH(pathIdProductTypes, handler.Update)
+
+		frozen := domain.Frozen
+		service.EXPECT().Update(gomock.Any(), 2, domain.ProductTypePatch{TemperatureClass: &frozen}).Return(dairy, nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodPatch, pathProductTypes+"2", strings.NewReader(`{"temperature_class": "frozen"}`)), `"1"`))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, etag.Format(dairy.Version), resp.Header().Get(etag.Header))
+	})
+
+	t.Run("Without If-Match", func(t *testing.T) {
+		_, handler, api := mockProductTypes(t)
+		api.PATCH(pathIdProductTypes, handler.Update)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPatch, pathProductTypes+"2", strings.NewReader(`{"name": "Milk"}`)))
+
+		assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+	})
+
+	t.Run("Changed since read", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.PATCH(pathIdProductTypes, handler.Update)
+
+		service.EXPECT().Update(gomock.Any(), 2, gomock.Any()).DoAndReturn(func(ctx context.Context, id int, arg domain.ProductTypePatch) (domain.ProductType, error) {
+			version, _ := etag.Version(ctx)
+			assert.Equal(t, 1, version)
+			return domain.ProductType{}, etag.ErrMismatch
+		})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodPatch, pathProductTypes+"2", strings.NewReader(`{"name": "Milk"}`)), `"1"`))
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+	})
+
+	t.Run("Unknown class", func(t *testing.T) {
+		_, handler, api := mockProductTypes(t)
+		api.PATCH(pathIdProductTypes, handler.Update)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodPatch, pathProductTypes+"2", strings.NewReader(`{"temperature_class": "warm"}`)), `"1"`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.PATCH(pathIdProductTypes, handler.Update)
+
+		service.EXPECT().Update(gomock.Any(), 9, gomock.Any()).Return(domain.ProductType{}, &domain.ErrorNotFound{Id: 9})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodPatch, pathProductTypes+"9", strings.NewReader(`{"name": "Milk"}`)), `"1"`))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestProductTypes_Delete(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.DELETE(pathIdProductTypes, handler.Delete)
+
+		service.EXPECT().Delete(gomock.Any(), 2).Return(nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodDelete, pathProductTypes+"2", nil), `"1"`))
+
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+	})
+
+	t.Run("Without If-Match", func(t *testing.T) {
+		_, handler, api := mockProductTypes(t)
+		api.DELETE(pathIdProductTypes, handler.Delete)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, pathProductTypes+"2", nil))
+
+		assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+	})
+
+	t.Run("Changed since read", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.DELETE(pathIdProductTypes, handler.Delete)
+
+		service.EXPECT().Delete(gomock.Any(), 2).Return(etag.ErrMismatch)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodDelete, pathProductTypes+"2", nil), `"1"`))
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+	})
+
+	t.Run("In use", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.DELETE(pathIdProductTypes, handler.Delete)
+
+		service.EXPECT().Delete(gomock.Any(), 2).Return(dependents.Check("product type", 2, dependents.Dependent{Type: "products", Count: 3}))
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodDelete, pathProductTypes+"2", nil), `"1"`))
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.DELETE(pathIdProductTypes, handler.Delete)
+
+		service.EXPECT().Delete(gomock.Any(), 9).Return(&domain.ErrorNotFound{Id: 9})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodDelete, pathProductTypes+"9", nil), `"1"`))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.DELETE(pathIdProductTypes, handler.Delete)
+
+		service.EXPECT().Delete(gomock.Any(), 2).Return(errors.New("some error"))
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, ifMatch(httptest.NewRequest(http.MethodDelete, pathProductTypes+"2", nil), `"1"`))
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func TestProductTypes_Restore(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.POST(pathRestoreTypes, handler.Restore)
+
+		service.EXPECT().Restore(gomock.Any(), 2).Return(dairy, nil)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathProductTypes+"2/restore", nil))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		service, handler, api := mockProductTypes(t)
+		api.POST(pathRestoreTypes, handler.Restore)
+
+		service.EXPECT().Restore(gomock.Any(), 9).Return(domain.ProductType{}, &domain.ErrorNotFound{Id: 9})
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathProductTypes+"9/restore", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		_, handler, api := mockProductTypes(t)
+		api.POST(pathRestoreTypes, handler.Restore)
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, pathProductTypes+"xpto/restore", nil))
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
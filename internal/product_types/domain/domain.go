@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+)
+
+// TemperatureClass is how a product type has to be kept.
+type TemperatureClass string
+
+const (
+	Ambient      TemperatureClass = "ambient"
+	Refrigerated TemperatureClass = "refrigerated"
+	Frozen       TemperatureClass = "frozen"
+)
+
+// ProductType groups products with the same storage needs. The temperature
+// range is the default storage constraint of its products and sections.
+type ProductType struct {
+	Id                 int              `json:"id"`
+	Name               string           `json:"name"`
+	TemperatureClass   TemperatureClass `json:"temperature_class"`
+	MinimumTemperature float64          `json:"minimum_temperature"`
+	MaximumTemperature float64          `json:"maximum_temperature"`
+	DeletedAt          date.DateTime    `json:"deleted_at"`
+	Version            int              `json:"version"`
+}
+
+// ProductTypePatch holds the fields of a partial update. Nil fields are left
+// untouched.
+type ProductTypePatch struct {
+	Name               *string           `json:"name"`
+	TemperatureClass   *TemperatureClass `json:"temperature_class"`
+	MinimumTemperature *float64          `json:"minimum_temperature"`
+	MaximumTemperature *float64          `json:"maximum_temperature"`
+}
+
+// ErrTemperatureRange is returned when the minimum temperature of a product
+// type is above its maximum.
+var ErrTemperatureRange = errors.New("minimum temperature must not be greater than maximum temperature")
+
+//go:generate mockgen -source=./domain.go -destination=./mock/domain_mock.go
+type Repository interface {
+	GetAll(ctx context.Context) ([]ProductType, error)
+	GetById(ctx context.Context, id int) (ProductType, error)
+	Create(ctx context.Context, arg ProductType) (ProductType, error)
+	Update(ctx context.Context, arg ProductType) (ProductType, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Dependents(ctx context.Context, id int) ([]dependents.Dependent, error)
+}
+
+type Service interface {
+	GetAll(ctx context.Context) ([]ProductType, error)
+	GetById(ctx context.Context, id int) (ProductType, error)
+	Create(ctx context.Context, arg ProductType) (ProductType, error)
+	Update(ctx context.Context, id int, arg ProductTypePatch) (ProductType, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) (ProductType, error)
+}
+
+type ErrorNotFound struct {
+	Id int
+}
+
+func (e *ErrorNotFound) Error() string {
+	return fmt.Sprintf("product type %d not found", e.Id)
+}
+
+type ErrorConflict struct {
+	Name string
+}
+
+func (e *ErrorConflict) Error() string {
+	return fmt.Sprintf("a product type named %q already exists", e.Name)
+}
@@ -0,0 +1,18 @@
+package repository
+
+const (
+	querySelect        = "SELECT id, name, temperature_class, minimum_temperature, maximum_temperature, deleted_at, version FROM product_types"
+	queryGetAll        = querySelect + " WHERE deleted_at IS NULL ORDER BY id"
+	queryGetById       = querySelect + " WHERE id = ? AND deleted_at IS NULL"
+	queryCreate        = "INSERT INTO product_types (name, temperature_class, minimum_temperature, maximum_temperature) VALUES (?, ?, ?, ?)"
+	queryUpdate        = "UPDATE product_types SET name = ?, temperature_class = ?, minimum_temperature = ?, maximum_temperature = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL AND version = ?"
+	queryDelete        = "UPDATE product_types SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL"
+	queryRestore       = "UPDATE product_types SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL"
+	queryCountProducts = "SELECT COUNT(*) FROM products WHERE product_type_id = ?"
+	queryCountSections = "SELECT COUNT(*) FROM section WHERE product_type_id = ?"
+
+	// Variants that also read soft deleted product types, used when the
+	// request has ?include_deleted=true.
+	queryGetAllWithDeleted  = querySelect + " ORDER BY id"
+	queryGetByIdWithDeleted = querySelect + " WHERE id = ?"
+)
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
+)
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domain.Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (domain.ProductType, error) {
+	var productType domain.ProductType
+	err := row.Scan(
+		&productType.Id,
+		&productType.Name,
+		&productType.TemperatureClass,
+		&productType.MinimumTemperature,
+		&productType.MaximumTemperature,
+		&productType.DeletedAt,
+		&productType.Version,
+	)
+	return productType, err
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	query := queryGetAll
+	if softdelete.IncludeDeleted(ctx) {
+		query = queryGetAllWithDeleted
+	}
+
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	productTypes := []domain.ProductType{}
+	for rows.Next() {
+		productType, err := scan(rows)
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			return nil, err
+		}
+		productTypes = append(productTypes, productType)
+	}
+	return productTypes, rows.Err()
+}
+
+func (r *repository) GetById(ctx context.Context, id int) (domain.ProductType, error) {
+	query := queryGetById
+	if softdelete.IncludeDeleted(ctx) {
+		query = queryGetByIdWithDeleted
+	}
+
+	productType, err := scan(transaction.From(ctx, r.db).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ProductType{}, &domain.ErrorNotFound{Id: id}
+	}
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.ProductType{}, err
+	}
+	return productType, nil
+}
+
+func (r *repository) Create(ctx context.Context, arg domain.ProductType) (domain.ProductType, error) {
+	result, err := transaction.From(ctx, r.db).ExecContext(
+		ctx,
+		queryCreate,
+		arg.Name,
+		arg.TemperatureClass,
+		arg.MinimumTemperature,
+		arg.MaximumTemperature,
+	)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.ProductType{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.ProductType{}, err
+	}
+
+	arg.Id = int(id)
+	arg.Version = 1
+	return arg, nil
+}
+
+func (r *repository) Update(ctx context.Context, arg domain.ProductType) (domain.ProductType, error) {
+	result, err := transaction.From(ctx, r.db).ExecContext(
+		ctx,
+		queryUpdate,
+		arg.Name,
+		arg.TemperatureClass,
+		arg.MinimumTemperature,
+		arg.MaximumTemperature,
+		arg.Id,
+		arg.Version,
+	)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.ProductType{}, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return domain.ProductType{}, err
+	}
+	// The product type was read with this version, so no rows means someone
+	// else wrote it in between.
+	if affected == 0 {
+		return domain.ProductType{}, etag.ErrMismatch
+	}
+
+	arg.Version++
+	return arg, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id int) error {
+	return r.exec(ctx, id, queryDelete, date.Now(), id)
+}
+
+func (r *repository) Restore(ctx context.Context, id int) error {
+	return r.exec(ctx, id, queryRestore, id)
+}
+
+// exec runs a statement that must change the product type id, requiring the
+// version the client read, when there is one.
+func (r *repository) exec(ctx context.Context, id int, query string, args ...interface{}) error {
+	version, versioned := etag.Version(ctx)
+	if versioned {
+		query, args = query+etag.Condition, append(args, version)
+	}
+
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// The product type is there, just not in the version the request
+		// expects.
+		if versioned {
+			if _, err := r.GetById(ctx, id); err == nil {
+				return etag.ErrMismatch
+			}
+		}
+		return &domain.ErrorNotFound{Id: id}
+	}
+	return nil
+}
+
+// Dependents counts the products and sections of the product type. Soft
+// deleted rows are counted too, since they still hold the foreign key.
+func (r *repository) Dependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	var products, sections int
+
+	if err := transaction.From(ctx, r.db).QueryRowContext(ctx, queryCountProducts, id).Scan(&products); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	if err := transaction.From(ctx, r.db).QueryRowContext(ctx, queryCountSections, id).Scan(&sections); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	return []dependents.Dependent{
+		{Type: "products", Count: products},
+		{Type: "sections", Count: sections},
+	}, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+)
+
+type auditedService struct {
+	domain.Service
+	audit auditDomain.Service
+}
+
+// NewAuditedService records every change made through s in the audit trail.
+func NewAuditedService(s domain.Service, audit auditDomain.Service) domain.Service {
+	return &auditedService{s, audit}
+}
+
+func (s *auditedService) Create(ctx context.Context, arg domain.ProductType) (domain.ProductType, error) {
+	productType, err := s.Service.Create(ctx, arg)
+	if err != nil {
+		return domain.ProductType{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityProductTypes, productType.Id, auditDomain.ActionCreate, nil, productType)
+
+	return productType, nil
+}
+
+func (s *auditedService) Update(ctx context.Context, id int, arg domain.ProductTypePatch) (domain.ProductType, error) {
+	before, _ := s.Service.GetById(ctx, id)
+
+	productType, err := s.Service.Update(ctx, id, arg)
+	if err != nil {
+		return domain.ProductType{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityProductTypes, id, auditDomain.ActionUpdate, before, productType)
+
+	return productType, nil
+}
+
+func (s *auditedService) Delete(ctx context.Context, id int) error {
+	before, _ := s.Service.GetById(ctx, id)
+
+	if err := s.Service.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityProductTypes, id, auditDomain.ActionDelete, before, nil)
+
+	return nil
+}
+
+func (s *auditedService) Restore(ctx context.Context, id int) (domain.ProductType, error) {
+	before, _ := s.Service.GetById(softdelete.WithDeleted(ctx), id)
+
+	productType, err := s.Service.Restore(ctx, id)
+	if err != nil {
+		return domain.ProductType{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityProductTypes, id, auditDomain.ActionRestore, before, productType)
+
+	return productType, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	mock_audit "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditedService(t *testing.T) {
+	before := dairy
+	after := dairy
+	after.MaximumTemperature = 8
+
+	t.Run("Create records the new product type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		arg := dairy
+		arg.Id = 0
+		service.EXPECT().Create(gomock.Any(), arg).Return(dairy, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityProductTypes, 2, auditDomain.ActionCreate, nil, dairy)
+
+		_, err := NewAuditedService(service, audit).Create(context.TODO(), arg)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Update records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		maximum := 8.0
+		patch := domain.ProductTypePatch{MaximumTemperature: &maximum}
+		service.EXPECT().GetById(gomock.Any(), 2).Return(before, nil)
+		service.EXPECT().Update(gomock.Any(), 2, patch).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityProductTypes, 2, auditDomain.ActionUpdate, before, after)
+
+		_, err := NewAuditedService(service, audit).Update(context.TODO(), 2, patch)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete records the removed product type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 2).Return(before, nil)
+		service.EXPECT().Delete(gomock.Any(), 2).Return(nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityProductTypes, 2, auditDomain.ActionDelete, before, nil)
+
+		assert.NoError(t, NewAuditedService(service, audit).Delete(context.TODO(), 2))
+	})
+
+	t.Run("Restore records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 2).Return(before, nil)
+		service.EXPECT().Restore(gomock.Any(), 2).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityProductTypes, 2, auditDomain.ActionRestore, before, after)
+
+		_, err := NewAuditedService(service, audit).Restore(context.TODO(), 2)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Failed changes are not recorded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 9).Return(domain.ProductType{}, &domain.ErrorNotFound{Id: 9})
+		service.EXPECT().Delete(gomock.Any(), 9).Return(&domain.ErrorNotFound{Id: 9})
+
+		assert.Error(t, NewAuditedService(service, audit).Delete(context.TODO(), 9))
+	})
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+)
+
+type service struct {
+	repository domain.Repository
+}
+
+func NewService(r domain.Repository) domain.Service {
+	return &service{
+		repository: r,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *service) GetById(ctx context.Context, id int) (domain.ProductType, error) {
+	return s.repository.GetById(ctx, id)
+}
+
+func (s *service) Create(ctx context.Context, arg domain.ProductType) (domain.ProductType, error) {
+	if err := s.validate(ctx, arg); err != nil {
+		return domain.ProductType{}, err
+	}
+
+	return s.repository.Create(ctx, arg)
+}
+
+func (s *service) Update(ctx context.Context, id int, arg domain.ProductTypePatch) (domain.ProductType, error) {
+	productType, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return domain.ProductType{}, err
+	}
+
+	if err := etag.Check(ctx, productType.Version); err != nil {
+		return domain.ProductType{}, err
+	}
+
+	if arg.Name != nil {
+		productType.Name = *arg.Name
+	}
+	if arg.TemperatureClass != nil {
+		productType.TemperatureClass = *arg.TemperatureClass
+	}
+	if arg.MinimumTemperature != nil {
+		productType.MinimumTemperature = *arg.MinimumTemperature
+	}
+	if arg.MaximumTemperature != nil {
+		productType.MaximumTemperature = *arg.MaximumTemperature
+	}
+
+	if err := s.validate(ctx, productType); err != nil {
+		return domain.ProductType{}, err
+	}
+
+	return s.repository.Update(ctx, productType)
+}
+
+// Delete soft deletes the product type. Types still used by products or
+// sections are never deleted.
+func (s *service) Delete(ctx context.Context, id int) error {
+	found, err := s.repository.Dependents(ctx, id)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	if err := dependents.Check("product type", id, found...); err != nil {
+		return err
+	}
+
+	return s.repository.Delete(ctx, id)
+}
+
+func (s *service) Restore(ctx context.Context, id int) (domain.ProductType, error) {
+	if err := s.repository.Restore(ctx, id); err != nil {
+		return domain.ProductType{}, err
+	}
+
+	return s.repository.GetById(ctx, id)
+}
+
+// validate checks that the temperature range of arg is not inverted and that
+// no other product type has its name, ignoring case. Deleted types keep
+// their names, so they can be restored.
+func (s *service) validate(ctx context.Context, arg domain.ProductType) error {
+	if arg.MinimumTemperature > arg.MaximumTemperature {
+		return domain.ErrTemperatureRange
+	}
+
+	productTypes, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	for _, productType := range productTypes {
+		if productType.Id != arg.Id && strings.EqualFold(productType.Name, arg.Name) {
+			return &domain.ErrorConflict{Name: arg.Name}
+		}
+	}
+
+	return nil
+}
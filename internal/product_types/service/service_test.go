@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	frozen = domain.ProductType{Id: 1, Name: "Frozen food", TemperatureClass: domain.Frozen, MinimumTemperature: -25, MaximumTemperature: -18}
+	dairy  = domain.ProductType{Id: 2, Name: "Dairy", TemperatureClass: domain.Refrigerated, MinimumTemperature: 1, MaximumTemperature: 5, Version: 2}
+)
+
+func callMock(t *testing.T) (*mock_domain.MockRepository, domain.Service, context.Context) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mock_domain.NewMockRepository(ctrl)
+	return repository, NewService(repository), context.Background()
+}
+
+func TestService_Create(t *testing.T) {
+	arg := domain.ProductType{Name: "Fruits", TemperatureClass: domain.Ambient, MinimumTemperature: 10, MaximumTemperature: 25}
+
+	t.Run("OK", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		created := arg
+		created.Id = 3
+		repository.EXPECT().GetAll(softdelete.WithDeleted(ctx)).Return([]domain.ProductType{frozen, dairy}, nil)
+		repository.EXPECT().Create(ctx, arg).Return(created, nil)
+
+		result, err := service.Create(ctx, arg)
+		assert.NoError(t, err)
+		assert.Equal(t, created, result)
+	})
+
+	t.Run("Name taken", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().GetAll(softdelete.WithDeleted(ctx)).Return([]domain.ProductType{frozen, dairy}, nil)
+
+		taken := arg
+		taken.Name = "DAIRY"
+		_, err := service.Create(ctx, taken)
+
+		var errConflict *domain.ErrorConflict
+		assert.ErrorAs(t, err, &errConflict)
+	})
+
+	t.Run("Name of a deleted type", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		deleted := dairy
+		deleted.DeletedAt = date.Now()
+		repository.EXPECT().GetAll(softdelete.WithDeleted(ctx)).Return([]domain.ProductType{frozen, deleted}, nil)
+
+		taken := arg
+		taken.Name = "Dairy"
+		_, err := service.Create(ctx, taken)
+
+		var errConflict *domain.ErrorConflict
+		assert.ErrorAs(t, err, &errConflict)
+	})
+
+	t.Run("Inverted temperature range", func(t *testing.T) {
+		_, service, ctx := callMock(t)
+
+		inverted := arg
+		inverted.MinimumTemperature = 30
+		_, err := service.Create(ctx, inverted)
+		assert.ErrorIs(t, err, domain.ErrTemperatureRange)
+	})
+}
+
+func TestService_Update(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		maximum := 8.0
+		updated := dairy
+		updated.MaximumTemperature = 8
+
+		repository.EXPECT().GetById(ctx, 2).Return(dairy, nil)
+		repository.EXPECT().GetAll(softdelete.WithDeleted(ctx)).Return([]domain.ProductType{frozen, dairy}, nil)
+		repository.EXPECT().Update(ctx, updated).Return(updated, nil)
+
+		result, err := service.Update(ctx, 2, domain.ProductTypePatch{MaximumTemperature: &maximum})
+		assert.NoError(t, err)
+		assert.Equal(t, updated, result)
+	})
+
+	t.Run("Inverted temperature range", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		maximum := -5.0
+		repository.EXPECT().GetById(ctx, 2).Return(dairy, nil)
+
+		_, err := service.Update(ctx, 2, domain.ProductTypePatch{MaximumTemperature: &maximum})
+		assert.ErrorIs(t, err, domain.ErrTemperatureRange)
+	})
+
+	t.Run("Changed since read", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		ctx = etag.WithVersion(ctx, 1)
+		repository.EXPECT().GetById(ctx, 2).Return(dairy, nil)
+
+		_, err := service.Update(ctx, 2, domain.ProductTypePatch{})
+		assert.ErrorIs(t, err, etag.ErrMismatch)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().GetById(ctx, 9).Return(domain.ProductType{}, &domain.ErrorNotFound{Id: 9})
+
+		_, err := service.Update(ctx, 9, domain.ProductTypePatch{})
+		assert.EqualError(t, err, "product type 9 not found")
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().Dependents(ctx, 2).Return([]dependents.Dependent{{Type: "products"}, {Type: "sections"}}, nil)
+		repository.EXPECT().Delete(ctx, 2).Return(nil)
+
+		assert.NoError(t, service.Delete(ctx, 2))
+	})
+
+	t.Run("In use", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().Dependents(ctx, 2).Return([]dependents.Dependent{{Type: "products", Count: 3}, {Type: "sections"}}, nil)
+
+		err := service.Delete(ctx, 2)
+		assert.EqualError(t, err, "product type 2 cannot be deleted, it still has 3 products")
+	})
+
+	t.Run("Dependents fail", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().Dependents(ctx, 2).Return(nil, errors.New("some error"))
+
+		assert.Error(t, service.Delete(ctx, 2))
+	})
+}
+
+func TestService_Restore(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().Restore(ctx, 2).Return(nil)
+		repository.EXPECT().GetById(ctx, 2).Return(dairy, nil)
+
+		result, err := service.Restore(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, dairy, result)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repository, service, ctx := callMock(t)
+
+		repository.EXPECT().Restore(ctx, 9).Return(&domain.ErrorNotFound{Id: 9})
+
+		_, err := service.Restore(ctx, 9)
+		assert.EqualError(t, err, "product type 9 not found")
+	})
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"net/http"
@@ -8,9 +9,17 @@ import (
 	"strings"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/bulk"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/export"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/patch"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"github.com/gin-gonic/gin"
 )
 
@@ -28,12 +37,22 @@ func NewProductController(service domain.ProductService) *ProductController {
 // @Tags         products
 // @Accept       json
 // @Produce      json
+// @Param        include_deleted  query  bool  false  "Include soft deleted products"
 // @Success      200  {array}  products.Product
 // @Success      204  "Empty database"
+// @Failure      400  {object}  response.Response
 // @Failure      500  {object}  response.Response
 // @Router       /api/v1/products [get]
 func (c *ProductController) GetAll() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if err := softdelete.FromQuery(ctx); err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(message))
+			return
+		}
+
 		products, err := c.service.GetAll(ctx)
 		if err != nil {
 			message := err.Error()
@@ -52,13 +71,41 @@ func (c *ProductController) GetAll() gin.HandlerFunc {
 	}
 }
 
+// ExportProducts godoc
+// @Summary      Export products
+// @Description  Stream every product as CSV, NDJSON or XLSX, with the same filters as the list
+// @Tags         products
+// @Produce      text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format           query  string  false  "csv (default), ndjson or xlsx"
+// @Param        include_deleted  query  bool    false  "Include soft deleted products"
+// @Success      200
+// @Failure      400  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /api/v1/products/export [get]
+func (c *ProductController) Export() gin.HandlerFunc {
+	handler := export.Handler("products", c.service.Stream)
+
+	return func(ctx *gin.Context) {
+		if err := softdelete.FromQuery(ctx); err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(message))
+			return
+		}
+
+		handler(ctx)
+	}
+}
+
 // GetProduct godoc
 // @Summary      Get a product by id
 // @Description  Get a product from the system searching by id
 // @Tags         products
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "Product id"
+// @Param        id               path   int   true   "Product id"
+// @Param        include_deleted  query  bool  false  "Include soft deleted products"
 // @Success      200  {object}  products.Product
 // @Failure      400  {object}  response.Response
 // @Failure      404  {object}  response.Response
@@ -74,6 +121,14 @@ func (c *ProductController) GetById() gin.HandlerFunc {
 			return
 		}
 
+		if err := softdelete.FromQuery(ctx); err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(message))
+			return
+		}
+
 		products, err := c.service.GetById(ctx, int(id))
 		if err != nil {
 			message := err.Error()
@@ -83,6 +138,7 @@ func (c *ProductController) GetById() gin.HandlerFunc {
 			return
 		}
 
+		etag.Set(ctx, products.Version)
 		ctx.JSON(http.StatusOK, response.NewResponse(products))
 	}
 }
@@ -101,6 +157,22 @@ type productsRequest struct {
 	SellerId                       int     `json:"seller_id" binding:"required,min=1"`
 }
 
+func (req productsRequest) product() domain.Product {
+	return domain.Product{
+		ProductCode:                    req.ProductCode,
+		Description:                    req.Description,
+		Width:                          req.Width,
+		Height:                         req.Height,
+		Length:                         req.Length,
+		NetWeight:                      req.NetWeight,
+		ExpirationRate:                 req.ExpirationRate,
+		RecommendedFreezingTemperature: req.RecommendedFreezingTemperature,
+		FreezingRate:                   req.FreezingRate,
+		ProductTypeId:                  req.ProductTypeId,
+		SellerId:                       req.SellerId,
+	}
+}
+
 // CreateProduct godoc
 // @Summary      Create a new product
 // @Description  Create a new product in the system
@@ -120,25 +192,11 @@ func (c *ProductController) Create() gin.HandlerFunc {
 			message := err.Error()
 			logger.Error(ctx, store.GetPathWithLine(), message)
 
-			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, response.DecodeError(message))
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 			return
 		}
 
-		arg := domain.Product{
-			ProductCode:                    req.ProductCode,
-			Description:                    req.Description,
-			Width:                          req.Width,
-			Height:                         req.Height,
-			Length:                         req.Length,
-			NetWeight:                      req.NetWeight,
-			ExpirationRate:                 req.ExpirationRate,
-			RecommendedFreezingTemperature: req.RecommendedFreezingTemperature,
-			FreezingRate:                   req.FreezingRate,
-			ProductTypeId:                  req.ProductTypeId,
-			SellerId:                       req.SellerId,
-		}
-
-		product, err := c.service.Create(ctx, arg)
+		product, err := c.service.Create(ctx, req.product())
 		if err != nil {
 			message := err.Error()
 			logger.Error(ctx, store.GetPathWithLine(), message)
@@ -147,36 +205,59 @@ func (c *ProductController) Create() gin.HandlerFunc {
 			return
 		}
 
+		etag.Set(ctx, product.Version)
 		ctx.JSON(http.StatusCreated, response.NewResponse(product))
 	}
 }
 
+// ImportProducts godoc
+// @Summary      Import products
+// @Description  Create products in bulk from a CSV or NDJSON upload, reporting the outcome of each row
+// @Tags         products
+// @Accept       text/csv,application/x-ndjson,multipart/form-data
+// @Produce      json
+// @Param        atomic  query     bool  false  "Keep no product when any row fails"
+// @Param        file    formData  file  false  "CSV or NDJSON file, for multipart uploads"
+// @Success      200     {object}  bulk.Report
+// @Failure      400     {object}  response.Response
+// @Failure      422     {object}  bulk.Report
+// @Router       /api/v1/products/import [post]
+func (c *ProductController) Import(runner transaction.Runner) gin.HandlerFunc {
+	return bulk.Handler(runner, func(ctx context.Context, req productsRequest) (int, error) {
+		product, err := c.service.Create(ctx, req.product())
+		return product.Id, err
+	})
+}
+
 type updateProductsRequest struct {
-	ProductCode                    string  `json:"product_code"`
-	Description                    string  `json:"description"`
-	Width                          float64 `json:"width"`
-	Height                         float64 `json:"height"`
-	Length                         float64 `json:"length"`
-	NetWeight                      float64 `json:"net_weight"`
-	ExpirationRate                 float64 `json:"expiration_rate"`
-	RecommendedFreezingTemperature float64 `json:"recommended_freezing_temperature"`
-	FreezingRate                   float64 `json:"freezing_rate"`
-	ProductTypeId                  int     `json:"product_type_id"`
-	SellerId                       int     `json:"seller_id"`
+	ProductCode                    *string  `json:"product_code" binding:"omitempty,min=1"`
+	Description                    *string  `json:"description" binding:"omitempty,min=1"`
+	Width                          *float64 `json:"width" binding:"omitempty,min=0"`
+	Height                         *float64 `json:"height" binding:"omitempty,min=0"`
+	Length                         *float64 `json:"length" binding:"omitempty,min=0"`
+	NetWeight                      *float64 `json:"net_weight" binding:"omitempty,min=0"`
+	ExpirationRate                 *float64 `json:"expiration_rate"`
+	RecommendedFreezingTemperature *float64 `json:"recommended_freezing_temperature"`
+	FreezingRate                   *float64 `json:"freezing_rate"`
+	ProductTypeId                  *int     `json:"product_type_id" binding:"omitempty,min=1"`
+	SellerId                       *int     `json:"seller_id" binding:"omitempty,min=1"`
 }
 
 // UpdateProduct godoc
 // @Summary      Update a product
-// @Description  Update a product in the system, selecting by id
+// @Description  Update a product in the system, selecting by id. The body is a JSON merge patch (RFC 7396)
 // @Tags         products
 // @Accept       json
 // @Produce      json
-// @Param        id       path      int              true   "Product id"
-// @Param        product  body      productsRequest  false  "Product to be updated (all fields are optional)"
+// @Param        id       path      int                    true   "Product id"
+// @Param        If-Match header    string                 true   "ETag of the product being updated"
+// @Param        product  body      updateProductsRequest  false  "Product to be updated (all fields are optional)"
 // @Success      200      {object}  products.Product
 // @Failure      400      {object}  response.Response
 // @Failure      404      {object}  response.Response
+// @Failure      412      {object}  response.Response
 // @Failure      422      {object}  response.Response
+// @Failure      428      {object}  response.Response
 // @Failure      500      {object}  response.Response
 // @Router       /api/v1/products/{id} [patch]
 func (c *ProductController) Update() gin.HandlerFunc {
@@ -190,18 +271,25 @@ func (c *ProductController) Update() gin.HandlerFunc {
 			return
 		}
 
+		if err := etag.FromIfMatch(ctx); err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(etag.Status(err), response.DecodeError(message))
+			return
+		}
+
 		var req updateProductsRequest
 
-		if err := ctx.ShouldBindJSON(&req); err != nil {
+		if err := patch.Bind(ctx, &req); err != nil {
 			message := err.Error()
 			logger.Error(ctx, store.GetPathWithLine(), message)
 
-			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, response.DecodeError(message))
+			ctx.AbortWithStatusJSON(patch.Status(err), validation.NewErrorResponse(err))
 			return
 		}
 
-		arg := domain.Product{
-			Id:                             int(id),
+		arg := domain.ProductPatch{
 			ProductCode:                    req.ProductCode,
 			Description:                    req.Description,
 			Width:                          req.Width,
@@ -215,7 +303,7 @@ func (c *ProductController) Update() gin.HandlerFunc {
 			SellerId:                       req.SellerId,
 		}
 
-		product, err := c.service.Update(ctx, arg)
+		product, err := c.service.Update(ctx, int(id), arg)
 		if err != nil {
 			message := err.Error()
 			logger.Error(ctx, store.GetPathWithLine(), message)
@@ -225,10 +313,16 @@ func (c *ProductController) Update() gin.HandlerFunc {
 				return
 			}
 
+			if errors.Is(err, etag.ErrMismatch) {
+				ctx.JSON(http.StatusPreconditionFailed, response.DecodeError(message))
+				return
+			}
+
 			ctx.JSON(http.StatusInternalServerError, response.DecodeError(message))
 			return
 		}
 
+		etag.Set(ctx, product.Version)
 		ctx.JSON(http.StatusOK, response.NewResponse(product))
 	}
 }
@@ -239,10 +333,14 @@ func (c *ProductController) Update() gin.HandlerFunc {
 // @Tags         products
 // @Accept       json
 // @Produce      json
-// @Param        id   path  int  true  "Product id"
+// @Param        id        path    int     true  "Product id"
+// @Param        If-Match  header  string  true  "ETag of the product being deleted"
 // @Success      204  "Successfully deleted"
 // @Failure      400  {object}  response.Response
 // @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Failure      412  {object}  response.Response
+// @Failure      428  {object}  response.Response
 // @Router       /api/v1/products/{id} [delete]
 func (c *ProductController) Delete() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -255,11 +353,30 @@ func (c *ProductController) Delete() gin.HandlerFunc {
 			return
 		}
 
+		if err := etag.FromIfMatch(ctx); err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(etag.Status(err), response.DecodeError(message))
+			return
+		}
+
 		err = c.service.Delete(ctx, int(id))
 		if err != nil {
 			message := err.Error()
 			logger.Error(ctx, store.GetPathWithLine(), message)
 
+			if errors.Is(err, etag.ErrMismatch) {
+				ctx.JSON(http.StatusPreconditionFailed, response.DecodeError(message))
+				return
+			}
+
+			var dependentsErr *dependents.Error
+			if errors.As(err, &dependentsErr) {
+				ctx.JSON(http.StatusConflict, response.DecodeDependents(dependentsErr))
+				return
+			}
+
 			ctx.JSON(http.StatusNotFound, response.DecodeError(message))
 			return
 		}
@@ -267,3 +384,44 @@ func (c *ProductController) Delete() gin.HandlerFunc {
 		ctx.AbortWithStatus(http.StatusNoContent)
 	}
 }
+
+// RestoreProduct godoc
+// @Summary      Restore a product
+// @Description  Restore a deleted product, selecting by id
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product id"
+// @Success      200  {object}  products.Product
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Router       /api/v1/products/{id}/restore [post]
+func (c *ProductController) Restore() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(message))
+			return
+		}
+
+		product, err := c.service.Restore(ctx, int(id))
+		if err != nil {
+			message := err.Error()
+			logger.Error(ctx, store.GetPathWithLine(), message)
+
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.JSON(http.StatusNotFound, response.DecodeError(message))
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, response.DecodeError(message))
+			return
+		}
+
+		etag.Set(ctx, product.Version)
+		ctx.JSON(http.StatusOK, response.NewResponse(product))
+	}
+}
@@ -1,9 +1,14 @@
 package controller
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -12,6 +17,12 @@ import (
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
 	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/bulk"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/export"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -66,8 +77,9 @@ type sliceResponseBody struct {
 }
 
 type productResponseBody struct {
-	Data  domain.Product `json:"data"`
-	Error string         `json:"error"`
+	Data   domain.Product        `json:"data"`
+	Error  string                `json:"error"`
+	Fields []response.FieldError `json:"fields"`
 }
 
 func callProductsMock(t *testing.T) (
@@ -311,6 +323,28 @@ func TestProductController_Create(t *testing.T) {
 				assert.NotEmpty(t, body.Error)
 			},
 		},
+		{
+			name: "InvalidFields",
+			payload: func() domain.Product {
+				product := newProduct
+				product.Width = -1
+				product.SellerId = -2
+				return product
+			}(),
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+
+				body := productResponseBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Empty(t, body.Data)
+				assert.Equal(t, []response.FieldError{
+					{Field: "width", Code: "min", Message: "must be greater than or equal to 0.1"},
+					{Field: "seller_id", Code: "min", Message: "must be greater than or equal to 1"},
+				}, body.Fields)
+			},
+		},
 		{
 			name:    "Conflict",
 			payload: newProduct,
@@ -367,30 +401,43 @@ func TestProductController_Create(t *testing.T) {
 func TestProductController_Update(t *testing.T) {
 	updatedProduct := secondProduct
 	updatedProduct.Id = firstProduct.Id
-	updatedProductWithInvalidId := secondProduct
-	updatedProductWithInvalidId.Id = INVALID_ID
 	invalidProduct := struct {
-		Width string
+		Width string `json:"width"`
 	}{
 		Width: "invalid",
 	}
+	updatePayload := updateProductsRequest{
+		ProductCode:                    &secondProduct.ProductCode,
+		Description:                    &secondProduct.Description,
+		Width:                          &secondProduct.Width,
+		Height:                         &secondProduct.Height,
+		Length:                         &secondProduct.Length,
+		NetWeight:                      &secondProduct.NetWeight,
+		ExpirationRate:                 &secondProduct.ExpirationRate,
+		RecommendedFreezingTemperature: &secondProduct.RecommendedFreezingTemperature,
+		FreezingRate:                   &secondProduct.FreezingRate,
+		ProductTypeId:                  &secondProduct.ProductTypeId,
+		SellerId:                       &secondProduct.SellerId,
+	}
+	updatePatch := domain.ProductPatch(updatePayload)
 
 	testCases := []struct {
 		name               string
 		payload            interface{}
 		wrongTypeProductId string
 		productId          int
+		withoutIfMatch     bool
 		buildStubs         func(service *mock_domain.MockProductService, ctx gomock.Matcher)
 		checkResult        func(t *testing.T, res *httptest.ResponseRecorder)
 	}{
 		{
 			name:      "OK",
-			payload:   updatedProduct,
+			payload:   updatePayload,
 			productId: updatedProduct.Id,
 			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
 				service.
 					EXPECT().
-					Update(ctx, updatedProduct).
+					Update(ctx, updatedProduct.Id, updatePatch).
 					Times(1).
 					Return(updatedProduct, nil)
 			},
@@ -402,6 +449,42 @@ func TestProductController_Update(t *testing.T) {
 
 				assert.Equal(t, updatedProduct, body.Data)
 				assert.Empty(t, body.Error)
+				assert.Equal(t, `"0"`, res.Header().Get("ETag"))
+			},
+		},
+		{
+			name:           "MissingIfMatch",
+			payload:        updatePayload,
+			productId:      updatedProduct.Id,
+			withoutIfMatch: true,
+			buildStubs:     func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, res.Code)
+
+				body := productResponseBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Equal(t, etag.ErrMissing.Error(), body.Error)
+			},
+		},
+		{
+			name:      "VersionMismatch",
+			payload:   updatePayload,
+			productId: updatedProduct.Id,
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Update(ctx, updatedProduct.Id, updatePatch).
+					Times(1).
+					Return(emptyProduct, etag.ErrMismatch)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, res.Code)
+
+				body := productResponseBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Equal(t, etag.ErrMismatch.Error(), body.Error)
 			},
 		},
 		{
@@ -419,9 +502,60 @@ func TestProductController_Update(t *testing.T) {
 				assert.NotEmpty(t, body.Error)
 			},
 		},
+		{
+			name:       "UnknownField",
+			payload:    map[string]interface{}{"id": 3, "width": 1.2},
+			productId:  firstProduct.Id,
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+
+				body := productResponseBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Empty(t, body.Data)
+				assert.Equal(t, []response.FieldError{
+					{Field: "id", Code: "unknown_field", Message: "is not a known field"},
+				}, body.Fields)
+			},
+		},
+		{
+			name:       "NullField",
+			payload:    map[string]interface{}{"width": nil},
+			productId:  firstProduct.Id,
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+
+				body := productResponseBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Empty(t, body.Data)
+				assert.Equal(t, []response.FieldError{
+					{Field: "width", Code: "null", Message: "cannot be null"},
+				}, body.Fields)
+			},
+		},
+		{
+			name:      "ZeroValue",
+			payload:   map[string]interface{}{"recommended_freezing_temperature": 0},
+			productId: firstProduct.Id,
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				zero := 0.0
+
+				service.
+					EXPECT().
+					Update(ctx, firstProduct.Id, domain.ProductPatch{RecommendedFreezingTemperature: &zero}).
+					Times(1).
+					Return(firstProduct, nil)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+			},
+		},
 		{
 			name:               "BadRequest",
-			payload:            updatedProduct,
+			payload:            updatePayload,
 			wrongTypeProductId: WRONG_TYPE_ID,
 			buildStubs:         func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
 			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
@@ -436,12 +570,12 @@ func TestProductController_Update(t *testing.T) {
 		},
 		{
 			name:      "NotFound",
-			payload:   updatedProduct,
+			payload:   updatePayload,
 			productId: INVALID_ID,
 			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
 				service.
 					EXPECT().
-					Update(ctx, updatedProductWithInvalidId).
+					Update(ctx, INVALID_ID, updatePatch).
 					Times(1).
 					Return(emptyProduct, fmt.Errorf("product (%d) not found", INVALID_ID))
 			},
@@ -457,12 +591,12 @@ func TestProductController_Update(t *testing.T) {
 		},
 		{
 			name:      "InternalServerError",
-			payload:   updatedProduct,
+			payload:   updatePayload,
 			productId: updatedProduct.Id,
 			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
 				service.
 					EXPECT().
-					Update(ctx, updatedProduct).
+					Update(ctx, updatedProduct.Id, updatePatch).
 					Times(1).
 					Return(emptyProduct, os.ErrClosed)
 			},
@@ -501,6 +635,9 @@ func TestProductController_Update(t *testing.T) {
 					bytes.NewBuffer(payload),
 				)
 			}
+			if !testCase.withoutIfMatch {
+				req.Header.Set("If-Match", `"1"`)
+			}
 			res := httptest.NewRecorder()
 			api.ServeHTTP(res, req)
 
@@ -571,6 +708,39 @@ func TestProductController_Delete(t *testing.T) {
 				assert.Equal(t, fmt.Sprintf("product (%d) not found", INVALID_ID), body.Error)
 			},
 		},
+		{
+			name:      "VersionMismatch",
+			productId: firstProduct.Id,
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Delete(ctx, firstProduct.Id).
+					Times(1).
+					Return(etag.ErrMismatch)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, res.Code)
+			},
+		},
+		{
+			name:      "Dependents",
+			productId: firstProduct.Id,
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Delete(ctx, firstProduct.Id).
+					Times(1).
+					Return(&dependents.Error{
+						Entity:     "product",
+						Id:         firstProduct.Id,
+						Dependents: []dependents.Dependent{{Type: "product_batches", Count: 2}},
+					})
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, res.Code)
+				assert.Contains(t, res.Body.String(), `"product_batches"`)
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -595,6 +765,92 @@ func TestProductController_Delete(t *testing.T) {
 					nil,
 				)
 			}
+			req.Header.Set("If-Match", `"1"`)
+			res := httptest.NewRecorder()
+			api.ServeHTTP(res, req)
+
+			testCase.checkResult(t, res)
+		})
+	}
+}
+
+func TestProductController_Restore(t *testing.T) {
+	testCases := []struct {
+		name        string
+		productId   string
+		buildStubs  func(service *mock_domain.MockProductService, ctx gomock.Matcher)
+		checkResult func(t *testing.T, res *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			productId: strconv.Itoa(firstProduct.Id),
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Restore(ctx, firstProduct.Id).
+					Times(1).
+					Return(firstProduct, nil)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+
+				body := productResponseBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Equal(t, firstProduct, body.Data)
+			},
+		},
+		{
+			name:       "Fail",
+			productId:  WRONG_TYPE_ID,
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, res.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			productId: strconv.Itoa(INVALID_ID),
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Restore(ctx, INVALID_ID).
+					Times(1).
+					Return(domain.Product{}, sql.ErrNoRows)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, res.Code)
+			},
+		},
+		{
+			name:      "InternalServerError",
+			productId: strconv.Itoa(firstProduct.Id),
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Restore(ctx, firstProduct.Id).
+					Times(1).
+					Return(domain.Product{}, os.ErrClosed)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, res.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, handler, api, ctx := callProductsMock(t)
+
+			api.POST(RELATIVE_PATH_WITH_ID+"/restore", handler.Restore())
+
+			testCase.buildStubs(service, ctx)
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				fmt.Sprintf("%s%s/restore", RELATIVE_PATH, testCase.productId),
+				nil,
+			)
 			res := httptest.NewRecorder()
 			api.ServeHTTP(res, req)
 
@@ -602,3 +858,335 @@ func TestProductController_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestProductController_GetAll_IncludeDeleted(t *testing.T) {
+	service, handler, api, _ := callProductsMock(t)
+
+	api.GET(RELATIVE_PATH, handler.GetAll())
+
+	service.
+		EXPECT().
+		GetAll(gomock.Any()).
+		DoAndReturn(func(ctx context.Context) ([]domain.Product, error) {
+			assert.True(t, softdelete.IncludeDeleted(ctx))
+			return allProducts, nil
+		})
+
+	res := httptest.NewRecorder()
+	api.ServeHTTP(res, httptest.NewRequest(http.MethodGet, RELATIVE_PATH+"?include_deleted=1", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	res = httptest.NewRecorder()
+	api.ServeHTTP(res, httptest.NewRequest(http.MethodGet, RELATIVE_PATH+"?include_deleted=xpto", nil))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+// runner runs imports without a database, so a rollback only shows in the
+// report.
+type runner struct{}
+
+func (runner) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestProductController_Import(t *testing.T) {
+	newProduct := firstProduct
+	newProduct.Id = 0
+
+	const (
+		csvHeader = "product_code,description,width,height,length,net_weight,expiration_rate,recommended_freezing_temperature,freezing_rate,product_type_id,seller_id\n"
+		csvRow    = "xpto,description,6.3,2.3,5.1,23.5,0.8,-4.3,0.4,3,5\n"
+	)
+
+	ndjsonRow, _ := json.Marshal(productsRequest{
+		ProductCode:                    newProduct.ProductCode,
+		Description:                    newProduct.Description,
+		Width:                          newProduct.Width,
+		Height:                         newProduct.Height,
+		Length:                         newProduct.Length,
+		NetWeight:                      newProduct.NetWeight,
+		ExpirationRate:                 newProduct.ExpirationRate,
+		RecommendedFreezingTemperature: newProduct.RecommendedFreezingTemperature,
+		FreezingRate:                   newProduct.FreezingRate,
+		ProductTypeId:                  newProduct.ProductTypeId,
+		SellerId:                       newProduct.SellerId,
+	})
+
+	type reportBody struct {
+		Data  bulk.Report `json:"data"`
+		Error string      `json:"error"`
+	}
+
+	testCases := []struct {
+		name        string
+		query       string
+		contentType string
+		payload     string
+		buildStubs  func(service *mock_domain.MockProductService, ctx gomock.Matcher)
+		checkResult func(t *testing.T, res *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "CSV",
+			contentType: bulk.MIMECSV,
+			payload:     csvHeader + csvRow,
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.EXPECT().Create(ctx, newProduct).Times(1).Return(firstProduct, nil)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+
+				body := reportBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Equal(t, 1, body.Data.Created)
+				assert.Equal(t, []bulk.Result{{Row: 1, Status: bulk.StatusCreated, Id: firstProduct.Id}}, body.Data.Rows)
+			},
+		},
+		{
+			name:        "NDJSONWithInvalidRow",
+			contentType: bulk.MIMENDJSON,
+			payload:     string(ndjsonRow) + "\n" + `{"product_code":"xablau"}` + "\n",
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.EXPECT().Create(ctx, newProduct).Times(1).Return(firstProduct, nil)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+
+				body := reportBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Equal(t, 2, body.Data.Total)
+				assert.Equal(t, 1, body.Data.Created)
+				assert.Equal(t, 1, body.Data.Failed)
+				assert.Equal(t, bulk.StatusFailed, body.Data.Rows[1].Status)
+				assert.NotEmpty(t, body.Data.Rows[1].Fields)
+			},
+		},
+		{
+			name:        "AtomicRollsBack",
+			query:       "?atomic=true",
+			contentType: bulk.MIMECSV,
+			payload:     csvHeader + csvRow + csvRow,
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				gomock.InOrder(
+					service.EXPECT().Create(ctx, newProduct).Return(firstProduct, nil),
+					service.EXPECT().Create(ctx, newProduct).Return(emptyProduct, fmt.Errorf("product code already exists")),
+				)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+
+				body := reportBody{}
+				json.Unmarshal(res.Body.Bytes(), &body)
+
+				assert.Equal(t, 0, body.Data.Created)
+				assert.Equal(t, 1, body.Data.Failed)
+				assert.Equal(t, bulk.StatusRolledBack, body.Data.Rows[0].Status)
+				assert.Zero(t, body.Data.Rows[0].Id)
+				assert.Equal(t, "product code already exists", body.Data.Rows[1].Error)
+			},
+		},
+		{
+			name:        "UnknownColumn",
+			contentType: bulk.MIMECSV,
+			payload:     "xpto\n1\n",
+			buildStubs:  func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, res.Code)
+			},
+		},
+		{
+			name:        "UnsupportedFormat",
+			contentType: gin.MIMEJSON,
+			payload:     string(ndjsonRow),
+			buildStubs:  func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, res.Code)
+			},
+		},
+		{
+			name:        "InvalidAtomic",
+			query:       "?atomic=xpto",
+			contentType: bulk.MIMECSV,
+			payload:     csvHeader + csvRow,
+			buildStubs:  func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, res.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, handler, api, ctx := callProductsMock(t)
+			tc.buildStubs(service, ctx)
+
+			api.POST(RELATIVE_PATH+"import", handler.Import(runner{}))
+			req := httptest.NewRequest(http.MethodPost, RELATIVE_PATH+"import"+tc.query, bytes.NewBufferString(tc.payload))
+			req.Header.Set("Content-Type", tc.contentType)
+			res := httptest.NewRecorder()
+
+			api.ServeHTTP(res, req)
+
+			tc.checkResult(t, res)
+		})
+	}
+}
+
+func TestProductController_Import_Multipart(t *testing.T) {
+	service, handler, api, ctx := callProductsMock(t)
+
+	newProduct := firstProduct
+	newProduct.Id = 0
+	service.EXPECT().Create(ctx, newProduct).Times(1).Return(firstProduct, nil)
+
+	api.POST(RELATIVE_PATH+"import", handler.Import(runner{}))
+
+	payload := &bytes.Buffer{}
+	form := multipart.NewWriter(payload)
+	file, _ := form.CreateFormFile(bulk.FileField, "products.csv")
+	file.Write([]byte("product_code,description,width,height,length,net_weight,expiration_rate,recommended_freezing_temperature,freezing_rate,product_type_id,seller_id\n" +
+		"xpto,description,6.3,2.3,5.1,23.5,0.8,-4.3,0.4,3,5\n"))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, RELATIVE_PATH+"import", payload)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	res := httptest.NewRecorder()
+
+	api.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func streamProducts(products ...domain.Product) func(ctx context.Context, fn func(domain.Product) error) error {
+	return func(ctx context.Context, fn func(domain.Product) error) error {
+		for _, product := range products {
+			if err := fn(product); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestProductController_Export(t *testing.T) {
+	testCases := []struct {
+		name        string
+		query       string
+		accept      string
+		buildStubs  func(service *mock_domain.MockProductService, ctx gomock.Matcher)
+		checkResult func(t *testing.T, res *httptest.ResponseRecorder)
+	}{
+		{
+			name: "CSV",
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.EXPECT().Stream(ctx, gomock.Any()).DoAndReturn(streamProducts(allProducts...))
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Equal(t, export.MIMECSV, res.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="products.csv"`, res.Header().Get("Content-Disposition"))
+				assert.Equal(t,
+					"id,product_code,description,width,height,length,net_weight,expiration_rate,recommended_freezing_temperature,freezing_rate,product_type_id,seller_id,deleted_at,version\n"+
+						"1,xpto,description,6.3,2.3,5.1,23.5,0.8,-4.3,0.4,3,5,,0\n"+
+						"2,xablau,description,3.6,3.2,1.5,5.23,0.08,-3.4,0.8,2,3,,0\n",
+					res.Body.String(),
+				)
+			},
+		},
+		{
+			name:   "NDJSONFromAccept",
+			accept: export.MIMENDJSON,
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.EXPECT().Stream(ctx, gomock.Any()).DoAndReturn(streamProducts(allProducts...))
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Equal(t, export.MIMENDJSON, res.Header().Get("Content-Type"))
+
+				decoder := json.NewDecoder(res.Body)
+				for _, expected := range allProducts {
+					var product domain.Product
+					assert.NoError(t, decoder.Decode(&product))
+					assert.Equal(t, expected, product)
+				}
+				assert.False(t, decoder.More())
+			},
+		},
+		{
+			name:  "XLSX",
+			query: "?format=xlsx",
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.EXPECT().Stream(ctx, gomock.Any()).DoAndReturn(streamProducts(firstProduct))
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Equal(t, export.MIMEXLSX, res.Header().Get("Content-Type"))
+
+				archive, err := zip.NewReader(bytes.NewReader(res.Body.Bytes()), int64(res.Body.Len()))
+				assert.NoError(t, err)
+
+				sheet, err := archive.Open("xl/worksheets/sheet1.xml")
+				assert.NoError(t, err)
+				content, _ := io.ReadAll(sheet)
+
+				assert.Contains(t, string(content), `<c r="B1" t="inlineStr"><is><t xml:space="preserve">product_code</t></is></c>`)
+				assert.Contains(t, string(content), `<c r="D2"><v>6.3</v></c>`)
+				assert.Contains(t, string(content), `</sheetData></worksheet>`)
+			},
+		},
+		{
+			name: "Empty",
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.EXPECT().Stream(ctx, gomock.Any()).Return(nil)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, res.Code)
+				assert.Equal(t, 1, bytes.Count(res.Body.Bytes(), []byte("\n")))
+			},
+		},
+		{
+			name: "Fail",
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {
+				service.EXPECT().Stream(ctx, gomock.Any()).Return(sql.ErrConnDone)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, res.Code)
+			},
+		},
+		{
+			name:       "InvalidFormat",
+			query:      "?format=pdf",
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, res.Code)
+			},
+		},
+		{
+			name:       "InvalidIncludeDeleted",
+			query:      "?include_deleted=xpto",
+			buildStubs: func(service *mock_domain.MockProductService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, res.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, handler, api, ctx := callProductsMock(t)
+			tc.buildStubs(service, ctx)
+
+			api.GET(RELATIVE_PATH+"export", handler.Export())
+			req := httptest.NewRequest(http.MethodGet, RELATIVE_PATH+"export"+tc.query, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			res := httptest.NewRecorder()
+
+			api.ServeHTTP(res, req)
+
+			tc.checkResult(t, res)
+		})
+	}
+}
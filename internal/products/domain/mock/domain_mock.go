@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	domain "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
+	dependents "github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -64,6 +65,36 @@ func (mr *MockProductRepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductRepository)(nil).Delete), arg0, arg1)
 }
 
+// Dependents mocks base method.
+func (m *MockProductRepository) Dependents(arg0 context.Context, arg1 int) ([]dependents.Dependent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dependents", arg0, arg1)
+	ret0, _ := ret[0].([]dependents.Dependent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dependents indicates an expected call of Dependents.
+func (mr *MockProductRepositoryMockRecorder) Dependents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dependents", reflect.TypeOf((*MockProductRepository)(nil).Dependents), arg0, arg1)
+}
+
+// ExistingIds mocks base method.
+func (m *MockProductRepository) ExistingIds(arg0 context.Context, arg1 []int) (map[int]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistingIds", arg0, arg1)
+	ret0, _ := ret[0].(map[int]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistingIds indicates an expected call of ExistingIds.
+func (mr *MockProductRepositoryMockRecorder) ExistingIds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistingIds", reflect.TypeOf((*MockProductRepository)(nil).ExistingIds), arg0, arg1)
+}
+
 // GetAll mocks base method.
 func (m *MockProductRepository) GetAll(arg0 context.Context) ([]domain.Product, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +125,49 @@ func (mr *MockProductRepositoryMockRecorder) GetById(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockProductRepository)(nil).GetById), arg0, arg1)
 }
 
+// ProductCodeExists mocks base method.
+func (m *MockProductRepository) ProductCodeExists(arg0 context.Context, arg1 string, arg2 int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProductCodeExists", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProductCodeExists indicates an expected call of ProductCodeExists.
+func (mr *MockProductRepositoryMockRecorder) ProductCodeExists(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProductCodeExists", reflect.TypeOf((*MockProductRepository)(nil).ProductCodeExists), arg0, arg1, arg2)
+}
+
+// Restore mocks base method.
+func (m *MockProductRepository) Restore(arg0 context.Context, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockProductRepositoryMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockProductRepository)(nil).Restore), arg0, arg1)
+}
+
+// Stream mocks base method.
+func (m *MockProductRepository) Stream(arg0 context.Context, arg1 func(domain.Product) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockProductRepositoryMockRecorder) Stream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockProductRepository)(nil).Stream), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockProductRepository) Update(arg0 context.Context, arg1 domain.Product) (domain.Product, error) {
 	m.ctrl.T.Helper()
@@ -191,17 +265,46 @@ func (mr *MockProductServiceMockRecorder) GetById(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockProductService)(nil).GetById), arg0, arg1)
 }
 
+// Restore mocks base method.
+func (m *MockProductService) Restore(arg0 context.Context, arg1 int) (domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockProductServiceMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockProductService)(nil).Restore), arg0, arg1)
+}
+
+// Stream mocks base method.
+func (m *MockProductService) Stream(arg0 context.Context, arg1 func(domain.Product) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockProductServiceMockRecorder) Stream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockProductService)(nil).Stream), arg0, arg1)
+}
+
 // Update mocks base method.
-func (m *MockProductService) Update(arg0 context.Context, arg1 domain.Product) (domain.Product, error) {
+func (m *MockProductService) Update(arg0 context.Context, arg1 int, arg2 domain.ProductPatch) (domain.Product, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2)
 	ret0, _ := ret[0].(domain.Product)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockProductServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockProductServiceMockRecorder) Update(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductService)(nil).Update), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductService)(nil).Update), arg0, arg1, arg2)
 }
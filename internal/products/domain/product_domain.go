@@ -1,34 +1,64 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+)
 
 type Product struct {
-	Id                             int     `json:"id"`
-	ProductCode                    string  `json:"product_code"`
-	Description                    string  `json:"description"`
-	Width                          float64 `json:"width"`
-	Height                         float64 `json:"height"`
-	Length                         float64 `json:"length"`
-	NetWeight                      float64 `json:"net_weight"`
-	ExpirationRate                 float64 `json:"expiration_rate"`
-	RecommendedFreezingTemperature float64 `json:"recommended_freezing_temperature"`
-	FreezingRate                   float64 `json:"freezing_rate"`
-	ProductTypeId                  int     `json:"product_type_id"`
-	SellerId                       int     `json:"seller_id"`
+	Id                             int           `json:"id"`
+	ProductCode                    string        `json:"product_code"`
+	Description                    string        `json:"description"`
+	Width                          float64       `json:"width"`
+	Height                         float64       `json:"height"`
+	Length                         float64       `json:"length"`
+	NetWeight                      float64       `json:"net_weight"`
+	ExpirationRate                 float64       `json:"expiration_rate"`
+	RecommendedFreezingTemperature float64       `json:"recommended_freezing_temperature"`
+	FreezingRate                   float64       `json:"freezing_rate"`
+	ProductTypeId                  int           `json:"product_type_id"`
+	SellerId                       int           `json:"seller_id"`
+	DeletedAt                      date.DateTime `json:"deleted_at"`
+	Version                        int           `json:"version"`
+}
+
+// ProductPatch holds the fields of a partial update. Nil fields are left
+// untouched, so a zero value can be set explicitly.
+type ProductPatch struct {
+	ProductCode                    *string  `json:"product_code"`
+	Description                    *string  `json:"description"`
+	Width                          *float64 `json:"width"`
+	Height                         *float64 `json:"height"`
+	Length                         *float64 `json:"length"`
+	NetWeight                      *float64 `json:"net_weight"`
+	ExpirationRate                 *float64 `json:"expiration_rate"`
+	RecommendedFreezingTemperature *float64 `json:"recommended_freezing_temperature"`
+	FreezingRate                   *float64 `json:"freezing_rate"`
+	ProductTypeId                  *int     `json:"product_type_id"`
+	SellerId                       *int     `json:"seller_id"`
 }
 
 type ProductRepository interface {
 	GetAll(ctx context.Context) ([]Product, error)
+	Stream(ctx context.Context, fn func(product Product) error) error
 	GetById(ctx context.Context, id int) (Product, error)
+	ExistingIds(ctx context.Context, ids []int) (map[int]bool, error)
+	ProductCodeExists(ctx context.Context, productCode string, exceptId int) (bool, error)
 	Create(ctx context.Context, arg Product) (Product, error)
 	Update(ctx context.Context, arg Product) (Product, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Dependents(ctx context.Context, id int) ([]dependents.Dependent, error)
 }
 
 type ProductService interface {
 	GetAll(ctx context.Context) ([]Product, error)
+	Stream(ctx context.Context, fn func(product Product) error) error
 	GetById(ctx context.Context, id int) (Product, error)
 	Create(ctx context.Context, arg Product) (Product, error)
-	Update(ctx context.Context, arg Product) (Product, error)
+	Update(ctx context.Context, id int, arg ProductPatch) (Product, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) (Product, error)
 }
@@ -1,7 +1,7 @@
 package mariadb
 
 const (
-	GetAllQuery = `
+	SelectQuery = `
 		SELECT
 			id,
 			product_code,
@@ -14,27 +14,18 @@ const (
 			recommended_freezing_temperature,
 			freezing_rate,
 			product_type_id,
-			seller_id
+			seller_id,
+			deleted_at,
+			version
 		FROM
 			products`
-	GetByIdQuery = `
-		SELECT
-			id,
-			product_code,
-			description,
-			width,
-			height,
-			length,
-			net_weight,
-			expiration_rate,
-			recommended_freezing_temperature,
-			freezing_rate,
-			product_type_id,
-			seller_id
-		FROM
-			products
+	GetAllQuery = SelectQuery + `
 		WHERE
-			id = ?`
+			deleted_at IS NULL`
+	GetByIdQuery = SelectQuery + `
+		WHERE
+			id = ?
+			AND deleted_at IS NULL`
 	CreateQuery = `
 		INSERT INTO products (
 			product_code,
@@ -62,7 +53,51 @@ const (
 			recommended_freezing_temperature = ?,
 			freezing_rate = ?,
 			product_type_id = ?,
-			seller_id = ?
-		WHERE id = ?`
-	DeleteQuery = `DELETE FROM products WHERE id = ?`
+			seller_id = ?,
+			version = version + 1
+		WHERE id = ? AND deleted_at IS NULL AND version = ?`
+	DeleteQuery  = `UPDATE products SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL`
+	RestoreQuery = `UPDATE products SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL`
+
+	// Variants that also read soft deleted products, used when the request
+	// has ?include_deleted=true.
+	GetAllWithDeletedQuery  = SelectQuery
+	GetByIdWithDeletedQuery = SelectQuery + `
+		WHERE
+			id = ?`
+
+	// Seller scoped variants, used when the request is limited to one seller.
+	GetAllBySellerQuery = GetAllQuery + `
+			AND seller_id = ?`
+	GetAllWithDeletedBySellerQuery = GetAllWithDeletedQuery + `
+		WHERE
+			seller_id = ?`
+	GetByIdAndSellerQuery = GetByIdQuery + `
+			AND seller_id = ?`
+	GetByIdWithDeletedAndSellerQuery = GetByIdWithDeletedQuery + `
+			AND seller_id = ?`
+	UpdateBySellerQuery  = UpdateQuery + ` AND seller_id = ?`
+	DeleteBySellerQuery  = DeleteQuery + ` AND seller_id = ?`
+	RestoreBySellerQuery = RestoreQuery + ` AND seller_id = ?`
+
+	// The ExistingIds queries take the placeholders of the ids in place of %s.
+	ExistingIdsQuery         = `SELECT id FROM products WHERE deleted_at IS NULL AND id IN (%s)`
+	ExistingIdsBySellerQuery = `SELECT id FROM products WHERE deleted_at IS NULL AND seller_id = ? AND id IN (%s)`
+
+	// ProductCodeExistsQuery reads the products of every seller, deleted ones
+	// included, as product codes are unique across the whole table.
+	ProductCodeExistsQuery = `SELECT EXISTS (SELECT 1 FROM products WHERE product_code = ? AND id <> ?)`
+
+	// DependentsQuery counts the rows that reference a product. It reads no
+	// row for products out of the scope of the request.
+	DependentsQuery = `
+		SELECT
+			(SELECT COUNT(*) FROM product_batches WHERE product_id = p.id),
+			(SELECT COUNT(*) FROM product_records WHERE product_id = p.id),
+			(SELECT COUNT(*) FROM purchase_order_lines WHERE product_id = p.id)
+		FROM
+			products p
+		WHERE
+			p.id = ?`
+	DependentsBySellerQuery = DependentsQuery + ` AND p.seller_id = ?`
 )
@@ -3,10 +3,19 @@ package mariadb
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/tenant"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 )
 
 type repository struct {
@@ -20,13 +29,40 @@ func NewRepository(db *sql.DB) domain.ProductRepository {
 func (r *repository) GetAll(ctx context.Context) ([]domain.Product, error) {
 	products := []domain.Product{}
 
-	rows, err := r.db.QueryContext(ctx, GetAllQuery)
+	err := r.Stream(ctx, func(product domain.Product) error {
+		products = append(products, product)
+		return nil
+	})
+	if err != nil {
+		return []domain.Product{}, err
+	}
+
+	return products, nil
+}
+
+// Stream hands the products GetAll returns to fn one at a time, as they are
+// read.
+func (r *repository) Stream(ctx context.Context, fn func(product domain.Product) error) error {
+	query, args := GetAllQuery, []interface{}{}
+	if softdelete.IncludeDeleted(ctx) {
+		query = GetAllWithDeletedQuery
+	}
+	if sellerId, scoped := tenant.SellerId(ctx); scoped {
+		query, args = GetAllBySellerQuery, append(args, sellerId)
+		if softdelete.IncludeDeleted(ctx) {
+			query = GetAllWithDeletedBySellerQuery
+		}
+	}
+
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 
-		return products, err
+		return err
 	}
 
+	defer rows.Close()
+
 	for rows.Next() {
 		product := domain.Product{}
 
@@ -43,21 +79,36 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Product, error) {
 			&product.FreezingRate,
 			&product.ProductTypeId,
 			&product.SellerId,
+			&product.DeletedAt,
+			&product.Version,
 		)
 		if err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
 
-			return []domain.Product{}, err
+			return err
 		}
 
-		products = append(products, product)
+		if err := fn(product); err != nil {
+			return err
+		}
 	}
 
-	return products, nil
+	return rows.Err()
 }
 
 func (r *repository) GetById(ctx context.Context, id int) (domain.Product, error) {
-	row := r.db.QueryRowContext(ctx, GetByIdQuery, id)
+	query, args := GetByIdQuery, []interface{}{id}
+	if softdelete.IncludeDeleted(ctx) {
+		query = GetByIdWithDeletedQuery
+	}
+	if sellerId, scoped := tenant.SellerId(ctx); scoped {
+		query, args = GetByIdAndSellerQuery, append(args, sellerId)
+		if softdelete.IncludeDeleted(ctx) {
+			query = GetByIdWithDeletedAndSellerQuery
+		}
+	}
+
+	row := transaction.From(ctx, r.db).QueryRowContext(ctx, query, args...)
 
 	product := domain.Product{}
 
@@ -74,6 +125,8 @@ func (r *repository) GetById(ctx context.Context, id int) (domain.Product, error
 		&product.FreezingRate,
 		&product.ProductTypeId,
 		&product.SellerId,
+		&product.DeletedAt,
+		&product.Version,
 	)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
@@ -84,8 +137,68 @@ func (r *repository) GetById(ctx context.Context, id int) (domain.Product, error
 	return product, nil
 }
 
+// ExistingIds reports which of ids belong to products that can be read, in a
+// single query.
+func (r *repository) ExistingIds(ctx context.Context, ids []int) (map[int]bool, error) {
+	existing := map[int]bool{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	query, args := ExistingIdsQuery, []interface{}{}
+	if sellerId, scoped := tenant.SellerId(ctx); scoped {
+		query, args = ExistingIdsBySellerQuery, append(args, sellerId)
+	}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query = fmt.Sprintf(query, strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "))
+
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+			return nil, err
+		}
+		existing[id] = true
+	}
+
+	return existing, rows.Err()
+}
+
+// ProductCodeExists reports whether a product other than exceptId has
+// productCode. It ignores the seller the request is scoped to, so a seller
+// cannot take the code of a product of another seller.
+func (r *repository) ProductCodeExists(ctx context.Context, productCode string, exceptId int) (bool, error) {
+	var exists bool
+
+	row := transaction.From(ctx, r.db).QueryRowContext(ctx, ProductCodeExistsQuery, productCode, exceptId)
+	if err := row.Scan(&exists); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return false, err
+	}
+
+	return exists, nil
+}
+
 func (r *repository) Create(ctx context.Context, arg domain.Product) (domain.Product, error) {
-	result, err := r.db.ExecContext(
+	// Sellers can only create products of their own.
+	if sellerId, scoped := tenant.SellerId(ctx); scoped {
+		arg.SellerId = sellerId
+	}
+
+	result, err := transaction.From(ctx, r.db).ExecContext(
 		ctx,
 		CreateQuery,
 		arg.ProductCode,
@@ -115,14 +228,20 @@ func (r *repository) Create(ctx context.Context, arg domain.Product) (domain.Pro
 
 	product := arg
 	product.Id = int(id)
+	product.Version = 1
 
 	return product, nil
 }
 
 func (r *repository) Update(ctx context.Context, arg domain.Product) (domain.Product, error) {
-	_, err := r.db.ExecContext(
-		ctx,
-		UpdateQuery,
+	query, scope := UpdateQuery, []interface{}{}
+	if sellerId, scoped := tenant.SellerId(ctx); scoped {
+		// Sellers can neither touch other sellers' products nor hand theirs over.
+		arg.SellerId = sellerId
+		query, scope = UpdateBySellerQuery, append(scope, sellerId)
+	}
+
+	args := []interface{}{
 		arg.ProductCode,
 		arg.Description,
 		arg.Width,
@@ -135,21 +254,105 @@ func (r *repository) Update(ctx context.Context, arg domain.Product) (domain.Pro
 		arg.ProductTypeId,
 		arg.SellerId,
 		arg.Id,
-	)
+		arg.Version,
+	}
+
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, query, append(args, scope...)...)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 
 		return domain.Product{}, err
 	}
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return domain.Product{}, err
+	}
+
+	// The product was read with this version, so no rows means someone else
+	// wrote it in between.
+	if affected == 0 {
+		return domain.Product{}, etag.ErrMismatch
+	}
+
+	arg.Version++
+
 	return arg, nil
 }
 
 func (r *repository) Delete(ctx context.Context, id int) error {
-	_, err := r.db.ExecContext(ctx, DeleteQuery, id)
+	return r.exec(ctx, id, DeleteQuery, DeleteBySellerQuery, date.Now(), id)
+}
+
+func (r *repository) Restore(ctx context.Context, id int) error {
+	return r.exec(ctx, id, RestoreQuery, RestoreBySellerQuery, id)
+}
+
+// Dependents counts the batches, records and order lines of the product.
+// Products out of the scope of the request have none, so their delete
+// reports them as not found.
+func (r *repository) Dependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	query, args := DependentsQuery, []interface{}{id}
+	if sellerId, scoped := tenant.SellerId(ctx); scoped {
+		query, args = DependentsBySellerQuery, append(args, sellerId)
+	}
+
+	var batches, records, lines int
+	err := transaction.From(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&batches, &records, &lines)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return nil, err
+	}
+
+	return []dependents.Dependent{
+		{Type: "product_batches", Count: batches},
+		{Type: "product_records", Count: records},
+		{Type: "purchase_order_lines", Count: lines},
+	}, nil
+}
+
+// exec runs a statement that must change exactly one product, using
+// scopedQuery for seller scoped requests and requiring the version the
+// client read, when there is one.
+func (r *repository) exec(ctx context.Context, id int, query, scopedQuery string, args ...interface{}) error {
+	if sellerId, scoped := tenant.SellerId(ctx); scoped {
+		query, args = scopedQuery, append(args, sellerId)
+	}
+
+	version, versioned := etag.Version(ctx)
+	if versioned {
+		query, args = query+etag.Condition, append(args, version)
+	}
+
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return err
+	}
+
+	affected, err := result.RowsAffected()
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return err
+	}
+
+	if affected == 0 {
+		if versioned {
+			if _, err := r.GetById(ctx, id); err == nil {
+				return etag.ErrMismatch
+			}
+		}
+
+		return sql.ErrNoRows
 	}
 
-	return err
+	return nil
 }
@@ -4,11 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"fmt"
+	"io"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/tenant"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,6 +35,7 @@ var (
 		FreezingRate:                   0.4,
 		ProductTypeId:                  3,
 		SellerId:                       5,
+		Version:                        1,
 	}
 	secondProduct = domain.Product{
 		Id:                             2,
@@ -41,6 +50,7 @@ var (
 		FreezingRate:                   0.8,
 		ProductTypeId:                  2,
 		SellerId:                       3,
+		Version:                        1,
 	}
 	allProducts = []domain.Product{
 		firstProduct,
@@ -76,6 +86,8 @@ func TestMariaDB_GetAll(t *testing.T) {
 					"freezing_rate",
 					"product_type_id",
 					"seller_id",
+					"deleted_at",
+					"version",
 				}).AddRow(
 					firstProduct.Id,
 					firstProduct.ProductCode,
@@ -89,6 +101,8 @@ func TestMariaDB_GetAll(t *testing.T) {
 					firstProduct.FreezingRate,
 					firstProduct.ProductTypeId,
 					firstProduct.SellerId,
+					nil,
+					firstProduct.Version,
 				).AddRow(
 					secondProduct.Id,
 					secondProduct.ProductCode,
@@ -102,9 +116,11 @@ func TestMariaDB_GetAll(t *testing.T) {
 					secondProduct.FreezingRate,
 					secondProduct.ProductTypeId,
 					secondProduct.SellerId,
+					nil,
+					secondProduct.Version,
 				)
 
-				mock.ExpectQuery(GetAllQuery).WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(GetAllQuery)).WillReturnRows(rows)
 			},
 			checkResult: func(t *testing.T, result []domain.Product, err error) {
 				assert.NoError(t, err)
@@ -114,7 +130,7 @@ func TestMariaDB_GetAll(t *testing.T) {
 		{
 			name: "Fail",
 			buildStubs: func() {
-				mock.ExpectQuery(GetAllQuery).WillReturnError(sql.ErrConnDone)
+				mock.ExpectQuery(regexp.QuoteMeta(GetAllQuery)).WillReturnError(sql.ErrConnDone)
 			},
 			checkResult: func(t *testing.T, result []domain.Product, err error) {
 				assert.Error(t, err)
@@ -135,7 +151,7 @@ func TestMariaDB_GetAll(t *testing.T) {
 					secondProduct.ProductCode,
 				)
 
-				mock.ExpectQuery(GetAllQuery).WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(GetAllQuery)).WillReturnRows(rows)
 			},
 			checkResult: func(t *testing.T, result []domain.Product, err error) {
 				assert.Error(t, err)
@@ -157,6 +173,41 @@ func TestMariaDB_GetAll(t *testing.T) {
 	}
 }
 
+func TestMariaDB_Stream_StopsOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{
+		"id",
+		"product_code",
+		"description",
+		"width",
+		"height",
+		"length",
+		"net_weight",
+		"expiration_rate",
+		"recommended_freezing_temperature",
+		"freezing_rate",
+		"product_type_id",
+		"seller_id",
+		"deleted_at",
+		"version",
+	}).
+		AddRow(firstProduct.Id, firstProduct.ProductCode, firstProduct.Description, firstProduct.Width, firstProduct.Height, firstProduct.Length, firstProduct.NetWeight, firstProduct.ExpirationRate, firstProduct.RecommendedFreezingTemperature, firstProduct.FreezingRate, firstProduct.ProductTypeId, firstProduct.SellerId, nil, firstProduct.Version).
+		AddRow(secondProduct.Id, secondProduct.ProductCode, secondProduct.Description, secondProduct.Width, secondProduct.Height, secondProduct.Length, secondProduct.NetWeight, secondProduct.ExpirationRate, secondProduct.RecommendedFreezingTemperature, secondProduct.FreezingRate, secondProduct.ProductTypeId, secondProduct.SellerId, nil, secondProduct.Version)
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetAllQuery)).WillReturnRows(rows)
+
+	streamed := []domain.Product{}
+	err = NewRepository(db).Stream(ctx, func(product domain.Product) error {
+		streamed = append(streamed, product)
+		return io.ErrClosedPipe
+	})
+
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+	assert.Equal(t, []domain.Product{firstProduct}, streamed)
+}
 func TestMariaDB_GetById(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -184,6 +235,8 @@ func TestMariaDB_GetById(t *testing.T) {
 					"freezing_rate",
 					"product_type_id",
 					"seller_id",
+					"deleted_at",
+					"version",
 				}).AddRow(
 					firstProduct.Id,
 					firstProduct.ProductCode,
@@ -197,9 +250,11 @@ func TestMariaDB_GetById(t *testing.T) {
 					firstProduct.FreezingRate,
 					firstProduct.ProductTypeId,
 					firstProduct.SellerId,
+					nil,
+					firstProduct.Version,
 				)
 
-				mock.ExpectQuery(GetByIdQuery).WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(GetByIdQuery)).WillReturnRows(rows)
 			},
 			id: firstProduct.Id,
 			checkResult: func(t *testing.T, result domain.Product, err error) {
@@ -218,7 +273,7 @@ func TestMariaDB_GetById(t *testing.T) {
 					firstProduct.ProductCode,
 				)
 
-				mock.ExpectQuery(GetByIdQuery).WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(GetByIdQuery)).WillReturnRows(rows)
 			},
 			id: firstProduct.Id,
 			checkResult: func(t *testing.T, result domain.Product, err error) {
@@ -374,13 +429,17 @@ func TestMariaDB_Update(t *testing.T) {
 						firstProduct.ProductTypeId,
 						firstProduct.SellerId,
 						firstProduct.Id,
+						firstProduct.Version,
 					).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			product: firstProduct,
 			checkResult: func(t *testing.T, result domain.Product, err error) {
+				updatedProduct := firstProduct
+				updatedProduct.Version = 2
+
 				assert.NoError(t, err)
-				assert.Equal(t, firstProduct, result)
+				assert.Equal(t, updatedProduct, result)
 			},
 		},
 		{
@@ -401,6 +460,7 @@ func TestMariaDB_Update(t *testing.T) {
 						firstProduct.ProductTypeId,
 						firstProduct.SellerId,
 						firstProduct.Id,
+						firstProduct.Version,
 					).
 					WillReturnError(sql.ErrConnDone)
 			},
@@ -441,7 +501,7 @@ func TestMariaDB_Delete(t *testing.T) {
 			buildStubs: func() {
 				mock.
 					ExpectExec(regexp.QuoteMeta(DeleteQuery)).
-					WithArgs(firstProduct.Id).
+					WithArgs(sqlmock.AnyArg(), firstProduct.Id).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			id: firstProduct.Id,
@@ -449,12 +509,25 @@ func TestMariaDB_Delete(t *testing.T) {
 				assert.NoError(t, err)
 			},
 		},
+		{
+			name: "Not Found",
+			buildStubs: func() {
+				mock.
+					ExpectExec(regexp.QuoteMeta(DeleteQuery)).
+					WithArgs(sqlmock.AnyArg(), firstProduct.Id).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			id: firstProduct.Id,
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+			},
+		},
 		{
 			name: "Fail",
 			buildStubs: func() {
 				mock.
 					ExpectExec(regexp.QuoteMeta(DeleteQuery)).
-					WithArgs(firstProduct.Id).
+					WithArgs(sqlmock.AnyArg(), firstProduct.Id).
 					WillReturnError(sql.ErrConnDone)
 			},
 			id: firstProduct.Id,
@@ -476,3 +549,287 @@ func TestMariaDB_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestMariaDB_SellerScope(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	const sellerId = 9
+	scopedCtx := tenant.WithSeller(ctx, sellerId)
+	repository := NewRepository(db)
+
+	t.Run("GetAll filters by seller", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(GetAllBySellerQuery)).
+			WithArgs(sellerId).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, _ = repository.GetAll(scopedCtx)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetById filters by seller", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(GetByIdAndSellerQuery)).
+			WithArgs(firstProduct.Id, sellerId).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repository.GetById(scopedCtx, firstProduct.Id)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Create assigns the product to the seller", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(CreateQuery)).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+
+		result, err := repository.Create(scopedCtx, firstProduct)
+		assert.NoError(t, err)
+		assert.Equal(t, sellerId, result.SellerId)
+	})
+
+	t.Run("Update only touches the seller's products", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(UpdateBySellerQuery)).
+			WithArgs(
+				firstProduct.ProductCode,
+				firstProduct.Description,
+				firstProduct.Width,
+				firstProduct.Height,
+				firstProduct.Length,
+				firstProduct.NetWeight,
+				firstProduct.ExpirationRate,
+				firstProduct.RecommendedFreezingTemperature,
+				firstProduct.FreezingRate,
+				firstProduct.ProductTypeId,
+				sellerId,
+				firstProduct.Id,
+				firstProduct.Version,
+				sellerId,
+			).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		result, err := repository.Update(scopedCtx, firstProduct)
+		assert.NoError(t, err)
+		assert.Equal(t, sellerId, result.SellerId)
+	})
+
+	t.Run("Delete of another seller's product is not found", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(DeleteBySellerQuery)).
+			WithArgs(sqlmock.AnyArg(), firstProduct.Id, sellerId).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repository.Delete(scopedCtx, firstProduct.Id)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("GetAll with deleted products still filters by seller", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(GetAllWithDeletedBySellerQuery)).
+			WithArgs(sellerId).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, _ = repository.GetAll(softdelete.WithDeleted(scopedCtx))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMariaDB_SoftDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repository := NewRepository(db)
+	deletedAt := date.NewDateTime(time.Date(2022, 7, 9, 10, 0, 0, 0, time.UTC))
+
+	t.Run("GetById reads deleted products when asked to", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{
+			"id",
+			"product_code",
+			"description",
+			"width",
+			"height",
+			"length",
+			"net_weight",
+			"expiration_rate",
+			"recommended_freezing_temperature",
+			"freezing_rate",
+			"product_type_id",
+			"seller_id",
+			"deleted_at",
+			"version",
+		}).AddRow(
+			firstProduct.Id,
+			firstProduct.ProductCode,
+			firstProduct.Description,
+			firstProduct.Width,
+			firstProduct.Height,
+			firstProduct.Length,
+			firstProduct.NetWeight,
+			firstProduct.ExpirationRate,
+			firstProduct.RecommendedFreezingTemperature,
+			firstProduct.FreezingRate,
+			firstProduct.ProductTypeId,
+			firstProduct.SellerId,
+			deletedAt.Time,
+			firstProduct.Version,
+		)
+
+		mock.ExpectQuery(regexp.QuoteMeta(GetByIdWithDeletedQuery) + "$").
+			WithArgs(firstProduct.Id).
+			WillReturnRows(rows)
+
+		result, err := repository.GetById(softdelete.WithDeleted(ctx), firstProduct.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, deletedAt, result.DeletedAt)
+	})
+
+	t.Run("Restore brings a deleted product back", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(RestoreQuery)).
+			WithArgs(firstProduct.Id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repository.Restore(ctx, firstProduct.Id))
+	})
+
+	t.Run("Restore of a product that is not deleted is not found", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(RestoreQuery)).
+			WithArgs(firstProduct.Id).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repository.Restore(ctx, firstProduct.Id), sql.ErrNoRows)
+	})
+}
+
+func TestMariaDB_Version(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repository := NewRepository(db)
+
+	t.Run("Update of a product changed in between is a mismatch", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(UpdateQuery)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := repository.Update(ctx, firstProduct)
+		assert.ErrorIs(t, err, etag.ErrMismatch)
+	})
+
+	t.Run("Delete requires the version read by the client", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(DeleteQuery+etag.Condition)+"$").
+			WithArgs(sqlmock.AnyArg(), firstProduct.Id, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repository.Delete(etag.WithVersion(ctx, 1), firstProduct.Id))
+	})
+
+	t.Run("Delete of a product changed in between is a mismatch", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(DeleteQuery+etag.Condition)+"$").
+			WithArgs(sqlmock.AnyArg(), firstProduct.Id, 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(GetByIdQuery) + "$").
+			WithArgs(firstProduct.Id).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_code", "description", "width", "height", "length", "net_weight", "expiration_rate", "recommended_freezing_temperature", "freezing_rate", "product_type_id", "seller_id", "deleted_at", "version"}).
+				AddRow(firstProduct.Id, firstProduct.ProductCode, firstProduct.Description, firstProduct.Width, firstProduct.Height, firstProduct.Length, firstProduct.NetWeight, firstProduct.ExpirationRate, firstProduct.RecommendedFreezingTemperature, firstProduct.FreezingRate, firstProduct.ProductTypeId, firstProduct.SellerId, nil, 2))
+
+		assert.ErrorIs(t, repository.Delete(etag.WithVersion(ctx, 1), firstProduct.Id), etag.ErrMismatch)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMariaDB_ExistingIds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repository := NewRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(ExistingIdsQuery, "?, ?"))).
+		WithArgs(firstProduct.Id, 99).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(firstProduct.Id))
+
+	existing, err := repository.ExistingIds(ctx, []int{firstProduct.Id, 99})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]bool{firstProduct.Id: true}, existing)
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(ExistingIdsBySellerQuery, "?"))).
+		WithArgs(9, firstProduct.Id).
+		WillReturnError(sql.ErrConnDone)
+
+	_, err = repository.ExistingIds(tenant.WithSeller(ctx, 9), []int{firstProduct.Id})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	existing, err = repository.ExistingIds(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, existing)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMariaDB_ProductCodeExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repository := NewRepository(db)
+
+	// The code of a product of seller 5 is taken for seller 9 too.
+	mock.ExpectQuery(regexp.QuoteMeta(ProductCodeExistsQuery)).
+		WithArgs(firstProduct.ProductCode, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := repository.ProductCodeExists(tenant.WithSeller(ctx, 9), firstProduct.ProductCode, 0)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	mock.ExpectQuery(regexp.QuoteMeta(ProductCodeExistsQuery)).
+		WithArgs(firstProduct.ProductCode, firstProduct.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	exists, err = repository.ProductCodeExists(ctx, firstProduct.ProductCode, firstProduct.Id)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	mock.ExpectQuery(regexp.QuoteMeta(ProductCodeExistsQuery)).WillReturnError(sql.ErrConnDone)
+
+	_, err = repository.ProductCodeExists(ctx, firstProduct.ProductCode, 0)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMariaDB_Dependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repository := NewRepository(db)
+	columns := []string{"batches", "records", "lines"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(DependentsQuery)).
+		WithArgs(firstProduct.Id).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 1, 0))
+
+	found, err := repository.Dependents(ctx, firstProduct.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []dependents.Dependent{
+		{Type: "product_batches", Count: 2},
+		{Type: "product_records", Count: 1},
+		{Type: "purchase_order_lines", Count: 0},
+	}, found)
+
+	// A product of another seller reads no row, and has no dependents to
+	// reveal.
+	mock.ExpectQuery(regexp.QuoteMeta(DependentsBySellerQuery)).
+		WithArgs(firstProduct.Id, 9).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	found, err = repository.Dependents(tenant.WithSeller(ctx, 9), firstProduct.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+
+	mock.ExpectQuery(regexp.QuoteMeta(DependentsQuery)).WillReturnError(sql.ErrConnDone)
+
+	_, err = repository.Dependents(ctx, firstProduct.Id)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
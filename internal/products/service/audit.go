@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+)
+
+type auditedService struct {
+	domain.ProductService
+	audit auditDomain.Service
+}
+
+// NewAuditedService records every change made through s in the audit trail.
+func NewAuditedService(s domain.ProductService, audit auditDomain.Service) domain.ProductService {
+	return &auditedService{s, audit}
+}
+
+func (s *auditedService) Create(ctx context.Context, arg domain.Product) (domain.Product, error) {
+	product, err := s.ProductService.Create(ctx, arg)
+	if err != nil {
+		return domain.Product{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityProducts, product.Id, auditDomain.ActionCreate, nil, product)
+
+	return product, nil
+}
+
+func (s *auditedService) Update(ctx context.Context, id int, arg domain.ProductPatch) (domain.Product, error) {
+	before, _ := s.ProductService.GetById(ctx, id)
+
+	product, err := s.ProductService.Update(ctx, id, arg)
+	if err != nil {
+		return domain.Product{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityProducts, id, auditDomain.ActionUpdate, before, product)
+
+	return product, nil
+}
+
+func (s *auditedService) Delete(ctx context.Context, id int) error {
+	before, _ := s.ProductService.GetById(ctx, id)
+
+	if err := s.ProductService.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityProducts, id, auditDomain.ActionDelete, before, nil)
+
+	return nil
+}
+
+func (s *auditedService) Restore(ctx context.Context, id int) (domain.Product, error) {
+	before, _ := s.ProductService.GetById(softdelete.WithDeleted(ctx), id)
+
+	product, err := s.ProductService.Restore(ctx, id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityProducts, id, auditDomain.ActionRestore, before, product)
+
+	return product, nil
+}
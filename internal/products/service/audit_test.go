@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	mock_audit "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditedService(t *testing.T) {
+	before := domain.Product{Id: 1, ProductCode: "xpto", Description: "old"}
+	after := domain.Product{Id: 1, ProductCode: "xpto", Description: "new"}
+	description := "new"
+
+	t.Run("Create records the new product", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockProductService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().Create(gomock.Any(), before).Return(before, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityProducts, 1, auditDomain.ActionCreate, nil, before)
+
+		result, err := NewAuditedService(service, audit).Create(context.TODO(), before)
+		assert.NoError(t, err)
+		assert.Equal(t, before, result)
+	})
+
+	t.Run("Update records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockProductService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		patch := domain.ProductPatch{Description: &description}
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Update(gomock.Any(), 1, patch).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityProducts, 1, auditDomain.ActionUpdate, before, after)
+
+		_, err := NewAuditedService(service, audit).Update(context.TODO(), 1, patch)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete records the removed product", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockProductService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Delete(gomock.Any(), 1).Return(nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityProducts, 1, auditDomain.ActionDelete, before, nil)
+
+		assert.NoError(t, NewAuditedService(service, audit).Delete(context.TODO(), 1))
+	})
+
+	t.Run("Restore records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockProductService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Restore(gomock.Any(), 1).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityProducts, 1, auditDomain.ActionRestore, before, after)
+
+		_, err := NewAuditedService(service, audit).Restore(context.TODO(), 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed changes are not recorded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockProductService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(domain.Product{}, errors.New("sql: no rows in result set"))
+		service.EXPECT().Delete(gomock.Any(), 1).Return(errors.New("sql: no rows in result set"))
+
+		assert.Error(t, NewAuditedService(service, audit).Delete(context.TODO(), 1))
+	})
+}
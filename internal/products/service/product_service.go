@@ -4,17 +4,21 @@ import (
 	"context"
 	"fmt"
 
+	productTypesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
 )
 
 type service struct {
-	repository domain.ProductRepository
+	repository   domain.ProductRepository
+	productTypes productTypesDomain.Repository
 }
 
-func NewService(r domain.ProductRepository) domain.ProductService {
-	return &service{repository: r}
+func NewService(r domain.ProductRepository, productTypes productTypesDomain.Repository) domain.ProductService {
+	return &service{repository: r, productTypes: productTypes}
 }
 
 func (s service) GetAll(ctx context.Context) ([]domain.Product, error) {
@@ -28,6 +32,10 @@ func (s service) GetAll(ctx context.Context) ([]domain.Product, error) {
 	return products, nil
 }
 
+func (s service) Stream(ctx context.Context, fn func(product domain.Product) error) error {
+	return s.repository.Stream(ctx, fn)
+}
+
 func (s service) GetById(ctx context.Context, id int) (domain.Product, error) {
 	product, err := s.repository.GetById(ctx, id)
 	if err != nil {
@@ -40,17 +48,21 @@ func (s service) GetById(ctx context.Context, id int) (domain.Product, error) {
 }
 
 func (s service) Create(ctx context.Context, arg domain.Product) (domain.Product, error) {
-	products, err := s.repository.GetAll(ctx)
+	exists, err := s.productCodeExists(ctx, arg)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 
 		return domain.Product{}, err
 	}
 
-	for _, product := range products {
-		if product.ProductCode == arg.ProductCode {
-			return domain.Product{}, fmt.Errorf("the product with code \"%s\" already exists", arg.ProductCode)
-		}
+	if exists {
+		return domain.Product{}, fmt.Errorf("the product with code \"%s\" already exists", arg.ProductCode)
+	}
+
+	if _, err := s.productTypes.GetById(ctx, arg.ProductTypeId); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return domain.Product{}, err
 	}
 
 	product, err := s.repository.Create(ctx, arg)
@@ -61,29 +73,28 @@ func (s service) Create(ctx context.Context, arg domain.Product) (domain.Product
 	return product, nil
 }
 
+// productCodeExists reports whether another product has the code of arg.
+// Codes are unique among the products of every seller, and deleted products
+// keep their code until they are purged.
 func (s service) productCodeExists(ctx context.Context, arg domain.Product) (bool, error) {
-	products, err := s.repository.GetAll(ctx)
+	exists, err := s.repository.ProductCodeExists(ctx, arg.ProductCode, arg.Id)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 
 		return true, err
 	}
 
-	for _, product := range products {
-		if product.Id != arg.Id && product.ProductCode == arg.ProductCode {
-			return true, nil
-		}
-	}
-
-	return false, nil
+	return exists, nil
 }
 
-func (s service) updateProduct(ctx context.Context, product, arg domain.Product) (
+func (s service) updateProduct(ctx context.Context, product domain.Product, arg domain.ProductPatch) (
 	domain.Product,
 	error,
 ) {
-	if arg.ProductCode != "" {
-		validProductCode, err := s.productCodeExists(ctx, arg)
+	if arg.ProductCode != nil {
+		product.ProductCode = *arg.ProductCode
+
+		validProductCode, err := s.productCodeExists(ctx, product)
 		if err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
 
@@ -91,63 +102,69 @@ func (s service) updateProduct(ctx context.Context, product, arg domain.Product)
 		}
 
 		if validProductCode {
-			return domain.Product{}, fmt.Errorf("the product with code \"%s\" already exists", arg.ProductCode)
+			return domain.Product{}, fmt.Errorf("the product with code \"%s\" already exists", product.ProductCode)
 		}
-
-		product.ProductCode = arg.ProductCode
 	}
 
-	if arg.Description != "" {
-		product.Description = arg.Description
+	if arg.Description != nil {
+		product.Description = *arg.Description
 	}
 
-	if arg.Width != 0 {
-		product.Width = arg.Width
+	if arg.Width != nil {
+		product.Width = *arg.Width
 	}
 
-	if arg.Height != 0 {
-		product.Height = arg.Height
+	if arg.Height != nil {
+		product.Height = *arg.Height
 	}
 
-	if arg.Length != 0 {
-		product.Length = arg.Length
+	if arg.Length != nil {
+		product.Length = *arg.Length
 	}
 
-	if arg.NetWeight != 0 {
-		product.NetWeight = arg.NetWeight
+	if arg.NetWeight != nil {
+		product.NetWeight = *arg.NetWeight
 	}
 
-	if arg.ExpirationRate != 0 {
-		product.ExpirationRate = arg.ExpirationRate
+	if arg.ExpirationRate != nil {
+		product.ExpirationRate = *arg.ExpirationRate
 	}
 
-	if arg.RecommendedFreezingTemperature != 0 {
-		product.RecommendedFreezingTemperature = arg.RecommendedFreezingTemperature
+	if arg.RecommendedFreezingTemperature != nil {
+		product.RecommendedFreezingTemperature = *arg.RecommendedFreezingTemperature
 	}
 
-	if arg.FreezingRate != 0 {
-		product.FreezingRate = arg.FreezingRate
+	if arg.FreezingRate != nil {
+		product.FreezingRate = *arg.FreezingRate
 	}
 
-	if arg.ProductTypeId != 0 {
-		product.ProductTypeId = arg.ProductTypeId
+	if arg.ProductTypeId != nil && *arg.ProductTypeId != product.ProductTypeId {
+		if _, err := s.productTypes.GetById(ctx, *arg.ProductTypeId); err != nil {
+			return domain.Product{}, err
+		}
+
+		product.ProductTypeId = *arg.ProductTypeId
 	}
 
-	if arg.SellerId != 0 {
-		product.SellerId = arg.SellerId
+	if arg.SellerId != nil {
+		product.SellerId = *arg.SellerId
 	}
 
 	return product, nil
 }
 
-func (s service) Update(ctx context.Context, arg domain.Product) (domain.Product, error) {
-	foundProduct, err := s.repository.GetById(ctx, arg.Id)
+func (s service) Update(ctx context.Context, id int, arg domain.ProductPatch) (domain.Product, error) {
+	foundProduct, err := s.repository.GetById(ctx, id)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 
 		return domain.Product{}, err
 	}
 
+	if err := etag.Check(ctx, foundProduct.Version); err != nil {
+		return domain.Product{}, err
+	}
+
 	updatedProduct, err := s.updateProduct(ctx, foundProduct, arg)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
@@ -165,8 +182,22 @@ func (s service) Update(ctx context.Context, arg domain.Product) (domain.Product
 	return updatedProduct, nil
 }
 
+// Delete removes the product. Its batches, records and order lines are
+// stock and sales history that is never deleted, so they always block the
+// delete, cascading or not.
 func (s service) Delete(ctx context.Context, id int) error {
-	err := s.repository.Delete(ctx, id)
+	found, err := s.repository.Dependents(ctx, id)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return err
+	}
+
+	if err := dependents.Check("product", id, found...); err != nil {
+		return err
+	}
+
+	err = s.repository.Delete(ctx, id)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 
@@ -175,3 +206,13 @@ func (s service) Delete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+func (s service) Restore(ctx context.Context, id int) (domain.Product, error) {
+	if err := s.repository.Restore(ctx, id); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+		return domain.Product{}, err
+	}
+
+	return s.repository.GetById(ctx, id)
+}
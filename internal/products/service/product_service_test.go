@@ -2,12 +2,18 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
 
+	productTypesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
+	mock_product_types "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain/mock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
 	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -16,14 +22,35 @@ func callMock(t *testing.T) (
 	*mock_domain.MockProductRepository,
 	domain.ProductService,
 	context.Context,
+) {
+	repository, productTypes, service, ctx := callMockWithProductTypes(t)
+
+	// Every product type exists unless a test says otherwise.
+	productTypes.
+		EXPECT().
+		GetById(ctx, gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(ctx context.Context, id int) (productTypesDomain.ProductType, error) {
+			return productTypesDomain.ProductType{Id: id}, nil
+		})
+
+	return repository, service, ctx
+}
+
+func callMockWithProductTypes(t *testing.T) (
+	*mock_domain.MockProductRepository,
+	*mock_product_types.MockRepository,
+	domain.ProductService,
+	context.Context,
 ) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	repository := mock_domain.NewMockProductRepository(ctrl)
-	service := NewService(repository)
+	productTypes := mock_product_types.NewMockRepository(ctrl)
+	service := NewService(repository, productTypes)
 
-	return repository, service, context.Background()
+	return repository, productTypes, service, context.Background()
 }
 
 func TestCreate(t *testing.T) {
@@ -52,9 +79,9 @@ func TestCreate(t *testing.T) {
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
 				repository.
 					EXPECT().
-					GetAll(ctx).
+					ProductCodeExists(ctx, expected.ProductCode, expected.Id).
 					Times(1).
-					Return([]domain.Product{}, nil)
+					Return(false, nil)
 
 				repository.
 					EXPECT().
@@ -73,9 +100,9 @@ func TestCreate(t *testing.T) {
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
 				repository.
 					EXPECT().
-					GetAll(ctx).
+					ProductCodeExists(ctx, expected.ProductCode, expected.Id).
 					Times(1).
-					Return([]domain.Product{}, os.ErrPermission)
+					Return(false, os.ErrPermission)
 			},
 			checkResult: func(t *testing.T, result domain.Product, err error) {
 				assert.Error(t, err)
@@ -88,9 +115,9 @@ func TestCreate(t *testing.T) {
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
 				repository.
 					EXPECT().
-					GetAll(ctx).
+					ProductCodeExists(ctx, expected.ProductCode, expected.Id).
 					Times(1).
-					Return([]domain.Product{expected}, nil)
+					Return(true, nil)
 			},
 			checkResult: func(t *testing.T, result domain.Product, err error) {
 				assert.Error(t, err)
@@ -104,9 +131,9 @@ func TestCreate(t *testing.T) {
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
 				repository.
 					EXPECT().
-					GetAll(ctx).
+					ProductCodeExists(ctx, expected.ProductCode, expected.Id).
 					Times(1).
-					Return([]domain.Product{}, nil)
+					Return(false, nil)
 
 				repository.
 					EXPECT().
@@ -134,6 +161,26 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreate_ProductTypeNotFound(t *testing.T) {
+	repository, productTypes, service, ctx := callMockWithProductTypes(t)
+
+	repository.
+		EXPECT().
+		ProductCodeExists(ctx, "xpto", 0).
+		Times(1).
+		Return(false, nil)
+
+	productTypes.
+		EXPECT().
+		GetById(ctx, 3).
+		Times(1).
+		Return(productTypesDomain.ProductType{}, &productTypesDomain.ErrorNotFound{Id: 3})
+
+	result, err := service.Create(ctx, domain.Product{ProductCode: "xpto", ProductTypeId: 3})
+	assert.EqualError(t, err, "product type 3 not found")
+	assert.Equal(t, domain.Product{}, result)
+}
+
 func TestGetAll(t *testing.T) {
 	expected := []domain.Product{
 		{
@@ -302,23 +349,6 @@ func TestUpdate(t *testing.T) {
 		SellerId:                       5,
 	}
 
-	secondProduct := domain.Product{
-		Id:                             2,
-		ProductCode:                    "xablau",
-		Description:                    "description",
-		Width:                          6.3,
-		Height:                         2.3,
-		Length:                         5.1,
-		NetWeight:                      23.5,
-		ExpirationRate:                 0.8,
-		RecommendedFreezingTemperature: -4.3,
-		FreezingRate:                   0.4,
-		ProductTypeId:                  3,
-		SellerId:                       5,
-	}
-
-	allProducts := []domain.Product{firstProduct, secondProduct}
-
 	updatedProduct := domain.Product{
 		Id:                             1,
 		ProductCode:                    "xpto",
@@ -349,15 +379,24 @@ func TestUpdate(t *testing.T) {
 		SellerId:                       5,
 	}
 
+	description := "xpto description"
+	conflictingProductCode := "xablau"
+	zero := 0.0
+
+	frozenProduct := firstProduct
+	frozenProduct.RecommendedFreezingTemperature = 0
+
 	testCases := []struct {
 		name           string
 		updatedProduct domain.Product
+		patch          domain.ProductPatch
 		buildStubs     func(repository *mock_domain.MockProductRepository, ctx context.Context)
 		checkResult    func(t *testing.T, result domain.Product, err error)
 	}{
 		{
 			name:           "OK",
 			updatedProduct: updatedProduct,
+			patch:          domain.ProductPatch{Description: &description},
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
 				repository.
 					EXPECT().
@@ -367,25 +406,43 @@ func TestUpdate(t *testing.T) {
 
 				repository.
 					EXPECT().
-					GetAll(ctx).
+					Update(ctx, updatedProduct).
 					Times(1).
-					Return(allProducts, nil)
+					Return(updatedProduct, nil)
+			},
+			checkResult: func(t *testing.T, result domain.Product, err error) {
+				assert.NoError(t, err)
 
+				assert.Equal(t, updatedProduct, result)
+			},
+		},
+		{
+			name:           "ZeroValue",
+			updatedProduct: frozenProduct,
+			patch:          domain.ProductPatch{RecommendedFreezingTemperature: &zero},
+			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
 				repository.
 					EXPECT().
-					Update(ctx, updatedProduct).
+					GetById(ctx, frozenProduct.Id).
 					Times(1).
-					Return(updatedProduct, nil)
+					Return(firstProduct, nil)
+
+				repository.
+					EXPECT().
+					Update(ctx, frozenProduct).
+					Times(1).
+					Return(frozenProduct, nil)
 			},
 			checkResult: func(t *testing.T, result domain.Product, err error) {
 				assert.NoError(t, err)
 
-				assert.Equal(t, updatedProduct, result)
+				assert.Equal(t, 0.0, result.RecommendedFreezingTemperature)
 			},
 		},
 		{
 			name:           "NotFound",
 			updatedProduct: updatedProduct,
+			patch:          domain.ProductPatch{Description: &description},
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
 				repository.
 					EXPECT().
@@ -403,6 +460,7 @@ func TestUpdate(t *testing.T) {
 		{
 			name:           "Fail",
 			updatedProduct: updatedProduct,
+			patch:          domain.ProductPatch{Description: &description},
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
 				repository.
 					EXPECT().
@@ -410,12 +468,6 @@ func TestUpdate(t *testing.T) {
 					Times(1).
 					Return(firstProduct, nil)
 
-				repository.
-					EXPECT().
-					GetAll(ctx).
-					Times(1).
-					Return(allProducts, nil)
-
 				repository.
 					EXPECT().
 					Update(ctx, updatedProduct).
@@ -431,6 +483,7 @@ func TestUpdate(t *testing.T) {
 		{
 			name:           "Conflict",
 			updatedProduct: conflictingUpdatedProduct,
+			patch:          domain.ProductPatch{ProductCode: &conflictingProductCode},
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
 				repository.
 					EXPECT().
@@ -440,9 +493,9 @@ func TestUpdate(t *testing.T) {
 
 				repository.
 					EXPECT().
-					GetAll(ctx).
+					ProductCodeExists(ctx, conflictingProductCode, conflictingUpdatedProduct.Id).
 					Times(1).
-					Return(allProducts, nil)
+					Return(true, nil)
 			},
 			checkResult: func(t *testing.T, result domain.Product, err error) {
 				assert.Equal(
@@ -460,6 +513,7 @@ func TestUpdate(t *testing.T) {
 		{
 			name:           "Fail2",
 			updatedProduct: conflictingUpdatedProduct,
+			patch:          domain.ProductPatch{ProductCode: &conflictingProductCode},
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
 				repository.
 					EXPECT().
@@ -469,9 +523,9 @@ func TestUpdate(t *testing.T) {
 
 				repository.
 					EXPECT().
-					GetAll(ctx).
+					ProductCodeExists(ctx, conflictingProductCode, conflictingUpdatedProduct.Id).
 					Times(1).
-					Return([]domain.Product{}, os.ErrClosed)
+					Return(false, os.ErrClosed)
 			},
 			checkResult: func(t *testing.T, result domain.Product, err error) {
 				assert.Error(t, err)
@@ -487,12 +541,65 @@ func TestUpdate(t *testing.T) {
 
 			testCase.buildStubs(repository, ctx)
 
-			result, err := service.Update(ctx, testCase.updatedProduct)
+			result, err := service.Update(ctx, testCase.updatedProduct.Id, testCase.patch)
 			testCase.checkResult(t, result, err)
 		})
 	}
 }
 
+func TestUpdate_ProductType(t *testing.T) {
+	product := domain.Product{Id: 1, ProductCode: "xpto", ProductTypeId: 3}
+	productTypeId := 4
+
+	t.Run("OK", func(t *testing.T) {
+		repository, productTypes, service, ctx := callMockWithProductTypes(t)
+
+		updated := product
+		updated.ProductTypeId = 4
+
+		repository.EXPECT().GetById(ctx, 1).Times(1).Return(product, nil)
+		productTypes.EXPECT().GetById(ctx, 4).Times(1).Return(productTypesDomain.ProductType{Id: 4}, nil)
+		repository.EXPECT().Update(ctx, updated).Times(1).Return(updated, nil)
+
+		result, err := service.Update(ctx, 1, domain.ProductPatch{ProductTypeId: &productTypeId})
+		assert.NoError(t, err)
+		assert.Equal(t, updated, result)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repository, productTypes, service, ctx := callMockWithProductTypes(t)
+
+		repository.EXPECT().GetById(ctx, 1).Times(1).Return(product, nil)
+		productTypes.
+			EXPECT().
+			GetById(ctx, 4).
+			Times(1).
+			Return(productTypesDomain.ProductType{}, &productTypesDomain.ErrorNotFound{Id: 4})
+
+		_, err := service.Update(ctx, 1, domain.ProductPatch{ProductTypeId: &productTypeId})
+		assert.EqualError(t, err, "product type 4 not found")
+	})
+}
+
+func TestUpdate_VersionMismatch(t *testing.T) {
+	product := domain.Product{Id: 1, ProductCode: "xpto", Version: 1}
+
+	repository, service, ctx := callMock(t)
+	ctx = etag.WithVersion(ctx, 2)
+
+	repository.
+		EXPECT().
+		GetById(ctx, product.Id).
+		Times(1).
+		Return(product, nil)
+
+	description := "xpto description"
+	result, err := service.Update(ctx, product.Id, domain.ProductPatch{Description: &description})
+
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+	assert.Equal(t, domain.Product{}, result)
+}
+
 func TestDelete(t *testing.T) {
 	existentId := 1
 	nonExistentId := 99
@@ -507,6 +614,12 @@ func TestDelete(t *testing.T) {
 			name:      "OK",
 			productId: existentId,
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
+				repository.
+					EXPECT().
+					Dependents(ctx, existentId).
+					Times(1).
+					Return([]dependents.Dependent{{Type: "product_batches"}, {Type: "product_records"}, {Type: "purchase_order_lines"}}, nil)
+
 				repository.
 					EXPECT().
 					Delete(ctx, existentId).
@@ -521,6 +634,12 @@ func TestDelete(t *testing.T) {
 			name:      "NotFound",
 			productId: nonExistentId,
 			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
+				repository.
+					EXPECT().
+					Dependents(ctx, nonExistentId).
+					Times(1).
+					Return(nil, nil)
+
 				repository.
 					EXPECT().
 					Delete(ctx, nonExistentId).
@@ -532,6 +651,46 @@ func TestDelete(t *testing.T) {
 				assert.EqualError(t, err, fmt.Sprintf("product (%d) not found", nonExistentId))
 			},
 		},
+		{
+			name:      "Dependents",
+			productId: existentId,
+			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
+				repository.
+					EXPECT().
+					Dependents(ctx, existentId).
+					Times(1).
+					Return([]dependents.Dependent{{Type: "product_batches", Count: 2}, {Type: "product_records", Count: 1}, {Type: "purchase_order_lines"}}, nil)
+
+				repository.
+					EXPECT().
+					Delete(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				var dependentsErr *dependents.Error
+				assert.ErrorAs(t, err, &dependentsErr)
+				assert.EqualError(t, err, "product 1 cannot be deleted, it still has 2 product_batches, 1 product_records")
+			},
+		},
+		{
+			name:      "Dependents fail",
+			productId: existentId,
+			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
+				repository.
+					EXPECT().
+					Dependents(ctx, existentId).
+					Times(1).
+					Return(nil, errors.New("connection refused"))
+
+				repository.
+					EXPECT().
+					Delete(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "connection refused")
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -545,3 +704,76 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDelete_Cascade(t *testing.T) {
+	repository, service, ctx := callMock(t)
+	ctx = dependents.WithCascade(ctx)
+
+	repository.EXPECT().Dependents(ctx, 1).Return([]dependents.Dependent{{Type: "purchase_order_lines", Count: 3}}, nil)
+	repository.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.Delete(ctx, 1)
+
+	var dependentsErr *dependents.Error
+	assert.ErrorAs(t, err, &dependentsErr)
+}
+
+func TestRestore(t *testing.T) {
+	existentId := 1
+	nonExistentId := 99
+
+	testCases := []struct {
+		name        string
+		productId   int
+		buildStubs  func(repository *mock_domain.MockProductRepository, ctx context.Context)
+		checkResult func(t *testing.T, result domain.Product, err error)
+	}{
+		{
+			name:      "OK",
+			productId: existentId,
+			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
+				repository.
+					EXPECT().
+					Restore(ctx, existentId).
+					Times(1).
+					Return(nil)
+
+				repository.
+					EXPECT().
+					GetById(ctx, existentId).
+					Times(1).
+					Return(domain.Product{Id: existentId}, nil)
+			},
+			checkResult: func(t *testing.T, result domain.Product, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, existentId, result.Id)
+			},
+		},
+		{
+			name:      "NotFound",
+			productId: nonExistentId,
+			buildStubs: func(repository *mock_domain.MockProductRepository, ctx context.Context) {
+				repository.
+					EXPECT().
+					Restore(ctx, nonExistentId).
+					Times(1).
+					Return(sql.ErrNoRows)
+			},
+			checkResult: func(t *testing.T, result domain.Product, err error) {
+				assert.ErrorIs(t, err, sql.ErrNoRows)
+				assert.Equal(t, domain.Product{}, result)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			repository, service, ctx := callMock(t)
+
+			testCase.buildStubs(repository, ctx)
+
+			result, err := service.Restore(ctx, testCase.productId)
+			testCase.checkResult(t, result, err)
+		})
+	}
+}
@@ -1,11 +1,18 @@
 package controller
 
 import (
-	"log"
+	"errors"
 	"net/http"
+	"strconv"
 
+	buyersDomain "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	orderStatusDomain "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/domain"
+	sequencesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/export"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,13 +20,29 @@ type PurchaseOrder struct {
 	service domain.Service
 }
 
+// requestPurchaseOrders lists the products of the new order in Lines.
+// ProductRecordId is only read to reject it, orders of a single product
+// record are the ones made before orders had lines.
 type requestPurchaseOrders struct {
-	OrderNumber     string `json:"order_number" binding:"required"`
-	OrderDate       string `json:"order_date"`
+	OrderNumber     string `json:"order_number"`
+	OrderDate       string `json:"order_date" binding:"required,date"`
 	TrackingCode    string `json:"tracking_code"`
-	BuyerId         int    `json:"buyer_id"`
-	ProductRecordId int    `json:"product_record_id"`
-	OrderStatusId   int    `json:"order_status_id"`
+	BuyerId         int    `json:"buyer_id" binding:"required,min=1"`
+	ProductRecordId int    `json:"product_record_id" binding:"isdefault"`
+	OrderStatusId   int    `json:"order_status_id" binding:"required,min=1"`
+	AddressId       int    `json:"address_id" binding:"omitempty,min=1"`
+	LocalityId      int    `json:"locality_id" binding:"omitempty,min=1,excluded_with=AddressId"`
+
+	Lines []requestLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+type requestLine struct {
+	ProductId int `json:"product_id" binding:"required,min=1"`
+	Quantity  int `json:"quantity" binding:"required,min=1"`
+}
+
+type requestCarrier struct {
+	LocalityId int `json:"locality_id" binding:"required,min=1"`
 }
 
 func NewPurchaseOrders(s domain.Service) *PurchaseOrder {
@@ -28,23 +51,208 @@ func NewPurchaseOrders(s domain.Service) *PurchaseOrder {
 	}
 }
 
+// createStatus maps the errors of creating an order to HTTP statuses. Errors
+// it does not know answer 500, so the idempotency key of the request is
+// released and the client may retry it.
+func createStatus(err error) int {
+	errStatusNF := &orderStatusDomain.ErrorNotFound{}
+	errBuyerNF := &buyersDomain.ErrorNotFound{}
+	errAddressNF := &buyersDomain.ErrorAddressNotFound{}
+	switch {
+	case errors.As(err, &errStatusNF), errors.As(err, &errBuyerNF), errors.As(err, &errAddressNF):
+		return http.StatusNotFound
+	case errors.Is(err, sequencesDomain.ErrReserved), errors.Is(err, domain.ErrNotInitialStatus), errors.Is(err, domain.ErrNoAddress):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrOrderNumberExists), errors.Is(err, domain.ErrNoCarrier), errors.Is(err, domain.ErrNoPrice):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
 func (por *PurchaseOrder) Create() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var req requestPurchaseOrders
 		if err := ctx.ShouldBindJSON(&req); err != nil {
-			log.Println(req)
-			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity,
-				gin.H{
-					"error":   "VALIDATEERR-1",
-					"message": "Invalid inputs. Please check your inputs",
-				})
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+		orderDate, err := date.Parse(req.OrderDate)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+		var lines []domain.OrderLine
+		for _, line := range req.Lines {
+			lines = append(lines, domain.OrderLine{ProductId: line.ProductId, Quantity: line.Quantity})
+		}
+		po, err := por.service.Create(ctx, domain.PurchaseOrder{
+			OrderNumber:   req.OrderNumber,
+			OrderDate:     orderDate,
+			TrackingCode:  req.TrackingCode,
+			BuyerId:       req.BuyerId,
+			OrderStatusId: req.OrderStatusId,
+			AddressId:     req.AddressId,
+			LocalityId:    req.LocalityId,
+			Lines:         lines,
+		})
+		if err != nil {
+			ctx.JSON(createStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusCreated, response.NewResponse(po))
+	}
+}
+
+// AssignCarrier godoc
+// @Summary Assign a carrier to a purchase order
+// @Tags PurchaseOrders
+// @Description set the delivery locality of the order and hand it to the carrier of that locality with the fewest orders
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Purchase order ID"
+// @Param carrier body requestCarrier true "Delivery locality"
+// @Success 200 {object} domain.PurchaseOrder
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /api/v1/purchase-orders/{id}/carrier [patch]
+func (por *PurchaseOrder) AssignCarrier() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+			return
+		}
+		var req requestCarrier
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+		po, err := por.service.AssignCarrier(ctx, id, req.LocalityId)
+		if errors.Is(err, domain.ErrNoCarrier) || errors.Is(err, domain.ErrNotEditable) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, response.NewResponse(po))
+	}
+}
+
+// GetById godoc
+// @Summary Get a purchase order
+// @Tags PurchaseOrders
+// @Description get a purchase order along with its lines and totals
+// @Produce  json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} domain.PurchaseOrder
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/purchase-orders/{id} [get]
+func (por *PurchaseOrder) GetById() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
 			return
 		}
-		po, err := por.service.Create(ctx, req.OrderNumber, req.OrderDate, req.TrackingCode, req.BuyerId, req.ProductRecordId, req.OrderStatusId)
+		po, err := por.service.GetById(ctx, id)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, response.NewResponse(po))
+	}
+}
+
+// AddLine godoc
+// @Summary Add a line to a purchase order
+// @Tags PurchaseOrders
+// @Description add a product to an order that is not in a terminal status, priced at the sale price of its latest product record
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Purchase order ID"
+// @Param line body requestLine true "Product and quantity"
+// @Success 201 {object} domain.PurchaseOrder
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /api/v1/purchase-orders/{id}/lines [post]
+func (por *PurchaseOrder) AddLine() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
 		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+			return
+		}
+		var req requestLine
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
+			return
+		}
+		po, err := por.service.AddLine(ctx, id, domain.OrderLine{ProductId: req.ProductId, Quantity: req.Quantity})
+		if errors.Is(err, domain.ErrNotEditable) || errors.Is(err, domain.ErrNoPrice) {
 			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusCreated, response.NewResponse(po))
 	}
 }
+
+// RemoveLine godoc
+// @Summary Remove a line from a purchase order
+// @Tags PurchaseOrders
+// @Description remove a product from an order that is not in a terminal status. The only line of an order cannot be removed
+// @Param id path int true "Purchase order ID"
+// @Param lineId path int true "Line ID"
+// @Success 204
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /api/v1/purchase-orders/{id}/lines/{lineId} [delete]
+func (por *PurchaseOrder) RemoveLine() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+			return
+		}
+		lineId, err := strconv.Atoi(ctx.Param("lineId"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid line ID"})
+			return
+		}
+		err = por.service.RemoveLine(ctx, id, lineId)
+		if errors.Is(err, domain.ErrNotEditable) || errors.Is(err, domain.ErrLastLine) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// Export godoc
+// @Summary Export purchase orders
+// @Tags PurchaseOrders
+// @Description stream every purchase order as CSV, NDJSON or XLSX
+// @Produce text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv (default), ndjson or xlsx"
+// @Success 200
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/purchase-orders/export [get]
+func (por *PurchaseOrder) Export() gin.HandlerFunc {
+	return export.Handler("purchase_orders", por.service.Stream)
+}
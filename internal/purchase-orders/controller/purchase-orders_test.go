@@ -4,12 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	buyersDomain "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	orderStatusDomain "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/domain"
 	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/domain/mock"
+	sequencesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -24,7 +29,7 @@ var (
 	purchaseOrder = domain.PurchaseOrder{
 		Id:              1,
 		OrderNumber:     "xpto",
-		OrderDate:       "2020-02-02",
+		OrderDate:       date.New(2020, 2, 2),
 		TrackingCode:    "ew23143543jn",
 		BuyerId:         1,
 		ProductRecordId: 1,
@@ -57,10 +62,23 @@ func callMock(t *testing.T) (
 
 func TestPurchaseOrderController_Create(t *testing.T) {
 	newPurchaseOrder := purchaseOrder
-	newPurchaseOrder.Id = 0
+	newPurchaseOrder.Id, newPurchaseOrder.ProductRecordId = 0, 0
+	newPurchaseOrder.Lines = []domain.OrderLine{{ProductId: 7, Quantity: 3}}
 
 	unprocessablePurchaseOrder := struct{}{}
 
+	addressAndLocality := newPurchaseOrder
+	addressAndLocality.AddressId, addressAndLocality.LocalityId = 5, 3
+
+	withoutLines := newPurchaseOrder
+	withoutLines.Lines = nil
+
+	productRecordAndLines := newPurchaseOrder
+	productRecordAndLines.ProductRecordId = 1
+
+	emptyLine := newPurchaseOrder
+	emptyLine.Lines = []domain.OrderLine{{ProductId: 7}}
+
 	testCases := []struct {
 		name        string
 		payload     interface{}
@@ -73,15 +91,7 @@ func TestPurchaseOrderController_Create(t *testing.T) {
 			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
 				service.
 					EXPECT().
-					Create(
-						ctx,
-						newPurchaseOrder.OrderNumber,
-						newPurchaseOrder.OrderDate,
-						newPurchaseOrder.TrackingCode,
-						newPurchaseOrder.BuyerId,
-						newPurchaseOrder.ProductRecordId,
-						newPurchaseOrder.OrderStatusId,
-					).
+					Create(ctx, newPurchaseOrder).
 					Times(ONCE).
 					Return(&purchaseOrder, nil)
 			},
@@ -109,23 +119,59 @@ func TestPurchaseOrderController_Create(t *testing.T) {
 				assert.NotEmpty(t, body.Error)
 			},
 		},
+		{
+			name:       "Address and locality",
+			payload:    addressAndLocality,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+				assert.Contains(t, res.Body.String(), "must not be set together with address_id")
+			},
+		},
+		{
+			name:       "Without lines",
+			payload:    withoutLines,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+				assert.Contains(t, res.Body.String(), "lines")
+			},
+		},
+		{
+			name:       "Empty lines",
+			payload:    map[string]interface{}{"order_date": "2020-02-02", "buyer_id": 1, "order_status_id": 1, "lines": []interface{}{}},
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+				assert.Contains(t, res.Body.String(), "must have at least 1 items")
+			},
+		},
+		{
+			name:       "Product record and lines",
+			payload:    productRecordAndLines,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+				assert.Contains(t, res.Body.String(), "must not be set")
+			},
+		},
+		{
+			name:       "Line without quantity",
+			payload:    emptyLine,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+			},
+		},
 		{
 			name:    "Conflict",
 			payload: newPurchaseOrder,
 			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
 				service.
 					EXPECT().
-					Create(
-						ctx,
-						newPurchaseOrder.OrderNumber,
-						newPurchaseOrder.OrderDate,
-						newPurchaseOrder.TrackingCode,
-						newPurchaseOrder.BuyerId,
-						newPurchaseOrder.ProductRecordId,
-						newPurchaseOrder.OrderStatusId,
-					).
+					Create(ctx, newPurchaseOrder).
 					Times(ONCE).
-					Return(&noPurchaseOrder, someError)
+					Return(&noPurchaseOrder, domain.ErrOrderNumberExists)
 			},
 			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusConflict, res.Code)
@@ -137,6 +183,105 @@ func TestPurchaseOrderController_Create(t *testing.T) {
 				assert.NotEmpty(t, body.Error)
 			},
 		},
+		{
+			name:    "Fail_Service",
+			payload: newPurchaseOrder,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Create(ctx, newPurchaseOrder).
+					Times(ONCE).
+					Return(nil, someError)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, res.Code)
+			},
+		},
+		{
+			name:    "Buyer not found",
+			payload: newPurchaseOrder,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Create(ctx, newPurchaseOrder).
+					Times(ONCE).
+					Return(nil, &buyersDomain.ErrorNotFound{Id: 1})
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, res.Code)
+			},
+		},
+		{
+			name:    "Order status not found",
+			payload: newPurchaseOrder,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Create(ctx, newPurchaseOrder).
+					Times(ONCE).
+					Return(nil, &orderStatusDomain.ErrorNotFound{Id: 1})
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, res.Code)
+			},
+		},
+		{
+			name:    "No default address",
+			payload: newPurchaseOrder,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Create(ctx, newPurchaseOrder).
+					Times(ONCE).
+					Return(nil, fmt.Errorf("%w: buyer 1", domain.ErrNoAddress))
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+			},
+		},
+		{
+			name:    "Not initial status",
+			payload: newPurchaseOrder,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Create(ctx, newPurchaseOrder).
+					Times(ONCE).
+					Return(nil, domain.ErrNotInitialStatus)
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+			},
+		},
+		{
+			name:    "No price",
+			payload: newPurchaseOrder,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Create(ctx, newPurchaseOrder).
+					Times(ONCE).
+					Return(nil, fmt.Errorf("%w: product 7", domain.ErrNoPrice))
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, res.Code)
+			},
+		},
+		{
+			name:    "Reserved order number",
+			payload: newPurchaseOrder,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.
+					EXPECT().
+					Create(ctx, newPurchaseOrder).
+					Times(ONCE).
+					Return(nil, fmt.Errorf("order number PO-20220801-0000425: %w", sequencesDomain.ErrReserved))
+			},
+			checkResult: func(t *testing.T, res *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+				assert.Contains(t, res.Body.String(), "reserved")
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -160,3 +305,281 @@ func TestPurchaseOrderController_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestPurchaseOrderController_AssignCarrier(t *testing.T) {
+	const path = "/api/v1/purchase-orders/:id/carrier"
+
+	testCases := []struct {
+		name       string
+		target     string
+		payload    string
+		buildStubs func(service *mock_domain.MockService, ctx gomock.Matcher)
+		status     int
+	}{
+		{
+			name:    "OK",
+			target:  PATH + "1/carrier",
+			payload: `{"locality_id": 3}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				assigned := purchaseOrder
+				assigned.LocalityId, assigned.CarrierId = 3, 2
+				service.EXPECT().AssignCarrier(ctx, 1, 3).Times(ONCE).Return(&assigned, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:    "No carrier",
+			target:  PATH + "1/carrier",
+			payload: `{"locality_id": 3}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().AssignCarrier(ctx, 1, 3).Times(ONCE).Return(nil, domain.ErrNoCarrier)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:    "Terminal status",
+			target:  PATH + "1/carrier",
+			payload: `{"locality_id": 3}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().AssignCarrier(ctx, 1, 3).Times(ONCE).Return(nil, domain.ErrNotEditable)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:    "Not found",
+			target:  PATH + "9/carrier",
+			payload: `{"locality_id": 3}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().AssignCarrier(ctx, 9, 3).Times(ONCE).Return(nil, someError)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid id",
+			target:     PATH + "xpto/carrier",
+			payload:    `{"locality_id": 3}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			status:     http.StatusBadRequest,
+		},
+		{
+			name:       "Missing locality",
+			target:     PATH + "1/carrier",
+			payload:    `{}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			status:     http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, handler, api, ctx := callMock(t)
+
+			api.PATCH(path, handler.AssignCarrier())
+
+			testCase.buildStubs(service, ctx)
+
+			req := httptest.NewRequest(http.MethodPatch, testCase.target, bytes.NewBufferString(testCase.payload))
+			res := httptest.NewRecorder()
+			api.ServeHTTP(res, req)
+
+			assert.Equal(t, testCase.status, res.Code)
+		})
+	}
+}
+
+func TestPurchaseOrderController_GetById(t *testing.T) {
+	const path = "/api/v1/purchase-orders/:id"
+
+	testCases := []struct {
+		name       string
+		target     string
+		buildStubs func(service *mock_domain.MockService, ctx gomock.Matcher)
+		status     int
+	}{
+		{
+			name:   "OK",
+			target: PATH + "1",
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().GetById(ctx, 1).Times(ONCE).Return(&purchaseOrder, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "Not found",
+			target: PATH + "9",
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().GetById(ctx, 9).Times(ONCE).Return(nil, someError)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid id",
+			target:     PATH + "xpto",
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			status:     http.StatusBadRequest,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, handler, api, ctx := callMock(t)
+
+			api.GET(path, handler.GetById())
+
+			testCase.buildStubs(service, ctx)
+
+			req := httptest.NewRequest(http.MethodGet, testCase.target, nil)
+			res := httptest.NewRecorder()
+			api.ServeHTTP(res, req)
+
+			assert.Equal(t, testCase.status, res.Code)
+		})
+	}
+}
+
+func TestPurchaseOrderController_AddLine(t *testing.T) {
+	const path = "/api/v1/purchase-orders/:id/lines"
+
+	line := domain.OrderLine{ProductId: 7, Quantity: 3}
+
+	testCases := []struct {
+		name       string
+		target     string
+		payload    string
+		buildStubs func(service *mock_domain.MockService, ctx gomock.Matcher)
+		status     int
+	}{
+		{
+			name:    "OK",
+			target:  PATH + "1/lines",
+			payload: `{"product_id": 7, "quantity": 3}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().AddLine(ctx, 1, line).Times(ONCE).Return(&purchaseOrder, nil)
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name:    "Not editable",
+			target:  PATH + "1/lines",
+			payload: `{"product_id": 7, "quantity": 3}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().AddLine(ctx, 1, line).Times(ONCE).Return(nil, domain.ErrNotEditable)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:    "No price",
+			target:  PATH + "1/lines",
+			payload: `{"product_id": 7, "quantity": 3}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().AddLine(ctx, 1, line).Times(ONCE).Return(nil, fmt.Errorf("%w: product 7", domain.ErrNoPrice))
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:    "Not found",
+			target:  PATH + "9/lines",
+			payload: `{"product_id": 7, "quantity": 3}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().AddLine(ctx, 9, line).Times(ONCE).Return(nil, someError)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid id",
+			target:     PATH + "xpto/lines",
+			payload:    `{"product_id": 7, "quantity": 3}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			status:     http.StatusBadRequest,
+		},
+		{
+			name:       "Missing quantity",
+			target:     PATH + "1/lines",
+			payload:    `{"product_id": 7}`,
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			status:     http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, handler, api, ctx := callMock(t)
+
+			api.POST(path, handler.AddLine())
+
+			testCase.buildStubs(service, ctx)
+
+			req := httptest.NewRequest(http.MethodPost, testCase.target, bytes.NewBufferString(testCase.payload))
+			res := httptest.NewRecorder()
+			api.ServeHTTP(res, req)
+
+			assert.Equal(t, testCase.status, res.Code)
+		})
+	}
+}
+
+func TestPurchaseOrderController_RemoveLine(t *testing.T) {
+	const path = "/api/v1/purchase-orders/:id/lines/:lineId"
+
+	testCases := []struct {
+		name       string
+		target     string
+		buildStubs func(service *mock_domain.MockService, ctx gomock.Matcher)
+		status     int
+	}{
+		{
+			name:   "OK",
+			target: PATH + "1/lines/3",
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().RemoveLine(ctx, 1, 3).Times(ONCE).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "Not editable",
+			target: PATH + "1/lines/3",
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().RemoveLine(ctx, 1, 3).Times(ONCE).Return(domain.ErrNotEditable)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:   "Last line",
+			target: PATH + "1/lines/3",
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().RemoveLine(ctx, 1, 3).Times(ONCE).Return(domain.ErrLastLine)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:   "Line not found",
+			target: PATH + "1/lines/9",
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {
+				service.EXPECT().RemoveLine(ctx, 1, 9).Times(ONCE).Return(domain.ErrLineNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid line id",
+			target:     PATH + "1/lines/xpto",
+			buildStubs: func(service *mock_domain.MockService, ctx gomock.Matcher) {},
+			status:     http.StatusBadRequest,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, handler, api, ctx := callMock(t)
+
+			api.DELETE(path, handler.RemoveLine())
+
+			testCase.buildStubs(service, ctx)
+
+			req := httptest.NewRequest(http.MethodDelete, testCase.target, nil)
+			res := httptest.NewRecorder()
+			api.ServeHTTP(res, req)
+
+			assert.Equal(t, testCase.status, res.Code)
+		})
+	}
+}
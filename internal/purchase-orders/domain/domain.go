@@ -1,22 +1,93 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
 
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+)
+
+// PurchaseOrder is delivered to LocalityId by CarrierId. Both are 0 while
+// the order has no delivery locality. AddressId is the buyer address the
+// locality was taken from, if any.
+//
+// The products of the order are its Lines. ProductRecordId is only set on
+// orders of a single product made before orders had lines.
 type PurchaseOrder struct {
-	Id              int    `json:"id"`
-	OrderNumber     string `json:"order_number"`
-	OrderDate       string `json:"order_date"`
-	TrackingCode    string `json:"tracking_code"`
-	BuyerId         int    `json:"buyer_id"`
-	ProductRecordId int    `json:"product_record_id"`
-	OrderStatusId   int    `json:"order_status_id"`
+	Id              int         `json:"id"`
+	OrderNumber     string      `json:"order_number"`
+	OrderDate       date.Date   `json:"order_date"`
+	TrackingCode    string      `json:"tracking_code"`
+	BuyerId         int         `json:"buyer_id"`
+	ProductRecordId int         `json:"product_record_id,omitempty"`
+	OrderStatusId   int         `json:"order_status_id"`
+	AddressId       int         `json:"address_id,omitempty"`
+	LocalityId      int         `json:"locality_id,omitempty"`
+	CarrierId       int         `json:"carrier_id,omitempty"`
+	Lines           []OrderLine `json:"lines,omitempty" export:"-"`
+	Total           float64     `json:"total,omitempty" export:"-"`
+}
+
+// OrderLine is a quantity of a product in a purchase order. UnitPrice is the
+// sale price of the latest record of the product when the line was added,
+// and ProductRecordId that record, so later price changes leave the order as
+// it was.
+type OrderLine struct {
+	Id              int     `json:"id"`
+	PurchaseOrderId int     `json:"purchase_order_id"`
+	ProductId       int     `json:"product_id"`
+	ProductRecordId int     `json:"product_record_id"`
+	Quantity        int     `json:"quantity"`
+	UnitPrice       float64 `json:"unit_price"`
+	Total           float64 `json:"total"`
 }
 
+// ErrNoCarrier is returned when no carrier serves the delivery locality of an
+// order.
+var ErrNoCarrier = errors.New("no carrier serves locality")
+
+// ErrNotInitialStatus is returned when an order is created in a status orders
+// cannot start in.
+var ErrNotInitialStatus = errors.New("order status is not an initial status")
+
+// ErrNotEditable is returned when the lines or the carrier of an order in a
+// terminal status are changed.
+var ErrNotEditable = errors.New("purchase order is not editable in its status")
+
+// ErrNoPrice is returned for a line of a product that has no product record
+// to take the unit price from.
+var ErrNoPrice = errors.New("product has no price record")
+
+// ErrOrderNumberExists is returned when an order is created with the number of
+// another order.
+var ErrOrderNumberExists = errors.New("order number already exists")
+
+var ErrLineNotFound = errors.New("purchase order line not found")
+
+// ErrLastLine is returned when the only line of an order is removed, as
+// orders have at least one line.
+var ErrLastLine = errors.New("cannot remove the only line of a purchase order")
+
+// ErrNoAddress is returned when an order names neither an address nor a
+// locality and its buyer has no default address to deliver to.
+var ErrNoAddress = errors.New("buyer has no default address")
+
 type Repository interface {
-	Create(ctx context.Context, OrderNumber string, OrderDate string, TrackingCode string, BuyerId int, ProductRecordId int, OrderStatusId int) (*PurchaseOrder, error)
+	Create(ctx context.Context, arg PurchaseOrder) (*PurchaseOrder, error)
 	GetAll(ctx context.Context) ([]PurchaseOrder, error)
+	GetById(ctx context.Context, id int) (*PurchaseOrder, error)
+	Stream(ctx context.Context, fn func(po PurchaseOrder) error) error
+	UpdateCarrier(ctx context.Context, id, localityId, carrierId int) error
+	GetLines(ctx context.Context, purchaseOrderId int) ([]OrderLine, error)
+	CreateLine(ctx context.Context, arg OrderLine) (OrderLine, error)
+	DeleteLine(ctx context.Context, purchaseOrderId, id int) error
 }
 
 type Service interface {
-	Create(ctx context.Context, OrderNumber string, OrderDate string, TrackingCode string, BuyerId int, ProductRecordId int, OrderStatusId int) (*PurchaseOrder, error)
+	Stream(ctx context.Context, fn func(po PurchaseOrder) error) error
+	Create(ctx context.Context, arg PurchaseOrder) (*PurchaseOrder, error)
+	AssignCarrier(ctx context.Context, id, localityId int) (*PurchaseOrder, error)
+	GetById(ctx context.Context, id int) (*PurchaseOrder, error)
+	AddLine(ctx context.Context, id int, arg OrderLine) (*PurchaseOrder, error)
+	RemoveLine(ctx context.Context, id, lineId int) error
 }
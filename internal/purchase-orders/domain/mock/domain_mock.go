@@ -36,18 +36,47 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockRepository) Create(arg0 context.Context, arg1, arg2, arg3 string, arg4, arg5, arg6 int) (*domain.PurchaseOrder, error) {
+func (m *MockRepository) Create(arg0 context.Context, arg1 domain.PurchaseOrder) (*domain.PurchaseOrder, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(*domain.PurchaseOrder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0, arg1)
+}
+
+// CreateLine mocks base method.
+func (m *MockRepository) CreateLine(arg0 context.Context, arg1 domain.OrderLine) (domain.OrderLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLine", arg0, arg1)
+	ret0, _ := ret[0].(domain.OrderLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLine indicates an expected call of CreateLine.
+func (mr *MockRepositoryMockRecorder) CreateLine(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLine", reflect.TypeOf((*MockRepository)(nil).CreateLine), arg0, arg1)
+}
+
+// DeleteLine mocks base method.
+func (m *MockRepository) DeleteLine(arg0 context.Context, arg1, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLine", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLine indicates an expected call of DeleteLine.
+func (mr *MockRepositoryMockRecorder) DeleteLine(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLine", reflect.TypeOf((*MockRepository)(nil).DeleteLine), arg0, arg1, arg2)
 }
 
 // GetAll mocks base method.
@@ -65,6 +94,64 @@ func (mr *MockRepositoryMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRepository)(nil).GetAll), arg0)
 }
 
+// GetById mocks base method.
+func (m *MockRepository) GetById(arg0 context.Context, arg1 int) (*domain.PurchaseOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", arg0, arg1)
+	ret0, _ := ret[0].(*domain.PurchaseOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockRepositoryMockRecorder) GetById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRepository)(nil).GetById), arg0, arg1)
+}
+
+// GetLines mocks base method.
+func (m *MockRepository) GetLines(arg0 context.Context, arg1 int) ([]domain.OrderLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLines", arg0, arg1)
+	ret0, _ := ret[0].([]domain.OrderLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLines indicates an expected call of GetLines.
+func (mr *MockRepositoryMockRecorder) GetLines(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLines", reflect.TypeOf((*MockRepository)(nil).GetLines), arg0, arg1)
+}
+
+// Stream mocks base method.
+func (m *MockRepository) Stream(arg0 context.Context, arg1 func(domain.PurchaseOrder) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockRepositoryMockRecorder) Stream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockRepository)(nil).Stream), arg0, arg1)
+}
+
+// UpdateCarrier mocks base method.
+func (m *MockRepository) UpdateCarrier(arg0 context.Context, arg1, arg2, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCarrier", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCarrier indicates an expected call of UpdateCarrier.
+func (mr *MockRepositoryMockRecorder) UpdateCarrier(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCarrier", reflect.TypeOf((*MockRepository)(nil).UpdateCarrier), arg0, arg1, arg2, arg3)
+}
+
 // MockService is a mock of Service interface.
 type MockService struct {
 	ctrl     *gomock.Controller
@@ -88,17 +175,90 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// AddLine mocks base method.
+func (m *MockService) AddLine(arg0 context.Context, arg1 int, arg2 domain.OrderLine) (*domain.PurchaseOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLine", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.PurchaseOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLine indicates an expected call of AddLine.
+func (mr *MockServiceMockRecorder) AddLine(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLine", reflect.TypeOf((*MockService)(nil).AddLine), arg0, arg1, arg2)
+}
+
+// AssignCarrier mocks base method.
+func (m *MockService) AssignCarrier(arg0 context.Context, arg1, arg2 int) (*domain.PurchaseOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignCarrier", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.PurchaseOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignCarrier indicates an expected call of AssignCarrier.
+func (mr *MockServiceMockRecorder) AssignCarrier(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignCarrier", reflect.TypeOf((*MockService)(nil).AssignCarrier), arg0, arg1, arg2)
+}
+
 // Create mocks base method.
-func (m *MockService) Create(arg0 context.Context, arg1, arg2, arg3 string, arg4, arg5, arg6 int) (*domain.PurchaseOrder, error) {
+func (m *MockService) Create(arg0 context.Context, arg1 domain.PurchaseOrder) (*domain.PurchaseOrder, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(*domain.PurchaseOrder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockServiceMockRecorder) Create(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), arg0, arg1)
+}
+
+// GetById mocks base method.
+func (m *MockService) GetById(arg0 context.Context, arg1 int) (*domain.PurchaseOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", arg0, arg1)
+	ret0, _ := ret[0].(*domain.PurchaseOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockServiceMockRecorder) GetById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockService)(nil).GetById), arg0, arg1)
+}
+
+// RemoveLine mocks base method.
+func (m *MockService) RemoveLine(arg0 context.Context, arg1, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveLine", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveLine indicates an expected call of RemoveLine.
+func (mr *MockServiceMockRecorder) RemoveLine(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLine", reflect.TypeOf((*MockService)(nil).RemoveLine), arg0, arg1, arg2)
+}
+
+// Stream mocks base method.
+func (m *MockService) Stream(arg0 context.Context, arg1 func(domain.PurchaseOrder) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockServiceMockRecorder) Stream(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockService)(nil).Stream), arg0, arg1)
 }
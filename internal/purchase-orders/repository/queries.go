@@ -1,6 +1,11 @@
 package repository
 
 const (
-	queryCreate = "insert into purchase_orders (order_number, order_date, tracking_code, buyer_id, product_record_id, order_status_id) values(?,?,?,?,?,?)"
-	queryGetAll = "SELECT id, order_number, order_date, tracking_code, buyer_id, product_record_id, order_status_id from purchase_orders "
+	queryCreate        = "insert into purchase_orders (order_number, order_date, tracking_code, buyer_id, product_record_id, order_status_id, address_id, locality_id, carrier_id) values(?,?,?,?,?,?,?,?,?)"
+	queryGetAll        = "SELECT id, order_number, order_date, tracking_code, buyer_id, product_record_id, order_status_id, address_id, locality_id, carrier_id from purchase_orders "
+	queryGetById       = queryGetAll + "where id = ?"
+	queryUpdateCarrier = "UPDATE purchase_orders SET locality_id = ?, carrier_id = ? WHERE id = ?"
+	queryGetLines      = "SELECT id, purchase_order_id, product_id, product_record_id, quantity, unit_price FROM purchase_order_lines WHERE purchase_order_id = ? ORDER BY id"
+	queryCreateLine    = "INSERT INTO purchase_order_lines (purchase_order_id, product_id, product_record_id, quantity, unit_price) VALUES (?, ?, ?, ?, ?)"
+	queryDeleteLine    = "DELETE FROM purchase_order_lines WHERE purchase_order_id = ? AND id = ?"
 )
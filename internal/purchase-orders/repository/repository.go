@@ -3,8 +3,10 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 )
 
 type repository struct {
@@ -12,49 +14,147 @@ type repository struct {
 }
 
 func (r *repository) GetAll(ctx context.Context) ([]domain.PurchaseOrder, error) {
-	rows, err := r.db.QueryContext(ctx, queryGetAll)
+	po := make([]domain.PurchaseOrder, 0)
+	err := r.Stream(ctx, func(p domain.PurchaseOrder) error {
+		po = append(po, p)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	po := make([]domain.PurchaseOrder, 0)
+	return po, nil
+}
+
+// Stream hands the purchase orders to fn one at a time, as they are read.
+func (r *repository) Stream(ctx context.Context, fn func(po domain.PurchaseOrder) error) error {
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, queryGetAll)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
 	for rows.Next() {
-		var p domain.PurchaseOrder
-		err := rows.Scan(
-			&p.Id,
-			&p.OrderNumber,
-			&p.OrderDate,
-			&p.TrackingCode,
-			&p.BuyerId,
-			&p.ProductRecordId,
-			&p.OrderStatusId,
+		p, err := scan(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *repository) GetById(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	p, err := scan(r.db.QueryRowContext(ctx, queryGetById, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("purchase order %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Create inserts the order along with its lines, in one transaction.
+func (r *repository) Create(ctx context.Context, arg domain.PurchaseOrder) (*domain.PurchaseOrder, error) {
+	i := arg
+	err := transaction.NewRunner(r.db).Run(ctx, func(ctx context.Context) error {
+		result, err := transaction.From(ctx, r.db).ExecContext(
+			ctx,
+			queryCreate,
+			arg.OrderNumber,
+			arg.OrderDate,
+			arg.TrackingCode,
+			arg.BuyerId,
+			nullable(arg.ProductRecordId),
+			arg.OrderStatusId,
+			nullable(arg.AddressId),
+			nullable(arg.LocalityId),
+			nullable(arg.CarrierId),
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		po = append(po, p)
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("error retrieving id %d", id)
+		}
+
+		i.Id = int(id)
+		if arg.Lines != nil {
+			i.Lines = make([]domain.OrderLine, len(arg.Lines))
+		}
+		for l, line := range arg.Lines {
+			line.PurchaseOrderId = i.Id
+			if i.Lines[l], err = r.CreateLine(ctx, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return po, nil
+	return &i, nil
 }
 
-func (r *repository) Create(ctx context.Context, OrderNumber string, OrderDate string, TrackingCode string, BuyerId int, ProductRecordId int, OrderStatusId int) (*domain.PurchaseOrder, error) {
-	result, err := r.db.ExecContext(ctx, queryCreate, OrderNumber, OrderDate, TrackingCode, BuyerId, ProductRecordId, OrderStatusId)
+func (r *repository) UpdateCarrier(ctx context.Context, id, localityId, carrierId int) error {
+	_, err := r.db.ExecContext(ctx, queryUpdateCarrier, nullable(localityId), nullable(carrierId), id)
+	return err
+}
+
+func (r *repository) GetLines(ctx context.Context, purchaseOrderId int) ([]domain.OrderLine, error) {
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, queryGetLines, purchaseOrderId)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+	lines := []domain.OrderLine{}
+	for rows.Next() {
+		var l domain.OrderLine
+		err := rows.Scan(&l.Id, &l.PurchaseOrderId, &l.ProductId, &l.ProductRecordId, &l.Quantity, &l.UnitPrice)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
+
+func (r *repository) CreateLine(ctx context.Context, arg domain.OrderLine) (domain.OrderLine, error) {
+	result, err := transaction.From(ctx, r.db).ExecContext(
+		ctx,
+		queryCreateLine,
+		arg.PurchaseOrderId,
+		arg.ProductId,
+		arg.ProductRecordId,
+		arg.Quantity,
+		arg.UnitPrice,
+	)
+	if err != nil {
+		return domain.OrderLine{}, err
+	}
 	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving id %d", id)
+		return domain.OrderLine{}, err
 	}
+	arg.Id = int(id)
+	return arg, nil
+}
 
-	i := domain.PurchaseOrder{Id: int(id),
-		OrderNumber:     OrderNumber,
-		OrderDate:       OrderDate,
-		TrackingCode:    TrackingCode,
-		BuyerId:         BuyerId,
-		ProductRecordId: ProductRecordId,
-		OrderStatusId:   OrderStatusId,
+func (r *repository) DeleteLine(ctx context.Context, purchaseOrderId, id int) error {
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryDeleteLine, purchaseOrderId, id)
+	if err != nil {
+		return err
 	}
-	return &i, nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrLineNotFound
+	}
+	return nil
 }
 
 func NewRepository(db *sql.DB) domain.Repository {
@@ -62,3 +162,36 @@ func NewRepository(db *sql.DB) domain.Repository {
 		db: db,
 	}
 }
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (domain.PurchaseOrder, error) {
+	var (
+		p                                domain.PurchaseOrder
+		productRecordId                  sql.NullInt64
+		addressId, localityId, carrierId sql.NullInt64
+	)
+	err := row.Scan(
+		&p.Id,
+		&p.OrderNumber,
+		&p.OrderDate,
+		&p.TrackingCode,
+		&p.BuyerId,
+		&productRecordId,
+		&p.OrderStatusId,
+		&addressId,
+		&localityId,
+		&carrierId,
+	)
+	p.ProductRecordId = int(productRecordId.Int64)
+	p.AddressId = int(addressId.Int64)
+	p.LocalityId, p.CarrierId = int(localityId.Int64), int(carrierId.Int64)
+	return p, err
+}
+
+// nullable stores the zero id as NULL, so the foreign key is left unset.
+func nullable(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
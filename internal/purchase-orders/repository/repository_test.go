@@ -10,6 +10,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,7 +18,7 @@ var (
 	firstPurchaseOrder = domain.PurchaseOrder{
 		Id:              1,
 		OrderNumber:     "xpto",
-		OrderDate:       "2020-02-02",
+		OrderDate:       date.New(2020, 2, 2),
 		TrackingCode:    "ew23143543jn",
 		BuyerId:         1,
 		ProductRecordId: 1,
@@ -26,11 +27,14 @@ var (
 	secondPurchaseOrder = domain.PurchaseOrder{
 		Id:              2,
 		OrderNumber:     "r2d2",
-		OrderDate:       "2020-02-02",
+		OrderDate:       date.New(2020, 2, 2),
 		TrackingCode:    "ks8347587345hd",
 		BuyerId:         2,
 		ProductRecordId: 2,
 		OrderStatusId:   2,
+		AddressId:       5,
+		LocalityId:      3,
+		CarrierId:       4,
 	}
 	emptyPurchaseOrder *domain.PurchaseOrder
 	allPurchaseOrders  = []domain.PurchaseOrder{
@@ -62,6 +66,9 @@ func TestRepository_GetAll(t *testing.T) {
 					"buyer_id",
 					"product_record_id",
 					"order_status_id",
+					"address_id",
+					"locality_id",
+					"carrier_id",
 				}).AddRow(
 					firstPurchaseOrder.Id,
 					firstPurchaseOrder.OrderNumber,
@@ -70,6 +77,9 @@ func TestRepository_GetAll(t *testing.T) {
 					firstPurchaseOrder.BuyerId,
 					firstPurchaseOrder.ProductRecordId,
 					firstPurchaseOrder.OrderStatusId,
+					nil,
+					nil,
+					nil,
 				).AddRow(
 					secondPurchaseOrder.Id,
 					secondPurchaseOrder.OrderNumber,
@@ -78,6 +88,9 @@ func TestRepository_GetAll(t *testing.T) {
 					secondPurchaseOrder.BuyerId,
 					secondPurchaseOrder.ProductRecordId,
 					secondPurchaseOrder.OrderStatusId,
+					secondPurchaseOrder.AddressId,
+					secondPurchaseOrder.LocalityId,
+					secondPurchaseOrder.CarrierId,
 				)
 
 				mock.ExpectQuery(regexp.QuoteMeta(queryGetAll)).WillReturnRows(rows)
@@ -147,6 +160,7 @@ func TestRepository_Create(t *testing.T) {
 		{
 			name: "OK",
 			buildStubs: func() {
+				mock.ExpectBegin()
 				mock.
 					ExpectExec(regexp.QuoteMeta(queryCreate)).
 					WithArgs(
@@ -156,8 +170,12 @@ func TestRepository_Create(t *testing.T) {
 						firstPurchaseOrder.BuyerId,
 						firstPurchaseOrder.ProductRecordId,
 						firstPurchaseOrder.OrderStatusId,
+						nil,
+						nil,
+						nil,
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			purchaseOrder: firstPurchaseOrder,
 			checkResult: func(t *testing.T, result *domain.PurchaseOrder, err error) {
@@ -168,6 +186,7 @@ func TestRepository_Create(t *testing.T) {
 		{
 			name: "Fail",
 			buildStubs: func() {
+				mock.ExpectBegin()
 				mock.
 					ExpectExec(regexp.QuoteMeta(queryCreate)).
 					WithArgs(
@@ -177,8 +196,12 @@ func TestRepository_Create(t *testing.T) {
 						firstPurchaseOrder.BuyerId,
 						firstPurchaseOrder.ProductRecordId,
 						firstPurchaseOrder.OrderStatusId,
+						nil,
+						nil,
+						nil,
 					).
 					WillReturnError(someError)
+				mock.ExpectRollback()
 			},
 			purchaseOrder: firstPurchaseOrder,
 			checkResult: func(t *testing.T, result *domain.PurchaseOrder, err error) {
@@ -189,6 +212,7 @@ func TestRepository_Create(t *testing.T) {
 		{
 			name: "Last Id Error",
 			buildStubs: func() {
+				mock.ExpectBegin()
 				mock.
 					ExpectExec(regexp.QuoteMeta(queryCreate)).
 					WithArgs(
@@ -198,8 +222,12 @@ func TestRepository_Create(t *testing.T) {
 						firstPurchaseOrder.BuyerId,
 						firstPurchaseOrder.ProductRecordId,
 						firstPurchaseOrder.OrderStatusId,
+						nil,
+						nil,
+						nil,
 					).
 					WillReturnResult(driver.ResultNoRows)
+				mock.ExpectRollback()
 			},
 			purchaseOrder: firstPurchaseOrder,
 			checkResult: func(t *testing.T, result *domain.PurchaseOrder, err error) {
@@ -216,17 +244,129 @@ func TestRepository_Create(t *testing.T) {
 
 			repository := NewRepository(db)
 
-			result, err := repository.Create(
-				context.Background(),
-				testCase.purchaseOrder.OrderNumber,
-				testCase.purchaseOrder.OrderDate,
-				testCase.purchaseOrder.TrackingCode,
-				testCase.purchaseOrder.BuyerId,
-				testCase.purchaseOrder.ProductRecordId,
-				testCase.purchaseOrder.OrderStatusId,
-			)
+			result, err := repository.Create(context.Background(), testCase.purchaseOrder)
 
 			testCase.checkResult(t, result, err)
 		})
 	}
 }
+
+func TestRepository_GetById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repository := NewRepository(db)
+	columns := []string{
+		"id", "order_number", "order_date", "tracking_code", "buyer_id", "product_record_id", "order_status_id", "address_id", "locality_id", "carrier_id",
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WithArgs(2).WillReturnRows(sqlmock.NewRows(columns).AddRow(
+		secondPurchaseOrder.Id,
+		secondPurchaseOrder.OrderNumber,
+		secondPurchaseOrder.OrderDate,
+		secondPurchaseOrder.TrackingCode,
+		secondPurchaseOrder.BuyerId,
+		secondPurchaseOrder.ProductRecordId,
+		secondPurchaseOrder.OrderStatusId,
+		secondPurchaseOrder.AddressId,
+		secondPurchaseOrder.LocalityId,
+		secondPurchaseOrder.CarrierId,
+	))
+
+	result, err := repository.GetById(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, &secondPurchaseOrder, result)
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WithArgs(9).WillReturnRows(sqlmock.NewRows(columns))
+
+	_, err = repository.GetById(context.Background(), 9)
+	assert.EqualError(t, err, "purchase order 9 not found")
+}
+
+func TestRepository_UpdateCarrier(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryUpdateCarrier)).WithArgs(3, 4, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewRepository(db).UpdateCarrier(context.Background(), 2, 3, 4)
+	assert.NoError(t, err)
+}
+
+func TestRepository_Create_Lines(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	arg := firstPurchaseOrder
+	arg.Id, arg.ProductRecordId = 0, 0
+	arg.Lines = []domain.OrderLine{
+		{ProductId: 7, ProductRecordId: 5, Quantity: 3, UnitPrice: 10.99},
+		{ProductId: 8, ProductRecordId: 6, Quantity: 10, UnitPrice: 0.35},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(queryCreate)).WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryCreateLine)).WithArgs(4, 7, 5, 3, 10.99).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryCreateLine)).WithArgs(4, 8, 6, 10, 0.35).WillReturnError(someError)
+	mock.ExpectRollback()
+
+	result, err := NewRepository(db).Create(context.Background(), arg)
+	assert.ErrorIs(t, err, someError)
+	assert.Nil(t, result)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(queryCreate)).WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryCreateLine)).WithArgs(4, 7, 5, 3, 10.99).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryCreateLine)).WithArgs(4, 8, 6, 10, 0.35).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	result, err = NewRepository(db).Create(context.Background(), arg)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Id)
+	assert.Equal(t, []domain.OrderLine{
+		{Id: 1, PurchaseOrderId: 4, ProductId: 7, ProductRecordId: 5, Quantity: 3, UnitPrice: 10.99},
+		{Id: 2, PurchaseOrderId: 4, ProductId: 8, ProductRecordId: 6, Quantity: 10, UnitPrice: 0.35},
+	}, result.Lines)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetLines(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "purchase_order_id", "product_id", "product_record_id", "quantity", "unit_price"}
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetLines)).WithArgs(4).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(1, 4, 7, 5, 3, 10.99).
+		AddRow(2, 4, 8, 6, 10, 0.35))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetLines)).WithArgs(5).WillReturnError(someError)
+
+	repository := NewRepository(db)
+
+	result, err := repository.GetLines(context.Background(), 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.OrderLine{
+		{Id: 1, PurchaseOrderId: 4, ProductId: 7, ProductRecordId: 5, Quantity: 3, UnitPrice: 10.99},
+		{Id: 2, PurchaseOrderId: 4, ProductId: 8, ProductRecordId: 6, Quantity: 10, UnitPrice: 0.35},
+	}, result)
+
+	_, err = repository.GetLines(context.Background(), 5)
+	assert.Error(t, err)
+}
+
+func TestRepository_DeleteLine(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryDeleteLine)).WithArgs(4, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryDeleteLine)).WithArgs(4, 9).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repository := NewRepository(db)
+
+	assert.NoError(t, repository.DeleteLine(context.Background(), 4, 1))
+	assert.ErrorIs(t, repository.DeleteLine(context.Background(), 4, 9), domain.ErrLineNotFound)
+}
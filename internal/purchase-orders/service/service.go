@@ -2,33 +2,268 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+
+	buyersDomain "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	carriersDomain "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain"
+	orderStatusDomain "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain"
+	productRecordDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/domain"
+	sequencesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/money"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 )
 
 type service struct {
 	repository domain.Repository
+	sequences  sequencesDomain.Service
+	carriers   carriersDomain.CarrierRepository
+	buyers     buyersDomain.Repository
+	statuses   orderStatusDomain.Repository
+	records    productRecordDomain.ProductRecordRepository
 }
 
-func NewService(r domain.Repository) domain.Service {
+func NewService(r domain.Repository, sequences sequencesDomain.Service, carriers carriersDomain.CarrierRepository, buyers buyersDomain.Repository, statuses orderStatusDomain.Repository, records productRecordDomain.ProductRecordRepository) domain.Service {
 	return &service{
 		repository: r,
+		sequences:  sequences,
+		carriers:   carriers,
+		buyers:     buyers,
+		statuses:   statuses,
+		records:    records,
 	}
 }
 
-func (s service) Create(ctx context.Context, orderNumber string, orderDate string, trackingCode string, buyerId int, productRecordId int, orderStatusId int) (*domain.PurchaseOrder, error) {
-	po, err := s.repository.GetAll(ctx)
+func (s service) Stream(ctx context.Context, fn func(po domain.PurchaseOrder) error) error {
+	return s.repository.Stream(ctx, fn)
+}
+
+func (s service) Create(ctx context.Context, arg domain.PurchaseOrder) (*domain.PurchaseOrder, error) {
+	status, err := s.statuses.GetById(ctx, arg.OrderStatusId)
 	if err != nil {
 		return nil, err
 	}
-	for p := range po {
-		if po[p].OrderNumber == orderNumber {
-			return nil, fmt.Errorf("order number already exists")
+	if !status.IsInitial {
+		return nil, domain.ErrNotInitialStatus
+	}
+	if _, err := s.buyers.GetById(ctx, arg.BuyerId); err != nil {
+		return nil, err
+	}
+	// Numbers sent by clients may not take the shape of the generated ones,
+	// so a number generated later never collides with one a client chose.
+	if arg.OrderNumber == "" {
+		number, err := s.sequences.Next(ctx, sequencesDomain.PurchaseOrderNumber)
+		if err != nil {
+			return nil, err
+		}
+		arg.OrderNumber = number
+	} else {
+		if s.sequences.Reserved(sequencesDomain.PurchaseOrderNumber, arg.OrderNumber) {
+			return nil, fmt.Errorf("order number %s: %w", arg.OrderNumber, sequencesDomain.ErrReserved)
+		}
+		po, err := s.repository.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for p := range po {
+			if po[p].OrderNumber == arg.OrderNumber {
+				return nil, domain.ErrOrderNumberExists
+			}
+		}
+	}
+	if arg.TrackingCode == "" {
+		code, err := s.sequences.Next(ctx, sequencesDomain.PurchaseOrderTracking)
+		if err != nil {
+			return nil, err
+		}
+		arg.TrackingCode = code
+	} else if s.sequences.Reserved(sequencesDomain.PurchaseOrderTracking, arg.TrackingCode) {
+		return nil, fmt.Errorf("tracking code %s: %w", arg.TrackingCode, sequencesDomain.ErrReserved)
+	}
+	if err := s.selectAddress(ctx, &arg); err != nil {
+		return nil, err
+	}
+	if arg.LocalityId != 0 {
+		carrierId, err := s.selectCarrier(ctx, arg.LocalityId)
+		if err != nil {
+			return nil, err
+		}
+		arg.CarrierId = carrierId
+	}
+	for l := range arg.Lines {
+		if err := s.priceLine(ctx, &arg.Lines[l]); err != nil {
+			return nil, err
 		}
 	}
-	por, err := s.repository.Create(ctx, orderNumber, orderDate, trackingCode, buyerId, productRecordId, orderStatusId)
+	por, err := s.repository.Create(ctx, arg)
 	if err != nil {
 		return nil, err
 	}
+	total(por)
 	return por, nil
 }
+
+// GetById returns the order along with its lines and totals.
+func (s service) GetById(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	po, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := s.repository.GetLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	po.Lines = lines
+	total(po)
+	return po, nil
+}
+
+// AddLine adds a line to the order, priced at the current sale price of the
+// product, and returns the updated order.
+func (s service) AddLine(ctx context.Context, id int, arg domain.OrderLine) (*domain.PurchaseOrder, error) {
+	if err := s.editable(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.priceLine(ctx, &arg); err != nil {
+		return nil, err
+	}
+	arg.PurchaseOrderId = id
+	if _, err := s.repository.CreateLine(ctx, arg); err != nil {
+		return nil, err
+	}
+	return s.GetById(ctx, id)
+}
+
+// RemoveLine removes a line from the order, unless it is the only one left.
+func (s service) RemoveLine(ctx context.Context, id, lineId int) error {
+	if err := s.editable(ctx, id); err != nil {
+		return err
+	}
+	lines, err := s.repository.GetLines(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 1 && lines[0].Id == lineId {
+		return domain.ErrLastLine
+	}
+	return s.repository.DeleteLine(ctx, id, lineId)
+}
+
+// editable checks that the lines of the order may still change, which they
+// may until it reaches a terminal status.
+func (s service) editable(ctx context.Context, id int) error {
+	po, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.open(ctx, po)
+}
+
+// open checks that po has not reached a terminal status.
+func (s service) open(ctx context.Context, po *domain.PurchaseOrder) error {
+	status, err := s.statuses.GetById(ctx, po.OrderStatusId)
+	if err != nil {
+		return err
+	}
+	if status.IsTerminal {
+		return domain.ErrNotEditable
+	}
+	return nil
+}
+
+// priceLine takes the unit price of the line from the record of its product
+// in effect now, ignoring the ones scheduled for later.
+func (s service) priceLine(ctx context.Context, line *domain.OrderLine) error {
+	record, err := s.records.GetCurrent(ctx, line.ProductId)
+	if errors.Is(err, productRecordDomain.ErrNoCurrentRecord) {
+		return fmt.Errorf("%w: product %d", domain.ErrNoPrice, line.ProductId)
+	}
+	if err != nil {
+		return err
+	}
+	line.ProductRecordId, line.UnitPrice = record.Id, record.SalePrice
+	return nil
+}
+
+// total sets the totals of the lines of po and of po itself, rounded to
+// cents.
+func total(po *domain.PurchaseOrder) {
+	po.Total = 0
+	for l := range po.Lines {
+		po.Lines[l].Total = money.Round(float64(po.Lines[l].Quantity) * po.Lines[l].UnitPrice)
+		po.Total += po.Lines[l].Total
+	}
+	po.Total = money.Round(po.Total)
+}
+
+// AssignCarrier sets the delivery locality of the order and hands it to a
+// carrier serving it. Orders in a terminal status keep their carrier.
+func (s service) AssignCarrier(ctx context.Context, id, localityId int) (*domain.PurchaseOrder, error) {
+	po, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.open(ctx, po); err != nil {
+		return nil, err
+	}
+	carrierId, err := s.selectCarrier(ctx, localityId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.UpdateCarrier(ctx, id, localityId, carrierId); err != nil {
+		return nil, err
+	}
+	po.LocalityId, po.CarrierId = localityId, carrierId
+	return po, nil
+}
+
+// selectAddress takes the delivery locality of the order from the chosen
+// address of the buyer, or from the default one when the order names neither
+// an address nor a locality. Without a default address the order has nowhere
+// to be delivered, and is refused.
+func (s service) selectAddress(ctx context.Context, arg *domain.PurchaseOrder) error {
+	if arg.AddressId != 0 {
+		address, err := s.buyers.GetAddress(ctx, arg.BuyerId, arg.AddressId)
+		if err != nil {
+			return err
+		}
+		arg.LocalityId = address.LocalityId
+		return nil
+	}
+	if arg.LocalityId != 0 {
+		return nil
+	}
+
+	addresses, err := s.buyers.GetAddresses(ctx, arg.BuyerId)
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		if address.IsDefault {
+			arg.AddressId, arg.LocalityId = address.Id, address.LocalityId
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: buyer %d", domain.ErrNoAddress, arg.BuyerId)
+}
+
+// selectCarrier picks the carrier of the locality with the fewest open orders
+// assigned, the oldest one on ties. Delivered and cancelled orders weigh
+// nothing on a carrier.
+func (s service) selectCarrier(ctx context.Context, localityId int) (int, error) {
+	workload, err := s.carriers.GetWorkload(ctx, localityId, period.Range{})
+	if err != nil {
+		return 0, err
+	}
+	if len(workload) == 0 {
+		return 0, fmt.Errorf("%w %d", domain.ErrNoCarrier, localityId)
+	}
+	selected := workload[0]
+	for _, carrier := range workload[1:] {
+		if carrier.OpenOrdersCount < selected.OpenOrdersCount {
+			selected = carrier
+		}
+	}
+	return selected.Id, nil
+}
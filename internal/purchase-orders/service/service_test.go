@@ -5,8 +5,20 @@ import (
 	"errors"
 	"testing"
 
+	buyersDomain "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain"
+	mock_buyers "github.com/douglmendes/mercado-fresco-round-go/internal/buyers/domain/mock"
+	carriersDomain "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain"
+	mock_carriers "github.com/douglmendes/mercado-fresco-round-go/internal/carriers/domain/mock"
+	orderStatusDomain "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain"
+	mock_order_status "github.com/douglmendes/mercado-fresco-round-go/internal/order_status/domain/mock"
+	productRecordDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain"
+	mock_product_record "github.com/douglmendes/mercado-fresco-round-go/internal/product_record/domain/mock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/domain"
 	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/purchase-orders/domain/mock"
+	sequencesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	mock_sequences "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,7 +31,7 @@ var (
 	purchaseOrder = domain.PurchaseOrder{
 		Id:              1,
 		OrderNumber:     "xpto",
-		OrderDate:       "2020-02-02",
+		OrderDate:       date.New(2020, 2, 2),
 		TrackingCode:    "ew23143543jn",
 		BuyerId:         1,
 		ProductRecordId: 1,
@@ -29,10 +41,97 @@ var (
 	purchaseOrders     = []domain.PurchaseOrder{purchaseOrder}
 	noPurchaseOrders   = []domain.PurchaseOrder{}
 	someError          = errors.New("some error")
+	defaultAddresses   = []buyersDomain.Address{{Id: 1, BuyerId: 1, LocalityId: 1, IsDefault: true}}
+	defaultWorkload    = []carriersDomain.CarrierWorkload{{Carrier: carriersDomain.Carrier{Id: 1}}}
 )
 
+// delivered returns po as created for a buyer with the default address of
+// callMockWithCarriers.
+func delivered(po domain.PurchaseOrder) domain.PurchaseOrder {
+	po.AddressId, po.LocalityId, po.CarrierId = 1, 1, 1
+	return po
+}
+
 func callMock(t *testing.T) (
 	*mock_domain.MockRepository,
+	*mock_sequences.MockService,
+	domain.Service,
+	context.Context,
+) {
+	repository, sequences, _, service, ctx := callMockWithCarriers(t)
+
+	return repository, sequences, service, ctx
+}
+
+func callMockWithCarriers(t *testing.T) (
+	*mock_domain.MockRepository,
+	*mock_sequences.MockService,
+	*mock_carriers.MockCarrierRepository,
+	domain.Service,
+	context.Context,
+) {
+	repository, sequences, carriers, buyers, service, ctx := callMockWithBuyers(t)
+
+	// Buyers deliver to their default address in locality 1, served by
+	// carrier 1, unless the order says otherwise.
+	buyers.EXPECT().GetAddresses(ctx, gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, buyerId int) ([]buyersDomain.Address, error) {
+			return []buyersDomain.Address{{Id: 1, BuyerId: buyerId, LocalityId: 1, IsDefault: true}}, nil
+		},
+	)
+	carriers.EXPECT().GetWorkload(ctx, 1, period.Range{}).AnyTimes().Return(defaultWorkload, nil)
+
+	return repository, sequences, carriers, service, ctx
+}
+
+func callMockWithBuyers(t *testing.T) (
+	*mock_domain.MockRepository,
+	*mock_sequences.MockService,
+	*mock_carriers.MockCarrierRepository,
+	*mock_buyers.MockRepository,
+	domain.Service,
+	context.Context,
+) {
+	repository, sequences, carriers, buyers, statuses, service, ctx := callMockWithStatuses(t)
+
+	// Orders are placed by a buyer that exists unless a test says otherwise.
+	buyers.EXPECT().GetById(ctx, gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id int) (*buyersDomain.Buyer, error) {
+			return &buyersDomain.Buyer{Id: id}, nil
+		},
+	)
+
+	// Orders are created in an initial status unless a test says otherwise.
+	statuses.EXPECT().GetById(ctx, gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id int) (orderStatusDomain.OrderStatus, error) {
+			return orderStatusDomain.OrderStatus{Id: id, Description: "pending", IsInitial: true}, nil
+		},
+	)
+
+	return repository, sequences, carriers, buyers, service, ctx
+}
+
+func callMockWithStatuses(t *testing.T) (
+	*mock_domain.MockRepository,
+	*mock_sequences.MockService,
+	*mock_carriers.MockCarrierRepository,
+	*mock_buyers.MockRepository,
+	*mock_order_status.MockRepository,
+	domain.Service,
+	context.Context,
+) {
+	repository, sequences, carriers, buyers, statuses, _, service, ctx := callMockWithRecords(t)
+
+	return repository, sequences, carriers, buyers, statuses, service, ctx
+}
+
+func callMockWithRecords(t *testing.T) (
+	*mock_domain.MockRepository,
+	*mock_sequences.MockService,
+	*mock_carriers.MockCarrierRepository,
+	*mock_buyers.MockRepository,
+	*mock_order_status.MockRepository,
+	*mock_product_record.MockProductRecordRepository,
 	domain.Service,
 	context.Context,
 ) {
@@ -40,9 +139,21 @@ func callMock(t *testing.T) (
 	defer ctrl.Finish()
 
 	repository := mock_domain.NewMockRepository(ctrl)
-	service := NewService(repository)
+	sequences := mock_sequences.NewMockService(ctrl)
+	carriers := mock_carriers.NewMockCarrierRepository(ctrl)
+	buyers := mock_buyers.NewMockRepository(ctrl)
+	statuses := mock_order_status.NewMockRepository(ctrl)
+	records := mock_product_record.NewMockProductRecordRepository(ctrl)
+	service := NewService(repository, sequences, carriers, buyers, statuses, records)
 
-	return repository, service, context.Background()
+	// Identifiers are reserved as the default formats reserve them.
+	sequences.EXPECT().Reserved(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(name, value string) bool {
+			return sequencesDomain.DefaultFormats[name].Matches(value)
+		},
+	)
+
+	return repository, sequences, carriers, buyers, statuses, records, service, context.Background()
 }
 
 func TestCreate(t *testing.T) {
@@ -63,15 +174,7 @@ func TestCreate(t *testing.T) {
 
 				repository.
 					EXPECT().
-					Create(
-						ctx,
-						purchaseOrder.OrderNumber,
-						purchaseOrder.OrderDate,
-						purchaseOrder.TrackingCode,
-						purchaseOrder.BuyerId,
-						purchaseOrder.ProductRecordId,
-						purchaseOrder.OrderStatusId,
-					).
+					Create(ctx, delivered(purchaseOrder)).
 					Times(ONCE).
 					Return(&purchaseOrder, nil)
 			},
@@ -93,15 +196,7 @@ func TestCreate(t *testing.T) {
 
 				repository.
 					EXPECT().
-					Create(
-						ctx,
-						purchaseOrder.OrderNumber,
-						purchaseOrder.OrderDate,
-						purchaseOrder.TrackingCode,
-						purchaseOrder.BuyerId,
-						purchaseOrder.ProductRecordId,
-						purchaseOrder.OrderStatusId,
-					).
+					Create(ctx, delivered(purchaseOrder)).
 					Times(ONCE).
 					Return(emptyPurchaseOrder, someError)
 			},
@@ -140,7 +235,7 @@ func TestCreate(t *testing.T) {
 			purchaseOrder: purchaseOrder,
 			checkResult: func(t *testing.T, result *domain.PurchaseOrder, err error) {
 				assert.Error(t, err)
-				assert.Equal(t, errors.New("order number already exists"), err)
+				assert.ErrorIs(t, err, domain.ErrOrderNumberExists)
 
 				assert.Equal(t, emptyPurchaseOrder, result)
 			},
@@ -149,21 +244,500 @@ func TestCreate(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			repository, service, ctx := callMock(t)
+			repository, _, service, ctx := callMock(t)
 
 			testCase.buildStubs(repository, ctx)
 
-			result, err := service.Create(
-				context.Background(),
-				testCase.purchaseOrder.OrderNumber,
-				testCase.purchaseOrder.OrderDate,
-				testCase.purchaseOrder.TrackingCode,
-				testCase.purchaseOrder.BuyerId,
-				testCase.purchaseOrder.ProductRecordId,
-				testCase.purchaseOrder.OrderStatusId,
-			)
+			result, err := service.Create(context.Background(), testCase.purchaseOrder)
 
 			testCase.checkResult(t, result, err)
 		})
 	}
 }
+
+func TestCreate_GeneratedIdentifiers(t *testing.T) {
+	t.Run("should generate the identifiers the client omitted", func(t *testing.T) {
+		repository, sequences, service, ctx := callMock(t)
+
+		sequences.EXPECT().Next(ctx, sequencesDomain.PurchaseOrderNumber).Return("PO-20220801-0000013", nil)
+		sequences.EXPECT().Next(ctx, sequencesDomain.PurchaseOrderTracking).Return("TRK220801000000019", nil)
+		repository.EXPECT().GetAll(gomock.Any()).Times(0)
+		generated := delivered(purchaseOrder)
+		generated.OrderNumber, generated.TrackingCode = "PO-20220801-0000013", "TRK220801000000019"
+		repository.
+			EXPECT().
+			Create(ctx, generated).
+			Times(ONCE).
+			Return(&purchaseOrder, nil)
+
+		arg := purchaseOrder
+		arg.OrderNumber, arg.TrackingCode = "", ""
+		_, err := service.Create(ctx, arg)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail when the number cannot be generated", func(t *testing.T) {
+		_, sequences, service, ctx := callMock(t)
+
+		sequences.EXPECT().Next(ctx, sequencesDomain.PurchaseOrderNumber).Return("", someError)
+
+		arg := purchaseOrder
+		arg.OrderNumber, arg.TrackingCode = "", ""
+		result, err := service.Create(ctx, arg)
+		assert.ErrorIs(t, err, someError)
+		assert.Equal(t, emptyPurchaseOrder, result)
+	})
+}
+
+func TestCreate_ReservedIdentifiers(t *testing.T) {
+	t.Run("should reject a number in the generated format", func(t *testing.T) {
+		repository, _, service, ctx := callMock(t)
+
+		repository.EXPECT().GetAll(gomock.Any()).Times(0)
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		arg := purchaseOrder
+		arg.OrderNumber = "PO-20220801-0000425"
+		result, err := service.Create(ctx, arg)
+		assert.ErrorIs(t, err, sequencesDomain.ErrReserved)
+		assert.Equal(t, emptyPurchaseOrder, result)
+	})
+
+	t.Run("should reject a tracking code in the generated format", func(t *testing.T) {
+		repository, _, service, ctx := callMock(t)
+
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		arg := purchaseOrder
+		arg.TrackingCode = "TRK220801000000427"
+		_, err := service.Create(ctx, arg)
+		assert.ErrorIs(t, err, sequencesDomain.ErrReserved)
+	})
+}
+
+func TestCreate_AssignsCarrier(t *testing.T) {
+	arg := purchaseOrder
+	arg.LocalityId = 3
+
+	t.Run("should pick the carrier of the locality with the fewest open orders", func(t *testing.T) {
+		repository, _, carriers, service, ctx := callMockWithCarriers(t)
+
+		// Carrier 2 delivered the most orders, but has the fewest still open.
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		carriers.EXPECT().GetWorkload(ctx, 3, period.Range{}).Return([]carriersDomain.CarrierWorkload{
+			{Carrier: carriersDomain.Carrier{Id: 1}, OrdersCount: 4, OpenOrdersCount: 4},
+			{Carrier: carriersDomain.Carrier{Id: 2}, OrdersCount: 9, OpenOrdersCount: 1},
+			{Carrier: carriersDomain.Carrier{Id: 5}, OrdersCount: 1, OpenOrdersCount: 1},
+		}, nil)
+
+		assigned := arg
+		assigned.CarrierId = 2
+		repository.EXPECT().Create(ctx, assigned).Times(ONCE).Return(&assigned, nil)
+
+		result, err := service.Create(ctx, arg)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.CarrierId)
+	})
+
+	t.Run("should fail when no carrier serves the locality", func(t *testing.T) {
+		repository, _, carriers, service, ctx := callMockWithCarriers(t)
+
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		carriers.EXPECT().GetWorkload(ctx, 3, period.Range{}).Return([]carriersDomain.CarrierWorkload{}, nil)
+
+		_, err := service.Create(ctx, arg)
+		assert.ErrorIs(t, err, domain.ErrNoCarrier)
+		assert.EqualError(t, err, "no carrier serves locality 3")
+	})
+}
+
+func TestCreate_SelectsAddress(t *testing.T) {
+	workload := []carriersDomain.CarrierWorkload{{Carrier: carriersDomain.Carrier{Id: 2}}}
+
+	t.Run("should deliver to the chosen address", func(t *testing.T) {
+		repository, _, carriers, buyers, service, ctx := callMockWithBuyers(t)
+
+		arg := purchaseOrder
+		arg.AddressId = 6
+
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		buyers.EXPECT().GetAddress(ctx, 1, 6).Return(&buyersDomain.Address{Id: 6, BuyerId: 1, LocalityId: 3}, nil)
+		carriers.EXPECT().GetWorkload(ctx, 3, period.Range{}).Return(workload, nil)
+
+		created := arg
+		created.LocalityId, created.CarrierId = 3, 2
+		repository.EXPECT().Create(ctx, created).Times(ONCE).Return(&created, nil)
+
+		result, err := service.Create(ctx, arg)
+		assert.NoError(t, err)
+		assert.Equal(t, &created, result)
+	})
+
+	t.Run("should deliver to the default address", func(t *testing.T) {
+		repository, _, carriers, buyers, service, ctx := callMockWithBuyers(t)
+
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		buyers.EXPECT().GetAddresses(ctx, 1).Return([]buyersDomain.Address{
+			{Id: 4, BuyerId: 1, LocalityId: 8},
+			{Id: 5, BuyerId: 1, LocalityId: 3, IsDefault: true},
+		}, nil)
+		carriers.EXPECT().GetWorkload(ctx, 3, period.Range{}).Return(workload, nil)
+
+		created := purchaseOrder
+		created.AddressId, created.LocalityId, created.CarrierId = 5, 3, 2
+		repository.EXPECT().Create(ctx, created).Times(ONCE).Return(&created, nil)
+
+		result, err := service.Create(ctx, purchaseOrder)
+		assert.NoError(t, err)
+		assert.Equal(t, &created, result)
+	})
+
+	t.Run("should keep the locality sent", func(t *testing.T) {
+		repository, _, carriers, _, service, ctx := callMockWithBuyers(t)
+
+		arg := purchaseOrder
+		arg.LocalityId = 3
+
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		carriers.EXPECT().GetWorkload(ctx, 3, period.Range{}).Return(workload, nil)
+
+		created := arg
+		created.CarrierId = 2
+		repository.EXPECT().Create(ctx, created).Times(ONCE).Return(&created, nil)
+
+		_, err := service.Create(ctx, arg)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail without a default address", func(t *testing.T) {
+		repository, _, _, buyers, service, ctx := callMockWithBuyers(t)
+
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		buyers.EXPECT().GetAddresses(ctx, 1).Return([]buyersDomain.Address{{Id: 4, BuyerId: 1, LocalityId: 8}}, nil)
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		result, err := service.Create(ctx, purchaseOrder)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNoAddress)
+		assert.EqualError(t, err, "buyer has no default address: buyer 1")
+	})
+
+	t.Run("should fail when the address is not the buyer's", func(t *testing.T) {
+		repository, _, _, buyers, service, ctx := callMockWithBuyers(t)
+
+		arg := purchaseOrder
+		arg.AddressId = 6
+
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		buyers.EXPECT().GetAddress(ctx, 1, 6).Return(nil, &buyersDomain.ErrorAddressNotFound{BuyerId: 1, Id: 6})
+
+		_, err := service.Create(ctx, arg)
+		assert.EqualError(t, err, "address 6 of buyer 1 not found")
+	})
+}
+
+func TestCreate_OrderStatus(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		repository, _, _, _, statuses, service, ctx := callMockWithStatuses(t)
+
+		statuses.EXPECT().GetById(ctx, 9).Times(ONCE).Return(orderStatusDomain.OrderStatus{}, &orderStatusDomain.ErrorNotFound{Id: 9})
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		arg := purchaseOrder
+		arg.OrderStatusId = 9
+		result, err := service.Create(ctx, arg)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "order status 9 not found")
+	})
+
+	t.Run("not initial", func(t *testing.T) {
+		repository, _, _, _, statuses, service, ctx := callMockWithStatuses(t)
+
+		statuses.EXPECT().GetById(ctx, 3).Times(ONCE).Return(orderStatusDomain.OrderStatus{Id: 3, Description: "delivered", IsTerminal: true}, nil)
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		arg := purchaseOrder
+		arg.OrderStatusId = 3
+		result, err := service.Create(ctx, arg)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNotInitialStatus)
+	})
+}
+
+func TestCreate_Buyer(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		repository, _, _, buyers, statuses, service, ctx := callMockWithStatuses(t)
+
+		statuses.EXPECT().GetById(ctx, 1).Return(orderStatusDomain.OrderStatus{Id: 1, IsInitial: true}, nil)
+		buyers.EXPECT().GetById(ctx, 9).Times(ONCE).Return(nil, &buyersDomain.ErrorNotFound{Id: 9})
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		arg := purchaseOrder
+		arg.BuyerId = 9
+		result, err := service.Create(ctx, arg)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "Buyer 9 not found")
+	})
+}
+
+func TestCreate_Lines(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		repository, _, carriers, buyers, statuses, records, service, ctx := callMockWithRecords(t)
+
+		statuses.EXPECT().GetById(ctx, 1).Return(orderStatusDomain.OrderStatus{Id: 1, IsInitial: true}, nil)
+		buyers.EXPECT().GetById(ctx, 1).Return(&buyersDomain.Buyer{Id: 1}, nil)
+		buyers.EXPECT().GetAddresses(ctx, 1).Return(defaultAddresses, nil)
+		carriers.EXPECT().GetWorkload(ctx, 1, period.Range{}).Return(defaultWorkload, nil)
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		records.EXPECT().GetCurrent(ctx, 7).Return(productRecordDomain.ProductRecord{Id: 5, SalePrice: 10.99, ProductId: 7}, nil)
+		records.EXPECT().GetCurrent(ctx, 8).Return(productRecordDomain.ProductRecord{Id: 6, SalePrice: 0.35, ProductId: 8}, nil)
+
+		arg := purchaseOrder
+		arg.Id, arg.ProductRecordId = 0, 0
+		arg.Lines = []domain.OrderLine{{ProductId: 7, Quantity: 3}, {ProductId: 8, Quantity: 10}}
+
+		priced := delivered(arg)
+		priced.Lines = []domain.OrderLine{
+			{ProductId: 7, ProductRecordId: 5, Quantity: 3, UnitPrice: 10.99},
+			{ProductId: 8, ProductRecordId: 6, Quantity: 10, UnitPrice: 0.35},
+		}
+		repository.EXPECT().Create(ctx, priced).DoAndReturn(
+			func(_ context.Context, po domain.PurchaseOrder) (*domain.PurchaseOrder, error) {
+				po.Id = 1
+				for l := range po.Lines {
+					po.Lines[l].Id, po.Lines[l].PurchaseOrderId = l+1, 1
+				}
+				return &po, nil
+			},
+		)
+
+		result, err := service.Create(ctx, arg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 32.97, result.Lines[0].Total)
+		assert.Equal(t, 3.5, result.Lines[1].Total)
+		assert.Equal(t, 36.47, result.Total)
+	})
+
+	t.Run("No price", func(t *testing.T) {
+		repository, _, carriers, buyers, statuses, records, service, ctx := callMockWithRecords(t)
+
+		statuses.EXPECT().GetById(ctx, 1).Return(orderStatusDomain.OrderStatus{Id: 1, IsInitial: true}, nil)
+		buyers.EXPECT().GetById(ctx, 1).Return(&buyersDomain.Buyer{Id: 1}, nil)
+		buyers.EXPECT().GetAddresses(ctx, 1).Return(defaultAddresses, nil)
+		carriers.EXPECT().GetWorkload(ctx, 1, period.Range{}).Return(defaultWorkload, nil)
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		records.EXPECT().GetCurrent(ctx, 7).Return(productRecordDomain.ProductRecord{}, productRecordDomain.ErrNoCurrentRecord)
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		arg := purchaseOrder
+		arg.Lines = []domain.OrderLine{{ProductId: 7, Quantity: 3}}
+		result, err := service.Create(ctx, arg)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNoPrice)
+	})
+
+	t.Run("Only future records", func(t *testing.T) {
+		repository, _, carriers, buyers, statuses, records, service, ctx := callMockWithRecords(t)
+
+		statuses.EXPECT().GetById(ctx, 1).Return(orderStatusDomain.OrderStatus{Id: 1, IsInitial: true}, nil)
+		buyers.EXPECT().GetById(ctx, 1).Return(&buyersDomain.Buyer{Id: 1}, nil)
+		buyers.EXPECT().GetAddresses(ctx, 1).Return(defaultAddresses, nil)
+		carriers.EXPECT().GetWorkload(ctx, 1, period.Range{}).Return(defaultWorkload, nil)
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		// The product has a single record, scheduled for next month, so the
+		// repository has none in effect.
+		records.EXPECT().GetCurrent(ctx, 7).Return(productRecordDomain.ProductRecord{}, productRecordDomain.ErrNoCurrentRecord)
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		arg := purchaseOrder
+		arg.Lines = []domain.OrderLine{{ProductId: 7, Quantity: 1}}
+		result, err := service.Create(ctx, arg)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNoPrice)
+		assert.NotErrorIs(t, err, productRecordDomain.ErrNoCurrentRecord)
+	})
+
+	t.Run("Records fail", func(t *testing.T) {
+		repository, _, carriers, buyers, statuses, records, service, ctx := callMockWithRecords(t)
+
+		statuses.EXPECT().GetById(ctx, 1).Return(orderStatusDomain.OrderStatus{Id: 1, IsInitial: true}, nil)
+		buyers.EXPECT().GetById(ctx, 1).Return(&buyersDomain.Buyer{Id: 1}, nil)
+		buyers.EXPECT().GetAddresses(ctx, 1).Return(defaultAddresses, nil)
+		carriers.EXPECT().GetWorkload(ctx, 1, period.Range{}).Return(defaultWorkload, nil)
+		repository.EXPECT().GetAll(ctx).Return(noPurchaseOrders, nil)
+		records.EXPECT().GetCurrent(ctx, 7).Return(productRecordDomain.ProductRecord{}, errors.New("connection refused"))
+		repository.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		arg := purchaseOrder
+		arg.Lines = []domain.OrderLine{{ProductId: 7, Quantity: 1}}
+		_, err := service.Create(ctx, arg)
+
+		assert.EqualError(t, err, "connection refused")
+	})
+}
+
+func TestGetById(t *testing.T) {
+	repository, _, service, ctx := callMock(t)
+
+	po := purchaseOrder
+	repository.EXPECT().GetById(ctx, 1).Return(&po, nil)
+	repository.EXPECT().GetLines(ctx, 1).Return([]domain.OrderLine{
+		{Id: 1, PurchaseOrderId: 1, ProductId: 7, ProductRecordId: 5, Quantity: 2, UnitPrice: 1.25},
+	}, nil)
+
+	result, err := service.GetById(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, result.Lines[0].Total)
+	assert.Equal(t, 2.5, result.Total)
+}
+
+func TestAddLine(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		repository, _, _, _, statuses, records, service, ctx := callMockWithRecords(t)
+
+		po := purchaseOrder
+		repository.EXPECT().GetById(ctx, 1).Times(2).Return(&po, nil)
+		statuses.EXPECT().GetById(ctx, 1).Return(orderStatusDomain.OrderStatus{Id: 1, IsInitial: true}, nil)
+		records.EXPECT().GetCurrent(ctx, 7).Return(productRecordDomain.ProductRecord{Id: 5, SalePrice: 4, ProductId: 7}, nil)
+
+		line := domain.OrderLine{PurchaseOrderId: 1, ProductId: 7, ProductRecordId: 5, Quantity: 2, UnitPrice: 4}
+		repository.EXPECT().CreateLine(ctx, line).Return(line, nil)
+		line.Id = 1
+		repository.EXPECT().GetLines(ctx, 1).Return([]domain.OrderLine{line}, nil)
+
+		result, err := service.AddLine(ctx, 1, domain.OrderLine{ProductId: 7, Quantity: 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Lines, 1)
+		assert.Equal(t, 8.0, result.Total)
+	})
+
+	t.Run("Terminal status", func(t *testing.T) {
+		repository, _, _, _, statuses, _, service, ctx := callMockWithRecords(t)
+
+		po := purchaseOrder
+		repository.EXPECT().GetById(ctx, 1).Return(&po, nil)
+		statuses.EXPECT().GetById(ctx, 1).Return(orderStatusDomain.OrderStatus{Id: 1, IsTerminal: true}, nil)
+		repository.EXPECT().CreateLine(gomock.Any(), gomock.Any()).Times(0)
+
+		result, err := service.AddLine(ctx, 1, domain.OrderLine{ProductId: 7, Quantity: 2})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNotEditable)
+	})
+
+	t.Run("Order not found", func(t *testing.T) {
+		repository, _, service, ctx := callMock(t)
+
+		repository.EXPECT().GetById(ctx, 9).Return(nil, someError)
+
+		result, err := service.AddLine(ctx, 9, domain.OrderLine{ProductId: 7, Quantity: 2})
+
+		assert.Nil(t, result)
+		assert.Error(t, err)
+	})
+}
+
+func TestRemoveLine(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		repository, _, service, ctx := callMock(t)
+
+		po := purchaseOrder
+		repository.EXPECT().GetById(ctx, 1).Return(&po, nil)
+		repository.EXPECT().GetLines(ctx, 1).Return([]domain.OrderLine{{Id: 2, PurchaseOrderId: 1}, {Id: 3, PurchaseOrderId: 1}}, nil)
+		repository.EXPECT().DeleteLine(ctx, 1, 3).Return(nil)
+
+		assert.NoError(t, service.RemoveLine(ctx, 1, 3))
+	})
+
+	t.Run("Last line", func(t *testing.T) {
+		repository, _, service, ctx := callMock(t)
+
+		po := purchaseOrder
+		repository.EXPECT().GetById(ctx, 1).Return(&po, nil)
+		repository.EXPECT().GetLines(ctx, 1).Return([]domain.OrderLine{{Id: 3, PurchaseOrderId: 1}}, nil)
+		repository.EXPECT().DeleteLine(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		assert.ErrorIs(t, service.RemoveLine(ctx, 1, 3), domain.ErrLastLine)
+	})
+
+	t.Run("Line of another order", func(t *testing.T) {
+		repository, _, service, ctx := callMock(t)
+
+		po := purchaseOrder
+		repository.EXPECT().GetById(ctx, 1).Return(&po, nil)
+		repository.EXPECT().GetLines(ctx, 1).Return([]domain.OrderLine{{Id: 3, PurchaseOrderId: 1}}, nil)
+		repository.EXPECT().DeleteLine(ctx, 1, 9).Return(domain.ErrLineNotFound)
+
+		assert.ErrorIs(t, service.RemoveLine(ctx, 1, 9), domain.ErrLineNotFound)
+	})
+
+	t.Run("Terminal status", func(t *testing.T) {
+		repository, _, _, _, statuses, service, ctx := callMockWithStatuses(t)
+
+		po := purchaseOrder
+		repository.EXPECT().GetById(ctx, 1).Return(&po, nil)
+		statuses.EXPECT().GetById(ctx, 1).Return(orderStatusDomain.OrderStatus{Id: 1, IsTerminal: true}, nil)
+		repository.EXPECT().DeleteLine(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		assert.ErrorIs(t, service.RemoveLine(ctx, 1, 3), domain.ErrNotEditable)
+	})
+}
+
+func TestAssignCarrier(t *testing.T) {
+	t.Run("should store the locality and the carrier", func(t *testing.T) {
+		repository, _, carriers, service, ctx := callMockWithCarriers(t)
+
+		found := purchaseOrder
+		repository.EXPECT().GetById(ctx, 1).Return(&found, nil)
+		carriers.EXPECT().GetWorkload(ctx, 4, period.Range{}).
+			Return([]carriersDomain.CarrierWorkload{{Carrier: carriersDomain.Carrier{Id: 7}}}, nil)
+		repository.EXPECT().UpdateCarrier(ctx, 1, 4, 7).Times(ONCE).Return(nil)
+
+		result, err := service.AssignCarrier(ctx, 1, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result.LocalityId)
+		assert.Equal(t, 7, result.CarrierId)
+	})
+
+	t.Run("should fail when the order does not exist", func(t *testing.T) {
+		repository, _, _, service, ctx := callMockWithCarriers(t)
+
+		repository.EXPECT().GetById(ctx, 1).Return(nil, someError)
+
+		_, err := service.AssignCarrier(ctx, 1, 4)
+		assert.ErrorIs(t, err, someError)
+	})
+
+	t.Run("should reject an order in a terminal status", func(t *testing.T) {
+		repository, _, _, _, statuses, service, ctx := callMockWithStatuses(t)
+
+		delivered := purchaseOrder
+		delivered.OrderStatusId = 4
+		repository.EXPECT().GetById(ctx, 1).Return(&delivered, nil)
+		statuses.EXPECT().GetById(ctx, 4).Return(orderStatusDomain.OrderStatus{Id: 4, IsTerminal: true}, nil)
+		repository.EXPECT().UpdateCarrier(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		result, err := service.AssignCarrier(ctx, 1, 4)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrNotEditable)
+	})
+
+	t.Run("should fail when the workload cannot be read", func(t *testing.T) {
+		repository, _, carriers, service, ctx := callMockWithCarriers(t)
+
+		repository.EXPECT().GetById(ctx, 1).Return(&purchaseOrder, nil)
+		carriers.EXPECT().GetWorkload(ctx, 4, period.Range{}).Return(nil, someError)
+
+		_, err := service.AssignCarrier(ctx, 1, 4)
+		assert.ErrorIs(t, err, someError)
+	})
+}
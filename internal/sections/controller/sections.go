@@ -5,8 +5,14 @@ import (
 	"net/http"
 	"strconv"
 
+	productTypesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/patch"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,12 +26,19 @@ type SectionsController struct {
 // @Tags         sections
 // @Accept       json
 // @Produce      json
+// @Param        include_deleted  query  bool  false  "Include soft deleted sections"
 // @Success      200  {array}  sections.Section
 // @Success      204  "Empty database"
+// @Failure      400  {object}  response.Response
 // @Failure      500  {object}  response.Response
 // @Router       /api/v1/sections [get]
 func (s *SectionsController) GetAll(c *gin.Context) {
-	sections, err := s.service.GetAll()
+	if err := softdelete.FromQuery(c); err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+		return
+	}
+
+	sections, err := s.service.GetAll(c)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.DecodeError(err.Error()))
 		return
@@ -45,7 +58,8 @@ func (s *SectionsController) GetAll(c *gin.Context) {
 // @Tags         sections
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "Section id"
+// @Param        id               path   int   true   "Section id"
+// @Param        include_deleted  query  bool  false  "Include soft deleted sections"
 // @Success      200  {object}  sections.Section
 // @Failure      400  {object}  response.Response
 // @Failure      404  {object}  response.Response
@@ -57,12 +71,18 @@ func (s *SectionsController) GetById(c *gin.Context) {
 		return
 	}
 
-	section, err := s.service.GetById(id)
+	if err := softdelete.FromQuery(c); err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+		return
+	}
+
+	section, err := s.service.GetById(c, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, response.DecodeError(err.Error()))
 		return
 	}
 
+	etag.Set(c, section.Version)
 	c.JSON(http.StatusOK, response.NewResponse(section))
 }
 
@@ -81,36 +101,48 @@ func (s *SectionsController) Create(c *gin.Context) {
 	var req sectionsRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, response.DecodeError(err.Error()))
+		c.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 		return
 	}
 
 	section, err := s.service.Create(
+		c,
 		req.SectionNumber, req.CurrentTemperature, req.MinimumTemperature,
 		req.CurrentCapacity, req.MinimumCapacity, req.MaximumCapacity,
 		req.WarehouseId, req.ProductTypeId,
 	)
 	if err != nil {
-		c.JSON(http.StatusConflict, response.DecodeError(err.Error()))
+		status := http.StatusConflict
+
+		errTemperature := &domain.ErrorTemperature{}
+		if errors.As(err, &errTemperature) {
+			status = http.StatusUnprocessableEntity
+		}
+
+		c.JSON(status, response.DecodeError(err.Error()))
 		return
 	}
 
+	etag.Set(c, section.Version)
 	c.JSON(http.StatusCreated, response.NewResponse(section))
 }
 
 // UpdateSection godoc
 // @Summary      Update a section
-// @Description  Update a section in the system, selecting by id
+// @Description  Update a section in the system, selecting by id. The body is a JSON merge patch (RFC 7396)
 // @Tags         sections
 // @Accept       json
 // @Produce      json
-// @Param        id       path      int              true   "Section id"
-// @Param        section  body      sectionsRequest  false  "Section to be updated (all fields are optional)"
+// @Param        id       path      int                    true   "Section id"
+// @Param        If-Match header    string                 true   "ETag of the section being updated"
+// @Param        section  body      updateSectionsRequest  false  "Section to be updated (all fields are optional)"
 // @Success      200      {object}  sections.Section
 // @Failure      400      {object}  response.Response
 // @Failure      404      {object}  response.Response
 // @Failure      409      {object}  response.Response
+// @Failure      412      {object}  response.Response
 // @Failure      422      {object}  response.Response
+// @Failure      428      {object}  response.Response
 // @Failure      500      {object}  response.Response
 // @Router       /api/v1/sections/{id} [patch]
 func (s *SectionsController) Update(c *gin.Context) {
@@ -120,13 +152,18 @@ func (s *SectionsController) Update(c *gin.Context) {
 		return
 	}
 
-	var args map[string]int
-	if err := c.ShouldBindJSON(&args); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, response.DecodeError(err.Error()))
+	if err := etag.FromIfMatch(c); err != nil {
+		c.JSON(etag.Status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	var req updateSectionsRequest
+	if err := patch.Bind(c, &req); err != nil {
+		c.JSON(patch.Status(err), validation.NewErrorResponse(err))
 		return
 	}
 
-	section, err := s.service.Update(id, args)
+	section, err := s.service.Update(c, id, domain.SectionPatch(req))
 	if err != nil {
 		c.JSON(func() int {
 			errNF := &domain.ErrorNotFound{}
@@ -139,11 +176,26 @@ func (s *SectionsController) Update(c *gin.Context) {
 				return http.StatusConflict
 			}
 
+			errPT := &productTypesDomain.ErrorNotFound{}
+			if errors.As(err, &errPT) {
+				return http.StatusNotFound
+			}
+
+			errTemperature := &domain.ErrorTemperature{}
+			if errors.As(err, &errTemperature) {
+				return http.StatusUnprocessableEntity
+			}
+
+			if errors.Is(err, etag.ErrMismatch) {
+				return http.StatusPreconditionFailed
+			}
+
 			return http.StatusInternalServerError
 		}(), response.DecodeError(err.Error()))
 		return
 	}
 
+	etag.Set(c, section.Version)
 	c.JSON(http.StatusOK, response.NewResponse(section))
 }
 
@@ -153,10 +205,14 @@ func (s *SectionsController) Update(c *gin.Context) {
 // @Tags         sections
 // @Accept       json
 // @Produce      json
-// @Param        id   path  int  true  "Section id"
+// @Param        id        path    int     true  "Section id"
+// @Param        If-Match  header  string  true  "ETag of the section being deleted"
 // @Success      204  "Successfully deleted"
 // @Failure      400  {object}  response.Response
 // @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Failure      412  {object}  response.Response
+// @Failure      428  {object}  response.Response
 // @Router       /api/v1/sections/{id} [delete]
 func (s *SectionsController) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -165,8 +221,24 @@ func (s *SectionsController) Delete(c *gin.Context) {
 		return
 	}
 
-	err = s.service.Delete(id)
+	if err := etag.FromIfMatch(c); err != nil {
+		c.JSON(etag.Status(err), response.DecodeError(err.Error()))
+		return
+	}
+
+	err = s.service.Delete(c, id)
 	if err != nil {
+		if errors.Is(err, etag.ErrMismatch) {
+			c.JSON(http.StatusPreconditionFailed, response.DecodeError(err.Error()))
+			return
+		}
+
+		var dependentsErr *dependents.Error
+		if errors.As(err, &dependentsErr) {
+			c.JSON(http.StatusConflict, response.DecodeDependents(dependentsErr))
+			return
+		}
+
 		c.JSON(http.StatusNotFound, response.DecodeError(err.Error()))
 		return
 	}
@@ -174,6 +246,42 @@ func (s *SectionsController) Delete(c *gin.Context) {
 	c.JSON(http.StatusNoContent, response.NewResponse(nil))
 }
 
+// RestoreSection godoc
+// @Summary      Restore a section
+// @Description  Restore a deleted section, selecting by id
+// @Tags         sections
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Section id"
+// @Success      200  {object}  sections.Section
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /api/v1/sections/{id}/restore [post]
+func (s *SectionsController) Restore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+		return
+	}
+
+	section, err := s.service.Restore(c, id)
+	if err != nil {
+		c.JSON(func() int {
+			errNF := &domain.ErrorNotFound{}
+			if errors.As(err, &errNF) {
+				return http.StatusNotFound
+			}
+
+			return http.StatusInternalServerError
+		}(), response.DecodeError(err.Error()))
+		return
+	}
+
+	etag.Set(c, section.Version)
+	c.JSON(http.StatusOK, response.NewResponse(section))
+}
+
 func NewSectionsController(s domain.Service) *SectionsController {
 	return &SectionsController{s}
 }
@@ -182,9 +290,20 @@ type sectionsRequest struct {
 	SectionNumber      int `json:"section_number" binding:"required"`
 	CurrentTemperature int `json:"current_temperature" binding:"required"`
 	MinimumTemperature int `json:"minimum_temperature" binding:"required"`
-	CurrentCapacity    int `json:"current_capacity" binding:"required"`
-	MinimumCapacity    int `json:"minimum_capacity" binding:"required"`
-	MaximumCapacity    int `json:"maximum_capacity" binding:"required"`
-	WarehouseId        int `json:"warehouse_id" binding:"required"`
-	ProductTypeId      int `json:"product_type_id" binding:"required"`
+	CurrentCapacity    int `json:"current_capacity" binding:"required,ltefield=MaximumCapacity"`
+	MinimumCapacity    int `json:"minimum_capacity" binding:"required,ltefield=MaximumCapacity"`
+	MaximumCapacity    int `json:"maximum_capacity" binding:"required,min=1"`
+	WarehouseId        int `json:"warehouse_id" binding:"required,min=1"`
+	ProductTypeId      int `json:"product_type_id" binding:"required,min=1"`
+}
+
+type updateSectionsRequest struct {
+	SectionNumber      *int `json:"section_number"`
+	CurrentTemperature *int `json:"current_temperature"`
+	MinimumTemperature *int `json:"minimum_temperature"`
+	CurrentCapacity    *int `json:"current_capacity" binding:"omitempty,min=0"`
+	MinimumCapacity    *int `json:"minimum_capacity" binding:"omitempty,min=0"`
+	MaximumCapacity    *int `json:"maximum_capacity" binding:"omitempty,min=0"`
+	WarehouseId        *int `json:"warehouse_id" binding:"omitempty,min=1"`
+	ProductTypeId      *int `json:"product_type_id" binding:"omitempty,min=1"`
 }
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -8,9 +9,13 @@ import (
 	"strings"
 	"testing"
 
+	productTypesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
 	mock_sections "github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -20,6 +25,8 @@ const (
 	pathSections   = "/api/v1/sections/"
 	pathIdSections = "/api/v1/sections/:id"
 	idSections     = "1"
+
+	pathRestoreSections = "/api/v1/sections/:id/restore"
 )
 
 func mockSections(t *testing.T) (*mock_sections.MockService, *SectionsController, *gin.Engine) {
@@ -47,7 +54,7 @@ func TestSections_Create_OK(t *testing.T) {
 		ProductTypeId:      5,
 	}
 
-	service.EXPECT().Create(3, 12, 14, 25, 5, 50, 3, 5).Return(&newSection, nil)
+	service.EXPECT().Create(gomock.Any(), 3, 12, 14, 25, 5, 50, 3, 5).Return(&newSection, nil)
 
 	payload := `{
 		"section_number": 3,
@@ -95,7 +102,7 @@ func TestSections_Create_Conflict(t *testing.T) {
 
 	expectedError := domain.ErrorConflict{SectionNumber: 3}
 
-	service.EXPECT().Create(3, 12, 14, 25, 5, 50, 3, 5).Return(nil, &expectedError)
+	service.EXPECT().Create(gomock.Any(), 3, 12, 14, 25, 5, 50, 3, 5).Return(nil, &expectedError)
 
 	payload := `{
 		"section_number": 3,
@@ -115,6 +122,31 @@ func TestSections_Create_Conflict(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, resp.Code)
 }
 
+func TestSections_Create_Temperature_Out_Of_Range(t *testing.T) {
+	service, handler, api := mockSections(t)
+	api.POST(pathSections, handler.Create)
+
+	service.EXPECT().Create(gomock.Any(), 3, 12, 14, 25, 5, 50, 3, 5).Return(nil, &domain.ErrorTemperature{Field: "current_temperature", Temperature: 12, ProductTypeId: 5, Minimum: -18, Maximum: -12})
+
+	payload := `{
+		"section_number": 3,
+		"current_temperature": 12,
+		"minimum_temperature": 14,
+		"current_capacity": 25,
+		"minimum_capacity": 5,
+		"maximum_capacity": 50,
+		"warehouse_id": 3,
+		"product_type_id": 5
+	}`
+
+	req := httptest.NewRequest(http.MethodPost, pathSections, strings.NewReader(payload))
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "out of the range of product type 5")
+}
+
 func TestSections_Find_All(t *testing.T) {
 	service, handler, api := mockSections(t)
 
@@ -144,7 +176,7 @@ func TestSections_Find_All(t *testing.T) {
 	}
 
 	api.GET(pathSections, handler.GetAll)
-	service.EXPECT().GetAll().Return(db, nil)
+	service.EXPECT().GetAll(gomock.Any()).Return(db, nil)
 
 	req := httptest.NewRequest(http.MethodGet, pathSections, nil)
 	resp := httptest.NewRecorder()
@@ -163,7 +195,7 @@ func TestSections_Find_All_Error(t *testing.T) {
 	service, handler, api := mockSections(t)
 	api.GET(pathSections, handler.GetAll)
 
-	service.EXPECT().GetAll().Return([]domain.Section{}, errors.New("internal server error"))
+	service.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{}, errors.New("internal server error"))
 
 	req := httptest.NewRequest(http.MethodGet, pathSections, nil)
 	resp := httptest.NewRecorder()
@@ -176,7 +208,7 @@ func TestSections_Find_All_Empty(t *testing.T) {
 	service, handler, api := mockSections(t)
 	api.GET(pathSections, handler.GetAll)
 
-	service.EXPECT().GetAll().Return([]domain.Section{}, nil)
+	service.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, pathSections, nil)
 	resp := httptest.NewRecorder()
@@ -189,7 +221,7 @@ func TestSections_Find_By_Id_Non_Existent(t *testing.T) {
 	service, handler, api := mockSections(t)
 	api.GET(pathIdSections, handler.GetById)
 
-	service.EXPECT().GetById(1).Return(nil, &domain.ErrorNotFound{Id: 1})
+	service.EXPECT().GetById(gomock.Any(), 1).Return(nil, &domain.ErrorNotFound{Id: 1})
 
 	req := httptest.NewRequest(http.MethodGet, pathSections+idSections, nil)
 	resp := httptest.NewRecorder()
@@ -212,9 +244,10 @@ func TestSections_Find_By_Id_Existent(t *testing.T) {
 		MaximumCapacity:    50,
 		WarehouseId:        3,
 		ProductTypeId:      5,
+		Version:            3,
 	}
 
-	service.EXPECT().GetById(1).Return(&db, nil)
+	service.EXPECT().GetById(gomock.Any(), 1).Return(&db, nil)
 
 	req := httptest.NewRequest(http.MethodGet, pathSections+idSections, nil)
 	resp := httptest.NewRecorder()
@@ -227,13 +260,14 @@ func TestSections_Find_By_Id_Existent(t *testing.T) {
 	assert.Nil(t, err)
 
 	assert.Equal(t, db, expectBody.Data)
+	assert.Equal(t, `"3"`, resp.Header().Get(etag.Header))
 }
 
 func TestSections_Find_By_Id_Bad_Request(t *testing.T) {
 	_, handler, api := mockSections(t)
 	api.GET(pathIdSections, handler.GetById)
 
-	//service.EXPECT().GetById(1).Return(nil, &sections.ErrorNotFound{Id: 1})
+	//service.EXPECT().GetById(gomock.Any(), 1).Return(nil, &sections.ErrorNotFound{Id: 1})
 
 	req := httptest.NewRequest(http.MethodGet, pathSections+"a", nil)
 	resp := httptest.NewRecorder()
@@ -256,9 +290,17 @@ func TestSections_Update_OK(t *testing.T) {
 		MaximumCapacity:    50,
 		WarehouseId:        3,
 		ProductTypeId:      5,
+		Version:            2,
 	}
 
-	service.EXPECT().Update(1, map[string]int{"current_temperature": 15, "minimum_capacity": 15}).Return(&db, nil)
+	service.EXPECT().Update(gomock.Any(), 1, domain.SectionPatch{CurrentTemperature: intPtr(15), MinimumCapacity: intPtr(15)}).DoAndReturn(
+		func(ctx context.Context, id int, args domain.SectionPatch) (*domain.Section, error) {
+			version, ok := etag.Version(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, 1, version)
+			return &db, nil
+		},
+	)
 
 	payload := `{
 		"current_temperature": 15,
@@ -266,6 +308,7 @@ func TestSections_Update_OK(t *testing.T) {
 	}`
 
 	req := httptest.NewRequest(http.MethodPatch, pathSections+idSections, strings.NewReader(payload))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
@@ -276,13 +319,39 @@ func TestSections_Update_OK(t *testing.T) {
 	assert.Nil(t, err)
 
 	assert.Equal(t, db, expecBody.Data)
+	assert.Equal(t, `"2"`, resp.Header().Get(etag.Header))
+}
+
+func TestSections_Update_Missing_If_Match(t *testing.T) {
+	_, handler, api := mockSections(t)
+	api.PATCH(pathIdSections, handler.Update)
+
+	req := httptest.NewRequest(http.MethodPatch, pathSections+idSections, strings.NewReader(`{"current_temperature": 15}`))
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+}
+
+func TestSections_Update_Version_Mismatch(t *testing.T) {
+	service, handler, api := mockSections(t)
+	api.PATCH(pathIdSections, handler.Update)
+
+	service.EXPECT().Update(gomock.Any(), 1, domain.SectionPatch{CurrentTemperature: intPtr(15)}).Return(nil, etag.ErrMismatch)
+
+	req := httptest.NewRequest(http.MethodPatch, pathSections+idSections, strings.NewReader(`{"current_temperature": 15}`))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
 }
 
 func TestSections_Update_Non_Existent(t *testing.T) {
 	service, handler, api := mockSections(t)
 	api.PATCH(pathIdSections, handler.Update)
 
-	service.EXPECT().Update(1, map[string]int{"current_temperature": 15, "minimum_capacity": 15}).Return(nil, &domain.ErrorNotFound{Id: 1})
+	service.EXPECT().Update(gomock.Any(), 1, domain.SectionPatch{CurrentTemperature: intPtr(15), MinimumCapacity: intPtr(15)}).Return(nil, &domain.ErrorNotFound{Id: 1})
 
 	payload := `{
 		"current_temperature": 15,
@@ -290,34 +359,185 @@ func TestSections_Update_Non_Existent(t *testing.T) {
 	}`
 
 	req := httptest.NewRequest(http.MethodPatch, pathSections+idSections, strings.NewReader(payload))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestSections_Update_Product_Type_Non_Existent(t *testing.T) {
+	service, handler, api := mockSections(t)
+	api.PATCH(pathIdSections, handler.Update)
+
+	service.EXPECT().Update(gomock.Any(), 1, domain.SectionPatch{ProductTypeId: intPtr(4)}).Return(nil, &productTypesDomain.ErrorNotFound{Id: 4})
+
+	req := httptest.NewRequest(http.MethodPatch, pathSections+idSections, strings.NewReader(`{"product_type_id": 4}`))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
 
+func TestSections_Update_Temperature_Out_Of_Range(t *testing.T) {
+	service, handler, api := mockSections(t)
+	api.PATCH(pathIdSections, handler.Update)
+
+	service.EXPECT().Update(gomock.Any(), 1, domain.SectionPatch{CurrentTemperature: intPtr(4)}).Return(nil, &domain.ErrorTemperature{Field: "current_temperature", Temperature: 4, ProductTypeId: 5, Minimum: -18, Maximum: -12})
+
+	req := httptest.NewRequest(http.MethodPatch, pathSections+idSections, strings.NewReader(`{"current_temperature": 4}`))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestSections_Update_Zero_Value(t *testing.T) {
+	service, handler, api := mockSections(t)
+	api.PATCH(pathIdSections, handler.Update)
+
+	service.EXPECT().Update(gomock.Any(), 1, domain.SectionPatch{CurrentTemperature: intPtr(0)}).Return(&domain.Section{Id: 1}, nil)
+
+	payload := `{"current_temperature": 0}`
+
+	req := httptest.NewRequest(http.MethodPatch, pathSections+idSections, strings.NewReader(payload))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestSections_Update_Unknown_Field(t *testing.T) {
+	_, handler, api := mockSections(t)
+	api.PATCH(pathIdSections, handler.Update)
+
+	payload := `{"current_temperatur": 15}`
+
+	req := httptest.NewRequest(http.MethodPatch, pathSections+idSections, strings.NewReader(payload))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
 func TestSections_Delete_Non_Existent(t *testing.T) {
 	service, handler, api := mockSections(t)
 	api.DELETE(pathIdSections, handler.Delete)
 
-	service.EXPECT().Delete(1).Return(&domain.ErrorNotFound{Id: 1})
+	service.EXPECT().Delete(gomock.Any(), 1).Return(&domain.ErrorNotFound{Id: 1})
 
 	req := httptest.NewRequest(http.MethodDelete, pathSections+idSections, nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
 
+func TestSections_Delete_Version_Mismatch(t *testing.T) {
+	service, handler, api := mockSections(t)
+	api.DELETE(pathIdSections, handler.Delete)
+
+	service.EXPECT().Delete(gomock.Any(), 1).Return(etag.ErrMismatch)
+
+	req := httptest.NewRequest(http.MethodDelete, pathSections+idSections, nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+}
+
+func TestSections_Delete_Dependents(t *testing.T) {
+	service, handler, api := mockSections(t)
+	api.DELETE(pathIdSections, handler.Delete)
+
+	service.EXPECT().Delete(gomock.Any(), 1).Return(&dependents.Error{
+		Entity:     "section",
+		Id:         1,
+		Dependents: []dependents.Dependent{{Type: "product_batches", Count: 4}},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, pathSections+idSections, nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"product_batches"`)
+}
+
 func TestSections_Delete_OK(t *testing.T) {
 	service, handler, api := mockSections(t)
 	api.DELETE(pathIdSections, handler.Delete)
 
-	service.EXPECT().Delete(1).Return(nil)
+	service.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 
 	req := httptest.NewRequest(http.MethodDelete, pathSections+idSections, nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusNoContent, resp.Code)
 }
+
+func TestSections_Find_All_Include_Deleted(t *testing.T) {
+	service, handler, api := mockSections(t)
+	api.GET(pathSections, handler.GetAll)
+
+	service.EXPECT().GetAll(gomock.Any()).DoAndReturn(func(ctx context.Context) ([]domain.Section, error) {
+		assert.True(t, softdelete.IncludeDeleted(ctx))
+		return []domain.Section{{Id: 1}}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, pathSections+"?include_deleted=true", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestSections_Find_All_Invalid_Include_Deleted(t *testing.T) {
+	_, handler, api := mockSections(t)
+	api.GET(pathSections, handler.GetAll)
+
+	req := httptest.NewRequest(http.MethodGet, pathSections+"?include_deleted=maybe", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestSections_Restore_OK(t *testing.T) {
+	service, handler, api := mockSections(t)
+	api.POST(pathRestoreSections, handler.Restore)
+
+	service.EXPECT().Restore(gomock.Any(), 1).Return(&domain.Section{Id: 1, SectionNumber: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, pathSections+idSections+"/restore", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestSections_Restore_Non_Existent(t *testing.T) {
+	service, handler, api := mockSections(t)
+	api.POST(pathRestoreSections, handler.Restore)
+
+	service.EXPECT().Restore(gomock.Any(), 1).Return(nil, &domain.ErrorNotFound{Id: 1})
+
+	req := httptest.NewRequest(http.MethodPost, pathSections+idSections+"/restore", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -1,35 +1,59 @@
 package domain
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+)
 
 type Section struct {
-	Id                 int `json:"id,omitempty"`
-	SectionNumber      int `json:"section_number,omitempty"`
-	CurrentTemperature int `json:"current_temperature,omitempty"`
-	MinimumTemperature int `json:"minimum_temperature,omitempty"`
-	CurrentCapacity    int `json:"current_capacity,omitempty"`
-	MinimumCapacity    int `json:"minimum_capacity,omitempty"`
-	MaximumCapacity    int `json:"maximum_capacity,omitempty"`
-	WarehouseId        int `json:"warehouse_id,omitempty"`
-	ProductTypeId      int `json:"product_type_id,omitempty"`
+	Id                 int           `json:"id,omitempty"`
+	SectionNumber      int           `json:"section_number,omitempty"`
+	CurrentTemperature int           `json:"current_temperature,omitempty"`
+	MinimumTemperature int           `json:"minimum_temperature,omitempty"`
+	CurrentCapacity    int           `json:"current_capacity,omitempty"`
+	MinimumCapacity    int           `json:"minimum_capacity,omitempty"`
+	MaximumCapacity    int           `json:"maximum_capacity,omitempty"`
+	WarehouseId        int           `json:"warehouse_id,omitempty"`
+	ProductTypeId      int           `json:"product_type_id,omitempty"`
+	DeletedAt          date.DateTime `json:"deleted_at"`
+	Version            int           `json:"version"`
+}
+
+// SectionPatch holds the fields of a partial update. Nil fields are left
+// untouched.
+type SectionPatch struct {
+	SectionNumber      *int `json:"section_number"`
+	CurrentTemperature *int `json:"current_temperature"`
+	MinimumTemperature *int `json:"minimum_temperature"`
+	CurrentCapacity    *int `json:"current_capacity"`
+	MinimumCapacity    *int `json:"minimum_capacity"`
+	MaximumCapacity    *int `json:"maximum_capacity"`
+	WarehouseId        *int `json:"warehouse_id"`
+	ProductTypeId      *int `json:"product_type_id"`
 }
 
 //go:generate mockgen -source=./domain.go -destination=./mock/domain_mock.go
 type Repository interface {
-	GetAll() ([]Section, error)
-	GetById(id int) (*Section, error)
-	Create(sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId int) (*Section, error)
-	Exists(id int) error
-	Update(id int, args map[string]int) (*Section, error)
-	Delete(id int) error
+	GetAll(ctx context.Context) ([]Section, error)
+	GetById(ctx context.Context, id int) (*Section, error)
+	Create(ctx context.Context, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId int) (*Section, error)
+	Exists(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, args SectionPatch) (*Section, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Dependents(ctx context.Context, id int) ([]dependents.Dependent, error)
 }
 
 type Service interface {
-	GetAll() ([]Section, error)
-	GetById(id int) (*Section, error)
-	Create(sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId int) (*Section, error)
-	Update(id int, args map[string]int) (*Section, error)
-	Delete(id int) error
+	GetAll(ctx context.Context) ([]Section, error)
+	GetById(ctx context.Context, id int) (*Section, error)
+	Create(ctx context.Context, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId int) (*Section, error)
+	Update(ctx context.Context, id int, args SectionPatch) (*Section, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) (*Section, error)
 }
 
 type ErrorNotFound struct {
@@ -47,3 +71,20 @@ type ErrorConflict struct {
 func (e *ErrorConflict) Error() string {
 	return fmt.Sprintf("a section with number %d already exists", e.SectionNumber)
 }
+
+// ErrorTemperature is returned when a temperature of a section is out of the
+// range of its product type.
+type ErrorTemperature struct {
+	Field         string
+	Temperature   int
+	ProductTypeId int
+	Minimum       float64
+	Maximum       float64
+}
+
+func (e *ErrorTemperature) Error() string {
+	return fmt.Sprintf(
+		"%s %d is out of the range of product type %d, from %g to %g",
+		e.Field, e.Temperature, e.ProductTypeId, e.Minimum, e.Maximum,
+	)
+}
@@ -1,7 +1,7 @@
 package repository
 
 const (
-	GetAllQuery = `
+	SelectQuery = `
 		SELECT
 			id,
 			section_number,
@@ -11,24 +11,18 @@ const (
 			minimum_capacity,
 			maximum_capacity,
 			warehouse_id,
-			product_type_id
+			product_type_id,
+			deleted_at,
+			version
 		FROM
 			section`
-	GetByIdQuery = `
-		SELECT
-			id,
-			section_number,
-			current_temperature,
-			minimum_temperature,
-			current_capacity,
-			minimum_capacity,
-			maximum_capacity,
-			warehouse_id,
-			product_type_id
-		FROM
-			section
+	GetAllQuery = SelectQuery + `
 		WHERE
-			id = ?`
+			deleted_at IS NULL`
+	GetByIdQuery = SelectQuery + `
+		WHERE
+			id = ?
+			AND deleted_at IS NULL`
 	CreateQuery = `
 		INSERT INTO section (
 			section_number,
@@ -50,12 +44,30 @@ const (
 			minimum_capacity = ?,
 			maximum_capacity = ?,
 			warehouse_id = ?,
-			product_type_id = ?
-		WHERE id = ?`
+			product_type_id = ?,
+			version = version + 1
+		WHERE
+			id = ?
+			AND version = ?`
 	DeleteQuery = `
-		DELETE
-		FROM
-			section
+		UPDATE section
+		SET deleted_at = ?, version = version + 1
+		WHERE
+			id = ?
+			AND deleted_at IS NULL`
+	RestoreQuery = `
+		UPDATE section
+		SET deleted_at = NULL, version = version + 1
+		WHERE
+			id = ?
+			AND deleted_at IS NOT NULL`
+
+	// Variants that also read soft deleted sections, used when the request
+	// has ?include_deleted=true.
+	GetAllWithDeletedQuery  = SelectQuery
+	GetByIdWithDeletedQuery = SelectQuery + `
 		WHERE
 			id = ?`
+
+	CountBatchesQuery = `SELECT COUNT(*) FROM product_batches WHERE section_id = ?`
 )
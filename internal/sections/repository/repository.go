@@ -1,19 +1,29 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 )
 
 type repository struct {
 	database *sql.DB
 }
 
-func (r *repository) GetAll() ([]domain.Section, error) {
+func (r *repository) GetAll(ctx context.Context) ([]domain.Section, error) {
 	var data []domain.Section
 
-	rows, err := r.database.Query(GetAllQuery)
+	query := GetAllQuery
+	if softdelete.IncludeDeleted(ctx) {
+		query = GetAllWithDeletedQuery
+	}
+
+	rows, err := r.database.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -21,7 +31,7 @@ func (r *repository) GetAll() ([]domain.Section, error) {
 	for rows.Next() {
 		var section domain.Section
 
-		if err := rows.Scan(&section.Id, &section.SectionNumber, &section.CurrentTemperature, &section.MinimumTemperature, &section.CurrentCapacity, &section.MinimumCapacity, &section.MaximumCapacity, &section.WarehouseId, &section.ProductTypeId); err != nil {
+		if err := rows.Scan(&section.Id, &section.SectionNumber, &section.CurrentTemperature, &section.MinimumTemperature, &section.CurrentCapacity, &section.MinimumCapacity, &section.MaximumCapacity, &section.WarehouseId, &section.ProductTypeId, &section.DeletedAt, &section.Version); err != nil {
 			return nil, err
 		}
 
@@ -31,20 +41,25 @@ func (r *repository) GetAll() ([]domain.Section, error) {
 	return data, nil
 }
 
-func (r *repository) GetById(id int) (*domain.Section, error) {
-	row := r.database.QueryRow(GetByIdQuery, id)
+func (r *repository) GetById(ctx context.Context, id int) (*domain.Section, error) {
+	query := GetByIdQuery
+	if softdelete.IncludeDeleted(ctx) {
+		query = GetByIdWithDeletedQuery
+	}
+
+	row := r.database.QueryRowContext(ctx, query, id)
 
 	var section domain.Section
 
-	if err := row.Scan(&section.Id, &section.SectionNumber, &section.CurrentTemperature, &section.MinimumTemperature, &section.CurrentCapacity, &section.MinimumCapacity, &section.MaximumCapacity, &section.WarehouseId, &section.ProductTypeId); err != nil {
+	if err := row.Scan(&section.Id, &section.SectionNumber, &section.CurrentTemperature, &section.MinimumTemperature, &section.CurrentCapacity, &section.MinimumCapacity, &section.MaximumCapacity, &section.WarehouseId, &section.ProductTypeId, &section.DeletedAt, &section.Version); err != nil {
 		return nil, err
 	}
 
 	return &section, nil
 }
 
-func (r *repository) Create(sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId int) (*domain.Section, error) {
-	result, err := r.database.Exec(CreateQuery, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId)
+func (r *repository) Create(ctx context.Context, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId int) (*domain.Section, error) {
+	result, err := r.database.ExecContext(ctx, CreateQuery, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId)
 	if err != nil {
 		return nil, err
 	}
@@ -64,53 +79,118 @@ func (r *repository) Create(sectionNumber, currentTemperature, minimumTemperatur
 		MaximumCapacity:    maximumCapacity,
 		WarehouseId:        warehouseId,
 		ProductTypeId:      productTypeId,
+		Version:            1,
 	}
 
 	return &section, nil
 }
 
-func (r *repository) Delete(id int) error {
-	_, err := r.database.Exec(DeleteQuery, id)
-	return err
+func (r *repository) Delete(ctx context.Context, id int) error {
+	query, args := DeleteQuery, []interface{}{date.Now(), id}
+	if version, ok := etag.Version(ctx); ok {
+		query, args = DeleteQuery+etag.Condition, append(args, version)
+	}
+
+	return r.exec(ctx, id, query, args...)
+}
+
+func (r *repository) Restore(ctx context.Context, id int) error {
+	return r.exec(ctx, id, RestoreQuery, id)
+}
+
+// Dependents counts the product batches stored in the section.
+func (r *repository) Dependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	var batches int
+	if err := r.database.QueryRowContext(ctx, CountBatchesQuery, id).Scan(&batches); err != nil {
+		return nil, err
+	}
+
+	return []dependents.Dependent{{Type: "product_batches", Count: batches}}, nil
+}
+
+// exec runs a statement that must change the section id.
+func (r *repository) exec(ctx context.Context, id int, query string, args ...interface{}) error {
+	result, err := r.database.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		// The section is there, just not in the version the request expects.
+		if _, ok := etag.Version(ctx); ok {
+			if _, err := r.GetById(ctx, id); err == nil {
+				return etag.ErrMismatch
+			}
+		}
+
+		return &domain.ErrorNotFound{Id: id}
+	}
+
+	return nil
 }
 
-func (r *repository) Exists(id int) error {
-	_, err := r.GetById(id)
+func (r *repository) Exists(ctx context.Context, id int) error {
+	_, err := r.GetById(ctx, id)
 	return err
 }
 
-func (r *repository) Update(id int, args map[string]int) (*domain.Section, error) {
-	section, err := r.GetById(id)
+func (r *repository) Update(ctx context.Context, id int, args domain.SectionPatch) (*domain.Section, error) {
+	section, err := r.GetById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	for key, value := range args {
-		switch key {
-		case "section_number":
-			section.SectionNumber = value
-		case "current_temperature":
-			section.CurrentTemperature = value
-		case "minimum_temperature":
-			section.MinimumTemperature = value
-		case "current_capacity":
-			section.CurrentCapacity = value
-		case "minimum_capacity":
-			section.MinimumCapacity = value
-		case "maximum_capacity":
-			section.MaximumCapacity = value
-		case "warehouse_id":
-			section.WarehouseId = value
-		case "product_type_id":
-			section.ProductTypeId = value
-		}
+	if err := etag.Check(ctx, section.Version); err != nil {
+		return nil, err
+	}
+
+	if args.SectionNumber != nil {
+		section.SectionNumber = *args.SectionNumber
+	}
+	if args.CurrentTemperature != nil {
+		section.CurrentTemperature = *args.CurrentTemperature
+	}
+	if args.MinimumTemperature != nil {
+		section.MinimumTemperature = *args.MinimumTemperature
+	}
+	if args.CurrentCapacity != nil {
+		section.CurrentCapacity = *args.CurrentCapacity
+	}
+	if args.MinimumCapacity != nil {
+		section.MinimumCapacity = *args.MinimumCapacity
+	}
+	if args.MaximumCapacity != nil {
+		section.MaximumCapacity = *args.MaximumCapacity
+	}
+	if args.WarehouseId != nil {
+		section.WarehouseId = *args.WarehouseId
+	}
+	if args.ProductTypeId != nil {
+		section.ProductTypeId = *args.ProductTypeId
 	}
 
-	_, err = r.database.Exec(UpdateQuery, section.SectionNumber, section.CurrentTemperature, section.MinimumTemperature, section.CurrentCapacity, section.MinimumCapacity, section.MaximumCapacity, section.WarehouseId, section.ProductTypeId, id)
+	result, err := r.database.ExecContext(ctx, UpdateQuery, section.SectionNumber, section.CurrentTemperature, section.MinimumTemperature, section.CurrentCapacity, section.MinimumCapacity, section.MaximumCapacity, section.WarehouseId, section.ProductTypeId, id, section.Version)
 	if err != nil {
 		return nil, err
 	}
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	// Someone else changed the section between the read and the write.
+	if affected == 0 {
+		return nil, etag.ErrMismatch
+	}
+
+	section.Version++
+
 	return section, nil
 }
 
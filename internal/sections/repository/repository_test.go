@@ -1,11 +1,15 @@
 package repository
 
 import (
+	"context"
 	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,6 +24,7 @@ var (
 		MaximumCapacity:    50,
 		WarehouseId:        3,
 		ProductTypeId:      5,
+		Version:            1,
 	}
 )
 
@@ -41,6 +46,7 @@ func TestRepository_Create(t *testing.T) {
 
 	repository := NewRepository(db)
 	section, err := repository.Create(
+		context.TODO(),
 		sampleSection.SectionNumber,
 		sampleSection.CurrentTemperature,
 		sampleSection.MinimumTemperature,
@@ -60,7 +66,7 @@ func TestRepository_Get_All(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	result := sqlmock.NewRows([]string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id"}).AddRow(
+	result := sqlmock.NewRows([]string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id", "deleted_at", "version"}).AddRow(
 		sampleSection.Id,
 		sampleSection.SectionNumber,
 		sampleSection.CurrentTemperature,
@@ -70,12 +76,14 @@ func TestRepository_Get_All(t *testing.T) {
 		sampleSection.MaximumCapacity,
 		sampleSection.WarehouseId,
 		sampleSection.ProductTypeId,
+		nil,
+		sampleSection.Version,
 	)
 
-	mock.ExpectQuery(GetAllQuery).WillReturnRows(result)
+	mock.ExpectQuery(regexp.QuoteMeta(GetAllQuery) + "$").WillReturnRows(result)
 
 	repository := NewRepository(db)
-	sections, err := repository.GetAll()
+	sections, err := repository.GetAll(context.TODO())
 
 	assert.NoError(t, err)
 	assert.Equal(t, []domain.Section{sampleSection}, sections)
@@ -86,7 +94,7 @@ func TestRepository_Get_ById(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	result := sqlmock.NewRows([]string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id"}).AddRow(
+	result := sqlmock.NewRows([]string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id", "deleted_at", "version"}).AddRow(
 		sampleSection.Id,
 		sampleSection.SectionNumber,
 		sampleSection.CurrentTemperature,
@@ -96,12 +104,14 @@ func TestRepository_Get_ById(t *testing.T) {
 		sampleSection.MaximumCapacity,
 		sampleSection.WarehouseId,
 		sampleSection.ProductTypeId,
+		nil,
+		sampleSection.Version,
 	)
 
-	mock.ExpectQuery(regexp.QuoteMeta(GetByIdQuery)).WithArgs(sampleSection.Id).WillReturnRows(result)
+	mock.ExpectQuery(regexp.QuoteMeta(GetByIdQuery) + "$").WithArgs(sampleSection.Id).WillReturnRows(result)
 
 	repository := NewRepository(db)
-	section, err := repository.GetById(sampleSection.Id)
+	section, err := repository.GetById(context.TODO(), sampleSection.Id)
 
 	assert.NoError(t, err)
 	assert.Equal(t, sampleSection, *section)
@@ -112,7 +122,7 @@ func TestRepository_Update(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	result := sqlmock.NewRows([]string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id"}).AddRow(
+	result := sqlmock.NewRows([]string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id", "deleted_at", "version"}).AddRow(
 		sampleSection.Id,
 		sampleSection.SectionNumber,
 		sampleSection.CurrentTemperature,
@@ -122,9 +132,11 @@ func TestRepository_Update(t *testing.T) {
 		sampleSection.MaximumCapacity,
 		sampleSection.WarehouseId,
 		sampleSection.ProductTypeId,
+		nil,
+		sampleSection.Version,
 	)
 
-	mock.ExpectQuery(regexp.QuoteMeta(GetByIdQuery)).WithArgs(sampleSection.Id).WillReturnRows(result)
+	mock.ExpectQuery(regexp.QuoteMeta(GetByIdQuery) + "$").WithArgs(sampleSection.Id).WillReturnRows(result)
 
 	updatedSection := domain.Section{
 		Id:                 sampleSection.Id,
@@ -136,6 +148,7 @@ func TestRepository_Update(t *testing.T) {
 		MaximumCapacity:    51,
 		WarehouseId:        3,
 		ProductTypeId:      5,
+		Version:            2,
 	}
 
 	mock.ExpectExec(regexp.QuoteMeta(UpdateQuery)).WithArgs(
@@ -148,20 +161,22 @@ func TestRepository_Update(t *testing.T) {
 		updatedSection.WarehouseId,
 		updatedSection.ProductTypeId,
 		updatedSection.Id,
+		sampleSection.Version,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	repository := NewRepository(db)
 	section, err := repository.Update(
+		context.TODO(),
 		sampleSection.Id,
-		map[string]int{
-			"section_number":      6,
-			"current_temperature": 15,
-			"minimum_temperature": 16,
-			"current_capacity":    26,
-			"minimum_capacity":    6,
-			"maximum_capacity":    51,
-			"warehouse_id":        3,
-			"product_type_id":     5,
+		domain.SectionPatch{
+			SectionNumber:      intPtr(6),
+			CurrentTemperature: intPtr(15),
+			MinimumTemperature: intPtr(16),
+			CurrentCapacity:    intPtr(26),
+			MinimumCapacity:    intPtr(6),
+			MaximumCapacity:    intPtr(51),
+			WarehouseId:        intPtr(3),
+			ProductTypeId:      intPtr(5),
 		},
 	)
 
@@ -174,10 +189,127 @@ func TestRepository_Delete(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectExec(regexp.QuoteMeta(DeleteQuery)).WithArgs(sampleSection.Id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(DeleteQuery)).WithArgs(sqlmock.AnyArg(), sampleSection.Id).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	repository := NewRepository(db)
-	err = repository.Delete(sampleSection.Id)
+	err = repository.Delete(context.TODO(), sampleSection.Id)
 
 	assert.NoError(t, err)
 }
+
+func TestRepository_Delete_Not_Found(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(DeleteQuery)).WithArgs(sqlmock.AnyArg(), sampleSection.Id).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repository := NewRepository(db)
+	err = repository.Delete(context.TODO(), sampleSection.Id)
+
+	assert.ErrorAs(t, err, new(*domain.ErrorNotFound))
+}
+
+func TestRepository_Dependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(CountBatchesQuery)).WithArgs(sampleSection.Id).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	repository := NewRepository(db)
+	found, err := repository.Dependents(context.TODO(), sampleSection.Id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dependents.Dependent{{Type: "product_batches", Count: 4}}, found)
+}
+
+func TestRepository_Get_ById_Include_Deleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	result := sqlmock.NewRows([]string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id", "deleted_at", "version"}).AddRow(
+		sampleSection.Id,
+		sampleSection.SectionNumber,
+		sampleSection.CurrentTemperature,
+		sampleSection.MinimumTemperature,
+		sampleSection.CurrentCapacity,
+		sampleSection.MinimumCapacity,
+		sampleSection.MaximumCapacity,
+		sampleSection.WarehouseId,
+		sampleSection.ProductTypeId,
+		"2022-07-09 10:00:00",
+		sampleSection.Version,
+	)
+
+	mock.ExpectQuery(regexp.QuoteMeta(GetByIdWithDeletedQuery) + "$").WithArgs(sampleSection.Id).WillReturnRows(result)
+
+	repository := NewRepository(db)
+	section, err := repository.GetById(softdelete.WithDeleted(context.TODO()), sampleSection.Id)
+
+	assert.NoError(t, err)
+	assert.False(t, section.DeletedAt.IsZero())
+}
+
+func TestRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(RestoreQuery)).WithArgs(sampleSection.Id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(RestoreQuery)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repository := NewRepository(db)
+
+	assert.NoError(t, repository.Restore(context.TODO(), sampleSection.Id))
+	assert.ErrorAs(t, repository.Restore(context.TODO(), 2), new(*domain.ErrorNotFound))
+}
+
+func TestRepository_Update_Version_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id", "deleted_at", "version"}
+	row := func() *sqlmock.Rows {
+		return sqlmock.NewRows(columns).AddRow(1, 3, 12, 14, 25, 5, 50, 3, 5, nil, 1)
+	}
+
+	repository := NewRepository(db)
+
+	// the client read an older version
+	mock.ExpectQuery(regexp.QuoteMeta(GetByIdQuery) + "$").WithArgs(sampleSection.Id).WillReturnRows(row())
+
+	_, err = repository.Update(etag.WithVersion(context.TODO(), 2), sampleSection.Id, domain.SectionPatch{SectionNumber: intPtr(6)})
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+
+	// the section changed between the read and the write
+	mock.ExpectQuery(regexp.QuoteMeta(GetByIdQuery) + "$").WithArgs(sampleSection.Id).WillReturnRows(row())
+	mock.ExpectExec(regexp.QuoteMeta(UpdateQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = repository.Update(etag.WithVersion(context.TODO(), 1), sampleSection.Id, domain.SectionPatch{SectionNumber: intPtr(6)})
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Delete_Version_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(DeleteQuery+etag.Condition)).WithArgs(sqlmock.AnyArg(), sampleSection.Id, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(GetByIdQuery) + "$").WithArgs(sampleSection.Id).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id", "deleted_at", "version"}).
+			AddRow(1, 3, 12, 14, 25, 5, 50, 3, 5, nil, 2),
+	)
+
+	repository := NewRepository(db)
+	err = repository.Delete(etag.WithVersion(context.TODO(), 1), sampleSection.Id)
+
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+)
+
+type auditedService struct {
+	domain.Service
+	audit auditDomain.Service
+}
+
+// NewAuditedService records every change made through s in the audit trail.
+func NewAuditedService(s domain.Service, audit auditDomain.Service) domain.Service {
+	return &auditedService{s, audit}
+}
+
+func (s *auditedService) Create(ctx context.Context, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId int) (*domain.Section, error) {
+	section, err := s.Service.Create(
+		ctx,
+		sectionNumber, currentTemperature, minimumTemperature,
+		currentCapacity, minimumCapacity, maximumCapacity,
+		warehouseId, productTypeId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntitySections, section.Id, auditDomain.ActionCreate, nil, section)
+
+	return section, nil
+}
+
+func (s *auditedService) Update(ctx context.Context, id int, args domain.SectionPatch) (*domain.Section, error) {
+	before, _ := s.Service.GetById(ctx, id)
+
+	section, err := s.Service.Update(ctx, id, args)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntitySections, id, auditDomain.ActionUpdate, before, section)
+
+	return section, nil
+}
+
+func (s *auditedService) Delete(ctx context.Context, id int) error {
+	before, _ := s.Service.GetById(ctx, id)
+
+	if err := s.Service.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntitySections, id, auditDomain.ActionDelete, before, nil)
+
+	return nil
+}
+
+func (s *auditedService) Restore(ctx context.Context, id int) (*domain.Section, error) {
+	before, _ := s.Service.GetById(softdelete.WithDeleted(ctx), id)
+
+	section, err := s.Service.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntitySections, id, auditDomain.ActionRestore, before, section)
+
+	return section, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	mock_audit "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditedService(t *testing.T) {
+	before := &domain.Section{Id: 1, SectionNumber: 3, CurrentTemperature: 10}
+	after := &domain.Section{Id: 1, SectionNumber: 3, CurrentTemperature: 15}
+
+	t.Run("Create records the new section", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().Create(gomock.Any(), 3, 10, 5, 25, 5, 50, 3, 5).Return(before, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntitySections, 1, auditDomain.ActionCreate, nil, before)
+
+		_, err := NewAuditedService(service, audit).Create(context.TODO(), 3, 10, 5, 25, 5, 50, 3, 5)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Update records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		patch := domain.SectionPatch{CurrentTemperature: intPtr(15)}
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Update(gomock.Any(), 1, patch).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntitySections, 1, auditDomain.ActionUpdate, before, after)
+
+		_, err := NewAuditedService(service, audit).Update(context.TODO(), 1, patch)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete records the removed section", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Delete(gomock.Any(), 1).Return(nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntitySections, 1, auditDomain.ActionDelete, before, nil)
+
+		assert.NoError(t, NewAuditedService(service, audit).Delete(context.TODO(), 1))
+	})
+	t.Run("Restore records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Restore(gomock.Any(), 1).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntitySections, 1, auditDomain.ActionRestore, before, after)
+
+		_, err := NewAuditedService(service, audit).Restore(context.TODO(), 1)
+		assert.NoError(t, err)
+	})
+}
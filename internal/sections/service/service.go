@@ -1,21 +1,29 @@
 package service
 
-import "github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
+import (
+	"context"
+
+	productTypesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+)
 
 type service struct {
-	repository domain.Repository
+	repository   domain.Repository
+	productTypes productTypesDomain.Repository
 }
 
-func (s *service) GetAll() ([]domain.Section, error) {
-	return s.repository.GetAll()
+func (s *service) GetAll(ctx context.Context) ([]domain.Section, error) {
+	return s.repository.GetAll(ctx)
 }
 
-func (s *service) GetById(id int) (*domain.Section, error) {
-	return s.repository.GetById(id)
+func (s *service) GetById(ctx context.Context, id int) (*domain.Section, error) {
+	return s.repository.GetById(ctx, id)
 }
 
-func (s *service) Create(sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId int) (*domain.Section, error) {
-	sections, err := s.repository.GetAll()
+func (s *service) Create(ctx context.Context, sectionNumber, currentTemperature, minimumTemperature, currentCapacity, minimumCapacity, maximumCapacity, warehouseId, productTypeId int) (*domain.Section, error) {
+	sections, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -26,39 +34,121 @@ func (s *service) Create(sectionNumber, currentTemperature, minimumTemperature,
 		}
 	}
 
+	productType, err := s.productTypes.GetById(ctx, productTypeId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTemperatures(productType, currentTemperature, minimumTemperature); err != nil {
+		return nil, err
+	}
+
 	return s.repository.Create(
+		ctx,
 		sectionNumber, currentTemperature, minimumTemperature,
 		currentCapacity, minimumCapacity, maximumCapacity,
 		warehouseId, productTypeId,
 	)
 }
 
-func (s *service) Update(id int, args map[string]int) (*domain.Section, error) {
-	err := s.repository.Exists(id)
+func (s *service) Update(ctx context.Context, id int, args domain.SectionPatch) (*domain.Section, error) {
+	err := s.repository.Exists(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if sectionNumber := args["section_number"]; sectionNumber != 0 {
-		sections, err := s.repository.GetAll()
+	if args.SectionNumber != nil {
+		sectionNumber := *args.SectionNumber
+
+		sections, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
 		if err != nil {
 			return nil, err
 		}
 
 		for _, section := range sections {
-			if section.SectionNumber == sectionNumber {
+			if section.Id != id && section.SectionNumber == sectionNumber {
 				return nil, &domain.ErrorConflict{SectionNumber: sectionNumber}
 			}
 		}
 	}
 
-	return s.repository.Update(id, args)
+	if args.CurrentTemperature != nil || args.MinimumTemperature != nil || args.ProductTypeId != nil {
+		section, err := s.repository.GetById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if args.CurrentTemperature != nil {
+			section.CurrentTemperature = *args.CurrentTemperature
+		}
+		if args.MinimumTemperature != nil {
+			section.MinimumTemperature = *args.MinimumTemperature
+		}
+		if args.ProductTypeId != nil {
+			section.ProductTypeId = *args.ProductTypeId
+		}
+
+		productType, err := s.productTypes.GetById(ctx, section.ProductTypeId)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkTemperatures(productType, section.CurrentTemperature, section.MinimumTemperature); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repository.Update(ctx, id, args)
+}
+
+// checkTemperatures keeps the temperatures of a section within the range of
+// its product type.
+func checkTemperatures(productType productTypesDomain.ProductType, currentTemperature, minimumTemperature int) error {
+	for _, temperature := range []struct {
+		field string
+		value int
+	}{
+		{"current_temperature", currentTemperature},
+		{"minimum_temperature", minimumTemperature},
+	} {
+		value := float64(temperature.value)
+		if value < productType.MinimumTemperature || value > productType.MaximumTemperature {
+			return &domain.ErrorTemperature{
+				Field:         temperature.field,
+				Temperature:   temperature.value,
+				ProductTypeId: productType.Id,
+				Minimum:       productType.MinimumTemperature,
+				Maximum:       productType.MaximumTemperature,
+			}
+		}
+	}
+
+	return nil
 }
 
-func (s *service) Delete(id int) error {
-	return s.repository.Delete(id)
+// Delete removes the section. Its product batches are stock that has to be
+// moved out first, so they always block the delete, cascading or not.
+func (s *service) Delete(ctx context.Context, id int) error {
+	found, err := s.repository.Dependents(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := dependents.Check("section", id, found...); err != nil {
+		return err
+	}
+
+	return s.repository.Delete(ctx, id)
+}
+
+func (s *service) Restore(ctx context.Context, id int) (*domain.Section, error) {
+	if err := s.repository.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetById(ctx, id)
 }
 
-func NewService(r domain.Repository) domain.Service {
-	return &service{r}
+func NewService(r domain.Repository, productTypes productTypesDomain.Repository) domain.Service {
+	return &service{r, productTypes}
 }
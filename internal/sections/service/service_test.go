@@ -1,26 +1,43 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 
+	productTypesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain"
+	mock_product_types "github.com/douglmendes/mercado-fresco-round-go/internal/product_types/domain/mock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
 	mock "github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
 
+var anyTemperature = productTypesDomain.ProductType{MinimumTemperature: -50, MaximumTemperature: 50}
+
 func callMock(t *testing.T) (*mock.MockRepository, domain.Service) {
+	apiMock, productTypes, service := callMockWithProductTypes(t)
+
+	// Every product type exists, and takes any temperature a test uses,
+	// unless the test says otherwise.
+	productTypes.EXPECT().GetById(gomock.Any(), gomock.Any()).AnyTimes().Return(anyTemperature, nil)
+
+	return apiMock, service
+}
+
+func callMockWithProductTypes(t *testing.T) (*mock.MockRepository, *mock_product_types.MockRepository, domain.Service) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	apiMock := mock.NewMockRepository(ctrl)
-	service := NewService(apiMock)
-	return apiMock, service
+	productTypes := mock_product_types.NewMockRepository(ctrl)
+	service := NewService(apiMock, productTypes)
+	return apiMock, productTypes, service
 }
 
 func TestService_Create_OK(t *testing.T) {
 	api, service := callMock(t)
-	api.EXPECT().GetAll().Return([]domain.Section{}, nil)
+	api.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{}, nil)
 
 	newSection := domain.Section{
 		Id:                 1,
@@ -34,16 +51,16 @@ func TestService_Create_OK(t *testing.T) {
 		ProductTypeId:      3,
 	}
 
-	api.EXPECT().Create(3, 15, 5, 150, 15, 250, 3, 3).Return(&newSection, nil)
+	api.EXPECT().Create(gomock.Any(), 3, 15, 5, 150, 15, 250, 3, 3).Return(&newSection, nil)
 
-	res, err := service.Create(3, 15, 5, 150, 15, 250, 3, 3)
+	res, err := service.Create(context.TODO(), 3, 15, 5, 150, 15, 250, 3, 3)
 	assert.Equal(t, res, &newSection)
 	assert.Nil(t, err)
 }
 
 func TestService_Create_Conflict(t *testing.T) {
 	api, service := callMock(t)
-	api.EXPECT().GetAll().Return([]domain.Section{{
+	api.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{{
 		Id:                 1,
 		SectionNumber:      1,
 		CurrentTemperature: 15,
@@ -57,9 +74,9 @@ func TestService_Create_Conflict(t *testing.T) {
 
 	expectedError := domain.ErrorConflict{SectionNumber: 1}
 
-	api.EXPECT().Create(1, 15, 5, 150, 15, 250, 1, 1).Return(nil, &expectedError)
+	api.EXPECT().Create(gomock.Any(), 1, 15, 5, 150, 15, 250, 1, 1).Return(nil, &expectedError)
 
-	resp, err := service.Create(1, 15, 5, 150, 15, 250, 1, 1)
+	resp, err := service.Create(context.TODO(), 1, 15, 5, 150, 15, 250, 1, 1)
 	assert.Nil(t, resp)
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, expectedError.Error())
@@ -67,15 +84,25 @@ func TestService_Create_Conflict(t *testing.T) {
 
 func TestService_Create_Data_Error(t *testing.T) {
 	api, service := callMock(t)
-	api.EXPECT().GetAll().Return(nil, errors.New("error"))
+	api.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("error"))
 
-	api.EXPECT().Create(1, 15, 5, 150, 15, 250, 1, 1).Return(nil, errors.New("error"))
+	api.EXPECT().Create(gomock.Any(), 1, 15, 5, 150, 15, 250, 1, 1).Return(nil, errors.New("error"))
 
-	resp, err := service.Create(1, 15, 5, 150, 15, 250, 1, 1)
+	resp, err := service.Create(context.TODO(), 1, 15, 5, 150, 15, 250, 1, 1)
 	assert.NotNil(t, err)
 	assert.Nil(t, resp)
 }
 
+func TestService_Create_Product_Type_Not_Found(t *testing.T) {
+	api, productTypes, service := callMockWithProductTypes(t)
+	api.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{}, nil)
+	productTypes.EXPECT().GetById(gomock.Any(), 4).Return(productTypesDomain.ProductType{}, &productTypesDomain.ErrorNotFound{Id: 4})
+
+	resp, err := service.Create(context.TODO(), 1, 15, 5, 150, 15, 250, 1, 4)
+	assert.Nil(t, resp)
+	assert.EqualError(t, err, "product type 4 not found")
+}
+
 func TestService_Find_All(t *testing.T) {
 	api, service := callMock(t)
 
@@ -104,9 +131,9 @@ func TestService_Find_All(t *testing.T) {
 		},
 	}
 
-	api.EXPECT().GetAll().Return(db, nil)
+	api.EXPECT().GetAll(gomock.Any()).Return(db, nil)
 
-	res, err := service.GetAll()
+	res, err := service.GetAll(context.TODO())
 	assert.Equal(t, len(res), len(db))
 	assert.Nil(t, err)
 }
@@ -115,9 +142,9 @@ func TestService_Find_By_Id_Non_Existent(t *testing.T) {
 	api, service := callMock(t)
 	expectedError := domain.ErrorNotFound{Id: 3}
 
-	api.EXPECT().GetById(3).Return(nil, &expectedError)
+	api.EXPECT().GetById(gomock.Any(), 3).Return(nil, &expectedError)
 
-	res, err := service.GetById(3)
+	res, err := service.GetById(context.TODO(), 3)
 	assert.Nil(t, res)
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, expectedError.Error())
@@ -138,16 +165,17 @@ func TestService_Find_By_Id_Existent(t *testing.T) {
 		ProductTypeId:      2,
 	}
 
-	api.EXPECT().GetById(2).Return(&foundSection, nil)
+	api.EXPECT().GetById(gomock.Any(), 2).Return(&foundSection, nil)
 
-	res, err := service.GetById(2)
+	res, err := service.GetById(context.TODO(), 2)
 	assert.Equal(t, res, &foundSection)
 	assert.Nil(t, err)
 }
 
 func TestService_Update_Existent(t *testing.T) {
 	api, service := callMock(t)
-	api.EXPECT().Exists(1).Return(nil)
+	api.EXPECT().Exists(gomock.Any(), 1).Return(nil)
+	api.EXPECT().GetById(gomock.Any(), 1).Return(&domain.Section{Id: 1, ProductTypeId: 1}, nil)
 
 	updatedSection := domain.Section{
 		Id:                 1,
@@ -161,9 +189,9 @@ func TestService_Update_Existent(t *testing.T) {
 		MinimumCapacity:    15,
 	}
 
-	api.EXPECT().Update(1, map[string]int{"current_temperature": 15, "minimum_capacity": 15}).Return(&updatedSection, nil)
+	api.EXPECT().Update(gomock.Any(), 1, domain.SectionPatch{CurrentTemperature: intPtr(15), MinimumCapacity: intPtr(15)}).Return(&updatedSection, nil)
 
-	res, err := service.Update(1, map[string]int{"current_temperature": 15, "minimum_capacity": 15})
+	res, err := service.Update(context.TODO(), 1, domain.SectionPatch{CurrentTemperature: intPtr(15), MinimumCapacity: intPtr(15)})
 	assert.Equal(t, res, &updatedSection)
 	assert.Nil(t, err)
 }
@@ -171,7 +199,7 @@ func TestService_Update_Existent(t *testing.T) {
 func TestService_Update_Section_Change(t *testing.T) {
 	api, service := callMock(t)
 
-	api.EXPECT().GetAll().Return([]domain.Section{
+	api.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{
 		{
 			Id:                 1,
 			SectionNumber:      1,
@@ -184,7 +212,7 @@ func TestService_Update_Section_Change(t *testing.T) {
 			MinimumCapacity:    15,
 		}}, nil)
 
-	api.EXPECT().Exists(1).Return(nil)
+	api.EXPECT().Exists(gomock.Any(), 1).Return(nil)
 
 	updatedSection := domain.Section{
 		Id:                 1,
@@ -198,9 +226,9 @@ func TestService_Update_Section_Change(t *testing.T) {
 		MinimumCapacity:    15,
 	}
 
-	api.EXPECT().Update(1, map[string]int{"section_number": 15}).Return(&updatedSection, nil)
+	api.EXPECT().Update(gomock.Any(), 1, domain.SectionPatch{SectionNumber: intPtr(15)}).Return(&updatedSection, nil)
 
-	res, err := service.Update(1, map[string]int{"section_number": 15})
+	res, err := service.Update(context.TODO(), 1, domain.SectionPatch{SectionNumber: intPtr(15)})
 	assert.Equal(t, res, &updatedSection)
 	assert.Nil(t, err)
 }
@@ -209,9 +237,9 @@ func TestService_Update_Non_Existent(t *testing.T) {
 	api, service := callMock(t)
 	expectedError := domain.ErrorNotFound{Id: 3}
 
-	api.EXPECT().Exists(3).Return(&expectedError)
+	api.EXPECT().Exists(gomock.Any(), 3).Return(&expectedError)
 
-	res, err := service.Update(3, map[string]int{"current_temperature": 8})
+	res, err := service.Update(context.TODO(), 3, domain.SectionPatch{CurrentTemperature: intPtr(8)})
 	assert.Nil(t, res)
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, expectedError.Error())
@@ -220,11 +248,12 @@ func TestService_Update_Non_Existent(t *testing.T) {
 func TestService_Update_Data_Error(t *testing.T) {
 	api, service := callMock(t)
 
-	api.EXPECT().Exists(1).Return(nil)
-	api.EXPECT().GetAll().Return([]domain.Section{}, errors.New("error"))
-	api.EXPECT().Update(1, map[string]int{"current_temperature": 8}).Return(nil, errors.New("error"))
+	api.EXPECT().Exists(gomock.Any(), 1).Return(nil)
+	api.EXPECT().GetById(gomock.Any(), 1).Return(&domain.Section{Id: 1, ProductTypeId: 1}, nil)
+	api.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{}, errors.New("error"))
+	api.EXPECT().Update(gomock.Any(), 1, domain.SectionPatch{CurrentTemperature: intPtr(8)}).Return(nil, errors.New("error"))
 
-	res, err := service.Update(1, map[string]int{"current_temperature": 8})
+	res, err := service.Update(context.TODO(), 1, domain.SectionPatch{CurrentTemperature: intPtr(8)})
 	assert.Nil(t, res)
 	assert.NotNil(t, err)
 }
@@ -232,25 +261,87 @@ func TestService_Update_Data_Error(t *testing.T) {
 func TestService_Update_Conflict(t *testing.T) {
 	api, service := callMock(t)
 
-	api.EXPECT().Exists(1).Return(nil)
-	api.EXPECT().GetAll().Return([]domain.Section{}, nil)
-	api.EXPECT().Update(1, map[string]int{"current_temperature": 8}).Return(nil, &domain.ErrorConflict{SectionNumber: 1})
+	api.EXPECT().Exists(gomock.Any(), 1).Return(nil)
+	api.EXPECT().GetById(gomock.Any(), 1).Return(&domain.Section{Id: 1, ProductTypeId: 1}, nil)
+	api.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{}, nil)
+	api.EXPECT().Update(gomock.Any(), 1, domain.SectionPatch{CurrentTemperature: intPtr(8)}).Return(nil, &domain.ErrorConflict{SectionNumber: 1})
 
-	res, err := service.Update(1, map[string]int{"current_temperature": 8})
+	res, err := service.Update(context.TODO(), 1, domain.SectionPatch{CurrentTemperature: intPtr(8)})
 	assert.Nil(t, res)
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, (&domain.ErrorConflict{SectionNumber: 1}).Error())
 }
 
+func TestService_Update_Product_Type_Not_Found(t *testing.T) {
+	api, productTypes, service := callMockWithProductTypes(t)
+	api.EXPECT().Exists(gomock.Any(), 1).Return(nil)
+	api.EXPECT().GetById(gomock.Any(), 1).Return(&domain.Section{Id: 1, ProductTypeId: 1}, nil)
+	productTypes.EXPECT().GetById(gomock.Any(), 4).Return(productTypesDomain.ProductType{}, &productTypesDomain.ErrorNotFound{Id: 4})
+
+	res, err := service.Update(context.TODO(), 1, domain.SectionPatch{ProductTypeId: intPtr(4)})
+	assert.Nil(t, res)
+	assert.EqualError(t, err, "product type 4 not found")
+}
+
+func TestService_Create_Temperature_Out_Of_Range(t *testing.T) {
+	api, productTypes, service := callMockWithProductTypes(t)
+	api.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{}, nil)
+	productTypes.EXPECT().GetById(gomock.Any(), 3).Return(productTypesDomain.ProductType{Id: 3, MinimumTemperature: -18, MaximumTemperature: -12}, nil)
+
+	res, err := service.Create(context.TODO(), 3, -15, -20, 150, 15, 250, 3, 3)
+	assert.Nil(t, res)
+	assert.EqualError(t, err, "minimum_temperature -20 is out of the range of product type 3, from -18 to -12")
+}
+
+func TestService_Update_Temperature_Out_Of_Range(t *testing.T) {
+	frozen := productTypesDomain.ProductType{Id: 3, MinimumTemperature: -18, MaximumTemperature: -12}
+
+	t.Run("Temperature patched", func(t *testing.T) {
+		api, productTypes, service := callMockWithProductTypes(t)
+		api.EXPECT().Exists(gomock.Any(), 1).Return(nil)
+		api.EXPECT().GetById(gomock.Any(), 1).Return(&domain.Section{Id: 1, CurrentTemperature: -15, MinimumTemperature: -16, ProductTypeId: 3}, nil)
+		productTypes.EXPECT().GetById(gomock.Any(), 3).Return(frozen, nil)
+
+		res, err := service.Update(context.TODO(), 1, domain.SectionPatch{CurrentTemperature: intPtr(4)})
+		assert.Nil(t, res)
+		errTemperature := &domain.ErrorTemperature{}
+		assert.ErrorAs(t, err, &errTemperature)
+		assert.Equal(t, "current_temperature", errTemperature.Field)
+	})
+
+	t.Run("Product type patched", func(t *testing.T) {
+		api, productTypes, service := callMockWithProductTypes(t)
+		api.EXPECT().Exists(gomock.Any(), 1).Return(nil)
+		api.EXPECT().GetById(gomock.Any(), 1).Return(&domain.Section{Id: 1, CurrentTemperature: 4, MinimumTemperature: 2, ProductTypeId: 1}, nil)
+		productTypes.EXPECT().GetById(gomock.Any(), 3).Return(frozen, nil)
+
+		_, err := service.Update(context.TODO(), 1, domain.SectionPatch{ProductTypeId: intPtr(3)})
+		assert.EqualError(t, err, "current_temperature 4 is out of the range of product type 3, from -18 to -12")
+	})
+
+	t.Run("Within range", func(t *testing.T) {
+		api, productTypes, service := callMockWithProductTypes(t)
+		api.EXPECT().Exists(gomock.Any(), 1).Return(nil)
+		api.EXPECT().GetById(gomock.Any(), 1).Return(&domain.Section{Id: 1, CurrentTemperature: 4, MinimumTemperature: 2, ProductTypeId: 1}, nil)
+		productTypes.EXPECT().GetById(gomock.Any(), 3).Return(frozen, nil)
+		patch := domain.SectionPatch{CurrentTemperature: intPtr(-14), MinimumTemperature: intPtr(-18), ProductTypeId: intPtr(3)}
+		api.EXPECT().Update(gomock.Any(), 1, patch).Return(&domain.Section{Id: 1}, nil)
+
+		_, err := service.Update(context.TODO(), 1, patch)
+		assert.NoError(t, err)
+	})
+}
+
 func TestService_Delete_Non_Existent(t *testing.T) {
 	api, service := callMock(t)
-	api.EXPECT().GetAll().Return([]domain.Section{}, nil)
+	api.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{}, nil)
 
 	expectedError := domain.ErrorNotFound{Id: 3}
 
-	api.EXPECT().Delete(3).Return(&expectedError)
+	api.EXPECT().Dependents(gomock.Any(), 3).Return([]dependents.Dependent{{Type: "product_batches"}}, nil)
+	api.EXPECT().Delete(gomock.Any(), 3).Return(&expectedError)
 
-	err := service.Delete(3)
+	err := service.Delete(context.TODO(), 3)
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, expectedError.Error())
 }
@@ -258,10 +349,64 @@ func TestService_Delete_Non_Existent(t *testing.T) {
 func TestService_Delete_OK(t *testing.T) {
 	api, service := callMock(t)
 
-	api.EXPECT().Delete(1).Return(nil)
+	api.EXPECT().Dependents(gomock.Any(), 1).Return([]dependents.Dependent{{Type: "product_batches"}}, nil)
+	api.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 
-	err := service.Delete(1)
+	err := service.Delete(context.TODO(), 1)
 	assert.Nil(t, err)
 
-	api.EXPECT().GetAll().Return([]domain.Section{}, nil)
+	api.EXPECT().GetAll(gomock.Any()).Return([]domain.Section{}, nil)
+}
+
+func TestService_Delete_Dependents(t *testing.T) {
+	api, service := callMock(t)
+
+	// Batches block the delete even when it cascades.
+	api.EXPECT().Dependents(gomock.Any(), 1).Times(2).Return([]dependents.Dependent{{Type: "product_batches", Count: 4}}, nil)
+	api.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.Delete(context.TODO(), 1)
+	assert.EqualError(t, err, "section 1 cannot be deleted, it still has 4 product_batches")
+
+	err = service.Delete(dependents.WithCascade(context.TODO()), 1)
+	var dependentsErr *dependents.Error
+	assert.ErrorAs(t, err, &dependentsErr)
+}
+
+func TestService_Delete_Dependents_Fail(t *testing.T) {
+	api, service := callMock(t)
+
+	api.EXPECT().Dependents(gomock.Any(), 1).Return(nil, errors.New("connection refused"))
+	api.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.Delete(context.TODO(), 1)
+	assert.EqualError(t, err, "connection refused")
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestService_Restore_OK(t *testing.T) {
+	api, service := callMock(t)
+
+	section := domain.Section{Id: 1, SectionNumber: 1}
+
+	api.EXPECT().Restore(gomock.Any(), 1).Return(nil)
+	api.EXPECT().GetById(gomock.Any(), 1).Return(&section, nil)
+
+	res, err := service.Restore(context.TODO(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, &section, res)
+}
+
+func TestService_Restore_Non_Existent(t *testing.T) {
+	api, service := callMock(t)
+	expectedError := domain.ErrorNotFound{Id: 3}
+
+	api.EXPECT().Restore(gomock.Any(), 3).Return(&expectedError)
+
+	res, err := service.Restore(context.TODO(), 3)
+	assert.Nil(t, res)
+	assert.EqualError(t, err, expectedError.Error())
 }
@@ -1,14 +1,24 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/bulk"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/patch"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,19 +27,19 @@ type SellerController struct {
 }
 
 type sqlCreateRequest struct {
-	Cid         int    `json:"cid" bindind:"required"`
-	CompanyName string `json:"company_name" bindind:"required"`
-	Address     string `json:"address" bindind:"required"`
-	Telephone   string `json:"telephone" bindind:"required"`
-	LocalityId  int `json:"locality_id" bindind:"required"`
+	Cid         int    `json:"cid" binding:"required,min=1"`
+	CompanyName string `json:"company_name" binding:"required"`
+	Address     string `json:"address" binding:"required"`
+	Telephone   string `json:"telephone" binding:"required,phone"`
+	LocalityId  int    `json:"locality_id" binding:"required,min=1"`
 }
 
 type sqlUpdateRequest struct {
-	Cid         int    `json:"cid"`
-	CompanyName string `json:"company_name"`
-	Address     string `json:"address"`
-	Telephone   string `json:"telephone"`
-	LocalityId  int `json:"locality_id"`
+	Cid         *int    `json:"cid" binding:"omitempty,min=1"`
+	CompanyName *string `json:"company_name" binding:"omitempty,min=1"`
+	Address     *string `json:"address" binding:"omitempty,min=1"`
+	Telephone   *string `json:"telephone" binding:"omitempty,phone"`
+	LocalityId  *int    `json:"locality_id" binding:"omitempty,min=1"`
 }
 
 func NewSeller(s domain.Service) *SellerController {
@@ -44,11 +54,18 @@ func NewSeller(s domain.Service) *SellerController {
 // @Tags Sellers
 // @Description get sellers
 // @Produce  json
+// @Param include_deleted query bool false "Include soft deleted sellers"
 // @Success 200 {array} sellers.Seller
+// @Failure 400 {object} string
 // @Failure 404 {object} string
 // @Router /api/v1/sellers [get]
 func (c *SellerController) GetAll() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if err := softdelete.FromQuery(ctx); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		s, err := c.service.GetAll(ctx)
 		if err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
@@ -69,6 +86,7 @@ func (c *SellerController) GetAll() gin.HandlerFunc {
 // @Accept  json
 // @Produce  json
 // @Param id   path int true "Seller ID"
+// @Param include_deleted query bool false "Include soft deleted sellers"
 // @Success 200 {object} sellers.Seller
 // @Failure 400 {object} string
 // @Failure 404 {object} string
@@ -82,6 +100,11 @@ func (c *SellerController) GetById() gin.HandlerFunc {
 			return
 		}
 
+		if err := softdelete.FromQuery(ctx); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		s, err := c.service.GetById(ctx, id)
 		if err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
@@ -89,10 +112,55 @@ func (c *SellerController) GetById() gin.HandlerFunc {
 			return
 		}
 
+		etag.Set(ctx, s.Version)
 		ctx.JSON(http.StatusOK, response.NewResponse(s))
 	}
 }
 
+// Report godoc
+// @Summary Seller performance report
+// @Tags Sellers
+// @Description product count, units in stock, units sold, revenue and margin of a seller
+// @Produce  json
+// @Param id   path int true "Seller ID"
+// @Param from query string false "First day of sales, YYYY-MM-DD"
+// @Param to query string false "Last day of sales, YYYY-MM-DD"
+// @Param period query string false "Grouping of sales: day, week or month (default)"
+// @Success 200 {object} domain.Report
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Router /api/v1/sellers/{id}/report [get]
+func (c *SellerController) Report() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+			return
+		}
+
+		dates, err := period.RangeFromQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		unit, err := period.UnitFromQuery(ctx, period.Month)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := c.service.GetReport(ctx, id, dates, unit)
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response.NewResponse(report))
+	}
+}
+
 // Create godoc
 // @Summary Create sellers
 // @Tags Sellers
@@ -108,7 +176,7 @@ func (c *SellerController) Create() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var req sqlCreateRequest
 		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			ctx.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 			return
 		}
 
@@ -145,22 +213,46 @@ func (c *SellerController) Create() gin.HandlerFunc {
 			return
 		}
 
+		etag.Set(ctx, s.Version)
 		ctx.JSON(http.StatusCreated, response.NewResponse(s))
 	}
 
 }
 
+// ImportSellers godoc
+// @Summary Import sellers
+// @Tags Sellers
+// @Description Create sellers in bulk from a CSV or NDJSON upload, reporting the outcome of each row
+// @Accept text/csv,application/x-ndjson,multipart/form-data
+// @Produce json
+// @Param atomic query bool false "Keep no seller when any row fails"
+// @Param file formData file false "CSV or NDJSON file, for multipart uploads"
+// @Success 200 {object} bulk.Report
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} bulk.Report
+// @Router /api/v1/sellers/import [post]
+func (c *SellerController) Import(runner transaction.Runner) gin.HandlerFunc {
+	return bulk.Handler(runner, func(ctx context.Context, req sqlCreateRequest) (int, error) {
+		seller, err := c.service.Create(ctx, req.Cid, req.CompanyName, req.Address, req.Telephone, req.LocalityId)
+		return seller.ID, err
+	})
+}
+
 // ListSellers godoc
 // @Summary Update seller
 // @Tags Sellers
-// @Description update seller
+// @Description update seller with a JSON merge patch (RFC 7396)
 // @Accept  json
 // @Produce  json
-// @Param product body request true "Seller to create"
+// @Param product body sqlUpdateRequest true "Fields to update"
 // @Param id   path int true "Seller ID"
+// @Param If-Match header string true "ETag of the seller being updated"
 // @Success 200 {object} sellers.Seller
 // @Failure 400 {object} string
 // @Failure 404 {object} string
+// @Failure 412 {object} response.Response
+// @Failure 422 {object} string
+// @Failure 428 {object} response.Response
 // @Router /api/v1/sellers/{id} [patch]
 func (s *SellerController) Update() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -170,18 +262,30 @@ func (s *SellerController) Update() gin.HandlerFunc {
 			return
 		}
 
+		if err := etag.FromIfMatch(ctx); err != nil {
+			ctx.JSON(etag.Status(err), response.DecodeError(err.Error()))
+			return
+		}
+
 		var req sqlUpdateRequest
-		if err := ctx.ShouldBindJSON(&req); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if err := patch.Bind(ctx, &req); err != nil {
+			ctx.JSON(patch.Status(err), validation.NewErrorResponse(err))
 			return
 		}
 
-		s, err := s.service.Update(ctx, id, req.Cid, req.CompanyName, req.Address, req.Telephone, req.LocalityId)
+		s, err := s.service.Update(ctx, id, domain.SellerPatch(req))
 		if err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+			if errors.Is(err, etag.ErrMismatch) {
+				ctx.JSON(http.StatusPreconditionFailed, response.DecodeError(err.Error()))
+				return
+			}
+
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		etag.Set(ctx, s.Version)
 		ctx.JSON(http.StatusOK, s)
 	}
 
@@ -190,9 +294,16 @@ func (s *SellerController) Update() gin.HandlerFunc {
 // ListSellers godoc
 // @Summary      Delete seller
 // @Tags         Sellers
-// @Description  delete seller
-// @Param        id   path      int  true  "Seller ID"
+// @Description  delete seller. Sellers that still have products are not deleted unless an admin passes cascade=true, which deletes the products too. A cascade is refused while those products have batches, records or order lines
+// @Param        id       path      int   true   "Seller ID"
+// @Param        cascade  query     bool  false  "Delete the seller's products as well"
+// @Param        If-Match header    string true   "ETag of the seller being deleted"
 // @Success      204  {object}  request
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      409  {object}  response.Response
+// @Failure      412  {object}  response.Response
+// @Failure      428  {object}  response.Response
 // @Router       /api/v1/sellers/{id} [delete]
 func (c *SellerController) Delete() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -203,9 +314,31 @@ func (c *SellerController) Delete() gin.HandlerFunc {
 			return
 		}
 
+		if err := dependents.FromQuery(ctx); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := etag.FromIfMatch(ctx); err != nil {
+			ctx.JSON(etag.Status(err), response.DecodeError(err.Error()))
+			return
+		}
+
 		err = c.service.Delete(ctx, id)
 		if err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+			if errors.Is(err, etag.ErrMismatch) {
+				ctx.JSON(http.StatusPreconditionFailed, response.DecodeError(err.Error()))
+				return
+			}
+
+			var dependentsErr *dependents.Error
+			if errors.As(err, &dependentsErr) {
+				ctx.JSON(http.StatusConflict, response.DecodeDependents(dependentsErr))
+				return
+			}
+
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
@@ -214,3 +347,33 @@ func (c *SellerController) Delete() gin.HandlerFunc {
 	}
 
 }
+
+// Restore godoc
+// @Summary      Restore seller
+// @Tags         Sellers
+// @Description  restore a deleted seller. Products deleted along with it by a cascade stay deleted, each one is restored on its own
+// @Produce      json
+// @Param        id   path      int  true  "Seller ID"
+// @Success      200  {object}  sellers.Seller
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Router       /api/v1/sellers/{id}/restore [post]
+func (c *SellerController) Restore() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+			return
+		}
+
+		s, err := c.service.Restore(ctx, id)
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		etag.Set(ctx, s.Version)
+		ctx.JSON(http.StatusOK, response.NewResponse(s))
+	}
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,12 @@ import (
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -19,6 +26,7 @@ import (
 const (
 	sellerRelativePath       = "/api/v1/sellers/"
 	sellerRelativePathWithId = "/api/v1/sellers/:id"
+	sellerRestorePath        = "/api/v1/sellers/:id/restore"
 	sellerId                 = "1"
 )
 
@@ -96,6 +104,7 @@ func TestSellersController_GetById(t *testing.T) {
 		Address:     "Melicidade",
 		Telephone:   "98787687",
 		LocalityId:  1,
+		Version:     3,
 	}
 
 	service, handler, api := callMockSeller(t)
@@ -113,6 +122,7 @@ func TestSellersController_GetById(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.Equal(t, sl.Cid, respExpect.Data.Cid)
+	assert.Equal(t, `"3"`, resp.Header().Get(etag.Header))
 }
 
 func TestSellersController_GetById_NOk(t *testing.T) {
@@ -234,6 +244,22 @@ func TestSellersController_Create_NoLocalityId(t *testing.T) {
 	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
 }
 
+var sellerPatch = domain.SellerPatch{
+	Cid:         intPtr(3),
+	CompanyName: stringPtr("Mercado Pago"),
+	Address:     stringPtr("Rua Bananeira, 130"),
+	Telephone:   stringPtr("34237123"),
+	LocalityId:  intPtr(1),
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
 func TestSellersController_Update(t *testing.T) {
 	sl := domain.Seller{
 		ID:          1,
@@ -247,11 +273,12 @@ func TestSellersController_Update(t *testing.T) {
 	service, handler, api := callMockSeller(t)
 	api.PATCH(sellerRelativePathWithId, handler.Update())
 
-	service.EXPECT().Update(gomock.Any(), gomock.Eq(1), 3, "Mercado Pago", "Rua Bananeira, 130", "34237123", 1).Return(sl, nil)
+	service.EXPECT().Update(gomock.Any(), gomock.Eq(1), sellerPatch).Return(sl, nil)
 
 	payload := `{"cid": 3, "company_name": "Mercado Pago", "address": "Rua Bananeira, 130", "telephone": "34237123", "locality_id": 1}`
 
 	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/sellers/%s", sellerId), bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
@@ -263,12 +290,13 @@ func TestSellersController_Update_NOk(t *testing.T) {
 	service, handler, api := callMockSeller(t)
 	api.PATCH(sellerRelativePathWithId, handler.Update())
 
-	service.EXPECT().Update(gomock.Any(), gomock.Eq(1), 3, "Mercado Pago", "Rua Bananeira, 130", "34237123", 1).
+	service.EXPECT().Update(gomock.Any(), gomock.Eq(1), sellerPatch).
 		Return(domain.Seller{}, errors.New("seller not found"))
 
 	payload := `{"cid": 3, "company_name": "Mercado Pago", "address": "Rua Bananeira, 130", "telephone": "34237123", "locality_id": 1}`
 
 	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/sellers/%s", sellerId), bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
@@ -283,6 +311,7 @@ func TestSellersController_Update_Badrequest(t *testing.T) {
 	payload := `{"cid": 3, "company_name": "Mercado Pago", "address": "Rua Bananeira, 130", "telephone": "34237123", "locality_id": 1}`
 
 	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/sellers/%s", "larousse"), bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
@@ -290,10 +319,49 @@ func TestSellersController_Update_Badrequest(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
 
+func TestSellersController_Update_UnknownField(t *testing.T) {
+	_, handler, api := callMockSeller(t)
+	api.PATCH(sellerRelativePathWithId, handler.Update())
+
+	payload := `{"cid": 3, "name": "Mercado Pago"}`
+
+	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/sellers/%s", sellerId), bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestSellersController_Update_Precondition(t *testing.T) {
+	service, handler, api := callMockSeller(t)
+	api.PATCH(sellerRelativePathWithId, handler.Update())
+
+	payload := `{"address": "Rua Bananeira, 130"}`
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/sellers/1", bytes.NewBuffer([]byte(payload)))
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+
+	service.EXPECT().Update(gomock.Any(), 1, domain.SellerPatch{Address: stringPtr("Rua Bananeira, 130")}).
+		Return(domain.Seller{}, etag.ErrMismatch)
+
+	req = httptest.NewRequest(http.MethodPatch, "/api/v1/sellers/1", bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp = httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+}
+
 func TestSellersController_Delete_Ok(t *testing.T) {
 	service, handler, api := callMockSeller(t)
 
 	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/sellers/%s", sellerId), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 
 	service.EXPECT().Delete(gomock.Any(), gomock.Eq(1)).Return(nil)
@@ -312,6 +380,7 @@ func TestSellersController_Delete_NOk(t *testing.T) {
 	service.EXPECT().Delete(gomock.Any(), gomock.Eq(1)).Return(errors.New("error 404"))
 
 	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/sellers/%s", sellerId), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 
 	api.ServeHTTP(resp, req)
@@ -324,8 +393,175 @@ func TestSellersController_Delete_BadRequest(t *testing.T) {
 	api.DELETE(sellerRelativePathWithId, handler.Delete())
 
 	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/sellers/%s", "enter-id"), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
+
+func TestSellersController_Delete_Precondition(t *testing.T) {
+	service, handler, api := callMockSeller(t)
+	api.DELETE(sellerRelativePathWithId, handler.Delete())
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/sellers/1", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+
+	service.EXPECT().Delete(gomock.Any(), 1).Return(etag.ErrMismatch)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/sellers/1", nil)
+	req.Header.Set(etag.IfMatchHeader, `"2"`)
+	resp = httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+}
+
+func TestSellersController_Delete_Conflict(t *testing.T) {
+	service, handler, api := callMockSeller(t)
+	api.DELETE(sellerRelativePathWithId, handler.Delete())
+
+	service.EXPECT().Delete(gomock.Any(), gomock.Eq(1)).Return(&dependents.Error{
+		Entity:     "seller",
+		Id:         1,
+		Dependents: []dependents.Dependent{{Type: "products", Count: 2}},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/sellers/%s", sellerId), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	var body response.Response
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, []dependents.Dependent{{Type: "products", Count: 2}}, body.Dependents)
+}
+
+func TestSellersController_Delete_Cascade(t *testing.T) {
+	service, handler, api := callMockSeller(t)
+	api.DELETE(sellerRelativePathWithId, handler.Delete())
+
+	service.EXPECT().Delete(gomock.Any(), gomock.Eq(1)).DoAndReturn(func(ctx context.Context, id int) error {
+		assert.True(t, dependents.Cascade(ctx))
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/sellers/%s?cascade=true", sellerId), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+}
+
+func TestSellersController_GetById_IncludeDeleted(t *testing.T) {
+	service, handler, api := callMockSeller(t)
+	api.GET(sellerRelativePathWithId, handler.GetById())
+	service.EXPECT().GetById(gomock.Any(), gomock.Eq(1)).DoAndReturn(func(ctx context.Context, id int) (domain.Seller, error) {
+		assert.True(t, softdelete.IncludeDeleted(ctx))
+		return domain.Seller{ID: id}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/sellers/1?include_deleted=true", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestSellersController_GetById_InvalidIncludeDeleted(t *testing.T) {
+	_, handler, api := callMockSeller(t)
+	api.GET(sellerRelativePathWithId, handler.GetById())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/sellers/1?include_deleted=yes", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestSellersController_Restore_Ok(t *testing.T) {
+	sl := domain.Seller{ID: 1, Cid: 20, CompanyName: "Mercado Livre"}
+
+	service, handler, api := callMockSeller(t)
+	api.POST(sellerRestorePath, handler.Restore())
+	service.EXPECT().Restore(gomock.Any(), gomock.Eq(1)).Return(sl, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/sellers/1/restore", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	respExpect := struct{ Data domain.Seller }{}
+	_ = json.Unmarshal(resp.Body.Bytes(), &respExpect)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, sl.Cid, respExpect.Data.Cid)
+}
+
+func TestSellersController_Restore_NOk(t *testing.T) {
+	service, handler, api := callMockSeller(t)
+	api.POST(sellerRestorePath, handler.Restore())
+	service.EXPECT().Restore(gomock.Any(), gomock.Eq(1)).Return(domain.Seller{}, errors.New("deleted seller 1 not found"))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/sellers/1/restore", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestSellersController_Report(t *testing.T) {
+	const path = "/api/v1/sellers/:id/report"
+
+	t.Run("OK", func(t *testing.T) {
+		service, handler, api := callMockSeller(t)
+		api.GET(path, handler.Report())
+
+		dates := period.Range{From: date.New(2022, 7, 1), To: date.New(2022, 7, 31)}
+		service.EXPECT().GetReport(gomock.Any(), 1, dates, period.Day).
+			Return(domain.Report{SellerId: 1, Range: dates, Period: period.Day, UnitsSold: 4, Revenue: 40}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/sellers/1/report?from=2022-07-01&to=2022-07-31&period=day", nil)
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		body := struct {
+			Data domain.Report `json:"data"`
+		}{}
+		json.Unmarshal(resp.Body.Bytes(), &body)
+		assert.Equal(t, 4, body.Data.UnitsSold)
+		assert.Equal(t, dates, body.Data.Range)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		service, handler, api := callMockSeller(t)
+		api.GET(path, handler.Report())
+
+		service.EXPECT().GetReport(gomock.Any(), 1, period.Range{}, period.Month).
+			Return(domain.Report{}, errors.New("seller 1 not found"))
+
+		resp := httptest.NewRecorder()
+		api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/sellers/1/report", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	for _, query := range []string{"xpto/report", "1/report?to=31-07-2022", "1/report?period=quarter"} {
+		t.Run("Bad request "+query, func(t *testing.T) {
+			_, handler, api := callMockSeller(t)
+			api.GET(path, handler.Report())
+
+			resp := httptest.NewRecorder()
+			api.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, sellerRelativePath+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
@@ -2,15 +2,65 @@ package domain
 
 import (
 	"context"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 )
 
 type Seller struct {
-	ID          int    `json:"id"`
-	Cid         int    `json:"cid"`
-	CompanyName string `json:"company_name"`
-	Address     string `json:"address"`
-	Telephone   string `json:"telephone"`
-	LocalityId  int `json:"locality_id"`
+	ID          int           `json:"id"`
+	Cid         int           `json:"cid"`
+	CompanyName string        `json:"company_name"`
+	Address     string        `json:"address"`
+	Telephone   string        `json:"telephone"`
+	LocalityId  int           `json:"locality_id"`
+	DeletedAt   date.DateTime `json:"deleted_at"`
+	Version     int           `json:"version"`
+}
+
+// SellerPatch holds the fields of a partial update. Nil fields are left
+// untouched.
+type SellerPatch struct {
+	Cid         *int    `json:"cid"`
+	CompanyName *string `json:"company_name"`
+	Address     *string `json:"address"`
+	Telephone   *string `json:"telephone"`
+	LocalityId  *int    `json:"locality_id"`
+}
+
+// Sale is an order line of a product of the seller. SalePrice is the unit
+// price the line was priced at, PurchasePrice the one of the product record
+// it was priced from. Orders made before orders had lines sell one unit at
+// the prices of their product record.
+type Sale struct {
+	OrderId       int
+	OrderDate     date.Date
+	PurchasePrice float64
+	SalePrice     float64
+	Quantity      int
+}
+
+// Report is the performance of a seller. Products and stock are counted as
+// they are now, sales as the order lines placed within the range. The margin
+// is the sale price less the purchase price of every unit sold.
+type Report struct {
+	SellerId int `json:"seller_id"`
+	period.Range
+	Period        period.Unit    `json:"period"`
+	ProductsCount int            `json:"products_count"`
+	StockUnits    int            `json:"stock_units"`
+	UnitsSold     int            `json:"units_sold"`
+	Revenue       float64        `json:"revenue"`
+	Margin        float64        `json:"margin"`
+	Periods       []ReportPeriod `json:"periods"`
+}
+
+type ReportPeriod struct {
+	Period    string  `json:"period"`
+	UnitsSold int     `json:"units_sold"`
+	Revenue   float64 `json:"revenue"`
+	Margin    float64 `json:"margin"`
 }
 
 //go:generate mockgen -source=./domain.go -destination=./mock/domain_mock.go
@@ -18,14 +68,21 @@ type Repository interface {
 	GetAll(ctx context.Context) ([]Seller, error)
 	GetById(ctx context.Context, id int) (Seller, error)
 	Create(ctx context.Context, cid int, commpanyName, address, telephone string, localityId int) (Seller, error)
-	Update(ctx context.Context, id, cid int, commpanyName, address, telephone string, localityId int) (Seller, error)
+	Update(ctx context.Context, id int, arg SellerPatch) (Seller, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Dependents(ctx context.Context, id int) ([]dependents.Dependent, error)
+	CascadeDependents(ctx context.Context, id int) ([]dependents.Dependent, error)
+	GetStock(ctx context.Context, id int) (products int, units int, err error)
+	GetSales(ctx context.Context, id int, dates period.Range) ([]Sale, error)
 }
 
 type Service interface {
 	GetAll(ctx context.Context) ([]Seller, error)
 	GetById(ctx context.Context, id int) (Seller, error)
 	Create(ctx context.Context, cid int, commpanyName, address, telephone string, localityId int) (Seller, error)
-	Update(ctx context.Context, id, cid int, companyname, address, telephone string, localityId int) (Seller, error)
+	Update(ctx context.Context, id int, arg SellerPatch) (Seller, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) (Seller, error)
+	GetReport(ctx context.Context, id int, dates period.Range, unit period.Unit) (Report, error)
 }
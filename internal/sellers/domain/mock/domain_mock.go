@@ -9,6 +9,8 @@ import (
 	reflect "reflect"
 
 	domain "github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain"
+	dependents "github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	period "github.com/douglmendes/mercado-fresco-round-go/pkg/period"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -35,6 +37,21 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CascadeDependents mocks base method.
+func (m *MockRepository) CascadeDependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CascadeDependents", ctx, id)
+	ret0, _ := ret[0].([]dependents.Dependent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CascadeDependents indicates an expected call of CascadeDependents.
+func (mr *MockRepositoryMockRecorder) CascadeDependents(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CascadeDependents", reflect.TypeOf((*MockRepository)(nil).CascadeDependents), ctx, id)
+}
+
 // Create mocks base method.
 func (m *MockRepository) Create(ctx context.Context, cid int, commpanyName, address, telephone string, localityId int) (domain.Seller, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +81,21 @@ func (mr *MockRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id)
 }
 
+// Dependents mocks base method.
+func (m *MockRepository) Dependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dependents", ctx, id)
+	ret0, _ := ret[0].([]dependents.Dependent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dependents indicates an expected call of Dependents.
+func (mr *MockRepositoryMockRecorder) Dependents(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dependents", reflect.TypeOf((*MockRepository)(nil).Dependents), ctx, id)
+}
+
 // GetAll mocks base method.
 func (m *MockRepository) GetAll(ctx context.Context) ([]domain.Seller, error) {
 	m.ctrl.T.Helper()
@@ -94,19 +126,64 @@ func (mr *MockRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRepository)(nil).GetById), ctx, id)
 }
 
+// GetSales mocks base method.
+func (m *MockRepository) GetSales(ctx context.Context, id int, dates period.Range) ([]domain.Sale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSales", ctx, id, dates)
+	ret0, _ := ret[0].([]domain.Sale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSales indicates an expected call of GetSales.
+func (mr *MockRepositoryMockRecorder) GetSales(ctx, id, dates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSales", reflect.TypeOf((*MockRepository)(nil).GetSales), ctx, id, dates)
+}
+
+// GetStock mocks base method.
+func (m *MockRepository) GetStock(ctx context.Context, id int) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStock", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockRepositoryMockRecorder) GetStock(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockRepository)(nil).GetStock), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockRepository) Restore(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepository)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockRepository) Update(ctx context.Context, id, cid int, commpanyName, address, telephone string, localityId int) (domain.Seller, error) {
+func (m *MockRepository) Update(ctx context.Context, id int, arg domain.SellerPatch) (domain.Seller, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, cid, commpanyName, address, telephone, localityId)
+	ret := m.ctrl.Call(m, "Update", ctx, id, arg)
 	ret0, _ := ret[0].(domain.Seller)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(ctx, id, cid, commpanyName, address, telephone, localityId interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, id, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, id, cid, commpanyName, address, telephone, localityId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, id, arg)
 }
 
 // MockService is a mock of Service interface.
@@ -191,17 +268,47 @@ func (mr *MockServiceMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockService)(nil).GetById), ctx, id)
 }
 
+// GetReport mocks base method.
+func (m *MockService) GetReport(ctx context.Context, id int, dates period.Range, unit period.Unit) (domain.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReport", ctx, id, dates, unit)
+	ret0, _ := ret[0].(domain.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReport indicates an expected call of GetReport.
+func (mr *MockServiceMockRecorder) GetReport(ctx, id, dates, unit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockService)(nil).GetReport), ctx, id, dates, unit)
+}
+
+// Restore mocks base method.
+func (m *MockService) Restore(ctx context.Context, id int) (domain.Seller, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(domain.Seller)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockServiceMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockService) Update(ctx context.Context, id, cid int, companyname, address, telephone string, localityId int) (domain.Seller, error) {
+func (m *MockService) Update(ctx context.Context, id int, arg domain.SellerPatch) (domain.Seller, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, cid, companyname, address, telephone, localityId)
+	ret := m.ctrl.Call(m, "Update", ctx, id, arg)
 	ret0, _ := ret[0].(domain.Seller)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockServiceMockRecorder) Update(ctx, id, cid, companyname, address, telephone, localityId interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Update(ctx, id, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, id, cid, companyname, address, telephone, localityId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, id, arg)
 }
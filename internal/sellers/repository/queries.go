@@ -1,10 +1,27 @@
 package repository
 
 const (
-	queryGetAll  = "SELECT id, cid, company_name, address, telephone, locality_id FROM sellers"
-	queryGetById = "SELECT id, cid, company_name, address, telephone, locality_id FROM sellers where id = ?"
-	queryCreate  = "INSERT INTO sellers (cid, company_name, address, telephone, locality_id) VALUES (?, ?, ?, ?, ?)"
-	queryUpdate  = "UPDATE sellers SET cid = ?, company_name = ?, address = ?, telephone = ?, locality_id = ? WHERE id = ?"
-	queryDelete  = "DELETE FROM sellers WHERE id = ?"
+	querySelect             = "SELECT id, cid, company_name, address, telephone, locality_id, deleted_at, version FROM sellers"
+	queryGetAll             = querySelect + " WHERE deleted_at IS NULL"
+	queryGetAllWithDeleted  = querySelect
+	queryGetById            = querySelect + " where id = ? AND deleted_at IS NULL"
+	queryGetByIdWithDeleted = querySelect + " where id = ?"
+	queryCreate             = "INSERT INTO sellers (cid, company_name, address, telephone, locality_id) VALUES (?, ?, ?, ?, ?)"
+	queryUpdate             = "UPDATE sellers SET cid = ?, company_name = ?, address = ?, telephone = ?, locality_id = ?, version = version + 1 WHERE id = ? AND version = ?"
+	queryDelete             = "UPDATE sellers SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL"
+	queryRestore            = "UPDATE sellers SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL"
+	queryCountProducts      = "SELECT COUNT(*) FROM products WHERE seller_id = ? AND deleted_at IS NULL"
+	// queryCountCascadeBlockers counts the rows that keep the live products of
+	// a seller from being deleted along with it.
+	queryCountCascadeBlockers = "SELECT (SELECT COUNT(*) FROM product_batches b INNER JOIN products p ON p.id = b.product_id WHERE p.seller_id = ? AND p.deleted_at IS NULL), (SELECT COUNT(*) FROM product_records r INNER JOIN products p ON p.id = r.product_id WHERE p.seller_id = ? AND p.deleted_at IS NULL), (SELECT COUNT(*) FROM purchase_order_lines l INNER JOIN products p ON p.id = l.product_id WHERE p.seller_id = ? AND p.deleted_at IS NULL)"
+	queryDeleteProducts       = "UPDATE products SET deleted_at = ?, version = version + 1 WHERE seller_id = ? AND deleted_at IS NULL"
+	queryGetStock             = "SELECT COUNT(DISTINCT p.id), COALESCE(SUM(b.current_quantity), 0) FROM products p LEFT JOIN product_batches b ON b.product_id = p.id WHERE p.seller_id = ? AND p.deleted_at IS NULL"
+	// queryGetSales lists the order lines of products of a seller, along with
+	// the orders made before orders had lines, as one unit of their product
+	// record. Both selects are completed with the date range conditions, and
+	// the union with queryGetSalesOrder.
+	queryGetSales       = "SELECT o.id, o.order_date, r.purchase_price, l.unit_price, l.quantity FROM purchase_orders o INNER JOIN purchase_order_lines l ON l.purchase_order_id = o.id INNER JOIN product_records r ON l.product_record_id = r.id INNER JOIN products p ON r.product_id = p.id WHERE p.seller_id = ?"
+	queryGetLegacySales = " UNION ALL SELECT o.id, o.order_date, r.purchase_price, r.sale_price, 1 FROM purchase_orders o INNER JOIN product_records r ON o.product_record_id = r.id INNER JOIN products p ON r.product_id = p.id WHERE p.seller_id = ? AND NOT EXISTS (SELECT 1 FROM purchase_order_lines l WHERE l.purchase_order_id = o.id)"
+	queryGetSalesOrder  = " ORDER BY order_date, id"
 	// queryGetLocality = "SELECT id, locality_name, province_name, country_name FROM localities WHERE id = ?"
-)
\ No newline at end of file
+)
@@ -5,11 +5,16 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
 
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
 )
 
 type repository struct {
@@ -18,7 +23,12 @@ type repository struct {
 
 func (r *repository) GetAll(ctx context.Context) ([]domain.Seller, error) {
 	var sellers []domain.Seller
-	rows, err := r.db.QueryContext(ctx, queryGetAll)
+	query := queryGetAll
+	if softdelete.IncludeDeleted(ctx) {
+		query = queryGetAllWithDeleted
+	}
+
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return []domain.Seller{}, err
@@ -36,6 +46,8 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Seller, error) {
 			&seller.Address,
 			&seller.Telephone,
 			&seller.LocalityId,
+			&seller.DeletedAt,
+			&seller.Version,
 		); err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
 			return sellers, err
@@ -48,7 +60,12 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Seller, error) {
 
 func (r *repository) GetById(ctx context.Context, id int) (domain.Seller, error) {
 
-	row := r.db.QueryRowContext(ctx, queryGetById, id)
+	query := queryGetById
+	if softdelete.IncludeDeleted(ctx) {
+		query = queryGetByIdWithDeleted
+	}
+
+	row := transaction.From(ctx, r.db).QueryRowContext(ctx, query, id)
 
 	seller := domain.Seller{}
 
@@ -59,6 +76,8 @@ func (r *repository) GetById(ctx context.Context, id int) (domain.Seller, error)
 		&seller.Address,
 		&seller.Telephone,
 		&seller.LocalityId,
+		&seller.DeletedAt,
+		&seller.Version,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -82,9 +101,10 @@ func (r *repository) Create(ctx context.Context, cid int, commpanyName, address,
 		Address:     address,
 		Telephone:   telephone,
 		LocalityId:  localityId,
+		Version:     1,
 	}
 
-	result, err := r.db.ExecContext(
+	result, err := transaction.From(ctx, r.db).ExecContext(
 		ctx,
 		queryCreate,
 		cid,
@@ -110,7 +130,7 @@ func (r *repository) Create(ctx context.Context, cid int, commpanyName, address,
 	return seller, nil
 }
 
-func (r *repository) Update(ctx context.Context, id, cid int, commpanyName, address, telephone string, localityId int) (domain.Seller, error) {
+func (r *repository) Update(ctx context.Context, id int, arg domain.SellerPatch) (domain.Seller, error) {
 
 	seller, err := r.GetById(ctx, id)
 	if err != nil {
@@ -118,23 +138,27 @@ func (r *repository) Update(ctx context.Context, id, cid int, commpanyName, addr
 		return domain.Seller{}, fmt.Errorf("seller %d not found", id)
 	}
 
-	if cid != 0 {
-		seller.Cid = cid
+	if err := etag.Check(ctx, seller.Version); err != nil {
+		return domain.Seller{}, err
 	}
-	if commpanyName != "" {
-		seller.CompanyName = commpanyName
+
+	if arg.Cid != nil {
+		seller.Cid = *arg.Cid
+	}
+	if arg.CompanyName != nil {
+		seller.CompanyName = *arg.CompanyName
 	}
-	if address != "" {
-		seller.Address = address
+	if arg.Address != nil {
+		seller.Address = *arg.Address
 	}
-	if telephone != "" {
-		seller.Telephone = telephone
+	if arg.Telephone != nil {
+		seller.Telephone = *arg.Telephone
 	}
-	if localityId != 0 {
-		seller.LocalityId = localityId
+	if arg.LocalityId != nil {
+		seller.LocalityId = *arg.LocalityId
 	}
 
-	result, err := r.db.ExecContext(
+	result, err := transaction.From(ctx, r.db).ExecContext(
 		ctx,
 		queryUpdate,
 		seller.Cid,
@@ -143,6 +167,7 @@ func (r *repository) Update(ctx context.Context, id, cid int, commpanyName, addr
 		seller.Telephone,
 		seller.LocalityId,
 		id,
+		seller.Version,
 	)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
@@ -154,30 +179,149 @@ func (r *repository) Update(ctx context.Context, id, cid int, commpanyName, addr
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return domain.Seller{}, err
 	}
-	log.Println(affectedRows)
+
+	// Someone else changed the seller between the read and the write.
+	if affectedRows == 0 {
+		return domain.Seller{}, etag.ErrMismatch
+	}
+
+	seller.Version++
 
 	return seller, nil
 }
 
 func (r *repository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.ExecContext(ctx, queryDelete, id)
+	deletedAt := date.Now()
+
+	if dependents.Cascade(ctx) {
+		if _, err := tx.ExecContext(ctx, queryDeleteProducts, deletedAt, id); err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			return err
+		}
+	}
+
+	query, args := queryDelete, []interface{}{deletedAt, id}
+	version, versioned := etag.Version(ctx)
+	if versioned {
+		query, args = queryDelete+etag.Condition, append(args, version)
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return err
 	}
 
 	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
 
 	if affectedRows == 0 {
+		// The seller is there, just not in the version the request expects.
+		if versioned {
+			if _, err := r.GetById(ctx, id); err == nil {
+				return etag.ErrMismatch
+			}
+		}
 		logger.Error(ctx, store.GetPathWithLine(), "seller not found")
 		return fmt.Errorf("seller %d not found", id)
 	}
 
+	return tx.Commit()
+}
+
+// Dependents counts the live products of the seller.
+func (r *repository) Dependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	var products int
+	if err := transaction.From(ctx, r.db).QueryRowContext(ctx, queryCountProducts, id).Scan(&products); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	return []dependents.Dependent{{Type: "products", Count: products}}, nil
+}
+
+// CascadeDependents counts the batches, records and order lines of the live
+// products of the seller. They keep those products from being deleted, so
+// they keep a cascade from taking them.
+func (r *repository) CascadeDependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	var batches, records, lines int
+	if err := transaction.From(ctx, r.db).QueryRowContext(ctx, queryCountCascadeBlockers, id, id, id).Scan(&batches, &records, &lines); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	return []dependents.Dependent{
+		{Type: "product_batches", Count: batches},
+		{Type: "product_records", Count: records},
+		{Type: "purchase_order_lines", Count: lines},
+	}, nil
+}
+
+// GetStock counts the live products of the seller and the units left in
+// their batches.
+func (r *repository) GetStock(ctx context.Context, id int) (int, int, error) {
+	var products, units int
+	if err := transaction.From(ctx, r.db).QueryRowContext(ctx, queryGetStock, id).Scan(&products, &units); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return 0, 0, err
+	}
+
+	return products, units, nil
+}
+
+// GetSales lists the order lines of products of the seller placed within
+// dates, oldest first.
+func (r *repository) GetSales(ctx context.Context, id int, dates period.Range) ([]domain.Sale, error) {
+	conditions, args := dates.Where("o.order_date")
+	query := queryGetSales + conditions + queryGetLegacySales + conditions + queryGetSalesOrder
+	args = append(append(append([]interface{}{id}, args...), id), args...)
+
+	rows, err := transaction.From(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := []domain.Sale{}
+	for rows.Next() {
+		var sale domain.Sale
+		if err := rows.Scan(&sale.OrderId, &sale.OrderDate, &sale.PurchasePrice, &sale.SalePrice, &sale.Quantity); err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			return nil, err
+		}
+
+		sales = append(sales, sale)
+	}
+	return sales, rows.Err()
+}
+
+func (r *repository) Restore(ctx context.Context, id int) error {
+	result, err := transaction.From(ctx, r.db).ExecContext(ctx, queryRestore, id)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
 
+	if affectedRows == 0 {
+		logger.Error(ctx, store.GetPathWithLine(), "deleted seller not found")
+		return fmt.Errorf("deleted seller %d not found", id)
+	}
+
 	return nil
 }
 
@@ -6,9 +6,15 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,7 +43,7 @@ func TestRepository_GetAll_Ok(t *testing.T) {
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "cid", "company_name", "address", "telephone", "locality_id",
+		"id", "cid", "company_name", "address", "telephone", "locality_id", "deleted_at", "version",
 	}).AddRow(
 		sellerMock[0].ID,
 		sellerMock[0].Cid,
@@ -45,6 +51,8 @@ func TestRepository_GetAll_Ok(t *testing.T) {
 		sellerMock[0].Address,
 		sellerMock[0].Telephone,
 		sellerMock[0].LocalityId,
+		nil,
+		1,
 	).AddRow(
 		sellerMock[1].ID,
 		sellerMock[1].Cid,
@@ -52,9 +60,11 @@ func TestRepository_GetAll_Ok(t *testing.T) {
 		sellerMock[1].Address,
 		sellerMock[1].Telephone,
 		sellerMock[0].LocalityId,
+		nil,
+		1,
 	)
 
-	mock.ExpectQuery(queryGetAll).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAll)).WillReturnRows(rows)
 
 	slRepo := NewRepository(db)
 
@@ -72,7 +82,7 @@ func TestRepository_GetAll_NOk(t *testing.T) {
 
 	sellerMock := []domain.Seller{}
 
-	mock.ExpectQuery(queryGetAll).WillReturnError(errors.New("erro"))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetAll)).WillReturnError(errors.New("erro"))
 
 	slRepo := NewRepository(db)
 
@@ -96,7 +106,7 @@ func TestRepository_GetById_Ok(t *testing.T) {
 	}
 
 	row := sqlmock.NewRows([]string{
-		"id", "cid", "company_name", "address", "telephone", "locality_id",
+		"id", "cid", "company_name", "address", "telephone", "locality_id", "deleted_at", "version",
 	}).AddRow(
 		sellerMock.ID,
 		sellerMock.Cid,
@@ -104,9 +114,11 @@ func TestRepository_GetById_Ok(t *testing.T) {
 		sellerMock.Address,
 		sellerMock.Telephone,
 		sellerMock.LocalityId,
+		nil,
+		1,
 	)
 
-	mock.ExpectQuery(queryGetById).WillReturnRows(row)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(row)
 
 	slRepo := NewRepository(db)
 
@@ -121,7 +133,7 @@ func TestRepository_GetById_NOk(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(queryGetById).WillReturnError(errors.New("error"))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnError(errors.New("error"))
 
 	slRepo := NewRepository(db)
 
@@ -136,7 +148,7 @@ func TestRepository_GetById_NoId(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(queryGetById).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnError(sql.ErrNoRows)
 
 	slRepo := NewRepository(db)
 
@@ -192,6 +204,15 @@ func TestRepository_Create_NOk(t *testing.T) {
 	assert.Error(t, err)
 }
 
+var sellerPatch = domain.SellerPatch{
+	Address:   stringPtr("Rua Gaspar, 111"),
+	Telephone: stringPtr("23222222"),
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
 func TestRepository_Update_Ok(t *testing.T) {
 
 	db, mock, err := sqlmock.New()
@@ -214,10 +235,11 @@ func TestRepository_Update_Ok(t *testing.T) {
 		Address:     "Rua Gaspar, 111",
 		Telephone:   "23222222",
 		LocalityId:  1,
+		Version:     2,
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "cid", "company_name", "address", "telephone", "locality_id",
+		"id", "cid", "company_name", "address", "telephone", "locality_id", "deleted_at", "version",
 	}).AddRow(
 		sellerMock.ID,
 		sellerMock.Cid,
@@ -225,9 +247,11 @@ func TestRepository_Update_Ok(t *testing.T) {
 		sellerMock.Address,
 		sellerMock.Telephone,
 		sellerMock.LocalityId,
+		nil,
+		1,
 	)
 
-	mock.ExpectQuery(queryGetById).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(rows)
 
 	mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).WithArgs(
 		sellerMockUpdated.Cid,
@@ -236,11 +260,12 @@ func TestRepository_Update_Ok(t *testing.T) {
 		sellerMockUpdated.Telephone,
 		sellerMockUpdated.LocalityId,
 		1,
+		1,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	slRepo := NewRepository(db)
 
-	result, err := slRepo.Update(context.TODO(), 1, 44, "Gasp", "Rua Gaspar, 111", "23222222", 1)
+	result, err := slRepo.Update(etag.WithVersion(context.TODO(), 1), 1, sellerPatch)
 	assert.NoError(t, err)
 	assert.Equal(t, sellerMockUpdated, result)
 }
@@ -255,7 +280,7 @@ func TestRepository_Update_NOk(t *testing.T) {
 
 	slRepo := NewRepository(db)
 
-	_, err = slRepo.Update(context.TODO(), 1, 44, "Gasp", "Rua Gaspar, 111", "23222222", 1)
+	_, err = slRepo.Update(context.TODO(), 1, sellerPatch)
 	assert.Error(t, err)
 }
 
@@ -281,10 +306,11 @@ func TestRepository_Update_ExecContextError(t *testing.T) {
 		Address:     "Rua Gaspar, 101",
 		Telephone:   "23225422",
 		LocalityId:  1,
+		Version:     1,
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "cid", "company_name", "address", "telephone", "locality_id",
+		"id", "cid", "company_name", "address", "telephone", "locality_id", "deleted_at", "version",
 	}).AddRow(
 		sellerMock.ID,
 		sellerMock.Cid,
@@ -292,9 +318,11 @@ func TestRepository_Update_ExecContextError(t *testing.T) {
 		sellerMock.Address,
 		sellerMock.Telephone,
 		sellerMock.LocalityId,
+		nil,
+		1,
 	)
 
-	mock.ExpectQuery(queryGetById).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(rows)
 
 	mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).WithArgs(
 		sellerMockUpdated.CompanyName,
@@ -306,7 +334,7 @@ func TestRepository_Update_ExecContextError(t *testing.T) {
 
 	slRepo := NewRepository(db)
 
-	_, err = slRepo.Update(context.TODO(), 1, 44, "Gasp", "Rua Gaspar, 111", "23222222", 1)
+	_, err = slRepo.Update(context.TODO(), 1, sellerPatch)
 	assert.Error(t, err)
 }
 
@@ -315,9 +343,12 @@ func TestRepository_Delete_Ok(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
+	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(queryDelete)).WithArgs(
+		sqlmock.AnyArg(),
 		1,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	slRepo := NewRepository(db)
 
@@ -330,7 +361,9 @@ func TestRepository_Delete_NOk(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(queryDelete).WillReturnError(errors.New("error"))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(queryDelete)).WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
 
 	slRepo := NewRepository(db)
 
@@ -343,12 +376,196 @@ func TestRepository_Delete_NoId(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
+	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(queryDelete)).WithArgs(
+		sqlmock.AnyArg(),
 		1,
 	).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
 
 	slRepo := NewRepository(db)
 
 	err = slRepo.Delete(context.TODO(), 1)
 	assert.Error(t, err)
 }
+
+func TestRepository_Update_Version_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "cid", "company_name", "address", "telephone", "locality_id", "deleted_at", "version"}
+	slRepo := NewRepository(db)
+
+	// The client read an older version.
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 44, "Gasp", "Rua Gaspar, 101", "23225422", 1, nil, 3))
+
+	_, err = slRepo.Update(etag.WithVersion(context.TODO(), 2), 1, sellerPatch)
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+
+	// Someone else wrote the seller between the read and the write.
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 44, "Gasp", "Rua Gaspar, 101", "23225422", 1, nil, 3))
+	mock.ExpectExec(regexp.QuoteMeta(queryUpdate)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = slRepo.Update(etag.WithVersion(context.TODO(), 3), 1, sellerPatch)
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Delete_Version_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(queryDelete+etag.Condition)).
+		WithArgs(sqlmock.AnyArg(), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetById)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "cid", "company_name", "address", "telephone", "locality_id", "deleted_at", "version"}).AddRow(1, 44, "Gasp", "Rua Gaspar, 101", "23225422", 1, nil, 3))
+	mock.ExpectRollback()
+
+	slRepo := NewRepository(db)
+
+	err = slRepo.Delete(etag.WithVersion(context.TODO(), 2), 1)
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+}
+
+func TestRepository_Delete_Cascade(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(queryDeleteProducts)).WithArgs(
+		sqlmock.AnyArg(),
+		1,
+	).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(queryDelete)).WithArgs(
+		sqlmock.AnyArg(),
+		1,
+	).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	slRepo := NewRepository(db)
+
+	err = slRepo.Delete(dependents.WithCascade(context.TODO()), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Dependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryCountProducts)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	slRepo := NewRepository(db)
+
+	result, err := slRepo.Dependents(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []dependents.Dependent{{Type: "products", Count: 3}}, result)
+}
+
+func TestRepository_CascadeDependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryCountCascadeBlockers)).WithArgs(1, 1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"batches", "records", "lines"}).AddRow(0, 2, 1))
+
+	slRepo := NewRepository(db)
+
+	result, err := slRepo.CascadeDependents(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []dependents.Dependent{
+		{Type: "product_batches", Count: 0},
+		{Type: "product_records", Count: 2},
+		{Type: "purchase_order_lines", Count: 1},
+	}, result)
+}
+
+func TestRepository_GetById_IncludeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	deletedAt := date.NewDateTime(time.Date(2022, 7, 9, 10, 0, 0, 0, time.UTC))
+
+	row := sqlmock.NewRows([]string{
+		"id", "cid", "company_name", "address", "telephone", "locality_id", "deleted_at", "version",
+	}).AddRow(1, 44, "Gasp", "Rua Gaspar, 101", "23225422", 1, deletedAt.Time, 2)
+
+	mock.ExpectQuery("^" + regexp.QuoteMeta(queryGetByIdWithDeleted) + "$").WithArgs(1).WillReturnRows(row)
+
+	slRepo := NewRepository(db)
+
+	result, err := slRepo.GetById(softdelete.WithDeleted(context.TODO()), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, deletedAt, result.DeletedAt)
+}
+
+func TestRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(queryRestore)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(queryRestore)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	slRepo := NewRepository(db)
+
+	assert.NoError(t, slRepo.Restore(context.TODO(), 1))
+	assert.EqualError(t, slRepo.Restore(context.TODO(), 2), "deleted seller 2 not found")
+}
+
+func TestRepository_GetStock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetStock)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"products", "units"}).AddRow(3, 120))
+
+	products, units, err := NewRepository(db).GetStock(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, products)
+	assert.Equal(t, 120, units)
+}
+
+func TestRepository_GetSales(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	dates := period.Range{From: date.New(2022, 7, 1), To: date.New(2022, 7, 31)}
+	orderDate := time.Date(2022, 7, 9, 0, 0, 0, 0, time.UTC)
+
+	conditions := " AND o.order_date >= ? AND o.order_date <= ?"
+	mock.ExpectQuery("^"+regexp.QuoteMeta(queryGetSales+conditions+queryGetLegacySales+conditions+queryGetSalesOrder)+"$").
+		WithArgs(1, dates.From, dates.To, 1, dates.From, dates.To).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_date", "purchase_price", "unit_price", "quantity"}).
+			AddRow(3, orderDate, 8.5, 12, 1).
+			AddRow(4, orderDate, 8.5, 12.5, 6))
+
+	result, err := NewRepository(db).GetSales(context.TODO(), 1, dates)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Sale{
+		{OrderId: 3, OrderDate: date.New(2022, 7, 9), PurchasePrice: 8.5, SalePrice: 12, Quantity: 1},
+		{OrderId: 4, OrderDate: date.New(2022, 7, 9), PurchasePrice: 8.5, SalePrice: 12.5, Quantity: 6},
+	}, result)
+}
+
+func TestRepository_GetSales_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(queryGetSales)).WithArgs(1).WillReturnError(errors.New("connection refused"))
+
+	_, err = NewRepository(db).GetSales(context.TODO(), 1, period.Range{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	productsDomain "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+)
+
+type auditedService struct {
+	domain.Service
+	audit    auditDomain.Service
+	products productsDomain.ProductRepository
+}
+
+// NewAuditedService records every change made through s in the audit trail,
+// the products a cascading delete removes along with a seller included.
+func NewAuditedService(s domain.Service, audit auditDomain.Service, products productsDomain.ProductRepository) domain.Service {
+	return &auditedService{s, audit, products}
+}
+
+func (s *auditedService) Create(ctx context.Context, cid int, companyName, address, telephone string, localityId int) (domain.Seller, error) {
+	seller, err := s.Service.Create(ctx, cid, companyName, address, telephone, localityId)
+	if err != nil {
+		return domain.Seller{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntitySellers, seller.ID, auditDomain.ActionCreate, nil, seller)
+
+	return seller, nil
+}
+
+func (s *auditedService) Update(ctx context.Context, id int, arg domain.SellerPatch) (domain.Seller, error) {
+	before, _ := s.Service.GetById(ctx, id)
+
+	seller, err := s.Service.Update(ctx, id, arg)
+	if err != nil {
+		return domain.Seller{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntitySellers, id, auditDomain.ActionUpdate, before, seller)
+
+	return seller, nil
+}
+
+func (s *auditedService) Delete(ctx context.Context, id int) error {
+	before, _ := s.Service.GetById(ctx, id)
+	products := s.cascaded(ctx, id)
+
+	if err := s.Service.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntitySellers, id, auditDomain.ActionDelete, before, nil)
+	for _, product := range products {
+		s.audit.Record(ctx, auditDomain.EntityProducts, product.Id, auditDomain.ActionDelete, product, nil)
+	}
+
+	return nil
+}
+
+// cascaded returns the products a cascading delete of the seller removes
+// with it, read before they are gone. A plain delete removes none.
+func (s *auditedService) cascaded(ctx context.Context, id int) []productsDomain.Product {
+	if !dependents.Cascade(ctx) {
+		return nil
+	}
+
+	products, _ := s.products.GetAll(ctx)
+
+	removed := []productsDomain.Product{}
+	for _, product := range products {
+		if product.SellerId == id {
+			removed = append(removed, product)
+		}
+	}
+
+	return removed
+}
+
+func (s *auditedService) Restore(ctx context.Context, id int) (domain.Seller, error) {
+	before, _ := s.Service.GetById(softdelete.WithDeleted(ctx), id)
+
+	seller, err := s.Service.Restore(ctx, id)
+	if err != nil {
+		return domain.Seller{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntitySellers, id, auditDomain.ActionRestore, before, seller)
+
+	return seller, nil
+}
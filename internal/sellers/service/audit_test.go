@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	mock_audit "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain/mock"
+	productsDomain "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain"
+	mock_products "github.com/douglmendes/mercado-fresco-round-go/internal/products/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditedService(t *testing.T) {
+	before := domain.Seller{ID: 1, Cid: 10, Address: "Old Street"}
+	after := domain.Seller{ID: 1, Cid: 10, Address: "New Street"}
+	address := "New Street"
+
+	t.Run("Create records the new seller", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().Create(gomock.Any(), 10, "", "Old Street", "", 2).Return(before, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntitySellers, 1, auditDomain.ActionCreate, nil, before)
+
+		_, err := NewAuditedService(service, audit, nil).Create(context.TODO(), 10, "", "Old Street", "", 2)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Update records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		patch := domain.SellerPatch{Address: &address}
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Update(gomock.Any(), 1, patch).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntitySellers, 1, auditDomain.ActionUpdate, before, after)
+
+		_, err := NewAuditedService(service, audit, nil).Update(context.TODO(), 1, patch)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete records the removed seller", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Delete(gomock.Any(), 1).Return(nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntitySellers, 1, auditDomain.ActionDelete, before, nil)
+
+		assert.NoError(t, NewAuditedService(service, audit, nil).Delete(context.TODO(), 1))
+	})
+
+	t.Run("Cascading Delete records the removed products", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+		products := mock_products.NewMockProductRepository(ctrl)
+		ctx := dependents.WithCascade(context.TODO())
+
+		own := productsDomain.Product{Id: 4, ProductCode: "PROD01", SellerId: 1}
+		other := productsDomain.Product{Id: 5, ProductCode: "PROD02", SellerId: 2}
+
+		service.EXPECT().GetById(ctx, 1).Return(before, nil)
+		products.EXPECT().GetAll(ctx).Return([]productsDomain.Product{own, other}, nil)
+		service.EXPECT().Delete(ctx, 1).Return(nil)
+		audit.EXPECT().Record(ctx, auditDomain.EntitySellers, 1, auditDomain.ActionDelete, before, nil)
+		audit.EXPECT().Record(ctx, auditDomain.EntityProducts, 4, auditDomain.ActionDelete, own, nil)
+
+		assert.NoError(t, NewAuditedService(service, audit, products).Delete(ctx, 1))
+	})
+
+	t.Run("Failed cascading Delete is not recorded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+		products := mock_products.NewMockProductRepository(ctrl)
+		ctx := dependents.WithCascade(context.TODO())
+
+		service.EXPECT().GetById(ctx, 1).Return(before, nil)
+		products.EXPECT().GetAll(ctx).Return([]productsDomain.Product{{Id: 4, SellerId: 1}}, nil)
+		service.EXPECT().Delete(ctx, 1).Return(errors.New("connection refused"))
+
+		assert.Error(t, NewAuditedService(service, audit, products).Delete(ctx, 1))
+	})
+
+	t.Run("Restore records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Restore(gomock.Any(), 1).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntitySellers, 1, auditDomain.ActionRestore, before, after)
+
+		_, err := NewAuditedService(service, audit, nil).Restore(context.TODO(), 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed changes are not recorded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().Create(gomock.Any(), 10, "", "Old Street", "", 2).Return(domain.Seller{}, errors.New("cid already exists"))
+
+		_, err := NewAuditedService(service, audit, nil).Create(context.TODO(), 10, "", "Old Street", "", 2)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/money"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/tenant"
+)
+
+// GetReport sums up the products and stock of the seller and the sales of
+// its products within dates, grouped by unit. A request scoped to another
+// seller finds no seller, so the report of a competitor is never shown.
+func (s service) GetReport(ctx context.Context, id int, dates period.Range, unit period.Unit) (domain.Report, error) {
+	if sellerId, scoped := tenant.SellerId(ctx); scoped && sellerId != id {
+		return domain.Report{}, fmt.Errorf("seller %d not found", id)
+	}
+
+	if _, err := s.repository.GetById(ctx, id); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.Report{}, err
+	}
+
+	products, units, err := s.repository.GetStock(ctx, id)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.Report{}, err
+	}
+
+	sales, err := s.repository.GetSales(ctx, id, dates)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.Report{}, err
+	}
+
+	report := domain.Report{
+		SellerId:      id,
+		Range:         dates,
+		Period:        unit,
+		ProductsCount: products,
+		StockUnits:    units,
+	}
+
+	// Sales come ordered by date.
+	periods := period.NewGrouping(unit, func(key string) domain.ReportPeriod {
+		return domain.ReportPeriod{Period: key}
+	})
+	for _, sale := range sales {
+		units := float64(sale.Quantity)
+		revenue, margin := sale.SalePrice*units, (sale.SalePrice-sale.PurchasePrice)*units
+
+		row := periods.Of(sale.OrderDate)
+		row.UnitsSold += sale.Quantity
+		row.Revenue += revenue
+		row.Margin += margin
+		report.UnitsSold += sale.Quantity
+		report.Revenue += revenue
+		report.Margin += margin
+	}
+	report.Periods = periods.Rows()
+
+	report.Revenue, report.Margin = money.Round(report.Revenue), money.Round(report.Margin)
+	for i := range report.Periods {
+		report.Periods[i].Revenue = money.Round(report.Periods[i].Revenue)
+		report.Periods[i].Margin = money.Round(report.Periods[i].Margin)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/period"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_GetReport(t *testing.T) {
+	dates := period.Range{From: date.New(2022, 7, 1)}
+
+	t.Run("OK", func(t *testing.T) {
+		repository, _, service := callMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), id).Return(domain.Seller{ID: id}, nil)
+		repository.EXPECT().GetStock(context.TODO(), id).Return(2, 150, nil)
+		repository.EXPECT().GetSales(context.TODO(), id, dates).Return([]domain.Sale{
+			{OrderId: 1, OrderDate: date.New(2022, 7, 4), PurchasePrice: 8.1, SalePrice: 10.2, Quantity: 1},
+			{OrderId: 2, OrderDate: date.New(2022, 7, 5), PurchasePrice: 8.1, SalePrice: 10.2, Quantity: 1},
+			{OrderId: 3, OrderDate: date.New(2022, 7, 12), PurchasePrice: 20, SalePrice: 18.5, Quantity: 1},
+		}, nil)
+
+		result, err := service.GetReport(context.TODO(), id, dates, period.Week)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Report{
+			SellerId:      id,
+			Range:         dates,
+			Period:        period.Week,
+			ProductsCount: 2,
+			StockUnits:    150,
+			UnitsSold:     3,
+			Revenue:       38.9,
+			Margin:        2.7,
+			Periods: []domain.ReportPeriod{
+				{Period: "2022-W27", UnitsSold: 2, Revenue: 20.4, Margin: 4.2},
+				{Period: "2022-W28", UnitsSold: 1, Revenue: 18.5, Margin: -1.5},
+			},
+		}, result)
+	})
+
+	t.Run("Order lines", func(t *testing.T) {
+		repository, _, service := callMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), id).Return(domain.Seller{ID: id}, nil)
+		repository.EXPECT().GetStock(context.TODO(), id).Return(2, 150, nil)
+		repository.EXPECT().GetSales(context.TODO(), id, dates).Return([]domain.Sale{
+			{OrderId: 4, OrderDate: date.New(2022, 7, 4), PurchasePrice: 8, SalePrice: 10, Quantity: 3},
+			{OrderId: 4, OrderDate: date.New(2022, 7, 4), PurchasePrice: 1.5, SalePrice: 2.25, Quantity: 4},
+		}, nil)
+
+		result, err := service.GetReport(context.TODO(), id, dates, period.Week)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, result.UnitsSold)
+		assert.Equal(t, 39.0, result.Revenue)
+		assert.Equal(t, 9.0, result.Margin)
+		assert.Equal(t, []domain.ReportPeriod{{Period: "2022-W27", UnitsSold: 7, Revenue: 39, Margin: 9}}, result.Periods)
+	})
+
+	t.Run("No sales", func(t *testing.T) {
+		repository, _, service := callMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), id).Return(domain.Seller{ID: id}, nil)
+		repository.EXPECT().GetStock(context.TODO(), id).Return(0, 0, nil)
+		repository.EXPECT().GetSales(context.TODO(), id, dates).Return([]domain.Sale{}, nil)
+
+		result, err := service.GetReport(context.TODO(), id, dates, period.Month)
+
+		assert.NoError(t, err)
+		assert.Zero(t, result.UnitsSold)
+		assert.Empty(t, result.Periods)
+	})
+
+	t.Run("Own report", func(t *testing.T) {
+		repository, _, service := callMock(t)
+		ctx := tenant.WithSeller(context.TODO(), id)
+
+		repository.EXPECT().GetById(ctx, id).Return(domain.Seller{ID: id}, nil)
+		repository.EXPECT().GetStock(ctx, id).Return(0, 0, nil)
+		repository.EXPECT().GetSales(ctx, id, dates).Return([]domain.Sale{}, nil)
+
+		_, err := service.GetReport(ctx, id, dates, period.Month)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Other seller", func(t *testing.T) {
+		_, _, service := callMock(t)
+
+		_, err := service.GetReport(tenant.WithSeller(context.TODO(), id+1), id, dates, period.Month)
+
+		assert.EqualError(t, err, fmt.Sprintf("seller %d not found", id))
+	})
+
+	t.Run("Seller not found", func(t *testing.T) {
+		repository, _, service := callMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), id).Return(domain.Seller{}, errors.New("seller 1 not found"))
+
+		_, err := service.GetReport(context.TODO(), id, dates, period.Month)
+
+		assert.EqualError(t, err, "seller 1 not found")
+	})
+
+	t.Run("Stock fails", func(t *testing.T) {
+		repository, _, service := callMock(t)
+
+		repository.EXPECT().GetById(context.TODO(), id).Return(domain.Seller{ID: id}, nil)
+		repository.EXPECT().GetStock(context.TODO(), id).Return(0, 0, errors.New("connection refused"))
+
+		_, err := service.GetReport(context.TODO(), id, dates, period.Month)
+
+		assert.Error(t, err)
+	})
+}
@@ -6,7 +6,9 @@ import (
 
 	localityD "github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
 )
 
@@ -44,7 +46,8 @@ func (s service) GetById(ctx context.Context, id int) (domain.Seller, error) {
 
 func (s service) Create(ctx context.Context, cid int, companyName, address, telephone string, localityId int) (domain.Seller, error) {
 
-	sl, err := s.repository.GetAll(ctx)
+	// Deleted sellers keep their cid until they are purged.
+	sl, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return domain.Seller{}, err
@@ -70,20 +73,22 @@ func (s service) Create(ctx context.Context, cid int, companyName, address, tele
 	return seller, nil
 }
 
-func (s service) Update(ctx context.Context, id, cid int, companyName, address, telephone string, localityId int) (domain.Seller, error) {
-	sl, err := s.repository.GetAll(ctx)
-	if err != nil {
-		logger.Error(ctx, store.GetPathWithLine(), err.Error())
-		return domain.Seller{}, err
-	}
+func (s service) Update(ctx context.Context, id int, arg domain.SellerPatch) (domain.Seller, error) {
+	if arg.Cid != nil {
+		sl, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			return domain.Seller{}, err
+		}
 
-	for i := range sl {
-		if sl[i].Cid == cid {
-			return domain.Seller{}, fmt.Errorf("this seller already exists")
+		for i := range sl {
+			if sl[i].ID != id && sl[i].Cid == *arg.Cid {
+				return domain.Seller{}, fmt.Errorf("this seller already exists")
+			}
 		}
 	}
 
-	seller, err := s.repository.Update(ctx, id, cid, companyName, address, telephone, localityId)
+	seller, err := s.repository.Update(ctx, id, arg)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return domain.Seller{}, err
@@ -93,10 +98,37 @@ func (s service) Update(ctx context.Context, id, cid int, companyName, address,
 }
 
 func (s service) Delete(ctx context.Context, id int) error {
-	err := s.repository.Delete(ctx, id)
+	// A cascading delete takes the seller's products along with it, as long
+	// as nothing keeps those from being deleted themselves.
+	dependentsOf := s.repository.Dependents
+	if dependents.Cascade(ctx) {
+		dependentsOf = s.repository.CascadeDependents
+	}
+
+	found, err := dependentsOf(ctx, id)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	if err := dependents.Check("seller", id, found...); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	err = s.repository.Delete(ctx, id)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return err
 	}
 	return nil
 }
+
+func (s service) Restore(ctx context.Context, id int) (domain.Seller, error) {
+	if err := s.repository.Restore(ctx, id); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.Seller{}, err
+	}
+
+	return s.repository.GetById(ctx, id)
+}
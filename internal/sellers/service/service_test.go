@@ -9,6 +9,7 @@ import (
 	"github.com/douglmendes/mercado-fresco-round-go/internal/sellers/domain/mock"
 	locality "github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain"
 	localityMock "github.com/douglmendes/mercado-fresco-round-go/internal/localities/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -128,7 +129,7 @@ func TestCreate_Ok(t *testing.T) {
 
 	apiMock, apiLocalityMock, service := callMock(t)
 
-	apiMock.EXPECT().GetAll(context.TODO()).Return(slList, nil)
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(slList, nil)
 	apiLocalityMock.EXPECT().GetById(context.TODO(), gomock.Eq(id)).Return(locality.Locality{}, nil)
 	apiMock.EXPECT().Create(context.TODO(), 20, "Mercado Livre", "Melicidade", "98787687", 1).Return(sl, nil)
 
@@ -160,7 +161,7 @@ func TestCreate_NOk(t *testing.T) {
 
 	apiMock, _, service := callMock(t)
 
-	apiMock.EXPECT().GetAll(context.TODO()).Return(slList, nil)
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(slList, nil)
 	apiMock.EXPECT().Create(3, 22, "Mercado Livre", "Melicidade", "98787687", 1).Return(domain.Seller{}, errors.New("this seller already exists"))
 
 	_, err := service.Create(context.TODO(), 22, "Mercado Livre", "Melicidade", "98787687", 1)
@@ -171,7 +172,7 @@ func TestCreate_GetAll_NOk(t *testing.T) {
 
 	apiMock, _, service := callMock(t)
 
-	apiMock.EXPECT().GetAll(context.TODO()).Return([]domain.Seller{}, errors.New("error"))
+	apiMock.EXPECT().GetAll(gomock.Any()).Return([]domain.Seller{}, errors.New("error"))
 
 	_, err := service.Create(context.TODO(), 22, "Mercado Livre", "Melicidade", "98787687", 1)
 	assert.NotNil(t, err)
@@ -200,7 +201,7 @@ func TestCreate_Locality_NOk(t *testing.T) {
 
 	apiMock, apiLocalityMock, service := callMock(t)
 
-	apiMock.EXPECT().GetAll(context.TODO()).Return(slList, nil)
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(slList, nil)
 	apiLocalityMock.EXPECT().GetById(context.TODO(), gomock.Eq(id)).Return(locality.Locality{}, errors.New("locality not found"))
 
 	_, err := service.Create(context.TODO(), 20, "Mercado Livre", "Melicidade", "98787687", 1)
@@ -238,10 +239,10 @@ func TestService_Update_Ok(t *testing.T) {
 
 	apiMock, _, service := callMock(t)
 
-	apiMock.EXPECT().GetAll(context.TODO()).Return(slList, nil)
-	apiMock.EXPECT().Update(context.TODO(), 1, 20, "Mercado Livre", "Melicidade", "98787687", 1).Return(sl, nil)
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(slList, nil)
+	apiMock.EXPECT().Update(context.TODO(), 1, domain.SellerPatch{Cid: intPtr(20), CompanyName: stringPtr("Mercado Livre"), Address: stringPtr("Melicidade"), Telephone: stringPtr("98787687"), LocalityId: intPtr(1)}).Return(sl, nil)
 
-	result, err := service.Update(context.TODO(), 1, 20, "Mercado Livre", "Melicidade", "98787687", 1)
+	result, err := service.Update(context.TODO(), 1, domain.SellerPatch{Cid: intPtr(20), CompanyName: stringPtr("Mercado Livre"), Address: stringPtr("Melicidade"), Telephone: stringPtr("98787687"), LocalityId: intPtr(1)})
 	assert.Nil(t, err)
 	assert.Equal(t, result, sl)
 }
@@ -271,10 +272,10 @@ func TestService_Update_NOk(t *testing.T) {
 
 	apiMock, _, service := callMock(t)
 
-	apiMock.EXPECT().GetAll(context.TODO()).Return(slList, nil)
-	apiMock.EXPECT().Update(context.TODO(), 10, 20, "Mercado Livre", "Melicidade", "98787687", 1).Return(sl, errors.New("seller 10 not found"))
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(slList, nil)
+	apiMock.EXPECT().Update(context.TODO(), 10, domain.SellerPatch{Cid: intPtr(20), CompanyName: stringPtr("Mercado Livre"), Address: stringPtr("Melicidade"), Telephone: stringPtr("98787687"), LocalityId: intPtr(1)}).Return(sl, errors.New("seller 10 not found"))
 
-	result, err := service.Update(context.TODO(), 10, 20, "Mercado Livre", "Melicidade", "98787687", 1)
+	result, err := service.Update(context.TODO(), 10, domain.SellerPatch{Cid: intPtr(20), CompanyName: stringPtr("Mercado Livre"), Address: stringPtr("Melicidade"), Telephone: stringPtr("98787687"), LocalityId: intPtr(1)})
 	assert.NotNil(t, err)
 	assert.Equal(t, result, sl)
 }
@@ -304,9 +305,9 @@ func TestService_Update_ExistentCid_NOk(t *testing.T) {
 
 	apiMock, _, service := callMock(t)
 
-	apiMock.EXPECT().GetAll(context.TODO()).Return(slList, nil)
+	apiMock.EXPECT().GetAll(gomock.Any()).Return(slList, nil)
 
-	result, err := service.Update(context.TODO(), 10, 22, "Mercado Livre", "Melicidade", "98787687", 1)
+	result, err := service.Update(context.TODO(), 10, domain.SellerPatch{Cid: intPtr(22), CompanyName: stringPtr("Mercado Livre"), Address: stringPtr("Melicidade"), Telephone: stringPtr("98787687"), LocalityId: intPtr(1)})
 	assert.NotNil(t, err)
 	assert.Equal(t, result, sl)
 }
@@ -317,9 +318,9 @@ func TestService_Update_GetAll_NOk(t *testing.T) {
 
 	apiMock, _, service := callMock(t)
 
-	apiMock.EXPECT().GetAll(context.TODO()).Return([]domain.Seller{}, errors.New("seller 10 not found"))
+	apiMock.EXPECT().GetAll(gomock.Any()).Return([]domain.Seller{}, errors.New("seller 10 not found"))
 
-	result, err := service.Update(context.TODO(), 10, 20, "Mercado Livre", "Melicidade", "98787687", 1)
+	result, err := service.Update(context.TODO(), 10, domain.SellerPatch{Cid: intPtr(20), CompanyName: stringPtr("Mercado Livre"), Address: stringPtr("Melicidade"), Telephone: stringPtr("98787687"), LocalityId: intPtr(1)})
 	assert.NotNil(t, err)
 	assert.Equal(t, result, sl)
 }
@@ -327,6 +328,7 @@ func TestService_Update_GetAll_NOk(t *testing.T) {
 func TestService_Delete_Ok(t *testing.T) {
 	apiMock, _, service := callMock(t)
 
+	apiMock.EXPECT().Dependents(context.TODO(), gomock.Eq(id)).Return([]dependents.Dependent{{Type: "products", Count: 0}}, nil)
 	apiMock.EXPECT().Delete(context.TODO(), gomock.Eq(id)).Return(nil)
 
 	err := service.Delete(context.TODO(), id)
@@ -336,8 +338,87 @@ func TestService_Delete_Ok(t *testing.T) {
 func TestService_Delete_NOk(t *testing.T) {
 	apiMock, _, service := callMock(t)
 
+	apiMock.EXPECT().Dependents(context.TODO(), gomock.Eq(id)).Return(nil, nil)
 	apiMock.EXPECT().Delete(context.TODO(), gomock.Eq(id)).Return(errors.New("id is not valid"))
 
 	err := service.Delete(context.TODO(), id)
 	assert.NotNil(t, err)
 }
+
+func TestService_Delete_WithProducts(t *testing.T) {
+	apiMock, _, service := callMock(t)
+
+	apiMock.EXPECT().Dependents(context.TODO(), gomock.Eq(id)).Return([]dependents.Dependent{{Type: "products", Count: 2}}, nil)
+
+	err := service.Delete(context.TODO(), id)
+
+	var dependentsErr *dependents.Error
+	assert.ErrorAs(t, err, &dependentsErr)
+	assert.Equal(t, []dependents.Dependent{{Type: "products", Count: 2}}, dependentsErr.Dependents)
+	assert.EqualError(t, err, "seller 1 cannot be deleted, it still has 2 products")
+}
+
+func TestService_Delete_Cascade(t *testing.T) {
+	apiMock, _, service := callMock(t)
+
+	ctx := dependents.WithCascade(context.TODO())
+	apiMock.EXPECT().CascadeDependents(ctx, gomock.Eq(id)).Return([]dependents.Dependent{
+		{Type: "product_batches", Count: 0},
+		{Type: "product_records", Count: 0},
+		{Type: "purchase_order_lines", Count: 0},
+	}, nil)
+	apiMock.EXPECT().Delete(ctx, gomock.Eq(id)).Return(nil)
+
+	err := service.Delete(ctx, id)
+	assert.Nil(t, err)
+}
+
+func TestService_Delete_Cascade_Blocked(t *testing.T) {
+	apiMock, _, service := callMock(t)
+
+	ctx := dependents.WithCascade(context.TODO())
+	apiMock.EXPECT().CascadeDependents(ctx, gomock.Eq(id)).Return([]dependents.Dependent{
+		{Type: "product_batches", Count: 0},
+		{Type: "product_records", Count: 2},
+		{Type: "purchase_order_lines", Count: 1},
+	}, nil)
+
+	err := service.Delete(ctx, id)
+
+	var dependentsErr *dependents.Error
+	assert.ErrorAs(t, err, &dependentsErr)
+	assert.Equal(t, []dependents.Dependent{
+		{Type: "product_records", Count: 2},
+		{Type: "purchase_order_lines", Count: 1},
+	}, dependentsErr.Dependents)
+}
+
+func TestService_Restore_Ok(t *testing.T) {
+	sl := domain.Seller{ID: id, Cid: 20, CompanyName: "Mercado Livre"}
+
+	apiMock, _, service := callMock(t)
+
+	apiMock.EXPECT().Restore(context.TODO(), gomock.Eq(id)).Return(nil)
+	apiMock.EXPECT().GetById(context.TODO(), gomock.Eq(id)).Return(sl, nil)
+
+	result, err := service.Restore(context.TODO(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, sl, result)
+}
+
+func TestService_Restore_NOk(t *testing.T) {
+	apiMock, _, service := callMock(t)
+
+	apiMock.EXPECT().Restore(context.TODO(), gomock.Eq(id)).Return(errors.New("deleted seller not found"))
+
+	_, err := service.Restore(context.TODO(), id)
+	assert.NotNil(t, err)
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sequences of the identifiers the server generates when clients omit them.
+const (
+	PurchaseOrderNumber   = "purchase_order_number"
+	PurchaseOrderTracking = "purchase_order_tracking"
+	InboundOrderNumber    = "inbound_order_number"
+)
+
+// Format describes an identifier: the prefix, the day it was generated in
+// DateLayout, the counter padded to Digits and, optionally, a check digit.
+// With a DateLayout the counter starts over whenever the formatted day
+// changes.
+type Format struct {
+	Prefix     string
+	DateLayout string
+	Separator  string
+	Digits     int
+	CheckDigit bool
+}
+
+// ErrReserved is returned for an identifier sent by a client that has the
+// shape of the generated ones, which could collide with one generated later.
+var ErrReserved = errors.New("value is reserved for generated identifiers")
+
+// DefaultFormats give identifiers such as "PO-20220801-0000425".
+var DefaultFormats = map[string]Format{
+	PurchaseOrderNumber:   {Prefix: "PO-", DateLayout: "20060102", Separator: "-", Digits: 6, CheckDigit: true},
+	PurchaseOrderTracking: {Prefix: "TRK", DateLayout: "060102", Digits: 8, CheckDigit: true},
+	InboundOrderNumber:    {Prefix: "IO-", DateLayout: "20060102", Separator: "-", Digits: 6, CheckDigit: true},
+}
+
+// Period returns the counter period at falls in.
+func (f Format) Period(at time.Time) string {
+	if f.DateLayout == "" {
+		return ""
+	}
+	return at.Format(f.DateLayout)
+}
+
+func (f Format) Build(at time.Time, counter int64) string {
+	value := fmt.Sprintf("%s%s%s%0*d", f.Prefix, f.Period(at), f.Separator, f.Digits, counter)
+	if f.CheckDigit {
+		value += fmt.Sprint(CheckDigit(value))
+	}
+	return value
+}
+
+// Matches reports whether value has the shape of the identifiers of the
+// format, whatever its day, counter and check digit.
+func (f Format) Matches(value string) bool {
+	if !strings.HasPrefix(value, f.Prefix) {
+		return false
+	}
+	rest := value[len(f.Prefix):]
+	if n := len(f.DateLayout); n > 0 {
+		if len(rest) < n || !digitsOnly(rest[:n]) {
+			return false
+		}
+		rest = rest[n:]
+	}
+	if !strings.HasPrefix(rest, f.Separator) {
+		return false
+	}
+	rest = rest[len(f.Separator):]
+
+	digits := f.Digits
+	if f.CheckDigit {
+		digits++
+	}
+	return len(rest) >= digits && digitsOnly(rest)
+}
+
+func digitsOnly(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckDigit computes the Luhn check digit of the digits in value, so typos
+// in a copied identifier can be caught before it is looked up.
+func CheckDigit(value string) int {
+	digits := strings.Map(func(r rune) rune {
+		if r < '0' || r > '9' {
+			return -1
+		}
+		return r
+	}, value)
+
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+
+	return (10 - sum%10) % 10
+}
+
+//go:generate mockgen -source=./sequences.go -destination=./mock/sequences_mock.go
+type Repository interface {
+	// Next increments the counter of the sequence in the period and returns
+	// the new value. Concurrent calls never get the same value.
+	Next(ctx context.Context, name, period string) (int64, error)
+}
+
+type Service interface {
+	Next(ctx context.Context, name string) (string, error)
+	// Reserved reports whether value has the shape of the identifiers the
+	// sequence generates. Clients may not send such values.
+	Reserved(name, value string) bool
+}
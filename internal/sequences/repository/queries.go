@@ -0,0 +1,10 @@
+package repository
+
+const (
+	// LAST_INSERT_ID(expr) makes the new value readable from the result of
+	// the statement itself, whether the row was inserted or updated.
+	sqlNext = `
+		INSERT INTO sequences (name, period, value)
+		VALUES (?, ?, LAST_INSERT_ID(1))
+		ON DUPLICATE KEY UPDATE value = LAST_INSERT_ID(value + 1)`
+)
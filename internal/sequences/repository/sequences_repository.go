@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+)
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domain.Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r repository) Next(ctx context.Context, name, period string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, sqlNext, name, period)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return 0, err
+	}
+
+	value, err := result.LastInsertId()
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return 0, err
+	}
+
+	return value, nil
+}
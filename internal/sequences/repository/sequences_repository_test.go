@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_Next(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlNext)).
+		WithArgs("purchase_order_number", "20220801").
+		WillReturnResult(sqlmock.NewResult(42, 2))
+
+	value, err := NewRepository(db).Next(context.TODO(), "purchase_order_number", "20220801")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), value)
+}
+
+func TestRepository_Next_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlNext)).WillReturnError(errors.New("connection refused"))
+
+	_, err = NewRepository(db).Next(context.TODO(), "purchase_order_number", "20220801")
+	assert.EqualError(t, err, "connection refused")
+}
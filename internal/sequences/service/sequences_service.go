@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+)
+
+type service struct {
+	repository domain.Repository
+	formats    map[string]domain.Format
+}
+
+func NewService(r domain.Repository, formats map[string]domain.Format) domain.Service {
+	return &service{
+		repository: r,
+		formats:    formats,
+	}
+}
+
+func (s *service) Next(ctx context.Context, name string) (string, error) {
+	format, ok := s.formats[name]
+	if !ok {
+		return "", fmt.Errorf("unknown sequence %s", name)
+	}
+
+	now := date.Now().Time
+
+	counter, err := s.repository.Next(ctx, name, format.Period(now))
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return "", err
+	}
+
+	return format.Build(now, counter), nil
+}
+
+func (s *service) Reserved(name, value string) bool {
+	format, ok := s.formats[name]
+	return ok && format.Matches(value)
+}
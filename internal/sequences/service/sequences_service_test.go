@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/sequences/domain/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Next(t *testing.T) {
+	t.Run("should format the counter of the day", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+
+		// The period is read back from the call instead of computed here, so
+		// the test holds when the day changes between the two clocks.
+		var period string
+		format := domain.DefaultFormats[domain.PurchaseOrderNumber]
+		repository.EXPECT().Next(gomock.Any(), domain.PurchaseOrderNumber, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, p string) (int64, error) {
+				period = p
+				return 42, nil
+			})
+
+		value, err := NewService(repository, domain.DefaultFormats).Next(context.TODO(), domain.PurchaseOrderNumber)
+		assert.NoError(t, err)
+
+		day, err := time.Parse(format.DateLayout, period)
+		assert.NoError(t, err)
+		assert.Regexp(t, `^PO-`+period+`-000042\d$`, value)
+		assert.Equal(t, format.Build(day, 42), value)
+	})
+
+	t.Run("should keep a single counter without a date", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().Next(gomock.Any(), "plain", "").Return(int64(7), nil)
+
+		formats := map[string]domain.Format{"plain": {Prefix: "N", Digits: 3}}
+		value, err := NewService(repository, formats).Next(context.TODO(), "plain")
+		assert.NoError(t, err)
+		assert.Equal(t, "N007", value)
+	})
+
+	t.Run("should reject unknown sequences", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+
+		_, err := NewService(repository, domain.DefaultFormats).Next(context.TODO(), "invoices")
+		assert.EqualError(t, err, "unknown sequence invoices")
+	})
+
+	t.Run("should return repository errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mock_domain.NewMockRepository(ctrl)
+		repository.EXPECT().Next(gomock.Any(), domain.InboundOrderNumber, gomock.Any()).Return(int64(0), errors.New("connection refused"))
+
+		_, err := NewService(repository, domain.DefaultFormats).Next(context.TODO(), domain.InboundOrderNumber)
+		assert.EqualError(t, err, "connection refused")
+	})
+}
+
+func TestService_Reserved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	formats := map[string]domain.Format{
+		domain.PurchaseOrderNumber: {Prefix: "ORD-", DateLayout: "20060102", Separator: "-", Digits: 6, CheckDigit: true},
+	}
+	service := NewService(mock_domain.NewMockRepository(ctrl), formats)
+
+	assert.True(t, service.Reserved(domain.PurchaseOrderNumber, "ORD-20220801-0000425"))
+	assert.False(t, service.Reserved(domain.PurchaseOrderNumber, "PO-20220801-0000425"))
+	assert.False(t, service.Reserved("invoices", "ORD-20220801-0000425"))
+}
+
+func TestFormat_Matches(t *testing.T) {
+	number := domain.DefaultFormats[domain.PurchaseOrderNumber]
+	tracking := domain.DefaultFormats[domain.PurchaseOrderTracking]
+
+	assert.True(t, number.Matches("PO-20220801-0000425"))
+	// A wrong check digit or a counter past its padding is still reserved.
+	assert.True(t, number.Matches("PO-20220801-0000421"))
+	assert.True(t, number.Matches("PO-20220801-12345678"))
+	assert.False(t, number.Matches("PO-2022080-0000425"))
+	assert.False(t, number.Matches("PO-20220801-000042"))
+	assert.False(t, number.Matches("PO-20220801/0000425"))
+	assert.False(t, number.Matches("PO-CUSTOMER-1"))
+	assert.False(t, number.Matches("IO-20220801-0000425"))
+
+	assert.True(t, tracking.Matches("TRK220801000000427"))
+	assert.False(t, tracking.Matches("TRK-220801000000427"))
+	assert.False(t, tracking.Matches("BR123456789"))
+}
+
+func TestFormat_Build(t *testing.T) {
+	at := time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "PO-20220801-0000425", domain.DefaultFormats[domain.PurchaseOrderNumber].Build(at, 42))
+	assert.Equal(t, "TRK220801000000427", domain.DefaultFormats[domain.PurchaseOrderTracking].Build(at, 42))
+	// Luhn reference value
+	assert.Equal(t, 3, domain.CheckDigit("7992739871"))
+}
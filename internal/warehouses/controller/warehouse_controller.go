@@ -1,10 +1,16 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/patch"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
 	"net/http"
 	"strconv"
 
@@ -31,7 +37,7 @@ func (w *WarehousesController) Create() gin.HandlerFunc {
 		var whRequest whCreateRequest
 
 		if err := ctx.ShouldBindJSON(&whRequest); err != nil {
-			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			ctx.JSON(http.StatusUnprocessableEntity, validation.NewErrorResponse(err))
 			return
 		}
 
@@ -48,6 +54,7 @@ func (w *WarehousesController) Create() gin.HandlerFunc {
 			return
 		}
 
+		etag.Set(ctx, warehouse.Version)
 		ctx.JSON(http.StatusCreated, warehouse)
 	}
 }
@@ -57,11 +64,17 @@ func (w *WarehousesController) Create() gin.HandlerFunc {
 // @Tags Warehouses
 // @Description List all available warehouses
 // @Produce  json
+// @Param include_deleted query bool false "Include soft deleted warehouses"
 // @Success 200 {array} response.Response{data=warehouses.Warehouse} "desc"
+// @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Router /api/v1/warehouses [get]
 func (w *WarehousesController) GetAll() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if err := softdelete.FromQuery(ctx); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
 
 		warehousesList, err := w.service.GetAll(ctx)
 		if err != nil {
@@ -80,6 +93,7 @@ func (w *WarehousesController) GetAll() gin.HandlerFunc {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Warehouse ID"
+// @Param include_deleted query bool false "Include soft deleted warehouses"
 // @Success 200 {object} domain.Warehouse
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
@@ -93,6 +107,11 @@ func (w *WarehousesController) GetById() gin.HandlerFunc {
 			return
 		}
 
+		if err := softdelete.FromQuery(ctx); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
 		warehouse, err := w.service.GetById(ctx, id)
 		if err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
@@ -100,6 +119,7 @@ func (w *WarehousesController) GetById() gin.HandlerFunc {
 			return
 		}
 
+		etag.Set(ctx, warehouse.Version)
 		ctx.JSON(http.StatusOK, response.NewResponse(warehouse))
 	}
 }
@@ -107,13 +127,17 @@ func (w *WarehousesController) GetById() gin.HandlerFunc {
 // Update godoc
 // @Summary Update warehouse
 // @Tags Warehouses
-// @Description Update a warehouse by ID
+// @Description Update a warehouse by ID with a JSON merge patch (RFC 7396)
 // @Accept  json
 // @Produce  json
-// @Param warehouse body whUpdateRequest true "Warehouse to update"
+// @Param warehouse body whUpdateRequest true "Fields to update"
 // @Param id path int true "Warehouse ID"
+// @Param If-Match header string true "ETag of the warehouse being updated"
 // @Success 200 {object} domain.Warehouse
 // @Failure 404 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Router /api/v1/warehouses/{id} [patch]
 func (w *WarehousesController) Update() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -124,25 +148,30 @@ func (w *WarehousesController) Update() gin.HandlerFunc {
 			return
 		}
 
+		if err := etag.FromIfMatch(ctx); err != nil {
+			ctx.JSON(etag.Status(err), response.DecodeError(err.Error()))
+			return
+		}
+
 		var whRequest whUpdateRequest
-		if err := ctx.ShouldBindJSON(&whRequest); err != nil {
-			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		if err := patch.Bind(ctx, &whRequest); err != nil {
+			ctx.JSON(patch.Status(err), validation.NewErrorResponse(err))
 			return
 		}
 
-		warehouse, err := w.service.Update(
-			ctx,
-			id,
-			whRequest.Address,
-			whRequest.Telephone,
-			whRequest.WarehouseCode,
-			whRequest.LocalityId,
-		)
+		warehouse, err := w.service.Update(ctx, id, domain.WarehousePatch(whRequest))
 		if err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+			if errors.Is(err, etag.ErrMismatch) {
+				ctx.JSON(http.StatusPreconditionFailed, response.DecodeError(err.Error()))
+				return
+			}
+
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		etag.Set(ctx, warehouse.Version)
 		ctx.JSON(http.StatusOK, warehouse)
 	}
 }
@@ -150,11 +179,16 @@ func (w *WarehousesController) Update() gin.HandlerFunc {
 // Delete Warehouse godoc
 // @Summary Delete warehouse
 // @Tags Warehouses
-// @Description Delete a warehouse by ID
+// @Description Delete a warehouse by ID. Warehouses that still have sections or employees are not deleted unless an admin passes cascade=true, which deletes them too. A cascade is refused while those sections have batches or those employees have inbound orders
 // @Param id path int true "Warehouse ID"
+// @Param cascade query bool false "Delete the warehouse's sections and employees as well"
+// @Param If-Match header string true "ETag of the warehouse being deleted"
 // @Success 204 {object} string
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Router /api/v1/warehouses/{id} [delete]
 func (w *WarehousesController) Delete() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -165,9 +199,31 @@ func (w *WarehousesController) Delete() gin.HandlerFunc {
 			return
 		}
 
+		if err := dependents.FromQuery(ctx); err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
+		if err := etag.FromIfMatch(ctx); err != nil {
+			ctx.JSON(etag.Status(err), response.DecodeError(err.Error()))
+			return
+		}
+
 		err = w.service.Delete(ctx, id)
 		if err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+
+			if errors.Is(err, etag.ErrMismatch) {
+				ctx.JSON(http.StatusPreconditionFailed, response.DecodeError(err.Error()))
+				return
+			}
+
+			var dependentsErr *dependents.Error
+			if errors.As(err, &dependentsErr) {
+				ctx.JSON(http.StatusConflict, response.DecodeDependents(dependentsErr))
+				return
+			}
+
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
@@ -175,6 +231,37 @@ func (w *WarehousesController) Delete() gin.HandlerFunc {
 	}
 }
 
+// Restore Warehouse godoc
+// @Summary Restore warehouse
+// @Tags Warehouses
+// @Description Restore a deleted warehouse by ID. Sections and employees deleted along with it by a cascade stay deleted, each one is restored on its own
+// @Produce  json
+// @Param id path int true "Warehouse ID"
+// @Success 200 {object} domain.Warehouse
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/warehouses/{id}/restore [post]
+func (w *WarehousesController) Restore() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError("id is not valid"))
+			return
+		}
+
+		warehouse, err := w.service.Restore(ctx, id)
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			ctx.JSON(http.StatusNotFound, response.DecodeError(err.Error()))
+			return
+		}
+
+		etag.Set(ctx, warehouse.Version)
+		ctx.JSON(http.StatusOK, response.NewResponse(warehouse))
+	}
+}
+
 func NewWarehouse(w domain.WarehouseService) *WarehousesController {
 	return &WarehousesController{
 		service: w,
@@ -183,14 +270,14 @@ func NewWarehouse(w domain.WarehouseService) *WarehousesController {
 
 type whCreateRequest struct {
 	Address       string `json:"address" binding:"required"`
-	Telephone     string `json:"telephone" binding:"required"`
+	Telephone     string `json:"telephone" binding:"required,phone"`
 	WarehouseCode string `json:"warehouse_code" binding:"required"`
-	LocalityId    int    `json:"locality_id" binding:"required"`
+	LocalityId    int    `json:"locality_id" binding:"required,min=1"`
 }
 
 type whUpdateRequest struct {
-	Address       string `json:"address"`
-	Telephone     string `json:"telephone"`
-	WarehouseCode string `json:"warehouse_code"`
-	LocalityId    int    `json:"locality_id"`
+	Address       *string `json:"address" binding:"omitempty,min=1"`
+	Telephone     *string `json:"telephone" binding:"omitempty,phone"`
+	WarehouseCode *string `json:"warehouse_code" binding:"omitempty,min=1"`
+	LocalityId    *int    `json:"locality_id" binding:"omitempty,min=1"`
 }
@@ -2,11 +2,16 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain"
 	mockwarehouses "github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +23,7 @@ import (
 const (
 	relativePath       = "/api/v1/warehouses/"
 	relativePathWithId = "/api/v1/warehouses/:id"
+	relativeRestore    = "/api/v1/warehouses/:id/restore"
 	idString           = "1"
 	idNumber           = 1
 	locality           = 101
@@ -91,6 +97,7 @@ func TestWarehousesController_GetById(t *testing.T) {
 		Telephone:     "12121212",
 		WarehouseCode: "GHI",
 		LocalityId:    locality,
+		Version:       2,
 	}
 
 	service, handler, api := callWarehousesMock(t)
@@ -108,6 +115,7 @@ func TestWarehousesController_GetById(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.Equal(t, wh.WarehouseCode, respExpect.Data.WarehouseCode)
+	assert.Equal(t, `"2"`, resp.Header().Get(etag.Header))
 }
 
 func TestWarehousesController_GetById_NOK(t *testing.T) {
@@ -200,6 +208,7 @@ func TestWarehousesController_Delete_OK(t *testing.T) {
 	service.EXPECT().Delete(ctxMock, gomock.Eq(idNumber)).Return(nil)
 
 	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/warehouses/%s", idString), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
@@ -213,23 +222,133 @@ func TestWarehousesController_Delete_NOK(t *testing.T) {
 	service.EXPECT().Delete(ctxMock, gomock.Eq(idNumber)).Return(errors.New("erro 404"))
 
 	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/warehouses/%s", idString), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
 
+func TestWarehousesController_Delete_Precondition(t *testing.T) {
+	service, handler, api := callWarehousesMock(t)
+	api.DELETE(relativePathWithId, handler.Delete())
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/warehouses/1", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+
+	service.EXPECT().Delete(ctxMock, idNumber).Return(etag.ErrMismatch)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/warehouses/1", nil)
+	req.Header.Set(etag.IfMatchHeader, `"2"`)
+	resp = httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+}
+
+func TestWarehousesController_Delete_Conflict(t *testing.T) {
+	service, handler, api := callWarehousesMock(t)
+	api.DELETE(relativePathWithId, handler.Delete())
+
+	service.EXPECT().Delete(ctxMock, gomock.Eq(idNumber)).Return(&dependents.Error{
+		Entity:     "warehouse",
+		Id:         idNumber,
+		Dependents: []dependents.Dependent{{Type: "employees", Count: 3}},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/warehouses/%s", idString), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	var body response.Response
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "warehouse 1 cannot be deleted, it still has 3 employees", body.Error)
+	assert.Equal(t, []dependents.Dependent{{Type: "employees", Count: 3}}, body.Dependents)
+}
+
+func TestWarehousesController_Delete_InvalidCascade(t *testing.T) {
+	_, handler, api := callWarehousesMock(t)
+	api.DELETE(relativePathWithId, handler.Delete())
+
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/warehouses/%s?cascade=all", idString), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
 func TestWarehousesController_Delete_BadRequest(t *testing.T) {
 	_, handler, api := callWarehousesMock(t)
 	api.DELETE(relativePathWithId, handler.Delete())
 
 	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/warehouses/%s", "cuidado-Mando"), nil)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
 
+func TestWarehousesController_GetAll_IncludeDeleted(t *testing.T) {
+	service, handler, api := callWarehousesMock(t)
+	api.GET(relativePath, handler.GetAll())
+
+	service.EXPECT().GetAll(ctxMock).DoAndReturn(func(ctx context.Context) ([]domain.Warehouse, error) {
+		assert.True(t, softdelete.IncludeDeleted(ctx))
+		return []domain.Warehouse{{Id: idNumber}}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, relativePath+"?include_deleted=true", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestWarehousesController_GetAll_InvalidIncludeDeleted(t *testing.T) {
+	_, handler, api := callWarehousesMock(t)
+	api.GET(relativePath, handler.GetAll())
+
+	req := httptest.NewRequest(http.MethodGet, relativePath+"?include_deleted=maybe", nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestWarehousesController_Restore_OK(t *testing.T) {
+	service, handler, api := callWarehousesMock(t)
+	api.POST(relativeRestore, handler.Restore())
+
+	service.EXPECT().Restore(ctxMock, gomock.Eq(idNumber)).Return(domain.Warehouse{Id: idNumber}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/warehouses/%s/restore", idString), nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestWarehousesController_Restore_NOK(t *testing.T) {
+	service, handler, api := callWarehousesMock(t)
+	api.POST(relativeRestore, handler.Restore())
+
+	service.EXPECT().Restore(ctxMock, gomock.Eq(idNumber)).Return(domain.Warehouse{}, errors.New("deleted warehouse with id 1 not found"))
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/warehouses/%s/restore", idString), nil)
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
 func TestWarehousesController_Update(t *testing.T) {
 	wh := domain.Warehouse{
 		Id:            1,
@@ -242,14 +361,12 @@ func TestWarehousesController_Update(t *testing.T) {
 	service, handler, api := callWarehousesMock(t)
 	api.PATCH(relativePathWithId, handler.Update())
 
-	service.EXPECT().Update(
-		ctxMock,
-		idNumber,
-		"Rua Sem Saida",
-		"888888888",
-		"LSW",
-		locality,
-	).Return(wh, nil)
+	service.EXPECT().Update(ctxMock, idNumber, domain.WarehousePatch{
+		Address:       stringPtr("Rua Sem Saida"),
+		Telephone:     stringPtr("888888888"),
+		WarehouseCode: stringPtr("LSW"),
+		LocalityId:    intPtr(locality),
+	}).Return(wh, nil)
 
 	payload := `{"address": "Rua Sem Saida","telephone": "888888888","warehouse_code": "LSW", "locality_id": 101}`
 
@@ -258,6 +375,7 @@ func TestWarehousesController_Update(t *testing.T) {
 		fmt.Sprintf("/api/v1/warehouses/%s", idString),
 		bytes.NewBuffer([]byte(payload)),
 	)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
@@ -269,14 +387,12 @@ func TestWarehousesController_Update_NOK(t *testing.T) {
 	service, handler, api := callWarehousesMock(t)
 	api.PATCH(relativePathWithId, handler.Update())
 
-	service.EXPECT().Update(
-		ctxMock,
-		idNumber,
-		"Rua Sem Saida",
-		"888888888",
-		"LSW",
-		locality,
-	).Return(domain.Warehouse{}, errors.New("warehouse not found"))
+	service.EXPECT().Update(ctxMock, idNumber, domain.WarehousePatch{
+		Address:       stringPtr("Rua Sem Saida"),
+		Telephone:     stringPtr("888888888"),
+		WarehouseCode: stringPtr("LSW"),
+		LocalityId:    intPtr(locality),
+	}).Return(domain.Warehouse{}, errors.New("warehouse not found"))
 
 	payload := `{"address": "Rua Sem Saida","telephone": "888888888","warehouse_code": "LSW", "locality_id": 101}`
 
@@ -285,6 +401,7 @@ func TestWarehousesController_Update_NOK(t *testing.T) {
 		fmt.Sprintf("/api/v1/warehouses/%s", idString),
 		bytes.NewBuffer([]byte(payload)),
 	)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
@@ -292,6 +409,29 @@ func TestWarehousesController_Update_NOK(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
 
+func TestWarehousesController_Update_Precondition(t *testing.T) {
+	service, handler, api := callWarehousesMock(t)
+	api.PATCH(relativePathWithId, handler.Update())
+
+	payload := `{"telephone": "888888888"}`
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/warehouses/1", bytes.NewBuffer([]byte(payload)))
+	resp := httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+
+	service.EXPECT().Update(ctxMock, idNumber, domain.WarehousePatch{Telephone: stringPtr("888888888")}).
+		Return(domain.Warehouse{}, etag.ErrMismatch)
+
+	req = httptest.NewRequest(http.MethodPatch, "/api/v1/warehouses/1", bytes.NewBuffer([]byte(payload)))
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
+	resp = httptest.NewRecorder()
+	api.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+}
+
 func TestWarehousesController_Update_Fail(t *testing.T) {
 	_, handler, api := callWarehousesMock(t)
 	api.PATCH(relativePathWithId, handler.Update())
@@ -303,6 +443,7 @@ func TestWarehousesController_Update_Fail(t *testing.T) {
 		fmt.Sprintf("/api/v1/warehouses/%s", idString),
 		bytes.NewBuffer([]byte(payload)),
 	)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
@@ -321,9 +462,18 @@ func TestWarehousesController_Update_BadRequest(t *testing.T) {
 		fmt.Sprintf("/api/v1/warehouses/%s", "Valfenda"),
 		bytes.NewBuffer([]byte(payload)),
 	)
+	req.Header.Set(etag.IfMatchHeader, `"1"`)
 
 	resp := httptest.NewRecorder()
 	api.ServeHTTP(resp, req)
 
 	assert.Equal(t, http.StatusBadRequest, resp.Code)
 }
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	domain "github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain"
+	dependents "github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -94,19 +95,34 @@ func (mr *MockWarehouseServiceMockRecorder) GetById(ctx, id interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockWarehouseService)(nil).GetById), ctx, id)
 }
 
+// Restore mocks base method.
+func (m *MockWarehouseService) Restore(ctx context.Context, id int) (domain.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(domain.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockWarehouseServiceMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockWarehouseService)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockWarehouseService) Update(ctx context.Context, id int, address, telephone, warehouseCode string, localityId int) (domain.Warehouse, error) {
+func (m *MockWarehouseService) Update(ctx context.Context, id int, arg domain.WarehousePatch) (domain.Warehouse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, address, telephone, warehouseCode, localityId)
+	ret := m.ctrl.Call(m, "Update", ctx, id, arg)
 	ret0, _ := ret[0].(domain.Warehouse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockWarehouseServiceMockRecorder) Update(ctx, id, address, telephone, warehouseCode, localityId interface{}) *gomock.Call {
+func (mr *MockWarehouseServiceMockRecorder) Update(ctx, id, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWarehouseService)(nil).Update), ctx, id, address, telephone, warehouseCode, localityId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWarehouseService)(nil).Update), ctx, id, arg)
 }
 
 // MockWarehouseRepository is a mock of WarehouseRepository interface.
@@ -132,6 +148,21 @@ func (m *MockWarehouseRepository) EXPECT() *MockWarehouseRepositoryMockRecorder
 	return m.recorder
 }
 
+// CascadeDependents mocks base method.
+func (m *MockWarehouseRepository) CascadeDependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CascadeDependents", ctx, id)
+	ret0, _ := ret[0].([]dependents.Dependent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CascadeDependents indicates an expected call of CascadeDependents.
+func (mr *MockWarehouseRepositoryMockRecorder) CascadeDependents(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CascadeDependents", reflect.TypeOf((*MockWarehouseRepository)(nil).CascadeDependents), ctx, id)
+}
+
 // Create mocks base method.
 func (m *MockWarehouseRepository) Create(ctx context.Context, address, telephone, warehouseCode string, localityId int) (domain.Warehouse, error) {
 	m.ctrl.T.Helper()
@@ -161,6 +192,21 @@ func (mr *MockWarehouseRepositoryMockRecorder) Delete(ctx, id interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWarehouseRepository)(nil).Delete), ctx, id)
 }
 
+// Dependents mocks base method.
+func (m *MockWarehouseRepository) Dependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dependents", ctx, id)
+	ret0, _ := ret[0].([]dependents.Dependent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dependents indicates an expected call of Dependents.
+func (mr *MockWarehouseRepositoryMockRecorder) Dependents(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dependents", reflect.TypeOf((*MockWarehouseRepository)(nil).Dependents), ctx, id)
+}
+
 // GetAll mocks base method.
 func (m *MockWarehouseRepository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
 	m.ctrl.T.Helper()
@@ -191,17 +237,31 @@ func (mr *MockWarehouseRepositoryMockRecorder) GetById(ctx, id interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockWarehouseRepository)(nil).GetById), ctx, id)
 }
 
+// Restore mocks base method.
+func (m *MockWarehouseRepository) Restore(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockWarehouseRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockWarehouseRepository)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockWarehouseRepository) Update(ctx context.Context, id int, address, telephone, warehouseCode string, localityId int) (domain.Warehouse, error) {
+func (m *MockWarehouseRepository) Update(ctx context.Context, id int, arg domain.WarehousePatch) (domain.Warehouse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, address, telephone, warehouseCode, localityId)
+	ret := m.ctrl.Call(m, "Update", ctx, id, arg)
 	ret0, _ := ret[0].(domain.Warehouse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockWarehouseRepositoryMockRecorder) Update(ctx, id, address, telephone, warehouseCode, localityId interface{}) *gomock.Call {
+func (mr *MockWarehouseRepositoryMockRecorder) Update(ctx, id, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWarehouseRepository)(nil).Update), ctx, id, address, telephone, warehouseCode, localityId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWarehouseRepository)(nil).Update), ctx, id, arg)
 }
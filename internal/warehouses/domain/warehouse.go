@@ -1,13 +1,29 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+)
 
 type Warehouse struct {
-	Id            int    `json:"id"`
-	Address       string `json:"address"`
-	Telephone     string `json:"telephone"`
-	WarehouseCode string `json:"warehouse_code"`
-	LocalityId    int    `json:"locality_id"`
+	Id            int           `json:"id"`
+	Address       string        `json:"address"`
+	Telephone     string        `json:"telephone"`
+	WarehouseCode string        `json:"warehouse_code"`
+	LocalityId    int           `json:"locality_id"`
+	DeletedAt     date.DateTime `json:"deleted_at"`
+	Version       int           `json:"version"`
+}
+
+// WarehousePatch holds the fields of a partial update. Nil fields are left
+// untouched.
+type WarehousePatch struct {
+	Address       *string `json:"address"`
+	Telephone     *string `json:"telephone"`
+	WarehouseCode *string `json:"warehouse_code"`
+	LocalityId    *int    `json:"locality_id"`
 }
 
 //go:generate mockgen -source=./warehouse.go -destination=./mock/warehouse_mock.go
@@ -15,14 +31,18 @@ type WarehouseService interface {
 	Create(ctx context.Context, address, telephone, warehouseCode string, localityId int) (*Warehouse, error)
 	GetAll(ctx context.Context) ([]Warehouse, error)
 	GetById(ctx context.Context, id int) (Warehouse, error)
-	Update(ctx context.Context, id int, address, telephone, warehouseCode string, localityId int) (Warehouse, error)
+	Update(ctx context.Context, id int, arg WarehousePatch) (Warehouse, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) (Warehouse, error)
 }
 
 type WarehouseRepository interface {
 	Create(ctx context.Context, address, telephone, warehouseCode string, localityId int) (Warehouse, error)
 	GetAll(ctx context.Context) ([]Warehouse, error)
 	GetById(ctx context.Context, id int) (Warehouse, error)
-	Update(ctx context.Context, id int, address, telephone, warehouseCode string, localityId int) (Warehouse, error)
+	Update(ctx context.Context, id int, arg WarehousePatch) (Warehouse, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Dependents(ctx context.Context, id int) ([]dependents.Dependent, error)
+	CascadeDependents(ctx context.Context, id int) ([]dependents.Dependent, error)
 }
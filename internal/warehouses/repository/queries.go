@@ -1,9 +1,21 @@
 package repository
 
 const (
-	sqlCreate  = "INSERT INTO warehouse (address, telephone, warehouse_code, locality_id) VALUES (?, ?, ?, ?)"
-	sqlGetAll  = "SELECT id, address, telephone, warehouse_code, locality_id FROM warehouse"
-	sqlGetById = "SELECT id, address, telephone, warehouse_code, locality_id FROM warehouse WHERE id = ?"
-	sqlDelete  = "DELETE FROM warehouse WHERE id = ?"
-	sqlUpdate  = "UPDATE warehouse SET address = ?, telephone = ?, warehouse_code = ?, locality_id = ? WHERE id = ?"
+	sqlSelect = "SELECT id, address, telephone, warehouse_code, locality_id, deleted_at, version FROM warehouse"
+
+	sqlCreate             = "INSERT INTO warehouse (address, telephone, warehouse_code, locality_id) VALUES (?, ?, ?, ?)"
+	sqlGetAll             = sqlSelect + " WHERE deleted_at IS NULL"
+	sqlGetAllWithDeleted  = sqlSelect
+	sqlGetById            = sqlSelect + " WHERE id = ? AND deleted_at IS NULL"
+	sqlGetByIdWithDeleted = sqlSelect + " WHERE id = ?"
+	sqlDelete             = "UPDATE warehouse SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL"
+	sqlRestore            = "UPDATE warehouse SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL"
+	sqlCountSections      = "SELECT COUNT(*) FROM section WHERE warehouse_id = ? AND deleted_at IS NULL"
+	sqlCountEmployees     = "SELECT COUNT(*) FROM employees WHERE warehouse_id = ? AND deleted_at IS NULL"
+	// sqlCountCascadeBlockers counts the rows that keep the live sections and
+	// employees of a warehouse from being deleted along with it.
+	sqlCountCascadeBlockers = "SELECT (SELECT COUNT(*) FROM product_batches b INNER JOIN section s ON s.id = b.section_id WHERE s.warehouse_id = ? AND s.deleted_at IS NULL), (SELECT COUNT(*) FROM inbound_orders i INNER JOIN employees e ON e.id = i.employee_id WHERE e.warehouse_id = ? AND e.deleted_at IS NULL)"
+	sqlDeleteSections       = "UPDATE section SET deleted_at = ?, version = version + 1 WHERE warehouse_id = ? AND deleted_at IS NULL"
+	sqlDeleteEmployees      = "UPDATE employees SET deleted_at = ?, version = version + 1 WHERE warehouse_id = ? AND deleted_at IS NULL"
+	sqlUpdate               = "UPDATE warehouse SET address = ?, telephone = ?, warehouse_code = ?, locality_id = ?, version = version + 1 WHERE id = ? AND version = ?"
 )
@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
-	"log"
 )
 
 type repository struct {
@@ -21,13 +24,20 @@ func NewRepository(db *sql.DB) domain.WarehouseRepository {
 }
 
 func (r *repository) GetById(ctx context.Context, id int) (warehouse domain.Warehouse, err error) {
-	row := r.db.QueryRowContext(ctx, sqlGetById, id)
+	query := sqlGetById
+	if softdelete.IncludeDeleted(ctx) {
+		query = sqlGetByIdWithDeleted
+	}
+
+	row := r.db.QueryRowContext(ctx, query, id)
 	if err := row.Scan(
 		&warehouse.Id,
 		&warehouse.Address,
 		&warehouse.Telephone,
 		&warehouse.WarehouseCode,
 		&warehouse.LocalityId,
+		&warehouse.DeletedAt,
+		&warehouse.Version,
 	); err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return domain.Warehouse{}, fmt.Errorf("warehouse not found")
@@ -39,7 +49,12 @@ func (r *repository) GetById(ctx context.Context, id int) (warehouse domain.Ware
 func (r *repository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
 	var warehouses []domain.Warehouse
 
-	rows, err := r.db.QueryContext(ctx, sqlGetAll)
+	query := sqlGetAll
+	if softdelete.IncludeDeleted(ctx) {
+		query = sqlGetAllWithDeleted
+	}
+
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return []domain.Warehouse{}, err
@@ -55,6 +70,8 @@ func (r *repository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
 			&warehouse.Telephone,
 			&warehouse.WarehouseCode,
 			&warehouse.LocalityId,
+			&warehouse.DeletedAt,
+			&warehouse.Version,
 		); err != nil {
 			logger.Error(ctx, store.GetPathWithLine(), err.Error())
 			return warehouses, err
@@ -70,6 +87,7 @@ func (r *repository) Create(ctx context.Context, address, telephone, warehouseCo
 		Telephone:     telephone,
 		WarehouseCode: warehouseCode,
 		LocalityId:    localityId,
+		Version:       1,
 	}
 
 	result, err := r.db.ExecContext(ctx, sqlCreate, &address, &telephone, &warehouseCode, &localityId)
@@ -89,14 +107,7 @@ func (r *repository) Create(ctx context.Context, address, telephone, warehouseCo
 	return warehouse, nil
 }
 
-func (r *repository) Update(
-	ctx context.Context,
-	id int,
-	address,
-	telephone,
-	warehouseCode string,
-	localityId int,
-) (domain.Warehouse, error) {
+func (r *repository) Update(ctx context.Context, id int, arg domain.WarehousePatch) (domain.Warehouse, error) {
 
 	warehouse, err := r.GetById(ctx, id)
 	if err != nil {
@@ -104,20 +115,24 @@ func (r *repository) Update(
 		return domain.Warehouse{}, fmt.Errorf("warehouse not found")
 	}
 
-	if address != "" {
-		warehouse.Address = address
+	if err := etag.Check(ctx, warehouse.Version); err != nil {
+		return domain.Warehouse{}, err
+	}
+
+	if arg.Address != nil {
+		warehouse.Address = *arg.Address
 	}
 
-	if telephone != "" {
-		warehouse.Telephone = telephone
+	if arg.Telephone != nil {
+		warehouse.Telephone = *arg.Telephone
 	}
 
-	if warehouseCode != "" {
-		warehouse.WarehouseCode = warehouseCode
+	if arg.WarehouseCode != nil {
+		warehouse.WarehouseCode = *arg.WarehouseCode
 	}
 
-	if localityId != 0 {
-		warehouse.LocalityId = localityId
+	if arg.LocalityId != nil {
+		warehouse.LocalityId = *arg.LocalityId
 	}
 
 	result, err := r.db.ExecContext(
@@ -128,23 +143,132 @@ func (r *repository) Update(
 		&warehouse.WarehouseCode,
 		&warehouse.LocalityId,
 		id,
+		warehouse.Version,
 	)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.Warehouse{}, err
+	}
 
 	affected, err := result.RowsAffected()
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return domain.Warehouse{}, err
 	}
-	log.Println(affected)
+
+	// Someone else changed the warehouse between the read and the write.
+	if affected == 0 {
+		return domain.Warehouse{}, etag.ErrMismatch
+	}
+
+	warehouse.Version++
 
 	return warehouse, nil
 }
 
-func (r *repository) Delete(ctx context.Context, id int) (err error) {
-	_, err = r.db.ExecContext(ctx, sqlDelete, id)
+func (r *repository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	deletedAt := date.Now()
+
+	if dependents.Cascade(ctx) {
+		for _, query := range []string{sqlDeleteSections, sqlDeleteEmployees} {
+			if _, err := tx.ExecContext(ctx, query, deletedAt, id); err != nil {
+				logger.Error(ctx, store.GetPathWithLine(), err.Error())
+				return err
+			}
+		}
+	}
+
+	query, args := sqlDelete, []interface{}{deletedAt, id}
+	version, versioned := etag.Version(ctx)
+	if versioned {
+		query, args = sqlDelete+etag.Condition, append(args, version)
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		// The warehouse is there, just not in the version the request expects.
+		if versioned {
+			if _, err := r.GetById(ctx, id); err == nil {
+				return etag.ErrMismatch
+			}
+		}
 		logger.Error(ctx, store.GetPathWithLine(), "warehouse not found")
 		return fmt.Errorf("warehouse with id %d not found", id)
 	}
-	return
+
+	return tx.Commit()
+}
+
+// Dependents counts the live sections and employees of the warehouse.
+func (r *repository) Dependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	var sections, employees int
+
+	if err := r.db.QueryRowContext(ctx, sqlCountSections, id).Scan(&sections); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	if err := r.db.QueryRowContext(ctx, sqlCountEmployees, id).Scan(&employees); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	return []dependents.Dependent{
+		{Type: "sections", Count: sections},
+		{Type: "employees", Count: employees},
+	}, nil
+}
+
+// CascadeDependents counts the batches in the live sections and the inbound
+// orders of the live employees of the warehouse. They keep those sections
+// and employees from being deleted, so they keep a cascade from taking them.
+func (r *repository) CascadeDependents(ctx context.Context, id int) ([]dependents.Dependent, error) {
+	var batches, inboundOrders int
+
+	if err := r.db.QueryRowContext(ctx, sqlCountCascadeBlockers, id, id).Scan(&batches, &inboundOrders); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return nil, err
+	}
+
+	return []dependents.Dependent{
+		{Type: "product_batches", Count: batches},
+		{Type: "inbound_orders", Count: inboundOrders},
+	}, nil
+}
+
+func (r *repository) Restore(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, sqlRestore, id)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		logger.Error(ctx, store.GetPathWithLine(), "deleted warehouse not found")
+		return fmt.Errorf("deleted warehouse with id %d not found", id)
+	}
+
+	return nil
 }
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/etag"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/stretchr/testify/assert"
 	"regexp"
 	"testing"
@@ -33,22 +36,26 @@ func TestRepository_GetAll(t *testing.T) {
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "address", "telephone", "warehouse_code", "locality_id",
+		"id", "address", "telephone", "warehouse_code", "locality_id", "deleted_at", "version",
 	}).AddRow(
 		warehosesMock[0].Id,
 		warehosesMock[0].Address,
 		warehosesMock[0].Telephone,
 		warehosesMock[0].WarehouseCode,
 		warehosesMock[0].LocalityId,
+		nil,
+		1,
 	).AddRow(
 		warehosesMock[1].Id,
 		warehosesMock[1].Address,
 		warehosesMock[1].Telephone,
 		warehosesMock[1].WarehouseCode,
 		warehosesMock[1].LocalityId,
+		nil,
+		1,
 	)
 
-	mock.ExpectQuery(sqlGetAll).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetAll) + "$").WillReturnRows(rows)
 
 	whRepo := NewRepository(db)
 
@@ -65,7 +72,7 @@ func TestRepository_GetAll_NOK(t *testing.T) {
 
 	whMock := []domain.Warehouse{}
 
-	mock.ExpectQuery(sqlGetAll).WillReturnError(errors.New("error"))
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetAll) + "$").WillReturnError(errors.New("error"))
 	whRepo := NewRepository(db)
 
 	result, err := whRepo.GetAll(context.Background())
@@ -88,10 +95,10 @@ func TestRepository_GetById(t *testing.T) {
 	}
 
 	row := sqlmock.NewRows([]string{
-		"id", "address", "telephone", "warehouse_code", "locality_id",
-	}).AddRow(wh.Id, wh.Address, wh.Telephone, wh.WarehouseCode, wh.LocalityId)
+		"id", "address", "telephone", "warehouse_code", "locality_id", "deleted_at", "version",
+	}).AddRow(wh.Id, wh.Address, wh.Telephone, wh.WarehouseCode, wh.LocalityId, nil, 1)
 
-	mock.ExpectQuery(sqlGetById).WillReturnRows(row)
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetById) + "$").WillReturnRows(row)
 
 	whRepo := NewRepository(db)
 
@@ -105,7 +112,7 @@ func TestRepository_GetById_NOK(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(sqlGetById).WillReturnError(errors.New("error"))
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetById) + "$").WillReturnError(errors.New("error"))
 	whRepo := NewRepository(db)
 
 	result, err := whRepo.GetById(context.Background(), 1)
@@ -180,6 +187,7 @@ func TestRepository_Update(t *testing.T) {
 		Telephone:     "9888100000",
 		WarehouseCode: "TEST",
 		LocalityId:    1,
+		Version:       1,
 	}
 
 	newWhMock := domain.Warehouse{
@@ -188,19 +196,22 @@ func TestRepository_Update(t *testing.T) {
 		Telephone:     "911111111",
 		WarehouseCode: "TEST",
 		LocalityId:    1,
+		Version:       2,
 	}
 
 	rows := sqlmock.NewRows([]string{
-		"id", "address", "telephone", "warehouse_code", "locality_id",
+		"id", "address", "telephone", "warehouse_code", "locality_id", "deleted_at", "version",
 	}).AddRow(
 		whMock.Id,
 		whMock.Address,
 		whMock.Telephone,
 		whMock.WarehouseCode,
 		whMock.LocalityId,
+		nil,
+		1,
 	)
 
-	mock.ExpectQuery(sqlGetById).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetById) + "$").WillReturnRows(rows)
 
 	mock.ExpectExec(regexp.QuoteMeta(sqlUpdate)).WithArgs(
 		newWhMock.Address,
@@ -208,31 +219,55 @@ func TestRepository_Update(t *testing.T) {
 		newWhMock.WarehouseCode,
 		newWhMock.LocalityId,
 		1,
+		1,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	whRepo := NewRepository(db)
 
-	result, err := whRepo.Update(
-		context.Background(),
-		1,
-		"Rua do Teste Nova",
-		"911111111",
-		"TEST",
-		1,
-	)
+	result, err := whRepo.Update(context.Background(), 1, domain.WarehousePatch{
+		Address:   stringPtr("Rua do Teste Nova"),
+		Telephone: stringPtr("911111111"),
+	})
 
 	assert.NoError(t, err)
 	assert.Equal(t, newWhMock, result)
 }
 
+func TestRepository_Update_Version_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "address", "telephone", "warehouse_code", "locality_id", "deleted_at", "version"}
+	patch := domain.WarehousePatch{Address: stringPtr("Rua do Teste Nova")}
+	whRepo := NewRepository(db)
+
+	// The client read an older version.
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetById)).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Rua do Teste", "9888100000", "TEST", 1, nil, 3))
+
+	_, err = whRepo.Update(etag.WithVersion(context.Background(), 2), 1, patch)
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+
+	// Someone else wrote the warehouse between the read and the write.
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetById)).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Rua do Teste", "9888100000", "TEST", 1, nil, 3))
+	mock.ExpectExec(regexp.QuoteMeta(sqlUpdate)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = whRepo.Update(etag.WithVersion(context.Background(), 3), 1, patch)
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRepository_Delete(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
+	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(sqlDelete)).WithArgs(
+		sqlmock.AnyArg(),
 		1,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	warehouseRepo := NewRepository(db)
 
@@ -245,10 +280,139 @@ func TestRepository_Delete_NOk(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery(sqlDelete).WillReturnError(errors.New("error"))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(sqlDelete)).WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
 
 	slRepo := NewRepository(db)
 
 	err = slRepo.Delete(context.TODO(), 1)
 	assert.Error(t, err)
 }
+
+func TestRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(sqlDelete)).WithArgs(
+		sqlmock.AnyArg(),
+		1,
+	).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	warehouseRepo := NewRepository(db)
+
+	err = warehouseRepo.Delete(context.Background(), 1)
+	assert.EqualError(t, err, "warehouse with id 1 not found")
+}
+
+func TestRepository_Delete_Version_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(sqlDelete+etag.Condition)).
+		WithArgs(sqlmock.AnyArg(), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetById)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "address", "telephone", "warehouse_code", "locality_id", "deleted_at", "version"}).AddRow(1, "Rua do Teste", "9888100000", "TEST", 1, nil, 3))
+	mock.ExpectRollback()
+
+	warehouseRepo := NewRepository(db)
+
+	err = warehouseRepo.Delete(etag.WithVersion(context.Background(), 2), 1)
+	assert.ErrorIs(t, err, etag.ErrMismatch)
+}
+
+func TestRepository_Delete_Cascade(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(sqlDeleteSections)).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(sqlDeleteEmployees)).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(regexp.QuoteMeta(sqlDelete)).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	warehouseRepo := NewRepository(db)
+
+	err = warehouseRepo.Delete(dependents.WithCascade(context.Background()), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Dependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqlCountSections)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(sqlCountEmployees)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	warehouseRepo := NewRepository(db)
+
+	result, err := warehouseRepo.Dependents(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []dependents.Dependent{{Type: "sections", Count: 2}, {Type: "employees", Count: 0}}, result)
+}
+
+func TestRepository_CascadeDependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqlCountCascadeBlockers)).WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"batches", "inbound_orders"}).AddRow(4, 1))
+
+	warehouseRepo := NewRepository(db)
+
+	result, err := warehouseRepo.CascadeDependents(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []dependents.Dependent{{Type: "product_batches", Count: 4}, {Type: "inbound_orders", Count: 1}}, result)
+}
+
+func TestRepository_GetById_IncludeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	row := sqlmock.NewRows([]string{
+		"id", "address", "telephone", "warehouse_code", "locality_id", "deleted_at", "version",
+	}).AddRow(1, "Rua 25 de Março", "9911100011", "XYZ", 101, "2022-07-09 10:00:00", 2)
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqlGetByIdWithDeleted) + "$").WithArgs(1).WillReturnRows(row)
+
+	whRepo := NewRepository(db)
+	result, err := whRepo.GetById(softdelete.WithDeleted(context.Background()), 1)
+
+	assert.NoError(t, err)
+	assert.False(t, result.DeletedAt.IsZero())
+}
+
+func TestRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlRestore)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(sqlRestore)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	warehouseRepo := NewRepository(db)
+
+	assert.NoError(t, warehouseRepo.Restore(context.Background(), 1))
+	assert.EqualError(t, warehouseRepo.Restore(context.Background(), 2), "deleted warehouse with id 2 not found")
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
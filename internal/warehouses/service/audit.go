@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	employeesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
+	sectionsDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
+)
+
+type auditedService struct {
+	domain.WarehouseService
+	audit     auditDomain.Service
+	sections  sectionsDomain.Repository
+	employees employeesDomain.Repository
+}
+
+// NewAuditedService records every change made through s in the audit trail,
+// the sections and employees a cascading delete removes along with a
+// warehouse included.
+func NewAuditedService(s domain.WarehouseService, audit auditDomain.Service, sections sectionsDomain.Repository, employees employeesDomain.Repository) domain.WarehouseService {
+	return &auditedService{s, audit, sections, employees}
+}
+
+func (s *auditedService) Create(ctx context.Context, address, telephone, warehouseCode string, localityId int) (*domain.Warehouse, error) {
+	warehouse, err := s.WarehouseService.Create(ctx, address, telephone, warehouseCode, localityId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityWarehouses, warehouse.Id, auditDomain.ActionCreate, nil, warehouse)
+
+	return warehouse, nil
+}
+
+func (s *auditedService) Update(ctx context.Context, id int, arg domain.WarehousePatch) (domain.Warehouse, error) {
+	before, _ := s.WarehouseService.GetById(ctx, id)
+
+	warehouse, err := s.WarehouseService.Update(ctx, id, arg)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityWarehouses, id, auditDomain.ActionUpdate, before, warehouse)
+
+	return warehouse, nil
+}
+
+func (s *auditedService) Delete(ctx context.Context, id int) error {
+	before, _ := s.WarehouseService.GetById(ctx, id)
+	sections, employees := s.cascaded(ctx, id)
+
+	if err := s.WarehouseService.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityWarehouses, id, auditDomain.ActionDelete, before, nil)
+	for _, section := range sections {
+		s.audit.Record(ctx, auditDomain.EntitySections, section.Id, auditDomain.ActionDelete, section, nil)
+	}
+	for _, employee := range employees {
+		s.audit.Record(ctx, auditDomain.EntityEmployees, int(employee.Id), auditDomain.ActionDelete, employee, nil)
+	}
+
+	return nil
+}
+
+// cascaded returns the sections and employees a cascading delete of the
+// warehouse removes with it, read before they are gone. A plain delete
+// removes none.
+func (s *auditedService) cascaded(ctx context.Context, id int) ([]sectionsDomain.Section, []employeesDomain.Employee) {
+	if !dependents.Cascade(ctx) {
+		return nil, nil
+	}
+
+	allSections, _ := s.sections.GetAll(ctx)
+	allEmployees, _ := s.employees.GetAll(ctx)
+
+	sections := []sectionsDomain.Section{}
+	for _, section := range allSections {
+		if section.WarehouseId == id {
+			sections = append(sections, section)
+		}
+	}
+
+	employees := []employeesDomain.Employee{}
+	for _, employee := range allEmployees {
+		if employee.WarehouseId == id {
+			employees = append(employees, employee)
+		}
+	}
+
+	return sections, employees
+}
+
+func (s *auditedService) Restore(ctx context.Context, id int) (domain.Warehouse, error) {
+	before, _ := s.WarehouseService.GetById(softdelete.WithDeleted(ctx), id)
+
+	warehouse, err := s.WarehouseService.Restore(ctx, id)
+	if err != nil {
+		return domain.Warehouse{}, err
+	}
+
+	s.audit.Record(ctx, auditDomain.EntityWarehouses, id, auditDomain.ActionRestore, before, warehouse)
+
+	return warehouse, nil
+}
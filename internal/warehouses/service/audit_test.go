@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	auditDomain "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain"
+	mock_audit "github.com/douglmendes/mercado-fresco-round-go/internal/audit/domain/mock"
+	employeesDomain "github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain"
+	mock_employees "github.com/douglmendes/mercado-fresco-round-go/internal/employees/domain/mock"
+	sectionsDomain "github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain"
+	mock_sections "github.com/douglmendes/mercado-fresco-round-go/internal/sections/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain"
+	mock_domain "github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditedService(t *testing.T) {
+	before := domain.Warehouse{Id: 1, Address: "Old Street", WarehouseCode: "CX-1"}
+	after := domain.Warehouse{Id: 1, Address: "New Street", WarehouseCode: "CX-1"}
+
+	t.Run("Create records the new warehouse", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockWarehouseService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().Create(gomock.Any(), "Old Street", "11999999999", "CX-1", 1).Return(&before, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityWarehouses, 1, auditDomain.ActionCreate, nil, &before)
+
+		_, err := NewAuditedService(service, audit, nil, nil).Create(context.TODO(), "Old Street", "11999999999", "CX-1", 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Update records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockWarehouseService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		address := "New Street"
+		patch := domain.WarehousePatch{Address: &address}
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Update(gomock.Any(), 1, patch).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityWarehouses, 1, auditDomain.ActionUpdate, before, after)
+
+		_, err := NewAuditedService(service, audit, nil, nil).Update(context.TODO(), 1, patch)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Delete records the removed warehouse", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockWarehouseService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Delete(gomock.Any(), 1).Return(nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityWarehouses, 1, auditDomain.ActionDelete, before, nil)
+
+		assert.NoError(t, NewAuditedService(service, audit, nil, nil).Delete(context.TODO(), 1))
+	})
+	t.Run("Cascading Delete records the removed sections and employees", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockWarehouseService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+		sections := mock_sections.NewMockRepository(ctrl)
+		employees := mock_employees.NewMockRepository(ctrl)
+		ctx := dependents.WithCascade(context.TODO())
+
+		section := sectionsDomain.Section{Id: 3, SectionNumber: 10, WarehouseId: 1}
+		employee := employeesDomain.Employee{Id: 7, CardNumberId: "402323", WarehouseId: 1}
+
+		service.EXPECT().GetById(ctx, 1).Return(before, nil)
+		sections.EXPECT().GetAll(ctx).Return([]sectionsDomain.Section{section, {Id: 4, WarehouseId: 2}}, nil)
+		employees.EXPECT().GetAll(ctx).Return([]employeesDomain.Employee{employee, {Id: 8, WarehouseId: 2}}, nil)
+		service.EXPECT().Delete(ctx, 1).Return(nil)
+		audit.EXPECT().Record(ctx, auditDomain.EntityWarehouses, 1, auditDomain.ActionDelete, before, nil)
+		audit.EXPECT().Record(ctx, auditDomain.EntitySections, 3, auditDomain.ActionDelete, section, nil)
+		audit.EXPECT().Record(ctx, auditDomain.EntityEmployees, 7, auditDomain.ActionDelete, employee, nil)
+
+		assert.NoError(t, NewAuditedService(service, audit, sections, employees).Delete(ctx, 1))
+	})
+
+	t.Run("Restore records both states", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := mock_domain.NewMockWarehouseService(ctrl)
+		audit := mock_audit.NewMockService(ctrl)
+
+		service.EXPECT().GetById(gomock.Any(), 1).Return(before, nil)
+		service.EXPECT().Restore(gomock.Any(), 1).Return(after, nil)
+		audit.EXPECT().Record(gomock.Any(), auditDomain.EntityWarehouses, 1, auditDomain.ActionRestore, before, after)
+
+		_, err := NewAuditedService(service, audit, nil, nil).Restore(context.TODO(), 1)
+		assert.NoError(t, err)
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
 )
 
@@ -45,7 +47,7 @@ func (s *service) Create(
 	localityId int,
 ) (*domain.Warehouse, error) {
 
-	whList, err := s.repository.GetAll(ctx)
+	whList, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return nil, err
@@ -66,14 +68,7 @@ func (s *service) Create(
 
 }
 
-func (s *service) Update(
-	ctx context.Context,
-	id int,
-	address,
-	telephone,
-	warehouseCode string,
-	localityId int,
-) (domain.Warehouse, error) {
+func (s *service) Update(ctx context.Context, id int, arg domain.WarehousePatch) (domain.Warehouse, error) {
 
 	warehouse, err := s.repository.GetById(ctx, id)
 	if err != nil {
@@ -81,30 +76,57 @@ func (s *service) Update(
 		return domain.Warehouse{}, err
 	}
 
-	if warehouse.WarehouseCode == warehouseCode {
-		return s.repository.Update(ctx, id, address, telephone, warehouseCode, localityId)
+	if arg.WarehouseCode == nil || warehouse.WarehouseCode == *arg.WarehouseCode {
+		return s.repository.Update(ctx, id, arg)
 	}
 
-	whList, err := s.repository.GetAll(ctx)
+	whList, err := s.repository.GetAll(softdelete.WithDeleted(ctx))
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return domain.Warehouse{}, err
 	}
 
 	for _, warehouse := range whList {
-		if warehouse.WarehouseCode == warehouseCode {
+		if warehouse.Id != id && warehouse.WarehouseCode == *arg.WarehouseCode {
 			return domain.Warehouse{}, fmt.Errorf("this warehouse already exists")
 		}
 	}
 
-	return s.repository.Update(ctx, id, address, telephone, warehouseCode, localityId)
+	return s.repository.Update(ctx, id, arg)
 }
 
 func (s *service) Delete(ctx context.Context, id int) error {
-	err := s.repository.Delete(ctx, id)
+	// A cascading delete takes the warehouse's sections and employees along,
+	// as long as nothing keeps those from being deleted themselves.
+	dependentsOf := s.repository.Dependents
+	if dependents.Cascade(ctx) {
+		dependentsOf = s.repository.CascadeDependents
+	}
+
+	found, err := dependentsOf(ctx, id)
+	if err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	if err := dependents.Check("warehouse", id, found...); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return err
+	}
+
+	err = s.repository.Delete(ctx, id)
 	if err != nil {
 		logger.Error(ctx, store.GetPathWithLine(), err.Error())
 		return err
 	}
 	return nil
 }
+
+func (s *service) Restore(ctx context.Context, id int) (domain.Warehouse, error) {
+	if err := s.repository.Restore(ctx, id); err != nil {
+		logger.Error(ctx, store.GetPathWithLine(), err.Error())
+		return domain.Warehouse{}, err
+	}
+
+	return s.repository.GetById(ctx, id)
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain"
 	mockWarehouses "github.com/douglmendes/mercado-fresco-round-go/internal/warehouses/domain/mock"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/softdelete"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -92,6 +94,7 @@ func TestService_GetById_NOK(t *testing.T) {
 func TestService_Delete_OK(t *testing.T) {
 	apiMock, service, ctxTest := callMock(t)
 
+	apiMock.EXPECT().Dependents(ctxTest, gomock.Eq(id)).Return(nil, nil)
 	apiMock.EXPECT().Delete(ctxTest, gomock.Eq(id)).Return(nil)
 
 	err := service.Delete(ctxTest, id)
@@ -101,29 +104,97 @@ func TestService_Delete_OK(t *testing.T) {
 func TestService_Delete_NOK(t *testing.T) {
 	apiMock, service, ctxTest := callMock(t)
 
+	apiMock.EXPECT().Dependents(ctxTest, gomock.Eq(id)).Return(nil, nil)
 	apiMock.EXPECT().Delete(ctxTest, gomock.Eq(id)).Return(errors.New("error id is not valid"))
 
 	err := service.Delete(ctxTest, id)
 	assert.NotNil(t, err)
 }
 
+func TestService_Delete_WithDependents(t *testing.T) {
+	apiMock, service, ctxTest := callMock(t)
+
+	apiMock.EXPECT().Dependents(ctxTest, gomock.Eq(id)).Return([]dependents.Dependent{
+		{Type: "sections", Count: 2},
+		{Type: "employees", Count: 0},
+	}, nil)
+
+	err := service.Delete(ctxTest, id)
+
+	var dependentsErr *dependents.Error
+	assert.ErrorAs(t, err, &dependentsErr)
+	assert.Equal(t, []dependents.Dependent{{Type: "sections", Count: 2}}, dependentsErr.Dependents)
+}
+
+func TestService_Delete_Cascade(t *testing.T) {
+	apiMock, service, ctxTest := callMock(t)
+
+	cascadeCtx := dependents.WithCascade(ctxTest)
+	apiMock.EXPECT().CascadeDependents(cascadeCtx, gomock.Eq(id)).Return([]dependents.Dependent{
+		{Type: "product_batches", Count: 0},
+		{Type: "inbound_orders", Count: 0},
+	}, nil)
+	apiMock.EXPECT().Delete(cascadeCtx, gomock.Eq(id)).Return(nil)
+
+	err := service.Delete(cascadeCtx, id)
+	assert.Nil(t, err)
+}
+
+func TestService_Delete_Cascade_Blocked(t *testing.T) {
+	apiMock, service, ctxTest := callMock(t)
+
+	cascadeCtx := dependents.WithCascade(ctxTest)
+	apiMock.EXPECT().CascadeDependents(cascadeCtx, gomock.Eq(id)).Return([]dependents.Dependent{
+		{Type: "product_batches", Count: 4},
+		{Type: "inbound_orders", Count: 0},
+	}, nil)
+
+	err := service.Delete(cascadeCtx, id)
+
+	var dependentsErr *dependents.Error
+	assert.ErrorAs(t, err, &dependentsErr)
+	assert.Equal(t, []dependents.Dependent{{Type: "product_batches", Count: 4}}, dependentsErr.Dependents)
+}
+
+func TestService_Restore_OK(t *testing.T) {
+	apiMock, service, ctxTest := callMock(t)
+
+	wh := domain.Warehouse{Id: id, WarehouseCode: "ABC"}
+
+	apiMock.EXPECT().Restore(ctxTest, gomock.Eq(id)).Return(nil)
+	apiMock.EXPECT().GetById(ctxTest, gomock.Eq(id)).Return(wh, nil)
+
+	result, err := service.Restore(ctxTest, id)
+	assert.Nil(t, err)
+	assert.Equal(t, wh, result)
+}
+
+func TestService_Restore_NOK(t *testing.T) {
+	apiMock, service, ctxTest := callMock(t)
+
+	apiMock.EXPECT().Restore(ctxTest, gomock.Eq(id)).Return(errors.New("deleted warehouse with id 1 not found"))
+
+	_, err := service.Restore(ctxTest, id)
+	assert.NotNil(t, err)
+}
+
 func TestServiceCreate_OK(t *testing.T) {
 	apiMock, service, ctxTest := callMock(t)
 
 	wh := []domain.Warehouse{
 		{
-			1,
-			"Rua Café Torrado",
-			"918288888",
-			"ABC",
-			101,
+			Id:            1,
+			Address:       "Rua Café Torrado",
+			Telephone:     "918288888",
+			WarehouseCode: "ABC",
+			LocalityId:    101,
 		},
 		{
-			2,
-			"Av. Santo Agostinho",
-			"7777777777",
-			"DEF",
-			102,
+			Id:            2,
+			Address:       "Av. Santo Agostinho",
+			Telephone:     "7777777777",
+			WarehouseCode: "DEF",
+			LocalityId:    102,
 		},
 	}
 
@@ -135,7 +206,7 @@ func TestServiceCreate_OK(t *testing.T) {
 		LocalityId:    101,
 	}
 
-	apiMock.EXPECT().GetAll(ctxTest).Return(wh, nil)
+	apiMock.EXPECT().GetAll(softdelete.WithDeleted(ctxTest)).Return(wh, nil)
 	apiMock.EXPECT().Create(
 		ctxTest,
 		"Rua Nova",
@@ -176,7 +247,7 @@ func TestServiceCreate_Conflict(t *testing.T) {
 		},
 	}
 
-	apiMock.EXPECT().GetAll(ctxTest).Return(wh, nil)
+	apiMock.EXPECT().GetAll(softdelete.WithDeleted(ctxTest)).Return(wh, nil)
 	apiMock.EXPECT().Create(
 		ctxTest,
 		"Rua Nova",
@@ -199,7 +270,7 @@ func TestServiceCreate_Conflict(t *testing.T) {
 
 func TestService_Create_GetAll_Fail(t *testing.T) {
 	apiMock, service, ctxTest := callMock(t)
-	apiMock.EXPECT().GetAll(ctxTest).Return([]domain.Warehouse{}, errors.New("error"))
+	apiMock.EXPECT().GetAll(softdelete.WithDeleted(ctxTest)).Return([]domain.Warehouse{}, errors.New("error"))
 
 	_, err := service.Create(
 		ctxTest,
@@ -215,15 +286,15 @@ func TestService_Create_NOK(t *testing.T) {
 	apiMock, service, ctxTest := callMock(t)
 	wh := []domain.Warehouse{
 		{
-			1,
-			"Rua Café Torrado",
-			"918288888",
-			"ABC",
-			1,
+			Id:            1,
+			Address:       "Rua Café Torrado",
+			Telephone:     "918288888",
+			WarehouseCode: "ABC",
+			LocalityId:    1,
 		},
 	}
 
-	apiMock.EXPECT().GetAll(ctxTest).Return(wh, nil)
+	apiMock.EXPECT().GetAll(softdelete.WithDeleted(ctxTest)).Return(wh, nil)
 	apiMock.EXPECT().Create(
 		ctxTest,
 		"Rua Nova",
@@ -264,23 +335,19 @@ func TestService_Update_WithTheSameWarehouseCode(t *testing.T) {
 	}
 
 	apiMock.EXPECT().GetById(ctxTest, gomock.Eq(id)).Return(oldWh, nil)
-	apiMock.EXPECT().Update(
-		ctxTest,
-		id,
-		"Rua 23 de Março",
-		"8899900099",
-		"XYZ",
-		2,
-	).Return(newWh, nil)
-
-	result, err := service.Update(
-		ctxTest,
-		id,
-		"Rua 23 de Março",
-		"8899900099",
-		"XYZ",
-		2,
-	)
+	apiMock.EXPECT().Update(ctxTest, id, domain.WarehousePatch{
+		Address:       stringPtr("Rua 23 de Março"),
+		Telephone:     stringPtr("8899900099"),
+		WarehouseCode: stringPtr("XYZ"),
+		LocalityId:    intPtr(2),
+	}).Return(newWh, nil)
+
+	result, err := service.Update(ctxTest, id, domain.WarehousePatch{
+		Address:       stringPtr("Rua 23 de Março"),
+		Telephone:     stringPtr("8899900099"),
+		WarehouseCode: stringPtr("XYZ"),
+		LocalityId:    intPtr(2),
+	})
 
 	assert.Equal(t, result, newWh)
 	assert.Nil(t, err)
@@ -323,24 +390,20 @@ func TestService_Update_WithOtherWarehouseCode(t *testing.T) {
 	}
 
 	apiMock.EXPECT().GetById(ctxTest, gomock.Eq(id)).Return(oldWh, nil)
-	apiMock.EXPECT().GetAll(ctxTest).Return(whList, nil)
-	apiMock.EXPECT().Update(
-		ctxTest,
-		1,
-		"Av. Paulista",
-		"000000000",
-		"LLL",
-		10,
-	).Return(newWh, nil)
-
-	result, err := service.Update(
-		ctxTest,
-		1,
-		"Av. Paulista",
-		"000000000",
-		"LLL",
-		10,
-	)
+	apiMock.EXPECT().GetAll(softdelete.WithDeleted(ctxTest)).Return(whList, nil)
+	apiMock.EXPECT().Update(ctxTest, 1, domain.WarehousePatch{
+		Address:       stringPtr("Av. Paulista"),
+		Telephone:     stringPtr("000000000"),
+		WarehouseCode: stringPtr("LLL"),
+		LocalityId:    intPtr(10),
+	}).Return(newWh, nil)
+
+	result, err := service.Update(ctxTest, 1, domain.WarehousePatch{
+		Address:       stringPtr("Av. Paulista"),
+		Telephone:     stringPtr("000000000"),
+		WarehouseCode: stringPtr("LLL"),
+		LocalityId:    intPtr(10),
+	})
 
 	assert.Equal(t, result, newWh)
 	assert.Nil(t, err)
@@ -375,18 +438,24 @@ func TestService_Update_WithOtherWarehouseCode_NOK(t *testing.T) {
 	}
 
 	apiMock.EXPECT().GetById(ctxTest, gomock.Eq(id)).Return(oldWh, nil)
-	apiMock.EXPECT().GetAll(ctxTest).Return(whList, nil)
+	apiMock.EXPECT().GetAll(softdelete.WithDeleted(ctxTest)).Return(whList, nil)
 
-	result, err := service.Update(
-		ctxTest,
-		1,
-		"Av. Paulista",
-		"000000000",
-		"GNK",
-		10,
-	)
+	result, err := service.Update(ctxTest, 1, domain.WarehousePatch{
+		Address:       stringPtr("Av. Paulista"),
+		Telephone:     stringPtr("000000000"),
+		WarehouseCode: stringPtr("GNK"),
+		LocalityId:    intPtr(10),
+	})
 
 	assert.Equal(t, result, domain.Warehouse{})
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, "this warehouse already exists")
 }
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -0,0 +1,146 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/transaction"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// QueryParam makes an import all-or-nothing: with ?atomic=true the rows are
+// created in one transaction, which is rolled back when any of them fails.
+const QueryParam = "atomic"
+
+const (
+	StatusCreated    = "created"
+	StatusUpdated    = "updated"
+	StatusFailed     = "failed"
+	StatusRolledBack = "rolled_back"
+)
+
+var errRowsFailed = errors.New("some rows failed")
+
+// Result is the outcome of one row. Rows are numbered from 1, not counting
+// the CSV header.
+type Result struct {
+	Row    int                   `json:"row"`
+	Status string                `json:"status"`
+	Id     int                   `json:"id,omitempty"`
+	Error  string                `json:"error,omitempty"`
+	Fields []response.FieldError `json:"fields,omitempty"`
+}
+
+// Report sums up the rows of an import. Updated is only set by the batches
+// that update records.
+type Report struct {
+	Atomic  bool     `json:"atomic"`
+	Total   int      `json:"total"`
+	Created int      `json:"created"`
+	Updated int      `json:"updated,omitempty"`
+	Failed  int      `json:"failed"`
+	Rows    []Result `json:"rows"`
+}
+
+// Create stores one row and returns the id of the new record.
+type Create[T any] func(ctx context.Context, row T) (int, error)
+
+// Handler imports the CSV or NDJSON upload of a request. Every row is bound
+// to T and validated with its binding rules, like the body of a single
+// create, and then handed to create. The response reports each row.
+func Handler[T any](runner transaction.Runner, create Create[T]) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		atomic, err := strconv.ParseBool(ctx.DefaultQuery(QueryParam, "false"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError("atomic must be true or false"))
+			return
+		}
+
+		rows, err := read[T](ctx)
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
+		report := Report{Atomic: atomic, Total: len(rows), Rows: make([]Result, 0, len(rows))}
+
+		importRows := func(ctx context.Context) error {
+			for i, row := range rows {
+				result := importRow(ctx, i+1, row, create)
+				if result.Status == StatusCreated {
+					report.Created++
+				} else {
+					report.Failed++
+				}
+				report.Rows = append(report.Rows, result)
+			}
+
+			if report.Failed > 0 {
+				return errRowsFailed
+			}
+			return nil
+		}
+
+		if !atomic {
+			_ = importRows(ctx)
+			ctx.JSON(http.StatusOK, response.NewResponse(report))
+			return
+		}
+
+		err = runner.Run(ctx, importRows)
+		if err == nil {
+			ctx.JSON(http.StatusOK, response.NewResponse(report))
+			return
+		}
+
+		if !errors.Is(err, errRowsFailed) {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			ctx.JSON(http.StatusInternalServerError, response.DecodeError(err.Error()))
+			return
+		}
+
+		// Nothing was kept, so the rows that went through are reported as
+		// rolled back.
+		for i := range report.Rows {
+			if report.Rows[i].Status == StatusCreated {
+				report.Rows[i].Status = StatusRolledBack
+				report.Rows[i].Id = 0
+			}
+		}
+		report.Created = 0
+
+		ctx.JSON(http.StatusUnprocessableEntity, response.NewResponse(report))
+	}
+}
+
+func importRow[T any](ctx context.Context, number int, row decoded[T], create Create[T]) Result {
+	result := Result{Row: number, Status: StatusFailed}
+
+	err := row.err
+	if err == nil {
+		err = binding.Validator.ValidateStruct(&row.value)
+	}
+	if err != nil {
+		result.Error = validation.Message
+		result.Fields = validation.Fields(err)
+		return result
+	}
+
+	id, err := create(ctx, row.value)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Status = StatusCreated
+	result.Id = id
+	return result
+}
@@ -0,0 +1,236 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	MIMECSV    = "text/csv"
+	MIMENDJSON = "application/x-ndjson"
+
+	// FileField is the form field of multipart uploads.
+	FileField = "file"
+
+	// MaxRows bounds the size of one import.
+	MaxRows = 5000
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("the upload must be CSV (text/csv) or NDJSON (application/x-ndjson)")
+	ErrNoRows            = errors.New("the upload has no rows")
+	ErrTooManyRows       = fmt.Errorf("the upload has more than %d rows", MaxRows)
+)
+
+// decoded is a row read from the upload, or the reason it could not be read
+// into the row type.
+type decoded[T any] struct {
+	value T
+	err   error
+}
+
+// read decodes the rows of the request body, or of the file field of a
+// multipart form. Errors returned here reject the whole upload; errors of a
+// single row are kept with the row.
+func read[T any](ctx *gin.Context) ([]decoded[T], error) {
+	body, format, err := upload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var rows []decoded[T]
+	switch format {
+	case MIMECSV:
+		rows, err = readCSV[T](body)
+	case MIMENDJSON:
+		rows, err = readNDJSON[T](body)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrNoRows
+	}
+
+	return rows, nil
+}
+
+func upload(ctx *gin.Context) (io.ReadCloser, string, error) {
+	if ctx.ContentType() != gin.MIMEMultipartPOSTForm {
+		return ctx.Request.Body, ctx.ContentType(), nil
+	}
+
+	header, err := ctx.FormFile(FileField)
+	if err != nil {
+		return nil, "", fmt.Errorf("the %s field is required", FileField)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, "", err
+	}
+
+	format, _, _ := mime.ParseMediaType(header.Header.Get("Content-Type"))
+	if format != MIMECSV && format != MIMENDJSON {
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".csv":
+			format = MIMECSV
+		case ".ndjson", ".jsonl":
+			format = MIMENDJSON
+		}
+	}
+
+	return file, format, nil
+}
+
+func readNDJSON[T any](body io.Reader) ([]decoded[T], error) {
+	rows := []decoded[T]{}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if len(rows) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+
+		var row decoded[T]
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		row.err = decoder.Decode(&row.value)
+
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// readCSV maps the columns named in the header to the JSON names of the
+// fields of T. Cells are converted to the type of their field and empty
+// cells are left out, so required fields are reported as missing.
+func readCSV[T any](body io.Reader) ([]decoded[T], error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fields := jsonFields(reflect.TypeOf((*T)(nil)).Elem())
+	columns := make([]reflect.StructField, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		header[i], columns[i] = name, field
+	}
+
+	rows := []decoded[T]{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+
+		rows = append(rows, decodeRecord[T](header, columns, record))
+	}
+
+	return rows, nil
+}
+
+func decodeRecord[T any](header []string, columns []reflect.StructField, record []string) decoded[T] {
+	var row decoded[T]
+
+	values := map[string]interface{}{}
+	for i, cell := range record {
+		if cell == "" {
+			continue
+		}
+
+		value, err := convert(cell, columns[i].Type)
+		if err != nil {
+			row.err = &json.UnmarshalTypeError{Value: "string", Type: columns[i].Type, Field: header[i]}
+			return row
+		}
+		values[header[i]] = value
+	}
+
+	data, err := json.Marshal(values)
+	if err == nil {
+		err = json.Unmarshal(data, &row.value)
+	}
+	row.err = err
+
+	return row
+}
+
+func convert(cell string, typ reflect.Type) (interface{}, error) {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(cell, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(cell, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(cell, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(cell)
+	}
+	return cell, nil
+}
+
+func jsonFields(typ reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
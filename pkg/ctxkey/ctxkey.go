@@ -0,0 +1,45 @@
+// Package ctxkey names the values a request carries in its context, such as
+// the seller it is scoped to or the transaction it runs in.
+//
+// Middlewares store those values on the *gin.Context with Set, which only
+// takes string keys, and *gin.Context.Value only looks up string keys among
+// them. Everywhere else values are added with context.WithValue under the
+// unexported key type, which no other package can collide with. Value finds
+// a value stored either way, so handlers may pass the *gin.Context on as the
+// context of the services they call.
+package ctxkey
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+type key string
+
+const (
+	SellerId       key = "tenant.seller_id"
+	IncludeDeleted key = "softdelete.include_deleted"
+	Version        key = "etag.version"
+	Cascade        key = "dependents.cascade"
+	Principal      key = "auth.principal"
+	Tx             key = "transaction.tx"
+)
+
+// With returns a copy of ctx that carries value under k.
+func With(ctx context.Context, k key, value interface{}) context.Context {
+	return context.WithValue(ctx, k, value)
+}
+
+// Set stores value under k on the request of ctx.
+func Set(ctx *gin.Context, k key, value interface{}) {
+	ctx.Set(string(k), value)
+}
+
+// Value returns the value ctx carries under k, or nil when it has none.
+func Value(ctx context.Context, k key) interface{} {
+	if value := ctx.Value(k); value != nil {
+		return value
+	}
+	return ctx.Value(string(k))
+}
@@ -0,0 +1,29 @@
+package ctxkey
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValue(t *testing.T) {
+	t.Run("reads values added with With", func(t *testing.T) {
+		ctx := With(context.Background(), SellerId, 5)
+
+		assert.Equal(t, 5, Value(ctx, SellerId))
+		assert.Nil(t, Value(ctx, Version))
+	})
+
+	t.Run("reads values Set on the gin request through wrapping contexts", func(t *testing.T) {
+		gctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		Set(gctx, SellerId, 5)
+
+		ctx := With(gctx, IncludeDeleted, true)
+
+		assert.Equal(t, 5, Value(ctx, SellerId))
+		assert.Equal(t, true, Value(ctx, IncludeDeleted))
+	})
+}
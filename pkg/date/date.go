@@ -0,0 +1,254 @@
+package date
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Layout is the ISO 8601 calendar date format used for DATE columns and
+	// for Date values in JSON.
+	Layout = "2006-01-02"
+	// SQLLayout is the format MySQL uses for DATETIME columns.
+	SQLLayout = "2006-01-02 15:04:05"
+)
+
+var location = time.UTC
+
+// Location returns the time zone used to interpret DATETIME columns and to
+// decide which day is today.
+func Location() *time.Location {
+	return location
+}
+
+// SetLocation changes the time zone returned by Location.
+func SetLocation(loc *time.Location) {
+	location = loc
+}
+
+// LoadLocation sets the time zone by its IANA name, e.g.
+// "America/Sao_Paulo". An empty name means UTC.
+func LoadLocation(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+
+	SetLocation(loc)
+	return nil
+}
+
+// Date is a calendar date without a time of day. It is stored at midnight
+// UTC, so two dates compare equal whatever the configured location is.
+type Date struct {
+	time.Time
+}
+
+func New(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// FromTime returns the date t falls on in the configured location.
+func FromTime(t time.Time) Date {
+	return New(t.In(location).Date())
+}
+
+func Today() Date {
+	return FromTime(time.Now())
+}
+
+func Parse(value string) (Date, error) {
+	t, err := time.ParseInLocation(Layout, value, time.UTC)
+	if err != nil {
+		return Date{}, err
+	}
+
+	return Date{t}, nil
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(Layout)
+}
+
+func (d Date) AddDays(days int) Date {
+	return Date{d.Time.AddDate(0, 0, days)}
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid date %s", data)
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid date %s", data)
+	}
+
+	*d = parsed
+	return nil
+}
+
+func (d *Date) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = New(value.Date())
+		return nil
+	case []byte:
+		return d.scanString(string(value))
+	case string:
+		return d.scanString(value)
+	}
+	return fmt.Errorf("cannot scan %T into date.Date", src)
+}
+
+func (d *Date) scanString(value string) error {
+	// DATETIME columns may also be read as dates.
+	if len(value) > len(Layout) {
+		value = value[:len(Layout)]
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+// DateTime is an instant in time, stored in DATETIME columns as the wall
+// clock of the configured location and serialized in JSON as RFC 3339.
+type DateTime struct {
+	time.Time
+}
+
+// dateTimeLayouts are the accepted inputs; values without an offset are
+// read in the configured location.
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	SQLLayout,
+	Layout,
+}
+
+func NewDateTime(t time.Time) DateTime {
+	return DateTime{t.In(location)}
+}
+
+func Now() DateTime {
+	return NewDateTime(time.Now())
+}
+
+func ParseDateTime(value string) (DateTime, error) {
+	var err error
+	for _, layout := range dateTimeLayouts {
+		var t time.Time
+		t, err = time.ParseInLocation(layout, value, location)
+		if err == nil {
+			return DateTime{t.In(location)}, nil
+		}
+	}
+
+	return DateTime{}, err
+}
+
+// Date returns the calendar day of dt in the configured location.
+func (dt DateTime) Date() Date {
+	return FromTime(dt.Time)
+}
+
+func (dt DateTime) String() string {
+	if dt.IsZero() {
+		return ""
+	}
+	return dt.In(location).Format(time.RFC3339)
+}
+
+func (dt DateTime) MarshalJSON() ([]byte, error) {
+	if dt.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(dt.String())
+}
+
+func (dt *DateTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*dt = DateTime{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid date and time %s", data)
+	}
+
+	parsed, err := ParseDateTime(value)
+	if err != nil {
+		return fmt.Errorf("invalid date and time %s", data)
+	}
+
+	*dt = parsed
+	return nil
+}
+
+func (dt *DateTime) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*dt = DateTime{}
+		return nil
+	case time.Time:
+		*dt = NewDateTime(value)
+		return nil
+	case []byte:
+		return dt.scanString(string(value))
+	case string:
+		return dt.scanString(value)
+	}
+	return fmt.Errorf("cannot scan %T into date.DateTime", src)
+}
+
+func (dt *DateTime) scanString(value string) error {
+	parsed, err := ParseDateTime(strings.TrimSpace(value))
+	if err != nil {
+		return err
+	}
+
+	*dt = parsed
+	return nil
+}
+
+func (dt DateTime) Value() (driver.Value, error) {
+	if dt.IsZero() {
+		return nil, nil
+	}
+	return dt.In(location).Format(SQLLayout), nil
+}
@@ -0,0 +1,244 @@
+package date
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// saoPaulo has the offset of America/Sao_Paulo, without relying on the
+// time zone database of the machine.
+var saoPaulo = time.FixedZone("-03", -3*60*60)
+
+// inLocation runs the test with Location set to loc, restoring it after.
+func inLocation(t *testing.T, loc *time.Location) {
+	previous := Location()
+	SetLocation(loc)
+	t.Cleanup(func() { SetLocation(previous) })
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected Date
+		wantErr  bool
+	}{
+		{name: "Date", value: "2022-08-01", expected: New(2022, 8, 1)},
+		{name: "Leap day", value: "2024-02-29", expected: New(2024, 2, 29)},
+		{name: "Not a leap year", value: "2022-02-29", wantErr: true},
+		{name: "Day first", value: "01/08/2022", wantErr: true},
+		{name: "With time", value: "2022-08-01T10:00:00Z", wantErr: true},
+		{name: "Empty", value: "", wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := Parse(testCase.value)
+
+			if testCase.wantErr {
+				assert.Error(t, err)
+				assert.True(t, result.IsZero())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	inLocation(t, saoPaulo)
+
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{name: "RFC 3339", value: "2022-08-01T13:00:00Z", expected: time.Date(2022, 8, 1, 13, 0, 0, 0, time.UTC)},
+		{name: "Without offset", value: "2022-08-01T10:00:00", expected: time.Date(2022, 8, 1, 13, 0, 0, 0, time.UTC)},
+		{name: "SQL", value: "2022-08-01 10:00:00", expected: time.Date(2022, 8, 1, 13, 0, 0, 0, time.UTC)},
+		{name: "Date only", value: "2022-08-01", expected: time.Date(2022, 8, 1, 3, 0, 0, 0, time.UTC)},
+		{name: "Invalid", value: "yesterday", wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := ParseDateTime(testCase.value)
+
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, testCase.expected.Equal(result.Time), "got %s", result)
+		})
+	}
+}
+
+func TestDate_JSON(t *testing.T) {
+	testCases := []struct {
+		name string
+		date Date
+		json string
+	}{
+		{name: "Date", date: New(2022, 8, 1), json: `"2022-08-01"`},
+		{name: "Zero", date: Date{}, json: `null`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			data, err := json.Marshal(testCase.date)
+			assert.NoError(t, err)
+			assert.JSONEq(t, testCase.json, string(data))
+
+			var result Date
+			assert.NoError(t, json.Unmarshal(data, &result))
+			assert.Equal(t, testCase.date, result)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, data := range []string{`"01/08/2022"`, `20220801`, `""`} {
+			var result Date
+			assert.EqualError(t, json.Unmarshal([]byte(data), &result), "invalid date "+data)
+		}
+	})
+}
+
+func TestDateTime_JSON(t *testing.T) {
+	inLocation(t, saoPaulo)
+
+	testCases := []struct {
+		name     string
+		dateTime DateTime
+		json     string
+	}{
+		{name: "Date and time", dateTime: NewDateTime(time.Date(2022, 8, 1, 13, 0, 0, 0, time.UTC)), json: `"2022-08-01T10:00:00-03:00"`},
+		{name: "Zero", dateTime: DateTime{}, json: `null`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			data, err := json.Marshal(testCase.dateTime)
+			assert.NoError(t, err)
+			assert.JSONEq(t, testCase.json, string(data))
+
+			var result DateTime
+			assert.NoError(t, json.Unmarshal(data, &result))
+			assert.True(t, testCase.dateTime.Equal(result.Time), "got %s", result)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, data := range []string{`"yesterday"`, `1659358800`} {
+			var result DateTime
+			assert.EqualError(t, json.Unmarshal([]byte(data), &result), "invalid date and time "+data)
+		}
+	})
+}
+
+func TestDate_SQL(t *testing.T) {
+	testCases := []struct {
+		name  string
+		date  Date
+		value driver.Value
+	}{
+		{name: "Date", date: New(2022, 8, 1), value: "2022-08-01"},
+		{name: "Zero", date: Date{}, value: nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, err := testCase.date.Value()
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.value, value)
+
+			var result Date
+			assert.NoError(t, result.Scan(value))
+			assert.Equal(t, testCase.date, result)
+		})
+	}
+
+	t.Run("Scan", func(t *testing.T) {
+		scanned := []interface{}{
+			time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+			[]byte("2022-08-01"),
+			"2022-08-01 10:00:00",
+		}
+		for _, src := range scanned {
+			var result Date
+			assert.NoError(t, result.Scan(src))
+			assert.Equal(t, New(2022, 8, 1), result)
+		}
+
+		var result Date
+		assert.EqualError(t, result.Scan(20220801), "cannot scan int into date.Date")
+		assert.Error(t, result.Scan("yesterday"))
+	})
+}
+
+func TestDateTime_SQL(t *testing.T) {
+	inLocation(t, saoPaulo)
+
+	testCases := []struct {
+		name     string
+		dateTime DateTime
+		value    driver.Value
+	}{
+		{name: "Date and time", dateTime: NewDateTime(time.Date(2022, 8, 1, 13, 0, 0, 0, time.UTC)), value: "2022-08-01 10:00:00"},
+		{name: "Zero", dateTime: DateTime{}, value: nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, err := testCase.dateTime.Value()
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.value, value)
+
+			var result DateTime
+			assert.NoError(t, result.Scan(value))
+			assert.True(t, testCase.dateTime.Equal(result.Time), "got %s", result)
+		})
+	}
+
+	t.Run("Scan", func(t *testing.T) {
+		var result DateTime
+		assert.NoError(t, result.Scan([]byte("2022-08-01 10:00:00")))
+		assert.True(t, time.Date(2022, 8, 1, 13, 0, 0, 0, time.UTC).Equal(result.Time))
+
+		assert.EqualError(t, result.Scan(1659358800), "cannot scan int into date.DateTime")
+	})
+}
+
+func TestZero(t *testing.T) {
+	assert.Equal(t, "", Date{}.String())
+	assert.Equal(t, "", DateTime{}.String())
+
+	// NULL columns and null JSON clear values already set.
+	d, dt := New(2022, 8, 1), Now()
+	assert.NoError(t, d.Scan(nil))
+	assert.NoError(t, dt.Scan(nil))
+	assert.True(t, d.IsZero())
+	assert.True(t, dt.IsZero())
+
+	d, dt = New(2022, 8, 1), Now()
+	assert.NoError(t, json.Unmarshal([]byte("null"), &d))
+	assert.NoError(t, json.Unmarshal([]byte("null"), &dt))
+	assert.True(t, d.IsZero())
+	assert.True(t, dt.IsZero())
+}
+
+func TestFromTime(t *testing.T) {
+	inLocation(t, saoPaulo)
+
+	// 01:00 UTC is still the day before in São Paulo.
+	instant := time.Date(2022, 8, 2, 1, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, New(2022, 8, 1), FromTime(instant))
+	assert.Equal(t, New(2022, 8, 1), NewDateTime(instant).Date())
+}
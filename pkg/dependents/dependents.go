@@ -0,0 +1,83 @@
+// Package dependents describes the rows that keep a record from being
+// deleted, and carries whether a delete should take them along instead.
+package dependents
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/ctxkey"
+	"github.com/gin-gonic/gin"
+)
+
+const QueryParam = "cascade"
+
+// Dependent counts the rows of one type that reference a record.
+type Dependent struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// Error is returned when a record cannot be deleted because other rows
+// still reference it.
+type Error struct {
+	Entity     string
+	Id         int
+	Dependents []Dependent
+}
+
+func (e *Error) Error() string {
+	counts := make([]string, 0, len(e.Dependents))
+	for _, dependent := range e.Dependents {
+		counts = append(counts, fmt.Sprintf("%d %s", dependent.Count, dependent.Type))
+	}
+
+	return fmt.Sprintf("%s %d cannot be deleted, it still has %s", e.Entity, e.Id, strings.Join(counts, ", "))
+}
+
+// Check returns an *Error when any of dependents has rows.
+func Check(entity string, id int, dependents ...Dependent) error {
+	var blocking []Dependent
+	for _, dependent := range dependents {
+		if dependent.Count > 0 {
+			blocking = append(blocking, dependent)
+		}
+	}
+
+	if len(blocking) == 0 {
+		return nil
+	}
+
+	return &Error{Entity: entity, Id: id, Dependents: blocking}
+}
+
+func WithCascade(ctx context.Context) context.Context {
+	return ctxkey.With(ctx, ctxkey.Cascade, true)
+}
+
+func Cascade(ctx context.Context) bool {
+	cascade, _ := ctxkey.Value(ctx, ctxkey.Cascade).(bool)
+	return cascade
+}
+
+// FromQuery makes the delete handled for ctx take its dependents along when
+// the request has ?cascade=true.
+func FromQuery(ctx *gin.Context) error {
+	value, ok := ctx.GetQuery(QueryParam)
+	if !ok {
+		return nil
+	}
+
+	cascade, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q", QueryParam, value)
+	}
+
+	if cascade {
+		ctxkey.Set(ctx, ctxkey.Cascade, true)
+	}
+
+	return nil
+}
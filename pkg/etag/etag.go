@@ -0,0 +1,97 @@
+// Package etag implements optimistic concurrency. Reads expose the version
+// of a record as its ETag, and writes carry the version the client last saw
+// in If-Match, so repositories refuse to overwrite changes made since.
+package etag
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/ctxkey"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	Header        = "ETag"
+	IfMatchHeader = "If-Match"
+
+	// Condition is appended to update and delete statements that must only
+	// apply to the version the client has seen.
+	Condition = " AND version = ?"
+)
+
+var (
+	ErrMissing  = errors.New("the If-Match header is required")
+	ErrMismatch = errors.New("the record was changed since it was read, fetch it again")
+)
+
+// Format renders version as a strong entity tag.
+func Format(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// Set sends version as the ETag of the response.
+func Set(ctx *gin.Context, version int) {
+	ctx.Header(Header, Format(version))
+}
+
+func WithVersion(ctx context.Context, version int) context.Context {
+	return ctxkey.With(ctx, ctxkey.Version, version)
+}
+
+// Version returns the version the request expects the record to have. It is
+// not set when the client sent If-Match: *, which matches any version.
+func Version(ctx context.Context) (int, bool) {
+	version, ok := ctxkey.Value(ctx, ctxkey.Version).(int)
+	return version, ok
+}
+
+// FromIfMatch stores the version sent in the If-Match header of the request.
+// The header is required; a tag that is not one of ours can never match.
+func FromIfMatch(ctx *gin.Context) error {
+	value := strings.TrimSpace(ctx.GetHeader(IfMatchHeader))
+	if value == "" {
+		return ErrMissing
+	}
+
+	if value == "*" {
+		return nil
+	}
+
+	tag := strings.TrimPrefix(strings.TrimSpace(strings.Split(value, ",")[0]), "W/")
+
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return ErrMismatch
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil {
+		return ErrMismatch
+	}
+
+	ctxkey.Set(ctx, ctxkey.Version, version)
+
+	return nil
+}
+
+// Check returns ErrMismatch when the request expects a version other than
+// current.
+func Check(ctx context.Context, current int) error {
+	if version, ok := Version(ctx); ok && version != current {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// Status is the response status for an error returned by FromIfMatch or
+// Check.
+func Status(err error) int {
+	if errors.Is(err, ErrMissing) {
+		return http.StatusPreconditionRequired
+	}
+	return http.StatusPreconditionFailed
+}
@@ -0,0 +1,156 @@
+// Package export streams records to the client as CSV, NDJSON or XLSX while
+// they are read from the database, so a dump never sits whole in memory.
+package export
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/logger"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/store"
+	"github.com/gin-gonic/gin"
+)
+
+// QueryParam picks the format; without it the Accept header is used, and
+// CSV is the default.
+const QueryParam = "format"
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+
+	MIMECSV    = "text/csv"
+	MIMENDJSON = "application/x-ndjson"
+	MIMEXLSX   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// flushEvery is how many rows are written between flushes to the client.
+const flushEvery = 500
+
+var mimeTypes = map[string]string{
+	FormatCSV:    MIMECSV,
+	FormatNDJSON: MIMENDJSON,
+	FormatXLSX:   MIMEXLSX,
+}
+
+// Stream reads records and hands each one to fn as soon as it is scanned.
+// It stops at the first error fn returns.
+type Stream[T any] func(ctx context.Context, fn func(record T) error) error
+
+type writer interface {
+	Write(record interface{}) error
+	Flush() error
+	Close() error
+}
+
+// Handler answers with every record of stream as an attachment named after
+// name. The columns of CSV and XLSX are the JSON fields of T.
+//
+// Rows reach the client in batches of flushEvery, so errors raised before the
+// first batch is sent are answered with a 500. Once rows have been sent the
+// status cannot change: the handler panics with http.ErrAbortHandler
+// instead, so net/http cuts the connection and the client sees a broken
+// transfer rather than a file that looks complete. Servers must recover with
+// Recovery, which lets that panic through.
+func Handler[T any](name string, stream Stream[T]) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		format, err := negotiate(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.DecodeError(err.Error()))
+			return
+		}
+
+		var (
+			out  writer
+			rows int
+		)
+
+		start := func() error {
+			ctx.Header("Content-Type", mimeTypes[format])
+			ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+			ctx.Status(http.StatusOK)
+
+			var err error
+			out, err = newWriter[T](format, name, ctx.Writer)
+			return err
+		}
+
+		err = stream(ctx, func(record T) error {
+			if out == nil {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+
+			if err := out.Write(record); err != nil {
+				return err
+			}
+
+			rows++
+			if rows%flushEvery == 0 {
+				if err := out.Flush(); err != nil {
+					return err
+				}
+				ctx.Writer.Flush()
+			}
+			return nil
+		})
+		if err == nil && out == nil {
+			err = start()
+		}
+		if err == nil {
+			err = out.Close()
+		}
+
+		if err != nil {
+			logger.Error(ctx, store.GetPathWithLine(), err.Error())
+			if out == nil || !ctx.Writer.Written() {
+				ctx.Writer.Header().Del("Content-Type")
+				ctx.Writer.Header().Del("Content-Disposition")
+				ctx.JSON(http.StatusInternalServerError, response.DecodeError(err.Error()))
+				return
+			}
+			panic(http.ErrAbortHandler)
+		}
+
+		ctx.Writer.Flush()
+	}
+}
+
+// Recovery answers panics with a 500 as gin.Recovery does, except
+// http.ErrAbortHandler, which is raised again for net/http to cut the
+// connection of a transfer Handler broke off.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(ctx *gin.Context, err interface{}) {
+		if err == http.ErrAbortHandler {
+			panic(err)
+		}
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
+
+func negotiate(ctx *gin.Context) (string, error) {
+	if format, ok := ctx.GetQuery(QueryParam); ok {
+		format = strings.ToLower(format)
+		if _, known := mimeTypes[format]; !known {
+			return "", fmt.Errorf("invalid %s value %q, it must be %s, %s or %s", QueryParam, format, FormatCSV, FormatNDJSON, FormatXLSX)
+		}
+		return format, nil
+	}
+
+	for _, accepted := range strings.Split(ctx.GetHeader("Accept"), ",") {
+		accepted, _, _ = mime.ParseMediaType(strings.TrimSpace(accepted))
+		for format, mimeType := range mimeTypes {
+			if accepted == mimeType {
+				return format, nil
+			}
+		}
+	}
+
+	return FormatCSV, nil
+}
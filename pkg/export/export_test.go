@@ -0,0 +1,188 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fruit struct {
+	Id     int      `json:"id"`
+	Name   string   `json:"name"`
+	Price  *float64 `json:"price"`
+	Fresh  bool     `json:"fresh"`
+	Detail string   `json:"detail" export:"-"`
+}
+
+var (
+	price  = 1.5
+	fruits = []fruit{
+		{Id: 1, Name: "Apple", Price: &price, Fresh: true, Detail: "red"},
+		{Id: 2, Name: "Pear, green", Fresh: false},
+	}
+	errStream = errors.New("connection refused")
+)
+
+// records streams the fruits, failing with err before the record at failAt
+// when failAt is not negative.
+func records(failAt int, err error) Stream[fruit] {
+	return func(ctx context.Context, fn func(record fruit) error) error {
+		for i, f := range fruits {
+			if i == failAt {
+				return err
+			}
+			if err := fn(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func serve(stream Stream[fruit], target string, header http.Header) *httptest.ResponseRecorder {
+	api := gin.New()
+	api.GET("/fruits", Handler("fruits", stream))
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	res := httptest.NewRecorder()
+	api.ServeHTTP(res, req)
+	return res
+}
+
+func TestHandler_CSV(t *testing.T) {
+	res := serve(records(-1, nil), "/fruits", nil)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, MIMECSV, res.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="fruits.csv"`, res.Header().Get("Content-Disposition"))
+	assert.Equal(t, "id,name,price,fresh\n1,Apple,1.5,true\n2,\"Pear, green\",,false\n", res.Body.String())
+}
+
+func TestHandler_NDJSON(t *testing.T) {
+	res := serve(records(-1, nil), "/fruits", http.Header{"Accept": {"text/html, application/x-ndjson;q=0.9"}})
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, MIMENDJSON, res.Header().Get("Content-Type"))
+	assert.Equal(t,
+		`{"id":1,"name":"Apple","price":1.5,"fresh":true,"detail":"red"}`+"\n"+
+			`{"id":2,"name":"Pear, green","price":null,"fresh":false,"detail":""}`+"\n",
+		res.Body.String(),
+	)
+}
+
+func TestHandler_XLSX(t *testing.T) {
+	res := serve(records(-1, nil), "/fruits?format=XLSX", nil)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, MIMEXLSX, res.Header().Get("Content-Type"))
+
+	archive, err := zip.NewReader(bytes.NewReader(res.Body.Bytes()), int64(res.Body.Len()))
+	assert.NoError(t, err)
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		content, err := file.Open()
+		assert.NoError(t, err)
+		data, err := io.ReadAll(content)
+		assert.NoError(t, err)
+		files[file.Name] = string(data)
+	}
+
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="fruits"`)
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+	assert.Contains(t, sheet, `<row r="2"><c r="A2"><v>1</v></c><c r="B2" t="inlineStr"><is><t xml:space="preserve">Apple</t></is></c><c r="C2"><v>1.5</v></c><c r="D2" t="b"><v>1</v></c></row>`)
+	// The missing price leaves its cell out.
+	assert.Contains(t, sheet, `<c r="B3" t="inlineStr"><is><t xml:space="preserve">Pear, green</t></is></c><c r="D3" t="b"><v>0</v></c></row>`)
+	assert.True(t, strings.HasSuffix(sheet, xlsxSheetEnd))
+}
+
+func TestHandler_Empty(t *testing.T) {
+	res := serve(records(0, nil), "/fruits", nil)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "id,name,price,fresh\n", res.Body.String())
+}
+
+func TestHandler_InvalidFormat(t *testing.T) {
+	res := serve(records(-1, nil), "/fruits?format=pdf", nil)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), `invalid format value \"pdf\"`)
+}
+
+func TestHandler_FailsBeforeFirstRecord(t *testing.T) {
+	res := serve(records(0, errStream), "/fruits", nil)
+
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Equal(t, "application/json; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.Contains(t, res.Body.String(), "connection refused")
+}
+
+func TestHandler_FailsBeforeFirstFlush(t *testing.T) {
+	res := serve(records(1, errStream), "/fruits", nil)
+
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Empty(t, res.Header().Get("Content-Disposition"))
+	assert.Contains(t, res.Body.String(), "connection refused")
+}
+
+// flushed streams a flush worth of fruits, then fails.
+func flushed(ctx context.Context, fn func(record fruit) error) error {
+	for i := 1; i <= flushEvery; i++ {
+		if err := fn(fruit{Id: i, Name: "Apple"}); err != nil {
+			return err
+		}
+	}
+	return errStream
+}
+
+func TestHandler_FailsMidStream(t *testing.T) {
+	t.Run("should abort the handler", func(t *testing.T) {
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			serve(flushed, "/fruits", nil)
+		})
+	})
+
+	t.Run("should cut the transfer through Recovery", func(t *testing.T) {
+		api := gin.New()
+		api.Use(Recovery())
+		api.GET("/fruits", Handler[fruit]("fruits", flushed))
+
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		res, err := http.Get(server.URL + "/fruits")
+		assert.NoError(t, err)
+		defer res.Body.Close()
+
+		// The rows sent arrive, but the file never completes.
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		body, err := io.ReadAll(res.Body)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.True(t, strings.HasPrefix(string(body), "id,name,price,fresh\n1,Apple,,false\n"))
+	})
+}
+
+func TestRecovery(t *testing.T) {
+	api := gin.New()
+	api.Use(Recovery())
+	api.GET("/panic", func(ctx *gin.Context) { panic("unexpected") })
+
+	res := httptest.NewRecorder()
+	api.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func newWriter[T any](format, name string, out io.Writer) (writer, error) {
+	columns := columnsOf(reflect.TypeOf((*T)(nil)).Elem())
+
+	switch format {
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(out)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(out, name, columns)
+	}
+	return newCSVWriter(out, columns)
+}
+
+type csvWriter struct {
+	writer  *csv.Writer
+	columns []column
+}
+
+func newCSVWriter(out io.Writer, columns []column) (*csvWriter, error) {
+	w := &csvWriter{writer: csv.NewWriter(out), columns: columns}
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+
+	return w, w.writer.Write(header)
+}
+
+func (w *csvWriter) Write(record interface{}) error {
+	values := valuesOf(record, w.columns)
+
+	cells := make([]string, len(values))
+	for i, value := range values {
+		cells[i] = text(value)
+	}
+
+	return w.writer.Write(cells)
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) Close() error {
+	return w.Flush()
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(record interface{}) error {
+	return w.encoder.Encode(record)
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return nil
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+// column is a field of the exported type, named after its JSON name.
+type column struct {
+	name  string
+	index []int
+}
+
+// columnsOf lists the fields of typ, leaving out the ones tagged export:"-",
+// which only some reads fill.
+func columnsOf(typ reflect.Type) []column {
+	columns := []column{}
+	for _, field := range reflect.VisibleFields(typ) {
+		if field.Anonymous || !field.IsExported() || field.Tag.Get("export") == "-" {
+			continue
+		}
+
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		columns = append(columns, column{name: name, index: field.Index})
+	}
+	return columns
+}
+
+// valuesOf returns the cells of a record: numbers and booleans as they are,
+// everything else as it reads in JSON. Null values are nil.
+func valuesOf(record interface{}, columns []column) []interface{} {
+	value := reflect.Indirect(reflect.ValueOf(record))
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = cell(value.FieldByIndex(column.index))
+	}
+	return values
+}
+
+func cell(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if _, ok := value.Interface().(json.Marshaler); !ok {
+		switch value.Kind() {
+		case reflect.String:
+			return value.String()
+		case reflect.Bool:
+			return value.Bool()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return value.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return value.Uint()
+		case reflect.Float32, reflect.Float64:
+			return value.Float()
+		}
+	}
+
+	data, err := json.Marshal(value.Interface())
+	if err != nil || string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		return s
+	}
+	return string(data)
+}
+
+func text(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,224 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now    = time.Date(2022, 7, 14, 10, 0, 0, 0, time.UTC)
+	secret = []byte("a-secret-long-enough-for-hs256")
+	claims = Claims{
+		Subject:   "user:1",
+		Issuer:    "mercado-fresco",
+		Audience:  Audience{"api"},
+		ExpiresAt: now.Add(time.Hour).Unix(),
+		Roles:     []string{"admin"},
+	}
+)
+
+func rsaKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	return key
+}
+
+func verifier(publicKey *rsa.PublicKey) Verifier {
+	return Verifier{
+		Secret:    secret,
+		PublicKey: publicKey,
+		Issuer:    "mercado-fresco",
+		Audience:  "api",
+		Now:       func() time.Time { return now },
+	}
+}
+
+func TestParse_HS256(t *testing.T) {
+	token, err := SignHS256(claims, secret)
+	assert.NoError(t, err)
+
+	t.Run("Valid", func(t *testing.T) {
+		result, err := verifier(nil).Parse(token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, claims, result)
+	})
+
+	t.Run("Bad signature", func(t *testing.T) {
+		other, err := SignHS256(claims, []byte("another-secret"))
+		assert.NoError(t, err)
+
+		_, err = verifier(nil).Parse(other)
+
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Tampered claims", func(t *testing.T) {
+		forged, err := SignHS256(Claims{Subject: "user:2", ExpiresAt: claims.ExpiresAt}, secret)
+		assert.NoError(t, err)
+		parts, forgedParts := strings.Split(token, "."), strings.Split(forged, ".")
+
+		_, err = verifier(nil).Parse(parts[0] + "." + forgedParts[1] + "." + parts[2])
+
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		v := verifier(nil)
+		v.Now = func() time.Time { return now.Add(2 * time.Hour) }
+
+		_, err := v.Parse(token)
+
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("Expired within leeway", func(t *testing.T) {
+		v := verifier(nil)
+		v.Leeway = time.Minute
+		v.Now = func() time.Time { return now.Add(time.Hour + 30*time.Second) }
+
+		_, err := v.Parse(token)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Without expiration", func(t *testing.T) {
+		forever, err := SignHS256(Claims{Subject: "user:1"}, secret)
+		assert.NoError(t, err)
+
+		_, err = Verifier{Secret: secret}.Parse(forever)
+
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("Wrong issuer", func(t *testing.T) {
+		v := verifier(nil)
+		v.Issuer = "someone-else"
+
+		_, err := v.Parse(token)
+
+		assert.ErrorIs(t, err, ErrInvalidIssuer)
+	})
+
+	t.Run("Wrong audience", func(t *testing.T) {
+		v := verifier(nil)
+		v.Audience = "admin-panel"
+
+		_, err := v.Parse(token)
+
+		assert.ErrorIs(t, err, ErrInvalidAudience)
+	})
+}
+
+func TestParse_RS256(t *testing.T) {
+	key := rsaKey(t)
+	token, err := SignRS256(claims, key)
+	assert.NoError(t, err)
+
+	t.Run("Valid", func(t *testing.T) {
+		result, err := verifier(&key.PublicKey).Parse(token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, claims, result)
+	})
+
+	t.Run("Bad signature", func(t *testing.T) {
+		other, err := SignRS256(claims, rsaKey(t))
+		assert.NoError(t, err)
+
+		_, err = verifier(&key.PublicKey).Parse(other)
+
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		v := verifier(&key.PublicKey)
+		v.Now = func() time.Time { return now.Add(2 * time.Hour) }
+
+		_, err := v.Parse(token)
+
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+}
+
+func TestParse_AlgMismatch(t *testing.T) {
+	key := rsaKey(t)
+
+	t.Run("RS256 without a public key", func(t *testing.T) {
+		token, err := SignRS256(claims, key)
+		assert.NoError(t, err)
+
+		_, err = verifier(nil).Parse(token)
+
+		assert.ErrorIs(t, err, ErrUnsupportedAlg)
+	})
+
+	t.Run("HS256 without a secret", func(t *testing.T) {
+		token, err := SignHS256(claims, secret)
+		assert.NoError(t, err)
+
+		v := verifier(&key.PublicKey)
+		v.Secret = nil
+
+		_, err = v.Parse(token)
+
+		assert.ErrorIs(t, err, ErrUnsupportedAlg)
+	})
+
+	t.Run("HS256 signed with the public key", func(t *testing.T) {
+		// The classic confusion attack: the public key is no secret, so a
+		// token signed with it must never pass for an RS256 one.
+		public := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)})
+		token, err := SignHS256(claims, public)
+		assert.NoError(t, err)
+
+		v := verifier(&key.PublicKey)
+		v.Secret = nil
+
+		_, err = v.Parse(token)
+
+		assert.ErrorIs(t, err, ErrUnsupportedAlg)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		token, err := encodeUnsigned("none", claims)
+		assert.NoError(t, err)
+
+		_, err = verifier(&key.PublicKey).Parse(token + ".")
+
+		assert.ErrorIs(t, err, ErrUnsupportedAlg)
+	})
+}
+
+func TestParse_Malformed(t *testing.T) {
+	for _, token := range []string{"", "a.b", "a.b.c", "e30.e30.%%%"} {
+		_, err := verifier(nil).Parse(token)
+
+		assert.ErrorIs(t, err, ErrMalformed, token)
+	}
+}
+
+func TestParseRSAPublicKey(t *testing.T) {
+	key := rsaKey(t)
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)})
+	parsed, err := ParseRSAPublicKey(pkcs1)
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(parsed))
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	parsed, err = ParseRSAPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(parsed))
+
+	_, err = ParseRSAPublicKey([]byte("not a key"))
+	assert.Error(t, err)
+}
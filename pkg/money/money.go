@@ -0,0 +1,9 @@
+// Package money keeps the amounts of orders and reports to the cent.
+package money
+
+import "math"
+
+// Round keeps the cents of an amount, dropping the noise of float math.
+func Round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
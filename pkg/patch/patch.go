@@ -0,0 +1,86 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// MediaType is the content type defined by RFC 7396 for merge patch documents.
+const MediaType = "application/merge-patch+json"
+
+var (
+	ErrNotObject = errors.New("merge patch document must be a JSON object")
+	ErrSyntax    = errors.New("merge patch document is not valid JSON")
+	ErrMediaType = fmt.Errorf("merge patch documents must be sent as %s", MediaType)
+)
+
+type NullFieldError struct {
+	Field string
+}
+
+func (e *NullFieldError) Error() string {
+	return fmt.Sprintf("field %q cannot be null", e.Field)
+}
+
+// Decode reads a JSON merge patch (RFC 7396) into dst, which is expected to be
+// a struct with pointer fields so that absent members stay nil. Members that
+// do not map to a field of dst are rejected, as well as explicit nulls, since
+// none of our resources have removable members.
+func Decode(r io.Reader, dst interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if !json.Valid(data) {
+		return ErrSyntax
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil || members == nil {
+		return ErrNotObject
+	}
+
+	for name, value := range members {
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			return &NullFieldError{Field: name}
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(dst)
+}
+
+// Bind decodes the request body as a merge patch and validates the result
+// against its binding tags. Bodies sent as application/json, or without a
+// content type, are read as merge patches too.
+func Bind(ctx *gin.Context, dst interface{}) error {
+	switch ctx.ContentType() {
+	case MediaType, binding.MIMEJSON, "":
+	default:
+		return ErrMediaType
+	}
+
+	if err := Decode(ctx.Request.Body, dst); err != nil {
+		return err
+	}
+
+	return binding.Validator.ValidateStruct(dst)
+}
+
+// Status is the response status for an error returned by Bind.
+func Status(err error) int {
+	if errors.Is(err, ErrMediaType) {
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusUnprocessableEntity
+}
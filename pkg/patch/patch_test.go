@@ -0,0 +1,87 @@
+package patch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type document struct {
+	Name  *string `json:"name" binding:"omitempty,min=1"`
+	Count *int    `json:"count"`
+}
+
+func TestDecode(t *testing.T) {
+	name := "xpto"
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected document
+		err      error
+	}{
+		{name: "Members", body: `{"name": "xpto"}`, expected: document{Name: &name}},
+		{name: "Empty object", body: `{}`},
+		{name: "Syntax", body: `{"name": `, err: ErrSyntax},
+		{name: "Empty body", body: ``, err: ErrSyntax},
+		{name: "Array", body: `[{"name": "xpto"}]`, err: ErrNotObject},
+		{name: "Null document", body: `null`, err: ErrNotObject},
+		{name: "Null member", body: `{"name": null}`, err: &NullFieldError{Field: "name"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var result document
+			err := Decode(strings.NewReader(testCase.body), &result)
+
+			assert.Equal(t, testCase.err, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+
+	t.Run("Unknown member", func(t *testing.T) {
+		var result document
+		assert.EqualError(t, Decode(strings.NewReader(`{"nickname": "xpto"}`), &result), `json: unknown field "nickname"`)
+	})
+}
+
+func TestBind(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{name: "Merge patch", contentType: MediaType, body: `{"count": 2}`, status: http.StatusOK},
+		{name: "JSON", contentType: "application/json; charset=utf-8", body: `{"count": 2}`, status: http.StatusOK},
+		{name: "Without content type", body: `{"count": 2}`, status: http.StatusOK},
+		{name: "JSON patch", contentType: "application/json-patch+json", body: `[]`, status: http.StatusUnsupportedMediaType},
+		{name: "Form", contentType: "application/x-www-form-urlencoded", body: `count=2`, status: http.StatusUnsupportedMediaType},
+		{name: "Invalid member", contentType: MediaType, body: `{"name": ""}`, status: http.StatusUnprocessableEntity},
+		{name: "Syntax", contentType: MediaType, body: `{"count": 2`, status: http.StatusUnprocessableEntity},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			res := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(res)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(testCase.body))
+			if testCase.contentType != "" {
+				ctx.Request.Header.Set("Content-Type", testCase.contentType)
+			}
+
+			var result document
+			status := http.StatusOK
+			if err := Bind(ctx, &result); err != nil {
+				status = Status(err)
+			}
+
+			assert.Equal(t, testCase.status, status)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package period reads the date range and the grouping unit of reports from
+// the query string, and groups the rows of reports by period.
+package period
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	FromParam = "from"
+	ToParam   = "to"
+	UnitParam = "period"
+)
+
+// Unit is how report rows are grouped over time.
+type Unit string
+
+const (
+	Day   Unit = "day"
+	Week  Unit = "week"
+	Month Unit = "month"
+)
+
+var ErrInvertedRange = errors.New("from must not be after to")
+
+// Range holds the days a report covers, both included. A zero bound leaves
+// that side open.
+type Range struct {
+	From date.Date `json:"from"`
+	To   date.Date `json:"to"`
+}
+
+// RangeFromQuery reads ?from= and ?to=, both in the date layout.
+func RangeFromQuery(ctx *gin.Context) (Range, error) {
+	var r Range
+
+	for param, bound := range map[string]*date.Date{FromParam: &r.From, ToParam: &r.To} {
+		value, ok := ctx.GetQuery(param)
+		if !ok {
+			continue
+		}
+
+		parsed, err := date.Parse(value)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid %s value %q", param, value)
+		}
+		*bound = parsed
+	}
+
+	if !r.From.IsZero() && !r.To.IsZero() && r.From.After(r.To.Time) {
+		return Range{}, ErrInvertedRange
+	}
+
+	return r, nil
+}
+
+// Contains reports whether d is within r.
+func (r Range) Contains(d date.Date) bool {
+	if !r.From.IsZero() && d.Before(r.From.Time) {
+		return false
+	}
+	if !r.To.IsZero() && d.After(r.To.Time) {
+		return false
+	}
+	return true
+}
+
+// Where returns the conditions that keep column within r, to be appended to
+// a WHERE clause, and their arguments.
+func (r Range) Where(column string) (string, []interface{}) {
+	var (
+		conditions string
+		args       []interface{}
+	)
+
+	if !r.From.IsZero() {
+		conditions, args = conditions+" AND "+column+" >= ?", append(args, r.From)
+	}
+	if !r.To.IsZero() {
+		conditions, args = conditions+" AND "+column+" <= ?", append(args, r.To)
+	}
+
+	return conditions, args
+}
+
+// UnitFromQuery reads ?period=, which defaults to fallback.
+func UnitFromQuery(ctx *gin.Context, fallback Unit) (Unit, error) {
+	value, ok := ctx.GetQuery(UnitParam)
+	if !ok {
+		return fallback, nil
+	}
+
+	switch unit := Unit(strings.ToLower(value)); unit {
+	case Day, Week, Month:
+		return unit, nil
+	}
+
+	return "", fmt.Errorf("invalid %s value %q, it must be %s, %s or %s", UnitParam, value, Day, Week, Month)
+}
+
+// Key names the period of u that d falls in: 2022-08-01 for days, 2022-W31
+// for ISO weeks and 2022-08 for months. Keys sort in time order.
+func (u Unit) Key(d date.Date) string {
+	switch u {
+	case Day:
+		return d.String()
+	case Week:
+		year, week := d.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	}
+	return d.Format("2006-01")
+}
+
+// Grouping gathers the rows of a report by period of a unit. Dates must be
+// fed in time order, so the rows come out in time order too.
+type Grouping[T any] struct {
+	unit    Unit
+	newRow  func(key string) T
+	rows    []T
+	lastKey string
+}
+
+// NewGrouping groups by unit. newRow makes the row of a period from its key
+// the first time a date falls in it.
+func NewGrouping[T any](unit Unit, newRow func(key string) T) *Grouping[T] {
+	return &Grouping[T]{unit: unit, newRow: newRow, rows: make([]T, 0)}
+}
+
+// Of returns the row of the period d falls in. It stays valid until the next
+// call.
+func (g *Grouping[T]) Of(d date.Date) *T {
+	if key := g.unit.Key(d); len(g.rows) == 0 || key != g.lastKey {
+		g.rows, g.lastKey = append(g.rows, g.newRow(key)), key
+	}
+	return &g.rows[len(g.rows)-1]
+}
+
+// Rows returns the rows, never nil.
+func (g *Grouping[T]) Rows() []T {
+	return g.rows
+}
@@ -0,0 +1,36 @@
+package period
+
+import (
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/stretchr/testify/assert"
+)
+
+type row struct {
+	Period string
+	Count  int
+}
+
+func newRow(key string) row {
+	return row{Period: key}
+}
+
+func TestGrouping(t *testing.T) {
+	t.Run("Groups dates in time order", func(t *testing.T) {
+		grouping := NewGrouping(Week, newRow)
+
+		for _, d := range []date.Date{date.New(2022, 7, 4), date.New(2022, 7, 5), date.New(2022, 7, 12)} {
+			grouping.Of(d).Count++
+		}
+
+		assert.Equal(t, []row{{Period: "2022-W27", Count: 2}, {Period: "2022-W28", Count: 1}}, grouping.Rows())
+	})
+
+	t.Run("Without dates", func(t *testing.T) {
+		rows := NewGrouping(Month, newRow).Rows()
+
+		assert.NotNil(t, rows)
+		assert.Empty(t, rows)
+	})
+}
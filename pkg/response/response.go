@@ -1,14 +1,35 @@
 package response
 
+import "github.com/douglmendes/mercado-fresco-round-go/pkg/dependents"
+
 type Response struct {
-	Data  interface{} `json:"data,omitempty"`
-	Error string      `json:"error,omitempty"`
+	Data       interface{}            `json:"data,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Fields     []FieldError           `json:"fields,omitempty"`
+	Dependents []dependents.Dependent `json:"dependents,omitempty"`
+}
+
+// FieldError describes why a single field of a request was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 func NewResponse(data interface{}) Response {
-	return Response{data, ""}
+	return Response{Data: data}
 }
 
 func DecodeError(err string) Response {
-	return Response{nil, err}
+	return Response{Error: err}
+}
+
+func DecodeFieldErrors(err string, fields []FieldError) Response {
+	return Response{Error: err, Fields: fields}
+}
+
+// DecodeDependents builds the body of a 409 returned when a delete is
+// blocked by the rows that still reference the record.
+func DecodeDependents(err *dependents.Error) Response {
+	return Response{Error: err.Error(), Dependents: err.Dependents}
 }
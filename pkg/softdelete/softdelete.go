@@ -0,0 +1,43 @@
+// Package softdelete carries whether a request wants soft deleted rows,
+// which repositories otherwise leave out of every read.
+package softdelete
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/ctxkey"
+	"github.com/gin-gonic/gin"
+)
+
+const QueryParam = "include_deleted"
+
+func WithDeleted(ctx context.Context) context.Context {
+	return ctxkey.With(ctx, ctxkey.IncludeDeleted, true)
+}
+
+func IncludeDeleted(ctx context.Context) bool {
+	include, _ := ctxkey.Value(ctx, ctxkey.IncludeDeleted).(bool)
+	return include
+}
+
+// FromQuery includes deleted rows in the reads made for ctx when the request
+// has ?include_deleted=true.
+func FromQuery(ctx *gin.Context) error {
+	value, ok := ctx.GetQuery(QueryParam)
+	if !ok {
+		return nil
+	}
+
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q", QueryParam, value)
+	}
+
+	if include {
+		ctxkey.Set(ctx, ctxkey.IncludeDeleted, true)
+	}
+
+	return nil
+}
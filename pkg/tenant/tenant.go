@@ -0,0 +1,20 @@
+// Package tenant carries the seller a request is limited to, so repositories
+// can filter seller-owned rows without every signature taking a seller id.
+package tenant
+
+import (
+	"context"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/ctxkey"
+)
+
+func WithSeller(ctx context.Context, sellerId int) context.Context {
+	return ctxkey.With(ctx, ctxkey.SellerId, sellerId)
+}
+
+// SellerId returns the seller the request is scoped to. Requests that are
+// not scoped, such as those made by admins, see every seller's data.
+func SellerId(ctx context.Context) (int, bool) {
+	sellerId, ok := ctxkey.Value(ctx, ctxkey.SellerId).(int)
+	return sellerId, ok
+}
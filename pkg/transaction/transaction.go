@@ -0,0 +1,63 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/ctxkey"
+)
+
+// Executor runs statements. Both *sql.DB and *sql.Tx implement it.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return ctxkey.With(ctx, ctxkey.Tx, tx)
+}
+
+// From returns the transaction ctx runs in, or db when there is none.
+// Repositories run their statements through it so callers can group several
+// service calls in one transaction.
+func From(ctx context.Context, db *sql.DB) Executor {
+	if tx, ok := ctxkey.Value(ctx, ctxkey.Tx).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// Runner runs a function inside a transaction.
+type Runner interface {
+	Run(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type runner struct {
+	db *sql.DB
+}
+
+func NewRunner(db *sql.DB) Runner {
+	return &runner{db}
+}
+
+// Run commits when fn succeeds and rolls back when it returns an error,
+// which is then returned as is. When ctx already runs in a transaction, fn
+// joins it and the outermost Run decides.
+func (r *runner) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctxkey.Value(ctx, ctxkey.Tx).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(WithTx(ctx, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
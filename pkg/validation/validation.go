@@ -0,0 +1,225 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/date"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/patch"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Message is the error summary sent along with the field list.
+const Message = "invalid request"
+
+const (
+	CodeInvalidBody  = "invalid_body"
+	CodeType         = "type"
+	CodeUnknownField = "unknown_field"
+	CodeNull         = "null"
+	CodeSyntax       = "syntax"
+)
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{6,18}[0-9]$`)
+
+var unknownFieldPattern = regexp.MustCompile(`^json: unknown field "(.+)"$`)
+
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	v.RegisterTagNameFunc(jsonName)
+
+	// date and timestamp accept the formats understood by the date package.
+	_ = v.RegisterValidation("date", func(fl validator.FieldLevel) bool {
+		_, err := date.Parse(fl.Field().String())
+		return err == nil
+	})
+	_ = v.RegisterValidation("timestamp", func(fl validator.FieldLevel) bool {
+		_, err := date.ParseDateTime(fl.Field().String())
+		return err == nil
+	})
+	_ = v.RegisterValidation("date_gtefield", func(fl validator.FieldLevel) bool {
+		other, _, _, ok := fl.GetStructFieldOKAdvanced2(fl.Parent(), fl.Param())
+		if !ok {
+			return false
+		}
+
+		current, err := date.Parse(fl.Field().String())
+		if err != nil {
+			return true // reported by the date rule
+		}
+		bound, err := date.Parse(other.String())
+		if err != nil {
+			return true
+		}
+
+		return !current.Before(bound.Time)
+	})
+
+	// phone accepts digits with an optional leading +, allowing spaces,
+	// dashes and parentheses as separators.
+	_ = v.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		return phonePattern.MatchString(fl.Field().String())
+	})
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// NewErrorResponse builds the body of a 422 response out of an error
+// returned by ShouldBindJSON or patch.Bind.
+func NewErrorResponse(err error) response.Response {
+	return response.DecodeFieldErrors(Message, Fields(err))
+}
+
+// Fields translates binding and decoding errors into one entry per
+// invalid field.
+func Fields(err error) []response.FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make([]response.FieldError, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			fields = append(fields, response.FieldError{
+				Field:   fieldPath(fe),
+				Code:    fe.Tag(),
+				Message: message(fe),
+			})
+		}
+		return fields
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return []response.FieldError{{
+			Field:   typeError.Field,
+			Code:    CodeType,
+			Message: fmt.Sprintf("must be of type %s", typeError.Type.String()),
+		}}
+	}
+
+	var nullError *patch.NullFieldError
+	if errors.As(err, &nullError) {
+		return []response.FieldError{{
+			Field:   nullError.Field,
+			Code:    CodeNull,
+			Message: "cannot be null",
+		}}
+	}
+
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, patch.ErrSyntax) {
+		return []response.FieldError{{
+			Code:    CodeSyntax,
+			Message: "must be valid JSON",
+		}}
+	}
+
+	if match := unknownFieldPattern.FindStringSubmatch(err.Error()); match != nil {
+		return []response.FieldError{{
+			Field:   match[1],
+			Code:    CodeUnknownField,
+			Message: "is not a known field",
+		}}
+	}
+
+	return []response.FieldError{{
+		Code:    CodeInvalidBody,
+		Message: err.Error(),
+	}}
+}
+
+// fieldPath drops the struct name from the namespace, so nested fields are
+// reported as "items[0].quantity".
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if unit := lengthUnit(fe.Kind()); unit != "" {
+			return fmt.Sprintf("must have at least %s %s", fe.Param(), unit)
+		}
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "max":
+		if unit := lengthUnit(fe.Kind()); unit != "" {
+			return fmt.Sprintf("must have at most %s %s", fe.Param(), unit)
+		}
+		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "gtefield", "date_gtefield":
+		return fmt.Sprintf("must be greater than or equal to %s", toSnakeCase(fe.Param()))
+	case "ltefield":
+		return fmt.Sprintf("must be less than or equal to %s", toSnakeCase(fe.Param()))
+	case "datetime":
+		return fmt.Sprintf("must be a date in the format %s", fe.Param())
+	case "date":
+		return fmt.Sprintf("must be a date in the format %s", date.Layout)
+	case "timestamp":
+		return "must be a date and time in RFC 3339 format"
+	case "phone":
+		return "must be a valid phone number"
+	case "numeric":
+		return "must contain only digits"
+	case "isdefault":
+		return "must not be set"
+	case "excluded_with":
+		return fmt.Sprintf("must not be set together with %s", toSnakeCase(fe.Param()))
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	}
+	return fmt.Sprintf("failed on the %s rule", fe.Tag())
+}
+
+// lengthUnit is what min and max count for values of kind, or "" for the
+// kinds they compare by value.
+func lengthUnit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return "items"
+	}
+	return ""
+}
+
+// toSnakeCase turns the Go field name referenced by cross-field rules into
+// the name clients know it by.
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/patch"
+	"github.com/douglmendes/mercado-fresco-round-go/pkg/response"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	ProductId int `json:"product_id" binding:"required,min=1"`
+	Quantity  int `json:"quantity" binding:"gt=0"`
+}
+
+type request struct {
+	Name      string `json:"name" binding:"required,max=5"`
+	Phone     string `json:"phone" binding:"omitempty,phone"`
+	StartDate string `json:"start_date" binding:"omitempty,date"`
+	EndDate   string `json:"end_date" binding:"omitempty,date,date_gtefield=StartDate"`
+	Status    string `json:"status" binding:"omitempty,oneof=open closed"`
+	Legacy    int    `json:"legacy" binding:"isdefault"`
+	Items     []item `json:"items" binding:"omitempty,min=2,dive"`
+	NoJSON    string `binding:"omitempty,numeric"`
+}
+
+func valid() request {
+	return request{Name: "xpto"}
+}
+
+func TestNewErrorResponse_Validator(t *testing.T) {
+	testCases := []struct {
+		name     string
+		modify   func(r *request)
+		expected []response.FieldError
+	}{
+		{
+			name:     "required",
+			modify:   func(r *request) { r.Name = "" },
+			expected: []response.FieldError{{Field: "name", Code: "required", Message: "is required"}},
+		},
+		{
+			name:     "max of a string",
+			modify:   func(r *request) { r.Name = "xablau" },
+			expected: []response.FieldError{{Field: "name", Code: "max", Message: "must have at most 5 characters"}},
+		},
+		{
+			name:     "phone",
+			modify:   func(r *request) { r.Phone = "call me" },
+			expected: []response.FieldError{{Field: "phone", Code: "phone", Message: "must be a valid phone number"}},
+		},
+		{
+			name:     "date",
+			modify:   func(r *request) { r.StartDate = "01/08/2022" },
+			expected: []response.FieldError{{Field: "start_date", Code: "date", Message: "must be a date in the format 2006-01-02"}},
+		},
+		{
+			name:     "date before another field",
+			modify:   func(r *request) { r.StartDate, r.EndDate = "2022-08-02", "2022-08-01" },
+			expected: []response.FieldError{{Field: "end_date", Code: "date_gtefield", Message: "must be greater than or equal to start_date"}},
+		},
+		{
+			name:     "oneof",
+			modify:   func(r *request) { r.Status = "lost" },
+			expected: []response.FieldError{{Field: "status", Code: "oneof", Message: "must be one of open, closed"}},
+		},
+		{
+			name:     "isdefault",
+			modify:   func(r *request) { r.Legacy = 1 },
+			expected: []response.FieldError{{Field: "legacy", Code: "isdefault", Message: "must not be set"}},
+		},
+		{
+			name:     "min of a slice",
+			modify:   func(r *request) { r.Items = []item{{ProductId: 1, Quantity: 1}} },
+			expected: []response.FieldError{{Field: "items", Code: "min", Message: "must have at least 2 items"}},
+		},
+		{
+			name:   "nested fields",
+			modify: func(r *request) { r.Items = []item{{ProductId: 1, Quantity: 1}, {Quantity: 0}} },
+			expected: []response.FieldError{
+				{Field: "items[1].product_id", Code: "required", Message: "is required"},
+				{Field: "items[1].quantity", Code: "gt", Message: "must be greater than 0"},
+			},
+		},
+		{
+			name:     "field without a json name",
+			modify:   func(r *request) { r.NoJSON = "12a" },
+			expected: []response.FieldError{{Field: "NoJSON", Code: "numeric", Message: "must contain only digits"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := valid()
+			testCase.modify(&req)
+
+			err := binding.Validator.ValidateStruct(&req)
+
+			assert.Equal(t, response.Response{Error: Message, Fields: testCase.expected}, NewErrorResponse(err))
+		})
+	}
+}
+
+func TestNewErrorResponse_Decoding(t *testing.T) {
+	decode := func(body string) error {
+		var req request
+		decoder := json.NewDecoder(strings.NewReader(body))
+		decoder.DisallowUnknownFields()
+		return decoder.Decode(&req)
+	}
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected []response.FieldError
+	}{
+		{
+			name:     "type",
+			err:      decode(`{"name": 5}`),
+			expected: []response.FieldError{{Field: "name", Code: CodeType, Message: "must be of type string"}},
+		},
+		{
+			name:     "unknown field",
+			err:      decode(`{"nickname": "xpto"}`),
+			expected: []response.FieldError{{Field: "nickname", Code: CodeUnknownField, Message: "is not a known field"}},
+		},
+		{
+			name:     "null in a merge patch",
+			err:      &patch.NullFieldError{Field: "name"},
+			expected: []response.FieldError{{Field: "name", Code: CodeNull, Message: "cannot be null"}},
+		},
+		{
+			name:     "truncated",
+			err:      decode(`{"name": `),
+			expected: []response.FieldError{{Code: CodeSyntax, Message: "must be valid JSON"}},
+		},
+		{
+			name:     "syntax",
+			err:      decode(`{"name" "xpto"}`),
+			expected: []response.FieldError{{Code: CodeSyntax, Message: "must be valid JSON"}},
+		},
+		{
+			name:     "syntax in a merge patch",
+			err:      patch.ErrSyntax,
+			expected: []response.FieldError{{Code: CodeSyntax, Message: "must be valid JSON"}},
+		},
+		{
+			name:     "merge patch that is not an object",
+			err:      patch.ErrNotObject,
+			expected: []response.FieldError{{Code: CodeInvalidBody, Message: "merge patch document must be a JSON object"}},
+		},
+		{
+			name:     "any other error",
+			err:      errors.New("http: request body too large"),
+			expected: []response.FieldError{{Code: CodeInvalidBody, Message: "http: request body too large"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, response.Response{Error: Message, Fields: testCase.expected}, NewErrorResponse(testCase.err))
+		})
+	}
+}